@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -24,19 +23,29 @@ import (
 	"nuimanbot/internal/infrastructure/audit"
 	"nuimanbot/internal/infrastructure/cache"
 	"nuimanbot/internal/infrastructure/crypto"
+	"nuimanbot/internal/infrastructure/fetch"
 	"nuimanbot/internal/infrastructure/health"
 	anthropic "nuimanbot/internal/infrastructure/llm/anthropic"
 	bedrock "nuimanbot/internal/infrastructure/llm/bedrock"
+	"nuimanbot/internal/infrastructure/llm/inspector"
+	"nuimanbot/internal/infrastructure/llm/keypool"
 	ollama "nuimanbot/internal/infrastructure/llm/ollama"
 	openai "nuimanbot/internal/infrastructure/llm/openai"
+	replay "nuimanbot/internal/infrastructure/llm/replay"
 	"nuimanbot/internal/infrastructure/logger"
+	searchClient "nuimanbot/internal/infrastructure/search"
 	skillinfra "nuimanbot/internal/infrastructure/skill"
+	weatherClient "nuimanbot/internal/infrastructure/weather"
 	"nuimanbot/internal/tools/calculator"
 	"nuimanbot/internal/tools/datetime"
 	"nuimanbot/internal/tools/notes"
+	"nuimanbot/internal/tools/preferences"
 	"nuimanbot/internal/tools/weather"
+	"nuimanbot/internal/tools/webfetch"
 	"nuimanbot/internal/tools/websearch"
+	"nuimanbot/internal/usecase/batch"
 	"nuimanbot/internal/usecase/chat"
+	llmusecase "nuimanbot/internal/usecase/llm"
 	"nuimanbot/internal/usecase/memory"
 	"nuimanbot/internal/usecase/security"
 	skillusecase "nuimanbot/internal/usecase/skill"
@@ -45,9 +54,14 @@ import (
 	"nuimanbot/internal/usecase/tool/common"
 	"nuimanbot/internal/usecase/tool/doc_summarize"
 	"nuimanbot/internal/usecase/tool/executor"
+	"nuimanbot/internal/usecase/tool/files"
 	"nuimanbot/internal/usecase/tool/github"
+	"nuimanbot/internal/usecase/tool/httptool"
+	"nuimanbot/internal/usecase/tool/openapi"
 	"nuimanbot/internal/usecase/tool/repo_search"
+	"nuimanbot/internal/usecase/tool/shell"
 	"nuimanbot/internal/usecase/tool/summarize"
+	"nuimanbot/internal/usecase/user"
 )
 
 // application represents the core NuimanBot application.
@@ -63,6 +77,10 @@ type application struct {
 	ToolExecutionService *tool.Service
 	HealthServer         *health.Server
 	DB                   *sql.DB
+	TraceStore           domain.LLMTraceStore // Nil unless the LLM request inspector is enabled
+	BatchService         *batch.Service
+	HTTPToolLoader       *httptool.Loader // Nil unless tools.load.watch is set
+	UserResolver         *user.Resolver
 }
 
 func main() {
@@ -157,14 +175,56 @@ func main() {
 	// 6. Initialize Memory Repository
 	memoryRepo := sqlite.NewMessageRepository(db)
 
-	// 7. Initialize Notes Repository
+	// 7. Initialize Notes, User and Preferences Repositories
 	notesRepo := sqlite.NewNotesRepository(db)
+	userRepo := sqlite.NewUserRepository(db)
+	prefsRepo := sqlite.NewPreferencesRepository(db)
+	if err := prefsRepo.Init(context.Background()); err != nil {
+		log.Fatalf("Failed to initialize user preferences table: %v", err)
+	}
 
 	// 8. Initialize LLM Service
-	llmService, err := initializeLLMService(cfg)
+	llmService, err := initializeLLMService(cfg, vault)
 	if err != nil {
 		log.Fatalf("Failed to create LLM service: %v", err)
 	}
+	providerClient := llmService // Undecorated, for provider batch endpoints
+
+	// Merge per-model default params (llm.models.<name>.params) into requests
+	var paramsService *llmusecase.ParamsService
+	if len(cfg.LLM.Models) > 0 {
+		paramsService, err = llmusecase.NewParamsService(llmService, cfg.LLM.Models)
+		if err != nil {
+			log.Fatalf("Invalid LLM model params: %v", err)
+		}
+		llmService = paramsService
+	}
+
+	// Optionally record redacted LLM traces for "/admin trace"
+	var traceStore domain.LLMTraceStore
+	if inspectorCfg := cfg.LLM.Inspector; inspectorCfg.Enabled {
+		store := inspector.NewStore(inspectorCfg.MaxRequests, time.Duration(inspectorCfg.RetentionHours)*time.Hour)
+		llmService = inspector.NewRecorder(llmService, store, common.NewOutputSanitizer(), inspectorCfg.SampleRate)
+		traceStore = store
+		slog.Info("LLM request inspector enabled",
+			"sample_rate", inspectorCfg.SampleRate,
+			"max_requests", inspectorCfg.MaxRequests,
+		)
+	}
+
+	// Batch jobs for bulk offline work ("/admin batch")
+	batchRepo := sqlite.NewBatchRepository(db)
+	if err := batchRepo.Init(context.Background()); err != nil {
+		log.Fatalf("Failed to initialize batch job tables: %v", err)
+	}
+	batchService := batch.NewService(batchRepo, llmService, cfg.LLM.Batch.Workers)
+	batchService.SetPollInterval(time.Duration(cfg.LLM.Batch.PollIntervalSeconds) * time.Second)
+	if batcher, ok := providerClient.(domain.LLMBatchService); ok {
+		batchService.SetBatchProvider(batcher)
+	}
+	if paramsService != nil {
+		batchService.SetRequestDefaults(paramsService)
+	}
 
 	// 8.5. Initialize Health Check Server
 	healthServer := health.NewServer(db, llmService, vaultPath)
@@ -175,11 +235,34 @@ func main() {
 	toolRegistry := tool.NewInMemoryRegistry()
 
 	// Register built-in skills
-	if err := registerBuiltInTools(toolRegistry, notesRepo, llmService); err != nil {
+	if err := registerBuiltInTools(toolRegistry, cfg, notesRepo, prefsRepo, llmService, securityService); err != nil {
 		log.Fatalf("Failed to register skills: %v", err)
 	}
 
+	// Register tools generated from OpenAPI specs (tools.openapi)
+	if err := registerOpenAPITools(toolRegistry, cfg.Tools.OpenAPI, vault); err != nil {
+		log.Fatalf("Failed to register OpenAPI tools: %v", err)
+	}
+
+	// Register declarative HTTP tools from tools.load.extra_dirs
+	var httpToolLoader *httptool.Loader
+	if len(cfg.Tools.Load.ExtraDirs) > 0 {
+		httpToolLoader = httptool.NewLoader(cfg.Tools.Load.ExtraDirs, toolRegistry, vault)
+		if err := httpToolLoader.Load(); err != nil {
+			log.Fatalf("Failed to load HTTP tools: %v", err)
+		}
+		slog.Info("HTTP tools loaded", "dirs", cfg.Tools.Load.ExtraDirs, "watch", cfg.Tools.Load.Watch)
+		if !cfg.Tools.Load.Watch {
+			httpToolLoader = nil
+		}
+	}
+
 	toolExecutionService := tool.NewService(&cfg.Tools, toolRegistry, securityService)
+	if results := toolExecutionService.Results(); results != nil {
+		if err := toolRegistry.Register(tool.NewReadResultTool(results)); err != nil {
+			log.Fatalf("Failed to register read_result tool: %v", err)
+		}
+	}
 
 	// 10. Initialize Chat Service
 	chatService := chat.NewService(llmService, memoryRepo, toolExecutionService, securityService)
@@ -192,6 +275,33 @@ func main() {
 		"ttl", "1h",
 	)
 
+	// Configure model capability catalog (bundled table + overrides + provider model lists)
+	modelCatalog, err := llmusecase.LoadCatalog(cfg.LLM.CatalogFile)
+	if err != nil {
+		log.Fatalf("Failed to load model catalog: %v", err)
+	}
+	catalogCtx, cancelCatalog := context.WithTimeout(context.Background(), 10*time.Second)
+	modelCatalog.Refresh(catalogCtx, llmService,
+		domain.LLMProviderAnthropic, domain.LLMProviderOpenAI, domain.LLMProviderOllama, domain.LLMProviderBedrock)
+	cancelCatalog()
+	chatService.SetModelCatalog(modelCatalog)
+
+	// Continuation of truncated responses (default applies when unset)
+	if cfg.LLM.MaxContinuations != 0 {
+		chatService.SetMaxContinuations(cfg.LLM.MaxContinuations)
+	}
+
+	// Resolve chat identities to users so the LLM is only offered, and can
+	// only run, the tools their role allows. Identities without a stored
+	// user get their configured default role.
+	userResolver := user.NewResolver(userRepo, cfg.Security.DefaultRole, cfg.Security.PlatformRoles)
+	chatService.SetUserResolver(userResolver)
+	chatService.SetPreferencesRepository(prefsRepo)
+	slog.Info("Chat tool access configured",
+		"default_role", cfg.Security.DefaultRole,
+		"platform_roles", cfg.Security.PlatformRoles,
+	)
+
 	// 11. Create Application
 	app := &application{
 		Config:               cfg,
@@ -204,6 +314,10 @@ func main() {
 		ToolExecutionService: toolExecutionService,
 		HealthServer:         healthServer,
 		DB:                   db,
+		TraceStore:           traceStore,
+		BatchService:         batchService,
+		HTTPToolLoader:       httpToolLoader,
+		UserResolver:         userResolver,
 	}
 
 	// 12. Run application in goroutine
@@ -252,8 +366,41 @@ func (app *application) connectGateway(gw domain.Gateway) {
 }
 
 // initializeLLMService initializes the LLM service based on configuration.
-func initializeLLMService(cfg *config.NuimanBotConfig) (domain.LLMService, error) {
+func initializeLLMService(cfg *config.NuimanBotConfig, vault domain.CredentialVault) (domain.LLMService, error) {
+	replayCfg := cfg.LLM.Replay
+	switch replay.Mode(replayCfg.Mode) {
+	case "":
+	case replay.ModeReplay:
+		slog.Info("Initializing LLM provider", "provider", "replay", "cassette_dir", replayCfg.CassetteDir)
+		return replay.New(replay.ModeReplay, replayCfg.CassetteDir, nil)
+	case replay.ModeRecord:
+		underlying, err := initializeProviderClient(cfg, vault)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Recording LLM interactions", "cassette_dir", replayCfg.CassetteDir)
+		return replay.New(replay.ModeRecord, replayCfg.CassetteDir, underlying)
+	default:
+		return nil, fmt.Errorf("invalid llm.replay.mode %q", replayCfg.Mode)
+	}
+
+	return initializeProviderClient(cfg, vault)
+}
+
+// initializeProviderClient creates the client for the configured real LLM provider.
+func initializeProviderClient(cfg *config.NuimanBotConfig, vault domain.CredentialVault) (domain.LLMService, error) {
 	// Try provider-specific configs first (new way)
+	// Check OpenAI key pool
+	if len(cfg.LLM.OpenAI.Keys) > 0 {
+		slog.Info("Initializing LLM provider", "provider", "openai", "source", "key_pool", "keys", len(cfg.LLM.OpenAI.Keys))
+		return keypool.FromConfig(context.Background(), domain.LLMProviderOpenAI, cfg.LLM.OpenAI.Keys, vault,
+			func(apiKey domain.SecureString) (domain.LLMService, error) {
+				openaiCfg := cfg.LLM.OpenAI
+				openaiCfg.APIKey = apiKey
+				return openai.New(&openaiCfg), nil
+			})
+	}
+
 	// Check OpenAI
 	if cfg.LLM.OpenAI.APIKey.Value() != "" {
 		slog.Info("Initializing LLM provider", "provider", "openai", "source", "legacy_config")
@@ -266,6 +413,18 @@ func initializeLLMService(cfg *config.NuimanBotConfig) (domain.LLMService, error
 		return ollama.New(&cfg.LLM.Ollama), nil
 	}
 
+	// Check Anthropic key pool
+	if len(cfg.LLM.Anthropic.Keys) > 0 {
+		slog.Info("Initializing LLM provider", "provider", "anthropic", "source", "key_pool", "keys", len(cfg.LLM.Anthropic.Keys))
+		return keypool.FromConfig(context.Background(), domain.LLMProviderAnthropic, cfg.LLM.Anthropic.Keys, vault,
+			func(apiKey domain.SecureString) (domain.LLMService, error) {
+				return anthropic.NewClient(&config.LLMProviderConfig{
+					Type:   domain.LLMProviderAnthropic,
+					APIKey: apiKey,
+				})
+			})
+	}
+
 	// Check Anthropic
 	if cfg.LLM.Anthropic.APIKey.Value() != "" {
 		slog.Info("Initializing LLM provider", "provider", "anthropic", "source", "legacy_config")
@@ -326,164 +485,393 @@ func initializeLLMService(cfg *config.NuimanBotConfig) (domain.LLMService, error
 	return nil, fmt.Errorf("no LLM providers configured (set llm.openai.api_key, llm.ollama.base_url, or llm.anthropic.api_key)")
 }
 
-// registerBuiltInTools registers all built-in skills with the registry.
-func registerBuiltInTools(registry tool.ToolRegistry, notesRepo *sqlite.NotesRepository, llmService domain.LLMService) error {
-	// Register Calculator skill
-	calc := calculator.NewCalculator()
-	if err := registry.Register(calc); err != nil {
-		return fmt.Errorf("failed to register calculator skill: %w", err)
-	}
-
-	// Register DateTime skill
-	dt := datetime.NewDateTime()
-	if err := registry.Register(dt); err != nil {
-		return fmt.Errorf("failed to register datetime skill: %w", err)
-	}
-
-	// Register Weather skill (if API key is available)
-	weatherAPIKey := os.Getenv("OPENWEATHERMAP_API_KEY")
-	if weatherAPIKey != "" {
-		w := weather.NewWeather(weatherAPIKey, 10)
-		if err := registry.Register(w); err != nil {
-			return fmt.Errorf("failed to register weather skill: %w", err)
+// registerOpenAPITools registers the selected operations of each configured
+// OpenAPI spec as HTTP tools.
+func registerOpenAPITools(registry tool.ToolRegistry, specs []config.OpenAPIConfig, vault domain.CredentialVault) error {
+	for _, spec := range specs {
+		defs, err := openapi.LoadFile(spec.Spec, openapi.Options{
+			Prefix:           spec.Prefix,
+			BaseURL:          spec.BaseURL,
+			Include:          spec.Include,
+			Exclude:          spec.Exclude,
+			AuthScheme:       spec.Auth.Scheme,
+			VaultKey:         spec.Auth.VaultKey,
+			MaxResponseChars: spec.MaxResponseChars,
+			Timeout:          spec.Timeout,
+		})
+		if err != nil {
+			return err
 		}
-		slog.Info("Skill registered", "skill", "weather")
-	} else {
-		slog.Warn("Skill skipped", "skill", "weather", "reason", "OPENWEATHERMAP_API_KEY not set")
+		for _, def := range defs {
+			t := httptool.NewTool(def, nil, vault)
+			t.SetSource(spec.Spec)
+			if err := registry.Register(t); err != nil {
+				return fmt.Errorf("%s: %w", spec.Spec, err)
+			}
+		}
+		slog.Info("OpenAPI tools registered", "spec", spec.Spec, "count", len(defs))
 	}
+	return nil
+}
 
-	// Register WebSearch skill
-	ws := websearch.NewWebSearch(10)
-	if err := registry.Register(ws); err != nil {
-		return fmt.Errorf("failed to register websearch skill: %w", err)
+// registerBuiltInTools builds the tools enabled in tools.entries and registers them.
+func registerBuiltInTools(registry tool.ToolRegistry, cfg *config.NuimanBotConfig, notesRepo *sqlite.NotesRepository, prefsRepo domain.PreferencesRepository, llmService domain.LLMService, auditor shell.Auditor) error {
+	factories, err := builtInToolFactories(notesRepo, prefsRepo, llmService, cfg.ToolSettings.Exec, cfg.ToolSettings.Fetch, auditor)
+	if err != nil {
+		return err
 	}
-	slog.Info("Skill registered", "skill", "websearch")
 
-	// Register Notes skill
-	notesSkill := notes.NewNotes(notesRepo)
-	if err := registry.Register(notesSkill); err != nil {
-		return fmt.Errorf("failed to register notes skill: %w", err)
+	tools, err := factories.Build(cfg.Tools.Entries)
+	if err != nil {
+		return fmt.Errorf("invalid tool configuration:\n%w", err)
 	}
-	slog.Info("Skill registered", "skill", "notes")
 
-	// Register Developer Productivity Skills (Phase 5)
-	if err := registerDeveloperProductivityTools(registry, llmService); err != nil {
-		return fmt.Errorf("failed to register developer productivity skills: %w", err)
+	for _, t := range tools {
+		if err := registry.Register(t); err != nil {
+			return fmt.Errorf("failed to register %s skill: %w", t.Name(), err)
+		}
+		slog.Info("Skill registered", "skill", t.Name())
 	}
 
-	slog.Info("Registered built-in skills successfully")
+	slog.Info("Registered built-in skills successfully", "count", len(tools))
 	return nil
 }
 
-// registerDeveloperProductivityTools registers developer productivity skills.
-func registerDeveloperProductivityTools(registry tool.ToolRegistry, llmService domain.LLMService) error {
-	// Create shared dependencies
-	executorSvc := executor.NewExecutorService()
+// builtInToolFactories declares every built-in tool: whether it is enabled
+// without a config entry, the params it accepts and how it is constructed.
+func builtInToolFactories(notesRepo *sqlite.NotesRepository, prefsRepo domain.PreferencesRepository, llmService domain.LLMService, execCfg config.ToolsExecConfig, fetchCfg config.ToolsFetchConfig, auditor shell.Auditor) (*tool.FactoryRegistry, error) {
+	// Shared dependencies
+	const mb = 1024 * 1024
+	executorSvc := executor.NewExecutorServiceWithOptions(executor.Options{
+		Limits: executor.ResourceLimits{
+			CPUSeconds:    uint64(execCfg.Limits.CPUSeconds),
+			MemoryBytes:   uint64(execCfg.Limits.MemoryMB) * mb,
+			FileSizeBytes: uint64(execCfg.Limits.FileSizeMB) * mb,
+			Processes:     uint64(execCfg.Limits.Processes),
+		},
+		SessionTTL: time.Duration(execCfg.SessionTTLMinutes) * time.Minute,
+	})
 	rateLimiter := common.NewRateLimiter()
 	sanitizer := common.NewOutputSanitizer()
-	httpClient := &http.Client{Timeout: 60 * time.Second}
+	fetcher := fetch.NewService(fetch.Options{
+		UserAgent:            fetchCfg.UserAgent,
+		MaxBytes:             int64(fetchCfg.MaxSizeMB) * mb,
+		Timeout:              time.Duration(fetchCfg.Timeout) * time.Second,
+		CacheTTL:             time.Duration(fetchCfg.CacheTTL) * time.Second,
+		IgnoreRobots:         fetchCfg.IgnoreRobots,
+		AllowPrivateNetworks: fetchCfg.AllowPrivateNetworks,
+	})
 
-	// Default workspace paths (can be configured later)
-	workspacePaths := []string{"."}
+	// Default workspace for tools that touch the filesystem
+	workspace := "."
 	if cwd, err := os.Getwd(); err == nil {
-		workspacePaths = []string{cwd}
+		workspace = cwd
 	}
-	pathValidator := common.NewPathValidator(workspacePaths)
 
-	// Register GitHubSkill
-	githubConfig := domain.ToolConfig{
-		Enabled: true,
-		Params: map[string]interface{}{
-			"timeout":    30,
-			"rate_limit": "30/minute",
-		},
+	timeoutParam := func(def int) map[string]any {
+		return map[string]any{"type": "integer", "minimum": 1, "default": def}
+	}
+	stringList := func(def ...string) map[string]any {
+		schema := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+		if len(def) > 0 {
+			values := make([]any, len(def))
+			for i, d := range def {
+				values[i] = d
+			}
+			schema["default"] = values
+		}
+		return schema
 	}
-	githubSkill := github.NewGitHubSkill(githubConfig, executorSvc, rateLimiter, sanitizer)
-	if err := registry.Register(githubSkill); err != nil {
-		return fmt.Errorf("failed to register github skill: %w", err)
+	params := func(props map[string]any) map[string]any {
+		return map[string]any{"type": "object", "properties": props, "additionalProperties": false}
 	}
-	slog.Info("Skill registered", "skill", "github")
 
-	// Register RepoSearchSkill
-	repoSearchConfig := domain.ToolConfig{
-		Enabled: true,
-		Params: map[string]interface{}{
-			"allowed_directories": workspacePaths,
+	factories := map[string]tool.Factory{
+		"calculator": {
+			Enabled: true,
+			Params:  params(map[string]any{}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				return calculator.NewCalculator(), nil
+			},
+		},
+		"datetime": {
+			Enabled: true,
+			Params:  params(map[string]any{"timezone": map[string]any{"type": "string", "minLength": 1}}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				dt := datetime.NewDateTime()
+				dt.SetPreferencesRepository(prefsRepo)
+				if tz, ok := cfg.Params["timezone"].(string); ok {
+					if err := dt.SetDefaultTimezone(tz); err != nil {
+						return nil, err
+					}
+				}
+				return dt, nil
+			},
+		},
+		"weather": {
+			Enabled: true,
+			Params: params(map[string]any{
+				"provider":      map[string]any{"type": "string", "enum": []string{"open-meteo", "openweathermap"}},
+				"timeout":       timeoutParam(10),
+				"cache_ttl":     map[string]any{"type": "integer", "minimum": 0, "default": 600},
+				"base_url":      map[string]any{"type": "string", "minLength": 1},
+				"geocoding_url": map[string]any{"type": "string", "minLength": 1},
+			}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				provider, err := weatherProvider(cfg)
+				if err != nil {
+					return nil, err
+				}
+				return weather.NewWeatherWithProvider(provider), nil
+			},
+		},
+		"websearch": {
+			Enabled: true,
+			Params: params(map[string]any{
+				"provider":    map[string]any{"type": "string", "enum": []string{"duckduckgo", "searxng", "brave", "tavily"}, "default": "duckduckgo"},
+				"timeout":     timeoutParam(10),
+				"cache_ttl":   map[string]any{"type": "integer", "minimum": 0, "default": 300},
+				"base_url":    map[string]any{"type": "string", "minLength": 1},
+				"region":      map[string]any{"type": "string", "pattern": "^[A-Za-z]{2}-[A-Za-z]{2}$"},
+				"safe_search": map[string]any{"type": "string", "enum": []string{"off", "moderate", "strict"}, "default": "moderate"},
+			}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				provider, err := searchProvider(cfg)
+				if err != nil {
+					return nil, err
+				}
+				region, _ := cfg.Params["region"].(string)
+				return websearch.NewWebSearchWithProvider(provider, searchClient.Options{
+					Region:     region,
+					SafeSearch: searchClient.SafeSearch(cfg.Params["safe_search"].(string)),
+				}), nil
+			},
+		},
+		"web_fetch": {
+			Enabled: true,
+			Params:  params(map[string]any{}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				return webfetch.NewWebFetch(fetcher), nil
+			},
+		},
+		"notes": {
+			Enabled: true,
+			Params:  params(map[string]any{}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				return notes.NewNotes(notesRepo), nil
+			},
+		},
+		"preferences": {
+			Enabled: true,
+			Params:  params(map[string]any{}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				return preferences.NewPreferences(prefsRepo), nil
+			},
+		},
+		"github": {
+			Enabled: true,
+			Params: params(map[string]any{
+				"timeout":      timeoutParam(30),
+				"rate_limit":   map[string]any{"type": "string", "pattern": `^\d+/(second|minute|hour)$`, "default": "30/minute"},
+				"default_repo": map[string]any{"type": "string", "pattern": `^[\w.-]+/[\w.-]+$`},
+			}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				return github.NewGitHubSkill(cfg, executorSvc, rateLimiter, sanitizer), nil
+			},
+		},
+		"repo_search": {
+			Enabled: true,
+			Params:  params(map[string]any{"allowed_directories": stringList(workspace)}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				dirs := stringParams(cfg.Params["allowed_directories"])
+				return repo_search.NewRepoSearchSkill(cfg, executorSvc, common.NewPathValidator(dirs), sanitizer), nil
+			},
+		},
+		"doc_summarize": {
+			Enabled: true,
+			Params: params(map[string]any{
+				"allowed_domains":   stringList("github.com", "docs.google.com", "notion.so"),
+				"max_document_size": map[string]any{"type": "integer", "minimum": 1, "default": 5 * 1024 * 1024},
+				"timeout":           timeoutParam(60),
+			}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				return doc_summarize.NewDocSummarizeSkill(cfg, llmService, fetcher), nil
+			},
+		},
+		"summarize": {
+			Enabled: true,
+			Params: params(map[string]any{
+				"timeout":    timeoutParam(90),
+				"user_agent": map[string]any{"type": "string", "minLength": 1, "default": "NuimanBot/1.0"},
+			}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				return summarize.NewSummarizeSkill(cfg, llmService, executorSvc, fetcher), nil
+			},
+		},
+		"coding_agent": {
+			Enabled: false, // Admin must explicitly enable
+			Params: params(map[string]any{
+				"allowed_tools": map[string]any{
+					"type": "array",
+					"items": map[string]any{"type": "string", "enum": []string{
+						coding_agent.ToolCodex, coding_agent.ToolClaudeCode, coding_agent.ToolOpenCode,
+						coding_agent.ToolGemini, coding_agent.ToolCopilot,
+					}},
+					"default": []any{coding_agent.ToolCodex, coding_agent.ToolClaudeCode},
+				},
+				"allowed_directories": stringList(workspace),
+			}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				dirs := stringParams(cfg.Params["allowed_directories"])
+				return coding_agent.NewCodingAgentSkill(cfg, executorSvc, common.NewPathValidator(dirs)), nil
+			},
+		},
+		"files": {
+			Enabled: true,
+			Params: params(map[string]any{
+				"allowed_directories": stringList(workspace),
+				"max_output_chars":    map[string]any{"type": "integer", "minimum": 1, "default": 16000},
+				"max_file_size":       map[string]any{"type": "integer", "minimum": 1, "default": 10 * 1024 * 1024},
+				"read_only":           map[string]any{"type": "boolean", "default": true},
+			}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				return files.NewFilesSkill(cfg, files.Settings{
+					Workspaces:     stringParams(cfg.Params["allowed_directories"]),
+					MaxOutputChars: int(cfg.Params["max_output_chars"].(float64)),
+					MaxFileSize:    int64(cfg.Params["max_file_size"].(float64)),
+					ReadOnly:       cfg.Params["read_only"].(bool),
+				}), nil
+			},
+		},
+		"exec": {
+			Enabled: false, // Admin must explicitly enable; limits come from tool_settings.exec
+			Params:  params(map[string]any{}),
+			New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+				execWorkspace := execCfg.Workspace
+				if execWorkspace == "" {
+					execWorkspace = workspace
+				}
+				return shell.NewExecSkill(cfg, shell.Settings{
+					Workspace:           execWorkspace,
+					RestrictToWorkspace: execCfg.RestrictToWorkspace,
+					AllowedCommands:     execCfg.AllowedCommands,
+					DeniedCommands:      execCfg.DeniedCommands,
+					AllowedEnv:          execCfg.AllowedEnv,
+					Timeout:             time.Duration(execCfg.Timeout) * time.Second,
+					MaxOutputChars:      execCfg.MaxOutputChars,
+					AllowBackground:     execCfg.AllowBackground,
+				}, executorSvc, sanitizer, auditor), nil
+			},
 		},
 	}
-	repoSearchSkill := repo_search.NewRepoSearchSkill(repoSearchConfig, executorSvc, pathValidator, sanitizer)
-	if err := registry.Register(repoSearchSkill); err != nil {
-		return fmt.Errorf("failed to register repo_search skill: %w", err)
-	}
-	slog.Info("Skill registered", "skill", "repo_search")
 
-	// Register DocSummarizeSkill
-	docSummarizeConfig := domain.ToolConfig{
-		Enabled: true,
-		Params: map[string]interface{}{
-			"allowed_domains":   []interface{}{"github.com", "docs.google.com", "notion.so"},
-			"max_document_size": 5 * 1024 * 1024,
-		},
+	registry := tool.NewFactoryRegistry()
+	for name, factory := range factories {
+		if err := registry.Register(name, factory); err != nil {
+			return nil, err
+		}
 	}
-	docSummarizeSkill := doc_summarize.NewDocSummarizeSkill(docSummarizeConfig, llmService, httpClient)
-	if err := registry.Register(docSummarizeSkill); err != nil {
-		return fmt.Errorf("failed to register doc_summarize skill: %w", err)
+	return registry, nil
+}
+
+// weatherProvider builds the weather tool's provider. OpenWeatherMap is used
+// when selected or when an api_key is set; otherwise the keyless Open-Meteo.
+func weatherProvider(cfg domain.ToolConfig) (weatherClient.Provider, error) {
+	timeout := int(cfg.Params["timeout"].(float64))
+	baseURL, _ := cfg.Params["base_url"].(string)
+	geocodingURL, _ := cfg.Params["geocoding_url"].(string)
+
+	name, _ := cfg.Params["provider"].(string)
+	if name == "" {
+		name = "open-meteo"
+		if cfg.APIKey.Value() != "" {
+			name = "openweathermap"
+		}
 	}
-	slog.Info("Skill registered", "skill", "doc_summarize")
 
-	// Register SummarizeSkill
-	summarizeConfig := domain.ToolConfig{
-		Enabled: true,
-		Params: map[string]interface{}{
-			"timeout":    90,
-			"user_agent": "NuimanBot/1.0",
-		},
+	var provider weatherClient.Provider
+	switch name {
+	case "openweathermap":
+		if cfg.APIKey.Value() == "" {
+			return nil, fmt.Errorf("api_key is required for the openweathermap provider")
+		}
+		if geocodingURL != "" {
+			return nil, fmt.Errorf("params.geocoding_url is only used by the open-meteo provider")
+		}
+		if baseURL != "" {
+			provider = weatherClient.NewClientWithBaseURL(cfg.APIKey.Value(), timeout, baseURL)
+		} else {
+			provider = weatherClient.NewClient(cfg.APIKey.Value(), timeout)
+		}
+	default:
+		provider = weatherClient.NewOpenMeteoClientWithBaseURLs(timeout, baseURL, geocodingURL)
 	}
-	summarizeSkill := summarize.NewSummarizeSkill(summarizeConfig, llmService, executorSvc, httpClient)
-	if err := registry.Register(summarizeSkill); err != nil {
-		return fmt.Errorf("failed to register summarize skill: %w", err)
+
+	if ttl := cfg.Params["cache_ttl"].(float64); ttl > 0 {
+		provider = weatherClient.NewCachedProvider(provider, time.Duration(ttl)*time.Second)
 	}
-	slog.Info("Skill registered", "skill", "summarize")
+	return provider, nil
+}
 
-	// Register CodingAgentSkill
-	codingAgentConfig := domain.ToolConfig{
-		Enabled: false, // Admin must explicitly enable
-		Params: map[string]interface{}{
-			"allowed_tools": []interface{}{"codex", "claude_code"},
-			"default_mode":  "interactive",
-			"pty_mode":      true,
-		},
+// searchProvider builds the websearch tool's provider from its params.
+// Brave and Tavily need an api_key and SearxNG the base_url of an instance.
+func searchProvider(cfg domain.ToolConfig) (searchClient.Provider, error) {
+	timeout := int(cfg.Params["timeout"].(float64))
+	baseURL, _ := cfg.Params["base_url"].(string)
+	apiKey := cfg.APIKey.Value()
+
+	var provider searchClient.Provider
+	switch cfg.Params["provider"].(string) {
+	case "searxng":
+		if baseURL == "" {
+			return nil, fmt.Errorf("params.base_url is required for the searxng provider")
+		}
+		provider = searchClient.NewSearxNGClient(timeout, baseURL)
+	case "brave":
+		if apiKey == "" {
+			return nil, fmt.Errorf("api_key is required for the brave provider")
+		}
+		provider = searchClient.NewBraveClientWithBaseURL(apiKey, timeout, baseURL)
+	case "tavily":
+		if apiKey == "" {
+			return nil, fmt.Errorf("api_key is required for the tavily provider")
+		}
+		provider = searchClient.NewTavilyClientWithBaseURL(apiKey, timeout, baseURL)
+	default:
+		if baseURL != "" {
+			provider = searchClient.NewClientWithBaseURL(timeout, baseURL)
+		} else {
+			provider = searchClient.NewClient(timeout)
+		}
 	}
-	codingAgentSkill := coding_agent.NewCodingAgentSkill(codingAgentConfig, executorSvc, pathValidator)
-	if err := registry.Register(codingAgentSkill); err != nil {
-		return fmt.Errorf("failed to register coding_agent skill: %w", err)
+
+	if ttl := cfg.Params["cache_ttl"].(float64); ttl > 0 {
+		provider = searchClient.NewCachedProvider(provider, time.Duration(ttl)*time.Second)
 	}
-	slog.Info("Skill registered", "skill", "coding_agent")
+	return provider, nil
+}
 
-	slog.Info("Registered developer productivity skills successfully")
-	return nil
+// stringParams converts a validated string list param to []string.
+func stringParams(v any) []string {
+	list, _ := v.([]any)
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
 }
 
 // initializeDatabase creates necessary tables if they don't exist.
 func initializeDatabase(db *sql.DB) error {
-	// Create users table
-	_, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS users (
-			id TEXT PRIMARY KEY,
-			platform TEXT NOT NULL,
-			platform_uid TEXT NOT NULL,
-			role TEXT NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE(platform, platform_uid)
-		)
-	`)
-	if err != nil {
+	// Create users table (migrating the legacy per-identity schema)
+	if err := sqlite.NewUserRepository(db).Init(context.Background()); err != nil {
 		return fmt.Errorf("failed to create users table: %w", err)
 	}
 
 	// Create messages table
-	_, err = db.Exec(`
+	_, err := db.Exec(`
 		CREATE TABLE IF NOT EXISTS messages (
 			id TEXT PRIMARY KEY,
 			conversation_id TEXT NOT NULL,
@@ -567,17 +955,6 @@ func initializeDatabase(db *sql.DB) error {
 		return fmt.Errorf("failed to create conversations user index: %w", err)
 	}
 
-	// Create unique index on users for platform-specific user lookups
-	// Note: This is redundant with the UNIQUE constraint in the table definition,
-	// but explicit indexes can help with query planning
-	_, err = db.Exec(`
-		CREATE UNIQUE INDEX IF NOT EXISTS idx_users_platform_uid
-		ON users(platform, platform_uid)
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to create users platform index: %w", err)
-	}
-
 	slog.Info("Database schema initialized successfully")
 	return nil
 }
@@ -594,6 +971,15 @@ func (app *application) Run(ctx context.Context) error {
 		}
 	}()
 
+	// Resume unfinished batch jobs and poll provider batches
+	go app.BatchService.Run(ctx)
+	defer app.BatchService.Stop()
+
+	// Hot-reload HTTP tool definitions
+	if app.HTTPToolLoader != nil {
+		go app.HTTPToolLoader.Watch(ctx, httptool.DefaultWatchInterval)
+	}
+
 	// Track active gateways for proper shutdown
 	var gateways []domain.Gateway
 
@@ -633,6 +1019,18 @@ func (app *application) Run(ctx context.Context) error {
 	// Initialize CLI gateway
 	cliGateway := cli.NewGateway(&app.Config.Gateways.CLI)
 	cliGateway.SetSkillHandler(skillHandler) // Enable /skill-name command support
+	adminHandler := cli.NewAdminCommandHandler(nil)
+	adminHandler.SetBatchService(app.BatchService) // Enable /admin batch
+	if app.TraceStore != nil {
+		adminHandler.SetTraceStore(app.TraceStore) // Enable /admin trace
+	}
+	cliGateway.SetAdminHandler(adminHandler)
+	// Authorize "/admin" as the operator's stored user or CLI platform role
+	if err := cliGateway.ResolveOperator(ctx, app.UserResolver); err != nil {
+		slog.Warn("Admin commands unavailable", "error", err)
+	}
+	// Forked skills run tools as the operator
+	skillCmd.SetUser(cliGateway.CurrentUser())
 	app.connectGateway(cliGateway)
 
 	// Phase 7: Connect skill handler to chat service through gateway's message handler
@@ -644,7 +1042,7 @@ func (app *application) Run(ctx context.Context) error {
 		}
 		return cliGateway.Send(ctx, response)
 	}
-	skillHandler.SetMessageHandler(messageHandler, domain.PlatformCLI, "cli_user")
+	skillHandler.SetMessageHandler(messageHandler, domain.PlatformCLI, cli.OperatorUID)
 
 	gateways = append(gateways, cliGateway) //nolint:staticcheck // Reserved for future shutdown handling
 	_ = gateways                            // Prevent unused variable warning
@@ -652,3 +652,64 @@ func TestCodingAgentToolRegistration(t *testing.T) {
 
 	t.Logf("coding_agent tool registered successfully: %s", desc)
 }
+
+// TestStoredUserRoleScopesTools tests that a user stored in SQLite gets the
+// tools of their role, both in the list offered to the LLM and when run.
+func TestStoredUserRoleScopesTools(t *testing.T) {
+	app, cleanup := setupTestApp(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mockLLM := app.LLMService.(*mockLLMService)
+	mockLLM.SetToolCall("List my notes", domain.ToolCall{
+		ToolName:  "notes",
+		Arguments: map[string]any{"operation": "list"},
+	})
+
+	offered := func() map[string]bool {
+		names := make(map[string]bool)
+		for _, def := range mockLLM.lastTools {
+			names[def.Name] = true
+		}
+		return names
+	}
+
+	// Unregistered identity: default role (guest) is not offered notes and
+	// cannot run it
+	msg := createTestMessage("List my notes")
+	response, err := app.ChatService.ProcessMessage(ctx, &msg)
+	if err != nil {
+		t.Fatalf("ProcessMessage failed: %v", err)
+	}
+	if tools := offered(); tools["notes"] || !tools["calculator"] {
+		t.Errorf("Expected guest tools without notes, got %v", tools)
+	}
+	if !strings.Contains(response.Content, "Error: insufficient permissions") {
+		t.Errorf("Expected the notes call to be denied, got: %s", response.Content)
+	}
+
+	// Store the identity as a registered user
+	err = app.UserRepo.SaveUser(ctx, &domain.User{
+		ID:           "stored-user",
+		Username:     "tester",
+		Role:         domain.RoleUser,
+		PlatformIDs:  map[domain.Platform]string{msg.Platform: msg.PlatformUID},
+		AllowedTools: []string{},
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("SaveUser failed: %v", err)
+	}
+
+	msg.ID = "test-msg-id-2"
+	response, err = app.ChatService.ProcessMessage(ctx, &msg)
+	if err != nil {
+		t.Fatalf("ProcessMessage failed: %v", err)
+	}
+	if tools := offered(); !tools["notes"] {
+		t.Errorf("Expected the stored user to be offered notes, got %v", tools)
+	}
+	if !strings.Contains(response.Content, "Result: No notes found") {
+		t.Errorf("Expected the notes call to run, got: %s", response.Content)
+	}
+}
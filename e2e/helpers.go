@@ -11,7 +11,6 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 
-	"net/http"
 	"time"
 
 	"nuimanbot/internal/adapter/gateway/cli"
@@ -19,6 +18,7 @@ import (
 	"nuimanbot/internal/config"
 	"nuimanbot/internal/domain"
 	"nuimanbot/internal/infrastructure/crypto"
+	"nuimanbot/internal/infrastructure/fetch"
 	"nuimanbot/internal/tools/calculator"
 	"nuimanbot/internal/tools/datetime"
 	"nuimanbot/internal/tools/notes"
@@ -35,6 +35,7 @@ import (
 	"nuimanbot/internal/usecase/tool/github"
 	"nuimanbot/internal/usecase/tool/repo_search"
 	"nuimanbot/internal/usecase/tool/summarize"
+	"nuimanbot/internal/usecase/user"
 )
 
 // testApplication represents a fully-initialized NuimanBot application for testing.
@@ -47,6 +48,7 @@ type testApplication struct {
 	ToolRegistry         tool.ToolRegistry
 	Vault                domain.CredentialVault
 	ToolExecutionService *tool.Service
+	UserRepo             *sqlite.UserRepository
 	DB                   *sql.DB
 	CLIGateway           *cli.Gateway
 	TempDir              string
@@ -55,18 +57,22 @@ type testApplication struct {
 // mockLLMService implements domain.LLMService for testing without real API calls.
 type mockLLMService struct {
 	responses map[string]string
+	toolCalls map[string]domain.ToolCall
+	lastTools []domain.ToolDefinition // Tools offered with the latest request
 	callCount int
 }
 
 func newMockLLMService() *mockLLMService {
 	return &mockLLMService{
 		responses: make(map[string]string),
+		toolCalls: make(map[string]domain.ToolCall),
 		callCount: 0,
 	}
 }
 
 func (m *mockLLMService) Complete(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
 	m.callCount++
+	m.lastTools = req.Tools
 
 	// Default behavior: echo the last user message
 	var lastUserMsg string
@@ -76,7 +82,14 @@ func (m *mockLLMService) Complete(ctx context.Context, provider domain.LLMProvid
 		}
 	}
 
-	// Check for mock responses
+	// Check for mock tool calls, then mock responses
+	if call, ok := m.toolCalls[lastUserMsg]; ok {
+		return &domain.LLMResponse{
+			ToolCalls:    []domain.ToolCall{call},
+			Usage:        domain.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			FinishReason: domain.FinishReasonToolCalls,
+		}, nil
+	}
 	if response, ok := m.responses[lastUserMsg]; ok {
 		return &domain.LLMResponse{
 			Content:      response,
@@ -97,6 +110,12 @@ func (m *mockLLMService) SetResponse(input, output string) {
 	m.responses[input] = output
 }
 
+// SetToolCall makes the mock answer input with a call to a tool. The tool
+// results that follow are echoed back like any other message.
+func (m *mockLLMService) SetToolCall(input string, call domain.ToolCall) {
+	m.toolCalls[input] = call
+}
+
 func (m *mockLLMService) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
 	// Not implemented for E2E tests
 	return nil, fmt.Errorf("streaming not implemented in mock")
@@ -219,7 +238,7 @@ func setupTestApp(t *testing.T) (*testApplication, func()) {
 	executorSvc := executor.NewExecutorService()
 	rateLimiter := common.NewRateLimiter()
 	sanitizer := common.NewOutputSanitizer()
-	httpClient := &http.Client{Timeout: 60 * time.Second}
+	fetcher := fetch.NewService(fetch.Options{Timeout: 60 * time.Second})
 
 	workspacePaths := []string{tempDir}
 	pathValidator := common.NewPathValidator(workspacePaths)
@@ -250,7 +269,7 @@ func setupTestApp(t *testing.T) (*testApplication, func()) {
 	docSummarizeSkill := doc_summarize.NewDocSummarizeSkill(
 		domain.ToolConfig{Enabled: true},
 		llmService,
-		httpClient,
+		fetcher,
 	)
 	if err := toolRegistry.Register(docSummarizeSkill); err != nil {
 		t.Fatalf("Failed to register doc_summarize tool: %v", err)
@@ -261,7 +280,7 @@ func setupTestApp(t *testing.T) (*testApplication, func()) {
 		domain.ToolConfig{Enabled: true},
 		llmService,
 		executorSvc,
-		httpClient,
+		fetcher,
 	)
 	if err := toolRegistry.Register(summarizeSkill); err != nil {
 		t.Fatalf("Failed to register summarize tool: %v", err)
@@ -278,9 +297,16 @@ func setupTestApp(t *testing.T) (*testApplication, func()) {
 	}
 
 	toolExecutionService := tool.NewService(&cfg.Tools, toolRegistry, securityService)
+	if results := toolExecutionService.Results(); results != nil {
+		if err := toolRegistry.Register(tool.NewReadResultTool(results)); err != nil {
+			t.Fatalf("Failed to register read_result tool: %v", err)
+		}
+	}
 
-	// Initialize chat service
+	// Initialize chat service, resolving identities to stored users
 	chatService := chat.NewService(llmService, memoryRepo, toolExecutionService, securityService)
+	userRepo := sqlite.NewUserRepository(db)
+	chatService.SetUserResolver(user.NewResolver(userRepo, cfg.Security.DefaultRole, cfg.Security.PlatformRoles))
 
 	// Initialize CLI gateway
 	cliGateway := cli.NewGateway(&cfg.Gateways.CLI)
@@ -295,6 +321,7 @@ func setupTestApp(t *testing.T) (*testApplication, func()) {
 		ToolRegistry:         toolRegistry,
 		ChatService:          chatService,
 		ToolExecutionService: toolExecutionService,
+		UserRepo:             userRepo,
 		DB:                   db,
 		CLIGateway:           cliGateway,
 		TempDir:              tempDir,
@@ -314,24 +341,13 @@ func setupTestApp(t *testing.T) (*testApplication, func()) {
 
 // initializeTestDatabase creates the database schema for testing.
 func initializeTestDatabase(db *sql.DB) error {
-	// Create users table
-	_, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS users (
-			id TEXT PRIMARY KEY,
-			platform TEXT NOT NULL,
-			platform_uid TEXT NOT NULL,
-			role TEXT NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE(platform, platform_uid)
-		)
-	`)
-	if err != nil {
+	// Create users table (migrating the legacy per-identity schema)
+	if err := sqlite.NewUserRepository(db).Init(context.Background()); err != nil {
 		return fmt.Errorf("failed to create users table: %w", err)
 	}
 
 	// Create messages table
-	_, err = db.Exec(`
+	_, err := db.Exec(`
 		CREATE TABLE IF NOT EXISTS messages (
 			id TEXT PRIMARY KEY,
 			conversation_id TEXT NOT NULL,
@@ -363,6 +379,23 @@ func initializeTestDatabase(db *sql.DB) error {
 		return fmt.Errorf("failed to create conversations table: %w", err)
 	}
 
+	// Create notes table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS notes (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			content TEXT NOT NULL,
+			tags TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY(user_id) REFERENCES users(id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create notes table: %w", err)
+	}
+
 	return nil
 }
 
@@ -38,6 +38,7 @@ type SkillCommand struct {
 	renderer  SkillRenderer
 	output    io.Writer
 	lifecycle LifecycleManager
+	user      *domain.User
 }
 
 // NewSkillCommand creates a new skill command handler.
@@ -58,6 +59,12 @@ func (c *SkillCommand) SetLifecycleManager(lifecycle LifecycleManager) {
 	c.lifecycle = lifecycle
 }
 
+// SetUser sets the user forked subagents run tools as. Without a user,
+// subagents cannot call tools.
+func (c *SkillCommand) SetUser(user *domain.User) {
+	c.user = user
+}
+
 // Execute executes a skill by name with arguments.
 // Returns rendered prompt and allowed tools.
 // For skills with context: fork, starts a subagent and returns immediately.
@@ -98,6 +105,7 @@ func (c *SkillCommand) executeAsSubagent(ctx context.Context, skill *domain.Skil
 	subagentCtx := domain.SubagentContext{
 		ID:              fmt.Sprintf("subagent-%s-%d", skill.Name, time.Now().UnixNano()),
 		ParentContextID: "cli-parent",
+		User:            c.user,
 		SkillName:       skill.Name,
 		AllowedTools:    rendered.AllowedTools,
 		ResourceLimits:  domain.DefaultResourceLimits(),
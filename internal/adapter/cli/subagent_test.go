@@ -82,9 +82,12 @@ func TestSkillCommand_Execute_ForkContext(t *testing.T) {
 		statusResults: make(map[string]*domain.SubagentResult),
 	}
 
+	operator := &domain.User{ID: "operator", Role: domain.RoleAdmin}
+
 	output := &bytes.Buffer{}
 	cmd := NewSkillCommand(registry, renderer, output)
 	cmd.SetLifecycleManager(lifecycle)
+	cmd.SetUser(operator)
 
 	ctx := context.Background()
 	result, err := cmd.Execute(ctx, "fork-skill", []string{})
@@ -111,6 +114,10 @@ func TestSkillCommand_Execute_ForkContext(t *testing.T) {
 	if len(started.AllowedTools) != 2 {
 		t.Errorf("AllowedTools count = %d, want 2", len(started.AllowedTools))
 	}
+
+	if started.User != operator {
+		t.Errorf("Started user = %v, want the operator", started.User)
+	}
 }
 
 // TestSkillCommand_Execute_InlineContext tests normal inline execution
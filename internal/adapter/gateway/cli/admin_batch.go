@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/batch"
+)
+
+// batchResultsPageSize is the number of items shown per "/admin batch results" page.
+const batchResultsPageSize = 10
+
+// BatchService is the subset of batch.Service used by admin commands.
+type BatchService interface {
+	Submit(ctx context.Context, spec batch.JobSpec) (*domain.BatchJob, error)
+	Get(ctx context.Context, jobID string) (*domain.BatchJob, error)
+	List(ctx context.Context, limit int) ([]*domain.BatchJob, error)
+	Results(ctx context.Context, jobID string, offset, limit int) ([]domain.BatchItem, error)
+	Cancel(ctx context.Context, jobID string) (*domain.BatchJob, error)
+}
+
+// SetBatchService enables the batch commands.
+func (h *AdminCommandHandler) SetBatchService(svc BatchService) {
+	h.batchService = svc
+}
+
+// handleBatchCommand handles batch job subcommands.
+func (h *AdminCommandHandler) handleBatchCommand(ctx context.Context, currentUser *domain.User, args []string) (string, error) {
+	if h.batchService == nil {
+		return "Batch jobs are not available.", nil
+	}
+	if len(args) == 0 {
+		return "Usage: /admin batch <submit|list|status|results|cancel> [args...]", nil
+	}
+
+	switch args[0] {
+	case "submit":
+		return h.submitBatch(ctx, currentUser, args[1:])
+	case "list":
+		return h.listBatches(ctx)
+	case "status":
+		return h.batchStatus(ctx, args[1:])
+	case "results":
+		return h.batchResults(ctx, args[1:])
+	case "cancel":
+		return h.cancelBatch(ctx, args[1:])
+	default:
+		return fmt.Sprintf("Unknown batch command: %s", args[0]), nil
+	}
+}
+
+// submitBatch launches a job from a JSONL file of requests.
+// Usage: /admin batch submit <provider> <file.jsonl> [--model <model>] [--name <name>] [--local]
+func (h *AdminCommandHandler) submitBatch(ctx context.Context, currentUser *domain.User, args []string) (string, error) {
+	const usage = "Usage: /admin batch submit <provider> <file.jsonl> [--model <model>] [--name <name>] [--local]"
+	if len(args) < 2 {
+		return usage, nil
+	}
+
+	spec := batch.JobSpec{
+		Provider:  domain.LLMProvider(args[0]),
+		CreatedBy: currentUser.ID,
+	}
+	path := args[1]
+	var model string
+
+	for i := 2; i < len(args); i++ {
+		switch args[i] {
+		case "--model", "--name":
+			if i+1 >= len(args) {
+				return fmt.Sprintf("Missing value for %s", args[i]), nil
+			}
+			if args[i] == "--model" {
+				model = args[i+1]
+			} else {
+				spec.Name = args[i+1]
+			}
+			i++
+		case "--local":
+			spec.Local = true
+		default:
+			return fmt.Sprintf("Unknown flag: %s\n%s", args[i], usage), nil
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open batch input: %w", err)
+	}
+	defer file.Close()
+
+	spec.Requests, err = batch.ParseRequests(file, model)
+	if err != nil {
+		return fmt.Sprintf("Invalid batch input: %v", err), nil
+	}
+
+	job, err := h.batchService.Submit(ctx, spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit batch: %w", err)
+	}
+
+	return fmt.Sprintf("✓ Batch job submitted\nID: %s\nName: %s\nMode: %s\nRequests: %d\nStatus: %s",
+		job.ID, job.Name, job.Mode, job.Counts.Total(), job.Status), nil
+}
+
+// listBatches lists recent jobs.
+// Usage: /admin batch list
+func (h *AdminCommandHandler) listBatches(ctx context.Context) (string, error) {
+	jobs, err := h.batchService.List(ctx, 20)
+	if err != nil {
+		return "", fmt.Errorf("failed to list batch jobs: %w", err)
+	}
+	if len(jobs) == 0 {
+		return "No batch jobs found.", nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Found %d batch job(s):\n\n", len(jobs)))
+	for i, job := range jobs {
+		result.WriteString(fmt.Sprintf("%d. %s (%s)\n", i+1, job.Name, job.ID))
+		result.WriteString(fmt.Sprintf("   %s via %s, %s, %s\n",
+			job.Provider, job.Mode, job.Status, formatBatchProgress(job.Counts)))
+	}
+	return result.String(), nil
+}
+
+// batchStatus shows a job's progress.
+// Usage: /admin batch status <job_id>
+func (h *AdminCommandHandler) batchStatus(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "Usage: /admin batch status <job_id>", nil
+	}
+
+	job, err := h.batchService.Get(ctx, args[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to get batch job: %w", err)
+	}
+
+	var result strings.Builder
+	result.WriteString("Batch Job:\n")
+	result.WriteString(fmt.Sprintf("ID: %s\n", job.ID))
+	result.WriteString(fmt.Sprintf("Name: %s\n", job.Name))
+	result.WriteString(fmt.Sprintf("Provider: %s (%s)\n", job.Provider, job.Mode))
+	if job.ProviderBatchID != "" {
+		result.WriteString(fmt.Sprintf("Provider Batch ID: %s\n", job.ProviderBatchID))
+	}
+	result.WriteString(fmt.Sprintf("Status: %s\n", job.Status))
+	result.WriteString(fmt.Sprintf("Progress: %s\n", formatBatchProgress(job.Counts)))
+	result.WriteString(fmt.Sprintf("Created: %s\n", job.CreatedAt.Format("2006-01-02 15:04:05")))
+	if !job.CompletedAt.IsZero() {
+		result.WriteString(fmt.Sprintf("Completed: %s\n", job.CompletedAt.Format("2006-01-02 15:04:05")))
+	}
+	if job.Error != "" {
+		result.WriteString(fmt.Sprintf("Error: %s\n", job.Error))
+	}
+	return result.String(), nil
+}
+
+// batchResults shows a page of a job's results, including partial results of running jobs.
+// Usage: /admin batch results <job_id> [page]
+func (h *AdminCommandHandler) batchResults(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "Usage: /admin batch results <job_id> [page]", nil
+	}
+
+	page := 1
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Sprintf("Invalid page: %s", args[1]), nil
+		}
+		page = n
+	}
+
+	items, err := h.batchService.Results(ctx, args[0], (page-1)*batchResultsPageSize, batchResultsPageSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to get batch results: %w", err)
+	}
+	if len(items) == 0 {
+		return "No results on this page.", nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Results (page %d):\n", page))
+	for _, item := range items {
+		result.WriteString(fmt.Sprintf("\n[%s] %s\n", item.CustomID, item.Status))
+		switch {
+		case item.Response != nil:
+			result.WriteString(item.Response.Content)
+			result.WriteString("\n")
+		case item.Error != "":
+			result.WriteString(fmt.Sprintf("Error: %s\n", item.Error))
+		}
+	}
+	if len(items) == batchResultsPageSize {
+		result.WriteString(fmt.Sprintf("\nNext page: /admin batch results %s %d\n", args[0], page+1))
+	}
+	return result.String(), nil
+}
+
+// cancelBatch cancels a job.
+// Usage: /admin batch cancel <job_id>
+func (h *AdminCommandHandler) cancelBatch(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "Usage: /admin batch cancel <job_id>", nil
+	}
+
+	job, err := h.batchService.Cancel(ctx, args[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to cancel batch job: %w", err)
+	}
+	return fmt.Sprintf("✓ Batch job %s is %s (%s)", job.ID, job.Status, formatBatchProgress(job.Counts)), nil
+}
+
+// formatBatchProgress summarizes item counts, e.g. "12/40 done, 1 failed".
+func formatBatchProgress(c domain.BatchItemCounts) string {
+	done := c.Succeeded + c.Failed + c.Cancelled
+	progress := fmt.Sprintf("%d/%d done", done, c.Total())
+	if c.Failed > 0 {
+		progress += fmt.Sprintf(", %d failed", c.Failed)
+	}
+	if c.Cancelled > 0 {
+		progress += fmt.Sprintf(", %d cancelled", c.Cancelled)
+	}
+	return progress
+}
@@ -0,0 +1,133 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/adapter/gateway/cli"
+	"nuimanbot/internal/config"
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/batch"
+	"nuimanbot/internal/usecase/user"
+)
+
+// stubBatchService records submissions and returns a fixed job.
+type stubBatchService struct {
+	submitted batch.JobSpec
+	job       *domain.BatchJob
+	items     []domain.BatchItem
+}
+
+func (s *stubBatchService) Submit(ctx context.Context, spec batch.JobSpec) (*domain.BatchJob, error) {
+	s.submitted = spec
+	s.job.Counts.Pending = len(spec.Requests)
+	return s.job, nil
+}
+
+func (s *stubBatchService) Get(ctx context.Context, jobID string) (*domain.BatchJob, error) {
+	if jobID != s.job.ID {
+		return nil, domain.ErrNotFound
+	}
+	return s.job, nil
+}
+
+func (s *stubBatchService) List(ctx context.Context, limit int) ([]*domain.BatchJob, error) {
+	return []*domain.BatchJob{s.job}, nil
+}
+
+func (s *stubBatchService) Results(ctx context.Context, jobID string, offset, limit int) ([]domain.BatchItem, error) {
+	return s.items, nil
+}
+
+func (s *stubBatchService) Cancel(ctx context.Context, jobID string) (*domain.BatchJob, error) {
+	s.job.Status = domain.BatchJobCancelled
+	return s.job, nil
+}
+
+func TestHandleAdminCommand_Batch(t *testing.T) {
+	handler, _ := setupAdminHandler()
+	ctx := context.Background()
+	admin := &domain.User{ID: "admin1", Role: domain.RoleAdmin}
+
+	// Batch service not configured
+	result, err := handler.HandleAdminCommand(ctx, admin, "/admin batch list")
+	if err != nil || !strings.Contains(result, "not available") {
+		t.Errorf("Expected batch unavailable message, got %q, %v", result, err)
+	}
+
+	svc := &stubBatchService{
+		job: &domain.BatchJob{ID: "job1", Name: "nightly", Provider: domain.LLMProviderAnthropic, Mode: domain.BatchModeProvider, Status: domain.BatchJobRunning},
+		items: []domain.BatchItem{
+			{CustomID: "conv-1", Status: domain.BatchItemSucceeded, Response: &domain.LLMResponse{Content: "Summary one"}},
+			{CustomID: "conv-2", Status: domain.BatchItemFailed, Error: "overloaded"},
+		},
+	}
+	handler.SetBatchService(svc)
+
+	input := filepath.Join(t.TempDir(), "requests.jsonl")
+	if err := os.WriteFile(input, []byte(`{"custom_id": "conv-1", "prompt": "one"}`+"\n"+`{"custom_id": "conv-2", "prompt": "two"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = handler.HandleAdminCommand(ctx, admin, "/admin batch submit anthropic "+input+" --model claude-haiku-4-5 --name nightly")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "Batch job submitted") || !strings.Contains(result, "Requests: 2") {
+		t.Errorf("Unexpected submit output: %s", result)
+	}
+	if svc.submitted.Provider != domain.LLMProviderAnthropic || svc.submitted.Name != "nightly" || svc.submitted.CreatedBy != "admin1" {
+		t.Errorf("Unexpected job spec: %+v", svc.submitted)
+	}
+	if len(svc.submitted.Requests) != 2 || svc.submitted.Requests[0].Request.Model != "claude-haiku-4-5" {
+		t.Errorf("Expected 2 requests using the --model default, got %+v", svc.submitted.Requests)
+	}
+
+	result, _ = handler.HandleAdminCommand(ctx, admin, "/admin batch status job1")
+	if !strings.Contains(result, "Status: running") || !strings.Contains(result, "0/2 done") {
+		t.Errorf("Unexpected status output: %s", result)
+	}
+
+	result, _ = handler.HandleAdminCommand(ctx, admin, "/admin batch results job1")
+	if !strings.Contains(result, "[conv-1] succeeded\nSummary one") || !strings.Contains(result, "Error: overloaded") {
+		t.Errorf("Unexpected results output: %s", result)
+	}
+
+	result, _ = handler.HandleAdminCommand(ctx, admin, "/admin batch cancel job1")
+	if !strings.Contains(result, "is cancelled") {
+		t.Errorf("Unexpected cancel output: %s", result)
+	}
+
+	result, _ = handler.HandleAdminCommand(ctx, admin, "/admin batch submit anthropic")
+	if !strings.Contains(result, "Usage:") {
+		t.Errorf("Expected usage for missing file, got %s", result)
+	}
+}
+
+func TestGateway_AdminBatchForResolvedOperator(t *testing.T) {
+	resolver := user.NewResolver(nil, domain.RoleUser, map[domain.Platform]domain.Role{domain.PlatformCLI: domain.RoleAdmin})
+	svc := &stubBatchService{job: &domain.BatchJob{ID: "job1", Name: "nightly", Status: domain.BatchJobRunning}}
+
+	output := new(bytes.Buffer)
+	g, readerPipe, writerPipe := newTestGateway(&config.CLIConfig{}, "/admin batch cancel job1\nexit\n", output)
+	defer readerPipe.Close()
+	defer writerPipe.Close()
+	handler := cli.NewAdminCommandHandler(nil)
+	handler.SetBatchService(svc)
+	g.SetAdminHandler(handler)
+
+	if err := g.ResolveOperator(context.Background(), resolver); err != nil {
+		t.Fatalf("ResolveOperator() error: %v", err)
+	}
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	if strings.Contains(output.String(), "insufficient permissions") || svc.job.Status != domain.BatchJobCancelled {
+		t.Errorf("Expected the operator to cancel the job, got: %s", output.String())
+	}
+}
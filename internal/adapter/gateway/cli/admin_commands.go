@@ -2,8 +2,10 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"nuimanbot/internal/domain"
 	"nuimanbot/internal/usecase/user"
@@ -11,16 +13,24 @@ import (
 
 // AdminCommandHandler handles administrative commands.
 type AdminCommandHandler struct {
-	userService *user.Service
+	userService  *user.Service
+	traceStore   domain.LLMTraceStore // Optional: LLM request inspector
+	batchService BatchService         // Optional: batch jobs
 }
 
 // NewAdminCommandHandler creates a new admin command handler.
+// userService may be nil, in which case user management commands are unavailable.
 func NewAdminCommandHandler(userService *user.Service) *AdminCommandHandler {
 	return &AdminCommandHandler{
 		userService: userService,
 	}
 }
 
+// SetTraceStore enables the trace command using the LLM request inspector's store.
+func (h *AdminCommandHandler) SetTraceStore(store domain.LLMTraceStore) {
+	h.traceStore = store
+}
+
 // IsAdminCommand checks if the input is an admin command.
 func IsAdminCommand(input string) bool {
 	return strings.HasPrefix(input, "/admin ")
@@ -46,6 +56,10 @@ func (h *AdminCommandHandler) HandleAdminCommand(ctx context.Context, currentUse
 	switch subcommand {
 	case "user":
 		return h.handleUserCommand(ctx, parts[2:])
+	case "trace":
+		return h.showTrace(parts[2:])
+	case "batch":
+		return h.handleBatchCommand(ctx, currentUser, parts[2:])
 	case "help":
 		return h.showHelp(), nil
 	default:
@@ -55,6 +69,9 @@ func (h *AdminCommandHandler) HandleAdminCommand(ctx context.Context, currentUse
 
 // handleUserCommand handles user management subcommands.
 func (h *AdminCommandHandler) handleUserCommand(ctx context.Context, args []string) (string, error) {
+	if h.userService == nil {
+		return "User management is not available.", nil
+	}
 	if len(args) == 0 {
 		return "Usage: /admin user <create|list|get|update|delete> [args...]", nil
 	}
@@ -235,6 +252,49 @@ func (h *AdminCommandHandler) deleteUser(ctx context.Context, args []string) (st
 	return fmt.Sprintf("✓ User %s deleted successfully", userID), nil
 }
 
+// showTrace prints the recorded LLM calls for a request.
+// Usage: /admin trace <request_id>
+func (h *AdminCommandHandler) showTrace(args []string) (string, error) {
+	if h.traceStore == nil {
+		return "LLM request inspector is not enabled (set llm.inspector.enabled).", nil
+	}
+	if len(args) < 1 {
+		return "Usage: /admin trace <request_id>", nil
+	}
+
+	requestID := args[0]
+	traces := h.traceStore.Traces(requestID)
+	if len(traces) == 0 {
+		return fmt.Sprintf("No trace recorded for request %s (it may not have been sampled or has expired).", requestID), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Request %s: %d LLM call(s)\n", requestID, len(traces)))
+
+	for i, trace := range traces {
+		result.WriteString(fmt.Sprintf("\n%d. %s/%s at %s (%s)\n", i+1, trace.Provider, trace.Model,
+			trace.StartedAt.Format("2006-01-02 15:04:05"), trace.Duration.Round(time.Millisecond)))
+		if trace.Response.FinishReason != "" {
+			result.WriteString(fmt.Sprintf("   Finish reason: %s\n", trace.Response.FinishReason))
+		}
+		if trace.Error != "" {
+			result.WriteString(fmt.Sprintf("   Error: %s\n", trace.Error))
+		}
+
+		data, err := json.MarshalIndent(struct {
+			Request  domain.LLMTraceRequest  `json:"request"`
+			Response domain.LLMTraceResponse `json:"response"`
+		}{trace.Request, trace.Response}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to format trace: %w", err)
+		}
+		result.Write(data)
+		result.WriteString("\n")
+	}
+
+	return result.String(), nil
+}
+
 // showHelp returns help text for admin commands.
 func (h *AdminCommandHandler) showHelp() string {
 	return `Admin Commands:
@@ -261,6 +321,27 @@ User Management:
   /admin user delete <user_id>
     Delete a user
 
+Batch Jobs:
+  /admin batch submit <provider> <file.jsonl> [--model <model>] [--name <name>] [--local]
+    Launch a batch job from a JSONL file (one request per line)
+    Example: /admin batch submit anthropic summaries.jsonl --model claude-haiku-4-5
+
+  /admin batch list
+    List recent batch jobs
+
+  /admin batch status <job_id>
+    Show a batch job's progress
+
+  /admin batch results <job_id> [page]
+    Show results (available while the job is still running)
+
+  /admin batch cancel <job_id>
+    Cancel a batch job
+
+Debugging:
+  /admin trace <request_id>
+    Show the LLM requests and responses recorded for a request
+
 General:
   /admin help
     Show this help message
@@ -1,12 +1,14 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"strings"
 	"testing"
 
 	"nuimanbot/internal/adapter/gateway/cli"
+	"nuimanbot/internal/config"
 	"nuimanbot/internal/domain"
 	"nuimanbot/internal/usecase/user"
 )
@@ -360,3 +362,107 @@ func TestHandleAdminCommand_MissingArguments(t *testing.T) {
 		}
 	}
 }
+
+// stubTraceStore returns fixed traces for one request ID.
+type stubTraceStore struct {
+	requestID string
+	traces    []domain.LLMTrace
+}
+
+func (s *stubTraceStore) Traces(requestID string) []domain.LLMTrace {
+	if requestID != s.requestID {
+		return nil
+	}
+	return s.traces
+}
+
+func TestHandleAdminCommand_Trace(t *testing.T) {
+	handler, _ := setupAdminHandler()
+	ctx := context.Background()
+	admin := &domain.User{ID: "admin1", Role: domain.RoleAdmin}
+
+	// Inspector disabled
+	result, err := handler.HandleAdminCommand(ctx, admin, "/admin trace req1")
+	if err != nil || !strings.Contains(result, "not enabled") {
+		t.Errorf("Expected inspector disabled message, got %q, %v", result, err)
+	}
+
+	handler.SetTraceStore(&stubTraceStore{
+		requestID: "req1",
+		traces: []domain.LLMTrace{{
+			RequestID: "req1",
+			Provider:  domain.LLMProviderAnthropic,
+			Model:     "claude-sonnet-4-5",
+			Request: domain.LLMTraceRequest{
+				SystemPrompt: "You are helpful",
+				Messages:     []domain.Message{{Role: "user", Content: "Hi"}},
+			},
+			Response: domain.LLMTraceResponse{Content: "Hello!", FinishReason: domain.FinishReasonStop},
+		}},
+	})
+
+	result, err = handler.HandleAdminCommand(ctx, admin, "/admin trace req1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"1 LLM call(s)", "anthropic/claude-sonnet-4-5", "Finish reason: stop", `"system_prompt": "You are helpful"`, `"content": "Hello!"`} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected trace output to contain %q, got:\n%s", want, result)
+		}
+	}
+
+	result, _ = handler.HandleAdminCommand(ctx, admin, "/admin trace unknown")
+	if !strings.Contains(result, "No trace recorded") {
+		t.Errorf("Expected no trace message, got %q", result)
+	}
+}
+
+func TestGateway_ResolveOperatorAuthorizesAdminCommands(t *testing.T) {
+	repo := NewMockUserRepository()
+	resolver := user.NewResolver(repo, domain.RoleUser, nil)
+
+	runAdminHelp := func() string {
+		output := new(bytes.Buffer)
+		g, readerPipe, writerPipe := newTestGateway(&config.CLIConfig{}, "/admin help\nexit\n", output)
+		defer readerPipe.Close()
+		defer writerPipe.Close()
+		g.SetAdminHandler(cli.NewAdminCommandHandler(nil))
+
+		if err := g.ResolveOperator(context.Background(), resolver); err != nil {
+			t.Fatalf("ResolveOperator() error: %v", err)
+		}
+		if err := g.Start(context.Background()); err != nil {
+			t.Fatalf("Start() error: %v", err)
+		}
+		return output.String()
+	}
+
+	// Without a stored user the operator gets the default role
+	if out := runAdminHelp(); !strings.Contains(out, "Error: insufficient permissions") {
+		t.Errorf("Expected admin commands to be refused, got: %s", out)
+	}
+
+	// A stored admin for the CLI operator identity is authorized
+	_ = repo.SaveUser(context.Background(), &domain.User{
+		ID:          "admin1",
+		Role:        domain.RoleAdmin,
+		PlatformIDs: map[domain.Platform]string{domain.PlatformCLI: cli.OperatorUID},
+	})
+	if out := runAdminHelp(); !strings.Contains(out, "Admin Commands") {
+		t.Errorf("Expected admin help for the stored admin, got: %s", out)
+	}
+}
+
+func TestGateway_ResolveOperatorError(t *testing.T) {
+	g := cli.NewGateway(&config.CLIConfig{})
+	err := g.ResolveOperator(context.Background(), &failingResolver{})
+	if err == nil || !strings.Contains(err.Error(), "db down") {
+		t.Errorf("Expected the resolver error, got: %v", err)
+	}
+}
+
+type failingResolver struct{}
+
+func (f *failingResolver) ResolveUser(ctx context.Context, platform domain.Platform, platformUID string) (*domain.User, error) {
+	return nil, errors.New("db down")
+}
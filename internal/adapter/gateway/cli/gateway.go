@@ -13,6 +13,14 @@ import (
 	"nuimanbot/internal/domain"
 )
 
+// OperatorUID is the platform user ID of the person at the CLI.
+const OperatorUID = "cli_user"
+
+// UserResolver maps a platform identity to a user.
+type UserResolver interface {
+	ResolveUser(ctx context.Context, platform domain.Platform, platformUID string) (*domain.User, error)
+}
+
 // SkillCommandHandler defines the interface for handling skill commands.
 type SkillCommandHandler interface {
 	Execute(ctx context.Context, skillName string, args []string) error
@@ -171,7 +179,7 @@ func (g *Gateway) Start(ctx context.Context) error {
 			incomingMsg := domain.IncomingMessage{
 				ID:          "cli-" + fmt.Sprintf("%d", time.Now().UnixNano()), // Unique ID
 				Platform:    domain.PlatformCLI,
-				PlatformUID: "cli_user", // Placeholder for CLI user ID
+				PlatformUID: OperatorUID,
 				Text:        input,
 				Timestamp:   time.Now(),
 				Metadata:    nil,
@@ -201,6 +209,11 @@ func (g *Gateway) Stop(ctx context.Context) error {
 
 // Send sends a message to a user (CLI output).
 func (g *Gateway) Send(ctx context.Context, msg domain.OutgoingMessage) error {
+	if msg.Reasoning != "" {
+		if _, err := fmt.Fprintf(g.Writer, "Thinking: %s\n", msg.Reasoning); err != nil {
+			return fmt.Errorf("failed to write to CLI output: %w", err)
+		}
+	}
 	_, err := fmt.Fprintf(g.Writer, "Bot: %s\n", msg.Content)
 	if err != nil {
 		return fmt.Errorf("failed to write to CLI output: %w", err)
@@ -223,6 +236,23 @@ func (g *Gateway) SetCurrentUser(user *domain.User) {
 	g.currentUser = user
 }
 
+// CurrentUser returns the current CLI user, or nil if none is set.
+func (g *Gateway) CurrentUser() *domain.User {
+	return g.currentUser
+}
+
+// ResolveOperator resolves the CLI operator with resolver and makes them the
+// current user for admin commands, so "/admin" is authorized by the
+// operator's stored user or the CLI platform role.
+func (g *Gateway) ResolveOperator(ctx context.Context, resolver UserResolver) error {
+	user, err := resolver.ResolveUser(ctx, domain.PlatformCLI, OperatorUID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve CLI operator: %w", err)
+	}
+	g.SetCurrentUser(user)
+	return nil
+}
+
 // SetSkillHandler sets the skill command handler for the gateway.
 func (g *Gateway) SetSkillHandler(handler SkillCommandHandler) {
 	g.skillHandler = handler
@@ -104,7 +104,7 @@ func (g *Gateway) Send(ctx context.Context, msg domain.OutgoingMessage) error {
 
 	// Check for thread_ts to reply in thread
 	opts := []slack.MsgOption{
-		slack.MsgOptionText(msg.Content, false),
+		slack.MsgOptionText(msg.ContentWithReasoning(), false),
 	}
 
 	if msg.Metadata != nil {
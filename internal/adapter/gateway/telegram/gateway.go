@@ -168,7 +168,7 @@ func (g *Gateway) Send(ctx context.Context, msg domain.OutgoingMessage) error {
 	// Send message with Markdown formatting
 	_, err := g.bot.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:    chatID,
-		Text:      msg.Content,
+		Text:      msg.ContentWithReasoning(),
 		ParseMode: models.ParseModeMarkdown,
 	})
 
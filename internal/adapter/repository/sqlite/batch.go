@@ -0,0 +1,349 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"nuimanbot/internal/domain"
+)
+
+// BatchRepository implements batch.Repository using SQLite.
+type BatchRepository struct {
+	db *sql.DB
+}
+
+// NewBatchRepository creates a new SQLite batch job repository.
+func NewBatchRepository(db *sql.DB) *BatchRepository {
+	return &BatchRepository{db: db}
+}
+
+// Init creates the batch_jobs and batch_items tables if they don't exist.
+func (r *BatchRepository) Init(ctx context.Context) error {
+	const createJobsTableSQL = `
+	CREATE TABLE IF NOT EXISTS batch_jobs (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		provider TEXT NOT NULL,
+		mode TEXT NOT NULL,
+		provider_batch_id TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		created_by TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		completed_at DATETIME
+	);`
+	if _, err := r.db.ExecContext(ctx, createJobsTableSQL); err != nil {
+		return fmt.Errorf("failed to create batch_jobs table: %w", err)
+	}
+
+	const createItemsTableSQL = `
+	CREATE TABLE IF NOT EXISTS batch_items (
+		job_id TEXT NOT NULL,
+		seq INTEGER NOT NULL,
+		custom_id TEXT NOT NULL,
+		request TEXT NOT NULL, -- Stored as JSON
+		status TEXT NOT NULL,
+		response TEXT, -- Stored as JSON
+		error TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (job_id, custom_id),
+		FOREIGN KEY (job_id) REFERENCES batch_jobs(id) ON DELETE CASCADE
+	);`
+	if _, err := r.db.ExecContext(ctx, createItemsTableSQL); err != nil {
+		return fmt.Errorf("failed to create batch_items table: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_batch_items_job_status ON batch_items(job_id, status)
+	`); err != nil {
+		return fmt.Errorf("failed to create batch_items index: %w", err)
+	}
+	return nil
+}
+
+// CreateJob stores a new job together with its items in one transaction.
+func (r *BatchRepository) CreateJob(ctx context.Context, job *domain.BatchJob, items []domain.BatchItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rbe := tx.Rollback(); rbe != nil && !errors.Is(rbe, sql.ErrTxDone) {
+			slog.Error("Rollback error in CreateJob", "error", rbe)
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO batch_jobs (id, name, provider, mode, provider_batch_id, status, error, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, job.ID, job.Name, job.Provider, job.Mode, job.ProviderBatchID, job.Status, job.Error, job.CreatedBy, job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create batch job: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO batch_items (job_id, seq, custom_id, request, status, error)
+		VALUES (?, ?, ?, ?, ?, '')
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare batch item insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, item := range items {
+		request, err := json.Marshal(item.Request)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request %s: %w", item.CustomID, err)
+		}
+		if _, err := stmt.ExecContext(ctx, job.ID, i, item.CustomID, string(request), item.Status); err != nil {
+			return fmt.Errorf("failed to create batch item %s: %w", item.CustomID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch job: %w", err)
+	}
+	return nil
+}
+
+// GetJob retrieves a job by ID, with its item counts.
+func (r *BatchRepository) GetJob(ctx context.Context, jobID string) (*domain.BatchJob, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, name, provider, mode, provider_batch_id, status, error, created_by, created_at, updated_at, completed_at
+		FROM batch_jobs
+		WHERE id = ?
+	`, jobID)
+
+	job, err := scanBatchJob(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch job: %w", err)
+	}
+
+	if err := r.fillCounts(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// ListJobs retrieves the most recent jobs, newest first.
+func (r *BatchRepository) ListJobs(ctx context.Context, limit int) ([]*domain.BatchJob, error) {
+	return r.queryJobs(ctx, `
+		SELECT id, name, provider, mode, provider_batch_id, status, error, created_by, created_at, updated_at, completed_at
+		FROM batch_jobs
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, limit)
+}
+
+// ListActiveJobs retrieves jobs that have not reached a terminal state, oldest first.
+func (r *BatchRepository) ListActiveJobs(ctx context.Context) ([]*domain.BatchJob, error) {
+	return r.queryJobs(ctx, `
+		SELECT id, name, provider, mode, provider_batch_id, status, error, created_by, created_at, updated_at, completed_at
+		FROM batch_jobs
+		WHERE status IN (?, ?, ?)
+		ORDER BY created_at ASC
+	`, domain.BatchJobPending, domain.BatchJobRunning, domain.BatchJobCancelling)
+}
+
+// UpdateJob updates a job's status, provider batch ID and error. The
+// completion time is set when the job reaches a terminal state.
+func (r *BatchRepository) UpdateJob(ctx context.Context, job *domain.BatchJob) error {
+	job.UpdatedAt = time.Now()
+	var completedAt sql.NullTime
+	if job.Status.IsTerminal() {
+		if job.CompletedAt.IsZero() {
+			job.CompletedAt = job.UpdatedAt
+		}
+		completedAt = sql.NullTime{Time: job.CompletedAt, Valid: true}
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE batch_jobs
+		SET provider_batch_id = ?, status = ?, error = ?, updated_at = ?, completed_at = ?
+		WHERE id = ?
+	`, job.ProviderBatchID, job.Status, job.Error, job.UpdatedAt, completedAt, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update batch job: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListItems retrieves a job's items in submission order. A status of ""
+// matches all items; limit <= 0 means no limit.
+func (r *BatchRepository) ListItems(ctx context.Context, jobID string, status domain.BatchItemStatus, offset, limit int) ([]domain.BatchItem, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT job_id, custom_id, request, status, response, error
+		FROM batch_items
+		WHERE job_id = ? AND (? = '' OR status = ?)
+		ORDER BY seq ASC
+		LIMIT ? OFFSET ?
+	`, jobID, status, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list batch items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []domain.BatchItem
+	for rows.Next() {
+		var item domain.BatchItem
+		var request string
+		var response sql.NullString
+		if err := rows.Scan(&item.JobID, &item.CustomID, &request, &item.Status, &response, &item.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan batch item: %w", err)
+		}
+		if err := json.Unmarshal([]byte(request), &item.Request); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal request %s: %w", item.CustomID, err)
+		}
+		if response.Valid && response.String != "" {
+			item.Response = &domain.LLMResponse{}
+			if err := json.Unmarshal([]byte(response.String), item.Response); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal response %s: %w", item.CustomID, err)
+			}
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating batch items: %w", err)
+	}
+	return items, nil
+}
+
+// SaveItemResult stores an item's status, response and error.
+func (r *BatchRepository) SaveItemResult(ctx context.Context, item *domain.BatchItem) error {
+	var response sql.NullString
+	if item.Response != nil {
+		data, err := json.Marshal(item.Response)
+		if err != nil {
+			return fmt.Errorf("failed to marshal response %s: %w", item.CustomID, err)
+		}
+		response = sql.NullString{String: string(data), Valid: true}
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE batch_items
+		SET status = ?, response = ?, error = ?
+		WHERE job_id = ? AND custom_id = ?
+	`, item.Status, response, item.Error, item.JobID, item.CustomID)
+	if err != nil {
+		return fmt.Errorf("failed to save batch item result: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// CancelPendingItems marks a job's pending items as cancelled.
+func (r *BatchRepository) CancelPendingItems(ctx context.Context, jobID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE batch_items SET status = ? WHERE job_id = ? AND status = ?
+	`, domain.BatchItemCancelled, jobID, domain.BatchItemPending)
+	if err != nil {
+		return fmt.Errorf("failed to cancel pending batch items: %w", err)
+	}
+	return nil
+}
+
+// queryJobs runs a job query and fills in item counts.
+func (r *BatchRepository) queryJobs(ctx context.Context, query string, args ...any) ([]*domain.BatchJob, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list batch jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*domain.BatchJob
+	for rows.Next() {
+		job, err := scanBatchJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan batch job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating batch jobs: %w", err)
+	}
+	rows.Close()
+
+	for _, job := range jobs {
+		if err := r.fillCounts(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
+
+// fillCounts sets a job's item counts by status.
+func (r *BatchRepository) fillCounts(ctx context.Context, job *domain.BatchJob) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT status, COUNT(*) FROM batch_items WHERE job_id = ? GROUP BY status
+	`, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count batch items: %w", err)
+	}
+	defer rows.Close()
+
+	job.Counts = domain.BatchItemCounts{}
+	for rows.Next() {
+		var status domain.BatchItemStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return fmt.Errorf("failed to scan batch item count: %w", err)
+		}
+		switch status {
+		case domain.BatchItemPending:
+			job.Counts.Pending = count
+		case domain.BatchItemSucceeded:
+			job.Counts.Succeeded = count
+		case domain.BatchItemFailed:
+			job.Counts.Failed = count
+		case domain.BatchItemCancelled:
+			job.Counts.Cancelled = count
+		}
+	}
+	return rows.Err()
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBatchJob(row rowScanner) (*domain.BatchJob, error) {
+	var job domain.BatchJob
+	var completedAt sql.NullTime
+	err := row.Scan(&job.ID, &job.Name, &job.Provider, &job.Mode, &job.ProviderBatchID, &job.Status,
+		&job.Error, &job.CreatedBy, &job.CreatedAt, &job.UpdatedAt, &completedAt)
+	if err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		job.CompletedAt = completedAt.Time
+	}
+	return &job, nil
+}
@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"nuimanbot/internal/domain"
+)
+
+func setupBatchRepo(t *testing.T) *BatchRepository {
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	repo := NewBatchRepository(db)
+	if err := repo.Init(context.Background()); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return repo
+}
+
+func TestBatchRepository_JobLifecycle(t *testing.T) {
+	repo := setupBatchRepo(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	job := &domain.BatchJob{
+		ID:        "job1",
+		Name:      "summaries",
+		Provider:  domain.LLMProviderAnthropic,
+		Mode:      domain.BatchModeProvider,
+		Status:    domain.BatchJobPending,
+		CreatedBy: "admin1",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	items := []domain.BatchItem{
+		{JobID: "job1", CustomID: "b", Status: domain.BatchItemPending, Request: domain.LLMRequest{Model: "m", Messages: []domain.Message{{Role: "user", Content: "first"}}}},
+		{JobID: "job1", CustomID: "a", Status: domain.BatchItemPending, Request: domain.LLMRequest{Model: "m", Messages: []domain.Message{{Role: "user", Content: "second"}}}},
+	}
+	if err := repo.CreateJob(ctx, job, items); err != nil {
+		t.Fatalf("CreateJob failed: %v", err)
+	}
+
+	job.Status = domain.BatchJobRunning
+	job.ProviderBatchID = "msgbatch_1"
+	if err := repo.UpdateJob(ctx, job); err != nil {
+		t.Fatalf("UpdateJob failed: %v", err)
+	}
+
+	item := items[0]
+	item.Status = domain.BatchItemSucceeded
+	item.Response = &domain.LLMResponse{Content: "done", FinishReason: domain.FinishReasonStop}
+	if err := repo.SaveItemResult(ctx, &item); err != nil {
+		t.Fatalf("SaveItemResult failed: %v", err)
+	}
+
+	got, err := repo.GetJob(ctx, "job1")
+	if err != nil {
+		t.Fatalf("GetJob failed: %v", err)
+	}
+	if got.Status != domain.BatchJobRunning || got.ProviderBatchID != "msgbatch_1" || !got.CompletedAt.IsZero() {
+		t.Errorf("Unexpected job: %+v", got)
+	}
+	if got.Counts.Succeeded != 1 || got.Counts.Pending != 1 {
+		t.Errorf("Counts = %+v, want 1 succeeded and 1 pending", got.Counts)
+	}
+
+	// Items come back in submission order with requests and responses intact
+	all, err := repo.ListItems(ctx, "job1", "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListItems failed: %v", err)
+	}
+	if len(all) != 2 || all[0].CustomID != "b" || all[1].CustomID != "a" {
+		t.Fatalf("Unexpected items: %+v", all)
+	}
+	if all[0].Response == nil || all[0].Response.Content != "done" || all[1].Request.Messages[0].Content != "second" {
+		t.Errorf("Item data not round-tripped: %+v", all)
+	}
+
+	pending, err := repo.ListItems(ctx, "job1", domain.BatchItemPending, 0, 0)
+	if err != nil || len(pending) != 1 || pending[0].CustomID != "a" {
+		t.Errorf("Pending items = %+v (err %v), want [a]", pending, err)
+	}
+
+	active, err := repo.ListActiveJobs(ctx)
+	if err != nil || len(active) != 1 {
+		t.Errorf("Active jobs = %d (err %v), want 1", len(active), err)
+	}
+
+	if err := repo.CancelPendingItems(ctx, "job1"); err != nil {
+		t.Fatalf("CancelPendingItems failed: %v", err)
+	}
+	job.Status = domain.BatchJobCancelled
+	if err := repo.UpdateJob(ctx, job); err != nil {
+		t.Fatalf("UpdateJob failed: %v", err)
+	}
+
+	jobs, err := repo.ListJobs(ctx, 10)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("ListJobs = %d jobs (err %v), want 1", len(jobs), err)
+	}
+	if jobs[0].Counts.Cancelled != 1 || jobs[0].CompletedAt.IsZero() {
+		t.Errorf("Expected cancelled job with completion time, got %+v", jobs[0])
+	}
+	if active, _ := repo.ListActiveJobs(ctx); len(active) != 0 {
+		t.Errorf("Expected no active jobs, got %d", len(active))
+	}
+}
+
+func TestBatchRepository_NotFound(t *testing.T) {
+	repo := setupBatchRepo(t)
+	ctx := context.Background()
+
+	if _, err := repo.GetJob(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetJob error = %v, want ErrNotFound", err)
+	}
+	if err := repo.UpdateJob(ctx, &domain.BatchJob{ID: "missing"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateJob error = %v, want ErrNotFound", err)
+	}
+	if err := repo.SaveItemResult(ctx, &domain.BatchItem{JobID: "missing", CustomID: "x"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SaveItemResult error = %v, want ErrNotFound", err)
+	}
+}
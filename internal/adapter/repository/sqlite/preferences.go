@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"nuimanbot/internal/domain"
+)
+
+// PreferencesRepository implements domain.PreferencesRepository using SQLite.
+type PreferencesRepository struct {
+	db *sql.DB
+}
+
+// NewPreferencesRepository creates a new SQLite preferences repository.
+func NewPreferencesRepository(db *sql.DB) *PreferencesRepository {
+	return &PreferencesRepository{db: db}
+}
+
+// Init creates the user_preferences table if it doesn't exist.
+func (r *PreferencesRepository) Init(ctx context.Context) error {
+	const createTableSQL = `
+	CREATE TABLE IF NOT EXISTS user_preferences (
+		user_id TEXT PRIMARY KEY,
+		preferences TEXT NOT NULL, -- Stored as JSON
+		updated_at DATETIME NOT NULL
+	);`
+	if _, err := r.db.ExecContext(ctx, createTableSQL); err != nil {
+		return fmt.Errorf("failed to create user_preferences table: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves user preferences by user ID.
+func (r *PreferencesRepository) Get(ctx context.Context, userID string) (domain.UserPreferences, error) {
+	var data string
+	err := r.db.QueryRowContext(ctx,
+		"SELECT preferences FROM user_preferences WHERE user_id = ?", userID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.UserPreferences{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.UserPreferences{}, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	var prefs domain.UserPreferences
+	if err := json.Unmarshal([]byte(data), &prefs); err != nil {
+		return domain.UserPreferences{}, fmt.Errorf("failed to unmarshal preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// Save stores user preferences, replacing any existing ones.
+func (r *PreferencesRepository) Save(ctx context.Context, userID string, prefs domain.UserPreferences) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preferences: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO user_preferences (user_id, preferences, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			preferences = excluded.preferences,
+			updated_at = excluded.updated_at
+	`, userID, string(data), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return nil
+}
+
+// Delete removes user preferences.
+func (r *PreferencesRepository) Delete(ctx context.Context, userID string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM user_preferences WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("failed to delete preferences: %w", err)
+	}
+	return nil
+}
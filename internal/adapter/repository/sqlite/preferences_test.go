@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"nuimanbot/internal/domain"
+)
+
+func TestPreferencesRepository_SaveGetDelete(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+
+	repo := NewPreferencesRepository(db)
+	if err := repo.Init(ctx); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	if _, err := repo.Get(ctx, "user-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for unknown user, got %v", err)
+	}
+
+	prefs := domain.DefaultUserPreferences()
+	prefs.Timezone = "Europe/Berlin"
+	if err := repo.Save(ctx, "user-1", prefs); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	prefs.Timezone = "Asia/Tokyo"
+	if err := repo.Save(ctx, "user-1", prefs); err != nil {
+		t.Fatalf("Save (update) failed: %v", err)
+	}
+
+	got, err := repo.Get(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Timezone != "Asia/Tokyo" || got.GetMaxTokens() != prefs.GetMaxTokens() {
+		t.Errorf("Unexpected preferences: %+v", got)
+	}
+
+	if err := repo.Delete(ctx, "user-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.Get(ctx, "user-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
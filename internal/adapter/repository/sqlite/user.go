@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"nuimanbot/internal/domain"
@@ -23,9 +24,69 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-// Init initializes the user table if it doesn't exist.
+// Init initializes the user table if it doesn't exist, first migrating a
+// users table left by older versions (one row per platform identity).
 func (r *UserRepository) Init(ctx context.Context) error {
-	const createTableSQL = `
+	legacy, err := r.hasColumn(ctx, "platform_uid")
+	if err != nil {
+		return err
+	}
+	if legacy {
+		return r.migrateLegacy(ctx)
+	}
+	_, err = r.db.ExecContext(ctx, createUsersTableSQL)
+	return err
+}
+
+// hasColumn reports whether the users table has the named column.
+func (r *UserRepository) hasColumn(ctx context.Context, column string) (bool, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT name FROM pragma_table_info('users');`)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect users table: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, fmt.Errorf("failed to inspect users table: %w", err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// migrateLegacy rebuilds a legacy users table in the current schema. Each
+// legacy row becomes a user named "<platform>:<uid>" with that one platform
+// identity. The table is rebuilt under a new name and renamed back so that
+// foreign keys in other tables keep referencing users.
+func (r *UserRepository) migrateLegacy(ctx context.Context) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin users migration: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // No-op after Commit
+
+	statements := []string{
+		strings.Replace(createUsersTableSQL, "users", "users_new", 1),
+		`INSERT INTO users_new (id, username, role, platform_ids, allowed_skills, created_at, updated_at)
+		SELECT id, platform || ':' || platform_uid, role, json_object(platform, platform_uid), '[]',
+			COALESCE(created_at, CURRENT_TIMESTAMP), COALESCE(updated_at, CURRENT_TIMESTAMP)
+		FROM users;`,
+		`DROP TABLE users;`,
+		`ALTER TABLE users_new RENAME TO users;`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to migrate users table: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+const createUsersTableSQL = `
 	CREATE TABLE IF NOT EXISTS users (
 		id TEXT PRIMARY KEY,
 		username TEXT NOT NULL UNIQUE,
@@ -35,9 +96,6 @@ func (r *UserRepository) Init(ctx context.Context) error {
 		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL
 	);`
-	_, err := r.db.ExecContext(ctx, createTableSQL)
-	return err
-}
 
 // SaveUser creates or updates a user in the database.
 func (r *UserRepository) SaveUser(ctx context.Context, user *domain.User) error {
@@ -120,15 +178,16 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*domain.Us
 
 // GetUserByPlatformID retrieves a user by their platform ID.
 func (r *UserRepository) GetUserByPlatformID(ctx context.Context, platform domain.Platform, platformUID string) (*domain.User, error) {
-	// This query uses LIKE to search within the JSON 'platform_ids' column.
-	// This is not the most efficient way to query JSON, but sufficient for MVP.
-	// For production, consider a JSON-enabled database or a more robust solution.
-	searchString := fmt.Sprintf(`"\"%q\":\"%q\""`, platform, platformUID)
+	// platform_ids is a JSON object keyed by platform; json_each matches the
+	// exact pair without a full-text pattern.
 	const selectSQL = `
 	SELECT id, username, role, platform_ids, allowed_skills, created_at, updated_at
 	FROM users
-	WHERE platform_ids LIKE ?;`
-	row := r.db.QueryRowContext(ctx, selectSQL, "%"+searchString+"%")
+	WHERE EXISTS (
+		SELECT 1 FROM json_each(users.platform_ids)
+		WHERE json_each.key = ? AND json_each.value = ?
+	);`
+	row := r.db.QueryRowContext(ctx, selectSQL, string(platform), platformUID)
 
 	user := &domain.User{}
 	var roleStr string
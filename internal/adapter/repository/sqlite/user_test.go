@@ -0,0 +1,129 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"nuimanbot/internal/adapter/repository/sqlite"
+	"nuimanbot/internal/domain"
+)
+
+func setupUserTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestUserRepository_GetUserByPlatformID(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlite.NewUserRepository(setupUserTestDB(t))
+	if err := repo.Init(ctx); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+
+	alice := &domain.User{
+		ID:       "u1",
+		Username: "alice",
+		Role:     domain.RoleAdmin,
+		PlatformIDs: map[domain.Platform]string{
+			domain.PlatformCLI:      "alice",
+			domain.PlatformTelegram: "12345",
+		},
+		AllowedTools: []string{},
+		CreatedAt:    time.Now(),
+	}
+	bob := &domain.User{
+		ID:           "u2",
+		Username:     "bob",
+		Role:         domain.RoleUser,
+		PlatformIDs:  map[domain.Platform]string{domain.PlatformCLI: "bob_1"},
+		AllowedTools: []string{},
+		CreatedAt:    time.Now(),
+	}
+	for _, u := range []*domain.User{alice, bob} {
+		if err := repo.SaveUser(ctx, u); err != nil {
+			t.Fatalf("SaveUser(%s) error: %v", u.ID, err)
+		}
+	}
+
+	tests := []struct {
+		platform domain.Platform
+		uid      string
+		wantID   string
+	}{
+		{domain.PlatformCLI, "alice", "u1"},
+		{domain.PlatformTelegram, "12345", "u1"},
+		{domain.PlatformCLI, "bob_1", "u2"},
+		{domain.PlatformCLI, "bobx1", ""}, // No LIKE-style wildcard matching
+		{domain.PlatformTelegram, "alice", ""},
+		{domain.PlatformCLI, "ali", ""},
+	}
+	for _, tt := range tests {
+		got, err := repo.GetUserByPlatformID(ctx, tt.platform, tt.uid)
+		if tt.wantID == "" {
+			if !errors.Is(err, domain.ErrUserNotFound) {
+				t.Errorf("GetUserByPlatformID(%s, %q) = %v, %v; want ErrUserNotFound", tt.platform, tt.uid, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("GetUserByPlatformID(%s, %q) error: %v", tt.platform, tt.uid, err)
+			continue
+		}
+		if got.ID != tt.wantID {
+			t.Errorf("GetUserByPlatformID(%s, %q) = %s, want %s", tt.platform, tt.uid, got.ID, tt.wantID)
+		}
+	}
+
+	got, _ := repo.GetUserByPlatformID(ctx, domain.PlatformCLI, "alice")
+	if got.Role != domain.RoleAdmin || got.PlatformIDs[domain.PlatformTelegram] != "12345" {
+		t.Errorf("Unexpected user: %+v", got)
+	}
+}
+
+func TestUserRepository_InitMigratesLegacyTable(t *testing.T) {
+	ctx := context.Background()
+	db := setupUserTestDB(t)
+	_, err := db.Exec(`
+		CREATE TABLE users (
+			id TEXT PRIMARY KEY,
+			platform TEXT NOT NULL,
+			platform_uid TEXT NOT NULL,
+			role TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(platform, platform_uid)
+		);
+		CREATE UNIQUE INDEX idx_users_platform_uid ON users(platform, platform_uid);
+		INSERT INTO users (id, platform, platform_uid, role) VALUES ('legacy1', 'cli', 'carol', 'admin');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create legacy users table: %v", err)
+	}
+
+	repo := sqlite.NewUserRepository(db)
+	if err := repo.Init(ctx); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+	// A second Init sees the current schema and leaves it alone
+	if err := repo.Init(ctx); err != nil {
+		t.Fatalf("second Init() error: %v", err)
+	}
+
+	got, err := repo.GetUserByPlatformID(ctx, domain.PlatformCLI, "carol")
+	if err != nil {
+		t.Fatalf("GetUserByPlatformID() error: %v", err)
+	}
+	if got.ID != "legacy1" || got.Username != "cli:carol" || got.Role != domain.RoleAdmin {
+		t.Errorf("Unexpected migrated user: %+v", got)
+	}
+}
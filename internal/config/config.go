@@ -15,6 +15,12 @@ type SecurityConfig struct {
 	TokenRotationHours int    `yaml:"token_rotation_hours"`
 	VaultPath          string `yaml:"vault_path"`
 	EncryptionKey      string `yaml:"encryption_key"`
+
+	// DefaultRole is the role of platform identities with no user record.
+	// Empty means guest.
+	DefaultRole domain.Role `yaml:"default_role"`
+	// PlatformRoles overrides DefaultRole per platform (e.g. cli: user).
+	PlatformRoles map[domain.Platform]domain.Role `yaml:"platform_roles"`
 }
 
 // LLMProviderConfig configures a specific LLM provider instance.
@@ -41,9 +47,19 @@ type LLMDefaultModelConfig struct {
 	Fallbacks []string `yaml:"fallbacks"`
 }
 
+// APIKeyConfig describes one credential in a provider's key pool.
+type APIKeyConfig struct {
+	ID                string              `yaml:"id"`                  // Label for logs and metrics
+	APIKey            domain.SecureString `yaml:"api_key"`             // Inline key; prefer VaultKey
+	VaultKey          string              `yaml:"vault_key"`           // CredentialVault entry holding the key
+	RequestsPerMinute int                 `yaml:"requests_per_minute"` // 0 means unlimited
+	TokensPerMinute   int                 `yaml:"tokens_per_minute"`   // 0 means unlimited
+}
+
 // AnthropicProviderConfig holds Anthropic-specific provider configuration.
 type AnthropicProviderConfig struct {
 	APIKey domain.SecureString `yaml:"api_key"`
+	Keys   []APIKeyConfig      `yaml:"keys"` // Optional key pool; used instead of APIKey when set
 }
 
 // OpenAIProviderConfig holds OpenAI-specific provider configuration.
@@ -52,6 +68,7 @@ type OpenAIProviderConfig struct {
 	BaseURL      string              `yaml:"base_url"`
 	DefaultModel string              `yaml:"default_model"`
 	Organization string              `yaml:"organization"`
+	Keys         []APIKeyConfig      `yaml:"keys"` // Optional key pool; used instead of APIKey when set
 }
 
 // OllamaProviderConfig holds Ollama-specific provider configuration.
@@ -69,6 +86,29 @@ type BedrockProviderConfig struct {
 	RequestTimeout int    `yaml:"request_timeout"` // Default: 120 seconds
 }
 
+// ReplayProviderConfig configures the record/replay LLM provider used for
+// deterministic tests and offline demos.
+type ReplayProviderConfig struct {
+	Mode        string `yaml:"mode"`         // "record", "replay", or empty to disable
+	CassetteDir string `yaml:"cassette_dir"` // Directory holding recorded cassettes
+}
+
+// InspectorConfig configures the LLM request inspector, which keeps redacted
+// request/response traces that admins can look up by request ID.
+type InspectorConfig struct {
+	Enabled        bool    `yaml:"enabled"`
+	SampleRate     float64 `yaml:"sample_rate"`     // Fraction of requests to record; 0 records all
+	MaxRequests    int     `yaml:"max_requests"`    // Request IDs kept in memory (default: 500)
+	RetentionHours int     `yaml:"retention_hours"` // Hours traces are kept (default: 24)
+}
+
+// BatchConfig configures batch LLM jobs, which run bulk offline work through
+// provider batch endpoints or a local worker pool.
+type BatchConfig struct {
+	Workers             int `yaml:"workers"`               // Concurrent requests for local jobs; 0 uses the default
+	PollIntervalSeconds int `yaml:"poll_interval_seconds"` // How often provider batches are checked; 0 uses the default
+}
+
 // LLMConfig encapsulates all LLM-related configurations.
 
 type LLMConfig struct {
@@ -82,6 +122,19 @@ type LLMConfig struct {
 	OpenAI    OpenAIProviderConfig    `yaml:"openai"`
 	Ollama    OllamaProviderConfig    `yaml:"ollama"`
 	Bedrock   BedrockProviderConfig   `yaml:"bedrock"`
+
+	Replay ReplayProviderConfig `yaml:"replay"`
+
+	Inspector InspectorConfig `yaml:"inspector"`
+
+	Batch BatchConfig `yaml:"batch"`
+
+	// CatalogFile optionally overrides or extends the bundled model capability catalog.
+	CatalogFile string `yaml:"catalog_file"`
+
+	// MaxContinuations is how many follow-up requests are made to finish a
+	// response cut off by the output token limit. 0 uses the default; negative disables.
+	MaxContinuations int `yaml:"max_continuations"`
 }
 
 // MCPClientConfig holds MCP client-specific configuration.
@@ -106,7 +159,7 @@ type MCPConfig struct {
 
 // ToolConfig configures an individual tool.
 type ToolConfig struct {
-	Enabled bool                   `yaml:"enabled"`
+	Enabled *bool                  `yaml:"enabled"` // nil uses the tool's default
 	APIKey  domain.SecureString    `yaml:"api_key"`
 	Env     map[string]string      `yaml:"env"`
 	Params  map[string]interface{} `yaml:"params"`
@@ -119,6 +172,29 @@ type ToolsSystemConfig struct {
 		ExtraDirs []string `yaml:"extra_dirs"`
 		Watch     bool     `yaml:"watch"`
 	} `yaml:"load"`
+	OpenAPI []OpenAPIConfig `yaml:"openapi"`
+	// Results bounds tool output passed to the LLM; longer outputs are kept
+	// for read_result and replaced by their first HeadChars characters
+	Results struct {
+		MaxChars   int `yaml:"max_chars"`   // Defaults to 16000; -1 passes all output through
+		HeadChars  int `yaml:"head_chars"`  // Defaults to 4000
+		TTLMinutes int `yaml:"ttl_minutes"` // How long stored outputs can be read; defaults to 30
+	} `yaml:"results"`
+}
+
+// OpenAPIConfig exposes the operations of an OpenAPI 3 document as tools.
+type OpenAPIConfig struct {
+	Spec    string   `yaml:"spec"`     // Path to the document (YAML or JSON)
+	Prefix  string   `yaml:"prefix"`   // Tool name prefix, e.g. "billing_"
+	BaseURL string   `yaml:"base_url"` // Overrides the document's first server URL
+	Include []string `yaml:"include"`  // operationIds or "METHOD /path" patterns (* wildcard); empty includes all
+	Exclude []string `yaml:"exclude"`  // Applied after include
+	Auth    struct {
+		Scheme   string `yaml:"scheme"`    // securitySchemes entry; defaults to the first one an operation requires
+		VaultKey string `yaml:"vault_key"` // Vault key of the token, API key or base64 user:password
+	} `yaml:"auth"`
+	MaxResponseChars int `yaml:"max_response_chars"` // Longer responses are truncated; defaults to 8000
+	Timeout          int `yaml:"timeout"`            // Seconds per request; defaults to 30
 }
 
 // StorageConfig holds storage-related configuration.
@@ -220,12 +296,40 @@ type ToolsWebSearchConfig struct {
 
 // ToolsExecConfig holds execution tool configuration.
 type ToolsExecConfig struct {
-	Timeout             int  `yaml:"timeout"`
-	RestrictToWorkspace bool `yaml:"restrict_to_workspace"`
+	Timeout             int      `yaml:"timeout"`               // Maximum seconds per command; defaults to 60
+	RestrictToWorkspace bool     `yaml:"restrict_to_workspace"` // Confine working directories to Workspace
+	Workspace           string   `yaml:"workspace"`             // Default working directory; defaults to the current directory
+	AllowedCommands     []string `yaml:"allowed_commands"`      // Executable names; empty allows any command not denied
+	DeniedCommands      []string `yaml:"denied_commands"`       // Replaces the built-in deny list when set
+	AllowedEnv          []string `yaml:"allowed_env"`           // Variables passed through to commands; defaults to PATH, HOME, LANG, TERM
+	MaxOutputChars      int      `yaml:"max_output_chars"`      // Longer output is truncated; defaults to 16000
+	AllowBackground     bool     `yaml:"allow_background"`      // Allow long-running background sessions
+
+	// Limits apply to every command the bot runs, including those of the
+	// github, summarize and coding_agent tools (Linux only; 0 is unlimited)
+	Limits struct {
+		CPUSeconds int `yaml:"cpu_seconds"`
+		MemoryMB   int `yaml:"memory_mb"`
+		FileSizeMB int `yaml:"file_size_mb"`
+		Processes  int `yaml:"processes"` // Counts all processes of the bot's user
+	} `yaml:"limits"`
+	SessionTTLMinutes int `yaml:"session_ttl_minutes"` // How long finished background sessions are kept; defaults to 60
+}
+
+// ToolsFetchConfig holds settings for the fetch service shared by web_fetch,
+// summarize and doc_summarize.
+type ToolsFetchConfig struct {
+	UserAgent            string `yaml:"user_agent"`             // Defaults to NuimanBot/1.0; also selects the robots.txt group
+	MaxSizeMB            int    `yaml:"max_size_mb"`            // Largest response read; defaults to 10
+	Timeout              int    `yaml:"timeout"`                // Seconds per fetch; defaults to 30
+	CacheTTL             int    `yaml:"cache_ttl"`              // Seconds fetched documents are reused; defaults to 300, -1 disables
+	IgnoreRobots         bool   `yaml:"ignore_robots"`          // Skip robots.txt checks
+	AllowPrivateNetworks bool   `yaml:"allow_private_networks"` // Permit loopback, private and link-local addresses
 }
 
 // ToolSettings holds all tool-specific configurations (API keys, limits, etc).
 type ToolSettings struct {
 	WebSearch ToolsWebSearchConfig `yaml:"web_search"`
 	Exec      ToolsExecConfig      `yaml:"exec"`
+	Fetch     ToolsFetchConfig     `yaml:"fetch"`
 }
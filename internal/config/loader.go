@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/mitchellh/mapstructure"
@@ -62,6 +65,9 @@ func LoadConfig(configPaths ...string) (*NuimanBotConfig, error) {
 		delete(llmSettings, "openai")
 		delete(llmSettings, "ollama")
 	}
+	if toolSettings, ok := allSettings["tools"].(map[string]interface{}); ok {
+		delete(toolSettings, "entries") // api_key needs SecureString handling
+	}
 
 	decoderConfig := &mapstructure.DecoderConfig{
 		Metadata: nil,
@@ -122,6 +128,7 @@ func LoadConfig(configPaths ...string) (*NuimanBotConfig, error) {
 	if v.IsSet("llm.anthropic.api_key") {
 		cfg.LLM.Anthropic.APIKey = domain.NewSecureStringFromString(v.GetString("llm.anthropic.api_key"))
 	}
+	cfg.LLM.Anthropic.Keys = loadAPIKeys(v, "llm.anthropic.keys")
 
 	// OpenAI
 	if v.IsSet("llm.openai.api_key") {
@@ -136,6 +143,7 @@ func LoadConfig(configPaths ...string) (*NuimanBotConfig, error) {
 	if v.IsSet("llm.openai.organization") {
 		cfg.LLM.OpenAI.Organization = v.GetString("llm.openai.organization")
 	}
+	cfg.LLM.OpenAI.Keys = loadAPIKeys(v, "llm.openai.keys")
 
 	// Ollama
 	if v.IsSet("llm.ollama.base_url") {
@@ -162,6 +170,44 @@ func LoadConfig(configPaths ...string) (*NuimanBotConfig, error) {
 		cfg.LLM.Bedrock.RequestTimeout = v.GetInt("llm.bedrock.request_timeout")
 	}
 
+	if v.IsSet("llm.catalog_file") {
+		cfg.LLM.CatalogFile = v.GetString("llm.catalog_file")
+	}
+
+	// Replay
+	if v.IsSet("llm.replay.mode") {
+		cfg.LLM.Replay.Mode = v.GetString("llm.replay.mode")
+	}
+	if v.IsSet("llm.replay.cassette_dir") {
+		cfg.LLM.Replay.CassetteDir = v.GetString("llm.replay.cassette_dir")
+	}
+
+	if v.IsSet("llm.max_continuations") {
+		cfg.LLM.MaxContinuations = v.GetInt("llm.max_continuations")
+	}
+
+	// Batch jobs
+	if v.IsSet("llm.batch.workers") {
+		cfg.LLM.Batch.Workers = v.GetInt("llm.batch.workers")
+	}
+	if v.IsSet("llm.batch.poll_interval_seconds") {
+		cfg.LLM.Batch.PollIntervalSeconds = v.GetInt("llm.batch.poll_interval_seconds")
+	}
+
+	// Inspector
+	if v.IsSet("llm.inspector.enabled") {
+		cfg.LLM.Inspector.Enabled = v.GetBool("llm.inspector.enabled")
+	}
+	if v.IsSet("llm.inspector.sample_rate") {
+		cfg.LLM.Inspector.SampleRate = v.GetFloat64("llm.inspector.sample_rate")
+	}
+	if v.IsSet("llm.inspector.max_requests") {
+		cfg.LLM.Inspector.MaxRequests = v.GetInt("llm.inspector.max_requests")
+	}
+	if v.IsSet("llm.inspector.retention_hours") {
+		cfg.LLM.Inspector.RetentionHours = v.GetInt("llm.inspector.retention_hours")
+	}
+
 	if v.IsSet("gateways.telegram.token") {
 		cfg.Gateways.Telegram.Token = domain.NewSecureStringFromString(v.GetString("gateways.telegram.token"))
 	}
@@ -178,7 +224,11 @@ func LoadConfig(configPaths ...string) (*NuimanBotConfig, error) {
 		cfg.ExternalAPI.REST.APIKey = domain.NewSecureStringFromString(v.GetString("external_api.rest.api_key"))
 	}
 
-	// Load tools from environment variables
+	// Tool entries from file, then API keys from environment variables
+	cfg.Tools.Entries, err = loadToolEntries(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tool configuration:\n%w", err)
+	}
 	loadToolsFromEnv(&cfg)
 
 	// Set environment from env var if not set in config
@@ -256,6 +306,14 @@ func applyEnvOverrides(cfg *NuimanBotConfig) {
 		}
 	}
 
+	// Replay provider configuration from environment
+	if val := os.Getenv("NUIMANBOT_LLM_REPLAY_MODE"); val != "" {
+		cfg.LLM.Replay.Mode = val
+	}
+	if val := os.Getenv("NUIMANBOT_LLM_REPLAY_CASSETTEDIR"); val != "" {
+		cfg.LLM.Replay.CassetteDir = val
+	}
+
 	// Gateway config
 	if val := os.Getenv("NUIMANBOT_GATEWAYS_CLI_DEBUGMODE"); val != "" {
 		if b, err := strconv.ParseBool(val); err == nil {
@@ -316,19 +374,140 @@ func loadProvidersFromEnv(cfg *NuimanBotConfig) {
 	}
 }
 
-// loadToolsFromEnv loads tool configurations from environment variables.
+// loadToolsFromEnv loads tool API keys from NUIMANBOT_TOOLS_ENTRIES_<NAME>_APIKEY
+// environment variables, e.g. NUIMANBOT_TOOLS_ENTRIES_WEATHER_APIKEY.
 func loadToolsFromEnv(cfg *NuimanBotConfig) {
 	// Initialize map if not exists
 	if cfg.Tools.Entries == nil {
 		cfg.Tools.Entries = make(map[string]ToolConfig)
 	}
 
-	// Check for calculator tool API key
-	if apiKey := os.Getenv("NUIMANBOT_TOOLS_ENTRIES_CALCULATOR_APIKEY"); apiKey != "" {
-		toolCfg := cfg.Tools.Entries["calculator"]
+	const prefix, suffix = "NUIMANBOT_TOOLS_ENTRIES_", "_APIKEY"
+	for _, kv := range os.Environ() {
+		key, apiKey, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, suffix) || apiKey == "" {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(key, prefix), suffix))
+		if name == "" {
+			continue
+		}
+		toolCfg := cfg.Tools.Entries[name]
 		toolCfg.APIKey = domain.NewSecureStringFromString(apiKey)
-		cfg.Tools.Entries["calculator"] = toolCfg
+		cfg.Tools.Entries[name] = toolCfg
 	}
+}
 
-	// Add more tools as needed
+// loadToolEntries reads tools.entries from the config file. Unknown keys
+// and wrongly typed values in an entry are errors, like unknown params.
+func loadToolEntries(v *viper.Viper) (map[string]ToolConfig, error) {
+	entries := make(map[string]ToolConfig)
+	raw, ok := v.Get("tools.entries").(map[string]interface{})
+	if !ok {
+		return entries, nil
+	}
+
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if raw[name] == nil {
+			entries[name] = ToolConfig{} // Bare entry, e.g. "calculator:"
+			continue
+		}
+		e, ok := raw[name].(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf("tools.entries.%s: must be a mapping, got %T", name, raw[name]))
+			continue
+		}
+
+		keys := make([]string, 0, len(e))
+		for key := range e {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var toolCfg ToolConfig
+		for _, key := range keys {
+			value := e[key]
+			switch key {
+			case "enabled":
+				enabled, ok := value.(bool)
+				if !ok {
+					errs = append(errs, fmt.Errorf("tools.entries.%s.enabled: must be true or false, got %T", name, value))
+					continue
+				}
+				toolCfg.Enabled = &enabled
+			case "api_key":
+				apiKey, ok := value.(string)
+				if !ok {
+					errs = append(errs, fmt.Errorf("tools.entries.%s.api_key: must be a string, got %T", name, value))
+					continue
+				}
+				toolCfg.APIKey = domain.NewSecureStringFromString(apiKey)
+			case "env":
+				env, ok := value.(map[string]interface{})
+				if !ok {
+					errs = append(errs, fmt.Errorf("tools.entries.%s.env: must be a mapping, got %T", name, value))
+					continue
+				}
+				toolCfg.Env = make(map[string]string, len(env))
+				for k, val := range env {
+					toolCfg.Env[k] = fmt.Sprint(val)
+				}
+			case "params":
+				params, ok := value.(map[string]interface{})
+				if !ok && value != nil {
+					errs = append(errs, fmt.Errorf("tools.entries.%s.params: must be a mapping, got %T", name, value))
+					continue
+				}
+				toolCfg.Params = params
+			default:
+				errs = append(errs, fmt.Errorf("tools.entries.%s: unknown key %q", name, key))
+			}
+		}
+		entries[name] = toolCfg
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return entries, nil
+}
+
+// loadAPIKeys reads a provider key pool list from the config file.
+func loadAPIKeys(v *viper.Viper, path string) []APIKeyConfig {
+	entries, ok := v.Get(path).([]interface{})
+	if !ok {
+		return nil
+	}
+
+	keys := make([]APIKeyConfig, 0, len(entries))
+	for _, entry := range entries {
+		k, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var keyCfg APIKeyConfig
+		if id, ok := k["id"].(string); ok {
+			keyCfg.ID = id
+		}
+		if apiKey, ok := k["api_key"].(string); ok {
+			keyCfg.APIKey = domain.NewSecureStringFromString(apiKey)
+		}
+		if vaultKey, ok := k["vault_key"].(string); ok {
+			keyCfg.VaultKey = vaultKey
+		}
+		if rpm, ok := k["requests_per_minute"].(int); ok {
+			keyCfg.RequestsPerMinute = rpm
+		}
+		if tpm, ok := k["tokens_per_minute"].(int); ok {
+			keyCfg.TokensPerMinute = tpm
+		}
+		keys = append(keys, keyCfg)
+	}
+	return keys
 }
@@ -348,3 +348,105 @@ llm:
 		t.Errorf("Expected Security.InputMaxLength 512 from file, got %d", cfg.Security.InputMaxLength)
 	}
 }
+
+func TestLoadConfig_ToolEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	configFilePath := filepath.Join(tempDir, "config.yaml")
+	configContent := `
+tools:
+  entries:
+    calculator:
+    weather:
+      enabled: true
+      api_key: owm-key
+      params:
+        timeout: 5
+    coding_agent:
+      enabled: false
+  openapi:
+    - spec: ./specs/billing.yaml
+      prefix: billing_
+      include: [listInvoices, "GET /customers/*"]
+      auth:
+        vault_key: billing_token
+`
+	if err := os.WriteFile(configFilePath, []byte(configContent), 0o644); err != nil {
+		t.Fatalf("Failed to write temp config file: %v", err)
+	}
+	if err := os.Setenv("NUIMANBOT_ENCRYPTION_KEY", "DDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDD="); err != nil {
+		t.Fatalf("Failed to set env var: %v", err)
+	}
+
+	cfg, err := config.LoadConfig(tempDir)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	calc, ok := cfg.Tools.Entries["calculator"]
+	if !ok || calc.Enabled != nil {
+		t.Errorf("Expected bare calculator entry with default enabled, got %+v (present %v)", calc, ok)
+	}
+	weather := cfg.Tools.Entries["weather"]
+	if weather.Enabled == nil || !*weather.Enabled {
+		t.Error("Expected weather enabled")
+	}
+	if weather.APIKey.Value() != "owm-key" {
+		t.Errorf("Expected weather api_key 'owm-key', got '%s'", weather.APIKey.Value())
+	}
+	if weather.Params["timeout"] != 5 {
+		t.Errorf("Expected weather timeout param 5, got %#v", weather.Params["timeout"])
+	}
+	if agent := cfg.Tools.Entries["coding_agent"]; agent.Enabled == nil || *agent.Enabled {
+		t.Error("Expected coding_agent explicitly disabled")
+	}
+
+	if len(cfg.Tools.OpenAPI) != 1 {
+		t.Fatalf("Expected 1 OpenAPI spec, got %d", len(cfg.Tools.OpenAPI))
+	}
+	spec := cfg.Tools.OpenAPI[0]
+	if spec.Spec != "./specs/billing.yaml" || spec.Prefix != "billing_" || len(spec.Include) != 2 || spec.Auth.VaultKey != "billing_token" {
+		t.Errorf("Unexpected OpenAPI config: %+v", spec)
+	}
+}
+
+func TestLoadConfig_ToolEntriesInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries string
+		want    string
+	}{
+		{
+			name:    "unknown key",
+			entries: "    weather:\n      enabeld: true\n",
+			want:    `tools.entries.weather: unknown key "enabeld"`,
+		},
+		{
+			name:    "non-bool enabled",
+			entries: "    notes:\n      enabled: \"no\"\n",
+			want:    "tools.entries.notes.enabled: must be true or false",
+		},
+		{
+			name:    "params not a mapping",
+			entries: "    github:\n      params: [timeout]\n",
+			want:    "tools.entries.github.params: must be a mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			configContent := "tools:\n  entries:\n" + tt.entries
+			if err := os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte(configContent), 0o644); err != nil {
+				t.Fatalf("Failed to write temp config file: %v", err)
+			}
+			if err := os.Setenv("NUIMANBOT_ENCRYPTION_KEY", "DDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDD="); err != nil {
+				t.Fatalf("Failed to set env var: %v", err)
+			}
+
+			_, err := config.LoadConfig(tempDir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
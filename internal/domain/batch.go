@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// BatchJobStatus represents the lifecycle state of a batch job.
+type BatchJobStatus string
+
+const (
+	// BatchJobPending indicates the job is stored but not yet submitted or started
+	BatchJobPending BatchJobStatus = "pending"
+
+	// BatchJobRunning indicates the job is being processed
+	BatchJobRunning BatchJobStatus = "running"
+
+	// BatchJobCancelling indicates cancellation was requested and the provider has not confirmed it yet
+	BatchJobCancelling BatchJobStatus = "cancelling"
+
+	// BatchJobCompleted indicates every item has a result (items may still have failed individually)
+	BatchJobCompleted BatchJobStatus = "completed"
+
+	// BatchJobFailed indicates the job could not be processed as a whole
+	BatchJobFailed BatchJobStatus = "failed"
+
+	// BatchJobCancelled indicates the job was cancelled; finished items keep their results
+	BatchJobCancelled BatchJobStatus = "cancelled"
+)
+
+// IsTerminal returns true if the status represents a terminal state
+func (s BatchJobStatus) IsTerminal() bool {
+	return s == BatchJobCompleted || s == BatchJobFailed || s == BatchJobCancelled
+}
+
+// BatchMode is how a batch job is executed.
+type BatchMode string
+
+const (
+	// BatchModeProvider submits the job to the provider's batch endpoint
+	BatchModeProvider BatchMode = "provider"
+
+	// BatchModeLocal runs the job through a local bounded worker pool
+	BatchModeLocal BatchMode = "local"
+)
+
+// BatchItemStatus represents the state of a single request in a batch job.
+type BatchItemStatus string
+
+const (
+	BatchItemPending   BatchItemStatus = "pending"
+	BatchItemSucceeded BatchItemStatus = "succeeded"
+	BatchItemFailed    BatchItemStatus = "failed"
+	BatchItemCancelled BatchItemStatus = "cancelled"
+)
+
+// BatchItemCounts summarizes item states for a job.
+type BatchItemCounts struct {
+	Pending   int
+	Succeeded int
+	Failed    int
+	Cancelled int
+}
+
+// Total returns the number of items in the job.
+func (c BatchItemCounts) Total() int {
+	return c.Pending + c.Succeeded + c.Failed + c.Cancelled
+}
+
+// BatchJob is a set of LLM requests processed offline.
+type BatchJob struct {
+	ID              string
+	Name            string
+	Provider        LLMProvider
+	Mode            BatchMode
+	ProviderBatchID string // Set once submitted in provider mode
+	Status          BatchJobStatus
+	Error           string
+	CreatedBy       string
+	Counts          BatchItemCounts // Filled in by the repository
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	CompletedAt     time.Time // Zero until the job reaches a terminal state
+}
+
+// BatchItem is a single request in a batch job and its result.
+type BatchItem struct {
+	JobID    string
+	CustomID string // Unique within the job; used to match provider results
+	Request  LLMRequest
+	Status   BatchItemStatus
+	Response *LLMResponse
+	Error    string
+}
+
+// BatchRequest is a request submitted to a provider batch endpoint.
+type BatchRequest struct {
+	CustomID string
+	Request  *LLMRequest
+}
+
+// BatchResult is the outcome of one request in a provider batch.
+type BatchResult struct {
+	CustomID string
+	Status   BatchItemStatus
+	Response *LLMResponse // Set when Status is BatchItemSucceeded
+	Error    string
+}
+
+// LLMBatchStatus is the provider-reported progress of a batch.
+type LLMBatchStatus struct {
+	Done       bool // Processing has ended and results can be fetched
+	Processing int
+	Succeeded  int
+	Failed     int
+	Cancelled  int
+}
+
+// LLMBatchService is implemented by provider clients with a native batch API
+// (e.g. Anthropic Message Batches, OpenAI Batch), which trade latency for
+// lower cost on bulk offline work.
+type LLMBatchService interface {
+	// SupportsBatch reports whether batches can be submitted for provider.
+	SupportsBatch(provider LLMProvider) bool
+
+	// SubmitBatch submits the requests and returns the provider's batch ID.
+	SubmitBatch(ctx context.Context, provider LLMProvider, requests []BatchRequest) (string, error)
+
+	// GetBatch returns the progress of a submitted batch.
+	GetBatch(ctx context.Context, provider LLMProvider, batchID string) (*LLMBatchStatus, error)
+
+	// BatchResults returns the results of a batch whose processing has ended.
+	BatchResults(ctx context.Context, provider LLMProvider, batchID string) ([]BatchResult, error)
+
+	// CancelBatch asks the provider to stop processing a batch.
+	CancelBatch(ctx context.Context, provider LLMProvider, batchID string) error
+}
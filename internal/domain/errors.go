@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // ErrNotFound is returned when a requested entity is not found.
@@ -41,6 +42,36 @@ var ErrRateLimitExceeded = errors.New("rate limit exceeded")
 // ErrLLMUnavailable is returned when an LLM provider is unavailable.
 var ErrLLMUnavailable = errors.New("LLM provider unavailable")
 
+// RateLimitError is returned by LLM providers when a request is rejected for
+// exceeding a rate limit (HTTP 429). It matches ErrRateLimitExceeded with errors.Is.
+type RateLimitError struct {
+	Provider   LLMProvider
+	RetryAfter time.Duration // How long the provider asked callers to wait; zero if unknown
+	Cause      error
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	msg := fmt.Sprintf("%s rate limit exceeded", e.Provider)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying provider error.
+func (e *RateLimitError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is ErrRateLimitExceeded.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimitExceeded
+}
+
 // Other potential errors could be added here as needed, e.g.:
 // ErrLLMProviderNotConfigured
 // ErrCredentialRotationFailed
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+)
 
 // LLMProvider identifies different LLM provider types.
 type LLMProvider string
@@ -16,6 +19,80 @@ const (
 type Message struct {
 	Role    string `json:"role"`    // e.g., "user", "assistant", "system"
 	Content string `json:"content"` // The message content
+	// Reasoning holds thinking blocks produced with an assistant message. Providers
+	// that sign thinking blocks (Anthropic, Bedrock Claude) require them to be echoed
+	// back unmodified during tool loops.
+	Reasoning []ReasoningBlock `json:"reasoning,omitempty"`
+}
+
+// ReasoningBlock is a single block of model reasoning (extended thinking).
+type ReasoningBlock struct {
+	Text         string `json:"text,omitempty"`
+	Signature    string `json:"signature,omitempty"`     // Provider signature verifying the text
+	RedactedData []byte `json:"redacted_data,omitempty"` // Encrypted reasoning the provider withheld
+}
+
+// ReasoningEffort is a coarse reasoning level for providers that accept effort hints.
+type ReasoningEffort string
+
+const (
+	ReasoningEffortLow    ReasoningEffort = "low"
+	ReasoningEffortMedium ReasoningEffort = "medium"
+	ReasoningEffortHigh   ReasoningEffort = "high"
+)
+
+// Thinking budgets used to translate between effort levels and token budgets.
+const (
+	ReasoningBudgetLow    = 1024
+	ReasoningBudgetMedium = 4096
+	ReasoningBudgetHigh   = 16384
+)
+
+// ReasoningConfig requests extended thinking or reasoning from the model.
+// Set either BudgetTokens or Effort; adapters translate one into the other
+// when the provider only supports a single form.
+type ReasoningConfig struct {
+	BudgetTokens int             // Thinking token budget (Anthropic, Bedrock Claude)
+	Effort       ReasoningEffort // Reasoning effort (OpenAI reasoning models)
+}
+
+// Enabled reports whether reasoning was requested.
+func (r ReasoningConfig) Enabled() bool {
+	return r.BudgetTokens > 0 || r.Effort != ""
+}
+
+// Budget returns the thinking token budget, derived from Effort if no explicit budget is set.
+func (r ReasoningConfig) Budget() int {
+	if r.BudgetTokens > 0 {
+		return r.BudgetTokens
+	}
+	switch r.Effort {
+	case ReasoningEffortLow:
+		return ReasoningBudgetLow
+	case ReasoningEffortHigh:
+		return ReasoningBudgetHigh
+	case ReasoningEffortMedium:
+		return ReasoningBudgetMedium
+	default:
+		return 0
+	}
+}
+
+// EffortLevel returns the reasoning effort, derived from BudgetTokens if no explicit effort is set.
+func (r ReasoningConfig) EffortLevel() ReasoningEffort {
+	if r.Effort != "" {
+		return r.Effort
+	}
+	switch {
+	case r.BudgetTokens <= 0:
+		return ""
+	case r.BudgetTokens <= ReasoningBudgetLow:
+		return ReasoningEffortLow
+	case r.BudgetTokens <= ReasoningBudgetMedium:
+		return ReasoningEffortMedium
+	default:
+		return ReasoningEffortHigh
+	}
 }
 
 // LLMRequest represents a request to an LLM.
@@ -26,6 +103,113 @@ type LLMRequest struct {
 	Temperature  float64
 	Tools        []ToolDefinition // For function calling
 	SystemPrompt string
+	Cache        CachePolicy     // Optional: prompt caching hints for providers that support it
+	Reasoning    ReasoningConfig // Optional: extended thinking / reasoning effort
+	// ResponseFormat constrains the response to JSON. Nil means free-form text.
+	ResponseFormat *ResponseFormat
+
+	// Tool use control; nil leaves the provider default (auto, parallel allowed)
+	ToolChoice        *ToolChoice
+	ParallelToolCalls *bool
+
+	// Additional sampling parameters; zero values leave the provider default.
+	// Adapters ignore parameters their provider does not support.
+	TopP          float64
+	TopK          int
+	StopSequences []string
+	Seed          *int // Best-effort determinism (OpenAI, Ollama)
+}
+
+// ToolChoiceMode controls whether and how the model calls tools.
+type ToolChoiceMode string
+
+const (
+	ToolChoiceAuto     ToolChoiceMode = "auto"     // Model decides
+	ToolChoiceNone     ToolChoiceMode = "none"     // Model must not call tools
+	ToolChoiceRequired ToolChoiceMode = "required" // Model must call at least one tool
+	ToolChoiceTool     ToolChoiceMode = "tool"     // Model must call the named tool
+)
+
+// ToolChoice selects how the model uses the request's tools.
+type ToolChoice struct {
+	Mode ToolChoiceMode
+	Name string // Tool name; required for ToolChoiceTool
+}
+
+// ForceTool returns a ToolChoice that requires a call to the named tool.
+func ForceTool(name string) *ToolChoice {
+	return &ToolChoice{Mode: ToolChoiceTool, Name: name}
+}
+
+// ParseToolChoice parses "auto", "none", "required" or a tool name.
+func ParseToolChoice(s string) *ToolChoice {
+	switch mode := ToolChoiceMode(s); mode {
+	case "":
+		return nil
+	case ToolChoiceAuto, ToolChoiceNone, ToolChoiceRequired:
+		return &ToolChoice{Mode: mode}
+	default:
+		return ForceTool(s)
+	}
+}
+
+// ResponseFormatType selects how a model formats its response.
+type ResponseFormatType string
+
+const (
+	ResponseFormatText       ResponseFormatType = "text"
+	ResponseFormatJSONObject ResponseFormatType = "json_object" // Any JSON object
+	ResponseFormatJSONSchema ResponseFormatType = "json_schema" // JSON matching Schema
+)
+
+// DefaultResponseFormatName names the schema (and emulation tool) when none is given.
+const DefaultResponseFormatName = "structured_output"
+
+// ResponseFormat requests structured (JSON) output from an LLM.
+// Providers with native support (OpenAI, Ollama) constrain decoding directly;
+// Anthropic and Bedrock emulate it by forcing a tool call whose input is the JSON.
+type ResponseFormat struct {
+	Type   ResponseFormatType
+	Name   string         // Optional: schema name (letters, digits, underscores)
+	Schema map[string]any // Required for ResponseFormatJSONSchema
+	Strict bool           // Ask providers that support it to enforce the schema strictly
+}
+
+// IsJSON reports whether the format requires a JSON response.
+func (f *ResponseFormat) IsJSON() bool {
+	return f != nil && (f.Type == ResponseFormatJSONObject || f.Type == ResponseFormatJSONSchema)
+}
+
+// SchemaName returns the schema name, defaulting to DefaultResponseFormatName.
+func (f *ResponseFormat) SchemaName() string {
+	if f == nil || f.Name == "" {
+		return DefaultResponseFormatName
+	}
+	return f.Name
+}
+
+// JSONSchema returns the schema the response must satisfy. For json_object
+// (or a json_schema format without a schema) it is a schema accepting any object.
+func (f *ResponseFormat) JSONSchema() map[string]any {
+	if f != nil && f.Type == ResponseFormatJSONSchema && f.Schema != nil {
+		return f.Schema
+	}
+	return map[string]any{"type": "object"}
+}
+
+// CachePolicy marks the stable prefixes of an LLMRequest that providers may cache.
+// Providers without prompt caching ignore it. Breakpoints are applied in prefix
+// order (tools, system prompt, leading history), matching how providers hash prompts.
+type CachePolicy struct {
+	Tools        bool // Cache the tool definitions
+	SystemPrompt bool // Cache the system prompt (and everything before it)
+	// HistoryMessages is the number of leading messages to cache; 0 disables history caching.
+	HistoryMessages int
+}
+
+// Enabled reports whether any cache breakpoint is requested.
+func (p CachePolicy) Enabled() bool {
+	return p.Tools || p.SystemPrompt || p.HistoryMessages > 0
 }
 
 // ToolDefinition defines the schema for a tool that an LLM can use.
@@ -50,10 +234,23 @@ type ToolResult struct {
 }
 
 // TokenUsage provides information about token usage in an LLM interaction.
+// PromptTokens counts every input token, including those written to or read
+// from the provider's prompt cache; the cache fields break that count down.
 type TokenUsage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	TotalTokens         int `json:"total_tokens"`
+	CacheCreationTokens int `json:"cache_creation_tokens,omitempty"` // Input tokens written to the prompt cache
+	CacheReadTokens     int `json:"cache_read_tokens,omitempty"`     // Input tokens served from the prompt cache
+}
+
+// UncachedPromptTokens returns the prompt tokens billed at the regular input rate.
+func (u TokenUsage) UncachedPromptTokens() int {
+	n := u.PromptTokens - u.CacheCreationTokens - u.CacheReadTokens
+	if n < 0 {
+		return 0
+	}
+	return n
 }
 
 // LLMResponse represents a response from an LLM.
@@ -61,23 +258,128 @@ type LLMResponse struct {
 	Content      string
 	ToolCalls    []ToolCall
 	Usage        TokenUsage
-	FinishReason string
+	FinishReason FinishReason
+	Reasoning    []ReasoningBlock // Thinking blocks, in the order the model produced them
+}
+
+// FinishReason is the provider-independent reason a model stopped generating.
+// Adapters map their native stop reasons onto these values.
+type FinishReason string
+
+const (
+	FinishReasonStop          FinishReason = "stop"           // Natural end of turn or a stop sequence
+	FinishReasonLength        FinishReason = "length"         // Output token limit reached; the response is truncated
+	FinishReasonToolCalls     FinishReason = "tool_calls"     // Model is waiting for tool results
+	FinishReasonContentFilter FinishReason = "content_filter" // Provider refused or filtered the output
+	FinishReasonContextWindow FinishReason = "context_window" // Prompt and output filled the context window; continuing cannot help
+	FinishReasonOther         FinishReason = "other"          // Any other provider-specific reason
+)
+
+// Truncated reports whether the output was cut off by the token limit.
+func (r FinishReason) Truncated() bool {
+	return r == FinishReasonLength
+}
+
+// PromoteToolCall moves the first call to the named tool into Content as JSON.
+// Adapters that emulate structured output with a forced tool call use it so that
+// callers always find the JSON document in Content. It reports whether a call was found.
+func (r *LLMResponse) PromoteToolCall(name string) bool {
+	for i, call := range r.ToolCalls {
+		if call.ToolName != name {
+			continue
+		}
+		data, err := json.Marshal(call.Arguments)
+		if err != nil {
+			return false
+		}
+		r.Content = string(data)
+		r.ToolCalls = append(r.ToolCalls[:i:i], r.ToolCalls[i+1:]...)
+		return true
+	}
+	return false
+}
+
+// ReasoningText returns the readable reasoning text, skipping redacted blocks.
+func (r *LLMResponse) ReasoningText() string {
+	return JoinReasoning(r.Reasoning)
 }
 
+// JoinReasoning concatenates the readable text of reasoning blocks.
+func JoinReasoning(blocks []ReasoningBlock) string {
+	var text string
+	for _, block := range blocks {
+		if block.Text == "" {
+			continue
+		}
+		if text != "" {
+			text += "\n\n"
+		}
+		text += block.Text
+	}
+	return text
+}
+
+// StreamChunkKind distinguishes the kind of content carried by a StreamChunk.
+type StreamChunkKind string
+
+const (
+	StreamChunkText     StreamChunkKind = ""         // Answer text (default)
+	StreamChunkThinking StreamChunkKind = "thinking" // Reasoning / extended thinking text
+)
+
 // StreamChunk represents a chunk of a streaming LLM response.
 type StreamChunk struct {
+	Kind     StreamChunkKind
 	Delta    string
 	ToolCall *ToolCall
 	Done     bool
 	Error    error
+	// Signature closes a thinking block (Kind == StreamChunkThinking) on providers that sign reasoning.
+	Signature string
+}
+
+// IsThinking reports whether the chunk carries reasoning rather than answer text.
+func (c StreamChunk) IsThinking() bool {
+	return c.Kind == StreamChunkThinking
 }
 
 // ModelInfo provides details about an available LLM model.
 type ModelInfo struct {
-	ID            string `json:"id"`
-	Name          string `json:"name"`
-	Provider      string `json:"provider"`
-	ContextWindow int    `json:"context_window"`
+	ID              string       `json:"id"`
+	Name            string       `json:"name"`
+	Provider        string       `json:"provider"`
+	ContextWindow   int          `json:"context_window"`
+	MaxOutputTokens int          `json:"max_output_tokens,omitempty"`
+	SupportsTools   bool         `json:"supports_tools,omitempty"`
+	SupportsVision  bool         `json:"supports_vision,omitempty"`
+	SupportsStream  bool         `json:"supports_streaming,omitempty"`
+	Pricing         ModelPricing `json:"pricing,omitempty"`
+}
+
+// ModelPricing lists per-million-token prices in USD. Zero means free or unknown.
+type ModelPricing struct {
+	InputPerMTok      float64 `json:"input_per_mtok,omitempty"`
+	OutputPerMTok     float64 `json:"output_per_mtok,omitempty"`
+	CacheWritePerMTok float64 `json:"cache_write_per_mtok,omitempty"`
+	CacheReadPerMTok  float64 `json:"cache_read_per_mtok,omitempty"`
+}
+
+// Cost returns the USD cost of usage at these prices. Cache writes and reads
+// fall back to the input price when no cache-specific price is set.
+func (p ModelPricing) Cost(usage TokenUsage) float64 {
+	cacheWrite, cacheRead := p.CacheWritePerMTok, p.CacheReadPerMTok
+	if cacheWrite == 0 {
+		cacheWrite = p.InputPerMTok
+	}
+	if cacheRead == 0 {
+		cacheRead = p.InputPerMTok
+	}
+
+	total := float64(usage.UncachedPromptTokens())*p.InputPerMTok +
+		float64(usage.CompletionTokens)*p.OutputPerMTok +
+		float64(usage.CacheCreationTokens)*cacheWrite +
+		float64(usage.CacheReadTokens)*cacheRead
+	return total / 1_000_000
 }
 
 // LLMService defines the contract for interacting with LLM providers.
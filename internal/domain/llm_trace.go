@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// LLMTrace is a redacted record of a single LLM call, kept so that operators can
+// see exactly what was sent to a model and what came back.
+type LLMTrace struct {
+	RequestID string           `json:"request_id"`
+	Provider  LLMProvider      `json:"provider"`
+	Model     string           `json:"model"`
+	Streamed  bool             `json:"streamed,omitempty"`
+	StartedAt time.Time        `json:"started_at"`
+	Duration  time.Duration    `json:"duration"`
+	Request   LLMTraceRequest  `json:"request"`
+	Response  LLMTraceResponse `json:"response"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// LLMTraceRequest is the request as the provider received it (after history
+// trimming and capability adjustments).
+type LLMTraceRequest struct {
+	SystemPrompt   string             `json:"system_prompt,omitempty"`
+	Messages       []Message          `json:"messages"`
+	Tools          []ToolDefinition   `json:"tools,omitempty"`
+	MaxTokens      int                `json:"max_tokens,omitempty"`
+	Temperature    float64            `json:"temperature,omitempty"`
+	ResponseFormat ResponseFormatType `json:"response_format,omitempty"`
+	ToolChoice     *ToolChoice        `json:"tool_choice,omitempty"`
+	TopP           float64            `json:"top_p,omitempty"`
+	TopK           int                `json:"top_k,omitempty"`
+	StopSequences  []string           `json:"stop_sequences,omitempty"`
+	Seed           *int               `json:"seed,omitempty"`
+}
+
+// LLMTraceResponse is the raw model response.
+type LLMTraceResponse struct {
+	Content      string       `json:"content,omitempty"`
+	Reasoning    []string     `json:"reasoning,omitempty"`
+	ToolCalls    []ToolCall   `json:"tool_calls,omitempty"`
+	FinishReason FinishReason `json:"finish_reason,omitempty"`
+	Usage        TokenUsage   `json:"usage"`
+}
+
+// LLMTraceStore looks up recorded LLM traces.
+type LLMTraceStore interface {
+	// Traces returns the calls recorded for a request ID, oldest first.
+	Traces(requestID string) []LLMTrace
+}
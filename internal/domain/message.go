@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
@@ -29,6 +30,20 @@ type OutgoingMessage struct {
 	Content     string
 	Format      string // "text", "markdown"
 	Metadata    map[string]any
+	Reasoning   string // Optional: model reasoning, set only when the user opted to see it
+}
+
+// ContentWithReasoning returns the content prefixed by the reasoning as a
+// markdown block quote, or just the content when there is no reasoning.
+func (m OutgoingMessage) ContentWithReasoning() string {
+	if m.Reasoning == "" {
+		return m.Content
+	}
+	lines := strings.Split(strings.TrimSpace(m.Reasoning), "\n")
+	for i, line := range lines {
+		lines[i] = "> " + line
+	}
+	return "> *Thinking*\n" + strings.Join(lines, "\n") + "\n\n" + m.Content
 }
 
 // StoredMessage represents a message stored in memory/database.
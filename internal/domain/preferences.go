@@ -13,6 +13,8 @@ type UserPreferences struct {
 	// Response Preferences
 	ResponseFormat string `json:"response_format,omitempty"` // markdown, text, json
 	StreamEnabled  bool   `json:"stream_enabled"`            // Enable streaming responses
+	ShowReasoning  bool   `json:"show_reasoning,omitempty"`  // Show model reasoning (extended thinking) alongside answers
+	Timezone       string `json:"timezone,omitempty"`        // IANA timezone, e.g. Europe/Berlin; empty uses the server default
 
 	// Conversation Preferences
 	ContextWindowSize *int `json:"context_window_size,omitempty"` // Max tokens for context, nil uses provider limit
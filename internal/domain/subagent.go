@@ -95,6 +95,10 @@ type SubagentContext struct {
 	// ParentContextID is the ID of the parent conversation context
 	ParentContextID string
 
+	// User is the user the parent conversation runs for. Tool calls made by
+	// the subagent are authorized as this user; nil means no tool calls.
+	User *User
+
 	// SkillName is the name of the skill being executed
 	SkillName string
 
@@ -0,0 +1,457 @@
+package fetch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// maxExcerptChars bounds excerpts taken from the start of the text.
+const maxExcerptChars = 300
+
+// Extract converts a downloaded body into a Document. contentType is the
+// Content-Type header, sniffed from data when empty; source is recorded as
+// the document URL and may be a file path.
+func Extract(data []byte, contentType, source string) (*Document, error) {
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	}
+
+	doc := &Document{URL: source, ContentType: mediaType, Bytes: int64(len(data))}
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		if err := extractHTML(doc, data, contentType); err != nil {
+			return nil, err
+		}
+	case mediaType == "application/pdf":
+		text, err := extractPDF(data)
+		if err != nil {
+			return nil, err
+		}
+		doc.Text = text
+	case isTextType(mediaType):
+		text, err := decode(data, contentType)
+		if err != nil {
+			return nil, err
+		}
+		doc.Text = strings.TrimSpace(text)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, mediaType)
+	}
+
+	if doc.Excerpt == "" {
+		doc.Excerpt = excerpt(doc.Text)
+	}
+	return doc, nil
+}
+
+// isTextType reports whether a media type is returned as plain text.
+func isTextType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" || mediaType == "application/xml" ||
+		strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// decode converts data to UTF-8 using the charset of contentType, a byte
+// order mark or, for HTML, a <meta charset> declaration.
+func decode(data []byte, contentType string) (string, error) {
+	r, err := charset.NewReader(bytes.NewReader(data), contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode charset: %w", err)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode charset: %w", err)
+	}
+	return string(decoded), nil
+}
+
+// excerpt returns the first paragraph of text, shortened to maxExcerptChars.
+func excerpt(text string) string {
+	first, _, _ := strings.Cut(strings.TrimSpace(text), "\n\n")
+	first = strings.Join(strings.Fields(first), " ")
+	if runes := []rune(first); len(runes) > maxExcerptChars {
+		return string(runes[:maxExcerptChars]) + "..."
+	}
+	return first
+}
+
+// extractPDF returns the text layer of a PDF. Scanned PDFs without one
+// produce an error rather than an empty document.
+func extractPDF(data []byte) (text string, err error) {
+	// The parser panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to parse PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("failed to extract PDF text: %w", err)
+	}
+	raw, err := io.ReadAll(plain)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract PDF text: %w", err)
+	}
+
+	text = strings.TrimSpace(string(raw))
+	if text == "" {
+		return "", fmt.Errorf("PDF has no text layer")
+	}
+	return text, nil
+}
+
+var (
+	// unlikelyCandidates matches class and id values of page furniture.
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|comment|cookie|footer|header|menu|modal|nav|newsletter|popup|promo|related|share|sidebar|social|sponsor|subscribe|advert|\bads?\b`)
+	// maybeCandidates rescues elements that also look like content.
+	maybeCandidates = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text`)
+	// positiveWeight and negativeWeight adjust candidate scores by class and id.
+	positiveWeight = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|story|text`)
+	negativeWeight = regexp.MustCompile(`(?i)comment|footer|meta|nav|related|share|sidebar|social|sponsor|widget|\bads?\b`)
+)
+
+// removedTags never contain article text.
+var removedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Iframe: true,
+	atom.Svg: true, atom.Nav: true, atom.Header: true, atom.Footer: true,
+	atom.Aside: true, atom.Form: true, atom.Button: true, atom.Template: true,
+	atom.Select: true, atom.Object: true, atom.Embed: true,
+}
+
+// extractHTML fills doc with the metadata and main content of an HTML page.
+func extractHTML(doc *Document, data []byte, contentType string) error {
+	decoded, err := decode(data, contentType)
+	if err != nil {
+		return err
+	}
+	root, err := html.Parse(strings.NewReader(decoded))
+	if err != nil {
+		return fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	readMetadata(doc, root)
+	clean(root)
+	doc.Text = render(mainContent(root))
+	return nil
+}
+
+// readMetadata sets the title, byline and excerpt from <title> and <meta> tags.
+func readMetadata(doc *Document, root *html.Node) {
+	var title, ogTitle string
+	walk(root, func(n *html.Node) bool {
+		switch n.DataAtom {
+		case atom.Title:
+			if title == "" {
+				title = textContent(n)
+			}
+		case atom.Meta:
+			name := strings.ToLower(attr(n, "name") + attr(n, "property"))
+			content := strings.TrimSpace(attr(n, "content"))
+			switch name {
+			case "og:title":
+				ogTitle = content
+			case "author", "article:author":
+				if doc.Byline == "" {
+					doc.Byline = content
+				}
+			case "description", "og:description":
+				if doc.Excerpt == "" {
+					doc.Excerpt = content
+				}
+			}
+		}
+		return true
+	})
+	doc.Title = title
+	if ogTitle != "" {
+		doc.Title = ogTitle
+	}
+}
+
+// clean removes scripts, navigation and other page furniture in place.
+func clean(root *html.Node) {
+	var remove []*html.Node
+	walk(root, func(n *html.Node) bool {
+		if n.Type == html.CommentNode {
+			remove = append(remove, n)
+			return false
+		}
+		if n.Type != html.ElementNode {
+			return true
+		}
+		if removedTags[n.DataAtom] || attr(n, "hidden") != "" || attr(n, "aria-hidden") == "true" {
+			remove = append(remove, n)
+			return false
+		}
+		switch n.DataAtom {
+		case atom.Html, atom.Body, atom.Article, atom.Main:
+			return true
+		}
+		if ids := attr(n, "class") + " " + attr(n, "id"); unlikelyCandidates.MatchString(ids) && !maybeCandidates.MatchString(ids) {
+			remove = append(remove, n)
+			return false
+		}
+		return true
+	})
+	for _, n := range remove {
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+	}
+}
+
+// mainContent picks the element holding the article: the largest <article>,
+// <main> or role="main" element, or otherwise the highest-scoring paragraph
+// container. It falls back to <body>.
+func mainContent(root *html.Node) *html.Node {
+	var body, semantic *html.Node
+	semanticLen := 0
+	walk(root, func(n *html.Node) bool {
+		if n.DataAtom == atom.Body && body == nil {
+			body = n
+		}
+		if n.DataAtom == atom.Article || n.DataAtom == atom.Main || attr(n, "role") == "main" {
+			if l := len(textContent(n)); l > semanticLen {
+				semantic, semanticLen = n, l
+			}
+		}
+		return true
+	})
+	if semantic != nil && semanticLen > 0 {
+		return semantic
+	}
+
+	// Readability-style scoring: paragraphs credit their parent fully and
+	// their grandparent by half
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+	walk(root, func(n *html.Node) bool {
+		switch n.DataAtom {
+		case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+		default:
+			return true
+		}
+		text := textContent(n)
+		if len(text) < 25 {
+			return false
+		}
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+		addScore(n.Parent, score)
+		if n.Parent != nil {
+			addScore(n.Parent.Parent, score/2)
+		}
+		return false
+	})
+
+	var best *html.Node
+	bestScore := 0.0
+	for _, n := range candidates {
+		score := scores[n] * (1 - linkDensity(n))
+		if best == nil || score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	if best != nil {
+		return best
+	}
+	if body != nil {
+		return body
+	}
+	return root
+}
+
+// initialScore is a candidate's score before its paragraphs are counted.
+func initialScore(n *html.Node) float64 {
+	score := 0.0
+	switch n.DataAtom {
+	case atom.Div, atom.Section, atom.Article:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+	for _, value := range []string{attr(n, "class"), attr(n, "id")} {
+		if value == "" {
+			continue
+		}
+		if positiveWeight.MatchString(value) {
+			score += 25
+		}
+		if negativeWeight.MatchString(value) {
+			score -= 25
+		}
+	}
+	return score
+}
+
+// linkDensity is the share of a node's text that sits inside links.
+func linkDensity(n *html.Node) float64 {
+	total := len(textContent(n))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	walk(n, func(c *html.Node) bool {
+		if c.DataAtom == atom.A {
+			linked += len(textContent(c))
+			return false
+		}
+		return true
+	})
+	return float64(linked) / float64(total)
+}
+
+// blockTags start a new paragraph when rendering.
+var blockTags = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Blockquote: true, atom.Dd: true,
+	atom.Div: true, atom.Dl: true, atom.Dt: true, atom.Figcaption: true,
+	atom.Figure: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true,
+	atom.H5: true, atom.H6: true, atom.Hr: true, atom.Li: true, atom.Main: true,
+	atom.Ol: true, atom.P: true, atom.Section: true, atom.Table: true,
+	atom.Tr: true, atom.Ul: true, atom.Body: true,
+}
+
+// renderer turns an HTML subtree into paragraphs separated by blank lines.
+type renderer struct {
+	blocks []string
+	lines  []string
+	line   strings.Builder
+	prefix string
+}
+
+// render returns the readable text of n.
+func render(n *html.Node) string {
+	r := &renderer{}
+	r.node(n)
+	r.flush()
+	return strings.Join(r.blocks, "\n\n")
+}
+
+func (r *renderer) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.line.WriteString(n.Data)
+		return
+	case html.ElementNode, html.DocumentNode:
+	default:
+		return
+	}
+
+	switch {
+	case n.DataAtom == atom.Br:
+		r.endLine()
+		return
+	case n.DataAtom == atom.Pre:
+		r.flush()
+		if text := strings.Trim(rawText(n), "\n"); strings.TrimSpace(text) != "" {
+			r.blocks = append(r.blocks, text)
+		}
+		return
+	case n.DataAtom == atom.Td || n.DataAtom == atom.Th:
+		r.line.WriteString(" ")
+	case blockTags[n.DataAtom]:
+		r.flush()
+		if n.DataAtom == atom.Li {
+			r.prefix = "- "
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.node(c)
+	}
+
+	if blockTags[n.DataAtom] {
+		r.flush()
+		r.prefix = ""
+	}
+}
+
+// endLine finishes the current line within the paragraph.
+func (r *renderer) endLine() {
+	if line := strings.Join(strings.Fields(r.line.String()), " "); line != "" {
+		r.lines = append(r.lines, line)
+	}
+	r.line.Reset()
+}
+
+// flush finishes the current paragraph.
+func (r *renderer) flush() {
+	r.endLine()
+	if len(r.lines) > 0 {
+		r.blocks = append(r.blocks, r.prefix+strings.Join(r.lines, "\n"))
+		r.prefix = ""
+	}
+	r.lines = nil
+}
+
+// walk visits n and its descendants in document order; returning false from
+// visit skips a node's children.
+func walk(n *html.Node, visit func(*html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		walk(c, visit)
+		c = next
+	}
+}
+
+// attr returns the value of an element's attribute.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// textContent returns the whitespace-normalized text below n.
+func textContent(n *html.Node) string {
+	return strings.Join(strings.Fields(rawText(n)), " ")
+}
+
+// rawText returns the text below n with whitespace preserved.
+func rawText(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+		return true
+	})
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package fetch_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/infrastructure/fetch"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Site | Fallback title</title>
+	<meta property="og:title" content="Go 1.24 Released">
+	<meta name="author" content="The Go Team">
+	<meta name="description" content="Release notes for Go 1.24.">
+	<script>var tracking = "do not include";</script>
+</head>
+<body>
+	<nav><a href="/">Home</a> <a href="/blog">Blog</a></nav>
+	<div class="sidebar">Popular posts you might like</div>
+	<div id="content" class="post-body">
+		<h1>Go 1.24 is out</h1>
+		<p>Today the Go team is happy to release Go 1.24, with generic type aliases, faster maps and more.</p>
+		<p>Generic type aliases let a type alias declare its own type parameters, just like a defined type.</p>
+		<ul><li>Swiss-table maps</li><li>Weak pointers</li></ul>
+		<pre>go install golang.org/dl/go1.24@latest
+go1.24 download</pre>
+	</div>
+	<div class="comments"><p>First! This comment, of course, is not part of the article at all.</p></div>
+	<footer>Copyright 2025</footer>
+</body>
+</html>`
+
+func TestExtract_HTMLMainContent(t *testing.T) {
+	doc, err := fetch.Extract([]byte(articlePage), "text/html; charset=utf-8", "https://go.dev/blog/go1.24")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if doc.Title != "Go 1.24 Released" {
+		t.Errorf("Expected og:title, got %q", doc.Title)
+	}
+	if doc.Byline != "The Go Team" {
+		t.Errorf("Expected byline 'The Go Team', got %q", doc.Byline)
+	}
+	if doc.Excerpt != "Release notes for Go 1.24." {
+		t.Errorf("Expected meta description excerpt, got %q", doc.Excerpt)
+	}
+	if doc.ContentType != "text/html" {
+		t.Errorf("Expected content type text/html, got %q", doc.ContentType)
+	}
+
+	for _, want := range []string{
+		"Go 1.24 is out",
+		"Today the Go team is happy to release Go 1.24",
+		"- Swiss-table maps\n\n- Weak pointers",
+		"go install golang.org/dl/go1.24@latest\ngo1.24 download",
+	} {
+		if !strings.Contains(doc.Text, want) {
+			t.Errorf("Expected text to contain %q, got:\n%s", want, doc.Text)
+		}
+	}
+	for _, unwanted := range []string{"tracking", "Home", "Popular posts", "First!", "Copyright"} {
+		if strings.Contains(doc.Text, unwanted) {
+			t.Errorf("Expected text not to contain %q, got:\n%s", unwanted, doc.Text)
+		}
+	}
+}
+
+func TestExtract_HTMLPrefersArticle(t *testing.T) {
+	page := `<html><body>
+		<div class="promo-box"><p>Buy now, limited offer, while stocks last, act fast.</p></div>
+		<article><h2>Heading</h2><p>Short article body.</p></article>
+	</body></html>`
+
+	doc, err := fetch.Extract([]byte(page), "text/html", "https://example.com")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Text != "Heading\n\nShort article body." {
+		t.Errorf("Unexpected text: %q", doc.Text)
+	}
+	if doc.Excerpt != "Heading" {
+		t.Errorf("Expected excerpt from the first paragraph, got %q", doc.Excerpt)
+	}
+}
+
+func TestExtract_Charset(t *testing.T) {
+	// "Café crème" in ISO-8859-1
+	latin1 := []byte("<html><head><meta charset=\"iso-8859-1\"></head><body><p>Caf\xe9 cr\xe8me</p></body></html>")
+
+	doc, err := fetch.Extract(latin1, "text/html", "https://example.com")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Text != "Café crème" {
+		t.Errorf("Expected decoded text 'Café crème', got %q", doc.Text)
+	}
+
+	doc, err = fetch.Extract([]byte("na\xefve"), "text/plain; charset=windows-1252", "https://example.com/a.txt")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Text != "naïve" {
+		t.Errorf("Expected decoded text 'naïve', got %q", doc.Text)
+	}
+}
+
+func TestExtract_PlainFormats(t *testing.T) {
+	doc, err := fetch.Extract([]byte(`{"name": "nuimanbot"}`), "application/json", "https://example.com/x.json")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Text != `{"name": "nuimanbot"}` {
+		t.Errorf("Expected JSON returned verbatim, got %q", doc.Text)
+	}
+
+	// Sniffed when no content type is given
+	doc, err = fetch.Extract([]byte("# README\n\nHello"), "", "README.md")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.ContentType != "text/plain" || doc.Text != "# README\n\nHello" {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+}
+
+func TestExtract_UnsupportedType(t *testing.T) {
+	_, err := fetch.Extract([]byte{0x89, 'P', 'N', 'G'}, "image/png", "https://example.com/a.png")
+	if !errors.Is(err, fetch.ErrUnsupportedType) {
+		t.Errorf("Expected ErrUnsupportedType, got %v", err)
+	}
+}
+
+func TestExtract_PDF(t *testing.T) {
+	doc, err := fetch.Extract(minimalPDF("Hello from a PDF"), "application/pdf", "https://example.com/a.pdf")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if !strings.Contains(doc.Text, "Hello from a PDF") {
+		t.Errorf("Expected PDF text, got %q", doc.Text)
+	}
+
+	if _, err := fetch.Extract([]byte("%PDF-1.4 truncated"), "application/pdf", "broken.pdf"); err == nil {
+		t.Error("Expected error for a malformed PDF")
+	}
+}
+
+// minimalPDF builds a one-page PDF showing text in Helvetica.
+func minimalPDF(text string) []byte {
+	content := fmt.Sprintf("BT /F1 12 Tf 72 712 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
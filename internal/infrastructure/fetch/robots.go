@@ -0,0 +1,213 @@
+package fetch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// robotsTTL is how long a host's robots.txt is reused.
+	robotsTTL = time.Hour
+	// maxRobotsBytes is the largest robots.txt parsed, as in RFC 9309.
+	maxRobotsBytes = 500 * 1024
+)
+
+// robotsCache fetches and caches robots.txt per scheme and host.
+type robotsCache struct {
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	hosts map[string]cachedRobots
+}
+
+// cachedRobots is a parsed robots.txt with its expiry.
+type cachedRobots struct {
+	robots    *robots
+	expiresAt time.Time
+}
+
+func newRobotsCache(httpClient *http.Client) *robotsCache {
+	return &robotsCache{httpClient: httpClient, hosts: make(map[string]cachedRobots)}
+}
+
+// allowed reports whether userAgent may fetch target.
+func (c *robotsCache) allowed(ctx context.Context, target *url.URL, userAgent string, now time.Time) (bool, error) {
+	key := target.Scheme + "://" + target.Host
+
+	c.mu.Lock()
+	entry, ok := c.hosts[key]
+	c.mu.Unlock()
+	if !ok || !now.Before(entry.expiresAt) {
+		r, err := c.fetch(ctx, key, userAgent)
+		if err != nil {
+			return false, err
+		}
+		entry = cachedRobots{robots: r, expiresAt: now.Add(robotsTTL)}
+		c.mu.Lock()
+		c.hosts[key] = entry
+		c.mu.Unlock()
+	}
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+	return entry.robots.allowed(productToken(userAgent), path), nil
+}
+
+// fetch downloads robots.txt for origin. Following RFC 9309, a missing file
+// (4xx) allows everything and an unavailable one (5xx or network failure)
+// disallows everything until the entry expires.
+func (c *robotsCache) fetch(ctx context.Context, origin, userAgent string) (*robots, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create robots.txt request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, ErrPrivateNetwork) {
+			return nil, fmt.Errorf("failed to fetch robots.txt: %w", err)
+		}
+		return &robots{disallowAll: true}, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return &robots{disallowAll: true}, nil
+	case resp.StatusCode != http.StatusOK:
+		return &robots{}, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read robots.txt: %w", err)
+	}
+	return parseRobots(body), nil
+}
+
+// robots holds the rule groups of a robots.txt file.
+type robots struct {
+	disallowAll bool
+	groups      []robotsGroup
+}
+
+// robotsGroup is a set of rules shared by one or more user agents.
+type robotsGroup struct {
+	agents []string
+	rules  []robotsRule
+}
+
+// robotsRule is one Allow or Disallow line.
+type robotsRule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+// parseRobots parses a robots.txt body. Unknown lines are ignored.
+func parseRobots(body []byte) *robots {
+	r := &robots{}
+	var current *robotsGroup
+	inAgents := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		switch field {
+		case "user-agent":
+			// Consecutive user-agent lines share the group that follows
+			if !inAgents {
+				r.groups = append(r.groups, robotsGroup{})
+				current = &r.groups[len(r.groups)-1]
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			if current == nil || value == "" {
+				// "Disallow:" with no path allows everything
+				continue
+			}
+			current.rules = append(current.rules, robotsRule{
+				allow:   field == "allow",
+				length:  len(value),
+				pattern: compileRobotsPattern(value),
+			})
+		default:
+			inAgents = false
+		}
+	}
+	return r
+}
+
+// compileRobotsPattern converts a path pattern with "*" wildcards and an
+// optional "$" end anchor into a prefix-matching regular expression.
+func compileRobotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// allowed reports whether agent may fetch path. Groups naming the agent take
+// precedence over "*"; within them the longest matching rule wins, with
+// Allow winning ties.
+func (r *robots) allowed(agent, path string) bool {
+	if r.disallowAll {
+		return false
+	}
+
+	var rules []robotsRule
+	for _, want := range []string{agent, "*"} {
+		matched := false
+		for _, g := range r.groups {
+			for _, a := range g.agents {
+				if a == want {
+					rules = append(rules, g.rules...)
+					matched = true
+					break
+				}
+			}
+		}
+		if matched {
+			break
+		}
+	}
+
+	allow, best := true, -1
+	for _, rule := range rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > best || (rule.length == best && rule.allow) {
+			allow, best = rule.allow, rule.length
+		}
+	}
+	return allow
+}
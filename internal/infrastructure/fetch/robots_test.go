@@ -0,0 +1,72 @@
+package fetch
+
+import "testing"
+
+func TestParseRobots(t *testing.T) {
+	r := parseRobots([]byte(`
+# Comments and unknown fields are ignored
+Sitemap: https://example.com/sitemap.xml
+
+User-agent: *
+Disallow: /private/
+Allow: /private/public-report.html
+Disallow: /*.pdf$
+Disallow: /search?
+
+User-agent: NuimanBot
+User-agent: OtherBot
+Disallow: /bots-keep-out
+Disallow:
+`))
+
+	tests := []struct {
+		agent string
+		path  string
+		want  bool
+	}{
+		{"somebot", "/", true},
+		{"somebot", "/private/notes.html", false},
+		{"somebot", "/private/public-report.html", true},
+		{"somebot", "/files/report.pdf", false},
+		{"somebot", "/files/report.pdf?download=1", true},
+		{"somebot", "/search?q=go", false},
+		{"somebot", "/search", true},
+		// NuimanBot's own group replaces the * rules
+		{"nuimanbot", "/private/notes.html", true},
+		{"nuimanbot", "/bots-keep-out/page", false},
+		{"otherbot", "/bots-keep-out", false},
+	}
+	for _, tt := range tests {
+		if got := r.allowed(tt.agent, tt.path); got != tt.want {
+			t.Errorf("allowed(%q, %q) = %v, want %v", tt.agent, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseRobots_LongestMatchWins(t *testing.T) {
+	r := parseRobots([]byte("User-agent: *\nAllow: /docs\nDisallow: /docs/internal\nAllow: /docs/internal/faq\n"))
+
+	if !r.allowed("bot", "/docs/guide") {
+		t.Error("Expected /docs/guide to be allowed")
+	}
+	if r.allowed("bot", "/docs/internal/design") {
+		t.Error("Expected /docs/internal/design to be disallowed")
+	}
+	if !r.allowed("bot", "/docs/internal/faq") {
+		t.Error("Expected the longer Allow rule to win")
+	}
+}
+
+func TestProductToken(t *testing.T) {
+	tests := map[string]string{
+		"NuimanBot/1.0":                        "nuimanbot",
+		"NuimanBot/1.0 (+https://example.com)": "nuimanbot",
+		"Mozilla/5.0 (compatible)":             "mozilla",
+		"MyBot":                                "mybot",
+	}
+	for ua, want := range tests {
+		if got := productToken(ua); got != want {
+			t.Errorf("productToken(%q) = %q, want %q", ua, got, want)
+		}
+	}
+}
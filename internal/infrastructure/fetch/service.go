@@ -0,0 +1,317 @@
+// Package fetch downloads web pages and documents for tools and extracts
+// their readable text. One Service is shared by every tool that reads URLs so
+// size limits, robots.txt policy and caching are applied consistently.
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultUserAgent identifies the bot to web servers and robots.txt.
+	DefaultUserAgent = "NuimanBot/1.0"
+	// DefaultMaxBytes is the largest response body read by default.
+	DefaultMaxBytes = 10 * 1024 * 1024
+	// DefaultTimeout bounds a fetch, including redirects and robots.txt.
+	DefaultTimeout = 30 * time.Second
+	// DefaultCacheTTL is how long fetched documents are reused.
+	DefaultCacheTTL = 5 * time.Minute
+
+	// maxCacheEntries bounds the document cache; the entry closest to expiry
+	// is evicted when a new entry would exceed it.
+	maxCacheEntries = 200
+	// maxRedirects matches net/http's default redirect limit.
+	maxRedirects = 10
+)
+
+var (
+	// ErrTooLarge reports a response body larger than the size limit.
+	ErrTooLarge = errors.New("response too large")
+	// ErrDisallowed reports a URL excluded by the site's robots.txt.
+	ErrDisallowed = errors.New("disallowed by robots.txt")
+	// ErrPrivateNetwork reports a URL resolving to a loopback, private or
+	// link-local address.
+	ErrPrivateNetwork = errors.New("private network addresses are not allowed")
+	// ErrUnsupportedType reports a content type without a text extractor.
+	ErrUnsupportedType = errors.New("unsupported content type")
+)
+
+// Options configures a Service. The zero value respects robots.txt, blocks
+// private networks and uses the package defaults.
+type Options struct {
+	// UserAgent is sent with every request; its product token ("NuimanBot")
+	// selects the robots.txt group.
+	UserAgent string
+	// MaxBytes is the default response size limit.
+	MaxBytes int64
+	// Timeout is the default time limit per fetch.
+	Timeout time.Duration
+	// CacheTTL is how long documents are cached; negative disables caching.
+	CacheTTL time.Duration
+	// IgnoreRobots skips robots.txt checks.
+	IgnoreRobots bool
+	// AllowPrivateNetworks permits loopback, private and link-local
+	// addresses, e.g. for intranet documentation.
+	AllowPrivateNetworks bool
+}
+
+// Request describes one fetch. Zero fields use the service defaults.
+type Request struct {
+	URL       string
+	MaxBytes  int64
+	UserAgent string
+	Timeout   time.Duration
+}
+
+// Document is the text extracted from a fetched resource.
+type Document struct {
+	// URL is the final URL after redirects.
+	URL string
+	// ContentType is the media type without parameters, e.g. "text/html".
+	ContentType string
+	Title       string
+	Byline      string
+	// Excerpt is the page description or the start of the text.
+	Excerpt string
+	// Text is the readable content: the main article of HTML pages, the
+	// text layer of PDFs, or the body of plain-text formats.
+	Text string
+	// Bytes is the size of the downloaded body.
+	Bytes     int64
+	FetchedAt time.Time
+}
+
+// Service fetches URLs and extracts their text. It is safe for concurrent use.
+type Service struct {
+	opts       Options
+	httpClient *http.Client
+	robots     *robotsCache
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedDocument
+}
+
+// cachedDocument is a cached fetch result with its expiry.
+type cachedDocument struct {
+	doc       Document
+	expiresAt time.Time
+}
+
+// NewService creates a fetch service.
+func NewService(opts Options) *Service {
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = DefaultCacheTTL
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !opts.AllowPrivateNetworks {
+		// Checked after DNS resolution so hostnames and redirects cannot
+		// smuggle requests to internal services.
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+				return fmt.Errorf("%w: %s", ErrPrivateNetwork, host)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+
+	s := &Service{
+		opts: opts,
+		httpClient: &http.Client{
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return fmt.Errorf("redirect to unsupported scheme: %s", req.URL.Scheme)
+				}
+				return nil
+			},
+		},
+		now:     time.Now,
+		entries: make(map[string]cachedDocument),
+	}
+	s.robots = newRobotsCache(s.httpClient)
+	return s
+}
+
+// isPrivateIP reports whether ip is not routable on the public internet.
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast()
+}
+
+// Fetch downloads req.URL and extracts its text. Documents are cached by URL
+// for the configured TTL; robots.txt is consulted before the first request
+// to a host.
+func (s *Service) Fetch(ctx context.Context, req Request) (*Document, error) {
+	target, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return nil, fmt.Errorf("only HTTP and HTTPS URLs are supported")
+	}
+	if target.Host == "" {
+		return nil, fmt.Errorf("invalid URL: missing host")
+	}
+	target.Fragment = ""
+	key := target.String()
+
+	maxBytes := req.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = s.opts.MaxBytes
+	}
+	userAgent := req.UserAgent
+	if userAgent == "" {
+		userAgent = s.opts.UserAgent
+	}
+	timeout := req.Timeout
+	if timeout <= 0 {
+		timeout = s.opts.Timeout
+	}
+
+	if doc, ok := s.cached(key); ok {
+		if doc.Bytes > maxBytes {
+			return nil, fmt.Errorf("%w: %d bytes (max %d)", ErrTooLarge, doc.Bytes, maxBytes)
+		}
+		return doc, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if !s.opts.IgnoreRobots {
+		allowed, err := s.robots.allowed(ctx, target, userAgent, s.now())
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: %s", ErrDisallowed, key)
+		}
+	}
+
+	doc, err := s.get(ctx, key, userAgent, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	s.store(key, *doc)
+	return doc, nil
+}
+
+// get performs the HTTP request and extracts the body.
+func (s *Service) get(ctx context.Context, rawURL, userAgent string, maxBytes int64) (*Document, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("User-Agent", userAgent)
+	httpReq.Header.Set("Accept", "text/html,application/xhtml+xml,application/pdf,text/plain;q=0.9,*/*;q=0.5")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes (max %d)", ErrTooLarge, resp.ContentLength, maxBytes)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, maxBytes)
+	}
+
+	finalURL := resp.Request.URL.String()
+	doc, err := Extract(body, resp.Header.Get("Content-Type"), finalURL)
+	if err != nil {
+		return nil, err
+	}
+	doc.FetchedAt = s.now()
+	return doc, nil
+}
+
+// cached returns an unexpired cached document.
+func (s *Service) cached(key string) (*Document, bool) {
+	if s.opts.CacheTTL < 0 {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok || !s.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	doc := entry.doc
+	return &doc, true
+}
+
+// store caches doc under key, dropping expired entries first.
+func (s *Service) store(key string, doc Document) {
+	if s.opts.CacheTTL < 0 {
+		return
+	}
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	if len(s.entries) >= maxCacheEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, e := range s.entries {
+			if oldestKey == "" || e.expiresAt.Before(oldest) {
+				oldestKey, oldest = k, e.expiresAt
+			}
+		}
+		delete(s.entries, oldestKey)
+	}
+	s.entries[key] = cachedDocument{doc: doc, expiresAt: now.Add(s.opts.CacheTTL)}
+}
+
+// productToken returns the lower-case product name of a User-Agent string,
+// e.g. "nuimanbot" for "NuimanBot/1.0 (+https://example.com)".
+func productToken(userAgent string) string {
+	token, _, _ := strings.Cut(strings.TrimSpace(userAgent), "/")
+	if i := strings.IndexAny(token, " ("); i >= 0 {
+		token = token[:i]
+	}
+	return strings.ToLower(token)
+}
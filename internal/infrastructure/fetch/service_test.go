@@ -0,0 +1,184 @@
+package fetch_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"nuimanbot/internal/infrastructure/fetch"
+)
+
+// newSite serves robots.txt and a few pages, counting page requests.
+func newSite(t *testing.T, robots string, robotsStatus int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(robotsStatus)
+		w.Write([]byte(robots))
+	})
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if ua := r.Header.Get("User-Agent"); !strings.HasPrefix(ua, "NuimanBot/") && ua != "TestBot/2.0" {
+			t.Errorf("Unexpected User-Agent %q", ua)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(articlePage))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/article", http.StatusFound)
+	})
+	mux.HandleFunc("/big", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(strings.Repeat("x", 2048)))
+	})
+	mux.HandleFunc("/streamed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		for i := 0; i < 4; i++ {
+			w.Write([]byte(strings.Repeat("y", 512)))
+			w.(http.Flusher).Flush()
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestService_Fetch(t *testing.T) {
+	server, _ := newSite(t, "", http.StatusNotFound)
+	svc := fetch.NewService(fetch.Options{AllowPrivateNetworks: true})
+
+	doc, err := svc.Fetch(context.Background(), fetch.Request{URL: server.URL + "/moved"})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if doc.URL != server.URL+"/article" {
+		t.Errorf("Expected final URL after redirect, got %s", doc.URL)
+	}
+	if doc.Title != "Go 1.24 Released" || !strings.Contains(doc.Text, "generic type aliases") {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+	if doc.FetchedAt.IsZero() || doc.Bytes != int64(len(articlePage)) {
+		t.Errorf("Expected fetch time and size to be set, got %v and %d", doc.FetchedAt, doc.Bytes)
+	}
+
+	if _, err := svc.Fetch(context.Background(), fetch.Request{URL: server.URL + "/missing"}); err == nil {
+		t.Error("Expected error for a 404 page")
+	}
+	if _, err := svc.Fetch(context.Background(), fetch.Request{URL: "ftp://example.com/file"}); err == nil {
+		t.Error("Expected error for a non-HTTP URL")
+	}
+}
+
+func TestService_Fetch_RequestUserAgent(t *testing.T) {
+	server, _ := newSite(t, "User-agent: TestBot\nDisallow: /article\n", http.StatusOK)
+	svc := fetch.NewService(fetch.Options{AllowPrivateNetworks: true})
+
+	if _, err := svc.Fetch(context.Background(), fetch.Request{URL: server.URL + "/article"}); err != nil {
+		t.Fatalf("Fetch() with the default user agent error = %v", err)
+	}
+
+	// Per-request user agents select their own robots.txt group
+	_, err := fetch.NewService(fetch.Options{AllowPrivateNetworks: true}).Fetch(context.Background(),
+		fetch.Request{URL: server.URL + "/article", UserAgent: "TestBot/2.0"})
+	if !errors.Is(err, fetch.ErrDisallowed) {
+		t.Errorf("Expected ErrDisallowed for TestBot, got %v", err)
+	}
+}
+
+func TestService_Fetch_Robots(t *testing.T) {
+	server, hits := newSite(t, "User-agent: *\nDisallow: /article\n", http.StatusOK)
+
+	_, err := fetch.NewService(fetch.Options{AllowPrivateNetworks: true}).
+		Fetch(context.Background(), fetch.Request{URL: server.URL + "/article"})
+	if !errors.Is(err, fetch.ErrDisallowed) {
+		t.Errorf("Expected ErrDisallowed, got %v", err)
+	}
+	if hits.Load() != 0 {
+		t.Error("Expected the disallowed page not to be requested")
+	}
+
+	_, err = fetch.NewService(fetch.Options{AllowPrivateNetworks: true, IgnoreRobots: true}).
+		Fetch(context.Background(), fetch.Request{URL: server.URL + "/article"})
+	if err != nil {
+		t.Errorf("Expected IgnoreRobots to bypass robots.txt, got %v", err)
+	}
+}
+
+func TestService_Fetch_RobotsUnavailable(t *testing.T) {
+	server, _ := newSite(t, "", http.StatusServiceUnavailable)
+	svc := fetch.NewService(fetch.Options{AllowPrivateNetworks: true})
+
+	_, err := svc.Fetch(context.Background(), fetch.Request{URL: server.URL + "/article"})
+	if !errors.Is(err, fetch.ErrDisallowed) {
+		t.Errorf("Expected ErrDisallowed while robots.txt is unavailable, got %v", err)
+	}
+}
+
+func TestService_Fetch_MaxBytes(t *testing.T) {
+	server, _ := newSite(t, "", http.StatusNotFound)
+	svc := fetch.NewService(fetch.Options{AllowPrivateNetworks: true, MaxBytes: 1024})
+
+	for _, path := range []string{"/big", "/streamed"} {
+		_, err := svc.Fetch(context.Background(), fetch.Request{URL: server.URL + path})
+		if !errors.Is(err, fetch.ErrTooLarge) {
+			t.Errorf("Fetch(%s) expected ErrTooLarge, got %v", path, err)
+		}
+	}
+
+	doc, err := svc.Fetch(context.Background(), fetch.Request{URL: server.URL + "/big", MaxBytes: 4096})
+	if err != nil {
+		t.Fatalf("Fetch() with a larger per-request limit error = %v", err)
+	}
+	if len(doc.Text) != 2048 {
+		t.Errorf("Expected 2048 characters, got %d", len(doc.Text))
+	}
+
+	// A cached document still honours smaller limits
+	_, err = svc.Fetch(context.Background(), fetch.Request{URL: server.URL + "/big"})
+	if !errors.Is(err, fetch.ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge for the cached document, got %v", err)
+	}
+}
+
+func TestService_Fetch_Cache(t *testing.T) {
+	server, hits := newSite(t, "", http.StatusNotFound)
+
+	svc := fetch.NewService(fetch.Options{AllowPrivateNetworks: true})
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Fetch(context.Background(), fetch.Request{URL: server.URL + "/article#section"}); err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+	}
+	if hits.Load() != 1 {
+		t.Errorf("Expected 1 request with caching, got %d", hits.Load())
+	}
+
+	hits.Store(0)
+	uncached := fetch.NewService(fetch.Options{AllowPrivateNetworks: true, CacheTTL: -1})
+	for i := 0; i < 2; i++ {
+		if _, err := uncached.Fetch(context.Background(), fetch.Request{URL: server.URL + "/article"}); err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+	}
+	if hits.Load() != 2 {
+		t.Errorf("Expected 2 requests without caching, got %d", hits.Load())
+	}
+}
+
+func TestService_Fetch_PrivateNetworkBlocked(t *testing.T) {
+	server, hits := newSite(t, "", http.StatusNotFound)
+	svc := fetch.NewService(fetch.Options{})
+
+	_, err := svc.Fetch(context.Background(), fetch.Request{URL: server.URL + "/article"})
+	if !errors.Is(err, fetch.ErrPrivateNetwork) {
+		t.Errorf("Expected ErrPrivateNetwork for a loopback server, got %v", err)
+	}
+	if hits.Load() != 0 {
+		t.Error("Expected no request to reach the loopback server")
+	}
+}
@@ -0,0 +1,151 @@
+package jsonschema
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Coerce returns a copy of value with schema defaults filled in for missing
+// object properties and lossless conversions applied where a value's type does
+// not match its schema:
+//
+//   - numeric strings to numbers ("42" -> 42), integers only if whole
+//   - "true"/"false" strings to booleans
+//   - numbers and booleans to strings
+//   - a single scalar to a one-element array
+//
+// Optional properties sent as null are treated as absent. Values that cannot
+// be converted are left unchanged for Validate to report. Numbers are produced
+// as float64, as encoding/json would.
+func Coerce(schema map[string]any, value any) any {
+	if schema == nil {
+		return value
+	}
+
+	if types := schemaTypes(schema); len(types) > 0 && !matchesAnyType(types, value) {
+		for _, t := range types {
+			if converted, ok := convert(t, value); ok {
+				value = converted
+				break
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return coerceObject(schema, v)
+	case []any:
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return v
+		}
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = Coerce(items, item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func coerceObject(schema map[string]any, obj map[string]any) map[string]any {
+	props, _ := schema["properties"].(map[string]any)
+	required := RequiredFields(schema)
+
+	out := make(map[string]any, len(obj)+len(props))
+	for name, item := range obj {
+		propSchema, known := props[name].(map[string]any)
+		if !known {
+			out[name] = item
+			continue
+		}
+		if item == nil && !allowsNull(propSchema) && !containsString(required, name) {
+			continue // Filled from the default below, if any
+		}
+		out[name] = Coerce(propSchema, item)
+	}
+
+	for _, name := range sortedKeys(props) {
+		propSchema, ok := props[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, present := out[name]; present {
+			continue
+		}
+		if def, ok := propSchema["default"]; ok {
+			if n, ok := toFloat(def); ok {
+				def = n
+			}
+			out[name] = def
+		}
+	}
+	return out
+}
+
+// convert applies a lossless conversion of value to the target schema type.
+func convert(target string, value any) (any, bool) {
+	switch target {
+	case "number", "integer":
+		s, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, false
+		}
+		if target == "integer" && n != math.Trunc(n) {
+			return nil, false
+		}
+		return n, true
+	case "boolean":
+		s, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+		return nil, false
+	case "string":
+		if b, ok := value.(bool); ok {
+			return strconv.FormatBool(b), true
+		}
+		if n, ok := toFloat(value); ok {
+			return strconv.FormatFloat(n, 'f', -1, 64), true
+		}
+		return nil, false
+	case "array":
+		switch TypeOf(value) {
+		case "string", "boolean", "number", "integer":
+			return []any{value}, true
+		}
+		return nil, false
+	default:
+		return nil, false
+	}
+}
+
+func allowsNull(schema map[string]any) bool {
+	for _, t := range schemaTypes(schema) {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
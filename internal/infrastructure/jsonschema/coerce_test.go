@@ -0,0 +1,76 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoerce(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"count":   map[string]any{"type": "integer", "default": 10},
+			"ratio":   map[string]any{"type": "number"},
+			"enabled": map[string]any{"type": "boolean"},
+			"label":   map[string]any{"type": "string"},
+			"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"unit":    map[string]any{"type": "string", "default": "metric"},
+		},
+		"required": []string{"ratio"},
+	}
+
+	tests := []struct {
+		name  string
+		value map[string]any
+		want  map[string]any
+	}{
+		{
+			name:  "defaults for missing properties",
+			value: map[string]any{"ratio": 0.5},
+			want:  map[string]any{"ratio": 0.5, "count": float64(10), "unit": "metric"},
+		},
+		{
+			name:  "string scalars",
+			value: map[string]any{"ratio": " 2.5", "count": "3", "enabled": "TRUE"},
+			want:  map[string]any{"ratio": 2.5, "count": float64(3), "enabled": true, "unit": "metric"},
+		},
+		{
+			name:  "scalars to strings and arrays",
+			value: map[string]any{"ratio": 1, "label": float64(7), "tags": 42},
+			want:  map[string]any{"ratio": 1, "label": "7", "tags": []any{"42"}, "count": float64(10), "unit": "metric"},
+		},
+		{
+			name:  "null optional property takes default",
+			value: map[string]any{"ratio": 1, "unit": nil},
+			want:  map[string]any{"ratio": 1, "count": float64(10), "unit": "metric"},
+		},
+		{
+			name:  "unconvertible values left for validation",
+			value: map[string]any{"ratio": nil, "count": "3.5", "enabled": "yes"},
+			want:  map[string]any{"ratio": nil, "count": "3.5", "enabled": "yes", "unit": "metric"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Coerce(schema, tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Coerce() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoerce_DoesNotModifyInput(t *testing.T) {
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"n": map[string]any{"type": "number"}},
+	}
+	input := map[string]any{"n": "1"}
+
+	Coerce(schema, input)
+
+	if input["n"] != "1" {
+		t.Errorf("input was modified: %#v", input)
+	}
+}
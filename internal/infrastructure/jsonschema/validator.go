@@ -0,0 +1,356 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ValidationError describes a single schema violation.
+type ValidationError struct {
+	Path    string `json:"path"`    // JSON path of the offending value (e.g., "$.items[0].name")
+	Message string `json:"message"` // Human-readable description of the violation
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors is the list of violations found in a document.
+type ValidationErrors []ValidationError
+
+// Error implements the error interface.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "schema validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks value against a JSON schema expressed as a decoded map.
+// Value must use the types produced by encoding/json (map[string]any, []any,
+// float64, string, bool, nil); Go ints are accepted as numbers as well.
+//
+// The supported subset covers what tool and response schemas use in practice:
+// type, properties, required, additionalProperties, items, enum, const,
+// minimum/maximum, exclusiveMinimum/exclusiveMaximum, minLength/maxLength,
+// pattern, minItems/maxItems, anyOf and oneOf. Unknown keywords are ignored.
+// It returns nil when the value is valid.
+func Validate(schema map[string]any, value any) error {
+	var errs ValidationErrors
+	validate(schema, value, "$", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ValidateJSON parses data and validates it against schema.
+func ValidateJSON(schema map[string]any, data []byte) (any, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, ValidationErrors{{Path: "$", Message: "invalid JSON: " + err.Error()}}
+	}
+	if err := Validate(schema, value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+func validate(schema map[string]any, value any, path string, errs *ValidationErrors) {
+	if schema == nil {
+		return
+	}
+
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if types := schemaTypes(schema); len(types) > 0 && !matchesAnyType(types, value) {
+		fail("expected %s, got %s", strings.Join(types, " or "), TypeOf(value))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !containsValue(enum, value) {
+		fail("must be one of %s", formatEnum(enum))
+	} else if enum, ok := schema["enum"].([]string); ok && !containsValue(stringsToAny(enum), value) {
+		fail("must be one of %s", formatEnum(stringsToAny(enum)))
+	}
+
+	if c, ok := schema["const"]; ok && !equalValues(c, value) {
+		fail("must equal %v", c)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		validateObject(schema, v, path, errs)
+	case []any:
+		validateArray(schema, v, path, errs)
+	case string:
+		validateString(schema, v, fail)
+	default:
+		if n, ok := toFloat(value); ok {
+			validateNumber(schema, n, fail)
+		}
+	}
+
+	validateCombinators(schema, value, path, errs)
+}
+
+func validateObject(schema map[string]any, obj map[string]any, path string, errs *ValidationErrors) {
+	for _, name := range RequiredFields(schema) {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, ValidationError{Path: path + "." + name, Message: "is required"})
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	for _, name := range sortedKeys(obj) {
+		propSchema, known := props[name].(map[string]any)
+		if known {
+			validate(propSchema, obj[name], path+"."+name, errs)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				*errs = append(*errs, ValidationError{Path: path + "." + name, Message: "is not an allowed property"})
+			}
+		case map[string]any:
+			validate(extra, obj[name], path+"."+name, errs)
+		}
+	}
+}
+
+func validateArray(schema map[string]any, arr []any, path string, errs *ValidationErrors) {
+	if minItems, ok := toInt(schema["minItems"]); ok && len(arr) < minItems {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf("must have at least %d items", minItems)})
+	}
+	if maxItems, ok := toInt(schema["maxItems"]); ok && len(arr) > maxItems {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf("must have at most %d items", maxItems)})
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range arr {
+			validate(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func validateString(schema map[string]any, s string, fail func(string, ...any)) {
+	length := len([]rune(s))
+	if minLen, ok := toInt(schema["minLength"]); ok && length < minLen {
+		fail("must be at least %d characters", minLen)
+	}
+	if maxLen, ok := toInt(schema["maxLength"]); ok && length > maxLen {
+		fail("must be at most %d characters", maxLen)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err == nil && !re.MatchString(s) {
+			fail("must match pattern %q", pattern)
+		}
+	}
+}
+
+func validateNumber(schema map[string]any, n float64, fail func(string, ...any)) {
+	if minimum, ok := toFloat(schema["minimum"]); ok && n < minimum {
+		fail("must be >= %v", minimum)
+	}
+	if maximum, ok := toFloat(schema["maximum"]); ok && n > maximum {
+		fail("must be <= %v", maximum)
+	}
+	if minimum, ok := toFloat(schema["exclusiveMinimum"]); ok && n <= minimum {
+		fail("must be > %v", minimum)
+	}
+	if maximum, ok := toFloat(schema["exclusiveMaximum"]); ok && n >= maximum {
+		fail("must be < %v", maximum)
+	}
+}
+
+func validateCombinators(schema map[string]any, value any, path string, errs *ValidationErrors) {
+	if anyOf := subSchemas(schema["anyOf"]); len(anyOf) > 0 {
+		matched := 0
+		for _, sub := range anyOf {
+			if Validate(sub, value) == nil {
+				matched++
+				break
+			}
+		}
+		if matched == 0 {
+			*errs = append(*errs, ValidationError{Path: path, Message: "does not match any allowed schema"})
+		}
+	}
+	if oneOf := subSchemas(schema["oneOf"]); len(oneOf) > 0 {
+		matched := 0
+		for _, sub := range oneOf {
+			if Validate(sub, value) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf("must match exactly one schema, matched %d", matched)})
+		}
+	}
+}
+
+// RequiredFields returns the schema's required property names.
+// It accepts both []string (Go literals) and []any (decoded JSON/YAML).
+func RequiredFields(schema map[string]any) []string {
+	switch req := schema["required"].(type) {
+	case []string:
+		return req
+	case []any:
+		result := make([]string, 0, len(req))
+		for _, r := range req {
+			if s, ok := r.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// TypeOf returns the JSON schema type name of a decoded value.
+func TypeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		if n, ok := toFloat(v); ok {
+			if n == math.Trunc(n) {
+				return "integer"
+			}
+			return "number"
+		}
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// schemaTypes returns the allowed types from a schema's "type" keyword.
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	default:
+		return nil
+	}
+}
+
+func matchesAnyType(types []string, value any) bool {
+	actual := TypeOf(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func subSchemas(v any) []map[string]any {
+	list, ok := v.([]any)
+	if !ok {
+		if typed, ok := v.([]map[string]any); ok {
+			return typed
+		}
+		return nil
+	}
+	result := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if m, ok := item.(map[string]any); ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func containsValue(list []any, value any) bool {
+	for _, item := range list {
+		if equalValues(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalValues(a, b any) bool {
+	if af, ok := toFloat(a); ok {
+		bf, ok := toFloat(b)
+		return ok && af == bf
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b) && TypeOf(a) == TypeOf(b)
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, v := range enum {
+		parts[i] = fmt.Sprintf("%v", v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func stringsToAny(list []string) []any {
+	result := make([]any, len(list))
+	for i, s := range list {
+		result[i] = s
+	}
+	return result
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toInt(v any) (int, bool) {
+	f, ok := toFloat(v)
+	return int(f), ok
+}
@@ -0,0 +1,155 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var personSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"name": map[string]any{"type": "string", "minLength": 1},
+		"age":  map[string]any{"type": "integer", "minimum": 0, "maximum": 150},
+		"role": map[string]any{"type": "string", "enum": []any{"admin", "user"}},
+		"tags": map[string]any{
+			"type":     "array",
+			"items":    map[string]any{"type": "string"},
+			"maxItems": 2,
+		},
+	},
+	"required":             []string{"name", "age"},
+	"additionalProperties": false,
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		wantErrs []string // substrings expected in the error, nil means valid
+	}{
+		{
+			name:  "valid",
+			value: map[string]any{"name": "Ada", "age": float64(36), "role": "admin", "tags": []any{"math"}},
+		},
+		{
+			name:     "missing required",
+			value:    map[string]any{"name": "Ada"},
+			wantErrs: []string{"$.age: is required"},
+		},
+		{
+			name:     "wrong type",
+			value:    map[string]any{"name": "Ada", "age": "36"},
+			wantErrs: []string{"$.age: expected integer, got string"},
+		},
+		{
+			name:     "non-integer number",
+			value:    map[string]any{"name": "Ada", "age": 36.5},
+			wantErrs: []string{"expected integer, got number"},
+		},
+		{
+			name:     "out of range",
+			value:    map[string]any{"name": "Ada", "age": float64(200)},
+			wantErrs: []string{"$.age: must be <= 150"},
+		},
+		{
+			name:     "enum",
+			value:    map[string]any{"name": "Ada", "age": 1, "role": "root"},
+			wantErrs: []string{"$.role: must be one of [admin, user]"},
+		},
+		{
+			name:     "array items and size",
+			value:    map[string]any{"name": "Ada", "age": 1, "tags": []any{"a", 2, "c"}},
+			wantErrs: []string{"$.tags: must have at most 2 items", "$.tags[1]: expected string"},
+		},
+		{
+			name:     "additional property",
+			value:    map[string]any{"name": "Ada", "age": 1, "email": "x"},
+			wantErrs: []string{"$.email: is not an allowed property"},
+		},
+		{
+			name:     "min length",
+			value:    map[string]any{"name": "", "age": 1},
+			wantErrs: []string{"$.name: must be at least 1 characters"},
+		},
+		{
+			name:     "not an object",
+			value:    []any{},
+			wantErrs: []string{"$: expected object, got array"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(personSchema, tt.value)
+			if tt.wantErrs == nil {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() expected error, got nil")
+			}
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Expected ValidationErrors, got %T", err)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error containing %q, got %q", want, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_Combinators(t *testing.T) {
+	schema := map[string]any{
+		"anyOf": []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "number", "minimum": 10},
+		},
+	}
+
+	if err := Validate(schema, "ok"); err != nil {
+		t.Errorf("Expected string to match anyOf, got %v", err)
+	}
+	if err := Validate(schema, float64(20)); err != nil {
+		t.Errorf("Expected 20 to match anyOf, got %v", err)
+	}
+	if err := Validate(schema, float64(5)); err == nil {
+		t.Error("Expected 5 to fail anyOf")
+	}
+}
+
+func TestValidate_PatternAndNullableType(t *testing.T) {
+	schema := map[string]any{
+		"type":    []any{"string", "null"},
+		"pattern": "^[a-z]+$",
+	}
+
+	if err := Validate(schema, nil); err != nil {
+		t.Errorf("Expected null to be allowed, got %v", err)
+	}
+	if err := Validate(schema, "abc"); err != nil {
+		t.Errorf("Expected 'abc' to match, got %v", err)
+	}
+	if err := Validate(schema, "ABC"); err == nil {
+		t.Error("Expected 'ABC' to fail pattern")
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	value, err := ValidateJSON(personSchema, []byte(`{"name":"Ada","age":36}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON() unexpected error: %v", err)
+	}
+	if value.(map[string]any)["name"] != "Ada" {
+		t.Errorf("Expected decoded value, got %v", value)
+	}
+
+	if _, err := ValidateJSON(personSchema, []byte(`{"name":`)); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("Expected invalid JSON error, got %v", err)
+	}
+}
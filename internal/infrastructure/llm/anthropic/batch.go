@@ -0,0 +1,134 @@
+package anthropic
+
+import (
+	"context"
+	"fmt"
+
+	anthropicsdk "github.com/anthropics/anthropic-sdk-go"
+
+	"nuimanbot/internal/domain"
+)
+
+// Batch result types reported by the Message Batches API
+const (
+	batchResultSucceeded = "succeeded"
+	batchResultCanceled  = "canceled"
+	batchResultExpired   = "expired"
+)
+
+// SupportsBatch reports whether the Message Batches API can be used for provider.
+func (c *Client) SupportsBatch(provider domain.LLMProvider) bool {
+	return provider == domain.LLMProviderAnthropic
+}
+
+// SubmitBatch creates a Message Batch from the requests.
+func (c *Client) SubmitBatch(ctx context.Context, provider domain.LLMProvider, requests []domain.BatchRequest) (string, error) {
+	if provider != domain.LLMProviderAnthropic {
+		return "", fmt.Errorf("Anthropic client cannot handle provider: %s", provider)
+	}
+
+	batchRequests := make([]anthropicsdk.MessageBatchNewParamsRequest, len(requests))
+	for i, req := range requests {
+		batchRequests[i] = anthropicsdk.MessageBatchNewParamsRequest{
+			CustomID: req.CustomID,
+			Params:   batchParams(buildParams(req.Request)),
+		}
+	}
+
+	batch, err := c.client.Messages.Batches.New(ctx, anthropicsdk.MessageBatchNewParams{Requests: batchRequests})
+	if err != nil {
+		return "", fmt.Errorf("anthropic batch submission failed: %w", wrapAPIError(err))
+	}
+	return batch.ID, nil
+}
+
+// GetBatch returns the processing status of a Message Batch.
+func (c *Client) GetBatch(ctx context.Context, provider domain.LLMProvider, batchID string) (*domain.LLMBatchStatus, error) {
+	if provider != domain.LLMProviderAnthropic {
+		return nil, fmt.Errorf("Anthropic client cannot handle provider: %s", provider)
+	}
+
+	batch, err := c.client.Messages.Batches.Get(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic batch lookup failed: %w", wrapAPIError(err))
+	}
+
+	counts := batch.RequestCounts
+	return &domain.LLMBatchStatus{
+		Done:       batch.ProcessingStatus == anthropicsdk.MessageBatchProcessingStatusEnded,
+		Processing: int(counts.Processing),
+		Succeeded:  int(counts.Succeeded),
+		Failed:     int(counts.Errored + counts.Expired),
+		Cancelled:  int(counts.Canceled),
+	}, nil
+}
+
+// BatchResults streams the results of an ended Message Batch.
+func (c *Client) BatchResults(ctx context.Context, provider domain.LLMProvider, batchID string) ([]domain.BatchResult, error) {
+	if provider != domain.LLMProviderAnthropic {
+		return nil, fmt.Errorf("Anthropic client cannot handle provider: %s", provider)
+	}
+
+	stream := c.client.Messages.Batches.ResultsStreaming(ctx, batchID)
+	defer stream.Close()
+
+	var results []domain.BatchResult
+	for stream.Next() {
+		results = append(results, convertBatchResult(stream.Current()))
+	}
+	if err := stream.Err(); err != nil {
+		return nil, fmt.Errorf("anthropic batch results failed: %w", wrapAPIError(err))
+	}
+	return results, nil
+}
+
+// CancelBatch cancels a Message Batch. Requests already processed keep their results.
+func (c *Client) CancelBatch(ctx context.Context, provider domain.LLMProvider, batchID string) error {
+	if provider != domain.LLMProviderAnthropic {
+		return fmt.Errorf("Anthropic client cannot handle provider: %s", provider)
+	}
+
+	if _, err := c.client.Messages.Batches.Cancel(ctx, batchID); err != nil {
+		return fmt.Errorf("anthropic batch cancellation failed: %w", wrapAPIError(err))
+	}
+	return nil
+}
+
+// batchParams copies the fields buildParams sets into the batch request form.
+func batchParams(p anthropicsdk.MessageNewParams) anthropicsdk.MessageBatchNewParamsRequestParams {
+	return anthropicsdk.MessageBatchNewParamsRequestParams{
+		MaxTokens:     p.MaxTokens,
+		Messages:      p.Messages,
+		Model:         p.Model,
+		Temperature:   p.Temperature,
+		TopK:          p.TopK,
+		TopP:          p.TopP,
+		StopSequences: p.StopSequences,
+		System:        p.System,
+		Thinking:      p.Thinking,
+		ToolChoice:    p.ToolChoice,
+		Tools:         p.Tools,
+	}
+}
+
+// convertBatchResult converts a single Message Batch result.
+func convertBatchResult(r anthropicsdk.MessageBatchIndividualResponse) domain.BatchResult {
+	result := domain.BatchResult{CustomID: r.CustomID}
+	switch r.Result.Type {
+	case batchResultSucceeded:
+		result.Status = domain.BatchItemSucceeded
+		result.Response = convertResponse(&r.Result.Message)
+	case batchResultCanceled:
+		result.Status = domain.BatchItemCancelled
+	case batchResultExpired:
+		result.Status = domain.BatchItemFailed
+		result.Error = "request expired before it was processed"
+	default: // errored
+		result.Status = domain.BatchItemFailed
+		result.Error = r.Result.Error.Error.Message
+		if result.Error == "" {
+			result.Error = "request failed"
+		}
+	}
+	return result
+}
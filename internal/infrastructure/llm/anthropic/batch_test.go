@@ -0,0 +1,96 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nuimanbot/internal/config"
+	"nuimanbot/internal/domain"
+)
+
+// TestBatch_SubmitAndResults tests Message Batch submission, status and result conversion
+func TestBatch_SubmitAndResults(t *testing.T) {
+	var submitted struct {
+		Requests []struct {
+			CustomID string         `json:"custom_id"`
+			Params   map[string]any `json:"params"`
+		} `json:"requests"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/messages/batches":
+			if err := json.NewDecoder(r.Body).Decode(&submitted); err != nil {
+				t.Errorf("Failed to decode batch request: %v", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id": "msgbatch_1", "type": "message_batch", "processing_status": "in_progress",
+				"request_counts": {"processing": 2, "succeeded": 0, "errored": 0, "canceled": 0, "expired": 0}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/messages/batches/msgbatch_1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id": "msgbatch_1", "type": "message_batch", "processing_status": "ended",
+				"request_counts": {"processing": 0, "succeeded": 1, "errored": 1, "canceled": 0, "expired": 0}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/messages/batches/msgbatch_1/results":
+			w.Header().Set("Content-Type", "application/x-jsonl")
+			_, _ = w.Write([]byte(`{"custom_id": "a", "result": {"type": "succeeded", "message": {"id": "msg_1", "type": "message", "role": "assistant", "model": "claude-haiku-4-5", "content": [{"type": "text", "text": "Summary A"}], "stop_reason": "end_turn", "usage": {"input_tokens": 10, "output_tokens": 5}}}}
+{"custom_id": "b", "result": {"type": "errored", "error": {"type": "error", "error": {"type": "invalid_request_error", "message": "bad request"}}}}
+`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithBaseURL(&config.LLMProviderConfig{
+		Type:   domain.LLMProviderAnthropic,
+		APIKey: domain.NewSecureStringFromString("test-api-key"),
+	}, server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	if !client.SupportsBatch(domain.LLMProviderAnthropic) || client.SupportsBatch(domain.LLMProviderOpenAI) {
+		t.Error("Expected batch support for Anthropic only")
+	}
+
+	batchID, err := client.SubmitBatch(ctx, domain.LLMProviderAnthropic, []domain.BatchRequest{
+		{CustomID: "a", Request: &domain.LLMRequest{Model: "claude-haiku-4-5", MaxTokens: 256, Messages: []domain.Message{{Role: "user", Content: "one"}}}},
+		{CustomID: "b", Request: &domain.LLMRequest{Model: "claude-haiku-4-5", MaxTokens: 256, Messages: []domain.Message{{Role: "user", Content: "two"}}}},
+	})
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	if batchID != "msgbatch_1" {
+		t.Errorf("Batch ID = %q, want msgbatch_1", batchID)
+	}
+	if len(submitted.Requests) != 2 || submitted.Requests[0].CustomID != "a" || submitted.Requests[0].Params["model"] != "claude-haiku-4-5" {
+		t.Errorf("Unexpected submitted requests: %+v", submitted.Requests)
+	}
+
+	status, err := client.GetBatch(ctx, domain.LLMProviderAnthropic, batchID)
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	if !status.Done || status.Succeeded != 1 || status.Failed != 1 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	results, err := client.BatchResults(ctx, domain.LLMProviderAnthropic, batchID)
+	if err != nil {
+		t.Fatalf("BatchResults failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Got %d results, want 2", len(results))
+	}
+	if results[0].Status != domain.BatchItemSucceeded || results[0].Response.Content != "Summary A" || results[0].Response.FinishReason != domain.FinishReasonStop {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if results[1].Status != domain.BatchItemFailed || results[1].Error != "bad request" {
+		t.Errorf("Unexpected second result: %+v", results[1])
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"nuimanbot/internal/config"
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/metrics"
 )
 
 const (
@@ -18,7 +19,12 @@ const (
 	eventTypeMessageStop       = "message_stop"
 
 	// Delta types
-	deltaTypeText = "text_delta"
+	deltaTypeText      = "text_delta"
+	deltaTypeThinking  = "thinking_delta"
+	deltaTypeSignature = "signature_delta"
+
+	// minThinkingBudget is the smallest thinking budget Anthropic accepts
+	minThinkingBudget = 1024
 )
 
 // Client implements domain.LLMService for the Anthropic API.
@@ -73,40 +79,21 @@ func (c *Client) Complete(ctx context.Context, provider domain.LLMProvider, req
 	}
 
 	// Build message parameters
-	params := anthropicsdk.MessageNewParams{
-		Model:     anthropicsdk.Model(req.Model),
-		MaxTokens: int64(req.MaxTokens),
-		Messages:  convertMessages(req.Messages),
-	}
-
-	// Add temperature if non-zero
-	if req.Temperature > 0 {
-		params.Temperature = anthropicsdk.Float(req.Temperature)
-	}
-
-	// Add system prompt if provided
-	if req.SystemPrompt != "" {
-		params.System = []anthropicsdk.TextBlockParam{
-			{
-				Type: "text",
-				Text: req.SystemPrompt,
-			},
-		}
-	}
-
-	// Add tool definitions if provided
-	if len(req.Tools) > 0 {
-		params.Tools = convertTools(req.Tools)
-	}
+	params := buildParams(req)
 
 	// Make API call
 	response, err := c.client.Messages.New(ctx, params)
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
+		return nil, fmt.Errorf("anthropic API call failed: %w", wrapAPIError(err))
 	}
 
 	// Convert response to domain format
-	return convertResponse(response), nil
+	result := convertResponse(response)
+	if req.ResponseFormat.IsJSON() {
+		result.PromoteToolCall(req.ResponseFormat.SchemaName())
+	}
+	metrics.RecordLLMUsage(string(domain.LLMProviderAnthropic), req.Model, result.Usage)
+	return result, nil
 }
 
 // Stream performs a streaming completion to the Anthropic API.
@@ -116,31 +103,7 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 	}
 
 	// Build message parameters
-	params := anthropicsdk.MessageNewParams{
-		Model:     anthropicsdk.Model(req.Model),
-		MaxTokens: int64(req.MaxTokens),
-		Messages:  convertMessages(req.Messages),
-	}
-
-	// Add temperature if non-zero
-	if req.Temperature > 0 {
-		params.Temperature = anthropicsdk.Float(req.Temperature)
-	}
-
-	// Add system prompt if provided
-	if req.SystemPrompt != "" {
-		params.System = []anthropicsdk.TextBlockParam{
-			{
-				Type: "text",
-				Text: req.SystemPrompt,
-			},
-		}
-	}
-
-	// Add tool definitions if provided
-	if len(req.Tools) > 0 {
-		params.Tools = convertTools(req.Tools)
-	}
+	params := buildParams(req)
 
 	// Create streaming request
 	stream := c.client.Messages.NewStreaming(ctx, params)
@@ -156,12 +119,23 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 		for stream.Next() {
 			event := stream.Current()
 
-			// Handle text deltas
+			// Handle text and thinking deltas
 			if event.Type == eventTypeContentBlockDelta {
-				if event.Delta.Type == deltaTypeText {
+				switch event.Delta.Type {
+				case deltaTypeText:
 					out <- domain.StreamChunk{
 						Delta: event.Delta.Text,
 					}
+				case deltaTypeThinking:
+					out <- domain.StreamChunk{
+						Kind:  domain.StreamChunkThinking,
+						Delta: event.Delta.Thinking,
+					}
+				case deltaTypeSignature:
+					out <- domain.StreamChunk{
+						Kind:      domain.StreamChunkThinking,
+						Signature: event.Delta.Signature,
+					}
 				}
 			}
 
@@ -177,7 +151,7 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 		// Check for errors
 		if err := stream.Err(); err != nil {
 			out <- domain.StreamChunk{
-				Error: fmt.Errorf("streaming error: %w", err),
+				Error: fmt.Errorf("streaming error: %w", wrapAPIError(err)),
 			}
 		}
 	}()
@@ -185,6 +159,65 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 	return out, nil
 }
 
+// buildParams converts a domain.LLMRequest into Anthropic message parameters,
+// including prompt cache breakpoints requested by req.Cache.
+func buildParams(req *domain.LLMRequest) anthropicsdk.MessageNewParams {
+	params := anthropicsdk.MessageNewParams{
+		Model:     anthropicsdk.Model(req.Model),
+		MaxTokens: int64(req.MaxTokens),
+		Messages:  convertMessages(req.Messages),
+	}
+
+	// Enable extended thinking; Anthropic requires max_tokens to exceed the
+	// budget and does not allow temperature to be changed while thinking
+	if req.Reasoning.Enabled() {
+		budget := max(req.Reasoning.Budget(), minThinkingBudget)
+		params.Thinking = anthropicsdk.ThinkingConfigParamOfEnabled(int64(budget))
+		if params.MaxTokens <= int64(budget) {
+			params.MaxTokens += int64(budget)
+		}
+	} else {
+		// Sampling parameters can only be changed without thinking
+		if req.Temperature > 0 {
+			params.Temperature = anthropicsdk.Float(req.Temperature)
+		}
+		if req.TopP > 0 {
+			params.TopP = anthropicsdk.Float(req.TopP)
+		}
+		if req.TopK > 0 {
+			params.TopK = anthropicsdk.Int(int64(req.TopK))
+		}
+	}
+	if len(req.StopSequences) > 0 {
+		params.StopSequences = req.StopSequences
+	}
+
+	// Add system prompt if provided
+	if req.SystemPrompt != "" {
+		params.System = []anthropicsdk.TextBlockParam{
+			{
+				Type: "text",
+				Text: req.SystemPrompt,
+			},
+		}
+	}
+
+	// Add tool definitions if provided
+	if len(req.Tools) > 0 {
+		params.Tools = convertTools(req.Tools)
+		params.ToolChoice = convertToolChoice(req.ToolChoice, req.ParallelToolCalls, req.Reasoning.Enabled())
+	}
+
+	// Emulate structured output with a forced tool call
+	if req.ResponseFormat.IsJSON() {
+		applyStructuredOutput(&params, req.ResponseFormat, req.Reasoning.Enabled())
+	}
+
+	applyCacheControl(&params, req.Cache)
+
+	return params
+}
+
 // ListModels returns available models for Anthropic.
 func (c *Client) ListModels(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
 	if provider != domain.LLMProviderAnthropic {
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"nuimanbot/internal/config"
 	"nuimanbot/internal/domain"
@@ -90,8 +92,8 @@ func TestComplete_Success(t *testing.T) {
 	if response.Usage.CompletionTokens != 20 {
 		t.Errorf("Expected 20 completion tokens, got %d", response.Usage.CompletionTokens)
 	}
-	if response.FinishReason != "end_turn" {
-		t.Errorf("Expected finish reason 'end_turn', got %s", response.FinishReason)
+	if response.FinishReason != domain.FinishReasonStop {
+		t.Errorf("Expected finish reason 'stop', got %s", response.FinishReason)
 	}
 }
 
@@ -264,8 +266,8 @@ func TestComplete_WithTools(t *testing.T) {
 	if response.ToolCalls[0].ToolName != "calculator" {
 		t.Errorf("Expected tool name 'calculator', got %s", response.ToolCalls[0].ToolName)
 	}
-	if response.FinishReason != "tool_use" {
-		t.Errorf("Expected finish reason 'tool_use', got %s", response.FinishReason)
+	if response.FinishReason != domain.FinishReasonToolCalls {
+		t.Errorf("Expected finish reason 'tool_calls', got %s", response.FinishReason)
 	}
 }
 
@@ -865,3 +867,376 @@ func TestConvertInputSchema_WithComplexRequired(t *testing.T) {
 		t.Errorf("convertInputSchema() required length = %d, want 2", len(result.Required))
 	}
 }
+
+// TestComplete_PromptCaching tests cache_control breakpoints and cache usage reporting
+func TestComplete_PromptCaching(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			System   []map[string]any `json:"system"`
+			Tools    []map[string]any `json:"tools"`
+			Messages []struct {
+				Content []map[string]any `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+
+		if _, ok := body.System[0]["cache_control"]; !ok {
+			t.Error("Expected cache_control on system prompt")
+		}
+		if _, ok := body.Tools[len(body.Tools)-1]["cache_control"]; !ok {
+			t.Error("Expected cache_control on last tool")
+		}
+		if _, ok := body.Messages[1].Content[0]["cache_control"]; !ok {
+			t.Error("Expected cache_control on last cached history message")
+		}
+		if _, ok := body.Messages[2].Content[0]["cache_control"]; ok {
+			t.Error("Expected no cache_control on the new user message")
+		}
+
+		response := map[string]interface{}{
+			"id":          "msg_123",
+			"type":        "message",
+			"role":        "assistant",
+			"model":       "claude-3-5-sonnet-20241022",
+			"content":     []map[string]interface{}{{"type": "text", "text": "ok"}},
+			"stop_reason": "end_turn",
+			"usage": map[string]interface{}{
+				"input_tokens":                5,
+				"output_tokens":               10,
+				"cache_creation_input_tokens": 100,
+				"cache_read_input_tokens":     2000,
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	cfg := &config.LLMProviderConfig{
+		Type:   domain.LLMProviderAnthropic,
+		APIKey: domain.NewSecureStringFromString("test-api-key"),
+	}
+	client, err := NewClientWithBaseURL(cfg, server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := &domain.LLMRequest{
+		Model:        "claude-3-5-sonnet-20241022",
+		MaxTokens:    1024,
+		SystemPrompt: "You are helpful",
+		Messages: []domain.Message{
+			{Role: "user", Content: "Hello"},
+			{Role: "assistant", Content: "Hi there"},
+			{Role: "user", Content: "What is 2+2?"},
+		},
+		Tools: []domain.ToolDefinition{
+			{Name: "calculator", InputSchema: map[string]any{"type": "object"}},
+			{Name: "datetime", InputSchema: map[string]any{"type": "object"}},
+		},
+		Cache: domain.CachePolicy{Tools: true, SystemPrompt: true, HistoryMessages: 2},
+	}
+
+	response, err := client.Complete(context.Background(), domain.LLMProviderAnthropic, req)
+	if err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+
+	if response.Usage.PromptTokens != 2105 {
+		t.Errorf("Expected 2105 prompt tokens, got %d", response.Usage.PromptTokens)
+	}
+	if response.Usage.CacheCreationTokens != 100 {
+		t.Errorf("Expected 100 cache creation tokens, got %d", response.Usage.CacheCreationTokens)
+	}
+	if response.Usage.CacheReadTokens != 2000 {
+		t.Errorf("Expected 2000 cache read tokens, got %d", response.Usage.CacheReadTokens)
+	}
+	if response.Usage.UncachedPromptTokens() != 5 {
+		t.Errorf("Expected 5 uncached prompt tokens, got %d", response.Usage.UncachedPromptTokens())
+	}
+	if response.Usage.TotalTokens != 2115 {
+		t.Errorf("Expected 2115 total tokens, got %d", response.Usage.TotalTokens)
+	}
+}
+
+// TestBuildParams_NoCachePolicy tests that no breakpoints are added by default
+func TestBuildParams_NoCachePolicy(t *testing.T) {
+	params := buildParams(&domain.LLMRequest{
+		Model:        "claude-3-5-sonnet-20241022",
+		MaxTokens:    100,
+		SystemPrompt: "You are helpful",
+		Messages:     []domain.Message{{Role: "user", Content: "Hello"}},
+	})
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("Failed to marshal params: %v", err)
+	}
+	if strings.Contains(string(data), "cache_control") {
+		t.Errorf("Expected no cache_control without a cache policy, got %s", data)
+	}
+}
+
+// TestComplete_ExtendedThinking tests thinking configuration and thinking block parsing
+func TestComplete_ExtendedThinking(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+
+		thinking, ok := body["thinking"].(map[string]any)
+		if !ok || thinking["type"] != "enabled" || thinking["budget_tokens"] != float64(2048) {
+			t.Errorf("Expected enabled thinking with 2048 budget, got %v", body["thinking"])
+		}
+		if body["max_tokens"] != float64(3072) {
+			t.Errorf("Expected max_tokens raised above the budget to 3072, got %v", body["max_tokens"])
+		}
+		if _, ok := body["temperature"]; ok {
+			t.Error("Expected temperature to be omitted while thinking")
+		}
+
+		// The previous assistant turn must replay its signed thinking block first
+		messages := body["messages"].([]any)
+		assistant := messages[1].(map[string]any)["content"].([]any)
+		first := assistant[0].(map[string]any)
+		if first["type"] != "thinking" || first["signature"] != "sig-prev" {
+			t.Errorf("Expected replayed thinking block, got %v", first)
+		}
+
+		response := map[string]interface{}{
+			"id":    "msg_123",
+			"type":  "message",
+			"role":  "assistant",
+			"model": "claude-3-7-sonnet-20250219",
+			"content": []map[string]interface{}{
+				{"type": "thinking", "thinking": "Let me work it out.", "signature": "sig-new"},
+				{"type": "redacted_thinking", "data": "opaque"},
+				{"type": "text", "text": "4"},
+			},
+			"stop_reason": "end_turn",
+			"usage":       map[string]interface{}{"input_tokens": 10, "output_tokens": 20},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	cfg := &config.LLMProviderConfig{
+		Type:   domain.LLMProviderAnthropic,
+		APIKey: domain.NewSecureStringFromString("test-api-key"),
+	}
+	client, err := NewClientWithBaseURL(cfg, server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	response, err := client.Complete(context.Background(), domain.LLMProviderAnthropic, &domain.LLMRequest{
+		Model:       "claude-3-7-sonnet-20250219",
+		MaxTokens:   1024,
+		Temperature: 0.7,
+		Reasoning:   domain.ReasoningConfig{BudgetTokens: 2048},
+		Messages: []domain.Message{
+			{Role: "user", Content: "Hi"},
+			{Role: "assistant", Content: "Hello", Reasoning: []domain.ReasoningBlock{{Text: "Greet back.", Signature: "sig-prev"}}},
+			{Role: "user", Content: "What is 2+2?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+
+	if response.Content != "4" {
+		t.Errorf("Expected content '4', got %q", response.Content)
+	}
+	if len(response.Reasoning) != 2 {
+		t.Fatalf("Expected 2 reasoning blocks, got %d", len(response.Reasoning))
+	}
+	if response.Reasoning[0].Signature != "sig-new" || response.Reasoning[0].Text != "Let me work it out." {
+		t.Errorf("Unexpected thinking block: %+v", response.Reasoning[0])
+	}
+	if string(response.Reasoning[1].RedactedData) != "opaque" {
+		t.Errorf("Expected redacted block data 'opaque', got %q", response.Reasoning[1].RedactedData)
+	}
+	if response.ReasoningText() != "Let me work it out." {
+		t.Errorf("Expected readable reasoning text, got %q", response.ReasoningText())
+	}
+}
+
+// TestComplete_StructuredOutput tests forced tool emulation of JSON schema output
+func TestComplete_StructuredOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+
+		choice, ok := body["tool_choice"].(map[string]any)
+		if !ok || choice["type"] != "tool" || choice["name"] != "person" {
+			t.Errorf("Expected forced tool_choice for 'person', got %v", body["tool_choice"])
+		}
+		tools, _ := body["tools"].([]any)
+		if len(tools) != 1 || tools[0].(map[string]any)["name"] != "person" {
+			t.Errorf("Expected the schema tool to be offered, got %v", body["tools"])
+		}
+
+		response := map[string]interface{}{
+			"id":    "msg_123",
+			"type":  "message",
+			"role":  "assistant",
+			"model": "claude-3-5-sonnet-20241022",
+			"content": []map[string]interface{}{
+				{"type": "tool_use", "id": "toolu_1", "name": "person", "input": map[string]any{"name": "Ada"}},
+			},
+			"stop_reason": "tool_use",
+			"usage":       map[string]interface{}{"input_tokens": 10, "output_tokens": 5},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	cfg := &config.LLMProviderConfig{
+		Type:   domain.LLMProviderAnthropic,
+		APIKey: domain.NewSecureStringFromString("test-api-key"),
+	}
+	client, err := NewClientWithBaseURL(cfg, server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	response, err := client.Complete(context.Background(), domain.LLMProviderAnthropic, &domain.LLMRequest{
+		Model:     "claude-3-5-sonnet-20241022",
+		MaxTokens: 1024,
+		Messages:  []domain.Message{{Role: "user", Content: "Who wrote the first program?"}},
+		ResponseFormat: &domain.ResponseFormat{
+			Type:   domain.ResponseFormatJSONSchema,
+			Name:   "person",
+			Schema: map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+
+	if response.Content != `{"name":"Ada"}` {
+		t.Errorf("Expected promoted JSON content, got %q", response.Content)
+	}
+	if len(response.ToolCalls) != 0 {
+		t.Errorf("Expected schema tool call to be consumed, got %d calls", len(response.ToolCalls))
+	}
+}
+
+// TestRetryAfter tests reading the rate limit reset time from response headers
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{
+			name:   "retry-after seconds",
+			header: http.Header{"Retry-After": {"7"}},
+			want:   7 * time.Second,
+		},
+		{
+			name: "latest reset header",
+			header: http.Header{
+				"Anthropic-Ratelimit-Requests-Reset": {"2025-01-01T12:00:05Z"},
+				"Anthropic-Ratelimit-Tokens-Reset":   {"2025-01-01T12:00:20Z"},
+			},
+			want: 20 * time.Second,
+		},
+		{
+			name:   "no headers",
+			header: http.Header{},
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.header, now); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBuildParams_ToolChoiceAndSampling tests tool choice and sampling parameter mapping
+func TestBuildParams_ToolChoiceAndSampling(t *testing.T) {
+	parallel := false
+	req := &domain.LLMRequest{
+		Model:             "claude-sonnet-4-5",
+		MaxTokens:         100,
+		Messages:          []domain.Message{{Role: "user", Content: "Hello"}},
+		Tools:             []domain.ToolDefinition{{Name: "calculator", InputSchema: map[string]any{"type": "object"}}},
+		ToolChoice:        domain.ForceTool("calculator"),
+		ParallelToolCalls: &parallel,
+		TopP:              0.9,
+		TopK:              40,
+		StopSequences:     []string{"END"},
+	}
+
+	data, err := json.Marshal(buildParams(req))
+	if err != nil {
+		t.Fatalf("Failed to marshal params: %v", err)
+	}
+	for _, want := range []string{
+		`"tool_choice":{"name":"calculator","disable_parallel_tool_use":true,"type":"tool"}`,
+		`"top_p":0.9`,
+		`"top_k":40`,
+		`"stop_sequences":["END"]`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in params, got %s", want, data)
+		}
+	}
+
+	// Thinking disallows forced tool use and custom sampling
+	req.Reasoning = domain.ReasoningConfig{BudgetTokens: 2048}
+	data, err = json.Marshal(buildParams(req))
+	if err != nil {
+		t.Fatalf("Failed to marshal params: %v", err)
+	}
+	if !strings.Contains(string(data), `"tool_choice":{"disable_parallel_tool_use":true,"type":"auto"}`) {
+		t.Errorf("Expected auto tool choice while thinking, got %s", data)
+	}
+	if strings.Contains(string(data), "top_k") || strings.Contains(string(data), "top_p") {
+		t.Errorf("Expected no sampling params while thinking, got %s", data)
+	}
+
+	// No tool choice or parallel setting leaves the provider default
+	data, _ = json.Marshal(buildParams(&domain.LLMRequest{Model: "claude-sonnet-4-5", MaxTokens: 100, Tools: req.Tools}))
+	if strings.Contains(string(data), "tool_choice") {
+		t.Errorf("Expected no tool_choice by default, got %s", data)
+	}
+}
+
+// TestConvertStopReason tests mapping Anthropic stop reasons to domain finish reasons
+func TestConvertStopReason(t *testing.T) {
+	tests := []struct {
+		input    anthropicsdk.StopReason
+		expected domain.FinishReason
+	}{
+		{anthropicsdk.StopReasonEndTurn, domain.FinishReasonStop},
+		{anthropicsdk.StopReasonStopSequence, domain.FinishReasonStop},
+		{anthropicsdk.StopReasonMaxTokens, domain.FinishReasonLength},
+		{anthropicsdk.StopReasonToolUse, domain.FinishReasonToolCalls},
+		{anthropicsdk.StopReasonRefusal, domain.FinishReasonContentFilter},
+		{anthropicsdk.StopReasonPauseTurn, domain.FinishReasonOther},
+	}
+
+	for _, tt := range tests {
+		if got := convertStopReason(tt.input); got != tt.expected {
+			t.Errorf("convertStopReason(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+	if !domain.FinishReasonLength.Truncated() || domain.FinishReasonStop.Truncated() {
+		t.Error("Expected only the length finish reason to report truncation")
+	}
+}
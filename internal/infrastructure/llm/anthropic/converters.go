@@ -5,14 +5,17 @@ import (
 	"fmt"
 
 	anthropicsdk "github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/packages/param"
 
 	"nuimanbot/internal/domain"
 )
 
 const (
 	// Content block types
-	contentTypeText    = "text"
-	contentTypeToolUse = "tool_use"
+	contentTypeText             = "text"
+	contentTypeToolUse          = "tool_use"
+	contentTypeThinking         = "thinking"
+	contentTypeRedactedThinking = "redacted_thinking"
 
 	// Message roles
 	roleSystem    = "system"
@@ -33,13 +36,19 @@ func convertMessages(messages []domain.Message) []anthropicsdk.MessageParam {
 			continue
 		}
 
+		// Thinking blocks must precede the text they led to, unmodified
+		content := make([]anthropicsdk.ContentBlockParamUnion, 0, len(msg.Reasoning)+1)
+		if msg.Role == roleAssistant {
+			content = append(content, convertReasoningBlocks(msg.Reasoning)...)
+		}
+
 		// Create text content block
-		contentBlock := anthropicsdk.NewTextBlock(msg.Content)
+		content = append(content, anthropicsdk.NewTextBlock(msg.Content))
 
 		// Create message based on role
 		msgParam := anthropicsdk.MessageParam{
 			Role:    anthropicsdk.MessageParamRole(msg.Role),
-			Content: []anthropicsdk.ContentBlockParamUnion{contentBlock},
+			Content: content,
 		}
 
 		result = append(result, msgParam)
@@ -48,6 +57,21 @@ func convertMessages(messages []domain.Message) []anthropicsdk.MessageParam {
 	return result
 }
 
+// convertReasoningBlocks converts domain reasoning blocks to Anthropic thinking blocks.
+// Unsigned blocks are dropped because Anthropic rejects thinking it cannot verify.
+func convertReasoningBlocks(blocks []domain.ReasoningBlock) []anthropicsdk.ContentBlockParamUnion {
+	result := make([]anthropicsdk.ContentBlockParamUnion, 0, len(blocks))
+	for _, block := range blocks {
+		switch {
+		case len(block.RedactedData) > 0:
+			result = append(result, anthropicsdk.NewRedactedThinkingBlock(string(block.RedactedData)))
+		case block.Signature != "":
+			result = append(result, anthropicsdk.NewThinkingBlock(block.Signature, block.Text))
+		}
+	}
+	return result
+}
+
 // convertTools converts domain.ToolDefinition slice to Anthropic SDK format
 func convertTools(tools []domain.ToolDefinition) []anthropicsdk.ToolUnionParam {
 	result := make([]anthropicsdk.ToolUnionParam, 0, len(tools))
@@ -131,12 +155,8 @@ func convertResponse(response *anthropicsdk.Message) *domain.LLMResponse {
 	result := &domain.LLMResponse{
 		Content:      "",
 		ToolCalls:    []domain.ToolCall{},
-		FinishReason: string(response.StopReason),
-		Usage: domain.TokenUsage{
-			PromptTokens:     int(response.Usage.InputTokens),
-			CompletionTokens: int(response.Usage.OutputTokens),
-			TotalTokens:      int(response.Usage.InputTokens + response.Usage.OutputTokens),
-		},
+		FinishReason: convertStopReason(response.StopReason),
+		Usage:        convertUsage(response.Usage),
 	}
 
 	// Extract content and tool calls from response
@@ -148,12 +168,118 @@ func convertResponse(response *anthropicsdk.Message) *domain.LLMResponse {
 		case contentTypeToolUse:
 			toolCall := parseToolCall(content)
 			result.ToolCalls = append(result.ToolCalls, toolCall)
+
+		case contentTypeThinking:
+			result.Reasoning = append(result.Reasoning, domain.ReasoningBlock{
+				Text:      content.Thinking,
+				Signature: content.Signature,
+			})
+
+		case contentTypeRedactedThinking:
+			result.Reasoning = append(result.Reasoning, domain.ReasoningBlock{
+				RedactedData: []byte(content.Data),
+			})
 		}
 	}
 
 	return result
 }
 
+// convertUsage converts Anthropic usage to domain.TokenUsage.
+// Anthropic reports uncached input tokens separately from cache writes and
+// reads, so all three are summed into PromptTokens.
+func convertUsage(usage anthropicsdk.Usage) domain.TokenUsage {
+	prompt := usage.InputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens
+	return domain.TokenUsage{
+		PromptTokens:        int(prompt),
+		CompletionTokens:    int(usage.OutputTokens),
+		TotalTokens:         int(prompt + usage.OutputTokens),
+		CacheCreationTokens: int(usage.CacheCreationInputTokens),
+		CacheReadTokens:     int(usage.CacheReadInputTokens),
+	}
+}
+
+// convertToolChoice converts the domain tool choice and parallel tool call
+// setting. Anthropic rejects forced tool use with extended thinking, so with
+// thinking enabled "required" and specific tools fall back to auto.
+func convertToolChoice(choice *domain.ToolChoice, parallel *bool, thinking bool) anthropicsdk.ToolChoiceUnionParam {
+	var disableParallel param.Opt[bool]
+	if parallel != nil {
+		disableParallel = anthropicsdk.Bool(!*parallel)
+	}
+
+	mode := domain.ToolChoiceAuto
+	if choice != nil {
+		mode = choice.Mode
+	}
+	if thinking && (mode == domain.ToolChoiceRequired || mode == domain.ToolChoiceTool) {
+		mode = domain.ToolChoiceAuto
+	}
+
+	switch mode {
+	case domain.ToolChoiceNone:
+		return anthropicsdk.ToolChoiceUnionParam{OfNone: &anthropicsdk.ToolChoiceNoneParam{}}
+	case domain.ToolChoiceRequired:
+		return anthropicsdk.ToolChoiceUnionParam{OfAny: &anthropicsdk.ToolChoiceAnyParam{DisableParallelToolUse: disableParallel}}
+	case domain.ToolChoiceTool:
+		return anthropicsdk.ToolChoiceUnionParam{OfTool: &anthropicsdk.ToolChoiceToolParam{Name: choice.Name, DisableParallelToolUse: disableParallel}}
+	default:
+		if choice == nil && parallel == nil {
+			return anthropicsdk.ToolChoiceUnionParam{} // Provider default
+		}
+		return anthropicsdk.ToolChoiceUnionParam{OfAuto: &anthropicsdk.ToolChoiceAutoParam{DisableParallelToolUse: disableParallel}}
+	}
+}
+
+// structuredOutputDescription is the description of the tool used to emulate structured output.
+const structuredOutputDescription = "Respond by calling this tool with a JSON document that matches its input schema."
+
+// applyStructuredOutput emulates JSON response formats by adding a tool whose
+// input schema is the requested schema and forcing the model to call it.
+// Anthropic does not allow forced tool use with extended thinking, so with
+// thinking enabled the tool is only offered and the system prompt asks for it.
+func applyStructuredOutput(params *anthropicsdk.MessageNewParams, format *domain.ResponseFormat, thinking bool) {
+	name := format.SchemaName()
+	params.Tools = append(params.Tools, convertTools([]domain.ToolDefinition{{
+		Name:        name,
+		Description: structuredOutputDescription,
+		InputSchema: format.JSONSchema(),
+	}})...)
+
+	if thinking {
+		params.System = append(params.System, anthropicsdk.TextBlockParam{
+			Text: fmt.Sprintf("Always answer by calling the %s tool.", name),
+		})
+		return
+	}
+	params.ToolChoice = anthropicsdk.ToolChoiceParamOfTool(name)
+}
+
+// applyCacheControl adds ephemeral cache_control breakpoints for the prefixes
+// marked in policy. Anthropic caches everything up to and including a
+// breakpoint, so each one is placed on the last block of its prefix.
+func applyCacheControl(params *anthropicsdk.MessageNewParams, policy domain.CachePolicy) {
+	if policy.Tools && len(params.Tools) > 0 {
+		if last := params.Tools[len(params.Tools)-1].OfTool; last != nil {
+			last.CacheControl = anthropicsdk.NewCacheControlEphemeralParam()
+		}
+	}
+
+	if policy.SystemPrompt && len(params.System) > 0 {
+		params.System[len(params.System)-1].CacheControl = anthropicsdk.NewCacheControlEphemeralParam()
+	}
+
+	if policy.HistoryMessages > 0 && len(params.Messages) > 0 {
+		idx := min(policy.HistoryMessages, len(params.Messages)) - 1
+		content := params.Messages[idx].Content
+		if len(content) > 0 {
+			if cc := content[len(content)-1].GetCacheControl(); cc != nil {
+				*cc = anthropicsdk.NewCacheControlEphemeralParam()
+			}
+		}
+	}
+}
+
 // parseToolCall extracts a tool call from a content block
 func parseToolCall(content anthropicsdk.ContentBlockUnion) domain.ToolCall {
 	toolCall := domain.ToolCall{
@@ -198,3 +324,21 @@ func convertToolResults(toolResults []domain.ToolResult, toolCallID string) anth
 		Content: contentBlocks,
 	}
 }
+
+// convertStopReason maps Anthropic stop reasons onto domain finish reasons.
+func convertStopReason(reason anthropicsdk.StopReason) domain.FinishReason {
+	switch reason {
+	case anthropicsdk.StopReasonEndTurn, anthropicsdk.StopReasonStopSequence:
+		return domain.FinishReasonStop
+	case anthropicsdk.StopReasonMaxTokens:
+		return domain.FinishReasonLength
+	case anthropicsdk.StopReasonToolUse:
+		return domain.FinishReasonToolCalls
+	case anthropicsdk.StopReasonRefusal:
+		return domain.FinishReasonContentFilter
+	case "":
+		return ""
+	default:
+		return domain.FinishReasonOther
+	}
+}
@@ -0,0 +1,55 @@
+package anthropic
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	anthropicsdk "github.com/anthropics/anthropic-sdk-go"
+
+	"nuimanbot/internal/domain"
+)
+
+// Rate limit reset headers, in order of preference.
+var rateLimitResetHeaders = []string{
+	"anthropic-ratelimit-requests-reset",
+	"anthropic-ratelimit-tokens-reset",
+	"anthropic-ratelimit-input-tokens-reset",
+	"anthropic-ratelimit-output-tokens-reset",
+}
+
+// wrapAPIError converts 429 responses into domain.RateLimitError so that
+// callers such as key pools can back off; other errors are returned unchanged.
+func wrapAPIError(err error) error {
+	var apiErr *anthropicsdk.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		return err
+	}
+
+	rlErr := &domain.RateLimitError{Provider: domain.LLMProviderAnthropic, Cause: err}
+	if apiErr.Response != nil {
+		rlErr.RetryAfter = retryAfter(apiErr.Response.Header, time.Now())
+	}
+	return rlErr
+}
+
+// retryAfter reads the wait time from Retry-After (seconds) or the latest
+// RFC 3339 rate limit reset header.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	if secs, err := strconv.Atoi(header.Get("retry-after")); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+
+	var wait time.Duration
+	for _, name := range rateLimitResetHeaders {
+		reset, err := time.Parse(time.RFC3339, header.Get(name))
+		if err != nil {
+			continue
+		}
+		if d := reset.Sub(now); d > wait {
+			wait = d
+		}
+	}
+	return wait
+}
@@ -7,6 +7,7 @@ import (
 
 	"nuimanbot/internal/config"
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/metrics"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
@@ -69,6 +70,10 @@ func (c *Client) Complete(ctx context.Context, provider domain.LLMProvider, req
 
 	// Convert response to domain format
 	response := convertResponse(output)
+	if req.ResponseFormat.IsJSON() {
+		response.PromoteToolCall(req.ResponseFormat.SchemaName())
+	}
+	metrics.RecordLLMUsage(string(domain.LLMProviderBedrock), req.Model, response.Usage)
 	return response, nil
 }
 
@@ -195,6 +200,30 @@ func (c *Client) buildConverseInput(req *domain.LLMRequest) (*bedrockruntime.Con
 		temperature := float32(req.Temperature)
 		inferenceConfig.Temperature = &temperature
 	}
+	additionalFields := applyReasoning(req.Reasoning, modelID, inferenceConfig)
+	thinking := additionalFields != nil
+	additionalFields = applySampling(req, modelID, inferenceConfig, additionalFields)
+
+	// Convert tools if present
+	var tools []types.Tool
+	var toolChoice types.ToolChoice
+	if len(req.Tools) > 0 {
+		var keepTools bool
+		toolChoice, keepTools = convertToolChoice(req.ToolChoice, thinking)
+		if keepTools {
+			tools = convertTools(req.Tools)
+		}
+	}
+
+	// Emulate structured output with a forced tool call
+	if req.ResponseFormat.IsJSON() {
+		tools, toolChoice = applyStructuredOutput(tools, req.ResponseFormat, thinking)
+	}
+
+	// Add prompt cache points for models that support them
+	if req.Cache.Enabled() && supportsPromptCaching(modelID) {
+		messages, systemBlocks, tools = applyCachePoints(messages, systemBlocks, tools, req.Cache)
+	}
 
 	// Build input
 	input := &bedrockruntime.ConverseInput{
@@ -208,13 +237,16 @@ func (c *Client) buildConverseInput(req *domain.LLMRequest) (*bedrockruntime.Con
 		input.System = systemBlocks
 	}
 
+	// Add model-specific fields (extended thinking, top_k) if present
+	if additionalFields != nil {
+		input.AdditionalModelRequestFields = additionalFields
+	}
+
 	// Add tools if present
-	if len(req.Tools) > 0 {
-		tools := convertTools(req.Tools)
-		if len(tools) > 0 {
-			input.ToolConfig = &types.ToolConfiguration{
-				Tools: tools,
-			}
+	if len(tools) > 0 {
+		input.ToolConfig = &types.ToolConfiguration{
+			Tools:      tools,
+			ToolChoice: toolChoice,
 		}
 	}
 
@@ -239,6 +271,30 @@ func (c *Client) buildConverseStreamInput(req *domain.LLMRequest) (*bedrockrunti
 		temperature := float32(req.Temperature)
 		inferenceConfig.Temperature = &temperature
 	}
+	additionalFields := applyReasoning(req.Reasoning, modelID, inferenceConfig)
+	thinking := additionalFields != nil
+	additionalFields = applySampling(req, modelID, inferenceConfig, additionalFields)
+
+	// Convert tools if present
+	var tools []types.Tool
+	var toolChoice types.ToolChoice
+	if len(req.Tools) > 0 {
+		var keepTools bool
+		toolChoice, keepTools = convertToolChoice(req.ToolChoice, thinking)
+		if keepTools {
+			tools = convertTools(req.Tools)
+		}
+	}
+
+	// Emulate structured output with a forced tool call
+	if req.ResponseFormat.IsJSON() {
+		tools, toolChoice = applyStructuredOutput(tools, req.ResponseFormat, thinking)
+	}
+
+	// Add prompt cache points for models that support them
+	if req.Cache.Enabled() && supportsPromptCaching(modelID) {
+		messages, systemBlocks, tools = applyCachePoints(messages, systemBlocks, tools, req.Cache)
+	}
 
 	// Build input
 	input := &bedrockruntime.ConverseStreamInput{
@@ -252,13 +308,16 @@ func (c *Client) buildConverseStreamInput(req *domain.LLMRequest) (*bedrockrunti
 		input.System = systemBlocks
 	}
 
+	// Add model-specific fields (extended thinking, top_k) if present
+	if additionalFields != nil {
+		input.AdditionalModelRequestFields = additionalFields
+	}
+
 	// Add tools if present
-	if len(req.Tools) > 0 {
-		tools := convertTools(req.Tools)
-		if len(tools) > 0 {
-			input.ToolConfig = &types.ToolConfiguration{
-				Tools: tools,
-			}
+	if len(tools) > 0 {
+		input.ToolConfig = &types.ToolConfiguration{
+			Tools:      tools,
+			ToolChoice: toolChoice,
 		}
 	}
 
@@ -274,11 +333,16 @@ func (c *Client) processStreamEvents(stream *bedrockruntime.ConverseStreamEventS
 		case *types.ConverseStreamOutputMemberContentBlockDelta:
 			// Handle content delta
 			if delta := e.Value.Delta; delta != nil {
-				if textDelta, ok := delta.(*types.ContentBlockDeltaMemberText); ok {
+				switch d := delta.(type) {
+				case *types.ContentBlockDeltaMemberText:
 					chunkChan <- domain.StreamChunk{
-						Delta: textDelta.Value,
+						Delta: d.Value,
 						Done:  false,
 					}
+				case *types.ContentBlockDeltaMemberReasoningContent:
+					if chunk, ok := convertReasoningDelta(d.Value); ok {
+						chunkChan <- chunk
+					}
 				}
 			}
 
@@ -49,11 +49,6 @@ func convertMessages(messages []domain.Message, systemPrompt string) ([]types.Me
 			continue
 		}
 
-		// Create text content block
-		contentBlock := &types.ContentBlockMemberText{
-			Value: msg.Content,
-		}
-
 		// Determine role
 		var role types.ConversationRole
 		if msg.Role == "user" {
@@ -62,10 +57,21 @@ func convertMessages(messages []domain.Message, systemPrompt string) ([]types.Me
 			role = types.ConversationRoleAssistant
 		}
 
+		// Reasoning blocks must precede the text they led to, unmodified
+		content := make([]types.ContentBlock, 0, len(msg.Reasoning)+1)
+		if role == types.ConversationRoleAssistant {
+			content = append(content, convertReasoningBlocks(msg.Reasoning)...)
+		}
+
+		// Create text content block
+		content = append(content, &types.ContentBlockMemberText{
+			Value: msg.Content,
+		})
+
 		// Create message
 		bedrockMsg := types.Message{
 			Role:    role,
-			Content: []types.ContentBlock{contentBlock},
+			Content: content,
 		}
 
 		bedrockMessages = append(bedrockMessages, bedrockMsg)
@@ -74,6 +80,131 @@ func convertMessages(messages []domain.Message, systemPrompt string) ([]types.Me
 	return bedrockMessages, systemBlocks
 }
 
+// convertReasoningBlocks converts domain reasoning blocks to Bedrock reasoning content.
+// Unsigned blocks are dropped because Claude rejects reasoning it cannot verify.
+func convertReasoningBlocks(blocks []domain.ReasoningBlock) []types.ContentBlock {
+	result := make([]types.ContentBlock, 0, len(blocks))
+	for _, block := range blocks {
+		switch {
+		case len(block.RedactedData) > 0:
+			result = append(result, &types.ContentBlockMemberReasoningContent{
+				Value: &types.ReasoningContentBlockMemberRedactedContent{Value: block.RedactedData},
+			})
+		case block.Signature != "":
+			result = append(result, &types.ContentBlockMemberReasoningContent{
+				Value: &types.ReasoningContentBlockMemberReasoningText{
+					Value: types.ReasoningTextBlock{
+						Text:      aws.String(block.Text),
+						Signature: aws.String(block.Signature),
+					},
+				},
+			})
+		}
+	}
+	return result
+}
+
+// parseReasoningBlock converts Bedrock reasoning content to a domain reasoning block.
+func parseReasoningBlock(block types.ReasoningContentBlock) domain.ReasoningBlock {
+	switch b := block.(type) {
+	case *types.ReasoningContentBlockMemberReasoningText:
+		return domain.ReasoningBlock{
+			Text:      aws.ToString(b.Value.Text),
+			Signature: aws.ToString(b.Value.Signature),
+		}
+	case *types.ReasoningContentBlockMemberRedactedContent:
+		return domain.ReasoningBlock{RedactedData: b.Value}
+	default:
+		return domain.ReasoningBlock{}
+	}
+}
+
+// convertReasoningDelta converts a streamed reasoning delta to a thinking chunk.
+// Redacted deltas carry no readable text and are skipped.
+func convertReasoningDelta(delta types.ReasoningContentBlockDelta) (domain.StreamChunk, bool) {
+	switch d := delta.(type) {
+	case *types.ReasoningContentBlockDeltaMemberText:
+		return domain.StreamChunk{Kind: domain.StreamChunkThinking, Delta: d.Value}, true
+	case *types.ReasoningContentBlockDeltaMemberSignature:
+		return domain.StreamChunk{Kind: domain.StreamChunkThinking, Signature: d.Value}, true
+	default:
+		return domain.StreamChunk{}, false
+	}
+}
+
+// minThinkingBudget is the smallest thinking budget Claude accepts.
+const minThinkingBudget = 1024
+
+// applyReasoning returns the additional model request fields that enable
+// extended thinking for Claude models, adjusting inference settings to meet
+// Claude's constraints. It returns nil when reasoning is not requested or
+// not supported by the model.
+func applyReasoning(reasoning domain.ReasoningConfig, modelID string, cfg *types.InferenceConfiguration) document.Interface {
+	if !reasoning.Enabled() || !strings.Contains(modelID, "anthropic.claude") {
+		return nil
+	}
+
+	budget := max(reasoning.Budget(), minThinkingBudget)
+
+	// Thinking requires max tokens above the budget and the default temperature
+	if cfg.MaxTokens == nil || *cfg.MaxTokens <= int32(budget) {
+		maxTokens := int32(budget)
+		if cfg.MaxTokens != nil {
+			maxTokens += *cfg.MaxTokens
+		} else {
+			maxTokens += int32(budget)
+		}
+		cfg.MaxTokens = &maxTokens
+	}
+	cfg.Temperature = nil
+
+	return document.NewLazyDocument(map[string]any{
+		"thinking": map[string]any{
+			"type":          "enabled",
+			"budget_tokens": budget,
+		},
+	})
+}
+
+// applySampling sets top_p and stop sequences. Converse has no top_k parameter,
+// so for Claude models it is passed through the additional model request
+// fields (unless thinking is enabled, which does not allow changing it).
+func applySampling(req *domain.LLMRequest, modelID string, cfg *types.InferenceConfiguration, additional document.Interface) document.Interface {
+	if req.TopP > 0 && additional == nil {
+		topP := float32(req.TopP)
+		cfg.TopP = &topP
+	}
+	if len(req.StopSequences) > 0 {
+		cfg.StopSequences = req.StopSequences
+	}
+	if req.TopK > 0 && additional == nil && strings.Contains(modelID, "anthropic.claude") {
+		return document.NewLazyDocument(map[string]any{"top_k": req.TopK})
+	}
+	return additional
+}
+
+// convertToolChoice converts the domain tool choice to Bedrock format. Converse
+// has no "none" choice, so it is reported by returning keepTools false. Claude
+// does not allow forced tool use while thinking, so it falls back to auto.
+func convertToolChoice(choice *domain.ToolChoice, thinking bool) (toolChoice types.ToolChoice, keepTools bool) {
+	if choice == nil {
+		return nil, true
+	}
+	switch choice.Mode {
+	case domain.ToolChoiceNone:
+		return nil, false
+	case domain.ToolChoiceRequired:
+		if !thinking {
+			return &types.ToolChoiceMemberAny{}, true
+		}
+	case domain.ToolChoiceTool:
+		if !thinking {
+			return &types.ToolChoiceMemberTool{Value: types.SpecificToolChoice{Name: aws.String(choice.Name)}}, true
+		}
+	}
+	return &types.ToolChoiceMemberAuto{}, true
+}
+
 // convertTools converts domain tool definitions to Bedrock format.
 func convertTools(tools []domain.ToolDefinition) []types.Tool {
 	result := make([]types.Tool, 0, len(tools))
@@ -99,6 +230,24 @@ func convertTools(tools []domain.ToolDefinition) []types.Tool {
 	return result
 }
 
+// applyStructuredOutput emulates JSON response formats by adding a tool whose
+// input schema is the requested schema and returning a tool choice that forces
+// the model to call it. Claude does not allow forced tool use while thinking,
+// so in that case the tool is only offered.
+func applyStructuredOutput(tools []types.Tool, format *domain.ResponseFormat, thinking bool) ([]types.Tool, types.ToolChoice) {
+	name := format.SchemaName()
+	tools = append(tools, convertTools([]domain.ToolDefinition{{
+		Name:        name,
+		Description: "Respond by calling this tool with a JSON document that matches its input schema.",
+		InputSchema: format.JSONSchema(),
+	}})...)
+
+	if thinking {
+		return tools, nil
+	}
+	return tools, &types.ToolChoiceMemberTool{Value: types.SpecificToolChoice{Name: aws.String(name)}}
+}
+
 // convertResponse converts Bedrock ConverseOutput to domain.LLMResponse.
 func convertResponse(output *bedrockruntime.ConverseOutput) *domain.LLMResponse {
 	result := &domain.LLMResponse{
@@ -118,6 +267,9 @@ func convertResponse(output *bedrockruntime.ConverseOutput) *domain.LLMResponse
 			case *types.ContentBlockMemberToolUse:
 				toolCall := parseToolUseBlock(block.Value)
 				result.ToolCalls = append(result.ToolCalls, toolCall)
+
+			case *types.ContentBlockMemberReasoningContent:
+				result.Reasoning = append(result.Reasoning, parseReasoningBlock(block.Value))
 			}
 		}
 	}
@@ -134,6 +286,8 @@ func convertUsage(usage *types.TokenUsage) domain.TokenUsage {
 	inputTokens := 0
 	outputTokens := 0
 	totalTokens := 0
+	cacheWriteTokens := 0
+	cacheReadTokens := 0
 
 	if usage.InputTokens != nil {
 		inputTokens = int(*usage.InputTokens)
@@ -144,19 +298,73 @@ func convertUsage(usage *types.TokenUsage) domain.TokenUsage {
 	if usage.TotalTokens != nil {
 		totalTokens = int(*usage.TotalTokens)
 	}
+	if usage.CacheWriteInputTokens != nil {
+		cacheWriteTokens = int(*usage.CacheWriteInputTokens)
+	}
+	if usage.CacheReadInputTokens != nil {
+		cacheReadTokens = int(*usage.CacheReadInputTokens)
+	}
+
+	// Bedrock reports cached input separately from InputTokens
+	promptTokens := inputTokens + cacheWriteTokens + cacheReadTokens
+	if totalTokens < promptTokens+outputTokens {
+		totalTokens = promptTokens + outputTokens
+	}
 
 	return domain.TokenUsage{
-		PromptTokens:     inputTokens,
-		CompletionTokens: outputTokens,
-		TotalTokens:      totalTokens,
+		PromptTokens:        promptTokens,
+		CompletionTokens:    outputTokens,
+		TotalTokens:         totalTokens,
+		CacheCreationTokens: cacheWriteTokens,
+		CacheReadTokens:     cacheReadTokens,
+	}
+}
+
+// supportsPromptCaching reports whether a Bedrock model accepts cache points.
+// Only Anthropic Claude and Amazon Nova models support prompt caching.
+func supportsPromptCaching(modelID string) bool {
+	return strings.Contains(modelID, "anthropic.claude") || strings.Contains(modelID, "amazon.nova")
+}
+
+// applyCachePoints appends Bedrock cache point blocks after the prefixes
+// marked in policy. Bedrock caches everything before a cache point.
+func applyCachePoints(messages []types.Message, system []types.SystemContentBlock, tools []types.Tool, policy domain.CachePolicy) ([]types.Message, []types.SystemContentBlock, []types.Tool) {
+	cachePoint := types.CachePointBlock{Type: types.CachePointTypeDefault}
+
+	if policy.Tools && len(tools) > 0 {
+		tools = append(tools, &types.ToolMemberCachePoint{Value: cachePoint})
+	}
+
+	if policy.SystemPrompt && len(system) > 0 {
+		system = append(system, &types.SystemContentBlockMemberCachePoint{Value: cachePoint})
 	}
+
+	if policy.HistoryMessages > 0 && len(messages) > 0 {
+		idx := min(policy.HistoryMessages, len(messages)) - 1
+		messages[idx].Content = append(messages[idx].Content, &types.ContentBlockMemberCachePoint{Value: cachePoint})
+	}
+
+	return messages, system, tools
 }
 
-// normalizeStopReason converts Bedrock stop reason to a normalized string.
-func normalizeStopReason(reason types.StopReason) string {
-	// Convert enum to string and lowercase with underscores
-	s := string(reason)
-	return strings.ToLower(strings.ReplaceAll(s, " ", "_"))
+// normalizeStopReason maps Bedrock stop reasons onto domain finish reasons.
+func normalizeStopReason(reason types.StopReason) domain.FinishReason {
+	switch reason {
+	case types.StopReasonEndTurn, types.StopReasonStopSequence:
+		return domain.FinishReasonStop
+	case types.StopReasonMaxTokens:
+		return domain.FinishReasonLength
+	case types.StopReasonModelContextWindowExceeded:
+		return domain.FinishReasonContextWindow
+	case types.StopReasonToolUse:
+		return domain.FinishReasonToolCalls
+	case types.StopReasonGuardrailIntervened, types.StopReasonContentFiltered:
+		return domain.FinishReasonContentFilter
+	case "":
+		return ""
+	default:
+		return domain.FinishReasonOther
+	}
 }
 
 // parseToolUseBlock extracts a tool call from a Bedrock ToolUseBlock.
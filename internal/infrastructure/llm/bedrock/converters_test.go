@@ -5,6 +5,7 @@ import (
 
 	"nuimanbot/internal/domain"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
@@ -192,8 +193,8 @@ func TestConvertResponse(t *testing.T) {
 		t.Errorf("Expected content 'Hello! How can I help you?', got %q", result.Content)
 	}
 
-	if result.FinishReason != "end_turn" {
-		t.Errorf("Expected finish reason 'end_turn', got %q", result.FinishReason)
+	if result.FinishReason != domain.FinishReasonStop {
+		t.Errorf("Expected finish reason 'stop', got %q", result.FinishReason)
 	}
 
 	if result.Usage.PromptTokens != 10 {
@@ -270,3 +271,212 @@ func TestConvertResponse_WithToolCall(t *testing.T) {
 		t.Errorf("Expected location 'San Francisco', got %q", location)
 	}
 }
+
+// TestConvertUsage_WithCache tests cache token reporting
+func TestConvertUsage_WithCache(t *testing.T) {
+	inputTokens := int32(5)
+	outputTokens := int32(10)
+	cacheWrite := int32(100)
+	cacheRead := int32(2000)
+
+	usage := convertUsage(&types.TokenUsage{
+		InputTokens:           &inputTokens,
+		OutputTokens:          &outputTokens,
+		CacheWriteInputTokens: &cacheWrite,
+		CacheReadInputTokens:  &cacheRead,
+	})
+
+	if usage.PromptTokens != 2105 {
+		t.Errorf("Expected 2105 prompt tokens, got %d", usage.PromptTokens)
+	}
+	if usage.CacheCreationTokens != 100 {
+		t.Errorf("Expected 100 cache creation tokens, got %d", usage.CacheCreationTokens)
+	}
+	if usage.CacheReadTokens != 2000 {
+		t.Errorf("Expected 2000 cache read tokens, got %d", usage.CacheReadTokens)
+	}
+	if usage.TotalTokens != 2115 {
+		t.Errorf("Expected 2115 total tokens, got %d", usage.TotalTokens)
+	}
+}
+
+// TestApplyCachePoints tests cache point placement
+func TestApplyCachePoints(t *testing.T) {
+	messages, system := convertMessages([]domain.Message{
+		{Role: "user", Content: "Hello"},
+		{Role: "assistant", Content: "Hi"},
+		{Role: "user", Content: "Bye"},
+	}, "You are helpful")
+	tools := convertTools([]domain.ToolDefinition{{Name: "calculator"}})
+
+	messages, system, tools = applyCachePoints(messages, system, tools, domain.CachePolicy{
+		Tools:           true,
+		SystemPrompt:    true,
+		HistoryMessages: 2,
+	})
+
+	if _, ok := tools[len(tools)-1].(*types.ToolMemberCachePoint); !ok {
+		t.Error("Expected cache point after tools")
+	}
+	if _, ok := system[len(system)-1].(*types.SystemContentBlockMemberCachePoint); !ok {
+		t.Error("Expected cache point after system prompt")
+	}
+	if _, ok := messages[1].Content[len(messages[1].Content)-1].(*types.ContentBlockMemberCachePoint); !ok {
+		t.Error("Expected cache point after second message")
+	}
+	if len(messages[2].Content) != 1 {
+		t.Errorf("Expected latest message to be untouched, got %d blocks", len(messages[2].Content))
+	}
+}
+
+// TestSupportsPromptCaching tests model detection for cache points
+func TestSupportsPromptCaching(t *testing.T) {
+	if !supportsPromptCaching("us.anthropic.claude-3-5-sonnet-20241022-v2:0") {
+		t.Error("Expected Claude on Bedrock to support prompt caching")
+	}
+	if supportsPromptCaching("meta.llama3-70b-instruct-v1:0") {
+		t.Error("Expected Llama on Bedrock not to support prompt caching")
+	}
+}
+
+// TestApplyReasoning tests extended thinking fields for Claude models
+func TestApplyReasoning(t *testing.T) {
+	maxTokens := int32(1000)
+	temperature := float32(0.7)
+	cfg := &types.InferenceConfiguration{MaxTokens: &maxTokens, Temperature: &temperature}
+
+	fields := applyReasoning(domain.ReasoningConfig{Effort: domain.ReasoningEffortMedium}, "anthropic.claude-3-7-sonnet-20250219-v1:0", cfg)
+	if fields == nil {
+		t.Fatal("Expected additional model request fields for Claude")
+	}
+	if *cfg.MaxTokens != 1000+domain.ReasoningBudgetMedium {
+		t.Errorf("Expected max tokens raised above budget, got %d", *cfg.MaxTokens)
+	}
+	if cfg.Temperature != nil {
+		t.Error("Expected temperature cleared while thinking")
+	}
+
+	if applyReasoning(domain.ReasoningConfig{BudgetTokens: 2048}, "meta.llama3-70b-instruct-v1:0", &types.InferenceConfiguration{}) != nil {
+		t.Error("Expected no reasoning fields for non-Claude models")
+	}
+	if applyReasoning(domain.ReasoningConfig{}, "anthropic.claude-3-7-sonnet-20250219-v1:0", &types.InferenceConfiguration{}) != nil {
+		t.Error("Expected no reasoning fields when reasoning is disabled")
+	}
+}
+
+// TestConvertResponse_WithReasoning tests reasoning content parsing and replay
+func TestConvertResponse_WithReasoning(t *testing.T) {
+	output := &bedrockruntime.ConverseOutput{
+		Output: &types.ConverseOutputMemberMessage{
+			Value: types.Message{
+				Role: types.ConversationRoleAssistant,
+				Content: []types.ContentBlock{
+					&types.ContentBlockMemberReasoningContent{
+						Value: &types.ReasoningContentBlockMemberReasoningText{
+							Value: types.ReasoningTextBlock{Text: aws.String("Thinking..."), Signature: aws.String("sig")},
+						},
+					},
+					&types.ContentBlockMemberText{Value: "Done"},
+				},
+			},
+		},
+	}
+
+	result := convertResponse(output)
+	if len(result.Reasoning) != 1 || result.Reasoning[0].Text != "Thinking..." || result.Reasoning[0].Signature != "sig" {
+		t.Fatalf("Unexpected reasoning: %+v", result.Reasoning)
+	}
+
+	messages, _ := convertMessages([]domain.Message{
+		{Role: "assistant", Content: result.Content, Reasoning: result.Reasoning},
+	}, "")
+	if len(messages[0].Content) != 2 {
+		t.Fatalf("Expected reasoning and text blocks, got %d", len(messages[0].Content))
+	}
+	if _, ok := messages[0].Content[0].(*types.ContentBlockMemberReasoningContent); !ok {
+		t.Errorf("Expected reasoning block first, got %T", messages[0].Content[0])
+	}
+}
+
+// TestApplyStructuredOutput tests forced tool emulation of JSON output
+func TestApplyStructuredOutput(t *testing.T) {
+	format := &domain.ResponseFormat{Type: domain.ResponseFormatJSONObject}
+
+	tools, choice := applyStructuredOutput(nil, format, false)
+	if len(tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(tools))
+	}
+	forced, ok := choice.(*types.ToolChoiceMemberTool)
+	if !ok || *forced.Value.Name != domain.DefaultResponseFormatName {
+		t.Errorf("Expected tool choice forcing %q, got %#v", domain.DefaultResponseFormatName, choice)
+	}
+
+	if _, choice := applyStructuredOutput(nil, format, true); choice != nil {
+		t.Errorf("Expected no forced tool choice while thinking, got %#v", choice)
+	}
+}
+
+// TestConvertToolChoice tests tool choice mapping, including the "none" and thinking fallbacks
+func TestConvertToolChoice(t *testing.T) {
+	if choice, keep := convertToolChoice(nil, false); choice != nil || !keep {
+		t.Errorf("Expected default tool choice, got %#v, %v", choice, keep)
+	}
+	if _, keep := convertToolChoice(&domain.ToolChoice{Mode: domain.ToolChoiceNone}, false); keep {
+		t.Error("Expected tools to be dropped for tool_choice none")
+	}
+	if choice, _ := convertToolChoice(&domain.ToolChoice{Mode: domain.ToolChoiceRequired}, false); choice == nil {
+		t.Error("Expected any tool choice for required")
+	} else if _, ok := choice.(*types.ToolChoiceMemberAny); !ok {
+		t.Errorf("Expected any tool choice for required, got %#v", choice)
+	}
+	choice, _ := convertToolChoice(domain.ForceTool("calculator"), false)
+	if forced, ok := choice.(*types.ToolChoiceMemberTool); !ok || *forced.Value.Name != "calculator" {
+		t.Errorf("Expected forced calculator tool, got %#v", choice)
+	}
+	if choice, _ := convertToolChoice(domain.ForceTool("calculator"), true); choice == nil {
+		t.Error("Expected auto tool choice while thinking")
+	} else if _, ok := choice.(*types.ToolChoiceMemberAuto); !ok {
+		t.Errorf("Expected auto tool choice while thinking, got %#v", choice)
+	}
+}
+
+// TestApplySampling tests top_p, stop sequences and top_k pass-through
+func TestApplySampling(t *testing.T) {
+	req := &domain.LLMRequest{TopP: 0.9, TopK: 40, StopSequences: []string{"END"}}
+
+	cfg := &types.InferenceConfiguration{}
+	fields := applySampling(req, "anthropic.claude-3-5-sonnet-20241022-v2:0", cfg, nil)
+	if cfg.TopP == nil || *cfg.TopP != float32(0.9) || len(cfg.StopSequences) != 1 {
+		t.Errorf("Expected top_p and stop sequences, got %+v", cfg)
+	}
+	data, err := fields.MarshalSmithyDocument()
+	if err != nil || string(data) != `{"top_k":40}` {
+		t.Errorf("Expected top_k additional field, got %s, %v", data, err)
+	}
+
+	if applySampling(req, "meta.llama3-70b-instruct-v1:0", &types.InferenceConfiguration{}, nil) != nil {
+		t.Error("Expected no top_k field for non-Claude models")
+	}
+}
+
+// TestNormalizeStopReason tests mapping Bedrock stop reasons to domain finish reasons
+func TestNormalizeStopReason(t *testing.T) {
+	tests := []struct {
+		input    types.StopReason
+		expected domain.FinishReason
+	}{
+		{types.StopReasonEndTurn, domain.FinishReasonStop},
+		{types.StopReasonStopSequence, domain.FinishReasonStop},
+		{types.StopReasonMaxTokens, domain.FinishReasonLength},
+		{types.StopReasonModelContextWindowExceeded, domain.FinishReasonContextWindow},
+		{types.StopReasonToolUse, domain.FinishReasonToolCalls},
+		{types.StopReasonGuardrailIntervened, domain.FinishReasonContentFilter},
+		{types.StopReason("something_new"), domain.FinishReasonOther},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeStopReason(tt.input); got != tt.expected {
+			t.Errorf("normalizeStopReason(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Package inspector records sampled LLM request/response pairs so that odd
+// answers can be debugged after the fact. The Recorder wraps an LLMService,
+// captures each call as the provider saw it (system prompt, trimmed history,
+// tool schemas, raw response and finish reason), redacts secrets and keeps the
+// result in a bounded Store keyed by request ID.
+package inspector
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/requestid"
+)
+
+// Redactor removes secrets from text before it is stored.
+// common.OutputSanitizer satisfies it.
+type Redactor interface {
+	SanitizeOutput(output string) string
+}
+
+// Recorder implements domain.LLMService, recording sampled calls to a Store.
+type Recorder struct {
+	underlying domain.LLMService
+	store      *Store
+	redactor   Redactor
+	sampleRate float64
+
+	now func() time.Time // Overridable for tests
+}
+
+// NewRecorder wraps underlying. sampleRate is the fraction of requests to record
+// (values <= 0 or >= 1 record everything). The decision is made per request ID,
+// so every LLM call made while handling a sampled request is kept together.
+// redactor may be nil if no redaction is wanted.
+func NewRecorder(underlying domain.LLMService, store *Store, redactor Redactor, sampleRate float64) *Recorder {
+	return &Recorder{
+		underlying: underlying,
+		store:      store,
+		redactor:   redactor,
+		sampleRate: sampleRate,
+		now:        time.Now,
+	}
+}
+
+// Store returns the store traces are recorded to.
+func (r *Recorder) Store() *Store {
+	return r.store
+}
+
+// Complete forwards the request and records the exchange if it is sampled.
+func (r *Recorder) Complete(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+	reqID := requestid.FromContext(ctx)
+	if !r.sampled(reqID) {
+		return r.underlying.Complete(ctx, provider, req)
+	}
+
+	trace := r.newTrace(reqID, provider, req)
+	resp, err := r.underlying.Complete(ctx, provider, req)
+	if resp != nil {
+		trace.Response = r.traceResponse(resp)
+	}
+	r.finish(trace, err)
+
+	return resp, err
+}
+
+// Stream forwards the stream and records the assembled response once it ends.
+func (r *Recorder) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+	reqID := requestid.FromContext(ctx)
+	if !r.sampled(reqID) {
+		return r.underlying.Stream(ctx, provider, req)
+	}
+
+	trace := r.newTrace(reqID, provider, req)
+	trace.Streamed = true
+
+	stream, err := r.underlying.Stream(ctx, provider, req)
+	if err != nil {
+		r.finish(trace, err)
+		return nil, err
+	}
+
+	outChan := make(chan domain.StreamChunk, 10)
+	go func() {
+		defer close(outChan)
+
+		var content, reasoning strings.Builder
+		var resp domain.LLMResponse
+		var streamErr error
+		for chunk := range stream {
+			switch {
+			case chunk.Error != nil:
+				streamErr = chunk.Error
+			case chunk.ToolCall != nil:
+				resp.ToolCalls = append(resp.ToolCalls, *chunk.ToolCall)
+			case chunk.IsThinking():
+				reasoning.WriteString(chunk.Delta)
+			default:
+				content.WriteString(chunk.Delta)
+			}
+			outChan <- chunk
+		}
+
+		resp.Content = content.String()
+		if reasoning.Len() > 0 {
+			resp.Reasoning = []domain.ReasoningBlock{{Text: reasoning.String()}}
+		}
+		trace.Response = r.traceResponse(&resp)
+		r.finish(trace, streamErr)
+	}()
+
+	return outChan, nil
+}
+
+// ListModels is not recorded.
+func (r *Recorder) ListModels(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
+	return r.underlying.ListModels(ctx, provider)
+}
+
+// sampled reports whether calls for reqID should be recorded. Calls without a
+// request ID cannot be looked up later and are never recorded.
+func (r *Recorder) sampled(reqID string) bool {
+	if reqID == "" {
+		return false
+	}
+	if r.sampleRate <= 0 || r.sampleRate >= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(reqID))
+	return float64(h.Sum32()%10000) < r.sampleRate*10000
+}
+
+// newTrace captures the redacted request.
+func (r *Recorder) newTrace(reqID string, provider domain.LLMProvider, req *domain.LLMRequest) *domain.LLMTrace {
+	trace := &domain.LLMTrace{
+		RequestID: reqID,
+		Provider:  provider,
+		Model:     req.Model,
+		StartedAt: r.now(),
+		Request: domain.LLMTraceRequest{
+			SystemPrompt:  r.redact(req.SystemPrompt),
+			Messages:      make([]domain.Message, len(req.Messages)),
+			Tools:         req.Tools,
+			MaxTokens:     req.MaxTokens,
+			Temperature:   req.Temperature,
+			ToolChoice:    req.ToolChoice,
+			TopP:          req.TopP,
+			TopK:          req.TopK,
+			StopSequences: req.StopSequences,
+			Seed:          req.Seed,
+		},
+	}
+	if req.ResponseFormat != nil {
+		trace.Request.ResponseFormat = req.ResponseFormat.Type
+	}
+	for i, msg := range req.Messages {
+		trace.Request.Messages[i] = domain.Message{Role: msg.Role, Content: r.redact(msg.Content)}
+	}
+	return trace
+}
+
+// traceResponse captures the redacted response.
+func (r *Recorder) traceResponse(resp *domain.LLMResponse) domain.LLMTraceResponse {
+	out := domain.LLMTraceResponse{
+		Content:      r.redact(resp.Content),
+		FinishReason: resp.FinishReason,
+		Usage:        resp.Usage,
+	}
+	for _, block := range resp.Reasoning {
+		if block.Text != "" {
+			out.Reasoning = append(out.Reasoning, r.redact(block.Text))
+		}
+	}
+	for _, call := range resp.ToolCalls {
+		out.ToolCalls = append(out.ToolCalls, domain.ToolCall{
+			ToolName:  call.ToolName,
+			Arguments: r.redactMap(call.Arguments),
+		})
+	}
+	return out
+}
+
+// finish stamps the duration and error and stores the trace.
+func (r *Recorder) finish(trace *domain.LLMTrace, err error) {
+	trace.Duration = r.now().Sub(trace.StartedAt)
+	if err != nil {
+		trace.Error = r.redact(err.Error())
+	}
+	r.store.Add(*trace)
+}
+
+func (r *Recorder) redact(s string) string {
+	if r.redactor == nil || s == "" {
+		return s
+	}
+	return r.redactor.SanitizeOutput(s)
+}
+
+// redactMap redacts string values in tool arguments, recursively.
+func (r *Recorder) redactMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = r.redactValue(v)
+	}
+	return out
+}
+
+func (r *Recorder) redactValue(v any) any {
+	switch val := v.(type) {
+	case string:
+		return r.redact(val)
+	case map[string]any:
+		return r.redactMap(val)
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.redactValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,185 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/requestid"
+	"nuimanbot/internal/usecase/tool/common"
+)
+
+// fakeLLM returns a fixed response or error.
+type fakeLLM struct {
+	resp   *domain.LLMResponse
+	err    error
+	chunks []domain.StreamChunk
+}
+
+func (f *fakeLLM) Complete(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+	return f.resp, f.err
+}
+
+func (f *fakeLLM) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+	ch := make(chan domain.StreamChunk, len(f.chunks))
+	for _, c := range f.chunks {
+		ch <- c
+	}
+	close(ch)
+	return ch, nil
+}
+
+func (f *fakeLLM) ListModels(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
+	return nil, nil
+}
+
+const secretKey = "sk-abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwx"
+
+func TestRecorder_Complete(t *testing.T) {
+	llm := &fakeLLM{resp: &domain.LLMResponse{
+		Content:      "Your key is " + secretKey,
+		FinishReason: domain.FinishReasonStop,
+		ToolCalls:    []domain.ToolCall{{ToolName: "http", Arguments: map[string]any{"headers": map[string]any{"auth": secretKey}}}},
+		Usage:        domain.TokenUsage{TotalTokens: 42},
+	}}
+	store := NewStore(0, 0)
+	rec := NewRecorder(llm, store, common.NewOutputSanitizer(), 1)
+
+	ctx := requestid.WithRequestID(context.Background(), "req1")
+	_, err := rec.Complete(ctx, domain.LLMProviderAnthropic, &domain.LLMRequest{
+		Model:        "claude-sonnet-4-5",
+		SystemPrompt: "system",
+		Messages:     []domain.Message{{Role: "user", Content: "my key is " + secretKey}},
+		Tools:        []domain.ToolDefinition{{Name: "http"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+
+	traces := store.Traces("req1")
+	if len(traces) != 1 {
+		t.Fatalf("Expected 1 trace, got %d", len(traces))
+	}
+	trace := traces[0]
+	if trace.Model != "claude-sonnet-4-5" || trace.Request.SystemPrompt != "system" || len(trace.Request.Tools) != 1 {
+		t.Errorf("Request not captured: %+v", trace.Request)
+	}
+	if trace.Response.FinishReason != domain.FinishReasonStop || trace.Response.Usage.TotalTokens != 42 {
+		t.Errorf("Response not captured: %+v", trace.Response)
+	}
+	if got := trace.Request.Messages[0].Content; got != "my key is [REDACTED]" {
+		t.Errorf("Expected redacted message, got %q", got)
+	}
+	if got := trace.Response.Content; got != "Your key is [REDACTED]" {
+		t.Errorf("Expected redacted response, got %q", got)
+	}
+	if got := trace.Response.ToolCalls[0].Arguments["headers"].(map[string]any)["auth"]; got != "[REDACTED]" {
+		t.Errorf("Expected redacted tool argument, got %v", got)
+	}
+	// The caller still sees the unredacted response
+	if llm.resp.ToolCalls[0].Arguments["headers"].(map[string]any)["auth"] != secretKey {
+		t.Error("Recorder must not modify the response returned to the caller")
+	}
+}
+
+func TestRecorder_CompleteError(t *testing.T) {
+	store := NewStore(0, 0)
+	rec := NewRecorder(&fakeLLM{err: errors.New("boom")}, store, nil, 0)
+
+	ctx := requestid.WithRequestID(context.Background(), "req1")
+	if _, err := rec.Complete(ctx, domain.LLMProviderOpenAI, &domain.LLMRequest{}); err == nil {
+		t.Fatal("Expected error")
+	}
+	if traces := store.Traces("req1"); len(traces) != 1 || traces[0].Error != "boom" {
+		t.Errorf("Expected error trace, got %+v", traces)
+	}
+
+	// Calls without a request ID cannot be looked up and are skipped
+	if _, err := rec.Complete(context.Background(), domain.LLMProviderOpenAI, &domain.LLMRequest{}); err == nil {
+		t.Fatal("Expected error")
+	}
+	if ids := store.RequestIDs(); len(ids) != 1 {
+		t.Errorf("Expected only req1 to be stored, got %v", ids)
+	}
+}
+
+func TestRecorder_Stream(t *testing.T) {
+	llm := &fakeLLM{chunks: []domain.StreamChunk{
+		{Kind: domain.StreamChunkThinking, Delta: "thinking"},
+		{Delta: "Hel"},
+		{Delta: "lo"},
+		{ToolCall: &domain.ToolCall{ToolName: "calculator"}},
+		{Done: true},
+	}}
+	store := NewStore(0, 0)
+	rec := NewRecorder(llm, store, nil, 1)
+
+	ctx := requestid.WithRequestID(context.Background(), "req1")
+	stream, err := rec.Stream(ctx, domain.LLMProviderAnthropic, &domain.LLMRequest{})
+	if err != nil {
+		t.Fatalf("Stream() error: %v", err)
+	}
+	count := 0
+	for range stream {
+		count++
+	}
+	if count != len(llm.chunks) {
+		t.Errorf("Expected %d chunks forwarded, got %d", len(llm.chunks), count)
+	}
+
+	traces := store.Traces("req1")
+	if len(traces) != 1 {
+		t.Fatalf("Expected 1 trace, got %d", len(traces))
+	}
+	resp := traces[0].Response
+	if !traces[0].Streamed || resp.Content != "Hello" || len(resp.Reasoning) != 1 || len(resp.ToolCalls) != 1 {
+		t.Errorf("Stream not assembled: %+v", traces[0])
+	}
+}
+
+func TestRecorder_Sampling(t *testing.T) {
+	store := NewStore(10000, 0)
+	rec := NewRecorder(&fakeLLM{resp: &domain.LLMResponse{}}, store, nil, 0.25)
+
+	for i := 0; i < 2000; i++ {
+		ctx := requestid.WithRequestID(context.Background(), fmt.Sprintf("req-%d", i))
+		// Both calls of a request share the sampling decision
+		_, _ = rec.Complete(ctx, domain.LLMProviderOpenAI, &domain.LLMRequest{})
+		_, _ = rec.Complete(ctx, domain.LLMProviderOpenAI, &domain.LLMRequest{})
+	}
+
+	ids := store.RequestIDs()
+	if len(ids) < 400 || len(ids) > 600 {
+		t.Errorf("Expected about 500 of 2000 requests sampled, got %d", len(ids))
+	}
+	for _, id := range ids {
+		if n := len(store.Traces(id)); n != 2 {
+			t.Errorf("Expected both calls for %s, got %d", id, n)
+		}
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	store := NewStore(2, time.Hour)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	store.Add(domain.LLMTrace{RequestID: "a", StartedAt: now})
+	store.Add(domain.LLMTrace{RequestID: "b", StartedAt: now})
+	store.Add(domain.LLMTrace{RequestID: "c", StartedAt: now})
+
+	if store.Traces("a") != nil {
+		t.Error("Expected oldest request to be evicted past max requests")
+	}
+	if ids := store.RequestIDs(); len(ids) != 2 || ids[0] != "c" {
+		t.Errorf("Expected [c b], got %v", ids)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if store.Traces("c") != nil {
+		t.Error("Expected traces to expire after the retention period")
+	}
+}
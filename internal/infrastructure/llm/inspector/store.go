@@ -0,0 +1,115 @@
+package inspector
+
+import (
+	"sync"
+	"time"
+
+	"nuimanbot/internal/domain"
+)
+
+const (
+	// DefaultMaxRequests is the number of request IDs kept when none is configured.
+	DefaultMaxRequests = 500
+
+	// DefaultRetention is how long traces are kept when no retention is configured.
+	DefaultRetention = 24 * time.Hour
+
+	// maxTracesPerRequest bounds a single request's trace list (e.g. a runaway tool loop).
+	maxTracesPerRequest = 50
+)
+
+// Store is a bounded in-memory trace store. It keeps the traces of the most
+// recent MaxRequests request IDs and drops anything older than the retention period.
+type Store struct {
+	mu          sync.Mutex
+	traces      map[string][]domain.LLMTrace
+	order       []string // Request IDs, oldest first
+	maxRequests int
+	retention   time.Duration
+
+	now func() time.Time // Overridable for tests
+}
+
+// NewStore creates a store. Non-positive limits use the defaults.
+func NewStore(maxRequests int, retention time.Duration) *Store {
+	if maxRequests <= 0 {
+		maxRequests = DefaultMaxRequests
+	}
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Store{
+		traces:      make(map[string][]domain.LLMTrace),
+		maxRequests: maxRequests,
+		retention:   retention,
+		now:         time.Now,
+	}
+}
+
+// Add stores a trace under its request ID.
+func (s *Store) Add(trace domain.LLMTrace) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+
+	existing, ok := s.traces[trace.RequestID]
+	if !ok {
+		s.order = append(s.order, trace.RequestID)
+	}
+	if len(existing) >= maxTracesPerRequest {
+		existing = existing[1:]
+	}
+	s.traces[trace.RequestID] = append(existing, trace)
+
+	for len(s.order) > s.maxRequests {
+		delete(s.traces, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// Traces returns the traces recorded for requestID, oldest first.
+func (s *Store) Traces(requestID string) []domain.LLMTrace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+
+	traces := s.traces[requestID]
+	if len(traces) == 0 {
+		return nil
+	}
+	result := make([]domain.LLMTrace, len(traces))
+	copy(result, traces)
+	return result
+}
+
+// RequestIDs returns the stored request IDs, most recent first.
+func (s *Store) RequestIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+
+	ids := make([]string, len(s.order))
+	for i, id := range s.order {
+		ids[len(s.order)-1-i] = id
+	}
+	return ids
+}
+
+// expire drops requests whose latest trace is past the retention period.
+// Callers must hold the lock.
+func (s *Store) expire() {
+	cutoff := s.now().Add(-s.retention)
+	kept := s.order[:0]
+	for _, id := range s.order {
+		traces := s.traces[id]
+		if traces[len(traces)-1].StartedAt.Before(cutoff) {
+			delete(s.traces, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	s.order = kept
+}
@@ -0,0 +1,98 @@
+package keypool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"nuimanbot/internal/domain"
+)
+
+// SupportsBatch reports whether any key's client can submit batches for provider.
+func (p *Pool) SupportsBatch(provider domain.LLMProvider) bool {
+	for _, key := range p.keys {
+		if batcher, ok := key.Client.(domain.LLMBatchService); ok && batcher.SupportsBatch(provider) {
+			return true
+		}
+	}
+	return false
+}
+
+// SubmitBatch submits the requests through the least-loaded key that supports
+// batches. Provider batches belong to the account of the key that submitted
+// them, so the returned ID is prefixed with the key's ID ("<key>:<batch>")
+// and later calls for the batch go through the same key.
+func (p *Pool) SubmitBatch(ctx context.Context, provider domain.LLMProvider, requests []domain.BatchRequest) (string, error) {
+	p.mu.Lock()
+	now := p.now()
+	var best *keyState
+	var batcher domain.LLMBatchService
+	for _, key := range p.keys {
+		b, ok := key.Client.(domain.LLMBatchService)
+		if !ok || !b.SupportsBatch(provider) {
+			continue
+		}
+		key.prune(now)
+		if best == nil || key.load() < best.load() {
+			best, batcher = key, b
+		}
+	}
+	p.mu.Unlock()
+
+	if best == nil {
+		return "", fmt.Errorf("no %s API key supports batches", provider)
+	}
+	batchID, err := batcher.SubmitBatch(ctx, provider, requests)
+	if err != nil {
+		return "", err
+	}
+	return best.ID + ":" + batchID, nil
+}
+
+// GetBatch returns the progress of a batch through the key that submitted it.
+func (p *Pool) GetBatch(ctx context.Context, provider domain.LLMProvider, batchID string) (*domain.LLMBatchStatus, error) {
+	batcher, id, err := p.batchKey(batchID)
+	if err != nil {
+		return nil, err
+	}
+	return batcher.GetBatch(ctx, provider, id)
+}
+
+// BatchResults returns the results of a batch through the key that submitted it.
+func (p *Pool) BatchResults(ctx context.Context, provider domain.LLMProvider, batchID string) ([]domain.BatchResult, error) {
+	batcher, id, err := p.batchKey(batchID)
+	if err != nil {
+		return nil, err
+	}
+	return batcher.BatchResults(ctx, provider, id)
+}
+
+// CancelBatch cancels a batch through the key that submitted it.
+func (p *Pool) CancelBatch(ctx context.Context, provider domain.LLMProvider, batchID string) error {
+	batcher, id, err := p.batchKey(batchID)
+	if err != nil {
+		return err
+	}
+	return batcher.CancelBatch(ctx, provider, id)
+}
+
+// batchKey splits a pool batch ID into the submitting key's client and the
+// provider's batch ID. Provider batch IDs never contain ':', key IDs may.
+func (p *Pool) batchKey(batchID string) (domain.LLMBatchService, string, error) {
+	i := strings.LastIndexByte(batchID, ':')
+	if i < 0 {
+		return nil, "", fmt.Errorf("batch %q was not submitted through the %s key pool", batchID, p.provider)
+	}
+	keyID, id := batchID[:i], batchID[i+1:]
+	for _, key := range p.keys {
+		if key.ID != keyID {
+			continue
+		}
+		batcher, ok := key.Client.(domain.LLMBatchService)
+		if !ok {
+			return nil, "", fmt.Errorf("%s API key %q does not support batches", p.provider, keyID)
+		}
+		return batcher, id, nil
+	}
+	return nil, "", fmt.Errorf("batch %q belongs to %s API key %q, which is no longer configured", id, p.provider, keyID)
+}
@@ -0,0 +1,103 @@
+package keypool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/domain"
+)
+
+// fakeBatchClient is a provider client with a batch API.
+type fakeBatchClient struct {
+	fakeClient
+	submitted []string
+	queried   []string
+}
+
+func (f *fakeBatchClient) SupportsBatch(provider domain.LLMProvider) bool {
+	return true
+}
+
+func (f *fakeBatchClient) SubmitBatch(ctx context.Context, provider domain.LLMProvider, requests []domain.BatchRequest) (string, error) {
+	f.submitted = append(f.submitted, requests[0].CustomID)
+	return "batch_" + f.name, nil
+}
+
+func (f *fakeBatchClient) GetBatch(ctx context.Context, provider domain.LLMProvider, batchID string) (*domain.LLMBatchStatus, error) {
+	f.queried = append(f.queried, batchID)
+	return &domain.LLMBatchStatus{}, nil
+}
+
+func (f *fakeBatchClient) BatchResults(ctx context.Context, provider domain.LLMProvider, batchID string) ([]domain.BatchResult, error) {
+	f.queried = append(f.queried, batchID)
+	return nil, nil
+}
+
+func (f *fakeBatchClient) CancelBatch(ctx context.Context, provider domain.LLMProvider, batchID string) error {
+	f.queried = append(f.queried, batchID)
+	return nil
+}
+
+func TestPool_BatchUsesSubmittingKey(t *testing.T) {
+	plain := &fakeClient{name: "plain"}
+	a := &fakeBatchClient{fakeClient: fakeClient{name: "a"}}
+	b := &fakeBatchClient{fakeClient: fakeClient{name: "b"}}
+	pool, _ := newTestPool(t, []Key{
+		{ID: "plain", Client: plain},
+		{ID: "team:a", Client: a, RequestsPerMinute: 10},
+		{ID: "b", Client: b, RequestsPerMinute: 10},
+	})
+	ctx := context.Background()
+	provider := domain.LLMProviderAnthropic
+
+	var batcher domain.LLMBatchService = pool
+	if !batcher.SupportsBatch(provider) {
+		t.Fatal("Expected the pool to support batches when a key does")
+	}
+
+	id, err := pool.SubmitBatch(ctx, provider, []domain.BatchRequest{{CustomID: "r1"}})
+	if err != nil {
+		t.Fatalf("SubmitBatch() error: %v", err)
+	}
+	submitter, other := a, b
+	if len(b.submitted) == 1 {
+		submitter, other = b, a
+	}
+	if len(submitter.submitted) != 1 || len(other.submitted) != 0 {
+		t.Fatalf("Expected exactly one batch-capable key to submit, got a=%v b=%v", a.submitted, b.submitted)
+	}
+	if !strings.HasSuffix(id, ":batch_"+submitter.name) {
+		t.Fatalf("Expected the batch ID to name the submitting key, got %q", id)
+	}
+
+	if _, err := pool.GetBatch(ctx, provider, id); err != nil {
+		t.Fatalf("GetBatch() error: %v", err)
+	}
+	if _, err := pool.BatchResults(ctx, provider, id); err != nil {
+		t.Fatalf("BatchResults() error: %v", err)
+	}
+	if err := pool.CancelBatch(ctx, provider, id); err != nil {
+		t.Fatalf("CancelBatch() error: %v", err)
+	}
+	want := "batch_" + submitter.name
+	if len(submitter.queried) != 3 || submitter.queried[0] != want || len(other.queried) != 0 {
+		t.Errorf("Expected all batch calls on the submitting key with ID %q, got a=%v b=%v", want, a.queried, b.queried)
+	}
+
+	for _, bad := range []string{"batch_a", "gone:batch_a", "plain:batch_a"} {
+		if _, err := pool.GetBatch(ctx, provider, bad); err == nil {
+			t.Errorf("Expected an error for batch ID %q", bad)
+		}
+	}
+}
+
+func TestPool_SupportsBatchWithoutBatchKeys(t *testing.T) {
+	pool, _ := newTestPool(t, []Key{{ID: "k1", Client: &fakeClient{name: "k1"}}})
+	if pool.SupportsBatch(domain.LLMProviderAnthropic) {
+		t.Error("Expected no batch support without batch-capable clients")
+	}
+	if _, err := pool.SubmitBatch(context.Background(), domain.LLMProviderAnthropic, nil); err == nil {
+		t.Error("Expected SubmitBatch to fail without batch-capable clients")
+	}
+}
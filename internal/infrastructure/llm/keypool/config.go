@@ -0,0 +1,53 @@
+package keypool
+
+import (
+	"context"
+	"fmt"
+
+	"nuimanbot/internal/config"
+	"nuimanbot/internal/domain"
+)
+
+// ClientFactory creates a provider client that authenticates with apiKey.
+type ClientFactory func(apiKey domain.SecureString) (domain.LLMService, error)
+
+// FromConfig builds a pool from key pool configuration. Keys with a VaultKey
+// are read from the credential vault; others use their inline APIKey.
+func FromConfig(ctx context.Context, provider domain.LLMProvider, keyCfgs []config.APIKeyConfig, vault domain.CredentialVault, factory ClientFactory) (*Pool, error) {
+	keys := make([]Key, 0, len(keyCfgs))
+	for i, kc := range keyCfgs {
+		id := kc.ID
+		if id == "" {
+			id = fmt.Sprintf("key-%d", i+1)
+		}
+
+		apiKey := kc.APIKey
+		if kc.VaultKey != "" {
+			if vault == nil {
+				return nil, fmt.Errorf("key %q references vault entry %q but no vault is configured", id, kc.VaultKey)
+			}
+			secret, err := vault.Retrieve(ctx, kc.VaultKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read key %q from vault: %w", id, err)
+			}
+			apiKey = secret
+		}
+		if apiKey.Value() == "" {
+			return nil, fmt.Errorf("key %q has no API key", id)
+		}
+
+		client, err := factory(apiKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for key %q: %w", id, err)
+		}
+
+		keys = append(keys, Key{
+			ID:                id,
+			Client:            client,
+			RequestsPerMinute: kc.RequestsPerMinute,
+			TokensPerMinute:   kc.TokensPerMinute,
+		})
+	}
+
+	return New(provider, keys)
+}
@@ -0,0 +1,376 @@
+// Package keypool spreads LLM requests for one provider across several API keys.
+// Each key has optional per-minute request and token budgets; the pool sends
+// every request through the least-loaded key and parks keys that hit a rate
+// limit until the provider says they may be used again.
+package keypool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/metrics"
+)
+
+const (
+	// window is the budget accounting period.
+	window = time.Minute
+
+	// DefaultParkDuration is used when a 429 response carries no reset time.
+	DefaultParkDuration = 30 * time.Second
+
+	// DefaultMaxWait bounds how long a request waits for a key to free up.
+	DefaultMaxWait = 30 * time.Second
+)
+
+// Key is one credential in a pool, with the client that uses it.
+type Key struct {
+	ID                string            // Label used in logs and metrics (never the secret)
+	Client            domain.LLMService // Provider client configured with this key
+	RequestsPerMinute int               // Request budget; 0 means unlimited
+	TokensPerMinute   int               // Token budget; 0 means unlimited
+}
+
+// usageEvent is a request inside the accounting window. Tokens start as an
+// estimate and are replaced with actual usage once the response arrives.
+type usageEvent struct {
+	at     time.Time
+	tokens int
+}
+
+// keyState tracks a key's recent usage.
+type keyState struct {
+	Key
+	events      []*usageEvent
+	inFlight    int
+	parkedUntil time.Time
+}
+
+// Pool implements domain.LLMService over a set of keys for a single provider.
+type Pool struct {
+	provider domain.LLMProvider
+	keys     []*keyState
+
+	mu sync.Mutex
+
+	// Overridable for tests
+	now          func() time.Time
+	parkDuration time.Duration
+	maxWait      time.Duration
+}
+
+// New creates a pool for provider. At least one key is required.
+func New(provider domain.LLMProvider, keys []Key) (*Pool, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("key pool for %s requires at least one key", provider)
+	}
+
+	states := make([]*keyState, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for i, k := range keys {
+		if k.Client == nil {
+			return nil, fmt.Errorf("key %q has no client", k.ID)
+		}
+		if k.ID == "" {
+			k.ID = fmt.Sprintf("key-%d", i+1)
+		}
+		if seen[k.ID] {
+			return nil, fmt.Errorf("duplicate key id %q", k.ID)
+		}
+		seen[k.ID] = true
+		states[i] = &keyState{Key: k}
+	}
+
+	return &Pool{
+		provider:     provider,
+		keys:         states,
+		now:          time.Now,
+		parkDuration: DefaultParkDuration,
+		maxWait:      DefaultMaxWait,
+	}, nil
+}
+
+// SetMaxWait sets how long a request may wait for a key before failing.
+func (p *Pool) SetMaxWait(d time.Duration) {
+	p.maxWait = d
+}
+
+// Complete sends the request through the least-loaded key, moving on to the
+// next key if one is rate limited.
+func (p *Pool) Complete(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+	estimate := estimateTokens(req)
+
+	var lastErr error
+	for attempt := 0; attempt < len(p.keys); attempt++ {
+		key, event, err := p.acquire(ctx, estimate)
+		if err != nil {
+			return nil, joinLast(err, lastErr)
+		}
+
+		resp, err := key.Client.Complete(ctx, provider, req)
+		if err == nil {
+			p.release(key, event, resp.Usage.TotalTokens, nil)
+			return resp, nil
+		}
+		p.release(key, event, 0, err)
+
+		if !errors.Is(err, domain.ErrRateLimitExceeded) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// Stream starts a stream through the least-loaded key. A rate limit reported
+// before any output is produced is retried on another key.
+func (p *Pool) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+	estimate := estimateTokens(req)
+
+	var lastErr error
+	for attempt := 0; attempt < len(p.keys); attempt++ {
+		key, event, err := p.acquire(ctx, estimate)
+		if err != nil {
+			return nil, joinLast(err, lastErr)
+		}
+
+		stream, err := key.Client.Stream(ctx, provider, req)
+		if err != nil {
+			p.release(key, event, 0, err)
+			if !errors.Is(err, domain.ErrRateLimitExceeded) {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+
+		// Some SDKs only report 429 as the first stream event
+		first, ok := <-stream
+		if ok && first.Error != nil && errors.Is(first.Error, domain.ErrRateLimitExceeded) {
+			p.release(key, event, 0, first.Error)
+			go drain(stream)
+			lastErr = first.Error
+			continue
+		}
+
+		return p.forward(key, event, first, ok, stream), nil
+	}
+	return nil, lastErr
+}
+
+// ListModels lists models using the first key.
+func (p *Pool) ListModels(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
+	return p.keys[0].Client.ListModels(ctx, provider)
+}
+
+// forward relays a stream to the caller and releases the key when it ends.
+func (p *Pool) forward(key *keyState, event *usageEvent, first domain.StreamChunk, ok bool, stream <-chan domain.StreamChunk) <-chan domain.StreamChunk {
+	out := make(chan domain.StreamChunk, 10)
+	go func() {
+		defer close(out)
+		var streamErr error
+		if ok {
+			streamErr = first.Error
+			out <- first
+			for chunk := range stream {
+				if chunk.Error != nil {
+					streamErr = chunk.Error
+				}
+				out <- chunk
+			}
+		}
+		p.release(key, event, 0, streamErr)
+	}()
+	return out
+}
+
+// acquire reserves the least-loaded available key, waiting up to maxWait for
+// one to become available.
+func (p *Pool) acquire(ctx context.Context, estimate int) (*keyState, *usageEvent, error) {
+	deadline := p.now().Add(p.maxWait)
+	for {
+		p.mu.Lock()
+		now := p.now()
+		key, nextFree := p.pick(now, estimate)
+		if key != nil {
+			event := &usageEvent{at: now, tokens: estimate}
+			key.events = append(key.events, event)
+			key.inFlight++
+			p.observe(key, now)
+			p.mu.Unlock()
+			metrics.LLMKeyRequestsTotal.WithLabelValues(string(p.provider), key.ID).Inc()
+			return key, event, nil
+		}
+		p.mu.Unlock()
+
+		if nextFree.After(deadline) {
+			return nil, nil, fmt.Errorf("%w: all %d %s API keys are busy until %s", domain.ErrRateLimitExceeded, len(p.keys), p.provider, nextFree.Format(time.RFC3339))
+		}
+
+		timer := time.NewTimer(nextFree.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pick returns the available key with the lowest load, or nil and the
+// earliest time a key frees up. Callers must hold the lock.
+func (p *Pool) pick(now time.Time, estimate int) (*keyState, time.Time) {
+	var best *keyState
+	var bestLoad float64
+	var nextFree time.Time
+
+	for _, key := range p.keys {
+		key.prune(now)
+
+		free := key.availableAt(now, estimate)
+		if free.After(now) {
+			if nextFree.IsZero() || free.Before(nextFree) {
+				nextFree = free
+			}
+			continue
+		}
+
+		load := key.load()
+		if best == nil || load < bestLoad || (load == bestLoad && key.inFlight < best.inFlight) {
+			best, bestLoad = key, load
+		}
+	}
+	return best, nextFree
+}
+
+// release settles a request's token reservation and parks the key on rate limits.
+func (p *Pool) release(key *keyState, event *usageEvent, actual int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	key.inFlight--
+
+	// Replace the estimate with the actual token spend when known
+	if actual > 0 {
+		event.tokens = actual
+	}
+
+	var rlErr *domain.RateLimitError
+	if errors.Is(err, domain.ErrRateLimitExceeded) {
+		wait := p.parkDuration
+		if errors.As(err, &rlErr) && rlErr.RetryAfter > 0 {
+			wait = rlErr.RetryAfter
+		}
+		key.parkedUntil = now.Add(wait)
+		metrics.LLMKeyRateLimitedTotal.WithLabelValues(string(p.provider), key.ID).Inc()
+		slog.Warn("LLM API key rate limited; parking key",
+			"provider", p.provider,
+			"key", key.ID,
+			"until", key.parkedUntil,
+		)
+	}
+
+	p.observe(key, now)
+}
+
+// observe publishes a key's utilization. Callers must hold the lock.
+func (p *Pool) observe(key *keyState, now time.Time) {
+	key.prune(now)
+	metrics.LLMKeyUtilization.WithLabelValues(string(p.provider), key.ID).Set(key.load())
+}
+
+// Utilization returns each key's current budget utilization (0 = idle, 1 = exhausted).
+func (p *Pool) Utilization() map[string]float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	result := make(map[string]float64, len(p.keys))
+	for _, key := range p.keys {
+		key.prune(now)
+		result[key.ID] = key.load()
+	}
+	return result
+}
+
+// prune drops events older than the accounting window.
+func (k *keyState) prune(now time.Time) {
+	cutoff := now.Add(-window)
+	i := 0
+	for i < len(k.events) && !k.events[i].at.After(cutoff) {
+		i++
+	}
+	k.events = k.events[i:]
+}
+
+// usage returns requests and tokens spent in the current window.
+func (k *keyState) usage() (requests, tokens int) {
+	for _, e := range k.events {
+		tokens += e.tokens
+	}
+	return len(k.events), tokens
+}
+
+// load is the fraction of the tighter budget in use.
+func (k *keyState) load() float64 {
+	requests, tokens := k.usage()
+	var load float64
+	if k.RequestsPerMinute > 0 {
+		load = float64(requests) / float64(k.RequestsPerMinute)
+	}
+	if k.TokensPerMinute > 0 {
+		load = max(load, float64(tokens)/float64(k.TokensPerMinute))
+	}
+	return load
+}
+
+// availableAt returns when the key can take a request of estimate tokens.
+func (k *keyState) availableAt(now time.Time, estimate int) time.Time {
+	free := now
+	if k.parkedUntil.After(free) {
+		free = k.parkedUntil
+	}
+	if len(k.events) == 0 {
+		return free
+	}
+
+	requests, tokens := k.usage()
+	overRequests := k.RequestsPerMinute > 0 && requests >= k.RequestsPerMinute
+	overTokens := k.TokensPerMinute > 0 && tokens+estimate > k.TokensPerMinute && tokens > 0
+	if overRequests || overTokens {
+		// Budget frees up as the oldest event leaves the window
+		if expiry := k.events[0].at.Add(window); expiry.After(free) {
+			free = expiry
+		}
+	}
+	return free
+}
+
+// estimateTokens approximates a request's token spend (about 4 characters per
+// token for the prompt, plus the full output allowance).
+func estimateTokens(req *domain.LLMRequest) int {
+	chars := len(req.SystemPrompt)
+	for _, msg := range req.Messages {
+		chars += len(msg.Content)
+	}
+	return chars/4 + req.MaxTokens
+}
+
+// joinLast prefers the last provider error over a generic acquisition error.
+func joinLast(err, last error) error {
+	if last != nil && errors.Is(err, domain.ErrRateLimitExceeded) {
+		return last
+	}
+	return err
+}
+
+// drain discards the rest of an abandoned stream so its producer can exit.
+func drain(stream <-chan domain.StreamChunk) {
+	for range stream { //nolint:revive // Intentionally empty
+	}
+}
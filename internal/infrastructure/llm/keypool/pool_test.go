@@ -0,0 +1,221 @@
+package keypool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"nuimanbot/internal/config"
+	"nuimanbot/internal/domain"
+)
+
+// fakeClient is a provider client that records calls and can be scripted to fail.
+type fakeClient struct {
+	name       string
+	calls      int
+	rateLimit  time.Duration // If >= 0 and limited is true, return a RateLimitError with this RetryAfter
+	limited    bool
+	streamFail bool // Report the rate limit as the first stream chunk instead
+	tokens     int
+}
+
+func (f *fakeClient) Complete(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+	f.calls++
+	if f.limited {
+		return nil, &domain.RateLimitError{Provider: provider, RetryAfter: f.rateLimit}
+	}
+	return &domain.LLMResponse{Content: f.name, Usage: domain.TokenUsage{TotalTokens: f.tokens}}, nil
+}
+
+func (f *fakeClient) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+	f.calls++
+	ch := make(chan domain.StreamChunk, 2)
+	if f.limited && f.streamFail {
+		ch <- domain.StreamChunk{Error: &domain.RateLimitError{Provider: provider}}
+	} else {
+		ch <- domain.StreamChunk{Delta: f.name}
+		ch <- domain.StreamChunk{Done: true}
+	}
+	close(ch)
+	return ch, nil
+}
+
+func (f *fakeClient) ListModels(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
+	return nil, nil
+}
+
+// newTestPool creates a pool with a controllable clock.
+func newTestPool(t *testing.T, keys []Key) (*Pool, *time.Time) {
+	t.Helper()
+	pool, err := New(domain.LLMProviderAnthropic, keys)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	pool.now = func() time.Time { return now }
+	pool.maxWait = 0
+	return pool, &now
+}
+
+func complete(t *testing.T, pool *Pool) (string, error) {
+	t.Helper()
+	resp, err := pool.Complete(context.Background(), domain.LLMProviderAnthropic, &domain.LLMRequest{MaxTokens: 100})
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func TestPool_LeastLoaded(t *testing.T) {
+	a := &fakeClient{name: "a", tokens: 100}
+	b := &fakeClient{name: "b", tokens: 100}
+	pool, _ := newTestPool(t, []Key{
+		{ID: "a", Client: a, RequestsPerMinute: 10},
+		{ID: "b", Client: b, RequestsPerMinute: 20},
+	})
+
+	for i := 0; i < 6; i++ {
+		if _, err := complete(t, pool); err != nil {
+			t.Fatalf("Complete() error: %v", err)
+		}
+	}
+
+	// b has twice the budget, so it should take twice the requests
+	if a.calls != 2 || b.calls != 4 {
+		t.Errorf("Expected 2/4 split across keys, got a=%d b=%d", a.calls, b.calls)
+	}
+
+	util := pool.Utilization()
+	if util["a"] != 0.2 || util["b"] != 0.2 {
+		t.Errorf("Expected 0.2 utilization on both keys, got %v", util)
+	}
+}
+
+func TestPool_ParksRateLimitedKey(t *testing.T) {
+	a := &fakeClient{name: "a", limited: true, rateLimit: 10 * time.Second}
+	b := &fakeClient{name: "b"}
+	pool, now := newTestPool(t, []Key{{ID: "a", Client: a}, {ID: "b", Client: b}})
+
+	got, err := complete(t, pool)
+	if err != nil || got != "b" {
+		t.Fatalf("Expected failover to key b, got %q, %v", got, err)
+	}
+
+	// a stays parked until its reset time, even though it is now less loaded
+	a.limited = false
+	if got, _ := complete(t, pool); got != "b" {
+		t.Errorf("Expected parked key to be skipped, got %q", got)
+	}
+
+	*now = now.Add(11 * time.Second)
+	if got, _ := complete(t, pool); got != "a" {
+		t.Errorf("Expected key a after its reset time, got %q", got)
+	}
+}
+
+func TestPool_AllKeysExhausted(t *testing.T) {
+	a := &fakeClient{name: "a", limited: true}
+	pool, _ := newTestPool(t, []Key{{ID: "a", Client: a}})
+
+	_, err := complete(t, pool)
+	var rlErr *domain.RateLimitError
+	if !errors.As(err, &rlErr) {
+		t.Fatalf("Expected provider RateLimitError, got %v", err)
+	}
+
+	// Parked with the default duration and no wait allowed
+	_, err = complete(t, pool)
+	if !errors.Is(err, domain.ErrRateLimitExceeded) {
+		t.Errorf("Expected ErrRateLimitExceeded while parked, got %v", err)
+	}
+	if a.calls != 1 {
+		t.Errorf("Expected parked key not to be called, got %d calls", a.calls)
+	}
+}
+
+func TestPool_TokenBudget(t *testing.T) {
+	a := &fakeClient{name: "a", tokens: 900}
+	pool, now := newTestPool(t, []Key{{ID: "a", Client: a, TokensPerMinute: 1000}})
+
+	if _, err := complete(t, pool); err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+	// 900 spent plus a 100-token estimate still fits the 1000-token budget
+	if _, err := complete(t, pool); err != nil {
+		t.Fatalf("Expected second request within budget, got %v", err)
+	}
+	if _, err := complete(t, pool); !errors.Is(err, domain.ErrRateLimitExceeded) {
+		t.Errorf("Expected token budget to be exhausted, got %v", err)
+	}
+
+	*now = now.Add(window + time.Second)
+	if _, err := complete(t, pool); err != nil {
+		t.Errorf("Expected budget to reset after the window, got %v", err)
+	}
+}
+
+func TestPool_StreamRetriesRateLimitedKey(t *testing.T) {
+	a := &fakeClient{name: "a", limited: true, streamFail: true}
+	b := &fakeClient{name: "b"}
+	pool, _ := newTestPool(t, []Key{{ID: "a", Client: a}, {ID: "b", Client: b}})
+
+	stream, err := pool.Stream(context.Background(), domain.LLMProviderAnthropic, &domain.LLMRequest{})
+	if err != nil {
+		t.Fatalf("Stream() error: %v", err)
+	}
+
+	var text string
+	for chunk := range stream {
+		if chunk.Error != nil {
+			t.Fatalf("Unexpected stream error: %v", chunk.Error)
+		}
+		text += chunk.Delta
+	}
+	if text != "b" {
+		t.Errorf("Expected stream from key b, got %q", text)
+	}
+}
+
+// fakeVault is an in-memory CredentialVault.
+type fakeVault struct {
+	domain.CredentialVault
+	secrets map[string]string
+}
+
+func (v *fakeVault) Retrieve(ctx context.Context, key string) (domain.SecureString, error) {
+	s, ok := v.secrets[key]
+	if !ok {
+		return domain.SecureString{}, domain.ErrNotFound
+	}
+	return domain.NewSecureStringFromString(s), nil
+}
+
+func TestFromConfig(t *testing.T) {
+	vault := &fakeVault{secrets: map[string]string{"anthropic_1": "sk-vault"}}
+
+	var seen []string
+	factory := func(apiKey domain.SecureString) (domain.LLMService, error) {
+		seen = append(seen, apiKey.Value())
+		return &fakeClient{}, nil
+	}
+
+	pool, err := FromConfig(context.Background(), domain.LLMProviderAnthropic, []config.APIKeyConfig{
+		{ID: "vaulted", VaultKey: "anthropic_1", RequestsPerMinute: 50},
+		{APIKey: domain.NewSecureStringFromString("sk-inline")},
+	}, vault, factory)
+	if err != nil {
+		t.Fatalf("FromConfig() error: %v", err)
+	}
+
+	if len(seen) != 2 || seen[0] != "sk-vault" || seen[1] != "sk-inline" {
+		t.Errorf("Expected vault and inline keys, got %v", seen)
+	}
+	if pool.keys[1].ID != "key-2" || pool.keys[0].RequestsPerMinute != 50 {
+		t.Errorf("Unexpected key settings: %+v, %+v", pool.keys[0].Key, pool.keys[1].Key)
+	}
+
+	if _, err := FromConfig(context.Background(), domain.LLMProviderAnthropic, []config.APIKeyConfig{{VaultKey: "missing"}}, vault, factory); err == nil {
+		t.Error("Expected error for missing vault entry")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"nuimanbot/internal/config"
@@ -17,6 +18,8 @@ import (
 type Client struct {
 	httpClient *http.Client
 	config     *config.OllamaProviderConfig
+
+	toolSupport sync.Map // model name -> bool, from /api/show capabilities
 }
 
 // New creates a new Ollama client with the provided configuration.
@@ -34,21 +37,27 @@ type ollamaChatRequest struct {
 	Model    string          `json:"model"`
 	Messages []ollamaMessage `json:"messages"`
 	Stream   bool            `json:"stream"`
+	Tools    []ollamaTool    `json:"tools,omitempty"`
+	Think    bool            `json:"think,omitempty"`
+	Format   any             `json:"format,omitempty"` // "json" or a JSON schema object
 	Options  map[string]any  `json:"options,omitempty"`
 }
 
 // ollamaMessage represents a message in Ollama format
 type ollamaMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	Thinking  string           `json:"thinking,omitempty"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
 }
 
 // ollamaChatResponse represents an Ollama /api/chat response
 type ollamaChatResponse struct {
-	Model     string        `json:"model"`
-	CreatedAt string        `json:"created_at"`
-	Message   ollamaMessage `json:"message"`
-	Done      bool          `json:"done"`
+	Model      string        `json:"model"`
+	CreatedAt  string        `json:"created_at"`
+	Message    ollamaMessage `json:"message"`
+	Done       bool          `json:"done"`
+	DoneReason string        `json:"done_reason,omitempty"` // "stop", "length", ...
 }
 
 // Complete performs a completion request to the Ollama API.
@@ -58,7 +67,9 @@ func (c *Client) Complete(ctx context.Context, provider domain.LLMProvider, req
 	}
 
 	// Convert domain.LLMRequest to Ollama format
-	ollamaReq := c.convertRequest(req)
+	req = applyToolChoice(req)
+	nativeTools := c.useNativeTools(ctx, req)
+	ollamaReq := c.convertRequest(req, nativeTools)
 
 	// Make HTTP POST to /api/chat
 	url := fmt.Sprintf("%s/api/chat", c.config.BaseURL)
@@ -93,19 +104,52 @@ func (c *Client) Complete(ctx context.Context, provider domain.LLMProvider, req
 	}
 
 	// Convert to domain.LLMResponse
-	return c.convertResponse(&ollamaResp), nil
+	result := c.convertResponse(&ollamaResp)
+	if !nativeTools {
+		if call, ok := parseFallbackToolCall(result.Content, req.Tools); ok {
+			result.Content = ""
+			result.ToolCalls = []domain.ToolCall{*call}
+			result.FinishReason = domain.FinishReasonToolCalls
+		}
+	}
+	return result, nil
+}
+
+// useNativeTools reports whether tools can be sent via the native tools parameter.
+// It is false only when the request has tools and the model lacks tool support.
+func (c *Client) useNativeTools(ctx context.Context, req *domain.LLMRequest) bool {
+	if len(req.Tools) == 0 {
+		return true
+	}
+	return c.supportsTools(ctx, c.resolveModel(req))
 }
 
-// convertRequest converts domain.LLMRequest to Ollama format
-func (c *Client) convertRequest(req *domain.LLMRequest) ollamaChatRequest {
+// resolveModel returns the requested model or the configured default.
+func (c *Client) resolveModel(req *domain.LLMRequest) string {
+	if req.Model == "" && c.config.DefaultModel != "" {
+		return c.config.DefaultModel
+	}
+	return req.Model
+}
+
+// convertRequest converts domain.LLMRequest to Ollama format.
+// When nativeTools is false, tools are described in the system prompt instead.
+func (c *Client) convertRequest(req *domain.LLMRequest, nativeTools bool) ollamaChatRequest {
 	// Convert messages
 	messages := make([]ollamaMessage, 0, len(req.Messages)+1)
 
 	// Add system prompt if provided
-	if req.SystemPrompt != "" {
+	systemPrompt := req.SystemPrompt
+	if !nativeTools && len(req.Tools) > 0 {
+		if systemPrompt != "" {
+			systemPrompt += "\n\n"
+		}
+		systemPrompt += fallbackToolPrompt(req.Tools)
+	}
+	if systemPrompt != "" {
 		messages = append(messages, ollamaMessage{
 			Role:    "system",
-			Content: req.SystemPrompt,
+			Content: systemPrompt,
 		})
 	}
 
@@ -117,17 +161,27 @@ func (c *Client) convertRequest(req *domain.LLMRequest) ollamaChatRequest {
 		})
 	}
 
-	// Use default model if not specified
-	model := req.Model
-	if model == "" && c.config.DefaultModel != "" {
-		model = c.config.DefaultModel
-	}
-
 	// Build request
 	ollamaReq := ollamaChatRequest{
-		Model:    model,
+		Model:    c.resolveModel(req),
 		Messages: messages,
 		Stream:   false,
+		// Ollama only supports toggling thinking on capable models
+		Think: req.Reasoning.Enabled(),
+	}
+
+	// Convert tools if the model supports native tool calling
+	if nativeTools && len(req.Tools) > 0 {
+		ollamaReq.Tools = convertTools(req.Tools)
+	}
+
+	// Constrain output format if requested
+	switch {
+	case req.ResponseFormat == nil:
+	case req.ResponseFormat.Type == domain.ResponseFormatJSONSchema:
+		ollamaReq.Format = req.ResponseFormat.JSONSchema()
+	case req.ResponseFormat.Type == domain.ResponseFormatJSONObject:
+		ollamaReq.Format = "json"
 	}
 
 	// Set options
@@ -138,6 +192,18 @@ func (c *Client) convertRequest(req *domain.LLMRequest) ollamaChatRequest {
 	if req.MaxTokens > 0 {
 		options["num_predict"] = req.MaxTokens
 	}
+	if req.TopP > 0 {
+		options["top_p"] = req.TopP
+	}
+	if req.TopK > 0 {
+		options["top_k"] = req.TopK
+	}
+	if len(req.StopSequences) > 0 {
+		options["stop"] = req.StopSequences
+	}
+	if req.Seed != nil {
+		options["seed"] = *req.Seed
+	}
 	if len(options) > 0 {
 		ollamaReq.Options = options
 	}
@@ -147,12 +213,35 @@ func (c *Client) convertRequest(req *domain.LLMRequest) ollamaChatRequest {
 
 // convertResponse converts Ollama response to domain.LLMResponse
 func (c *Client) convertResponse(resp *ollamaChatResponse) *domain.LLMResponse {
-	return &domain.LLMResponse{
+	result := &domain.LLMResponse{
 		Content: resp.Message.Content,
 		// Ollama doesn't provide token usage in non-streaming mode
-		Usage: domain.TokenUsage{},
-		// Ollama doesn't provide finish_reason in this format
-		FinishReason: "stop",
+		Usage:        domain.TokenUsage{},
+		FinishReason: convertDoneReason(resp.DoneReason),
+	}
+
+	if resp.Message.Thinking != "" {
+		result.Reasoning = []domain.ReasoningBlock{{Text: resp.Message.Thinking}}
+	}
+
+	if len(resp.Message.ToolCalls) > 0 {
+		result.ToolCalls = convertToolCalls(resp.Message.ToolCalls)
+		result.FinishReason = domain.FinishReasonToolCalls
+	}
+
+	return result
+}
+
+// convertDoneReason maps Ollama's done_reason onto domain finish reasons.
+// Older Ollama versions omit it, so a missing reason means a normal stop.
+func convertDoneReason(reason string) domain.FinishReason {
+	switch reason {
+	case "", "stop":
+		return domain.FinishReasonStop
+	case "length":
+		return domain.FinishReasonLength
+	default:
+		return domain.FinishReasonOther
 	}
 }
 
@@ -163,7 +252,9 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 	}
 
 	// Convert domain.LLMRequest to Ollama format
-	ollamaReq := c.convertRequest(req)
+	req = applyToolChoice(req)
+	nativeTools := c.useNativeTools(ctx, req)
+	ollamaReq := c.convertRequest(req, nativeTools)
 	ollamaReq.Stream = true // Enable streaming
 
 	// Make HTTP POST to /api/chat
@@ -200,11 +291,28 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 		defer close(outChan)
 		defer func() { _ = resp.Body.Close() }()
 
+		// In prompt-based tool mode, content that might be a JSON tool call is
+		// held back until it can be told apart from a normal answer.
+		var buffered string
+		buffering := !nativeTools
+		flush := func() {
+			if !buffering {
+				return
+			}
+			buffering = false
+			if call, ok := parseFallbackToolCall(buffered, req.Tools); ok {
+				outChan <- domain.StreamChunk{ToolCall: call}
+			} else if buffered != "" {
+				outChan <- domain.StreamChunk{Delta: buffered}
+			}
+		}
+
 		decoder := json.NewDecoder(resp.Body)
 		for {
 			var chunk ollamaChatResponse
 			if err := decoder.Decode(&chunk); err != nil {
 				if err == io.EOF {
+					flush()
 					outChan <- domain.StreamChunk{Done: true}
 					return
 				}
@@ -212,13 +320,31 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 				return
 			}
 
+			// Send thinking delta
+			if chunk.Message.Thinking != "" {
+				outChan <- domain.StreamChunk{Kind: domain.StreamChunkThinking, Delta: chunk.Message.Thinking}
+			}
+
 			// Send content delta
 			if chunk.Message.Content != "" {
-				outChan <- domain.StreamChunk{Delta: chunk.Message.Content}
+				if buffering {
+					buffered += chunk.Message.Content
+					if !mayBeFallbackToolCall(buffered) {
+						flush()
+					}
+				} else {
+					outChan <- domain.StreamChunk{Delta: chunk.Message.Content}
+				}
+			}
+
+			// Send native tool calls
+			for _, call := range convertToolCalls(chunk.Message.ToolCalls) {
+				outChan <- domain.StreamChunk{ToolCall: &call}
 			}
 
 			// Check if done
 			if chunk.Done {
+				flush()
 				outChan <- domain.StreamChunk{Done: true}
 				return
 			}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"nuimanbot/internal/config"
@@ -155,3 +156,301 @@ func TestStream(t *testing.T) {
 		t.Error("Never received done signal")
 	}
 }
+
+func TestComplete_Thinking(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if body["think"] != true {
+			t.Errorf("Expected think=true, got %v", body["think"])
+		}
+
+		resp := map[string]any{
+			"model":   "qwen3",
+			"message": map[string]string{"role": "assistant", "content": "4", "thinking": "2+2 is 4."},
+			"done":    true,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := ollama.New(&config.OllamaProviderConfig{BaseURL: server.URL})
+
+	resp, err := client.Complete(context.Background(), domain.LLMProviderOllama, &domain.LLMRequest{
+		Model:     "qwen3",
+		Messages:  []domain.Message{{Role: "user", Content: "What is 2+2?"}},
+		Reasoning: domain.ReasoningConfig{Effort: domain.ReasoningEffortLow},
+	})
+	if err != nil {
+		t.Fatalf("Complete() returned error: %v", err)
+	}
+
+	if resp.Content != "4" {
+		t.Errorf("Expected content '4', got '%s'", resp.Content)
+	}
+	if resp.ReasoningText() != "2+2 is 4." {
+		t.Errorf("Expected reasoning '2+2 is 4.', got '%s'", resp.ReasoningText())
+	}
+}
+
+func TestComplete_ResponseFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		format, ok := body["format"].(map[string]any)
+		if !ok || format["type"] != "object" {
+			t.Errorf("Expected schema format, got %v", body["format"])
+		}
+
+		resp := map[string]any{
+			"model":   "llama3.2",
+			"message": map[string]string{"role": "assistant", "content": `{"answer":4}`},
+			"done":    true,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := ollama.New(&config.OllamaProviderConfig{BaseURL: server.URL})
+
+	resp, err := client.Complete(context.Background(), domain.LLMProviderOllama, &domain.LLMRequest{
+		Model:    "llama3.2",
+		Messages: []domain.Message{{Role: "user", Content: "What is 2+2?"}},
+		ResponseFormat: &domain.ResponseFormat{
+			Type:   domain.ResponseFormatJSONSchema,
+			Schema: map[string]any{"type": "object", "properties": map[string]any{"answer": map[string]any{"type": "integer"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Complete() returned error: %v", err)
+	}
+	if resp.Content != `{"answer":4}` {
+		t.Errorf("Expected JSON content, got '%s'", resp.Content)
+	}
+}
+
+var weatherTool = domain.ToolDefinition{
+	Name:        "weather",
+	Description: "Get the weather",
+	InputSchema: map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}},
+	},
+}
+
+// newToolServer returns a server advertising capabilities on /api/show and
+// answering /api/chat with handler.
+func newToolServer(t *testing.T, capabilities []string, handler func(body map[string]any) any) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/show":
+			json.NewEncoder(w).Encode(map[string]any{"capabilities": capabilities})
+		case "/api/chat":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode request: %v", err)
+			}
+			switch resp := handler(body).(type) {
+			case []map[string]any:
+				for _, chunk := range resp {
+					json.NewEncoder(w).Encode(chunk)
+				}
+			default:
+				json.NewEncoder(w).Encode(resp)
+			}
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestComplete_NativeToolCalls(t *testing.T) {
+	server := newToolServer(t, []string{"completion", "tools"}, func(body map[string]any) any {
+		tools, _ := body["tools"].([]any)
+		if len(tools) != 1 {
+			t.Fatalf("Expected 1 native tool, got %v", body["tools"])
+		}
+		fn := tools[0].(map[string]any)["function"].(map[string]any)
+		if fn["name"] != "weather" {
+			t.Errorf("Expected tool 'weather', got %v", fn["name"])
+		}
+		return map[string]any{
+			"model": "llama3.1",
+			"message": map[string]any{
+				"role":    "assistant",
+				"content": "",
+				"tool_calls": []map[string]any{
+					{"function": map[string]any{"name": "weather", "arguments": map[string]any{"city": "Paris"}}},
+				},
+			},
+			"done": true,
+		}
+	})
+	defer server.Close()
+
+	client := ollama.New(&config.OllamaProviderConfig{BaseURL: server.URL})
+
+	resp, err := client.Complete(context.Background(), domain.LLMProviderOllama, &domain.LLMRequest{
+		Model:    "llama3.1",
+		Messages: []domain.Message{{Role: "user", Content: "Weather in Paris?"}},
+		Tools:    []domain.ToolDefinition{weatherTool},
+	})
+	if err != nil {
+		t.Fatalf("Complete() returned error: %v", err)
+	}
+
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ToolName != "weather" || resp.ToolCalls[0].Arguments["city"] != "Paris" {
+		t.Errorf("Expected weather tool call for Paris, got %+v", resp.ToolCalls)
+	}
+	if resp.FinishReason != "tool_calls" {
+		t.Errorf("Expected finish reason 'tool_calls', got '%s'", resp.FinishReason)
+	}
+}
+
+func TestComplete_FallbackToolCalls(t *testing.T) {
+	server := newToolServer(t, []string{"completion"}, func(body map[string]any) any {
+		if _, ok := body["tools"]; ok {
+			t.Error("Expected no native tools for a model without tool support")
+		}
+		system := body["messages"].([]any)[0].(map[string]any)
+		if system["role"] != "system" || !strings.Contains(system["content"].(string), "- weather: Get the weather") {
+			t.Errorf("Expected tools described in system prompt, got %v", system)
+		}
+		return map[string]any{
+			"model":   "gemma2",
+			"message": map[string]any{"role": "assistant", "content": "```json\n{\"tool_call\": {\"name\": \"weather\", \"arguments\": {\"city\": \"Oslo\"}}}\n```"},
+			"done":    true,
+		}
+	})
+	defer server.Close()
+
+	client := ollama.New(&config.OllamaProviderConfig{BaseURL: server.URL})
+
+	resp, err := client.Complete(context.Background(), domain.LLMProviderOllama, &domain.LLMRequest{
+		Model:        "gemma2",
+		SystemPrompt: "You are helpful",
+		Messages:     []domain.Message{{Role: "user", Content: "Weather in Oslo?"}},
+		Tools:        []domain.ToolDefinition{weatherTool},
+	})
+	if err != nil {
+		t.Fatalf("Complete() returned error: %v", err)
+	}
+
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Arguments["city"] != "Oslo" {
+		t.Errorf("Expected parsed fallback tool call, got %+v", resp.ToolCalls)
+	}
+	if resp.Content != "" {
+		t.Errorf("Expected tool call JSON removed from content, got '%s'", resp.Content)
+	}
+}
+
+func TestStream_FallbackToolCalls(t *testing.T) {
+	tests := []struct {
+		name     string
+		chunks   []string
+		wantCall bool
+		wantText string
+	}{
+		{
+			name:     "tool call",
+			chunks:   []string{`{"tool_call": `, `{"name": "weather", "arguments": {"city": "Rome"}}}`},
+			wantCall: true,
+		},
+		{
+			name:     "plain answer",
+			chunks:   []string{"It is ", "sunny."},
+			wantText: "It is sunny.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newToolServer(t, []string{"completion"}, func(body map[string]any) any {
+				var chunks []map[string]any
+				for _, c := range tt.chunks {
+					chunks = append(chunks, map[string]any{"message": map[string]any{"role": "assistant", "content": c}, "done": false})
+				}
+				return append(chunks, map[string]any{"message": map[string]any{"role": "assistant", "content": ""}, "done": true})
+			})
+			defer server.Close()
+
+			client := ollama.New(&config.OllamaProviderConfig{BaseURL: server.URL})
+
+			stream, err := client.Stream(context.Background(), domain.LLMProviderOllama, &domain.LLMRequest{
+				Model:    "gemma2",
+				Messages: []domain.Message{{Role: "user", Content: "Weather?"}},
+				Tools:    []domain.ToolDefinition{weatherTool},
+			})
+			if err != nil {
+				t.Fatalf("Stream() returned error: %v", err)
+			}
+
+			var text string
+			var calls []*domain.ToolCall
+			for chunk := range stream {
+				if chunk.Error != nil {
+					t.Fatalf("Stream error: %v", chunk.Error)
+				}
+				text += chunk.Delta
+				if chunk.ToolCall != nil {
+					calls = append(calls, chunk.ToolCall)
+				}
+			}
+
+			if tt.wantCall && (len(calls) != 1 || calls[0].Arguments["city"] != "Rome") {
+				t.Errorf("Expected one weather tool call, got %+v", calls)
+			}
+			if !tt.wantCall && len(calls) != 0 {
+				t.Errorf("Expected no tool calls, got %+v", calls)
+			}
+			if text != tt.wantText {
+				t.Errorf("Expected text '%s', got '%s'", tt.wantText, text)
+			}
+		})
+	}
+}
+
+func TestComplete_SamplingAndToolChoice(t *testing.T) {
+	seed := 42
+	server := newToolServer(t, []string{"completion", "tools"}, func(body map[string]any) any {
+		options, _ := body["options"].(map[string]any)
+		if options["top_p"] != 0.9 || options["top_k"] != float64(40) || options["seed"] != float64(42) {
+			t.Errorf("Expected sampling options, got %v", options)
+		}
+		if stop, _ := options["stop"].([]any); len(stop) != 1 || stop[0] != "END" {
+			t.Errorf("Expected stop sequences, got %v", options["stop"])
+		}
+		if _, ok := body["tools"]; ok {
+			t.Errorf("Expected tools to be dropped for tool_choice none, got %v", body["tools"])
+		}
+		return map[string]any{
+			"model":   "llama3.1",
+			"message": map[string]any{"role": "assistant", "content": "Hi"},
+			"done":    true,
+		}
+	})
+	defer server.Close()
+
+	client := ollama.New(&config.OllamaProviderConfig{BaseURL: server.URL})
+	_, err := client.Complete(context.Background(), domain.LLMProviderOllama, &domain.LLMRequest{
+		Model:         "llama3.1",
+		Messages:      []domain.Message{{Role: "user", Content: "Hello"}},
+		Tools:         []domain.ToolDefinition{weatherTool},
+		ToolChoice:    &domain.ToolChoice{Mode: domain.ToolChoiceNone},
+		TopP:          0.9,
+		TopK:          40,
+		StopSequences: []string{"END"},
+		Seed:          &seed,
+	})
+	if err != nil {
+		t.Fatalf("Complete() returned error: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"nuimanbot/internal/domain"
+)
+
+// capabilityTools is the /api/show capability advertised by models with native tool calling.
+const capabilityTools = "tools"
+
+// ollamaTool represents a function tool in Ollama format
+type ollamaTool struct {
+	Type     string             `json:"type"`
+	Function ollamaToolFunction `json:"function"`
+}
+
+// ollamaToolFunction describes a callable function
+type ollamaToolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+// ollamaToolCall represents a tool call returned in message.tool_calls
+type ollamaToolCall struct {
+	Function struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+	} `json:"function"`
+}
+
+// fallbackToolCall is the JSON shape models without native tool support are asked to emit.
+type fallbackToolCall struct {
+	ToolCall *struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+	} `json:"tool_call"`
+}
+
+// supportsTools reports whether model advertises native tool calling via /api/show.
+// Results are cached per model. If capabilities cannot be determined (older Ollama
+// versions or transient errors), native tool calling is assumed.
+func (c *Client) supportsTools(ctx context.Context, model string) bool {
+	if cached, ok := c.toolSupport.Load(model); ok {
+		return cached.(bool)
+	}
+
+	capabilities, err := c.showCapabilities(ctx, model)
+	if err != nil {
+		return true // don't cache; retry on the next request
+	}
+
+	supported := capabilities == nil // field absent: server predates capability reporting
+	for _, capability := range capabilities {
+		if capability == capabilityTools {
+			supported = true
+			break
+		}
+	}
+
+	c.toolSupport.Store(model, supported)
+	return supported
+}
+
+// showCapabilities queries /api/show for a model's capabilities.
+func (c *Client) showCapabilities(ctx context.Context, model string) ([]string, error) {
+	body, err := json.Marshal(map[string]string{"model": model})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/show", c.config.BaseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama API error: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Capabilities []string `json:"capabilities"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Capabilities, nil
+}
+
+// convertTools converts domain.ToolDefinition to Ollama tool format
+func convertTools(tools []domain.ToolDefinition) []ollamaTool {
+	result := make([]ollamaTool, len(tools))
+	for i, tool := range tools {
+		result[i] = ollamaTool{
+			Type: "function",
+			Function: ollamaToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.InputSchema,
+			},
+		}
+	}
+	return result
+}
+
+// convertToolCalls converts Ollama tool calls to domain.ToolCall
+func convertToolCalls(calls []ollamaToolCall) []domain.ToolCall {
+	result := make([]domain.ToolCall, len(calls))
+	for i, call := range calls {
+		args := call.Function.Arguments
+		if args == nil {
+			args = map[string]any{}
+		}
+		result[i] = domain.ToolCall{ToolName: call.Function.Name, Arguments: args}
+	}
+	return result
+}
+
+// fallbackToolPrompt describes the available tools for models without native tool calling.
+func fallbackToolPrompt(tools []domain.ToolDefinition) string {
+	var b strings.Builder
+	b.WriteString("You have access to the following tools:\n\n")
+	for _, tool := range tools {
+		schema, _ := json.Marshal(tool.InputSchema) //nolint:errcheck // Schemas are plain JSON maps
+		fmt.Fprintf(&b, "- %s: %s\n  Parameters (JSON schema): %s\n", tool.Name, tool.Description, schema)
+	}
+	b.WriteString("\nTo call a tool, respond with ONLY a JSON object in this exact format and nothing else:\n")
+	b.WriteString(`{"tool_call": {"name": "<tool name>", "arguments": {<arguments>}}}`)
+	b.WriteString("\nIf no tool is needed, answer the user normally in plain text.")
+	return b.String()
+}
+
+// parseFallbackToolCall extracts a prompt-based tool call from content.
+// It only accepts calls to one of the offered tools.
+func parseFallbackToolCall(content string, tools []domain.ToolDefinition) (*domain.ToolCall, bool) {
+	s := strings.TrimSpace(content)
+	s = strings.TrimPrefix(s, "```json")
+	s = strings.TrimPrefix(s, "```")
+	s = strings.TrimSuffix(s, "```")
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") {
+		return nil, false
+	}
+
+	var parsed fallbackToolCall
+	if err := json.Unmarshal([]byte(s), &parsed); err != nil || parsed.ToolCall == nil {
+		return nil, false
+	}
+
+	for _, tool := range tools {
+		if tool.Name == parsed.ToolCall.Name {
+			args := parsed.ToolCall.Arguments
+			if args == nil {
+				args = map[string]any{}
+			}
+			return &domain.ToolCall{ToolName: tool.Name, Arguments: args}, true
+		}
+	}
+	return nil, false
+}
+
+// mayBeFallbackToolCall reports whether streamed content could still turn out to be
+// a prompt-based tool call, so it must be buffered rather than forwarded.
+func mayBeFallbackToolCall(content string) bool {
+	s := strings.TrimSpace(content)
+	return s == "" || strings.HasPrefix(s, "{") || strings.HasPrefix(s, "`")
+}
+
+// applyToolChoice emulates tool_choice, which Ollama has no parameter for:
+// "none" drops the tools and a specific tool narrows the list to that tool.
+// The request is copied rather than modified.
+func applyToolChoice(req *domain.LLMRequest) *domain.LLMRequest {
+	if req.ToolChoice == nil || len(req.Tools) == 0 {
+		return req
+	}
+
+	var tools []domain.ToolDefinition
+	switch req.ToolChoice.Mode {
+	case domain.ToolChoiceNone:
+	case domain.ToolChoiceTool:
+		for _, tool := range req.Tools {
+			if tool.Name == req.ToolChoice.Name {
+				tools = append(tools, tool)
+			}
+		}
+	default:
+		return req
+	}
+
+	narrowed := *req
+	narrowed.Tools = tools
+	return &narrowed
+}
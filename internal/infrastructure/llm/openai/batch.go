@@ -0,0 +1,171 @@
+package openai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"nuimanbot/internal/domain"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Batch statuses reported by the Batch API
+const (
+	batchStatusCompleted = "completed"
+	batchStatusFailed    = "failed"
+	batchStatusExpired   = "expired"
+	batchStatusCancelled = "cancelled"
+)
+
+// batchOutputLine is one line of a batch output or error file.
+type batchOutputLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int                           `json:"status_code"`
+		Body       openai.ChatCompletionResponse `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// SupportsBatch reports whether the Batch API can be used for provider.
+func (c *Client) SupportsBatch(provider domain.LLMProvider) bool {
+	return provider == domain.LLMProviderOpenAI
+}
+
+// SubmitBatch uploads the requests as a JSONL file and creates a batch over it.
+func (c *Client) SubmitBatch(ctx context.Context, provider domain.LLMProvider, requests []domain.BatchRequest) (string, error) {
+	if provider != domain.LLMProviderOpenAI {
+		return "", fmt.Errorf("OpenAI client cannot handle provider: %s", provider)
+	}
+
+	lines := make([]openai.BatchLineItem, len(requests))
+	for i, req := range requests {
+		lines[i] = openai.BatchChatCompletionRequest{
+			CustomID: req.CustomID,
+			Body:     c.convertRequest(req.Request),
+			Method:   http.MethodPost,
+			URL:      openai.BatchEndpointChatCompletions,
+		}
+	}
+
+	resp, err := c.client.CreateBatchWithUploadFile(ctx, openai.CreateBatchWithUploadFileRequest{
+		Endpoint:               openai.BatchEndpointChatCompletions,
+		UploadBatchFileRequest: openai.UploadBatchFileRequest{Lines: lines},
+	})
+	if err != nil {
+		return "", fmt.Errorf("OpenAI batch submission failed: %w", wrapAPIError(err))
+	}
+	return resp.ID, nil
+}
+
+// GetBatch returns the processing status of a batch.
+func (c *Client) GetBatch(ctx context.Context, provider domain.LLMProvider, batchID string) (*domain.LLMBatchStatus, error) {
+	if provider != domain.LLMProviderOpenAI {
+		return nil, fmt.Errorf("OpenAI client cannot handle provider: %s", provider)
+	}
+
+	batch, err := c.client.RetrieveBatch(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI batch lookup failed: %w", wrapAPIError(err))
+	}
+
+	counts := batch.RequestCounts
+	status := &domain.LLMBatchStatus{
+		Processing: counts.Total - counts.Completed - counts.Failed,
+		Succeeded:  counts.Completed,
+		Failed:     counts.Failed,
+	}
+	switch batch.Status {
+	case batchStatusCompleted, batchStatusFailed, batchStatusExpired, batchStatusCancelled:
+		status.Done = true
+	}
+	return status, nil
+}
+
+// BatchResults reads the output and error files of a finished batch. Requests
+// that never ran (e.g. the batch expired or was cancelled) have no result.
+func (c *Client) BatchResults(ctx context.Context, provider domain.LLMProvider, batchID string) ([]domain.BatchResult, error) {
+	if provider != domain.LLMProviderOpenAI {
+		return nil, fmt.Errorf("OpenAI client cannot handle provider: %s", provider)
+	}
+
+	batch, err := c.client.RetrieveBatch(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI batch lookup failed: %w", wrapAPIError(err))
+	}
+
+	var results []domain.BatchResult
+	for _, fileID := range []*string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == nil || *fileID == "" {
+			continue
+		}
+		fileResults, err := c.readBatchFile(ctx, *fileID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fileResults...)
+	}
+	return results, nil
+}
+
+// CancelBatch cancels a batch. Requests already processed keep their results.
+func (c *Client) CancelBatch(ctx context.Context, provider domain.LLMProvider, batchID string) error {
+	if provider != domain.LLMProviderOpenAI {
+		return fmt.Errorf("OpenAI client cannot handle provider: %s", provider)
+	}
+
+	if _, err := c.client.CancelBatch(ctx, batchID); err != nil {
+		return fmt.Errorf("OpenAI batch cancellation failed: %w", wrapAPIError(err))
+	}
+	return nil
+}
+
+// readBatchFile downloads a batch output or error file and converts each line.
+func (c *Client) readBatchFile(ctx context.Context, fileID string) ([]domain.BatchResult, error) {
+	content, err := c.client.GetFileContent(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download batch file %s: %w", fileID, wrapAPIError(err))
+	}
+	defer content.Close()
+
+	var results []domain.BatchResult
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Responses can be long
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var line batchOutputLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("invalid line in batch file %s: %w", fileID, err)
+		}
+		results = append(results, c.convertBatchLine(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch file %s: %w", fileID, err)
+	}
+	return results, nil
+}
+
+// convertBatchLine converts a single batch output line.
+func (c *Client) convertBatchLine(line batchOutputLine) domain.BatchResult {
+	result := domain.BatchResult{CustomID: line.CustomID, Status: domain.BatchItemFailed}
+	switch {
+	case line.Error != nil:
+		result.Error = line.Error.Message
+	case line.Response == nil:
+		result.Error = "no response"
+	case line.Response.StatusCode != http.StatusOK:
+		result.Error = fmt.Sprintf("request failed with status %d", line.Response.StatusCode)
+	default:
+		result.Status = domain.BatchItemSucceeded
+		result.Response = c.convertResponse(&line.Response.Body)
+	}
+	return result
+}
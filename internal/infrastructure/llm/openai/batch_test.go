@@ -0,0 +1,88 @@
+package openai_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/config"
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/llm/openai"
+)
+
+func TestBatch_SubmitAndResults(t *testing.T) {
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/files":
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("Expected multipart file upload: %v", err)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			uploaded = string(data)
+			_, _ = w.Write([]byte(`{"id": "file-in", "object": "file", "purpose": "batch"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/batches":
+			_, _ = w.Write([]byte(`{"id": "batch_1", "object": "batch", "status": "validating"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/batches/batch_1":
+			_, _ = w.Write([]byte(`{"id": "batch_1", "object": "batch", "status": "completed",
+				"output_file_id": "file-out", "error_file_id": "file-err",
+				"request_counts": {"total": 2, "completed": 1, "failed": 1}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/files/file-out/content":
+			_, _ = w.Write([]byte(`{"custom_id": "a", "response": {"status_code": 200, "body": {"choices": [{"message": {"role": "assistant", "content": "Summary A"}, "finish_reason": "length"}], "usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}}}, "error": null}` + "\n"))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/files/file-err/content":
+			_, _ = w.Write([]byte(`{"custom_id": "b", "response": null, "error": {"code": "invalid_request", "message": "bad request"}}` + "\n"))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := openai.New(&config.OpenAIProviderConfig{
+		APIKey:  domain.NewSecureStringFromString("test-key"),
+		BaseURL: server.URL + "/v1",
+	})
+	ctx := context.Background()
+
+	batchID, err := client.SubmitBatch(ctx, domain.LLMProviderOpenAI, []domain.BatchRequest{
+		{CustomID: "a", Request: &domain.LLMRequest{Model: "gpt-4o-mini", Messages: []domain.Message{{Role: "user", Content: "one"}}}},
+		{CustomID: "b", Request: &domain.LLMRequest{Model: "gpt-4o-mini", Messages: []domain.Message{{Role: "user", Content: "two"}}}},
+	})
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	if batchID != "batch_1" {
+		t.Errorf("Batch ID = %q, want batch_1", batchID)
+	}
+	if lines := strings.Split(uploaded, "\n"); len(lines) != 2 || !strings.Contains(lines[0], `"custom_id":"a"`) || !strings.Contains(lines[0], `"url":"/v1/chat/completions"`) {
+		t.Errorf("Unexpected uploaded batch file: %s", uploaded)
+	}
+
+	status, err := client.GetBatch(ctx, domain.LLMProviderOpenAI, batchID)
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	if !status.Done || status.Succeeded != 1 || status.Failed != 1 || status.Processing != 0 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	results, err := client.BatchResults(ctx, domain.LLMProviderOpenAI, batchID)
+	if err != nil {
+		t.Fatalf("BatchResults failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Got %d results, want 2", len(results))
+	}
+	if results[0].Status != domain.BatchItemSucceeded || results[0].Response.Content != "Summary A" || results[0].Response.FinishReason != domain.FinishReasonLength {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if results[1].Status != domain.BatchItemFailed || results[1].Error != "bad request" {
+		t.Errorf("Unexpected second result: %+v", results[1])
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"nuimanbot/internal/config"
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/metrics"
 
 	openai "github.com/sashabaranov/go-openai"
 )
@@ -51,11 +52,13 @@ func (c *Client) Complete(ctx context.Context, provider domain.LLMProvider, req
 	// Make API call
 	resp, err := c.client.CreateChatCompletion(ctx, oaiReq)
 	if err != nil {
-		return nil, fmt.Errorf("OpenAI API error: %w", err)
+		return nil, fmt.Errorf("OpenAI API error: %w", wrapAPIError(err))
 	}
 
 	// Convert response to domain.LLMResponse
-	return c.convertResponse(&resp), nil
+	result := c.convertResponse(&resp)
+	metrics.RecordLLMUsage(string(domain.LLMProviderOpenAI), oaiReq.Model, result.Usage)
+	return result, nil
 }
 
 // Stream performs a streaming completion request to the OpenAI API.
@@ -71,7 +74,7 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 	// Create stream
 	stream, err := c.client.CreateChatCompletionStream(ctx, oaiReq)
 	if err != nil {
-		return nil, fmt.Errorf("OpenAI API streaming error: %w", err)
+		return nil, fmt.Errorf("OpenAI API streaming error: %w", wrapAPIError(err))
 	}
 
 	// Create output channel
@@ -91,7 +94,7 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 					return
 				}
 				// Send error chunk
-				outChan <- domain.StreamChunk{Error: fmt.Errorf("stream error: %w", err)}
+				outChan <- domain.StreamChunk{Error: fmt.Errorf("stream error: %w", wrapAPIError(err))}
 				return
 			}
 
@@ -99,6 +102,11 @@ func (c *Client) Stream(ctx context.Context, provider domain.LLMProvider, req *d
 			if len(resp.Choices) > 0 {
 				delta := resp.Choices[0].Delta
 
+				// Send reasoning delta if present (reasoning-capable compatible APIs)
+				if delta.ReasoningContent != "" {
+					outChan <- domain.StreamChunk{Kind: domain.StreamChunkThinking, Delta: delta.ReasoningContent}
+				}
+
 				// Send content delta if present
 				if delta.Content != "" {
 					outChan <- domain.StreamChunk{Delta: delta.Content}
@@ -196,22 +204,103 @@ func (c *Client) convertRequest(req *domain.LLMRequest) openai.ChatCompletionReq
 		Messages: messages,
 	}
 
-	// Set optional parameters
-	if req.MaxTokens > 0 {
-		oaiReq.MaxTokens = req.MaxTokens
-	}
-	if req.Temperature > 0 {
-		oaiReq.Temperature = float32(req.Temperature)
+	// Set optional parameters. Reasoning models only accept
+	// max_completion_tokens and reject custom temperatures.
+	if req.Reasoning.Enabled() {
+		oaiReq.ReasoningEffort = string(req.Reasoning.EffortLevel())
+		if req.MaxTokens > 0 {
+			oaiReq.MaxCompletionTokens = req.MaxTokens
+		}
+	} else {
+		if req.MaxTokens > 0 {
+			oaiReq.MaxTokens = req.MaxTokens
+		}
+		if req.Temperature > 0 {
+			oaiReq.Temperature = float32(req.Temperature)
+		}
+		if req.TopP > 0 {
+			oaiReq.TopP = float32(req.TopP)
+		}
 	}
+	oaiReq.Stop = req.StopSequences
+	oaiReq.Seed = req.Seed
 
 	// Convert tools if provided
 	if len(req.Tools) > 0 {
 		oaiReq.Tools = c.convertTools(req.Tools)
+		if req.ToolChoice != nil {
+			oaiReq.ToolChoice = convertToolChoice(req.ToolChoice)
+		}
+		if req.ParallelToolCalls != nil {
+			oaiReq.ParallelToolCalls = *req.ParallelToolCalls
+		}
+	}
+
+	// Constrain output format if requested
+	if req.ResponseFormat != nil {
+		oaiReq.ResponseFormat = convertResponseFormat(req.ResponseFormat)
 	}
 
 	return oaiReq
 }
 
+// convertToolChoice converts domain.ToolChoice to OpenAI tool_choice
+// ("auto", "none", "required" or a specific function).
+func convertToolChoice(choice *domain.ToolChoice) any {
+	if choice.Mode == domain.ToolChoiceTool {
+		return openai.ToolChoice{
+			Type:     openai.ToolTypeFunction,
+			Function: openai.ToolFunction{Name: choice.Name},
+		}
+	}
+	return string(choice.Mode)
+}
+
+// convertFinishReason maps OpenAI finish reasons onto domain finish reasons.
+func convertFinishReason(reason openai.FinishReason) domain.FinishReason {
+	switch reason {
+	case openai.FinishReasonStop:
+		return domain.FinishReasonStop
+	case openai.FinishReasonLength:
+		return domain.FinishReasonLength
+	case openai.FinishReasonToolCalls, openai.FinishReasonFunctionCall:
+		return domain.FinishReasonToolCalls
+	case openai.FinishReasonContentFilter:
+		return domain.FinishReasonContentFilter
+	case "":
+		return ""
+	default:
+		return domain.FinishReasonOther
+	}
+}
+
+// jsonSchema adapts a map-based JSON schema to the json.Marshaler the SDK expects.
+type jsonSchema map[string]any
+
+// MarshalJSON implements json.Marshaler.
+func (s jsonSchema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any(s))
+}
+
+// convertResponseFormat converts domain.ResponseFormat to OpenAI response_format.
+func convertResponseFormat(format *domain.ResponseFormat) *openai.ChatCompletionResponseFormat {
+	switch format.Type {
+	case domain.ResponseFormatJSONObject:
+		return &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+	case domain.ResponseFormatJSONSchema:
+		return &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   format.SchemaName(),
+				Schema: jsonSchema(format.JSONSchema()),
+				Strict: format.Strict,
+			},
+		}
+	default:
+		return &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeText}
+	}
+}
+
 // convertTools converts domain.ToolDefinition to openai.Tool
 func (c *Client) convertTools(tools []domain.ToolDefinition) []openai.Tool {
 	oaiTools := make([]openai.Tool, len(tools))
@@ -238,11 +327,21 @@ func (c *Client) convertResponse(resp *openai.ChatCompletionResponse) *domain.LL
 		},
 	}
 
+	// OpenAI caches long prompt prefixes automatically and reports the hits
+	if resp.Usage.PromptTokensDetails != nil {
+		result.Usage.CacheReadTokens = resp.Usage.PromptTokensDetails.CachedTokens
+	}
+
 	// Extract content and tool calls from first choice
 	if len(resp.Choices) > 0 {
 		choice := resp.Choices[0]
 		result.Content = choice.Message.Content
-		result.FinishReason = string(choice.FinishReason)
+		result.FinishReason = convertFinishReason(choice.FinishReason)
+
+		// OpenAI keeps reasoning hidden; compatible APIs (e.g. DeepSeek) return it
+		if choice.Message.ReasoningContent != "" {
+			result.Reasoning = []domain.ReasoningBlock{{Text: choice.Message.ReasoningContent}}
+		}
 
 		// Convert tool calls if present
 		if len(choice.Message.ToolCalls) > 0 {
@@ -1,6 +1,8 @@
 package openai
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"nuimanbot/internal/config"
@@ -192,7 +194,7 @@ func TestConvertResponse(t *testing.T) {
 		name             string
 		resp             *openai.ChatCompletionResponse
 		wantContent      string
-		wantFinishReason string
+		wantFinishReason domain.FinishReason
 		wantToolCalls    int
 		wantPromptTokens int
 		wantCompTokens   int
@@ -382,3 +384,122 @@ func TestConvertToolCalls(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertRequest_Reasoning(t *testing.T) {
+	client := New(&config.OpenAIProviderConfig{APIKey: domain.NewSecureStringFromString("sk-test")})
+
+	oaiReq := client.convertRequest(&domain.LLMRequest{
+		Model:       "o3-mini",
+		MaxTokens:   2000,
+		Temperature: 0.7,
+		Messages:    []domain.Message{{Role: "user", Content: "Hello"}},
+		Reasoning:   domain.ReasoningConfig{BudgetTokens: 16000},
+	})
+
+	if oaiReq.ReasoningEffort != "high" {
+		t.Errorf("Expected reasoning effort 'high', got %q", oaiReq.ReasoningEffort)
+	}
+	if oaiReq.MaxCompletionTokens != 2000 || oaiReq.MaxTokens != 0 {
+		t.Errorf("Expected max_completion_tokens=2000 and no max_tokens, got %d/%d", oaiReq.MaxCompletionTokens, oaiReq.MaxTokens)
+	}
+	if oaiReq.Temperature != 0 {
+		t.Errorf("Expected temperature omitted for reasoning models, got %v", oaiReq.Temperature)
+	}
+}
+
+func TestConvertResponse_ReasoningContent(t *testing.T) {
+	client := New(&config.OpenAIProviderConfig{APIKey: domain.NewSecureStringFromString("sk-test")})
+
+	result := client.convertResponse(&openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{
+			Message: openai.ChatCompletionMessage{Content: "4", ReasoningContent: "2+2=4"},
+		}},
+	})
+
+	if result.ReasoningText() != "2+2=4" {
+		t.Errorf("Expected reasoning '2+2=4', got %q", result.ReasoningText())
+	}
+}
+
+func TestConvertRequest_ResponseFormat(t *testing.T) {
+	client := New(&config.OpenAIProviderConfig{APIKey: domain.NewSecureStringFromString("sk-test")})
+
+	oaiReq := client.convertRequest(&domain.LLMRequest{
+		Model:    "gpt-4o",
+		Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+		ResponseFormat: &domain.ResponseFormat{
+			Type:   domain.ResponseFormatJSONSchema,
+			Name:   "greeting",
+			Schema: map[string]any{"type": "object"},
+			Strict: true,
+		},
+	})
+
+	if oaiReq.ResponseFormat == nil || oaiReq.ResponseFormat.Type != openai.ChatCompletionResponseFormatTypeJSONSchema {
+		t.Fatalf("Expected json_schema response format, got %+v", oaiReq.ResponseFormat)
+	}
+	data, err := json.Marshal(oaiReq.ResponseFormat)
+	if err != nil {
+		t.Fatalf("Failed to marshal response format: %v", err)
+	}
+	want := `{"type":"json_schema","json_schema":{"name":"greeting","schema":{"type":"object"},"strict":true}}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	oaiReq = client.convertRequest(&domain.LLMRequest{
+		Model:          "gpt-4o",
+		Messages:       []domain.Message{{Role: "user", Content: "Hello"}},
+		ResponseFormat: &domain.ResponseFormat{Type: domain.ResponseFormatJSONObject},
+	})
+	if oaiReq.ResponseFormat.Type != openai.ChatCompletionResponseFormatTypeJSONObject {
+		t.Errorf("Expected json_object response format, got %q", oaiReq.ResponseFormat.Type)
+	}
+}
+
+func TestConvertRequest_SamplingAndToolChoice(t *testing.T) {
+	client := New(&config.OpenAIProviderConfig{APIKey: domain.NewSecureStringFromString("sk-test")})
+	seed := 7
+	parallel := false
+
+	oaiReq := client.convertRequest(&domain.LLMRequest{
+		Model:             "gpt-4o",
+		Messages:          []domain.Message{{Role: "user", Content: "Hello"}},
+		Tools:             []domain.ToolDefinition{{Name: "calculator", InputSchema: map[string]any{"type": "object"}}},
+		ToolChoice:        domain.ForceTool("calculator"),
+		ParallelToolCalls: &parallel,
+		TopP:              0.9,
+		TopK:              40, // Not supported by OpenAI; ignored
+		StopSequences:     []string{"END"},
+		Seed:              &seed,
+	})
+
+	data, err := json.Marshal(oaiReq)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	for _, want := range []string{
+		`"top_p":0.9`,
+		`"stop":["END"]`,
+		`"seed":7`,
+		`"tool_choice":{"type":"function","function":{"name":"calculator"}}`,
+		`"parallel_tool_calls":false`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in request, got %s", want, data)
+		}
+	}
+
+	oaiReq = client.convertRequest(&domain.LLMRequest{
+		Model:      "gpt-4o",
+		Messages:   []domain.Message{{Role: "user", Content: "Hello"}},
+		Tools:      []domain.ToolDefinition{{Name: "calculator"}},
+		ToolChoice: &domain.ToolChoice{Mode: domain.ToolChoiceRequired},
+	})
+	if oaiReq.ToolChoice != "required" {
+		t.Errorf("Expected tool_choice 'required', got %v", oaiReq.ToolChoice)
+	}
+	if oaiReq.ParallelToolCalls != nil {
+		t.Errorf("Expected parallel_tool_calls unset, got %v", oaiReq.ParallelToolCalls)
+	}
+}
@@ -0,0 +1,25 @@
+package openai
+
+import (
+	"errors"
+	"net/http"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"nuimanbot/internal/domain"
+)
+
+// wrapAPIError converts 429 responses into domain.RateLimitError so that
+// callers such as key pools can back off; other errors are returned unchanged.
+// The SDK does not expose response headers on errors, so RetryAfter is unknown.
+func wrapAPIError(err error) error {
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.HTTPStatusCode == http.StatusTooManyRequests:
+	case errors.As(err, &reqErr) && reqErr.HTTPStatusCode == http.StatusTooManyRequests:
+	default:
+		return err
+	}
+	return &domain.RateLimitError{Provider: domain.LLMProviderOpenAI, Cause: err}
+}
@@ -0,0 +1,227 @@
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nuimanbot/internal/domain"
+)
+
+// Interaction kinds stored in cassettes.
+const (
+	KindComplete = "complete"
+	KindStream   = "stream"
+)
+
+// Cassette is a single recorded LLM interaction.
+type Cassette struct {
+	Key      string             `json:"key"`
+	Kind     string             `json:"kind"`
+	Provider domain.LLMProvider `json:"provider"`
+	Request  NormalizedRequest  `json:"request"`
+	Response *RecordedResponse  `json:"response,omitempty"`
+	Chunks   []RecordedChunk    `json:"chunks,omitempty"`
+	Error    string             `json:"error,omitempty"`
+}
+
+// NormalizedRequest is the provider-independent part of an LLMRequest that
+// determines the response. Transport hints such as cache breakpoints are excluded
+// so that they don't invalidate recordings.
+type NormalizedRequest struct {
+	Model          string                  `json:"model,omitempty"`
+	SystemPrompt   string                  `json:"system_prompt,omitempty"`
+	Messages       []NormalizedMessage     `json:"messages"`
+	MaxTokens      int                     `json:"max_tokens,omitempty"`
+	Temperature    float64                 `json:"temperature,omitempty"`
+	Tools          []domain.ToolDefinition `json:"tools,omitempty"`
+	Reasoning      *domain.ReasoningConfig `json:"reasoning,omitempty"`
+	ResponseFormat *domain.ResponseFormat  `json:"response_format,omitempty"`
+
+	ToolChoice        *domain.ToolChoice `json:"tool_choice,omitempty"`
+	ParallelToolCalls *bool              `json:"parallel_tool_calls,omitempty"`
+	TopP              float64            `json:"top_p,omitempty"`
+	TopK              int                `json:"top_k,omitempty"`
+	StopSequences     []string           `json:"stop_sequences,omitempty"`
+	Seed              *int               `json:"seed,omitempty"`
+}
+
+// NormalizedMessage is a conversation message without provider signatures.
+type NormalizedMessage struct {
+	Role      string `json:"role"`
+	Content   string `json:"content"`
+	Reasoning string `json:"reasoning,omitempty"`
+}
+
+// RecordedResponse is a serializable LLMResponse.
+type RecordedResponse struct {
+	Content      string                  `json:"content"`
+	ToolCalls    []domain.ToolCall       `json:"tool_calls,omitempty"`
+	Reasoning    []domain.ReasoningBlock `json:"reasoning,omitempty"`
+	Usage        domain.TokenUsage       `json:"usage"`
+	FinishReason domain.FinishReason     `json:"finish_reason,omitempty"`
+}
+
+// RecordedChunk is a serializable StreamChunk.
+type RecordedChunk struct {
+	Kind      domain.StreamChunkKind `json:"kind,omitempty"`
+	Delta     string                 `json:"delta,omitempty"`
+	ToolCall  *domain.ToolCall       `json:"tool_call,omitempty"`
+	Done      bool                   `json:"done,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	Signature string                 `json:"signature,omitempty"`
+}
+
+// Normalize converts a request into its stable, comparable form.
+func Normalize(req *domain.LLMRequest) NormalizedRequest {
+	n := NormalizedRequest{
+		Model:          req.Model,
+		SystemPrompt:   strings.TrimSpace(req.SystemPrompt),
+		Messages:       make([]NormalizedMessage, len(req.Messages)),
+		MaxTokens:      req.MaxTokens,
+		Temperature:    req.Temperature,
+		ResponseFormat: req.ResponseFormat,
+
+		ToolChoice:        req.ToolChoice,
+		ParallelToolCalls: req.ParallelToolCalls,
+		TopP:              req.TopP,
+		TopK:              req.TopK,
+		StopSequences:     req.StopSequences,
+		Seed:              req.Seed,
+	}
+
+	for i, msg := range req.Messages {
+		n.Messages[i] = NormalizedMessage{
+			Role:      msg.Role,
+			Content:   strings.TrimSpace(msg.Content),
+			Reasoning: domain.JoinReasoning(msg.Reasoning),
+		}
+	}
+
+	// Tool order is registry iteration order, which is not stable
+	if len(req.Tools) > 0 {
+		n.Tools = append([]domain.ToolDefinition(nil), req.Tools...)
+		sort.Slice(n.Tools, func(i, j int) bool { return n.Tools[i].Name < n.Tools[j].Name })
+	}
+
+	if req.Reasoning.Enabled() {
+		reasoning := req.Reasoning
+		n.Reasoning = &reasoning
+	}
+
+	return n
+}
+
+// Key returns the stable hash identifying a request of the given kind.
+func Key(kind string, provider domain.LLMProvider, req NormalizedRequest) string {
+	// encoding/json sorts map keys, so schemas hash deterministically
+	data, _ := json.Marshal(req) //nolint:errcheck // NormalizedRequest only holds JSON-safe values
+	sum := sha256.Sum256([]byte(kind + "\x00" + string(provider) + "\x00" + string(data)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func newRecordedResponse(resp *domain.LLMResponse) *RecordedResponse {
+	return &RecordedResponse{
+		Content:      resp.Content,
+		ToolCalls:    resp.ToolCalls,
+		Reasoning:    resp.Reasoning,
+		Usage:        resp.Usage,
+		FinishReason: resp.FinishReason,
+	}
+}
+
+func (r *RecordedResponse) toDomain() *domain.LLMResponse {
+	return &domain.LLMResponse{
+		Content:      r.Content,
+		ToolCalls:    r.ToolCalls,
+		Reasoning:    r.Reasoning,
+		Usage:        r.Usage,
+		FinishReason: r.FinishReason,
+	}
+}
+
+func newRecordedChunk(chunk domain.StreamChunk) RecordedChunk {
+	rc := RecordedChunk{
+		Kind:      chunk.Kind,
+		Delta:     chunk.Delta,
+		ToolCall:  chunk.ToolCall,
+		Done:      chunk.Done,
+		Signature: chunk.Signature,
+	}
+	if chunk.Error != nil {
+		rc.Error = chunk.Error.Error()
+	}
+	return rc
+}
+
+func (c RecordedChunk) toDomain() domain.StreamChunk {
+	chunk := domain.StreamChunk{
+		Kind:      c.Kind,
+		Delta:     c.Delta,
+		ToolCall:  c.ToolCall,
+		Done:      c.Done,
+		Signature: c.Signature,
+	}
+	if c.Error != "" {
+		chunk.Error = fmt.Errorf("%s", c.Error)
+	}
+	return chunk
+}
+
+// store reads and writes cassettes as one JSON file per interaction.
+type store struct {
+	dir string
+}
+
+func (s *store) path(kind, key string) string {
+	return filepath.Join(s.dir, kind+"-"+key+".json")
+}
+
+func (s *store) load(kind, key string) (*Cassette, error) {
+	data, err := os.ReadFile(s.path(kind, key))
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", s.path(kind, key), err)
+	}
+	return &c, nil
+}
+
+func (s *store) save(c *Cassette) error {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	return os.WriteFile(s.path(c.Kind, c.Key), append(data, '\n'), 0o600)
+}
+
+// all returns every cassette of the given kind.
+func (s *store) all(kind string) ([]*Cassette, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, kind+"-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	cassettes := make([]*Cassette, 0, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		var c Cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			continue // skip unreadable cassettes when looking for the closest match
+		}
+		cassettes = append(cassettes, &c)
+	}
+	return cassettes, nil
+}
@@ -0,0 +1,76 @@
+package replay
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// requestLines renders a normalized request as indented JSON lines for diffing.
+func requestLines(req NormalizedRequest) []string {
+	data, _ := json.MarshalIndent(req, "", "  ") //nolint:errcheck // NormalizedRequest only holds JSON-safe values
+	return strings.Split(string(data), "\n")
+}
+
+// lineDiff returns a unified-style diff of two line slices ("-" recorded, "+" actual)
+// along with the number of changed lines. Unchanged lines are elided except for
+// one line of context around each change.
+func lineDiff(recorded, actual []string) (string, int) {
+	// Longest common subsequence table
+	n, m := len(recorded), len(actual)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if recorded[i] == actual[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type op struct {
+		prefix string
+		line   string
+	}
+	var ops []op
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && recorded[i] == actual[j]:
+			ops = append(ops, op{" ", recorded[i]})
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			ops = append(ops, op{"+", actual[j]})
+			j++
+		default:
+			ops = append(ops, op{"-", recorded[i]})
+			i++
+		}
+	}
+
+	changed := 0
+	var b strings.Builder
+	elided := false
+	for k, o := range ops {
+		if o.prefix != " " {
+			changed++
+		}
+		near := o.prefix != " " ||
+			(k > 0 && ops[k-1].prefix != " ") ||
+			(k+1 < len(ops) && ops[k+1].prefix != " ")
+		if !near {
+			if !elided {
+				b.WriteString("  ...\n")
+				elided = true
+			}
+			continue
+		}
+		elided = false
+		b.WriteString(o.prefix + " " + o.line + "\n")
+	}
+	return b.String(), changed
+}
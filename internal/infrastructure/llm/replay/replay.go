@@ -0,0 +1,211 @@
+// Package replay provides a record/replay LLM provider. In record mode it proxies
+// requests to a real provider and writes each interaction to a cassette file; in
+// replay mode it serves responses from those cassettes without network access.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"nuimanbot/internal/domain"
+)
+
+// Mode selects whether the provider records or replays interactions.
+type Mode string
+
+const (
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+// ErrCassetteNotFound is returned in replay mode when no cassette matches a request.
+var ErrCassetteNotFound = errors.New("no recorded cassette matches request")
+
+// MismatchError explains a replay miss by diffing the request against the
+// closest recorded cassette.
+type MismatchError struct {
+	Kind    string
+	Key     string
+	Closest string // Key of the closest cassette, empty if none were recorded
+	Diff    string
+}
+
+// Error implements the error interface.
+func (e *MismatchError) Error() string {
+	if e.Closest == "" {
+		return fmt.Sprintf("%s: %s request %s (no cassettes recorded)", ErrCassetteNotFound, e.Kind, e.Key)
+	}
+	return fmt.Sprintf("%s: %s request %s; closest cassette %s differs:\n%s", ErrCassetteNotFound, e.Kind, e.Key, e.Closest, e.Diff)
+}
+
+// Unwrap allows errors.Is(err, ErrCassetteNotFound).
+func (e *MismatchError) Unwrap() error {
+	return ErrCassetteNotFound
+}
+
+// Service implements domain.LLMService on top of recorded cassettes.
+type Service struct {
+	mode       Mode
+	store      *store
+	underlying domain.LLMService // Real provider; only used in record mode
+}
+
+// New creates a replay provider storing cassettes in dir.
+// underlying is required in record mode and ignored in replay mode.
+func New(mode Mode, dir string, underlying domain.LLMService) (*Service, error) {
+	switch mode {
+	case ModeRecord:
+		if underlying == nil {
+			return nil, fmt.Errorf("record mode requires an underlying LLM provider")
+		}
+	case ModeReplay:
+	default:
+		return nil, fmt.Errorf("invalid replay mode %q (expected %q or %q)", mode, ModeRecord, ModeReplay)
+	}
+	if dir == "" {
+		return nil, fmt.Errorf("cassette directory is required")
+	}
+
+	return &Service{
+		mode:       mode,
+		store:      &store{dir: dir},
+		underlying: underlying,
+	}, nil
+}
+
+// Complete records or replays a completion.
+func (s *Service) Complete(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+	normalized := Normalize(req)
+	key := Key(KindComplete, provider, normalized)
+
+	if s.mode == ModeReplay {
+		c, err := s.lookup(KindComplete, key, normalized)
+		if err != nil {
+			return nil, err
+		}
+		if c.Error != "" {
+			return nil, errors.New(c.Error)
+		}
+		return c.Response.toDomain(), nil
+	}
+
+	resp, err := s.underlying.Complete(ctx, provider, req)
+
+	c := &Cassette{Key: key, Kind: KindComplete, Provider: provider, Request: normalized}
+	if err != nil {
+		c.Error = err.Error()
+	} else {
+		c.Response = newRecordedResponse(resp)
+	}
+	if saveErr := s.store.save(c); saveErr != nil {
+		slog.Warn("Failed to save LLM cassette", "key", key, "error", saveErr)
+	}
+
+	return resp, err
+}
+
+// Stream records or replays a streaming completion.
+func (s *Service) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+	normalized := Normalize(req)
+	key := Key(KindStream, provider, normalized)
+
+	if s.mode == ModeReplay {
+		c, err := s.lookup(KindStream, key, normalized)
+		if err != nil {
+			return nil, err
+		}
+		if c.Error != "" {
+			return nil, errors.New(c.Error)
+		}
+
+		outChan := make(chan domain.StreamChunk, len(c.Chunks))
+		for _, chunk := range c.Chunks {
+			outChan <- chunk.toDomain()
+		}
+		close(outChan)
+		return outChan, nil
+	}
+
+	c := &Cassette{Key: key, Kind: KindStream, Provider: provider, Request: normalized}
+
+	stream, err := s.underlying.Stream(ctx, provider, req)
+	if err != nil {
+		c.Error = err.Error()
+		if saveErr := s.store.save(c); saveErr != nil {
+			slog.Warn("Failed to save LLM cassette", "key", key, "error", saveErr)
+		}
+		return nil, err
+	}
+
+	// Tee chunks to the caller and save the cassette once the stream ends
+	outChan := make(chan domain.StreamChunk, 10)
+	go func() {
+		defer close(outChan)
+		for chunk := range stream {
+			c.Chunks = append(c.Chunks, newRecordedChunk(chunk))
+			outChan <- chunk
+		}
+		if saveErr := s.store.save(c); saveErr != nil {
+			slog.Warn("Failed to save LLM cassette", "key", key, "error", saveErr)
+		}
+	}()
+
+	return outChan, nil
+}
+
+// ListModels proxies to the underlying provider in record mode and returns the
+// models referenced by recorded cassettes in replay mode.
+func (s *Service) ListModels(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
+	if s.mode == ModeRecord {
+		return s.underlying.ListModels(ctx, provider)
+	}
+
+	seen := make(map[string]bool)
+	var models []domain.ModelInfo
+	for _, kind := range []string{KindComplete, KindStream} {
+		cassettes, err := s.store.all(kind)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cassettes {
+			if c.Provider != provider || c.Request.Model == "" || seen[c.Request.Model] {
+				continue
+			}
+			seen[c.Request.Model] = true
+			models = append(models, domain.ModelInfo{ID: c.Request.Model, Name: c.Request.Model, Provider: string(provider)})
+		}
+	}
+	return models, nil
+}
+
+// lookup loads the cassette for key, or builds a MismatchError against the closest recording.
+func (s *Service) lookup(kind, key string, req NormalizedRequest) (*Cassette, error) {
+	c, err := s.store.load(kind, key)
+	if err == nil {
+		return c, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	cassettes, err := s.store.all(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatch := &MismatchError{Kind: kind, Key: key}
+	actual := requestLines(req)
+	best := -1
+	for _, candidate := range cassettes {
+		diff, changed := lineDiff(requestLines(candidate.Request), actual)
+		if best == -1 || changed < best {
+			best = changed
+			mismatch.Closest = candidate.Key
+			mismatch.Diff = diff
+		}
+	}
+	return nil, mismatch
+}
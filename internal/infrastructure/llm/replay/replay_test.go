@@ -0,0 +1,187 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/domain"
+)
+
+// fakeProvider is a scripted upstream provider used while recording.
+type fakeProvider struct {
+	calls int
+}
+
+func (f *fakeProvider) Complete(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+	f.calls++
+	last := req.Messages[len(req.Messages)-1].Content
+	if last == "fail" {
+		return nil, errors.New("upstream unavailable")
+	}
+	return &domain.LLMResponse{
+		Content:      "echo: " + last,
+		ToolCalls:    []domain.ToolCall{{ToolName: "calculator", Arguments: map[string]any{"expression": "2+2"}}},
+		Usage:        domain.TokenUsage{PromptTokens: 10, CompletionTokens: 3, TotalTokens: 13},
+		FinishReason: domain.FinishReasonToolCalls,
+	}, nil
+}
+
+func (f *fakeProvider) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+	f.calls++
+	ch := make(chan domain.StreamChunk, 4)
+	ch <- domain.StreamChunk{Kind: domain.StreamChunkThinking, Delta: "hmm"}
+	ch <- domain.StreamChunk{Delta: "Hello"}
+	ch <- domain.StreamChunk{Delta: " world"}
+	ch <- domain.StreamChunk{Done: true}
+	close(ch)
+	return ch, nil
+}
+
+func (f *fakeProvider) ListModels(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
+	return []domain.ModelInfo{{ID: "gpt-4o"}}, nil
+}
+
+func newRequest(content string) *domain.LLMRequest {
+	return &domain.LLMRequest{
+		Model:        "gpt-4o",
+		SystemPrompt: "You are helpful",
+		Messages:     []domain.Message{{Role: "user", Content: content}},
+		Tools: []domain.ToolDefinition{
+			{Name: "weather", InputSchema: map[string]any{"type": "object"}},
+			{Name: "calculator", InputSchema: map[string]any{"type": "object"}},
+		},
+	}
+}
+
+func TestRecordAndReplay_Complete(t *testing.T) {
+	dir := t.TempDir()
+	upstream := &fakeProvider{}
+
+	recorder, err := New(ModeRecord, dir, upstream)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	recorded, err := recorder.Complete(context.Background(), domain.LLMProviderOpenAI, newRequest("hi"))
+	if err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+
+	player, err := New(ModeReplay, dir, nil)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	// Tool order and cache hints must not affect matching
+	req := newRequest("hi")
+	req.Tools[0], req.Tools[1] = req.Tools[1], req.Tools[0]
+	req.Cache = domain.CachePolicy{SystemPrompt: true}
+
+	replayed, err := player.Complete(context.Background(), domain.LLMProviderOpenAI, req)
+	if err != nil {
+		t.Fatalf("replay Complete() error: %v", err)
+	}
+
+	if replayed.Content != recorded.Content || replayed.FinishReason != recorded.FinishReason || replayed.Usage != recorded.Usage {
+		t.Errorf("Replayed response %+v differs from recorded %+v", replayed, recorded)
+	}
+	if len(replayed.ToolCalls) != 1 || replayed.ToolCalls[0].Arguments["expression"] != "2+2" {
+		t.Errorf("Expected recorded tool call, got %+v", replayed.ToolCalls)
+	}
+	if upstream.calls != 1 {
+		t.Errorf("Expected replay to skip the upstream provider, got %d calls", upstream.calls)
+	}
+}
+
+func TestRecordAndReplay_Error(t *testing.T) {
+	dir := t.TempDir()
+	recorder, _ := New(ModeRecord, dir, &fakeProvider{})
+	if _, err := recorder.Complete(context.Background(), domain.LLMProviderOpenAI, newRequest("fail")); err == nil {
+		t.Fatal("Expected upstream error while recording")
+	}
+
+	player, _ := New(ModeReplay, dir, nil)
+	_, err := player.Complete(context.Background(), domain.LLMProviderOpenAI, newRequest("fail"))
+	if err == nil || err.Error() != "upstream unavailable" {
+		t.Errorf("Expected recorded error, got %v", err)
+	}
+}
+
+func TestRecordAndReplay_Stream(t *testing.T) {
+	dir := t.TempDir()
+	recorder, _ := New(ModeRecord, dir, &fakeProvider{})
+
+	stream, err := recorder.Stream(context.Background(), domain.LLMProviderOpenAI, newRequest("hi"))
+	if err != nil {
+		t.Fatalf("Stream() error: %v", err)
+	}
+	for range stream {
+		// drain so the cassette is written
+	}
+
+	player, _ := New(ModeReplay, dir, nil)
+	stream, err = player.Stream(context.Background(), domain.LLMProviderOpenAI, newRequest("hi"))
+	if err != nil {
+		t.Fatalf("replay Stream() error: %v", err)
+	}
+
+	var text, thinking string
+	done := false
+	for chunk := range stream {
+		if chunk.IsThinking() {
+			thinking += chunk.Delta
+		} else {
+			text += chunk.Delta
+		}
+		done = done || chunk.Done
+	}
+	if text != "Hello world" || thinking != "hmm" || !done {
+		t.Errorf("Unexpected replayed stream: text=%q thinking=%q done=%v", text, thinking, done)
+	}
+}
+
+func TestReplay_MismatchDiff(t *testing.T) {
+	dir := t.TempDir()
+	recorder, _ := New(ModeRecord, dir, &fakeProvider{})
+	if _, err := recorder.Complete(context.Background(), domain.LLMProviderOpenAI, newRequest("What is 2+2?")); err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+
+	player, _ := New(ModeReplay, dir, nil)
+	_, err := player.Complete(context.Background(), domain.LLMProviderOpenAI, newRequest("What is 3+3?"))
+	if !errors.Is(err, ErrCassetteNotFound) {
+		t.Fatalf("Expected ErrCassetteNotFound, got %v", err)
+	}
+
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) || mismatch.Closest == "" {
+		t.Fatalf("Expected MismatchError with a closest cassette, got %v", err)
+	}
+	if !strings.Contains(mismatch.Diff, `-       "content": "What is 2+2?"`) || !strings.Contains(mismatch.Diff, `+       "content": "What is 3+3?"`) {
+		t.Errorf("Expected content diff, got:\n%s", mismatch.Diff)
+	}
+	if strings.Contains(mismatch.Diff, "weather") {
+		t.Errorf("Expected unchanged lines to be elided, got:\n%s", mismatch.Diff)
+	}
+}
+
+func TestReplay_NoCassettes(t *testing.T) {
+	player, _ := New(ModeReplay, t.TempDir(), nil)
+	_, err := player.Complete(context.Background(), domain.LLMProviderOpenAI, newRequest("hi"))
+	if !errors.Is(err, ErrCassetteNotFound) || !strings.Contains(err.Error(), "no cassettes recorded") {
+		t.Errorf("Expected empty-directory error, got %v", err)
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	if _, err := New(ModeRecord, t.TempDir(), nil); err == nil {
+		t.Error("Expected error for record mode without a provider")
+	}
+	if _, err := New("rewind", t.TempDir(), nil); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+	if _, err := New(ModeReplay, "", nil); err == nil {
+		t.Error("Expected error for missing cassette directory")
+	}
+}
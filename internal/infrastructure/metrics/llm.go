@@ -0,0 +1,27 @@
+package metrics
+
+import "nuimanbot/internal/domain"
+
+// Token type labels for LLMTokensUsed.
+const (
+	TokenTypePrompt        = "prompt"
+	TokenTypeCompletion    = "completion"
+	TokenTypeCacheCreation = "cache_creation"
+	TokenTypeCacheRead     = "cache_read"
+)
+
+// RecordLLMUsage records token usage for a completed LLM request.
+// Prompt tokens are recorded net of cache writes and reads so that each
+// token is counted once under the rate it is billed at.
+func RecordLLMUsage(provider, model string, usage domain.TokenUsage) {
+	add := func(tokenType string, n int) {
+		if n > 0 {
+			LLMTokensUsed.WithLabelValues(provider, model, tokenType).Add(float64(n))
+		}
+	}
+
+	add(TokenTypePrompt, usage.UncachedPromptTokens())
+	add(TokenTypeCompletion, usage.CompletionTokens)
+	add(TokenTypeCacheCreation, usage.CacheCreationTokens)
+	add(TokenTypeCacheRead, usage.CacheReadTokens)
+}
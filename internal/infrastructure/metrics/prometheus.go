@@ -58,6 +58,31 @@ var (
 		[]string{"provider", "model"},
 	)
 
+	// LLM API key pool metrics
+	LLMKeyUtilization = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "llm_key_utilization_ratio",
+			Help: "Fraction of a pooled API key's per-minute request/token budget in use",
+		},
+		[]string{"provider", "key"},
+	)
+
+	LLMKeyRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "llm_key_requests_total",
+			Help: "Total LLM requests sent with each pooled API key",
+		},
+		[]string{"provider", "key"},
+	)
+
+	LLMKeyRateLimitedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "llm_key_rate_limited_total",
+			Help: "Total rate limit (429) responses received for each pooled API key",
+		},
+		[]string{"provider", "key"},
+	)
+
 	// Skill Metrics
 	SkillExecutionsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBraveBaseURL = "https://api.search.brave.com/res/v1"
+
+	// braveMaxCount is the most results the Brave API returns per request.
+	braveMaxCount = 20
+)
+
+// BraveClient searches with the Brave Search API.
+type BraveClient struct {
+	apiKey     string
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewBraveClient creates a new Brave Search client with default base URL.
+func NewBraveClient(apiKey string, timeoutSeconds int) *BraveClient {
+	return NewBraveClientWithBaseURL(apiKey, timeoutSeconds, defaultBraveBaseURL)
+}
+
+// NewBraveClientWithBaseURL creates a new Brave Search client with custom base URL.
+// An empty baseURL uses the default.
+func NewBraveClientWithBaseURL(apiKey string, timeoutSeconds int, baseURL string) *BraveClient {
+	if baseURL == "" {
+		baseURL = defaultBraveBaseURL
+	}
+	return &BraveClient{
+		apiKey: apiKey,
+		httpClient: &http.Client{
+			Timeout: time.Duration(timeoutSeconds) * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Name returns the provider name.
+func (c *BraveClient) Name() string {
+	return "brave"
+}
+
+// braveFreshness maps freshness to Brave's freshness parameter.
+var braveFreshness = map[Freshness]string{
+	FreshnessDay:   "pd",
+	FreshnessWeek:  "pw",
+	FreshnessMonth: "pm",
+	FreshnessYear:  "py",
+}
+
+// Search performs a web search and returns results. Brave returns at most
+// 20 results per request.
+func (c *BraveClient) Search(ctx context.Context, query string, opts Options) ([]SearchResult, error) {
+	if err := opts.validate(query); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("count", strconv.Itoa(min(opts.Limit, braveMaxCount)))
+	params.Set("safesearch", string(opts.SafeSearch))
+	if freshness, ok := braveFreshness[opts.Freshness]; ok {
+		params.Set("freshness", freshness)
+	}
+	if country, language, ok := opts.regionParts(); ok {
+		params.Set("country", strings.ToUpper(country))
+		params.Set("search_lang", language)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/web/search?%s", c.baseURL, params.Encode()), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Subscription-Token", c.apiKey)
+
+	var apiResp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := doJSON(c.httpClient, req, &apiResp); err != nil {
+		return nil, err
+	}
+
+	// Brave marks query terms with <strong> in titles and descriptions
+	results := make([]SearchResult, 0, len(apiResp.Web.Results))
+	for _, r := range apiResp.Web.Results {
+		results = append(results, SearchResult{
+			Title:   stripTags(r.Title),
+			URL:     strings.TrimSpace(r.URL),
+			Snippet: stripTags(r.Description),
+		})
+	}
+	return dedupe(results, opts.Limit), nil
+}
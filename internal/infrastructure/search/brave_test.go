@@ -0,0 +1,61 @@
+package search_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nuimanbot/internal/infrastructure/search"
+)
+
+func TestBrave_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/web/search" {
+			t.Errorf("Expected path '/web/search', got '%s'", r.URL.Path)
+		}
+		if r.Header.Get("X-Subscription-Token") != "brave-key" {
+			t.Errorf("Expected subscription token header, got '%s'", r.Header.Get("X-Subscription-Token"))
+		}
+		if q.Get("count") != "20" || q.Get("freshness") != "pd" || q.Get("safesearch") != "off" {
+			t.Errorf("Expected count=20 freshness=pd safesearch=off, got %s", r.URL.RawQuery)
+		}
+		if q.Get("country") != "GB" || q.Get("search_lang") != "en" {
+			t.Errorf("Expected country=GB search_lang=en, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"web": {"results": [
+			{"title": "<strong>Go</strong> release notes", "url": "https://go.dev/doc/devel/release", "description": "History of <strong>Go</strong> releases"}
+		]}}`))
+	}))
+	defer server.Close()
+
+	client := search.NewBraveClientWithBaseURL("brave-key", 10, server.URL)
+
+	results, err := client.Search(context.Background(), "go releases", search.Options{
+		Limit:      50,
+		Freshness:  search.FreshnessDay,
+		Region:     "gb-en",
+		SafeSearch: search.SafeSearchOff,
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Go release notes" || results[0].Snippet != "History of Go releases" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
+
+func TestBrave_InvalidKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": {"detail": "invalid token"}}`))
+	}))
+	defer server.Close()
+
+	client := search.NewBraveClientWithBaseURL("bad-key", 10, server.URL)
+
+	if _, err := client.Search(context.Background(), "golang", search.Options{Limit: 5}); err == nil {
+		t.Fatal("Expected error for invalid API key")
+	}
+}
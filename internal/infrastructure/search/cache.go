@@ -0,0 +1,94 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the cache; the entry closest to expiry is evicted
+// when a new entry would exceed it.
+const maxCacheEntries = 1000
+
+// CachedProvider wraps a Provider with an in-memory TTL cache. One instance
+// serves all users, so repeated searches share a single upstream request.
+// Errors are never cached.
+type CachedProvider struct {
+	provider Provider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedResults
+}
+
+// cachedResults is a cached search response with its expiry.
+type cachedResults struct {
+	results   []SearchResult
+	expiresAt time.Time
+}
+
+// NewCachedProvider wraps provider so identical searches within ttl are
+// served from memory.
+func NewCachedProvider(provider Provider, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]cachedResults),
+	}
+}
+
+// Name returns the wrapped provider's name.
+func (c *CachedProvider) Name() string {
+	return c.provider.Name()
+}
+
+// Search returns cached results for the same query and options, or searches
+// and caches the results. Queries differing only in case or spacing share
+// an entry.
+func (c *CachedProvider) Search(ctx context.Context, query string, opts Options) ([]SearchResult, error) {
+	key := fmt.Sprintf("%s|%d|%s|%s|%s",
+		strings.Join(strings.Fields(strings.ToLower(query)), " "),
+		opts.Limit, opts.Freshness, strings.ToLower(opts.Region), opts.SafeSearch)
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return append([]SearchResult(nil), entry.results...), nil
+	}
+
+	results, err := c.provider.Search(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	if len(c.entries) >= maxCacheEntries {
+		c.evictOldest()
+	}
+	c.entries[key] = cachedResults{results: results, expiresAt: now.Add(c.ttl)}
+	return append([]SearchResult(nil), results...), nil
+}
+
+// evictOldest removes the entry that expires first. Callers hold c.mu.
+func (c *CachedProvider) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for k, e := range c.entries {
+		if oldestKey == "" || e.expiresAt.Before(oldest) {
+			oldestKey, oldest = k, e.expiresAt
+		}
+	}
+	delete(c.entries, oldestKey)
+}
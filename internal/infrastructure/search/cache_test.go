@@ -0,0 +1,112 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingProvider is a Provider stub that counts searches.
+type countingProvider struct {
+	calls int
+	err   error
+}
+
+func (p *countingProvider) Name() string { return "stub" }
+
+func (p *countingProvider) Search(ctx context.Context, query string, opts Options) ([]SearchResult, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []SearchResult{{Title: query, URL: "https://example.com/" + query}}, nil
+}
+
+func TestCachedProvider_Search(t *testing.T) {
+	stub := &countingProvider{}
+	cached := NewCachedProvider(stub, time.Minute)
+	now := time.Unix(1700000000, 0)
+	cached.now = func() time.Time { return now }
+	ctx := context.Background()
+	opts := Options{Limit: 5}
+
+	for _, query := range []string{"golang", "GoLang", "  golang "} {
+		results, err := cached.Search(ctx, query, opts)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("Expected 1 result, got %d", len(results))
+		}
+	}
+	if stub.calls != 1 {
+		t.Errorf("Expected equivalent queries to share one search, got %d", stub.calls)
+	}
+
+	if _, err := cached.Search(ctx, "golang", Options{Limit: 5, Freshness: FreshnessDay}); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if stub.calls != 2 {
+		t.Errorf("Expected different options to miss the cache, got %d calls", stub.calls)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := cached.Search(ctx, "golang", opts); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if stub.calls != 3 {
+		t.Errorf("Expected expired entry to be refetched, got %d calls", stub.calls)
+	}
+}
+
+func TestCachedProvider_ErrorsNotCached(t *testing.T) {
+	stub := &countingProvider{err: errors.New("rate limited")}
+	cached := NewCachedProvider(stub, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Search(context.Background(), "golang", Options{Limit: 5}); err == nil {
+			t.Fatal("Expected error from provider")
+		}
+	}
+	if stub.calls != 2 {
+		t.Errorf("Expected failed searches to be retried, got %d calls", stub.calls)
+	}
+}
+
+func TestCachedProvider_Bounded(t *testing.T) {
+	stub := &countingProvider{}
+	cached := NewCachedProvider(stub, time.Minute)
+
+	for i := 0; i < maxCacheEntries+10; i++ {
+		if _, err := cached.Search(context.Background(), string(rune('a'+i%26))+time.Duration(i).String(), Options{Limit: 5}); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+	}
+	if len(cached.entries) > maxCacheEntries {
+		t.Errorf("Expected at most %d entries, got %d", maxCacheEntries, len(cached.entries))
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	results := []SearchResult{
+		{URL: "https://www.example.com/page/"},
+		{URL: "http://example.com/page#section"},
+		{URL: "https://example.com/page?utm_source=news"},
+		{URL: ""},
+		{URL: "https://example.com/page?id=2"},
+		{URL: "https://example.com/other"},
+	}
+
+	unique := dedupe(results, 10)
+	if len(unique) != 3 {
+		t.Fatalf("Expected 3 unique results, got %d: %+v", len(unique), unique)
+	}
+	if unique[1].URL != "https://example.com/page?id=2" {
+		t.Errorf("Expected distinct query strings to be kept, got %+v", unique)
+	}
+
+	if got := dedupe(results, 2); len(got) != 2 {
+		t.Errorf("Expected dedupe to truncate to the limit, got %d", len(got))
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strings"
 	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 const (
@@ -43,19 +45,43 @@ func NewClientWithBaseURL(timeoutSeconds int, baseURL string) *Client {
 	}
 }
 
+// Name returns the provider name.
+func (c *Client) Name() string {
+	return "duckduckgo"
+}
+
+// ddgFreshness maps freshness to DuckDuckGo's df parameter.
+var ddgFreshness = map[Freshness]string{
+	FreshnessDay:   "d",
+	FreshnessWeek:  "w",
+	FreshnessMonth: "m",
+	FreshnessYear:  "y",
+}
+
+// ddgSafeSearch maps safe search to DuckDuckGo's kp parameter.
+var ddgSafeSearch = map[SafeSearch]string{
+	SafeSearchOff:      "-2",
+	SafeSearchModerate: "-1",
+	SafeSearchStrict:   "1",
+}
+
 // Search performs a web search and returns results.
-func (c *Client) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+func (c *Client) Search(ctx context.Context, query string, opts Options) ([]SearchResult, error) {
 	// Validate inputs
-	if query == "" {
-		return nil, fmt.Errorf("query cannot be empty")
-	}
-	if limit <= 0 || limit > 50 {
-		return nil, fmt.Errorf("limit must be between 1 and 50")
+	if err := opts.validate(query); err != nil {
+		return nil, err
 	}
 
 	// Build request URL
 	params := url.Values{}
 	params.Set("q", query)
+	params.Set("kp", ddgSafeSearch[opts.SafeSearch])
+	if df, ok := ddgFreshness[opts.Freshness]; ok {
+		params.Set("df", df)
+	}
+	if country, language, ok := opts.regionParts(); ok {
+		params.Set("kl", country+"-"+language)
+	}
 	fullURL := fmt.Sprintf("%s?%s", c.baseURL, params.Encode())
 
 	// Create request
@@ -79,43 +105,118 @@ func (c *Client) Search(ctx context.Context, query string, limit int) ([]SearchR
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Parse HTML results
+	results, err := parseResults(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	// Parse HTML results
-	results := c.parseResults(string(body), limit)
-
-	return results, nil
+	return dedupe(results, opts.Limit), nil
 }
 
-// parseResults extracts search results from DuckDuckGo HTML.
-func (c *Client) parseResults(html string, limit int) []SearchResult {
-	results := []SearchResult{}
+// parseResults extracts search results from DuckDuckGo's HTML page. Titles
+// are "result__a" links and snippets the following "result__snippet"
+// element; sponsored results ("result--ad") are skipped.
+func parseResults(r io.Reader) ([]SearchResult, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
 
-	// Simple regex-based parsing (for production, use an HTML parser like goquery)
-	// Match result links: <a class="result__a" href="URL">Title</a>
-	linkPattern := regexp.MustCompile(`<a[^>]+class="result__a"[^>]+href="([^"]+)"[^>]*>([^<]+)</a>`)
-	snippetPattern := regexp.MustCompile(`<a[^>]+class="result__snippet"[^>]*>([^<]+)</a>`)
+	var results []SearchResult
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch {
+			case hasClass(n, "result--ad"):
+				return
+			case hasClass(n, "result__a"):
+				results = append(results, SearchResult{
+					URL:   resultURL(attr(n, "href")),
+					Title: nodeText(n),
+				})
+				return
+			case hasClass(n, "result__snippet"):
+				if len(results) > 0 && results[len(results)-1].Snippet == "" {
+					results[len(results)-1].Snippet = nodeText(n)
+				}
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
 
-	links := linkPattern.FindAllStringSubmatch(html, -1)
-	snippets := snippetPattern.FindAllStringSubmatch(html, -1)
+	return results, nil
+}
 
-	for i := 0; i < len(links) && i < limit; i++ {
-		result := SearchResult{
-			URL:   strings.TrimSpace(links[i][1]),
-			Title: strings.TrimSpace(links[i][2]),
+// resultURL unwraps DuckDuckGo's redirect links ("//duckduckgo.com/l/?uddg=...").
+func resultURL(href string) string {
+	href = strings.TrimSpace(href)
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	if strings.HasSuffix(u.Host, "duckduckgo.com") && u.Path == "/l/" {
+		if target := u.Query().Get("uddg"); target != "" {
+			return target
 		}
+	}
+	if u.Scheme == "" && u.Host != "" {
+		u.Scheme = "https"
+		return u.String()
+	}
+	return href
+}
 
-		// Add snippet if available
-		if i < len(snippets) {
-			result.Snippet = strings.TrimSpace(snippets[i][1])
+// attr returns the value of an element's attribute.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
 		}
+	}
+	return ""
+}
 
-		results = append(results, result)
+// hasClass reports whether an element has the given CSS class.
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
 	}
+	return false
+}
 
-	return results
+// stripTags returns the text of an HTML fragment such as "<strong>Go</strong> docs".
+func stripTags(fragment string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return strings.TrimSpace(fragment)
+	}
+	var b strings.Builder
+	for _, n := range nodes {
+		writeText(&b, n)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// nodeText returns the whitespace-normalized text content of a node.
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	writeText(&b, n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// writeText appends the text nodes below n to b.
+func writeText(b *strings.Builder, n *html.Node) {
+	if n.Type == html.TextNode {
+		b.WriteString(n.Data)
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		writeText(b, child)
+	}
 }
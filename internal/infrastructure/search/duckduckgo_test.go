@@ -39,7 +39,7 @@ func TestSearch_Success(t *testing.T) {
 	client := search.NewClientWithBaseURL(10, server.URL)
 	ctx := context.Background()
 
-	results, err := client.Search(ctx, "test query", 5)
+	results, err := client.Search(ctx, "test query", search.Options{Limit: 5})
 	if err != nil {
 		t.Fatalf("Search() error = %v", err)
 	}
@@ -53,7 +53,7 @@ func TestSearch_EmptyQuery(t *testing.T) {
 	client := search.NewClient(10)
 	ctx := context.Background()
 
-	_, err := client.Search(ctx, "", 5)
+	_, err := client.Search(ctx, "", search.Options{Limit: 5})
 	if err == nil {
 		t.Fatal("Expected error for empty query")
 	}
@@ -63,12 +63,12 @@ func TestSearch_InvalidLimit(t *testing.T) {
 	client := search.NewClient(10)
 	ctx := context.Background()
 
-	_, err := client.Search(ctx, "test", 0)
+	_, err := client.Search(ctx, "test", search.Options{Limit: 0})
 	if err == nil {
 		t.Fatal("Expected error for invalid limit")
 	}
 
-	_, err = client.Search(ctx, "test", 100)
+	_, err = client.Search(ctx, "test", search.Options{Limit: 100})
 	if err == nil {
 		t.Fatal("Expected error for limit > 50")
 	}
@@ -83,8 +83,81 @@ func TestSearch_HTTPError(t *testing.T) {
 	client := search.NewClientWithBaseURL(10, server.URL)
 	ctx := context.Background()
 
-	_, err := client.Search(ctx, "test query", 5)
+	_, err := client.Search(ctx, "test query", search.Options{Limit: 5})
 	if err == nil {
 		t.Fatal("Expected error for HTTP 500")
 	}
 }
+
+func TestSearch_ParsesDuckDuckGoMarkup(t *testing.T) {
+	// Markup as served by html.duckduckgo.com: redirect links, highlighted
+	// terms, sponsored results and a repeated result
+	mockHTML := `<html><body>
+		<div class="result results_links results_links_deep result--ad">
+			<h2 class="result__title"><a class="result__a" href="https://ads.example.com">Sponsored</a></h2>
+			<a class="result__snippet" href="https://ads.example.com">Buy now</a>
+		</div>
+		<div class="result results_links results_links_deep web-result">
+			<h2 class="result__title">
+				<a rel="nofollow" class="result__a" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fgo.dev%2Fdoc%2F&amp;rut=abc">The <b>Go</b> Programming Language</a>
+			</h2>
+			<a class="result__snippet" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fgo.dev%2Fdoc%2F"><b>Go</b> is an open source
+				programming language &amp; more.</a>
+		</div>
+		<div class="result results_links results_links_deep web-result">
+			<h2 class="result__title"><a class="result__a" href="https://www.go.dev/doc">Go docs (mirror)</a></h2>
+		</div>
+		<div class="result results_links results_links_deep web-result">
+			<h2 class="result__title"><a class="result__a" href="https://pkg.go.dev/">Go Packages</a></h2>
+			<div class="result__snippet">Find <b>Go</b> packages</div>
+		</div>
+	</body></html>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("kl") != "de-de" || q.Get("df") != "w" || q.Get("kp") != "1" {
+			t.Errorf("Expected kl=de-de df=w kp=1, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(mockHTML))
+	}))
+	defer server.Close()
+
+	client := search.NewClientWithBaseURL(10, server.URL)
+
+	results, err := client.Search(context.Background(), "golang", search.Options{
+		Limit:      10,
+		Freshness:  search.FreshnessWeek,
+		Region:     "DE-de",
+		SafeSearch: search.SafeSearchStrict,
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	want := []search.SearchResult{
+		{Title: "The Go Programming Language", URL: "https://go.dev/doc/", Snippet: "Go is an open source programming language & more."},
+		{Title: "Go Packages", URL: "https://pkg.go.dev/", Snippet: "Find Go packages"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d results, got %d: %+v", len(want), len(results), results)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+}
+
+func TestSearch_InvalidOptions(t *testing.T) {
+	client := search.NewClient(10)
+
+	for _, opts := range []search.Options{
+		{Limit: 5, Freshness: "decade"},
+		{Limit: 5, SafeSearch: "none"},
+		{Limit: 5, Region: "germany"},
+	} {
+		if _, err := client.Search(context.Background(), "test", opts); err == nil {
+			t.Errorf("Expected error for options %+v", opts)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Freshness limits results to pages published within a recent period.
+type Freshness string
+
+const (
+	FreshnessAny   Freshness = ""
+	FreshnessDay   Freshness = "day"
+	FreshnessWeek  Freshness = "week"
+	FreshnessMonth Freshness = "month"
+	FreshnessYear  Freshness = "year"
+)
+
+// SafeSearch sets how strictly adult content is filtered.
+type SafeSearch string
+
+const (
+	SafeSearchOff      SafeSearch = "off"
+	SafeSearchModerate SafeSearch = "moderate"
+	SafeSearchStrict   SafeSearch = "strict"
+)
+
+// maxLimit is the largest number of results any search may request.
+const maxLimit = 50
+
+// Options controls a search. Providers ignore filters their API lacks.
+type Options struct {
+	// Limit is the maximum number of results (1-50).
+	Limit int
+	// Freshness limits results by age; empty means any time.
+	Freshness Freshness
+	// Region is a country-language code such as "us-en" or "de-de"; empty
+	// means no regional preference.
+	Region string
+	// SafeSearch defaults to moderate when empty.
+	SafeSearch SafeSearch
+}
+
+// Provider performs web searches against one search backend.
+type Provider interface {
+	// Name identifies the provider in output and logs.
+	Name() string
+	// Search returns up to opts.Limit deduplicated results for query.
+	Search(ctx context.Context, query string, opts Options) ([]SearchResult, error)
+}
+
+// validate checks the query and options shared by all providers and fills
+// in defaults.
+func (o *Options) validate(query string) error {
+	if query == "" {
+		return fmt.Errorf("query cannot be empty")
+	}
+	if o.Limit <= 0 || o.Limit > maxLimit {
+		return fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	switch o.Freshness {
+	case FreshnessAny, FreshnessDay, FreshnessWeek, FreshnessMonth, FreshnessYear:
+	default:
+		return fmt.Errorf("invalid freshness: %s (must be day, week, month or year)", o.Freshness)
+	}
+	if o.SafeSearch == "" {
+		o.SafeSearch = SafeSearchModerate
+	}
+	switch o.SafeSearch {
+	case SafeSearchOff, SafeSearchModerate, SafeSearchStrict:
+	default:
+		return fmt.Errorf("invalid safe_search: %s (must be off, moderate or strict)", o.SafeSearch)
+	}
+	if o.Region != "" {
+		if _, _, ok := o.regionParts(); !ok {
+			return fmt.Errorf("invalid region: %s (must look like us-en)", o.Region)
+		}
+	}
+	return nil
+}
+
+// regionParts splits Region into its lower-case country and language codes.
+func (o *Options) regionParts() (country, language string, ok bool) {
+	country, language, ok = strings.Cut(strings.ToLower(o.Region), "-")
+	if !ok || len(country) != 2 || len(language) != 2 {
+		return "", "", false
+	}
+	return country, language, true
+}
+
+// doJSON executes req and decodes a JSON response into out.
+func doJSON(httpClient *http.Client, req *http.Request, out any) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512)) //nolint:errcheck // Best effort error message extraction
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// dedupe drops results without a URL or whose URL repeats an earlier result,
+// then truncates to limit.
+func dedupe(results []SearchResult, limit int) []SearchResult {
+	seen := make(map[string]bool, len(results))
+	unique := make([]SearchResult, 0, len(results))
+	for _, r := range results {
+		if r.URL == "" {
+			continue
+		}
+		key := canonicalURL(r.URL)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, r)
+		if len(unique) == limit {
+			break
+		}
+	}
+	return unique
+}
+
+// canonicalURL normalizes a URL for duplicate detection: scheme, "www.",
+// fragments, tracking parameters and trailing slashes are ignored.
+func canonicalURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(key, "utm_") {
+			query.Del(key)
+		}
+	}
+
+	key := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		key += "?" + encoded
+	}
+	return key
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SearxNGClient searches a self-hosted SearxNG instance through its JSON API.
+// The instance must enable the json output format.
+type SearxNGClient struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewSearxNGClient creates a new client for the SearxNG instance at baseURL.
+func NewSearxNGClient(timeoutSeconds int, baseURL string) *SearxNGClient {
+	return &SearxNGClient{
+		httpClient: &http.Client{
+			Timeout: time.Duration(timeoutSeconds) * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Name returns the provider name.
+func (c *SearxNGClient) Name() string {
+	return "searxng"
+}
+
+// searxngSafeSearch maps safe search to SearxNG's safesearch levels.
+var searxngSafeSearch = map[SafeSearch]string{
+	SafeSearchOff:      "0",
+	SafeSearchModerate: "1",
+	SafeSearchStrict:   "2",
+}
+
+// Search performs a web search and returns results.
+func (c *SearxNGClient) Search(ctx context.Context, query string, opts Options) ([]SearchResult, error) {
+	if err := opts.validate(query); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	params.Set("safesearch", searxngSafeSearch[opts.SafeSearch])
+	if opts.Freshness != FreshnessAny {
+		params.Set("time_range", string(opts.Freshness))
+	}
+	if country, language, ok := opts.regionParts(); ok {
+		params.Set("language", language+"-"+strings.ToUpper(country))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/search?%s", c.baseURL, params.Encode()), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var apiResp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := doJSON(c.httpClient, req, &apiResp); err != nil {
+		return nil, err
+	}
+
+	// SearxNG merges engines and may already report the same page twice
+	results := make([]SearchResult, 0, len(apiResp.Results))
+	for _, r := range apiResp.Results {
+		results = append(results, SearchResult{
+			Title:   strings.TrimSpace(r.Title),
+			URL:     strings.TrimSpace(r.URL),
+			Snippet: strings.TrimSpace(r.Content),
+		})
+	}
+	return dedupe(results, opts.Limit), nil
+}
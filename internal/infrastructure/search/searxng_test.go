@@ -0,0 +1,57 @@
+package search_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nuimanbot/internal/infrastructure/search"
+)
+
+func TestSearxNG_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/search" {
+			t.Errorf("Expected path '/search', got '%s'", r.URL.Path)
+		}
+		if q.Get("format") != "json" || q.Get("q") != "golang" {
+			t.Errorf("Expected JSON search for 'golang', got %s", r.URL.RawQuery)
+		}
+		if q.Get("time_range") != "month" || q.Get("language") != "en-US" || q.Get("safesearch") != "1" {
+			t.Errorf("Expected time_range=month language=en-US safesearch=1, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"results": [
+			{"title": "Go", "url": "https://go.dev/", "content": "The Go language"},
+			{"title": "Go (duplicate engine hit)", "url": "https://go.dev/?utm_source=searx", "content": "Again"},
+			{"title": "Go blog", "url": "https://go.dev/blog", "content": "News"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := search.NewSearxNGClient(10, server.URL+"/")
+
+	results, err := client.Search(context.Background(), "golang", search.Options{Limit: 5, Freshness: search.FreshnessMonth, Region: "us-en"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Title != "Go" || results[1].URL != "https://go.dev/blog" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+	if client.Name() != "searxng" {
+		t.Errorf("Expected name 'searxng', got '%s'", client.Name())
+	}
+}
+
+func TestSearxNG_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := search.NewSearxNGClient(10, server.URL)
+
+	if _, err := client.Search(context.Background(), "golang", search.Options{Limit: 5}); err == nil {
+		t.Fatal("Expected error when the JSON format is disabled")
+	}
+}
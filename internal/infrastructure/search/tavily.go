@@ -0,0 +1,98 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTavilyBaseURL = "https://api.tavily.com"
+
+	// tavilyMaxResults is the most results the Tavily API returns per request.
+	tavilyMaxResults = 20
+)
+
+// TavilyClient searches with the Tavily Search API.
+type TavilyClient struct {
+	apiKey     string
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewTavilyClient creates a new Tavily client with default base URL.
+func NewTavilyClient(apiKey string, timeoutSeconds int) *TavilyClient {
+	return NewTavilyClientWithBaseURL(apiKey, timeoutSeconds, defaultTavilyBaseURL)
+}
+
+// NewTavilyClientWithBaseURL creates a new Tavily client with custom base URL.
+// An empty baseURL uses the default.
+func NewTavilyClientWithBaseURL(apiKey string, timeoutSeconds int, baseURL string) *TavilyClient {
+	if baseURL == "" {
+		baseURL = defaultTavilyBaseURL
+	}
+	return &TavilyClient{
+		apiKey: apiKey,
+		httpClient: &http.Client{
+			Timeout: time.Duration(timeoutSeconds) * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Name returns the provider name.
+func (c *TavilyClient) Name() string {
+	return "tavily"
+}
+
+// Search performs a web search and returns results. Tavily has no region or
+// safe-search filters, so those options are ignored.
+func (c *TavilyClient) Search(ctx context.Context, query string, opts Options) ([]SearchResult, error) {
+	if err := opts.validate(query); err != nil {
+		return nil, err
+	}
+
+	payload := map[string]any{
+		"query":       query,
+		"max_results": min(opts.Limit, tavilyMaxResults),
+	}
+	if opts.Freshness != FreshnessAny {
+		payload["time_range"] = string(opts.Freshness)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/search", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	var apiResp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := doJSON(c.httpClient, req, &apiResp); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(apiResp.Results))
+	for _, r := range apiResp.Results {
+		results = append(results, SearchResult{
+			Title:   strings.TrimSpace(r.Title),
+			URL:     strings.TrimSpace(r.URL),
+			Snippet: strings.TrimSpace(r.Content),
+		})
+	}
+	return dedupe(results, opts.Limit), nil
+}
@@ -0,0 +1,46 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nuimanbot/internal/infrastructure/search"
+)
+
+func TestTavily_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/search" {
+			t.Errorf("Expected POST /search, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer tvly-key" {
+			t.Errorf("Expected bearer token, got '%s'", r.Header.Get("Authorization"))
+		}
+
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if payload["query"] != "golang generics" || payload["max_results"] != float64(3) || payload["time_range"] != "year" {
+			t.Errorf("Unexpected payload: %v", payload)
+		}
+
+		w.Write([]byte(`{"results": [
+			{"title": "Tutorial: Getting started with generics", "url": "https://go.dev/doc/tutorial/generics", "content": "This tutorial introduces generics", "score": 0.98},
+			{"title": "An Introduction To Generics", "url": "https://go.dev/blog/intro-generics", "content": "Blog post", "score": 0.91}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := search.NewTavilyClientWithBaseURL("tvly-key", 10, server.URL)
+
+	results, err := client.Search(context.Background(), "golang generics", search.Options{Limit: 3, Freshness: search.FreshnessYear})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 2 || results[0].URL != "https://go.dev/doc/tutorial/generics" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
@@ -0,0 +1,120 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CachedProvider wraps a Provider with an in-memory TTL cache. Geocoding,
+// current conditions, forecasts and alerts are cached separately; errors are
+// never cached except ErrAlertsUnsupported.
+type CachedProvider struct {
+	provider Provider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+// cachedResponse is a cached provider result with its expiry.
+type cachedResponse struct {
+	value     any
+	err       error
+	expiresAt time.Time
+}
+
+// NewCachedProvider wraps provider so identical requests within ttl are served
+// from memory.
+func NewCachedProvider(provider Provider, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]cachedResponse),
+	}
+}
+
+// Name returns the wrapped provider's name.
+func (c *CachedProvider) Name() string {
+	return c.provider.Name()
+}
+
+// Geocode resolves a location, cached by query.
+func (c *CachedProvider) Geocode(ctx context.Context, query string) (*Place, error) {
+	v, err := c.get("geocode|"+query, func() (any, error) {
+		return c.provider.Geocode(ctx, query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Place), nil
+}
+
+// GetCurrentWeather fetches current weather, cached by place and units.
+func (c *CachedProvider) GetCurrentWeather(ctx context.Context, place Place, units string) (*CurrentWeather, error) {
+	v, err := c.get(fmt.Sprintf("current|%s|%s", placeKey(place), units), func() (any, error) {
+		return c.provider.GetCurrentWeather(ctx, place, units)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*CurrentWeather), nil
+}
+
+// GetForecast fetches a forecast, cached by place, units and granularity.
+func (c *CachedProvider) GetForecast(ctx context.Context, place Place, units string, granularity Granularity) (*ForecastData, error) {
+	v, err := c.get(fmt.Sprintf("forecast|%s|%s|%s", placeKey(place), units, granularity), func() (any, error) {
+		return c.provider.GetForecast(ctx, place, units, granularity)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ForecastData), nil
+}
+
+// GetAlerts fetches alerts, cached by place.
+func (c *CachedProvider) GetAlerts(ctx context.Context, place Place) ([]Alert, error) {
+	v, err := c.get("alerts|"+placeKey(place), func() (any, error) {
+		return c.provider.GetAlerts(ctx, place)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]Alert), nil
+}
+
+// get returns the cached value for key or calls fetch and caches its result.
+func (c *CachedProvider) get(key string, fetch func() (any, error)) (any, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.value, entry.err
+	}
+
+	value, err := fetch()
+	if err != nil && !errors.Is(err, ErrAlertsUnsupported) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedResponse{value: value, err: err, expiresAt: now.Add(c.ttl)}
+	return value, err
+}
+
+// placeKey identifies a place by its coordinates.
+func placeKey(place Place) string {
+	return fmt.Sprintf("%.4f,%.4f", place.Latitude, place.Longitude)
+}
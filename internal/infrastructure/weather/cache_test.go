@@ -0,0 +1,102 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingProvider is a Provider stub that counts calls.
+type countingProvider struct {
+	calls     map[string]int
+	failCalls bool
+}
+
+func (p *countingProvider) Name() string { return "stub" }
+
+func (p *countingProvider) Geocode(ctx context.Context, query string) (*Place, error) {
+	p.calls["geocode"]++
+	if p.failCalls {
+		return nil, errors.New("unavailable")
+	}
+	return &Place{Name: query, Latitude: 1, Longitude: 2}, nil
+}
+
+func (p *countingProvider) GetCurrentWeather(ctx context.Context, place Place, units string) (*CurrentWeather, error) {
+	p.calls["current"]++
+	return &CurrentWeather{Location: place.Name, Units: units}, nil
+}
+
+func (p *countingProvider) GetForecast(ctx context.Context, place Place, units string, granularity Granularity) (*ForecastData, error) {
+	p.calls["forecast"]++
+	return &ForecastData{Location: place.Name, Units: units, Granularity: granularity}, nil
+}
+
+func (p *countingProvider) GetAlerts(ctx context.Context, place Place) ([]Alert, error) {
+	p.calls["alerts"]++
+	return nil, ErrAlertsUnsupported
+}
+
+func TestCachedProvider(t *testing.T) {
+	stub := &countingProvider{calls: make(map[string]int)}
+	cached := NewCachedProvider(stub, time.Minute)
+	now := time.Unix(1700000000, 0)
+	cached.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	place, err := cached.Geocode(ctx, "Oslo")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Geocode(ctx, "Oslo"); err != nil {
+			t.Fatalf("Geocode() error = %v", err)
+		}
+		if _, err := cached.GetCurrentWeather(ctx, *place, "metric"); err != nil {
+			t.Fatalf("GetCurrentWeather() error = %v", err)
+		}
+		if _, err := cached.GetForecast(ctx, *place, "metric", GranularityDaily); err != nil {
+			t.Fatalf("GetForecast() error = %v", err)
+		}
+		if _, err := cached.GetAlerts(ctx, *place); !errors.Is(err, ErrAlertsUnsupported) {
+			t.Fatalf("GetAlerts() error = %v, want ErrAlertsUnsupported", err)
+		}
+	}
+	for _, op := range []string{"geocode", "current", "forecast", "alerts"} {
+		if stub.calls[op] != 1 {
+			t.Errorf("Expected 1 %s call, got %d", op, stub.calls[op])
+		}
+	}
+
+	// Different parameters are separate entries
+	if _, err := cached.GetForecast(ctx, *place, "imperial", GranularityDaily); err != nil {
+		t.Fatalf("GetForecast() error = %v", err)
+	}
+	if stub.calls["forecast"] != 2 {
+		t.Errorf("Expected imperial forecast to miss the cache, got %d calls", stub.calls["forecast"])
+	}
+
+	// Entries expire after the TTL
+	now = now.Add(time.Minute)
+	if _, err := cached.GetCurrentWeather(ctx, *place, "metric"); err != nil {
+		t.Fatalf("GetCurrentWeather() error = %v", err)
+	}
+	if stub.calls["current"] != 2 {
+		t.Errorf("Expected expired entry to be refetched, got %d calls", stub.calls["current"])
+	}
+}
+
+func TestCachedProvider_ErrorsNotCached(t *testing.T) {
+	stub := &countingProvider{calls: make(map[string]int), failCalls: true}
+	cached := NewCachedProvider(stub, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Geocode(context.Background(), "Oslo"); err == nil {
+			t.Fatal("Expected error from provider")
+		}
+	}
+	if stub.calls["geocode"] != 2 {
+		t.Errorf("Expected failed lookups to be retried, got %d calls", stub.calls["geocode"])
+	}
+}
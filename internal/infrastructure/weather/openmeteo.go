@@ -0,0 +1,309 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultOpenMeteoForecastURL  = "https://api.open-meteo.com/v1"
+	defaultOpenMeteoGeocodingURL = "https://geocoding-api.open-meteo.com/v1"
+
+	// openMeteoForecastDays is the forecast length, matching OpenWeatherMap's.
+	openMeteoForecastDays = 5
+	// kelvinOffset converts Celsius to Kelvin for "standard" units.
+	kelvinOffset = 273.15
+)
+
+// OpenMeteoClient is a keyless Open-Meteo (open-meteo.com) API client.
+type OpenMeteoClient struct {
+	httpClient   *http.Client
+	forecastURL  string
+	geocodingURL string
+}
+
+// NewOpenMeteoClient creates a new Open-Meteo client with the default base URLs.
+func NewOpenMeteoClient(timeoutSeconds int) *OpenMeteoClient {
+	return NewOpenMeteoClientWithBaseURLs(timeoutSeconds, defaultOpenMeteoForecastURL, defaultOpenMeteoGeocodingURL)
+}
+
+// NewOpenMeteoClientWithBaseURLs creates a new Open-Meteo client with custom
+// forecast and geocoding base URLs. Empty URLs use the defaults.
+func NewOpenMeteoClientWithBaseURLs(timeoutSeconds int, forecastURL, geocodingURL string) *OpenMeteoClient {
+	if forecastURL == "" {
+		forecastURL = defaultOpenMeteoForecastURL
+	}
+	if geocodingURL == "" {
+		geocodingURL = defaultOpenMeteoGeocodingURL
+	}
+	return &OpenMeteoClient{
+		httpClient: &http.Client{
+			Timeout: time.Duration(timeoutSeconds) * time.Second,
+		},
+		forecastURL:  forecastURL,
+		geocodingURL: geocodingURL,
+	}
+}
+
+// Name returns the provider name.
+func (c *OpenMeteoClient) Name() string {
+	return "open-meteo"
+}
+
+// Geocode resolves a location with the Open-Meteo geocoding API. The API only
+// searches names, so qualifiers after the first comma ("Paris, FR") pick among
+// the candidates by region or country.
+func (c *OpenMeteoClient) Geocode(ctx context.Context, query string) (*Place, error) {
+	name, qualifiers := splitQuery(query)
+	if name == "" {
+		return nil, fmt.Errorf("location cannot be empty")
+	}
+
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("count", "10")
+	params.Set("language", "en")
+	params.Set("format", "json")
+
+	var apiResp struct {
+		Results []struct {
+			Name        string  `json:"name"`
+			Admin1      string  `json:"admin1"`
+			Country     string  `json:"country"`
+			CountryCode string  `json:"country_code"`
+			Latitude    float64 `json:"latitude"`
+			Longitude   float64 `json:"longitude"`
+		} `json:"results"`
+	}
+	fullURL := fmt.Sprintf("%s/search?%s", c.geocodingURL, params.Encode())
+	if err := getJSON(ctx, c.httpClient, fullURL, "reason", &apiResp); err != nil {
+		return nil, err
+	}
+
+	for _, r := range apiResp.Results {
+		if matchesQualifiers(qualifiers, r.Admin1, r.Country, r.CountryCode) {
+			return &Place{Name: r.Name, Region: r.Admin1, Country: r.CountryCode, Latitude: r.Latitude, Longitude: r.Longitude}, nil
+		}
+	}
+	return nil, fmt.Errorf("location not found: %s", query)
+}
+
+// openMeteoResponse is the subset of the /forecast response the client reads.
+type openMeteoResponse struct {
+	UTCOffsetSeconds int `json:"utc_offset_seconds"`
+	Current          struct {
+		Temperature         float64 `json:"temperature_2m"`
+		ApparentTemperature float64 `json:"apparent_temperature"`
+		RelativeHumidity    float64 `json:"relative_humidity_2m"`
+		PressureMSL         float64 `json:"pressure_msl"`
+		WindSpeed           float64 `json:"wind_speed_10m"`
+		WeatherCode         int     `json:"weather_code"`
+	} `json:"current"`
+	Hourly struct {
+		Time                     []int64   `json:"time"`
+		Temperature              []float64 `json:"temperature_2m"`
+		WeatherCode              []int     `json:"weather_code"`
+		PrecipitationProbability []*int    `json:"precipitation_probability"`
+	} `json:"hourly"`
+	Daily struct {
+		Time                     []int64   `json:"time"`
+		TemperatureMax           []float64 `json:"temperature_2m_max"`
+		TemperatureMin           []float64 `json:"temperature_2m_min"`
+		WeatherCode              []int     `json:"weather_code"`
+		PrecipitationProbability []*int    `json:"precipitation_probability_max"`
+	} `json:"daily"`
+}
+
+// forecast calls the /forecast endpoint with the given variable selections.
+func (c *OpenMeteoClient) forecast(ctx context.Context, place Place, units string, selection url.Values) (*openMeteoResponse, error) {
+	if err := validateUnits(units); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("latitude", fmt.Sprintf("%f", place.Latitude))
+	params.Set("longitude", fmt.Sprintf("%f", place.Longitude))
+	params.Set("timezone", "auto")
+	params.Set("timeformat", "unixtime")
+	if units == "imperial" {
+		params.Set("temperature_unit", "fahrenheit")
+		params.Set("wind_speed_unit", "mph")
+	} else {
+		params.Set("wind_speed_unit", "ms")
+	}
+	for key, values := range selection {
+		params[key] = values
+	}
+
+	var apiResp openMeteoResponse
+	fullURL := fmt.Sprintf("%s/forecast?%s", c.forecastURL, params.Encode())
+	if err := getJSON(ctx, c.httpClient, fullURL, "reason", &apiResp); err != nil {
+		return nil, err
+	}
+	return &apiResp, nil
+}
+
+// GetCurrentWeather fetches current weather for a place.
+func (c *OpenMeteoClient) GetCurrentWeather(ctx context.Context, place Place, units string) (*CurrentWeather, error) {
+	selection := url.Values{}
+	selection.Set("current", "temperature_2m,apparent_temperature,relative_humidity_2m,pressure_msl,wind_speed_10m,weather_code")
+
+	apiResp, err := c.forecast(ctx, place, units, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	current := apiResp.Current
+	return &CurrentWeather{
+		Location:    place.DisplayName(),
+		Temperature: convertTemperature(current.Temperature, units),
+		FeelsLike:   convertTemperature(current.ApparentTemperature, units),
+		Humidity:    int(current.RelativeHumidity + 0.5),
+		Pressure:    int(current.PressureMSL + 0.5),
+		Description: describeWeatherCode(current.WeatherCode),
+		WindSpeed:   current.WindSpeed,
+		Units:       units,
+	}, nil
+}
+
+// GetForecast fetches the forecast for a place in hourly or daily steps.
+func (c *OpenMeteoClient) GetForecast(ctx context.Context, place Place, units string, granularity Granularity) (*ForecastData, error) {
+	if err := validateGranularity(granularity); err != nil {
+		return nil, err
+	}
+
+	// forecast_hours starts the hourly series at the current hour rather than
+	// at midnight.
+	selection := url.Values{}
+	if granularity == GranularityDaily {
+		selection.Set("forecast_days", fmt.Sprintf("%d", openMeteoForecastDays))
+		selection.Set("daily", "temperature_2m_max,temperature_2m_min,weather_code,precipitation_probability_max")
+	} else {
+		selection.Set("forecast_hours", fmt.Sprintf("%d", openMeteoForecastDays*24))
+		selection.Set("hourly", "temperature_2m,weather_code,precipitation_probability")
+	}
+
+	apiResp, err := c.forecast(ctx, place, units, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	var forecasts []ForecastEntry
+	if granularity == GranularityDaily {
+		daily := apiResp.Daily
+		forecasts = make([]ForecastEntry, 0, len(daily.Time))
+		for i, ts := range daily.Time {
+			if i >= len(daily.TemperatureMax) || i >= len(daily.TemperatureMin) {
+				break
+			}
+			tempMax := convertTemperature(daily.TemperatureMax[i], units)
+			tempMin := convertTemperature(daily.TemperatureMin[i], units)
+			forecasts = append(forecasts, ForecastEntry{
+				Timestamp:           ts,
+				Temperature:         (tempMax + tempMin) / 2,
+				TempMin:             tempMin,
+				TempMax:             tempMax,
+				Description:         describeWeatherCode(valueAt(daily.WeatherCode, i)),
+				PrecipitationChance: percentAt(daily.PrecipitationProbability, i),
+			})
+		}
+	} else {
+		hourly := apiResp.Hourly
+		forecasts = make([]ForecastEntry, 0, len(hourly.Time))
+		for i, ts := range hourly.Time {
+			if i >= len(hourly.Temperature) {
+				break
+			}
+			temp := convertTemperature(hourly.Temperature[i], units)
+			forecasts = append(forecasts, ForecastEntry{
+				Timestamp:           ts,
+				Temperature:         temp,
+				TempMin:             temp,
+				TempMax:             temp,
+				Description:         describeWeatherCode(valueAt(hourly.WeatherCode, i)),
+				PrecipitationChance: percentAt(hourly.PrecipitationProbability, i),
+			})
+		}
+	}
+
+	return &ForecastData{
+		Location:    place.DisplayName(),
+		Forecasts:   forecasts,
+		Units:       units,
+		Granularity: granularity,
+		UTCOffset:   apiResp.UTCOffsetSeconds,
+	}, nil
+}
+
+// GetAlerts reports ErrAlertsUnsupported: Open-Meteo publishes no alerts.
+func (c *OpenMeteoClient) GetAlerts(ctx context.Context, place Place) ([]Alert, error) {
+	return nil, ErrAlertsUnsupported
+}
+
+// convertTemperature converts an API temperature (Celsius or Fahrenheit) to
+// the requested units; Open-Meteo has no Kelvin option.
+func convertTemperature(value float64, units string) float64 {
+	if units == "standard" {
+		return value + kelvinOffset
+	}
+	return value
+}
+
+// valueAt returns values[i], or 0 when the series is shorter.
+func valueAt(values []int, i int) int {
+	if i < len(values) {
+		return values[i]
+	}
+	return 0
+}
+
+// percentAt returns values[i], or 0 when missing or null.
+func percentAt(values []*int, i int) int {
+	if i < len(values) && values[i] != nil {
+		return *values[i]
+	}
+	return 0
+}
+
+// wmoDescriptions maps WMO weather interpretation codes to descriptions.
+var wmoDescriptions = map[int]string{
+	0:  "clear sky",
+	1:  "mainly clear",
+	2:  "partly cloudy",
+	3:  "overcast",
+	45: "fog",
+	48: "depositing rime fog",
+	51: "light drizzle",
+	53: "moderate drizzle",
+	55: "dense drizzle",
+	56: "light freezing drizzle",
+	57: "dense freezing drizzle",
+	61: "slight rain",
+	63: "moderate rain",
+	65: "heavy rain",
+	66: "light freezing rain",
+	67: "heavy freezing rain",
+	71: "slight snow fall",
+	73: "moderate snow fall",
+	75: "heavy snow fall",
+	77: "snow grains",
+	80: "slight rain showers",
+	81: "moderate rain showers",
+	82: "violent rain showers",
+	85: "slight snow showers",
+	86: "heavy snow showers",
+	95: "thunderstorm",
+	96: "thunderstorm with slight hail",
+	99: "thunderstorm with heavy hail",
+}
+
+// describeWeatherCode returns the description for a WMO weather code.
+func describeWeatherCode(code int) string {
+	if desc, ok := wmoDescriptions[code]; ok {
+		return desc
+	}
+	return fmt.Sprintf("weather code %d", code)
+}
@@ -0,0 +1,176 @@
+package weather_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nuimanbot/internal/infrastructure/weather"
+)
+
+func TestOpenMeteo_Geocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			t.Errorf("Expected path '/search', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("name") != "Paris" {
+			t.Errorf("Expected name 'Paris', got '%s'", r.URL.Query().Get("name"))
+		}
+		w.Write([]byte(`{"results": [
+			{"name": "Paris", "admin1": "Texas", "country": "United States", "country_code": "US", "latitude": 33.66, "longitude": -95.55},
+			{"name": "Paris", "admin1": "Île-de-France", "country": "France", "country_code": "FR", "latitude": 48.85, "longitude": 2.35}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := weather.NewOpenMeteoClientWithBaseURLs(10, server.URL, server.URL)
+
+	tests := []struct {
+		query       string
+		wantCountry string
+	}{
+		{"Paris", "US"},
+		{"Paris, FR", "FR"},
+		{"Paris, france", "FR"},
+		{"Paris, Texas, US", "US"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			place, err := client.Geocode(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Geocode() error = %v", err)
+			}
+			if place.Country != tt.wantCountry {
+				t.Errorf("Geocode(%q) country = %s, want %s", tt.query, place.Country, tt.wantCountry)
+			}
+		})
+	}
+
+	if _, err := client.Geocode(context.Background(), "Paris, DE"); err == nil {
+		t.Error("Expected error when no candidate matches the qualifiers")
+	}
+	if _, err := client.Geocode(context.Background(), ""); err == nil {
+		t.Error("Expected error for empty location")
+	}
+}
+
+func TestOpenMeteo_GetCurrentWeather(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/forecast" {
+			t.Errorf("Expected path '/forecast', got '%s'", r.URL.Path)
+		}
+		if q.Get("current") == "" {
+			t.Error("Expected current variables to be requested")
+		}
+		if q.Get("temperature_unit") != "fahrenheit" || q.Get("wind_speed_unit") != "mph" {
+			t.Errorf("Expected imperial units, got %s and %s", q.Get("temperature_unit"), q.Get("wind_speed_unit"))
+		}
+		w.Write([]byte(`{
+			"utc_offset_seconds": 3600,
+			"current": {
+				"temperature_2m": 59.5,
+				"apparent_temperature": 57.2,
+				"relative_humidity_2m": 71.6,
+				"pressure_msl": 1012.8,
+				"wind_speed_10m": 9.1,
+				"weather_code": 61
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := weather.NewOpenMeteoClientWithBaseURLs(10, server.URL, server.URL)
+	place := weather.Place{Name: "Paris", Country: "FR", Latitude: 48.85, Longitude: 2.35}
+
+	current, err := client.GetCurrentWeather(context.Background(), place, "imperial")
+	if err != nil {
+		t.Fatalf("GetCurrentWeather() error = %v", err)
+	}
+
+	if current.Location != "Paris, FR" {
+		t.Errorf("Expected location 'Paris, FR', got '%s'", current.Location)
+	}
+	if current.Temperature != 59.5 || current.FeelsLike != 57.2 {
+		t.Errorf("Expected temperature 59.5 (feels 57.2), got %f (%f)", current.Temperature, current.FeelsLike)
+	}
+	if current.Humidity != 72 || current.Pressure != 1013 {
+		t.Errorf("Expected humidity 72 and pressure 1013, got %d and %d", current.Humidity, current.Pressure)
+	}
+	if current.Description != "slight rain" {
+		t.Errorf("Expected description 'slight rain', got '%s'", current.Description)
+	}
+}
+
+func TestOpenMeteo_GetForecast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("daily") != "" {
+			w.Write([]byte(`{
+				"utc_offset_seconds": 3600,
+				"daily": {
+					"time": [1609455600, 1609542000],
+					"temperature_2m_max": [8.0, 5.0],
+					"temperature_2m_min": [2.0, -1.0],
+					"weather_code": [3, 71],
+					"precipitation_probability_max": [10, null]
+				}
+			}`))
+			return
+		}
+		if q.Get("forecast_hours") != "120" {
+			t.Errorf("Expected forecast_hours 120, got '%s'", q.Get("forecast_hours"))
+		}
+		w.Write([]byte(`{
+			"utc_offset_seconds": 3600,
+			"hourly": {
+				"time": [1609459200, 1609462800],
+				"temperature_2m": [4.0, 5.0],
+				"weather_code": [0, 2],
+				"precipitation_probability": [0, 15]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := weather.NewOpenMeteoClientWithBaseURLs(10, server.URL, server.URL)
+	place := weather.Place{Name: "Paris", Latitude: 48.85, Longitude: 2.35}
+
+	hourly, err := client.GetForecast(context.Background(), place, "metric", weather.GranularityHourly)
+	if err != nil {
+		t.Fatalf("GetForecast(hourly) error = %v", err)
+	}
+	if len(hourly.Forecasts) != 2 || hourly.Forecasts[1].Description != "partly cloudy" || hourly.Forecasts[1].PrecipitationChance != 15 {
+		t.Errorf("Unexpected hourly forecast: %+v", hourly.Forecasts)
+	}
+	if hourly.UTCOffset != 3600 {
+		t.Errorf("Expected UTC offset 3600, got %d", hourly.UTCOffset)
+	}
+
+	daily, err := client.GetForecast(context.Background(), place, "standard", weather.GranularityDaily)
+	if err != nil {
+		t.Fatalf("GetForecast(daily) error = %v", err)
+	}
+	if len(daily.Forecasts) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(daily.Forecasts))
+	}
+	day := daily.Forecasts[1]
+	if math.Abs(day.TempMax-278.15) > 1e-9 || math.Abs(day.TempMin-272.15) > 1e-9 {
+		t.Errorf("Expected Kelvin min/max 272.15/278.15, got %f/%f", day.TempMin, day.TempMax)
+	}
+	if day.Description != "slight snow fall" || day.PrecipitationChance != 0 {
+		t.Errorf("Unexpected day: %+v", day)
+	}
+}
+
+func TestOpenMeteo_GetAlerts(t *testing.T) {
+	client := weather.NewOpenMeteoClient(10)
+
+	_, err := client.GetAlerts(context.Background(), weather.Place{})
+	if !errors.Is(err, weather.ErrAlertsUnsupported) {
+		t.Errorf("Expected ErrAlertsUnsupported, got %v", err)
+	}
+}
@@ -2,15 +2,17 @@ package weather
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"time"
 )
 
 const (
-	defaultBaseURL = "https://api.openweathermap.org/data/2.5"
+	defaultBaseURL = "https://api.openweathermap.org"
 )
 
 // Client represents an OpenWeatherMap API client.
@@ -20,38 +22,13 @@ type Client struct {
 	baseURL    string
 }
 
-// CurrentWeather represents current weather data for a location.
-type CurrentWeather struct {
-	Location    string
-	Temperature float64
-	FeelsLike   float64
-	Humidity    int
-	Pressure    int
-	Description string
-	WindSpeed   float64
-	Units       string
-}
-
-// ForecastData represents forecast weather data for a location.
-type ForecastData struct {
-	Location  string
-	Forecasts []ForecastEntry
-	Units     string
-}
-
-// ForecastEntry represents a single forecast entry.
-type ForecastEntry struct {
-	Timestamp   int64
-	Temperature float64
-	Description string
-}
-
 // NewClient creates a new OpenWeatherMap client with default base URL.
 func NewClient(apiKey string, timeoutSeconds int) *Client {
 	return NewClientWithBaseURL(apiKey, timeoutSeconds, defaultBaseURL)
 }
 
 // NewClientWithBaseURL creates a new OpenWeatherMap client with custom base URL.
+// The geocoding (/geo/1.0) and data (/data/2.5, /data/3.0) APIs share it.
 func NewClientWithBaseURL(apiKey string, timeoutSeconds int, baseURL string) *Client {
 	return &Client{
 		apiKey: apiKey,
@@ -62,89 +39,144 @@ func NewClientWithBaseURL(apiKey string, timeoutSeconds int, baseURL string) *Cl
 	}
 }
 
-// validateInputs validates location and units parameters.
-func (c *Client) validateInputs(location, units string) error {
-	if location == "" {
-		return fmt.Errorf("location cannot be empty")
-	}
-	if units != "metric" && units != "imperial" && units != "standard" {
-		return fmt.Errorf("invalid units: %s (must be metric, imperial, or standard)", units)
-	}
-	return nil
+// Name returns the provider name.
+func (c *Client) Name() string {
+	return "openweathermap"
 }
 
 // makeRequest performs an HTTP GET request to the OpenWeatherMap API.
-func (c *Client) makeRequest(ctx context.Context, endpoint, location, units string) (map[string]interface{}, error) {
-	// Build request URL
-	reqURL := fmt.Sprintf("%s%s", c.baseURL, endpoint)
-	params := url.Values{}
-	params.Set("q", location)
-	params.Set("units", units)
+func (c *Client) makeRequest(ctx context.Context, endpoint string, params url.Values, out any) error {
 	params.Set("appid", c.apiKey)
+	fullURL := fmt.Sprintf("%s%s?%s", c.baseURL, endpoint, params.Encode())
+	return getJSON(ctx, c.httpClient, fullURL, "message", out)
+}
 
-	fullURL := fmt.Sprintf("%s?%s", reqURL, params.Encode())
+// coordinates returns the lat/lon query parameters for a place.
+func coordinates(place Place) url.Values {
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(place.Latitude, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(place.Longitude, 'f', -1, 64))
+	return params
+}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// Geocode resolves a location with the OpenWeatherMap geocoding API.
+func (c *Client) Geocode(ctx context.Context, query string) (*Place, error) {
+	if query == "" {
+		return nil, fmt.Errorf("location cannot be empty")
 	}
 
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", "1")
+
+	var results []struct {
+		Name    string  `json:"name"`
+		State   string  `json:"state"`
+		Country string  `json:"country"`
+		Lat     float64 `json:"lat"`
+		Lon     float64 `json:"lon"`
 	}
-	defer func() { _ = resp.Body.Close() }()
+	if err := c.makeRequest(ctx, "/geo/1.0/direct", params, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("location not found: %s", query)
+	}
+
+	r := results[0]
+	return &Place{Name: r.Name, Region: r.State, Country: r.Country, Latitude: r.Lat, Longitude: r.Lon}, nil
+}
 
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		var errorResp map[string]interface{}
-		_ = json.NewDecoder(resp.Body).Decode(&errorResp) //nolint:errcheck // Best effort error message extraction
-		return nil, fmt.Errorf("API error (status %d): %v", resp.StatusCode, errorResp["message"])
+// GetCurrentWeather fetches current weather for a place.
+func (c *Client) GetCurrentWeather(ctx context.Context, place Place, units string) (*CurrentWeather, error) {
+	if err := validateUnits(units); err != nil {
+		return nil, err
 	}
 
-	// Parse response
+	params := coordinates(place)
+	params.Set("units", units)
+
 	var apiResp map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.makeRequest(ctx, "/data/2.5/weather", params, &apiResp); err != nil {
+		return nil, err
 	}
 
-	return apiResp, nil
+	current, err := c.parseCurrentWeather(apiResp, units)
+	if err != nil {
+		return nil, err
+	}
+	if place.Name != "" {
+		current.Location = place.DisplayName()
+	}
+	return current, nil
 }
 
-// GetCurrentWeather fetches current weather for a location.
-func (c *Client) GetCurrentWeather(ctx context.Context, location, units string) (*CurrentWeather, error) {
-	// Validate inputs
-	if err := c.validateInputs(location, units); err != nil {
+// GetForecast fetches the 5-day forecast for a place. Hourly entries are the
+// API's 3-hour steps; daily entries aggregate them per local day.
+func (c *Client) GetForecast(ctx context.Context, place Place, units string, granularity Granularity) (*ForecastData, error) {
+	if err := validateUnits(units); err != nil {
 		return nil, err
 	}
-
-	// Make API request
-	apiResp, err := c.makeRequest(ctx, "/weather", location, units)
-	if err != nil {
+	if err := validateGranularity(granularity); err != nil {
 		return nil, err
 	}
 
-	// Extract weather data
-	return c.parseCurrentWeather(apiResp, units)
-}
+	params := coordinates(place)
+	params.Set("units", units)
 
-// GetForecast fetches 5-day forecast for a location.
-func (c *Client) GetForecast(ctx context.Context, location, units string) (*ForecastData, error) {
-	// Validate inputs
-	if err := c.validateInputs(location, units); err != nil {
+	var apiResp map[string]interface{}
+	if err := c.makeRequest(ctx, "/data/2.5/forecast", params, &apiResp); err != nil {
 		return nil, err
 	}
 
-	// Make API request
-	apiResp, err := c.makeRequest(ctx, "/forecast", location, units)
+	forecast, err := c.parseForecast(apiResp, units)
 	if err != nil {
 		return nil, err
 	}
+	if place.Name != "" {
+		forecast.Location = place.DisplayName()
+	}
+	if granularity == GranularityDaily {
+		forecast.Forecasts = dailyForecasts(forecast.Forecasts, forecast.UTCOffset)
+	}
+	forecast.Granularity = granularity
+	return forecast, nil
+}
+
+// GetAlerts fetches active alerts from the One Call 3.0 API. Keys without a
+// One Call subscription are rejected by the API and report ErrAlertsUnsupported.
+func (c *Client) GetAlerts(ctx context.Context, place Place) ([]Alert, error) {
+	params := coordinates(place)
+	params.Set("exclude", "current,minutely,hourly,daily")
+
+	var apiResp struct {
+		Alerts []struct {
+			SenderName  string `json:"sender_name"`
+			Event       string `json:"event"`
+			Start       int64  `json:"start"`
+			End         int64  `json:"end"`
+			Description string `json:"description"`
+		} `json:"alerts"`
+	}
+	if err := c.makeRequest(ctx, "/data/3.0/onecall", params, &apiResp); err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && (apiErr.status == http.StatusUnauthorized || apiErr.status == http.StatusForbidden) {
+			return nil, ErrAlertsUnsupported
+		}
+		return nil, err
+	}
 
-	// Extract forecast data
-	return c.parseForecast(apiResp, units)
+	alerts := make([]Alert, 0, len(apiResp.Alerts))
+	for _, a := range apiResp.Alerts {
+		alerts = append(alerts, Alert{
+			Event:       a.Event,
+			Sender:      a.SenderName,
+			Start:       a.Start,
+			End:         a.End,
+			Description: a.Description,
+		})
+	}
+	return alerts, nil
 }
 
 // parseCurrentWeather extracts current weather data from API response.
@@ -171,14 +203,19 @@ func (c *Client) parseCurrentWeather(apiResp map[string]interface{}, units strin
 	}
 
 	name, _ := apiResp["name"].(string)
+	temp, _ := main["temp"].(float64)
+	feelsLike, _ := main["feels_like"].(float64)
+	humidity, _ := main["humidity"].(float64)
+	pressure, _ := main["pressure"].(float64)
+	desc, _ := weatherData["description"].(string)
 
 	return &CurrentWeather{
 		Location:    name,
-		Temperature: main["temp"].(float64),
-		FeelsLike:   main["feels_like"].(float64),
-		Humidity:    int(main["humidity"].(float64)),
-		Pressure:    int(main["pressure"].(float64)),
-		Description: weatherData["description"].(string),
+		Temperature: temp,
+		FeelsLike:   feelsLike,
+		Humidity:    int(humidity),
+		Pressure:    int(pressure),
+		Description: desc,
 		WindSpeed:   windSpeed,
 		Units:       units,
 	}, nil
@@ -192,6 +229,7 @@ func (c *Client) parseForecast(apiResp map[string]interface{}, units string) (*F
 		return nil, fmt.Errorf("invalid response format: missing 'city' field")
 	}
 	cityName, _ := city["name"].(string)
+	offset, _ := city["timezone"].(float64)
 
 	// Extract forecast list
 	list, ok := apiResp["list"].([]interface{})
@@ -209,9 +247,11 @@ func (c *Client) parseForecast(apiResp map[string]interface{}, units string) (*F
 	}
 
 	return &ForecastData{
-		Location:  cityName,
-		Forecasts: forecasts,
-		Units:     units,
+		Location:    cityName,
+		Forecasts:   forecasts,
+		Units:       units,
+		Granularity: GranularityHourly,
+		UTCOffset:   int(offset),
 	}, nil
 }
 
@@ -240,10 +280,83 @@ func (c *Client) parseForecastEntry(item interface{}) *ForecastEntry {
 	dt, _ := entry["dt"].(float64)
 	temp, _ := main["temp"].(float64)
 	desc, _ := weatherData["description"].(string)
+	pop, _ := entry["pop"].(float64)
 
 	return &ForecastEntry{
-		Timestamp:   int64(dt),
-		Temperature: temp,
-		Description: desc,
+		Timestamp:           int64(dt),
+		Temperature:         temp,
+		TempMin:             temp,
+		TempMax:             temp,
+		Description:         desc,
+		PrecipitationChance: int(pop*100 + 0.5),
+	}
+}
+
+// dailyForecasts aggregates forecast steps into one entry per local day:
+// min/max/mean temperature, the highest precipitation chance and the most
+// frequent description.
+func dailyForecasts(steps []ForecastEntry, utcOffset int) []ForecastEntry {
+	zone := time.FixedZone("", utcOffset)
+
+	type day struct {
+		entry  ForecastEntry
+		sum    float64
+		count  int
+		counts map[string]int
+	}
+	var order []string
+	days := make(map[string]*day)
+
+	for _, step := range steps {
+		local := time.Unix(step.Timestamp, 0).In(zone)
+		key := local.Format("2006-01-02")
+		d, ok := days[key]
+		if !ok {
+			midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, zone)
+			d = &day{
+				entry:  ForecastEntry{Timestamp: midnight.Unix(), TempMin: step.TempMin, TempMax: step.TempMax},
+				counts: make(map[string]int),
+			}
+			days[key] = d
+			order = append(order, key)
+		}
+		d.sum += step.Temperature
+		d.count++
+		d.counts[step.Description]++
+		if step.TempMin < d.entry.TempMin {
+			d.entry.TempMin = step.TempMin
+		}
+		if step.TempMax > d.entry.TempMax {
+			d.entry.TempMax = step.TempMax
+		}
+		if step.PrecipitationChance > d.entry.PrecipitationChance {
+			d.entry.PrecipitationChance = step.PrecipitationChance
+		}
+	}
+
+	result := make([]ForecastEntry, 0, len(order))
+	for _, key := range order {
+		d := days[key]
+		d.entry.Temperature = d.sum / float64(d.count)
+		d.entry.Description = mostFrequent(d.counts)
+		result = append(result, d.entry)
+	}
+	return result
+}
+
+// mostFrequent returns the most frequent key, breaking ties alphabetically.
+func mostFrequent(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	best := ""
+	for _, k := range keys {
+		if best == "" || counts[k] > counts[best] {
+			best = k
+		}
 	}
+	return best
 }
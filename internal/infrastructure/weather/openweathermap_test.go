@@ -3,6 +3,7 @@ package weather_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +11,8 @@ import (
 	"nuimanbot/internal/infrastructure/weather"
 )
 
+var london = weather.Place{Name: "London", Region: "England", Country: "GB", Latitude: 51.5073, Longitude: -0.1276}
+
 func TestNewClient(t *testing.T) {
 	client := weather.NewClient("test-api-key", 10)
 	if client == nil {
@@ -42,8 +45,11 @@ func TestGetCurrentWeather_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Verify request parameters
 		q := r.URL.Query()
-		if q.Get("q") != "London" {
-			t.Errorf("Expected location 'London', got '%s'", q.Get("q"))
+		if r.URL.Path != "/data/2.5/weather" {
+			t.Errorf("Expected path '/data/2.5/weather', got '%s'", r.URL.Path)
+		}
+		if q.Get("lat") != "51.5073" || q.Get("lon") != "-0.1276" {
+			t.Errorf("Expected coordinates 51.5073,-0.1276, got %s,%s", q.Get("lat"), q.Get("lon"))
 		}
 		if q.Get("units") != "metric" {
 			t.Errorf("Expected units 'metric', got '%s'", q.Get("units"))
@@ -60,13 +66,13 @@ func TestGetCurrentWeather_Success(t *testing.T) {
 	client := weather.NewClientWithBaseURL("test-api-key", 10, server.URL)
 	ctx := context.Background()
 
-	result, err := client.GetCurrentWeather(ctx, "London", "metric")
+	result, err := client.GetCurrentWeather(ctx, london, "metric")
 	if err != nil {
 		t.Fatalf("GetCurrentWeather() error = %v", err)
 	}
 
-	if result.Location != "London" {
-		t.Errorf("Expected location 'London', got '%s'", result.Location)
+	if result.Location != "London, England, GB" {
+		t.Errorf("Expected location 'London, England, GB', got '%s'", result.Location)
 	}
 	if result.Temperature != 15.5 {
 		t.Errorf("Expected temperature 15.5, got %f", result.Temperature)
@@ -93,7 +99,7 @@ func TestGetCurrentWeather_InvalidAPIKey(t *testing.T) {
 	client := weather.NewClientWithBaseURL("invalid-key", 10, server.URL)
 	ctx := context.Background()
 
-	_, err := client.GetCurrentWeather(ctx, "London", "metric")
+	_, err := client.GetCurrentWeather(ctx, london, "metric")
 	if err == nil {
 		t.Fatal("Expected error for invalid API key, got nil")
 	}
@@ -113,7 +119,7 @@ func TestGetCurrentWeather_LocationNotFound(t *testing.T) {
 	client := weather.NewClientWithBaseURL("test-api-key", 10, server.URL)
 	ctx := context.Background()
 
-	_, err := client.GetCurrentWeather(ctx, "NonexistentCity", "metric")
+	_, err := client.Geocode(ctx, "NonexistentCity")
 	if err == nil {
 		t.Fatal("Expected error for nonexistent city, got nil")
 	}
@@ -153,9 +159,8 @@ func TestGetForecast_Success(t *testing.T) {
 
 	// Create mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		q := r.URL.Query()
-		if q.Get("q") != "London" {
-			t.Errorf("Expected location 'London', got '%s'", q.Get("q"))
+		if r.URL.Path != "/data/2.5/forecast" {
+			t.Errorf("Expected path '/data/2.5/forecast', got '%s'", r.URL.Path)
 		}
 
 		w.WriteHeader(http.StatusOK)
@@ -166,13 +171,13 @@ func TestGetForecast_Success(t *testing.T) {
 	client := weather.NewClientWithBaseURL("test-api-key", 10, server.URL)
 	ctx := context.Background()
 
-	result, err := client.GetForecast(ctx, "London", "metric")
+	result, err := client.GetForecast(ctx, london, "metric", weather.GranularityHourly)
 	if err != nil {
 		t.Fatalf("GetForecast() error = %v", err)
 	}
 
-	if result.Location != "London" {
-		t.Errorf("Expected location 'London', got '%s'", result.Location)
+	if result.Location != "London, England, GB" {
+		t.Errorf("Expected location 'London, England, GB', got '%s'", result.Location)
 	}
 	if len(result.Forecasts) != 2 {
 		t.Errorf("Expected 2 forecasts, got %d", len(result.Forecasts))
@@ -186,7 +191,7 @@ func TestGetCurrentWeather_EmptyLocation(t *testing.T) {
 	client := weather.NewClient("test-api-key", 10)
 	ctx := context.Background()
 
-	_, err := client.GetCurrentWeather(ctx, "", "metric")
+	_, err := client.Geocode(ctx, "")
 	if err == nil {
 		t.Fatal("Expected error for empty location, got nil")
 	}
@@ -196,8 +201,137 @@ func TestGetCurrentWeather_InvalidUnits(t *testing.T) {
 	client := weather.NewClient("test-api-key", 10)
 	ctx := context.Background()
 
-	_, err := client.GetCurrentWeather(ctx, "London", "invalid")
+	_, err := client.GetCurrentWeather(ctx, london, "invalid")
 	if err == nil {
 		t.Fatal("Expected error for invalid units, got nil")
 	}
 }
+
+func TestGeocode_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/geo/1.0/direct" {
+			t.Errorf("Expected path '/geo/1.0/direct', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("q") != "London, GB" {
+			t.Errorf("Expected query 'London, GB', got '%s'", r.URL.Query().Get("q"))
+		}
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"name": "London", "state": "England", "country": "GB", "lat": 51.5073, "lon": -0.1276},
+		})
+	}))
+	defer server.Close()
+
+	client := weather.NewClientWithBaseURL("test-api-key", 10, server.URL)
+
+	place, err := client.Geocode(context.Background(), "London, GB")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if *place != london {
+		t.Errorf("Geocode() = %+v, want %+v", *place, london)
+	}
+}
+
+func TestGeocode_NoResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := weather.NewClientWithBaseURL("test-api-key", 10, server.URL)
+
+	if _, err := client.Geocode(context.Background(), "Atlantis"); err == nil {
+		t.Fatal("Expected error for unknown location, got nil")
+	}
+}
+
+func TestGetForecast_Daily(t *testing.T) {
+	// Three steps on Jan 1 and one on Jan 2 in UTC+1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"city": {"name": "Paris", "timezone": 3600},
+			"list": [
+				{"dt": 1609459200, "main": {"temp": 4.0}, "weather": [{"description": "rain"}], "pop": 0.2},
+				{"dt": 1609470000, "main": {"temp": 8.0}, "weather": [{"description": "rain"}], "pop": 0.75},
+				{"dt": 1609480800, "main": {"temp": 6.0}, "weather": [{"description": "cloudy"}]},
+				{"dt": 1609542000, "main": {"temp": 2.0}, "weather": [{"description": "snow"}]}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := weather.NewClientWithBaseURL("test-api-key", 10, server.URL)
+
+	result, err := client.GetForecast(context.Background(), weather.Place{Name: "Paris"}, "metric", weather.GranularityDaily)
+	if err != nil {
+		t.Fatalf("GetForecast() error = %v", err)
+	}
+
+	if result.Granularity != weather.GranularityDaily {
+		t.Errorf("Expected daily granularity, got %s", result.Granularity)
+	}
+	if len(result.Forecasts) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(result.Forecasts))
+	}
+
+	day := result.Forecasts[0]
+	if day.TempMin != 4.0 || day.TempMax != 8.0 || day.Temperature != 6.0 {
+		t.Errorf("Expected min 4, max 8, mean 6, got %+v", day)
+	}
+	if day.Description != "rain" {
+		t.Errorf("Expected most frequent description 'rain', got '%s'", day.Description)
+	}
+	if day.PrecipitationChance != 75 {
+		t.Errorf("Expected precipitation chance 75, got %d", day.PrecipitationChance)
+	}
+	if day.Timestamp != 1609455600 {
+		t.Errorf("Expected local midnight 1609455600, got %d", day.Timestamp)
+	}
+}
+
+func TestGetForecast_InvalidGranularity(t *testing.T) {
+	client := weather.NewClient("test-api-key", 10)
+
+	if _, err := client.GetForecast(context.Background(), london, "metric", "weekly"); err == nil {
+		t.Fatal("Expected error for invalid granularity, got nil")
+	}
+}
+
+func TestGetAlerts_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/3.0/onecall" {
+			t.Errorf("Expected path '/data/3.0/onecall', got '%s'", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"alerts": [
+				{"sender_name": "Met Office", "event": "Yellow wind warning", "start": 1609459200, "end": 1609502400, "description": "Strong winds"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := weather.NewClientWithBaseURL("test-api-key", 10, server.URL)
+
+	alerts, err := client.GetAlerts(context.Background(), london)
+	if err != nil {
+		t.Fatalf("GetAlerts() error = %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Event != "Yellow wind warning" || alerts[0].Sender != "Met Office" {
+		t.Errorf("Unexpected alerts: %+v", alerts)
+	}
+}
+
+func TestGetAlerts_NoSubscription(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"cod": 401, "message": "Please note that using One Call 3.0 requires a separate subscription"}`))
+	}))
+	defer server.Close()
+
+	client := weather.NewClientWithBaseURL("test-api-key", 10, server.URL)
+
+	_, err := client.GetAlerts(context.Background(), london)
+	if !errors.Is(err, weather.ErrAlertsUnsupported) {
+		t.Errorf("Expected ErrAlertsUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Granularity selects the spacing of forecast entries.
+type Granularity string
+
+const (
+	// GranularityHourly returns the provider's finest steps (1h or 3h).
+	GranularityHourly Granularity = "hourly"
+	// GranularityDaily returns one entry per local day with min/max temperatures.
+	GranularityDaily Granularity = "daily"
+)
+
+// ErrAlertsUnsupported is returned by providers that cannot supply weather
+// alerts, either at all or with the configured plan.
+var ErrAlertsUnsupported = errors.New("weather alerts are not available from this provider")
+
+// Provider fetches weather data. Locations are resolved once with Geocode and
+// every other call works on the resulting coordinates.
+type Provider interface {
+	// Name identifies the provider in output and logs.
+	Name() string
+	// Geocode resolves a free-text location such as "Paris, FR".
+	Geocode(ctx context.Context, query string) (*Place, error)
+	// GetCurrentWeather fetches current conditions at a place.
+	GetCurrentWeather(ctx context.Context, place Place, units string) (*CurrentWeather, error)
+	// GetForecast fetches the forecast at a place.
+	GetForecast(ctx context.Context, place Place, units string, granularity Granularity) (*ForecastData, error)
+	// GetAlerts fetches active weather alerts at a place, or ErrAlertsUnsupported.
+	GetAlerts(ctx context.Context, place Place) ([]Alert, error)
+}
+
+// Place is a geocoded location.
+type Place struct {
+	Name      string
+	Region    string
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+// DisplayName returns the place as "Name, Region, Country", skipping empty parts.
+func (p Place) DisplayName() string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{p.Name, p.Region, p.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%.4f, %.4f", p.Latitude, p.Longitude)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CurrentWeather represents current weather data for a location.
+type CurrentWeather struct {
+	Location    string
+	Temperature float64
+	FeelsLike   float64
+	Humidity    int
+	Pressure    int
+	Description string
+	WindSpeed   float64
+	Units       string
+}
+
+// ForecastData represents forecast weather data for a location.
+type ForecastData struct {
+	Location    string
+	Forecasts   []ForecastEntry
+	Units       string
+	Granularity Granularity
+	// UTCOffset is the location's offset from UTC in seconds, for local times.
+	UTCOffset int
+}
+
+// ForecastEntry represents a single forecast entry. Daily entries also set
+// TempMin and TempMax; Temperature is then the day's mean.
+type ForecastEntry struct {
+	Timestamp   int64
+	Temperature float64
+	TempMin     float64
+	TempMax     float64
+	Description string
+	// PrecipitationChance is the probability of precipitation in percent.
+	PrecipitationChance int
+}
+
+// Alert is an active weather warning issued for a place.
+type Alert struct {
+	Event       string
+	Sender      string
+	Start       int64
+	End         int64
+	Description string
+}
+
+// validateUnits checks the units parameter shared by all providers.
+func validateUnits(units string) error {
+	if units != "metric" && units != "imperial" && units != "standard" {
+		return fmt.Errorf("invalid units: %s (must be metric, imperial, or standard)", units)
+	}
+	return nil
+}
+
+// validateGranularity checks the forecast granularity parameter.
+func validateGranularity(granularity Granularity) error {
+	if granularity != GranularityHourly && granularity != GranularityDaily {
+		return fmt.Errorf("invalid granularity: %s (must be hourly or daily)", granularity)
+	}
+	return nil
+}
+
+// getJSON performs a GET request and decodes the JSON response into out.
+// errField names the field of an error response that carries the message.
+func getJSON(ctx context.Context, httpClient *http.Client, fullURL, errField string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&errorResp) //nolint:errcheck // Best effort error message extraction
+		return &apiError{status: resp.StatusCode, message: fmt.Sprint(errorResp[errField])}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// apiError is a non-200 response from a weather API.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.status, e.message)
+}
+
+// splitQuery splits "City, Region, Country" into the name and qualifiers.
+func splitQuery(query string) (string, []string) {
+	parts := strings.Split(query, ",")
+	name := strings.TrimSpace(parts[0])
+	var qualifiers []string
+	for _, part := range parts[1:] {
+		if q := strings.TrimSpace(part); q != "" {
+			qualifiers = append(qualifiers, q)
+		}
+	}
+	return name, qualifiers
+}
+
+// matchesQualifiers reports whether every qualifier names the place's region
+// or country (by name or ISO code), case-insensitively.
+func matchesQualifiers(qualifiers []string, values ...string) bool {
+	for _, q := range qualifiers {
+		found := false
+		for _, v := range values {
+			if v != "" && strings.EqualFold(q, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -3,12 +3,14 @@ package calculator
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 
 	"nuimanbot/internal/domain"
 )
 
-// Calculator implements the domain.Tool interface for basic arithmetic operations.
+// Calculator implements the domain.Tool interface for arithmetic and expression evaluation.
 type Calculator struct {
 	config domain.ToolConfig
 }
@@ -29,7 +31,10 @@ func (c *Calculator) Name() string {
 
 // Description returns a description of the calculator tool.
 func (c *Calculator) Description() string {
-	return "Performs basic arithmetic operations: add, subtract, multiply, divide"
+	return "Performs arithmetic: add, subtract, multiply and divide two numbers, or evaluate an expression. " +
+		"Expressions support + - * / ^, parentheses, percentages (4.5%), variables (r = 4.5%; 10000 * (1 + r)^7), " +
+		"exact decimal arithmetic, the constants pi and e, the functions " + strings.Join(functionNames(), ", ") +
+		", and units with conversions (3 ft + 2 in to cm, 60 mph to km/h, 100 degF to degC, 2 GiB to MB)."
 }
 
 // InputSchema returns the JSON schema for the calculator's input parameters.
@@ -40,7 +45,7 @@ func (c *Calculator) InputSchema() map[string]any {
 			"operation": map[string]any{
 				"type":        "string",
 				"description": "The arithmetic operation to perform",
-				"enum":        []string{"add", "subtract", "multiply", "divide"},
+				"enum":        []string{"add", "subtract", "multiply", "divide", "evaluate"},
 			},
 			"a": map[string]any{
 				"type":        "number",
@@ -50,8 +55,17 @@ func (c *Calculator) InputSchema() map[string]any {
 				"type":        "number",
 				"description": "The second operand",
 			},
+			"expression": map[string]any{
+				"type":        "string",
+				"description": "Expression for evaluate; statements may be separated by ';'",
+			},
+			"variables": map[string]any{
+				"type":                 "object",
+				"description":          "Variable values for evaluate, e.g. {\"principal\": 10000}",
+				"additionalProperties": map[string]any{"type": []string{"number", "string"}},
+			},
 		},
-		"required": []string{"operation", "a", "b"},
+		"required": []string{"operation"},
 	}
 }
 
@@ -64,6 +78,9 @@ func (c *Calculator) Execute(ctx context.Context, params map[string]any) (*domai
 			Error: "missing or invalid 'operation' parameter",
 		}, nil
 	}
+	if operation == "evaluate" {
+		return c.evaluate(params), nil
+	}
 
 	a, ok := params["a"].(float64)
 	if !ok {
@@ -120,6 +137,71 @@ func (c *Calculator) Config() domain.ToolConfig {
 	return c.config
 }
 
+// evaluate parses and evaluates an expression.
+func (c *Calculator) evaluate(params map[string]any) *domain.ExecutionResult {
+	expression, ok := params["expression"].(string)
+	if !ok || strings.TrimSpace(expression) == "" {
+		return &domain.ExecutionResult{
+			Error: "missing or invalid 'expression' parameter",
+		}
+	}
+
+	vars, err := parseVariables(params["variables"])
+	if err != nil {
+		return &domain.ExecutionResult{Error: err.Error()}
+	}
+
+	program, err := parse(expression)
+	if err != nil {
+		return &domain.ExecutionResult{Error: fmt.Sprintf("invalid expression: %v", err)}
+	}
+	result, err := newEvaluator(vars).run(program)
+	if err != nil {
+		return &domain.ExecutionResult{Error: fmt.Sprintf("evaluation failed: %v", err)}
+	}
+
+	metadata := map[string]any{"operation": "evaluate", "expression": expression, "exact": result.exact}
+	if len(result.units) > 0 {
+		metadata["unit"] = result.units.String()
+	}
+	return &domain.ExecutionResult{
+		Output:   format(result),
+		Metadata: metadata,
+	}
+}
+
+// parseVariables converts the variables param. Numbers are read through
+// their shortest decimal form so 0.1 stays exactly 0.1.
+func parseVariables(raw any) (map[string]value, error) {
+	vars := map[string]value{}
+	if raw == nil {
+		return vars, nil
+	}
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'variables' parameter: expected an object")
+	}
+	for name, v := range m {
+		var text string
+		switch v := v.(type) {
+		case float64:
+			text = strconv.FormatFloat(v, 'g', -1, 64)
+		case int:
+			text = strconv.Itoa(v)
+		case string:
+			text = strings.TrimSpace(v)
+		default:
+			return nil, fmt.Errorf("invalid value for variable %q: expected a number", name)
+		}
+		r, ok := new(big.Rat).SetString(text)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for variable %q: %s", name, text)
+		}
+		vars[name] = exactValue(r)
+	}
+	return vars, nil
+}
+
 // formatResult formats the result as a string, removing unnecessary decimal places.
 func formatResult(f float64) string {
 	// If the result is a whole number, format without decimals
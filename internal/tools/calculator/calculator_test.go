@@ -2,6 +2,7 @@ package calculator_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"nuimanbot/internal/tools/calculator"
@@ -207,3 +208,111 @@ func TestCalculator_Config(t *testing.T) {
 	// Check that config is returned (basic smoke test)
 	_ = config
 }
+
+func TestCalculator_Execute_Evaluate(t *testing.T) {
+	calc := calculator.NewCalculator()
+	ctx := context.Background()
+
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"2 + 3 * 4", "14"},
+		{"(2 + 3) * 4", "20"},
+		{"2^3^2", "512"},
+		{"-2^2", "-4"},
+		{"0.1 + 0.2", "0.3"},
+		{"1/3", "0.333333333333333"},
+		{"10000 * (1 + 4.5%)^7", "13608.61830465653828125"},
+		{"round(10000 * (1 + 4.5%)^7, 2)", "13608.62"},
+		{"r = 4.5%; p = 10000\np * (1 + r)^7 - p", "3608.61830465653828125"},
+		{"sqrt(16) + abs(-2)", "6"},
+		{"sqrt(2)", "1.4142135623731"},
+		{"round(sin(30 deg), 10)", "0.5"},
+		{"log(1000) + ln(e)", "4"},
+		{"mod(-7, 3)", "2"},
+		{"max(1 km, 500 m)", "1 km"},
+		{"floor(-2.5) + ceil(2.1)", "0"},
+		{"60 mph to km/h", "96.56064 km/h"},
+		{"3 ft + 2 in to cm", "96.52 cm"},
+		{"5 km / 2 h", "2.5 km/h"},
+		{"100 degF to degC", "37.777777777777778 degC"},
+		{"30 °C in K", "303.15 K"},
+		{"2 GiB to MB", "2147.483648 MB"},
+		{"sqrt(25 m^2)", "5 m"},
+		{"3 kg * 9.81 m/s^2 to N", "29.43 N"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			result, err := calc.Execute(ctx, map[string]any{"operation": "evaluate", "expression": tt.expression})
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if result.Error != "" {
+				t.Fatalf("Expected no error, got: %s", result.Error)
+			}
+			if result.Output != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, result.Output)
+			}
+		})
+	}
+}
+
+func TestCalculator_Execute_EvaluateVariables(t *testing.T) {
+	calc := calculator.NewCalculator()
+
+	result, err := calc.Execute(context.Background(), map[string]any{
+		"operation":  "evaluate",
+		"expression": "principal * (1 + rate)^years",
+		"variables":  map[string]any{"principal": float64(10000), "rate": 0.045, "years": float64(7)},
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Output != "13608.61830465653828125" {
+		t.Errorf("Expected exact result, got %q (error %q)", result.Output, result.Error)
+	}
+	if result.Metadata["exact"] != true {
+		t.Errorf("Expected exact metadata, got %v", result.Metadata["exact"])
+	}
+}
+
+func TestCalculator_Execute_EvaluateErrors(t *testing.T) {
+	calc := calculator.NewCalculator()
+	ctx := context.Background()
+
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{"", "missing or invalid 'expression'"},
+		{"1 +", "unexpected end"},
+		{"2 $ 3", "unexpected character"},
+		{"1/0", "division by zero"},
+		{"unknown + 1", "unknown variable or unit"},
+		{"1 m + 1 s", "cannot add m and s"},
+		{"5 degC + 1 degC", "can only be converted"},
+		{"1 kg to m", "cannot convert kg to m"},
+		{"2^100000", "number too large"},
+		{strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100), "nested"},
+		{strings.Repeat("1+", 3000) + "1", "longer than"},
+		{strings.Repeat("3^5000 + ", 200) + "1", "steps"},
+	}
+
+	for _, tt := range tests {
+		name := tt.expression
+		if len(name) > 20 {
+			name = name[:20]
+		}
+		t.Run(name, func(t *testing.T) {
+			result, err := calc.Execute(ctx, map[string]any{"operation": "evaluate", "expression": tt.expression})
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if !strings.Contains(result.Error, tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q (output %q)", tt.wantErr, result.Error, result.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,607 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// value is a number with an optional unit. Arithmetic is exact on
+// rationals; functions such as sqrt or sin fall back to float64 and clear
+// exact.
+type value struct {
+	num   *big.Rat
+	exact bool
+	units unitList
+}
+
+func exactValue(r *big.Rat) value { return value{num: r, exact: true} }
+
+// evaluator runs parsed statements with a step budget.
+type evaluator struct {
+	vars  map[string]value
+	steps int
+}
+
+func newEvaluator(vars map[string]value) *evaluator {
+	if vars == nil {
+		vars = map[string]value{}
+	}
+	return &evaluator{vars: vars}
+}
+
+// run evaluates every statement and returns the last value.
+func (e *evaluator) run(program []node) (value, error) {
+	var result value
+	for _, stmt := range program {
+		v, err := stmt.eval(e)
+		if err != nil {
+			return value{}, err
+		}
+		result = v
+	}
+	return result, nil
+}
+
+// step charges n steps against the budget.
+func (e *evaluator) step(n int) error {
+	e.steps += n
+	if e.steps > maxSteps {
+		return fmt.Errorf("evaluation exceeded %d steps", maxSteps)
+	}
+	return nil
+}
+
+// checked rejects numbers too large to keep computing with.
+func checked(v value) (value, error) {
+	if v.num.Num().BitLen()+v.num.Denom().BitLen() > maxBits {
+		return value{}, fmt.Errorf("number too large")
+	}
+	return v, nil
+}
+
+func floatValue(f float64, units unitList) (value, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return value{}, fmt.Errorf("result is not a finite number")
+	}
+	return value{num: new(big.Rat).SetFloat64(f), units: units}, nil
+}
+
+func (v value) float() float64 {
+	f, _ := v.num.Float64()
+	return f
+}
+
+func (n numberNode) eval(e *evaluator) (value, error) {
+	if err := e.step(1); err != nil {
+		return value{}, err
+	}
+	r, ok := new(big.Rat).SetString(n.text)
+	if !ok {
+		return value{}, fmt.Errorf("invalid number %q", n.text)
+	}
+	return checked(exactValue(r))
+}
+
+var constants = map[string]float64{"pi": math.Pi, "e": math.E, "tau": 2 * math.Pi}
+
+func (n identNode) eval(e *evaluator) (value, error) {
+	if err := e.step(1); err != nil {
+		return value{}, err
+	}
+	if v, ok := e.vars[n.name]; ok {
+		return v, nil
+	}
+	if c, ok := constants[n.name]; ok {
+		return floatValue(c, nil)
+	}
+	if u, ok := units[n.name]; ok {
+		return value{num: big.NewRat(1, 1), exact: true, units: unitList{{u, 1}}}, nil
+	}
+	if _, ok := functions[n.name]; ok {
+		return value{}, fmt.Errorf("%s is a function; call it as %s(...)", n.name, n.name)
+	}
+	return value{}, fmt.Errorf("unknown variable or unit: %s", n.name)
+}
+
+func (n assignNode) eval(e *evaluator) (value, error) {
+	v, err := n.value.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	e.vars[n.name] = v
+	return v, nil
+}
+
+func (n negateNode) eval(e *evaluator) (value, error) {
+	v, err := n.operand.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	v.num = new(big.Rat).Neg(v.num)
+	return v, nil
+}
+
+func (n percentNode) eval(e *evaluator) (value, error) {
+	v, err := n.operand.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	v.num = new(big.Rat).Quo(v.num, big.NewRat(100, 1))
+	return v, nil
+}
+
+func (n binaryNode) eval(e *evaluator) (value, error) {
+	left, err := n.left.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	right, err := n.right.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	if err := e.step(1); err != nil {
+		return value{}, err
+	}
+
+	switch n.op {
+	case "+", "-":
+		return add(left, right, n.op == "-")
+	case "*", "/":
+		return multiply(left, right, n.op == "/")
+	default:
+		return e.power(left, right)
+	}
+}
+
+// add adds or subtracts, converting the right operand to the left's unit.
+func add(left, right value, subtract bool) (value, error) {
+	if left.units.hasAffine() || right.units.hasAffine() {
+		return value{}, fmt.Errorf("degC and degF can only be converted; use K for temperature arithmetic")
+	}
+	if left.units.dim() != right.units.dim() {
+		return value{}, fmt.Errorf("cannot add %s and %s", describeUnits(left.units), describeUnits(right.units))
+	}
+	r := new(big.Rat).Mul(right.num, right.units.scale())
+	r.Quo(r, left.units.scale())
+	if subtract {
+		r.Neg(r)
+	}
+	return checked(value{num: r.Add(r, left.num), exact: left.exact && right.exact, units: left.units})
+}
+
+// multiply multiplies or divides, combining units.
+func multiply(left, right value, divide bool) (value, error) {
+	if right.num.Sign() == 0 && divide {
+		return value{}, fmt.Errorf("division by zero")
+	}
+	// A temperature with an offset may only be scaled by a plain number
+	if (left.units.hasAffine() && len(right.units) > 0) || (right.units.hasAffine() && (len(left.units) > 0 || divide)) {
+		return value{}, fmt.Errorf("degC and degF can only be converted; use K for temperature arithmetic")
+	}
+
+	result := value{num: new(big.Rat), exact: left.exact && right.exact}
+	sign := 1
+	if divide {
+		result.num.Quo(left.num, right.num)
+		sign = -1
+	} else {
+		result.num.Mul(left.num, right.num)
+	}
+	result.units = left.units.mul(right.units, sign)
+	return checked(simplify(result))
+}
+
+// simplify folds dimensionless units such as km/m or deg into the number.
+func simplify(v value) value {
+	if len(v.units) == 0 || v.units.dim() != (dimension{}) {
+		return v
+	}
+	v.num = new(big.Rat).Mul(v.num, v.units.scale())
+	v.exact = v.exact && !v.units.approx()
+	v.units = nil
+	return v
+}
+
+// power raises to a power: exactly for integer exponents of exact numbers,
+// otherwise in float64. Units need an integer exponent.
+func (e *evaluator) power(base, exp value) (value, error) {
+	if len(exp.units) > 0 {
+		return value{}, fmt.Errorf("exponent must be a plain number, not %s", describeUnits(exp.units))
+	}
+	if base.units.hasAffine() {
+		return value{}, fmt.Errorf("degC and degF can only be converted; use K for temperature arithmetic")
+	}
+
+	if !exp.num.IsInt() || !exp.num.Num().IsInt64() {
+		if len(base.units) > 0 {
+			return value{}, fmt.Errorf("units can only be raised to integer powers")
+		}
+		return floatValue(math.Pow(base.float(), exp.float()), nil)
+	}
+
+	n := exp.num.Num().Int64()
+	if base.num.Sign() == 0 && n < 0 {
+		return value{}, fmt.Errorf("division by zero")
+	}
+	if abs64(n) > math.MaxInt32 {
+		return value{}, fmt.Errorf("exponent too large")
+	}
+	units := base.units.pow(int(n))
+	if !base.exact {
+		return floatValue(math.Pow(base.float(), float64(n)), units)
+	}
+
+	// Estimate the result size before computing it
+	size := int64(base.num.Num().BitLen() + base.num.Denom().BitLen() - 2)
+	if size > 0 && abs64(n)*size > maxBits {
+		return value{}, fmt.Errorf("number too large")
+	}
+	// Charge for the multiplications, which grow with the result size
+	if err := e.step(bits.Len64(uint64(abs64(n))) * int(1+abs64(n)*size/64)); err != nil {
+		return value{}, err
+	}
+	return checked(value{num: ratPow(base.num, int(n)), exact: true, units: units})
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func (n convertNode) eval(e *evaluator) (value, error) {
+	v, err := n.operand.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	if err := e.step(1); err != nil {
+		return value{}, err
+	}
+	return convert(v, n.target)
+}
+
+// convert expresses v in the target unit.
+func convert(v value, target unitList) (value, error) {
+	if v.units.dim() != target.dim() {
+		return value{}, fmt.Errorf("cannot convert %s to %s", describeUnits(v.units), describeUnits(target))
+	}
+
+	from, to := v.units.affine(), target.affine()
+	if (from != nil || to != nil) && (len(v.units) != 1 || len(target) != 1) {
+		return value{}, fmt.Errorf("degC and degF can only be converted to other temperatures")
+	}
+
+	// Through base units: base = x*scale + offset
+	r := new(big.Rat).Mul(v.num, v.units.scale())
+	if from != nil {
+		r.Add(r, from.offset)
+	}
+	if to != nil {
+		r.Sub(r, to.offset)
+	}
+	r.Quo(r, target.scale())
+	exact := v.exact && !v.units.approx() && !target.approx()
+	return checked(value{num: r, exact: exact, units: target})
+}
+
+func describeUnits(l unitList) string {
+	if len(l) == 0 {
+		return "a plain number"
+	}
+	return l.String()
+}
+
+// function is a built-in function; arity -1 accepts one or more arguments.
+type function struct {
+	minArgs, maxArgs int
+	call             func(args []value) (value, error)
+}
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"sqrt":  {1, 1, func(a []value) (value, error) { return root(a[0], 2) }},
+		"cbrt":  {1, 1, func(a []value) (value, error) { return root(a[0], 3) }},
+		"abs":   {1, 1, func(a []value) (value, error) { return withNum(a[0], new(big.Rat).Abs(a[0].num)), nil }},
+		"round": {1, 2, roundFunc(roundHalfAway)},
+		"floor": {1, 2, roundFunc(roundFloor)},
+		"ceil":  {1, 2, roundFunc(roundCeil)},
+		"trunc": {1, 1, roundFunc(roundTrunc)},
+		"exp":   {1, 1, floatFunc(math.Exp)},
+		"ln":    {1, 1, floatFunc(math.Log)},
+		"log10": {1, 1, floatFunc(math.Log10)},
+		"log2":  {1, 1, floatFunc(math.Log2)},
+		"log":   {1, 2, logFunc},
+		"sin":   {1, 1, floatFunc(math.Sin)},
+		"cos":   {1, 1, floatFunc(math.Cos)},
+		"tan":   {1, 1, floatFunc(math.Tan)},
+		"asin":  {1, 1, floatFunc(math.Asin)},
+		"acos":  {1, 1, floatFunc(math.Acos)},
+		"atan":  {1, 1, floatFunc(math.Atan)},
+		"sinh":  {1, 1, floatFunc(math.Sinh)},
+		"cosh":  {1, 1, floatFunc(math.Cosh)},
+		"tanh":  {1, 1, floatFunc(math.Tanh)},
+		"atan2": {2, 2, func(a []value) (value, error) { return floatValue(math.Atan2(a[0].float(), a[1].float()), nil) }},
+		"mod":   {2, 2, modFunc},
+		"min":   {1, -1, extremeFunc(-1)},
+		"max":   {1, -1, extremeFunc(1)},
+	}
+}
+
+// functionNames lists the built-in functions for the tool description.
+func functionNames() []string {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (n callNode) eval(e *evaluator) (value, error) {
+	fn, ok := functions[n.name]
+	if !ok {
+		return value{}, fmt.Errorf("unknown function: %s", n.name)
+	}
+	if len(n.args) < fn.minArgs || (fn.maxArgs >= 0 && len(n.args) > fn.maxArgs) {
+		return value{}, fmt.Errorf("wrong number of arguments for %s: %d", n.name, len(n.args))
+	}
+	args := make([]value, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(e)
+		if err != nil {
+			return value{}, err
+		}
+		if v.units.hasAffine() {
+			return value{}, fmt.Errorf("degC and degF can only be converted; use K for temperature arithmetic")
+		}
+		args[i] = v
+	}
+	if err := e.step(1); err != nil {
+		return value{}, err
+	}
+	v, err := fn.call(args)
+	if err != nil {
+		return value{}, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return checked(v)
+}
+
+func withNum(v value, r *big.Rat) value {
+	v.num = r
+	return v
+}
+
+func plain(v value) error {
+	if len(v.units) > 0 {
+		return fmt.Errorf("expected a plain number, got %s", v.units)
+	}
+	return nil
+}
+
+func floatFunc(f func(float64) float64) func([]value) (value, error) {
+	return func(a []value) (value, error) {
+		if err := plain(a[0]); err != nil {
+			return value{}, err
+		}
+		return floatValue(f(a[0].float()), nil)
+	}
+}
+
+func logFunc(a []value) (value, error) {
+	for _, v := range a {
+		if err := plain(v); err != nil {
+			return value{}, err
+		}
+	}
+	if len(a) == 1 {
+		return floatValue(math.Log10(a[0].float()), nil)
+	}
+	return floatValue(math.Log(a[0].float())/math.Log(a[1].float()), nil)
+}
+
+// root takes the nth root, exactly for perfect powers. Unit powers must be
+// divisible by n.
+func root(v value, n int) (value, error) {
+	if n == 2 && v.num.Sign() < 0 {
+		return value{}, fmt.Errorf("square root of a negative number")
+	}
+	units := make(unitList, len(v.units))
+	for i, up := range v.units {
+		if up.power%n != 0 {
+			return value{}, fmt.Errorf("cannot take the root of %s", v.units)
+		}
+		units[i] = unitPower{up.unit, up.power / n}
+	}
+	if len(units) == 0 {
+		units = nil
+	}
+
+	if v.exact {
+		num, okNum := intRoot(v.num.Num(), n)
+		den, okDen := intRoot(v.num.Denom(), n)
+		if okNum && okDen {
+			return value{num: new(big.Rat).SetFrac(num, den), exact: true, units: units}, nil
+		}
+	}
+	if n == 2 {
+		return floatValue(math.Sqrt(v.float()), units)
+	}
+	return floatValue(math.Cbrt(v.float()), units)
+}
+
+// intRoot returns the integer nth root of x if x is a perfect power.
+func intRoot(x *big.Int, n int) (*big.Int, bool) {
+	neg := x.Sign() < 0
+	abs := new(big.Int).Abs(x)
+	var r *big.Int
+	if n == 2 {
+		r = new(big.Int).Sqrt(abs)
+	} else {
+		f, _ := new(big.Float).SetInt(abs).Float64()
+		r = big.NewInt(int64(math.Round(math.Cbrt(f))))
+	}
+	if new(big.Int).Exp(r, big.NewInt(int64(n)), nil).Cmp(abs) != 0 {
+		return nil, false
+	}
+	if neg {
+		r.Neg(r)
+	}
+	return r, true
+}
+
+type roundMode int
+
+const (
+	roundHalfAway roundMode = iota
+	roundFloor
+	roundCeil
+	roundTrunc
+)
+
+// roundFunc rounds to a number of decimals (default 0), exactly.
+func roundFunc(mode roundMode) func([]value) (value, error) {
+	return func(a []value) (value, error) {
+		digits := 0
+		if len(a) > 1 {
+			if err := plain(a[1]); err != nil {
+				return value{}, err
+			}
+			if !a[1].num.IsInt() || !a[1].num.Num().IsInt64() || abs64(a[1].num.Num().Int64()) > 100 {
+				return value{}, fmt.Errorf("decimals must be an integer between -100 and 100")
+			}
+			digits = int(a[1].num.Num().Int64())
+		}
+		scale := ratPow(big.NewRat(10, 1), digits)
+		x := new(big.Rat).Mul(a[0].num, scale)
+		q, m := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int)) // Truncates toward zero
+		if m.Sign() != 0 {
+			switch mode {
+			case roundHalfAway:
+				twice := new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2))
+				if twice.Cmp(x.Denom()) >= 0 {
+					q.Add(q, big.NewInt(int64(x.Sign())))
+				}
+			case roundFloor:
+				if x.Sign() < 0 {
+					q.Sub(q, big.NewInt(1))
+				}
+			case roundCeil:
+				if x.Sign() > 0 {
+					q.Add(q, big.NewInt(1))
+				}
+			}
+		}
+		r := new(big.Rat).SetInt(q)
+		result := withNum(a[0], r.Quo(r, scale))
+		if !a[0].exact {
+			// Rounding a float result yields the decimal it displays as
+			result.exact = true
+		}
+		return result, nil
+	}
+}
+
+func modFunc(a []value) (value, error) {
+	if a[0].units.dim() != a[1].units.dim() {
+		return value{}, fmt.Errorf("cannot take %s modulo %s", describeUnits(a[0].units), describeUnits(a[1].units))
+	}
+	divisor := new(big.Rat).Mul(a[1].num, a[1].units.scale())
+	divisor.Quo(divisor, a[0].units.scale())
+	if divisor.Sign() == 0 {
+		return value{}, fmt.Errorf("division by zero")
+	}
+	// a - divisor*floor(a/divisor), so the result has the divisor's sign
+	q := new(big.Rat).Quo(a[0].num, divisor)
+	floor := new(big.Int).Div(q.Num(), q.Denom()) // Euclidean; adjust for negative divisors
+	if divisor.Sign() < 0 && new(big.Rat).SetInt(floor).Cmp(q) != 0 {
+		floor.Add(floor, big.NewInt(1))
+	}
+	r := new(big.Rat).Mul(divisor, new(big.Rat).SetInt(floor))
+	return value{num: r.Sub(a[0].num, r), exact: a[0].exact && a[1].exact, units: a[0].units}, nil
+}
+
+func extremeFunc(sign int) func([]value) (value, error) {
+	return func(a []value) (value, error) {
+		best := a[0]
+		for _, v := range a[1:] {
+			if v.units.dim() != best.units.dim() {
+				return value{}, fmt.Errorf("cannot compare %s and %s", describeUnits(best.units), describeUnits(v.units))
+			}
+			x := new(big.Rat).Mul(v.num, v.units.scale())
+			y := new(big.Rat).Mul(best.num, best.units.scale())
+			if x.Cmp(y) == sign {
+				best = v
+			}
+		}
+		return best, nil
+	}
+}
+
+// format renders a value. Exact results are written out in full when they
+// have a finite decimal expansion of reasonable length.
+func format(v value) string {
+	s := formatNumber(v)
+	if len(v.units) > 0 {
+		s += " " + v.units.String()
+	}
+	return s
+}
+
+func formatNumber(v value) string {
+	if !v.exact {
+		f := v.float()
+		if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+			return strconv.FormatFloat(f, 'f', 0, 64)
+		}
+		return strconv.FormatFloat(f, 'g', 15, 64)
+	}
+	if v.num.IsInt() {
+		return v.num.Num().String()
+	}
+	if isDecimal(v.num.Denom()) {
+		if digits := decimalDigits(v.num.Denom()); digits <= 20 {
+			return v.num.FloatString(digits)
+		}
+	}
+	return strings.TrimRight(strings.TrimRight(v.num.FloatString(15), "0"), ".")
+}
+
+// isDecimal reports whether 1/d has a finite decimal expansion.
+func isDecimal(d *big.Int) bool {
+	d = new(big.Int).Set(d)
+	m := new(big.Int)
+	for _, p := range []int64{2, 5} {
+		bp := big.NewInt(p)
+		for d.Cmp(big.NewInt(1)) > 0 {
+			q, rem := new(big.Int).QuoRem(d, bp, m)
+			if rem.Sign() != 0 {
+				break
+			}
+			d = q
+		}
+	}
+	return d.Cmp(big.NewInt(1)) == 0
+}
+
+// decimalDigits returns how many decimals 1/d needs, for d = 2^a * 5^b.
+func decimalDigits(d *big.Int) int {
+	digits := 0
+	ten := big.NewInt(10)
+	p := big.NewInt(1)
+	for new(big.Int).Mod(p, d).Sign() != 0 {
+		p.Mul(p, ten)
+		digits++
+		if digits > 20 {
+			break
+		}
+	}
+	return digits
+}
@@ -0,0 +1,435 @@
+package calculator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Limits that keep evaluation cheap whatever the input
+const (
+	maxExpressionLength = 4096
+	maxDepth            = 64
+	maxSteps            = 10000
+	maxBits             = 8192 // Numerator plus denominator, about 2500 digits
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp // + - * / ^ % ( ) , = ;
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// tokenize splits an expression into tokens. "**" is read as "^", "°C"
+// and "°F" as degC and degF, and newlines separate statements like ";".
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			tokens = append(tokens, token{tokOp, ";", i})
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == '_') {
+				i++
+			}
+			// Exponent, e.g. 1.5e-3; "2e" alone stays 2 times e
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					for j < len(runes) && unicode.IsDigit(runes[j]) {
+						j++
+					}
+					i = j
+				}
+			}
+			tokens = append(tokens, token{tokNumber, strings.ReplaceAll(string(runes[start:i]), "_", ""), start})
+		case r == '°' && i+1 < len(runes) && (runes[i+1] == 'C' || runes[i+1] == 'F'):
+			tokens = append(tokens, token{tokIdent, "deg" + string(runes[i+1]), i})
+			i += 2
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, string(runes[start:i]), start})
+		case r == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			tokens = append(tokens, token{tokOp, "^", i})
+			i += 2
+		case strings.ContainsRune("+-*/^%(),=;×÷−", r):
+			text := string(r)
+			switch r {
+			case '×':
+				text = "*"
+			case '÷':
+				text = "/"
+			case '−':
+				text = "-"
+			}
+			tokens = append(tokens, token{tokOp, text, i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i+1)
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(runes)}), nil
+}
+
+// node is a parsed expression.
+type node interface {
+	eval(e *evaluator) (value, error)
+}
+
+type (
+	numberNode  struct{ text string }
+	identNode   struct{ name string }
+	negateNode  struct{ operand node }
+	percentNode struct{ operand node }
+	binaryNode  struct {
+		op          string
+		left, right node
+	}
+	callNode struct {
+		name string
+		args []node
+	}
+	convertNode struct {
+		operand node
+		target  unitList
+	}
+	assignNode struct {
+		name  string
+		value node
+	}
+)
+
+// parser is a recursive descent parser. Grammar, loosest binding first:
+//
+//	program  = stmt { ";" stmt }
+//	stmt     = ident "=" convert | convert
+//	convert  = sum [ ("to" | "in") units ]
+//	sum      = product { ("+" | "-") product }
+//	product  = implicit { ("*" | "/") implicit }
+//	implicit = unary { power }
+//	unary    = ("-" | "+") unary | power
+//	power    = postfix [ "^" unary ]
+//	postfix  = primary { "%" }
+//	primary  = number | ident | ident "(" args ")" | "(" sum ")"
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func parse(src string) ([]node, error) {
+	if len(src) > maxExpressionLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", maxExpressionLength)
+	}
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+
+	var program []node
+	for {
+		for p.peek().text == ";" {
+			p.pos++
+		}
+		if p.peek().kind == tokEOF {
+			break
+		}
+		stmt, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		program = append(program, stmt)
+		if t := p.peek(); t.kind != tokEOF && t.text != ";" {
+			return nil, p.unexpected(t)
+		}
+	}
+	if len(program) == 0 {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	return program, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset < len(p.tokens) {
+		return p.tokens[p.pos+offset]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(op string) error {
+	if t := p.next(); t.text != op || t.kind != tokOp {
+		return fmt.Errorf("expected %q at position %d", op, t.pos+1)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	if t.kind == tokEOF {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+}
+
+func (p *parser) statement() (node, error) {
+	if p.peek().kind == tokIdent && p.peekAt(1).text == "=" {
+		name := p.next().text
+		p.next()
+		value, err := p.convert()
+		if err != nil {
+			return nil, err
+		}
+		return assignNode{name, value}, nil
+	}
+	return p.convert()
+}
+
+// isConversion reports whether the next token starts "to <unit>" or
+// "in <unit>"; any other "in" is the inch.
+func (p *parser) isConversion() bool {
+	t, next := p.peek(), p.peekAt(1)
+	if t.kind != tokIdent || next.kind != tokIdent {
+		return false
+	}
+	_, isUnit := units[next.text]
+	return t.text == "to" || t.text == "in" && isUnit
+}
+
+func (p *parser) convert() (node, error) {
+	n, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isConversion() {
+		return n, nil
+	}
+	p.next()
+	target, err := p.units()
+	if err != nil {
+		return nil, err
+	}
+	return convertNode{n, target}, nil
+}
+
+// units parses a unit expression such as km/h or kg*m/s^2.
+func (p *parser) units() (unitList, error) {
+	var result unitList
+	sign := 1
+	for {
+		t := p.next()
+		u, ok := units[t.text]
+		if t.kind != tokIdent || !ok {
+			return nil, fmt.Errorf("unknown unit %q at position %d", t.text, t.pos+1)
+		}
+		power := 1
+		if p.peek().text == "^" {
+			p.next()
+			neg := p.peek().text == "-"
+			if neg {
+				p.next()
+			}
+			exp := p.next()
+			if exp.kind != tokNumber || strings.ContainsAny(exp.text, ".eE") {
+				return nil, fmt.Errorf("unit powers must be integers at position %d", exp.pos+1)
+			}
+			power, _ = strconv.Atoi(exp.text)
+			if neg {
+				power = -power
+			}
+		}
+		result = result.mul(unitList{{u, power}}, sign)
+
+		switch p.peek().text {
+		case "*":
+			sign = 1
+		case "/":
+			sign = -1
+		default:
+			return result, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxDepth {
+		return fmt.Errorf("expression is nested more than %d levels deep", maxDepth)
+	}
+	return nil
+}
+
+func (p *parser) sum() (node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.text == "+" || t.text == "-"; t = p.peek() {
+		p.next()
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{t.text, left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) product() (node, error) {
+	left, err := p.implicit()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokOp && (t.text == "*" || t.text == "/"); t = p.peek() {
+		p.next()
+		right, err := p.implicit()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{t.text, left, right}
+	}
+	return left, nil
+}
+
+// implicit parses juxtaposed factors such as 5 km or 2(3+4). They bind
+// tighter than "*" and "/", so 5 km / 2 h is a speed.
+func (p *parser) implicit() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokIdent && !p.isConversion() || t.text == "("; t = p.peek() {
+		right, err := p.power()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{"*", left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	switch p.peek().text {
+	case "-":
+		p.next()
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{operand}, nil
+	case "+":
+		p.next()
+		return p.unary()
+	}
+	return p.power()
+}
+
+func (p *parser) power() (node, error) {
+	base, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().text != "^" {
+		return base, nil
+	}
+	p.next()
+	exp, err := p.unary() // Right associative: 2^3^2 = 2^9
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{"^", base, exp}, nil
+}
+
+func (p *parser) postfix() (node, error) {
+	n, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().text == "%" {
+		p.next()
+		n = percentNode{n}
+	}
+	return n, nil
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokNumber:
+		return numberNode{t.text}, nil
+	case t.kind == tokIdent && p.peek().text == "(":
+		p.next()
+		var args []node
+		if p.peek().text != ")" {
+			for {
+				arg, err := p.sum()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if p.peek().text != "," {
+					break
+				}
+				p.next()
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return callNode{t.text, args}, nil
+	case t.kind == tokIdent:
+		return identNode{t.text}, nil
+	case t.text == "(":
+		n, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return nil, p.unexpected(t)
+}
@@ -0,0 +1,276 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Dimensions a unit can have; a unit's dimension is a vector of powers.
+const (
+	dimLength = iota
+	dimMass
+	dimTime
+	dimTemperature
+	dimData
+	dimCount
+)
+
+type dimension [dimCount]int
+
+// unit is a named unit: scale converts it to the base unit of its
+// dimension (m, kg, s, K, byte). offset is set for temperatures whose zero
+// is not absolute zero. approx marks scales that are float approximations.
+type unit struct {
+	name   string
+	dim    dimension
+	scale  *big.Rat
+	offset *big.Rat
+	approx bool
+}
+
+// units maps every accepted spelling to its unit.
+var units = map[string]*unit{}
+
+func defineUnit(names string, dim dimension, scale string) {
+	u := &unit{dim: dim, scale: mustRat(scale)}
+	for i, name := range strings.Fields(names) {
+		if i == 0 {
+			u.name = name
+		}
+		units[name] = u
+	}
+}
+
+func mustRat(s string) *big.Rat {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		panic("invalid unit scale: " + s)
+	}
+	return r
+}
+
+func init() {
+	length := dimension{dimLength: 1}
+	area := dimension{dimLength: 2}
+	volume := dimension{dimLength: 3}
+	mass := dimension{dimMass: 1}
+	duration := dimension{dimTime: 1}
+	speed := dimension{dimLength: 1, dimTime: -1}
+	force := dimension{dimMass: 1, dimLength: 1, dimTime: -2}
+	pressure := dimension{dimMass: 1, dimLength: -1, dimTime: -2}
+	energy := dimension{dimMass: 1, dimLength: 2, dimTime: -2}
+	power := dimension{dimMass: 1, dimLength: 2, dimTime: -3}
+	data := dimension{dimData: 1}
+
+	defineUnit("m meter meters metre metres", length, "1")
+	defineUnit("km kilometer kilometers kilometre kilometres", length, "1000")
+	defineUnit("cm centimeter centimeters", length, "1/100")
+	defineUnit("mm millimeter millimeters", length, "1/1000")
+	defineUnit("um micrometer micrometers", length, "1/1000000")
+	defineUnit("nm nanometer nanometers", length, "1/1000000000")
+	defineUnit("mi mile miles", length, "1609.344")
+	defineUnit("yd yard yards", length, "0.9144")
+	defineUnit("ft foot feet", length, "0.3048")
+	defineUnit("inch inches in", length, "0.0254")
+	defineUnit("nmi", length, "1852")
+
+	defineUnit("ha hectare hectares", area, "10000")
+	defineUnit("acre acres", area, "4046.8564224")
+
+	defineUnit("L l liter liters litre litres", volume, "1/1000")
+	defineUnit("mL ml milliliter milliliters", volume, "1/1000000")
+	defineUnit("gal gallon gallons", volume, "0.003785411784")
+
+	defineUnit("kg kilogram kilograms", mass, "1")
+	defineUnit("g gram grams", mass, "1/1000")
+	defineUnit("mg milligram milligrams", mass, "1/1000000")
+	defineUnit("t tonne tonnes", mass, "1000")
+	defineUnit("lb lbs pound pounds", mass, "0.45359237")
+	defineUnit("oz ounce ounces", mass, "0.028349523125")
+
+	defineUnit("s sec second seconds", duration, "1")
+	defineUnit("ms millisecond milliseconds", duration, "1/1000")
+	defineUnit("min minute minutes", duration, "60")
+	defineUnit("h hr hour hours", duration, "3600")
+	defineUnit("day days", duration, "86400")
+	defineUnit("week weeks", duration, "604800")
+	defineUnit("year years yr", duration, "31557600") // Julian year
+
+	defineUnit("mph", speed, "0.44704")
+	defineUnit("kph kmh", speed, "5/18")
+	defineUnit("knot knots kn", speed, "1852/3600")
+
+	defineUnit("N newton newtons", force, "1")
+	defineUnit("Pa pascal", pressure, "1")
+	defineUnit("kPa", pressure, "1000")
+	defineUnit("bar", pressure, "100000")
+	defineUnit("atm", pressure, "101325")
+	defineUnit("psi", pressure, "6894.757293168")
+
+	defineUnit("J joule joules", energy, "1")
+	defineUnit("kJ", energy, "1000")
+	defineUnit("cal calorie calories", energy, "4.184")
+	defineUnit("kcal", energy, "4184")
+	defineUnit("Wh", energy, "3600")
+	defineUnit("kWh", energy, "3600000")
+	defineUnit("W watt watts", power, "1")
+	defineUnit("kW kilowatt kilowatts", power, "1000")
+	defineUnit("MW megawatt megawatts", power, "1000000")
+	defineUnit("hp horsepower", power, "745.69987158227022")
+
+	defineUnit("B byte bytes", data, "1")
+	defineUnit("bit bits", data, "1/8")
+	defineUnit("KB kB", data, "1000")
+	defineUnit("MB", data, "1000000")
+	defineUnit("GB", data, "1000000000")
+	defineUnit("TB", data, "1000000000000")
+	defineUnit("KiB", data, "1024")
+	defineUnit("MiB", data, "1048576")
+	defineUnit("GiB", data, "1073741824")
+	defineUnit("TiB", data, "1099511627776")
+
+	defineUnit("K kelvin", dimension{dimTemperature: 1}, "1")
+	defineUnit("degC celsius", dimension{dimTemperature: 1}, "1")
+	units["degC"].offset = mustRat("273.15")
+	defineUnit("degF fahrenheit", dimension{dimTemperature: 1}, "5/9")
+	units["degF"].offset = mustRat("45967/180") // 459.67 * 5/9
+
+	// Dimensionless angle units, for trig in degrees
+	defineUnit("rad radian radians", dimension{}, "1")
+	units["deg"] = &unit{name: "deg", scale: new(big.Rat).SetFloat64(math.Pi / 180), approx: true}
+	units["degree"], units["degrees"] = units["deg"], units["deg"]
+}
+
+// unitPower is one factor of a compound unit, e.g. s^-1.
+type unitPower struct {
+	unit  *unit
+	power int
+}
+
+// unitList is a compound unit such as km/h, in the order it was written.
+type unitList []unitPower
+
+func (l unitList) dim() dimension {
+	var d dimension
+	for _, up := range l {
+		for i := range d {
+			d[i] += up.unit.dim[i] * up.power
+		}
+	}
+	return d
+}
+
+// scale returns the factor converting the compound unit to base units.
+func (l unitList) scale() *big.Rat {
+	s := big.NewRat(1, 1)
+	for _, up := range l {
+		s.Mul(s, ratPow(up.unit.scale, up.power))
+	}
+	return s
+}
+
+// approx reports whether any factor has an approximate scale.
+func (l unitList) approx() bool {
+	for _, up := range l {
+		if up.unit.approx {
+			return true
+		}
+	}
+	return false
+}
+
+// affine returns the temperature unit with an offset if l is exactly one.
+func (l unitList) affine() *unit {
+	if len(l) == 1 && l[0].power == 1 && l[0].unit.offset != nil {
+		return l[0].unit
+	}
+	return nil
+}
+
+func (l unitList) hasAffine() bool {
+	for _, up := range l {
+		if up.unit.offset != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// mul combines two compound units, cancelling equal units.
+func (l unitList) mul(other unitList, sign int) unitList {
+	result := append(unitList(nil), l...)
+	for _, up := range other {
+		found := false
+		for i := range result {
+			if result[i].unit == up.unit {
+				result[i].power += sign * up.power
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, unitPower{up.unit, sign * up.power})
+		}
+	}
+	kept := result[:0]
+	for _, up := range result {
+		if up.power != 0 {
+			kept = append(kept, up)
+		}
+	}
+	return kept
+}
+
+func (l unitList) pow(n int) unitList {
+	result := make(unitList, len(l))
+	for i, up := range l {
+		result[i] = unitPower{up.unit, up.power * n}
+	}
+	return result
+}
+
+func (l unitList) String() string {
+	var num, den []string
+	for _, up := range l {
+		name := up.unit.name
+		p := up.power
+		if p < 0 {
+			p = -p
+		}
+		if p != 1 {
+			name = fmt.Sprintf("%s^%d", name, p)
+		}
+		if up.power > 0 {
+			num = append(num, name)
+		} else {
+			den = append(den, name)
+		}
+	}
+	s := strings.Join(num, "*")
+	if len(num) == 0 {
+		s = "1"
+	}
+	if len(den) > 0 {
+		s += "/" + strings.Join(den, "/")
+	}
+	return s
+}
+
+// ratPow raises r to an integer power.
+func ratPow(r *big.Rat, n int) *big.Rat {
+	result := big.NewRat(1, 1)
+	base := new(big.Rat).Set(r)
+	if n < 0 {
+		base.Inv(base)
+		n = -n
+	}
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result.Mul(result, base)
+		}
+		base.Mul(base, base)
+	}
+	return result
+}
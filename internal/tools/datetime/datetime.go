@@ -2,16 +2,26 @@ package datetime
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/tool"
 )
 
+// readableLayout is used for human readable times in outputs.
+const readableLayout = "Mon 2 Jan 2006 15:04:05"
+
 // DateTime implements the domain.Tool interface for date and time operations.
 type DateTime struct {
-	config domain.ToolConfig
+	config     domain.ToolConfig
+	defaultLoc *time.Location
+	prefs      domain.PreferencesRepository
+	now        func() time.Time
 }
 
 // NewDateTime creates a new DateTime tool instance.
@@ -20,7 +30,25 @@ func NewDateTime() *DateTime {
 		config: domain.ToolConfig{
 			Enabled: true,
 		},
+		defaultLoc: time.Local,
+		now:        time.Now,
+	}
+}
+
+// SetDefaultTimezone sets the timezone used when neither the request nor
+// the user's preferences name one.
+func (d *DateTime) SetDefaultTimezone(name string) error {
+	loc, err := resolveZone(name)
+	if err != nil {
+		return fmt.Errorf("failed to set default timezone: %w", err)
 	}
+	d.defaultLoc = loc
+	return nil
+}
+
+// SetPreferencesRepository enables using each user's preferred timezone.
+func (d *DateTime) SetPreferencesRepository(repo domain.PreferencesRepository) {
+	d.prefs = repo
 }
 
 // Name returns the tool name.
@@ -30,7 +58,9 @@ func (d *DateTime) Name() string {
 
 // Description returns a description of the datetime tool.
 func (d *DateTime) Description() string {
-	return "Provides current date and time information with various formatting options"
+	return "Date and time operations: current time, timezone conversion, date arithmetic, " +
+		"differences including business days, ISO week info, and parsing of natural " +
+		"language times such as 'next tuesday 3pm' or '3pm PST'"
 }
 
 // InputSchema returns the JSON schema for the datetime's input parameters.
@@ -39,9 +69,48 @@ func (d *DateTime) InputSchema() map[string]any {
 		"type": "object",
 		"properties": map[string]any{
 			"operation": map[string]any{
+				"type": "string",
+				"description": "The datetime operation: now, format (with format), unix, " +
+					"convert (time to to_timezone), add (duration and/or business_days to time), " +
+					"diff (from start to end), info (ISO week, day of year, quarter), parse, " +
+					"or set_timezone (remember timezone as the user's default)",
+				"enum": []string{"now", "format", "unix", "convert", "add", "diff", "info", "parse", "set_timezone"},
+			},
+			"time": map[string]any{
+				"type": "string",
+				"description": "Optional: the time to work on, defaulting to now. Accepts ISO 8601, " +
+					"unix timestamps and phrases like 'tomorrow 9am', 'in 3 days', 'march 3' or '3pm PST'",
+			},
+			"timezone": map[string]any{
+				"type": "string",
+				"description": "Optional: timezone for results and for times without one " +
+					"(IANA name like Europe/Berlin, city, abbreviation like PST, or offset like UTC+5:30). " +
+					"Defaults to the user's timezone. For set_timezone, the timezone to remember",
+			},
+			"to_timezone": map[string]any{
+				"type":        "string",
+				"description": "Target timezone for 'convert'",
+			},
+			"duration": map[string]any{
 				"type":        "string",
-				"description": "The datetime operation to perform",
-				"enum":        []string{"now", "format", "unix"},
+				"description": "Duration for 'add', e.g. '2h30m', '3 days 4 hours', '-1 week' or 'P1DT2H'",
+			},
+			"business_days": map[string]any{
+				"type":        "integer",
+				"description": "Business days (Monday to Friday, excluding holidays) for 'add'; may be negative",
+			},
+			"start": map[string]any{
+				"type":        "string",
+				"description": "Start time for 'diff', defaulting to now",
+			},
+			"end": map[string]any{
+				"type":        "string",
+				"description": "End time for 'diff'",
+			},
+			"holidays": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Optional: dates (YYYY-MM-DD) that are not business days",
 			},
 			"format": map[string]any{
 				"type":        "string",
@@ -62,45 +131,296 @@ func (d *DateTime) Execute(ctx context.Context, params map[string]any) (*domain.
 		}, nil
 	}
 
-	now := time.Now()
+	if operation == "set_timezone" {
+		return d.setTimezone(ctx, params), nil
+	}
+
+	loc, err := d.location(ctx, params)
+	if err != nil {
+		return &domain.ExecutionResult{Error: err.Error()}, nil
+	}
+	now := d.now().In(loc)
 
+	var result *domain.ExecutionResult
 	switch operation {
-	case "now":
-		// Return current time in RFC3339 format
+	case "now", "format", "unix", "info", "parse":
+		t, err := d.timeParam(params, "time", now, loc)
+		if err != nil {
+			return &domain.ExecutionResult{Error: err.Error()}, nil
+		}
+		result, err = d.describe(operation, t, params)
+		if err != nil {
+			return &domain.ExecutionResult{Error: err.Error()}, nil
+		}
+	case "convert":
+		result, err = d.convert(params, now, loc)
+	case "add":
+		result, err = d.add(params, now, loc)
+	case "diff":
+		result, err = d.diff(params, now, loc)
+	default:
 		return &domain.ExecutionResult{
-			Output:   now.Format(time.RFC3339),
-			Metadata: map[string]any{"operation": "now", "format": "RFC3339"},
-			Error:    "",
+			Error: fmt.Sprintf("unsupported operation: %s", operation),
 		}, nil
+	}
+	if err != nil {
+		return &domain.ExecutionResult{Error: err.Error()}, nil
+	}
+	return result, nil
+}
+
+// location picks the timezone: the timezone param, then the user's
+// preference, then the default.
+func (d *DateTime) location(ctx context.Context, params map[string]any) (*time.Location, error) {
+	if name, _ := params["timezone"].(string); name != "" {
+		return resolveZone(name)
+	}
+	if d.prefs != nil {
+		if user := tool.UserFromContext(ctx); user != nil {
+			prefs, err := d.prefs.Get(ctx, user.ID)
+			if err == nil && prefs.Timezone != "" {
+				if loc, err := resolveZone(prefs.Timezone); err == nil {
+					return loc, nil
+				}
+			}
+		}
+	}
+	return d.defaultLoc, nil
+}
+
+// setTimezone saves the timezone param as the calling user's preference.
+func (d *DateTime) setTimezone(ctx context.Context, params map[string]any) *domain.ExecutionResult {
+	name, _ := params["timezone"].(string)
+	if name == "" {
+		return &domain.ExecutionResult{Error: "missing 'timezone' parameter for 'set_timezone' operation"}
+	}
+	loc, err := resolveZone(name)
+	if err != nil {
+		return &domain.ExecutionResult{Error: err.Error()}
+	}
+	user := tool.UserFromContext(ctx)
+	if d.prefs == nil || user == nil {
+		return &domain.ExecutionResult{Error: "timezone preferences are not available"}
+	}
+
+	prefs, err := d.prefs.Get(ctx, user.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		prefs, err = domain.DefaultUserPreferences(), nil
+	}
+	if err != nil {
+		return &domain.ExecutionResult{Error: fmt.Sprintf("failed to load preferences: %v", err)}
+	}
+	prefs.Timezone = loc.String()
+	if err := d.prefs.Save(ctx, user.ID, prefs); err != nil {
+		return &domain.ExecutionResult{Error: fmt.Sprintf("failed to save preferences: %v", err)}
+	}
+
+	now := d.now().In(loc)
+	return &domain.ExecutionResult{
+		Output:   fmt.Sprintf("Timezone set to %s; it is now %s", loc, readable(now)),
+		Metadata: map[string]any{"operation": "set_timezone", "timezone": loc.String()},
+	}
+}
+
+// timeParam parses a time param, defaulting to now when it is absent.
+func (d *DateTime) timeParam(params map[string]any, name string, now time.Time, loc *time.Location) (time.Time, error) {
+	s, _ := params[name].(string)
+	if strings.TrimSpace(s) == "" {
+		return now, nil
+	}
+	t, err := parseTime(s, now, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid '%s': %w", name, err)
+	}
+	return t, nil
+}
+
+// describe handles the operations that report on a single time.
+func (d *DateTime) describe(operation string, t time.Time, params map[string]any) (*domain.ExecutionResult, error) {
+	meta := map[string]any{"operation": operation, "time": t.Format(time.RFC3339), "timezone": zoneLabel(t)}
+
+	switch operation {
+	case "now":
+		meta["format"] = "RFC3339"
+		meta["weekday"] = t.Weekday().String()
+		return &domain.ExecutionResult{Output: t.Format(time.RFC3339), Metadata: meta}, nil
 
 	case "format":
 		// Return formatted time using provided format string
 		format, ok := params["format"].(string)
 		if !ok || format == "" {
-			return &domain.ExecutionResult{
-				Error: "missing or invalid 'format' parameter for 'format' operation",
-			}, nil
+			return nil, fmt.Errorf("missing or invalid 'format' parameter for 'format' operation")
 		}
-		return &domain.ExecutionResult{
-			Output:   now.Format(format),
-			Metadata: map[string]any{"operation": "format", "format": format},
-			Error:    "",
-		}, nil
+		meta["format"] = format
+		return &domain.ExecutionResult{Output: t.Format(format), Metadata: meta}, nil
 
 	case "unix":
-		// Return Unix timestamp
-		timestamp := now.Unix()
-		return &domain.ExecutionResult{
-			Output:   strconv.FormatInt(timestamp, 10),
-			Metadata: map[string]any{"operation": "unix", "timestamp": timestamp},
-			Error:    "",
-		}, nil
+		timestamp := t.Unix()
+		meta["timestamp"] = timestamp
+		return &domain.ExecutionResult{Output: strconv.FormatInt(timestamp, 10), Metadata: meta}, nil
 
-	default:
-		return &domain.ExecutionResult{
-			Error: fmt.Sprintf("unsupported operation: %s", operation),
-		}, nil
+	case "parse":
+		meta["weekday"] = t.Weekday().String()
+		return &domain.ExecutionResult{Output: readable(t), Metadata: meta}, nil
+	}
+
+	year, week := t.ISOWeek()
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	daysInYear := time.Date(t.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	quarter := (int(t.Month())-1)/3 + 1
+	meta["weekday"] = t.Weekday().String()
+	meta["iso_year"] = year
+	meta["iso_week"] = week
+	meta["day_of_year"] = t.YearDay()
+	meta["quarter"] = quarter
+	meta["days_in_month"] = daysInMonth
+	meta["leap_year"] = daysInYear == 366
+	meta["unix"] = t.Unix()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n", readable(t))
+	fmt.Fprintf(&sb, "Weekday: %s\n", t.Weekday())
+	fmt.Fprintf(&sb, "ISO week: %d-W%02d\n", year, week)
+	fmt.Fprintf(&sb, "Day of year: %d of %d\n", t.YearDay(), daysInYear)
+	fmt.Fprintf(&sb, "Quarter: Q%d\n", quarter)
+	fmt.Fprintf(&sb, "Days in month: %d\n", daysInMonth)
+	fmt.Fprintf(&sb, "Unix: %d", t.Unix())
+	return &domain.ExecutionResult{Output: sb.String(), Metadata: meta}, nil
+}
+
+// convert shows a time in another timezone.
+func (d *DateTime) convert(params map[string]any, now time.Time, loc *time.Location) (*domain.ExecutionResult, error) {
+	target, _ := params["to_timezone"].(string)
+	if target == "" {
+		return nil, fmt.Errorf("missing 'to_timezone' parameter for 'convert' operation")
+	}
+	to, err := resolveZone(target)
+	if err != nil {
+		return nil, err
+	}
+	t, err := d.timeParam(params, "time", now, loc)
+	if err != nil {
+		return nil, err
+	}
+	converted := t.In(to)
+
+	_, fromOffset := t.Zone()
+	_, toOffset := converted.Zone()
+	return &domain.ExecutionResult{
+		Output: fmt.Sprintf("%s = %s", readable(t), readable(converted)),
+		Metadata: map[string]any{
+			"operation":    "convert",
+			"from":         t.Format(time.RFC3339),
+			"to":           converted.Format(time.RFC3339),
+			"from_zone":    zoneLabel(t),
+			"to_zone":      zoneLabel(converted),
+			"offset_hours": float64(toOffset-fromOffset) / 3600,
+		},
+	}, nil
+}
+
+// add moves a time by a duration and/or a number of business days.
+func (d *DateTime) add(params map[string]any, now time.Time, loc *time.Location) (*domain.ExecutionResult, error) {
+	durationText, _ := params["duration"].(string)
+	days, hasDays := params["business_days"].(float64)
+	if durationText == "" && !hasDays {
+		return nil, fmt.Errorf("missing 'duration' or 'business_days' parameter for 'add' operation")
+	}
+	if hasDays && (days != math.Trunc(days) || math.Abs(days) > 10000) {
+		return nil, fmt.Errorf("'business_days' must be a whole number up to 10000")
 	}
+	t, err := d.timeParam(params, "time", now, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := t
+	if durationText != "" {
+		sp, err := parseSpan(durationText)
+		if err != nil {
+			return nil, err
+		}
+		result = sp.addTo(result)
+	}
+	if hasDays {
+		holidays, err := holidayParam(params)
+		if err != nil {
+			return nil, err
+		}
+		result = addBusinessDays(result, int(days), holidays)
+	}
+
+	return &domain.ExecutionResult{
+		Output: readable(result),
+		Metadata: map[string]any{
+			"operation": "add",
+			"from":      t.Format(time.RFC3339),
+			"time":      result.Format(time.RFC3339),
+			"weekday":   result.Weekday().String(),
+		},
+	}, nil
+}
+
+// diff reports the time between start and end, in total and in business days.
+func (d *DateTime) diff(params map[string]any, now time.Time, loc *time.Location) (*domain.ExecutionResult, error) {
+	if s, _ := params["end"].(string); s == "" {
+		return nil, fmt.Errorf("missing 'end' parameter for 'diff' operation")
+	}
+	start, err := d.timeParam(params, "start", now, loc)
+	if err != nil {
+		return nil, err
+	}
+	end, err := d.timeParam(params, "end", now, loc)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := holidayParam(params)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := end.Sub(start)
+	calendarDays := int(civilDate(end).Sub(civilDate(start)).Hours() / 24)
+	business := businessDays(start, end, holidays)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From %s to %s\n", readable(start), readable(end))
+	fmt.Fprintf(&sb, "Duration: %s (%.2f hours)\n", formatDuration(elapsed), elapsed.Hours())
+	fmt.Fprintf(&sb, "Calendar days: %d\n", calendarDays)
+	fmt.Fprintf(&sb, "Business days: %d", business)
+
+	return &domain.ExecutionResult{
+		Output: sb.String(),
+		Metadata: map[string]any{
+			"operation":     "diff",
+			"start":         start.Format(time.RFC3339),
+			"end":           end.Format(time.RFC3339),
+			"seconds":       int64(elapsed.Seconds()),
+			"hours":         elapsed.Hours(),
+			"calendar_days": calendarDays,
+			"business_days": business,
+		},
+	}, nil
+}
+
+// holidayParam reads the holidays param into a set of YYYY-MM-DD dates.
+func holidayParam(params map[string]any) (map[string]bool, error) {
+	list, _ := params["holidays"].([]any)
+	holidays := make(map[string]bool, len(list))
+	for _, item := range list {
+		s, _ := item.(string)
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q; use YYYY-MM-DD", s)
+		}
+		holidays[date.Format(time.DateOnly)] = true
+	}
+	return holidays, nil
+}
+
+// readable formats t for people, e.g. "Tue 3 Mar 2026 15:00:00 CET (Europe/Berlin)".
+func readable(t time.Time) string {
+	return t.Format(readableLayout) + " " + zoneLabel(t)
 }
 
 // RequiredPermissions returns the permissions required to execute this tool.
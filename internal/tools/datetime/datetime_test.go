@@ -2,10 +2,14 @@ package datetime_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
+	"nuimanbot/internal/adapter/repository/memory"
+	"nuimanbot/internal/domain"
 	"nuimanbot/internal/tools/datetime"
+	"nuimanbot/internal/usecase/tool"
 )
 
 func TestDateTime_Name(t *testing.T) {
@@ -151,3 +155,190 @@ func TestDateTime_Config(t *testing.T) {
 	// Check that config is returned (basic smoke test)
 	_ = config
 }
+
+func TestDateTime_Execute_Convert(t *testing.T) {
+	dt := datetime.NewDateTime()
+
+	result, err := dt.Execute(context.Background(), map[string]any{
+		"operation":   "convert",
+		"time":        "2026-01-15 15:00 PST",
+		"to_timezone": "Berlin",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Expected no error, got: %s", result.Error)
+	}
+	if got := result.Metadata["to"]; got != "2026-01-16T00:00:00+01:00" {
+		t.Errorf("Expected 2026-01-16T00:00:00+01:00, got %v", got)
+	}
+	if !strings.Contains(result.Output, "Europe/Berlin") {
+		t.Errorf("Expected output to name the target zone, got: %s", result.Output)
+	}
+}
+
+func TestDateTime_Execute_Add(t *testing.T) {
+	dt := datetime.NewDateTime()
+
+	result, err := dt.Execute(context.Background(), map[string]any{
+		"operation":     "add",
+		"time":          "2026-10-16T09:00:00Z",
+		"duration":      "2h",
+		"business_days": float64(3),
+		"timezone":      "UTC",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Expected no error, got: %s", result.Error)
+	}
+	if got := result.Metadata["time"]; got != "2026-10-21T11:00:00Z" {
+		t.Errorf("Expected 2026-10-21T11:00:00Z, got %v", got)
+	}
+}
+
+func TestDateTime_Execute_Diff(t *testing.T) {
+	dt := datetime.NewDateTime()
+
+	result, err := dt.Execute(context.Background(), map[string]any{
+		"operation": "diff",
+		"start":     "2026-12-21",
+		"end":       "2027-01-04",
+		"holidays":  []any{"2026-12-25", "2027-01-01"},
+		"timezone":  "UTC",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Expected no error, got: %s", result.Error)
+	}
+	if got := result.Metadata["calendar_days"]; got != 14 {
+		t.Errorf("Expected 14 calendar days, got %v", got)
+	}
+	if got := result.Metadata["business_days"]; got != 8 {
+		t.Errorf("Expected 8 business days, got %v", got)
+	}
+	if !strings.Contains(result.Output, "14 days") {
+		t.Errorf("Expected duration in output, got: %s", result.Output)
+	}
+}
+
+func TestDateTime_Execute_Info(t *testing.T) {
+	dt := datetime.NewDateTime()
+
+	result, err := dt.Execute(context.Background(), map[string]any{
+		"operation": "info",
+		"time":      "2027-01-01",
+		"timezone":  "UTC",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Expected no error, got: %s", result.Error)
+	}
+	// 1 January 2027 is a Friday in ISO week 53 of 2026
+	if result.Metadata["iso_year"] != 2026 || result.Metadata["iso_week"] != 53 {
+		t.Errorf("Expected ISO week 2026-W53, got %v-W%v", result.Metadata["iso_year"], result.Metadata["iso_week"])
+	}
+	if !strings.Contains(result.Output, "2026-W53") {
+		t.Errorf("Expected ISO week in output, got: %s", result.Output)
+	}
+}
+
+func TestDateTime_Execute_UserTimezone(t *testing.T) {
+	dt := datetime.NewDateTime()
+	if err := dt.SetDefaultTimezone("UTC"); err != nil {
+		t.Fatalf("SetDefaultTimezone failed: %v", err)
+	}
+	prefs := memory.NewPreferencesRepository()
+	if err := prefs.Save(context.Background(), "user-1", domain.UserPreferences{Timezone: "Asia/Tokyo"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	dt.SetPreferencesRepository(prefs)
+
+	params := map[string]any{"operation": "parse", "time": "2026-05-01 09:00"}
+
+	ctx := tool.ContextWithUser(context.Background(), &domain.User{ID: "user-1"})
+	result, err := dt.Execute(ctx, params)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := result.Metadata["time"]; got != "2026-05-01T09:00:00+09:00" {
+		t.Errorf("Expected the user's timezone, got %v", got)
+	}
+
+	result, err = dt.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := result.Metadata["time"]; got != "2026-05-01T09:00:00Z" {
+		t.Errorf("Expected the default timezone without a user, got %v", got)
+	}
+}
+
+func TestDateTime_Execute_SetTimezone(t *testing.T) {
+	dt := datetime.NewDateTime()
+	if err := dt.SetDefaultTimezone("UTC"); err != nil {
+		t.Fatalf("SetDefaultTimezone failed: %v", err)
+	}
+	prefs := memory.NewPreferencesRepository()
+	dt.SetPreferencesRepository(prefs)
+	ctx := tool.ContextWithUser(context.Background(), &domain.User{ID: "user-1"})
+
+	result, err := dt.Execute(ctx, map[string]any{"operation": "set_timezone", "timezone": "tokyo"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Unexpected error: %s", result.Error)
+	}
+	saved, err := prefs.Get(context.Background(), "user-1")
+	if err != nil || saved.Timezone != "Asia/Tokyo" {
+		t.Fatalf("Expected Asia/Tokyo to be saved, got %+v, %v", saved, err)
+	}
+
+	result, _ = dt.Execute(ctx, map[string]any{"operation": "parse", "time": "2026-05-01 09:00"})
+	if got := result.Metadata["time"]; got != "2026-05-01T09:00:00+09:00" {
+		t.Errorf("Expected the saved timezone to be used, got %v", got)
+	}
+
+	for _, params := range []map[string]any{
+		{"operation": "set_timezone"},
+		{"operation": "set_timezone", "timezone": "Mars/Olympus"},
+	} {
+		result, _ := dt.Execute(ctx, params)
+		if result.Error == "" {
+			t.Errorf("Expected an error for %v", params)
+		}
+	}
+	result, _ = dt.Execute(context.Background(), map[string]any{"operation": "set_timezone", "timezone": "UTC"})
+	if result.Error == "" {
+		t.Error("Expected an error without a user")
+	}
+}
+
+func TestDateTime_Execute_InvalidInput(t *testing.T) {
+	dt := datetime.NewDateTime()
+	tests := []map[string]any{
+		{"operation": "now", "timezone": "Mars/Olympus"},
+		{"operation": "parse", "time": "whenever"},
+		{"operation": "convert", "time": "3pm"},
+		{"operation": "add", "time": "3pm"},
+		{"operation": "add", "duration": "soon"},
+		{"operation": "diff", "start": "today"},
+		{"operation": "diff", "end": "today", "holidays": []any{"christmas"}},
+	}
+	for _, params := range tests {
+		result, err := dt.Execute(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if result.Error == "" {
+			t.Errorf("Expected error for %v", params)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package datetime
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// span is a duration with calendar parts, which vary in length (a month
+// is 28 to 31 days, a day is 23 to 25 hours across DST changes).
+type span struct {
+	years, months, days int
+	clock               time.Duration
+}
+
+// addTo adds the span to t: calendar parts first, then clock time.
+func (s span) addTo(t time.Time) time.Time {
+	return t.AddDate(s.years, s.months, s.days).Add(s.clock)
+}
+
+func (s span) negate() span {
+	return span{-s.years, -s.months, -s.days, -s.clock}
+}
+
+// addUnit adds n of a unit (singular, e.g. "day") to the span.
+func (s *span) addUnit(n int, unit string) {
+	switch unit {
+	case "year":
+		s.years += n
+	case "month":
+		s.months += n
+	case "week":
+		s.days += 7 * n
+	case "day":
+		s.days += n
+	case "hour":
+		s.clock += time.Duration(n) * time.Hour
+	case "minute":
+		s.clock += time.Duration(n) * time.Minute
+	case "second":
+		s.clock += time.Duration(n) * time.Second
+	}
+}
+
+// unitNames maps unit spellings to their singular name.
+var unitNames = map[string]string{
+	"y": "year", "yr": "year", "yrs": "year", "year": "year", "years": "year",
+	"mo": "month", "mon": "month", "month": "month", "months": "month",
+	"w": "week", "wk": "week", "wks": "week", "week": "week", "weeks": "week",
+	"d": "day", "day": "day", "days": "day",
+	"h": "hour", "hr": "hour", "hrs": "hour", "hour": "hour", "hours": "hour",
+	"m": "minute", "min": "minute", "mins": "minute", "minute": "minute", "minutes": "minute",
+	"s": "second", "sec": "second", "secs": "second", "second": "second", "seconds": "second",
+}
+
+var (
+	isoDurationPattern  = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+	durationPartPattern = regexp.MustCompile(`(\d+)\s*([a-z]+)`)
+	articlePattern      = regexp.MustCompile(`\ban?\s+`)
+)
+
+// parseSpan parses "2h30m", "3 days 4 hours", "1 week, 2 days", ISO 8601
+// "P1DT2H", or any of these with a leading "-".
+func parseSpan(input string) (span, error) {
+	s := strings.TrimSpace(input)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+"))
+	if s == "" {
+		return span{}, fmt.Errorf("duration is empty")
+	}
+
+	var result span
+	if m := isoDurationPattern.FindStringSubmatch(strings.ToUpper(s)); m != nil && s != "P" {
+		for i, unit := range []string{"year", "month", "week", "day", "hour", "minute", "second"} {
+			if m[i+1] != "" {
+				n, _ := strconv.Atoi(m[i+1])
+				result.addUnit(n, unit)
+			}
+		}
+	} else {
+		lower := strings.ToLower(strings.NewReplacer(",", " ", " and ", " ").Replace(s))
+		lower = articlePattern.ReplaceAllString(lower, "1 ")
+		matches := durationPartPattern.FindAllStringSubmatchIndex(lower, -1)
+		covered := 0
+		for _, m := range matches {
+			if strings.TrimSpace(lower[covered:m[0]]) != "" {
+				return span{}, fmt.Errorf("invalid duration %q", input)
+			}
+			covered = m[1]
+			n, err := strconv.Atoi(lower[m[2]:m[3]])
+			if err != nil {
+				return span{}, fmt.Errorf("invalid duration %q", input)
+			}
+			unit, ok := unitNames[lower[m[4]:m[5]]]
+			if !ok {
+				return span{}, fmt.Errorf("unknown duration unit %q", lower[m[4]:m[5]])
+			}
+			result.addUnit(n, unit)
+		}
+		if len(matches) == 0 || strings.TrimSpace(lower[covered:]) != "" {
+			return span{}, fmt.Errorf("invalid duration %q; use e.g. 2h30m, 3 days or P1DT2H", input)
+		}
+	}
+
+	if negative {
+		result = result.negate()
+	}
+	return result, nil
+}
+
+// formatDuration renders an exact duration as days, hours, minutes and
+// seconds, e.g. "2 days, 3 hours, 5 minutes".
+func formatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	d = d.Round(time.Second)
+	parts := []struct {
+		n    int64
+		unit string
+	}{
+		{int64(d / (24 * time.Hour)), "day"},
+		{int64(d % (24 * time.Hour) / time.Hour), "hour"},
+		{int64(d % time.Hour / time.Minute), "minute"},
+		{int64(d % time.Minute / time.Second), "second"},
+	}
+	var out []string
+	for _, p := range parts {
+		if p.n == 0 {
+			continue
+		}
+		unit := p.unit
+		if p.n != 1 {
+			unit += "s"
+		}
+		out = append(out, fmt.Sprintf("%d %s", p.n, unit))
+	}
+	if len(out) == 0 {
+		return "0 seconds"
+	}
+	return sign + strings.Join(out, ", ")
+}
+
+// businessDays counts Monday-Friday dates after start's date up to and
+// including end's date, skipping holidays (keyed by "2006-01-02"). The
+// count is negative when end is before start.
+func businessDays(start, end time.Time, holidays map[string]bool) int {
+	sign := 1
+	if end.Before(start) {
+		start, end = end, start
+		sign = -1
+	}
+	from := civilDate(start)
+	to := civilDate(end)
+	count := 0
+	for d := from.AddDate(0, 0, 1); !d.After(to); d = d.AddDate(0, 0, 1) {
+		if isBusinessDay(d, holidays) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+// addBusinessDays moves t by n business days, keeping its time of day.
+func addBusinessDays(t time.Time, n int, holidays map[string]bool) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if isBusinessDay(t, holidays) {
+			n--
+		}
+	}
+	return t
+}
+
+func isBusinessDay(t time.Time, holidays map[string]bool) bool {
+	wd := t.Weekday()
+	return wd != time.Saturday && wd != time.Sunday && !holidays[t.Format(time.DateOnly)]
+}
+
+// civilDate returns midnight UTC of t's calendar date, so date loops are
+// not affected by DST.
+func civilDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,485 @@
+package datetime
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// absoluteLayouts are tried before natural language parsing.
+var absoluteLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+}
+
+var (
+	clockPattern   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2}))?(am|pm|a\.m\.|p\.m\.)?$`)
+	dayPattern     = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	isoDatePattern = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	usDatePattern  = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})/(\d{4})$`)
+	unixPattern    = regexp.MustCompile(`^@?(\d{9,11})$`)
+	compactSpan    = regexp.MustCompile(`^\d+[a-z]+$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// dayParts are vague times of day and the clock time they stand for.
+var dayParts = map[string]int{
+	"midnight": 0, "morning": 9, "noon": 12, "midday": 12,
+	"afternoon": 15, "evening": 18, "tonight": 20,
+}
+
+// keywords are the words parseTime understands; any other trailing word is
+// tried as a timezone.
+var keywords = map[string]bool{
+	"now": true, "today": true, "tomorrow": true, "yesterday": true,
+	"next": true, "last": true, "this": true, "in": true, "ago": true,
+	"from": true, "later": true, "at": true, "on": true, "the": true, "of": true,
+	"start": true, "beginning": true, "end": true, "week": true, "month": true, "year": true,
+	"day": true, "am": true, "pm": true, "a": true, "an": true, "and": true,
+}
+
+// parseTime parses an absolute or natural language time such as
+// "2026-03-03", "next tuesday 3pm", "in 2 hours", "march 3" or
+// "3pm PST", relative to now. Times without a zone are in loc.
+func parseTime(input string, now time.Time, loc *time.Location) (time.Time, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("time is empty")
+	}
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	if m := unixPattern.FindStringSubmatch(s); m != nil {
+		sec, _ := strconv.ParseInt(m[1], 10, 64)
+		return time.Unix(sec, 0).In(loc), nil
+	}
+
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(s, ",", " ")))
+
+	// A trailing timezone of one or two words: "3pm PST", "9:00 new york"
+	for n := 2; n >= 1; n-- {
+		if len(words) <= n {
+			continue
+		}
+		tail := words[len(words)-n:]
+		if keywords[tail[0]] || isDateOrTimeWord(tail[0]) {
+			continue
+		}
+		if zone, err := resolveZone(strings.Join(tail, " ")); err == nil {
+			loc = zone
+			words = words[:len(words)-n]
+			break
+		}
+	}
+
+	p := &timeParser{words: words, now: now.In(loc), t: now.In(loc), loc: loc}
+	if err := p.run(); err != nil {
+		return time.Time{}, err
+	}
+	return p.result(), nil
+}
+
+// isDateOrTimeWord reports whether w is understood by the natural language
+// parser and so must not be read as a timezone.
+func isDateOrTimeWord(w string) bool {
+	_, weekday := weekdays[w]
+	_, month := months[w]
+	_, part := dayParts[w]
+	_, unit := unitNames[w]
+	return weekday || month || part || unit || clockPattern.MatchString(w) ||
+		isoDatePattern.MatchString(w) || usDatePattern.MatchString(w) || compactSpan.MatchString(w)
+}
+
+// timeParser walks the words of a natural language time. t holds the
+// date so far; the clock is applied at the end.
+type timeParser struct {
+	words []string
+	pos   int
+	now   time.Time
+	t     time.Time
+	loc   *time.Location
+
+	// dateOnly is set by words naming a day, which means midnight unless
+	// a clock time is given; exact is set when t already has the time.
+	dateOnly bool
+	exact    bool
+	clockSet bool
+	hour     int
+	minute   int
+	second   int
+}
+
+func (p *timeParser) peek(offset int) string {
+	if p.pos+offset < len(p.words) {
+		return p.words[p.pos+offset]
+	}
+	return ""
+}
+
+func (p *timeParser) run() error {
+	for p.pos < len(p.words) {
+		w := p.words[p.pos]
+		p.pos++
+
+		switch w {
+		case "at", "on", "the", "of":
+		case "now":
+			p.t = p.now
+			p.exact = true
+		case "today":
+			p.dateOnly = true
+		case "tomorrow":
+			p.t = p.t.AddDate(0, 0, 1)
+			p.dateOnly = true
+		case "yesterday":
+			p.t = p.t.AddDate(0, 0, -1)
+			p.dateOnly = true
+		case "next", "last", "this":
+			if err := p.relative(w); err != nil {
+				return err
+			}
+		case "in":
+			sp, ok := p.span()
+			if !ok {
+				return fmt.Errorf("expected a duration after \"in\"")
+			}
+			p.t = sp.addTo(p.t)
+		case "start", "beginning", "end":
+			if err := p.boundary(w == "end"); err != nil {
+				return err
+			}
+		default:
+			if err := p.word(w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// word handles weekdays, months, dates, clock times and "N units ago".
+func (p *timeParser) word(w string) error {
+	if wd, ok := weekdays[w]; ok {
+		p.t = p.t.AddDate(0, 0, (int(wd)-int(p.t.Weekday())+7)%7)
+		p.dateOnly = true
+		return nil
+	}
+	if m, ok := months[w]; ok {
+		return p.monthDay(m, 0)
+	}
+	if h, ok := dayParts[w]; ok {
+		if w == "tonight" {
+			p.t = p.now
+		}
+		return p.setClock(h, 0, 0)
+	}
+	if m := isoDatePattern.FindStringSubmatch(w); m != nil {
+		return p.setDate(atoi(m[1]), atoi(m[2]), atoi(m[3]))
+	}
+	if m := usDatePattern.FindStringSubmatch(w); m != nil {
+		return p.setDate(atoi(m[3]), atoi(m[1]), atoi(m[2]))
+	}
+
+	// "3 march", before trying "3" as a time or a duration
+	if m := dayPattern.FindStringSubmatch(w); m != nil {
+		if month, ok := months[p.peek(0)]; ok {
+			p.pos++
+			return p.monthDay(month, atoi(m[1]))
+		}
+	}
+
+	// "2 days ago", "a week from now", "3 hours later"
+	p.pos--
+	start := p.pos
+	if sp, ok := p.span(); ok {
+		switch {
+		case p.peek(0) == "ago":
+			p.pos++
+			p.t = sp.negate().addTo(p.t)
+			return nil
+		case p.peek(0) == "from" && p.peek(1) == "now":
+			p.pos += 2
+			p.t = sp.addTo(p.t)
+			return nil
+		case p.peek(0) == "later":
+			p.pos++
+			p.t = sp.addTo(p.t)
+			return nil
+		}
+	}
+	p.pos = start + 1
+
+	if m := clockPattern.FindStringSubmatch(w); m != nil {
+		suffix := m[4]
+		if suffix == "" && (p.peek(0) == "am" || p.peek(0) == "pm") {
+			suffix = p.peek(0)
+			p.pos++
+		}
+		if suffix == "" && m[2] == "" {
+			return fmt.Errorf("ambiguous number %q; write a time as 3pm or 15:00", w)
+		}
+		return p.clock(atoi(m[1]), atoi(m[2]), atoi(m[3]), suffix)
+	}
+	return fmt.Errorf("unrecognized word %q in time", w)
+}
+
+// span reads a duration such as "2 hours 30 minutes", "a week" or "90m".
+func (p *timeParser) span() (span, bool) {
+	end := p.pos
+	units := 0
+	for end < len(p.words) {
+		w := p.words[end]
+		switch {
+		case w == "and" && units > 0:
+		case w == "a" || w == "an" || isNumber(w):
+			if _, ok := unitNames[p.wordAt(end+1)]; !ok {
+				return p.finishSpan(end, units)
+			}
+		case compactSpan.MatchString(w):
+			if _, err := parseSpan(w); err != nil {
+				return p.finishSpan(end, units)
+			}
+			units++
+		default:
+			if _, ok := unitNames[w]; !ok || end == p.pos {
+				return p.finishSpan(end, units)
+			}
+			units++
+		}
+		end++
+	}
+	return p.finishSpan(end, units)
+}
+
+func (p *timeParser) finishSpan(end, units int) (span, bool) {
+	if units == 0 {
+		return span{}, false
+	}
+	sp, err := parseSpan(strings.Join(p.words[p.pos:end], " "))
+	if err != nil {
+		return span{}, false
+	}
+	p.pos = end
+	return sp, true
+}
+
+func (p *timeParser) wordAt(i int) string {
+	if i < len(p.words) {
+		return p.words[i]
+	}
+	return ""
+}
+
+// relative handles next/last/this followed by a weekday, week, month or year.
+func (p *timeParser) relative(which string) error {
+	w := p.peek(0)
+	p.pos++
+	step := map[string]int{"next": 1, "last": -1, "this": 0}[which]
+
+	if wd, ok := weekdays[w]; ok {
+		diff := (int(wd) - int(p.t.Weekday()) + 7) % 7
+		switch which {
+		case "next":
+			if diff == 0 {
+				diff = 7
+			}
+		case "last":
+			diff -= 7
+		}
+		p.t = p.t.AddDate(0, 0, diff)
+		p.dateOnly = true
+		return nil
+	}
+	switch w {
+	case "week":
+		p.t = p.t.AddDate(0, 0, 7*step)
+	case "month":
+		p.t = p.t.AddDate(0, step, 0)
+	case "year":
+		p.t = p.t.AddDate(step, 0, 0)
+	default:
+		return fmt.Errorf("expected a weekday, week, month or year after %q", which)
+	}
+	p.dateOnly = true
+	return nil
+}
+
+// boundary handles "start of week", "end of the month" and similar.
+func (p *timeParser) boundary(end bool) error {
+	for p.peek(0) == "of" || p.peek(0) == "the" || p.peek(0) == "this" {
+		p.pos++
+	}
+	period := p.peek(0)
+	p.pos++
+	if period == "next" || period == "last" {
+		if err := p.relative(period); err != nil {
+			return err
+		}
+		period = p.words[p.pos-1]
+	}
+
+	y, m, d := p.t.Date()
+	var start, next time.Time
+	switch period {
+	case "day":
+		start = time.Date(y, m, d, 0, 0, 0, 0, p.loc)
+		next = start.AddDate(0, 0, 1)
+	case "week":
+		offset := (int(p.t.Weekday()) + 6) % 7 // ISO weeks start on Monday
+		start = time.Date(y, m, d-offset, 0, 0, 0, 0, p.loc)
+		next = start.AddDate(0, 0, 7)
+	case "month":
+		start = time.Date(y, m, 1, 0, 0, 0, 0, p.loc)
+		next = start.AddDate(0, 1, 0)
+	case "year":
+		start = time.Date(y, time.January, 1, 0, 0, 0, 0, p.loc)
+		next = start.AddDate(1, 0, 0)
+	default:
+		return fmt.Errorf("expected day, week, month or year after start/end")
+	}
+	p.t = start
+	if end {
+		p.t = next.Add(-time.Second)
+	}
+	p.exact = true
+	p.dateOnly = false
+	return nil
+}
+
+// monthDay handles "march 3", "march 3rd 2027", "3 march" and "march".
+// Without a year, a date already past this year means next year.
+func (p *timeParser) monthDay(month time.Month, day int) error {
+	if day == 0 {
+		if m := dayPattern.FindStringSubmatch(p.peek(0)); m != nil && !p.isClock(1) {
+			day = atoi(m[1])
+			p.pos++
+		}
+	}
+	year := 0
+	if w := p.peek(0); len(w) == 4 && isNumber(w) {
+		year = atoi(w)
+		p.pos++
+	}
+	if day == 0 {
+		day = 1
+	}
+	if year == 0 {
+		year = p.now.Year()
+		if time.Date(year, month, day, 0, 0, 0, 0, p.loc).Before(civilStart(p.now)) {
+			year++
+		}
+	}
+	return p.setDate(year, int(month), day)
+}
+
+// isClock reports whether the word at offset is "am" or "pm", as in
+// "march 3 pm" where 3 is the hour.
+func (p *timeParser) isClock(offset int) bool {
+	w := p.peek(offset)
+	return w == "am" || w == "pm"
+}
+
+func (p *timeParser) setDate(year, month, day int) error {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, p.loc)
+	if t.Day() != day || int(t.Month()) != month {
+		return fmt.Errorf("invalid date %04d-%02d-%02d", year, month, day)
+	}
+	p.t = t
+	p.dateOnly = true
+	return nil
+}
+
+func (p *timeParser) clock(hour, minute, second int, suffix string) error {
+	suffix = strings.ReplaceAll(suffix, ".", "")
+	if suffix != "" {
+		if hour < 1 || hour > 12 {
+			return fmt.Errorf("invalid hour %d for %s", hour, suffix)
+		}
+		hour %= 12
+		if suffix == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return fmt.Errorf("invalid time %02d:%02d:%02d", hour, minute, second)
+	}
+	return p.setClock(hour, minute, second)
+}
+
+func (p *timeParser) setClock(hour, minute, second int) error {
+	if p.clockSet {
+		return fmt.Errorf("time of day given twice")
+	}
+	p.clockSet = true
+	p.hour, p.minute, p.second = hour, minute, second
+	return nil
+}
+
+func (p *timeParser) result() time.Time {
+	y, m, d := p.t.Date()
+	switch {
+	case p.clockSet:
+		return time.Date(y, m, d, p.hour, p.minute, p.second, 0, p.loc)
+	case p.dateOnly && !p.exact:
+		return time.Date(y, m, d, 0, 0, 0, 0, p.loc)
+	}
+	return p.t
+}
+
+// civilStart returns midnight at the start of t's day in t's location.
+func civilStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
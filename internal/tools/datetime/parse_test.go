@@ -0,0 +1,152 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+	// Sunday 18 October 2026, 14:30 in Berlin
+	now := time.Date(2026, time.October, 18, 14, 30, 0, 0, berlin)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"2026-03-03T09:00:00Z", "2026-03-03T09:00:00Z"},
+		{"2026-12-24", "2026-12-24T00:00:00+01:00"},
+		{"2026-12-24 18:00", "2026-12-24T18:00:00+01:00"},
+		{"12/24/2026", "2026-12-24T00:00:00+01:00"},
+		{"@1700000000", "2023-11-14T23:13:20+01:00"},
+		{"now", "2026-10-18T14:30:00+02:00"},
+		{"today", "2026-10-18T00:00:00+02:00"},
+		{"tomorrow 9am", "2026-10-19T09:00:00+02:00"},
+		{"yesterday at noon", "2026-10-17T12:00:00+02:00"},
+		{"3pm", "2026-10-18T15:00:00+02:00"},
+		{"3:45 pm", "2026-10-18T15:45:00+02:00"},
+		{"15:00", "2026-10-18T15:00:00+02:00"},
+		{"in 2 hours", "2026-10-18T16:30:00+02:00"},
+		{"in 1 week and 2 days", "2026-10-27T14:30:00+01:00"},
+		{"3 days ago", "2026-10-15T14:30:00+02:00"},
+		{"a week from now", "2026-10-25T14:30:00+01:00"},
+		{"next tuesday", "2026-10-20T00:00:00+02:00"},
+		{"next sunday", "2026-10-25T00:00:00+02:00"},
+		{"last friday 5pm", "2026-10-16T17:00:00+02:00"},
+		{"sunday", "2026-10-18T00:00:00+02:00"},
+		{"march 3", "2027-03-03T00:00:00+01:00"},
+		{"3rd march 2026", "2026-03-03T00:00:00+01:00"},
+		{"december 1st at 8:15", "2026-12-01T08:15:00+01:00"},
+		{"next month", "2026-11-18T00:00:00+01:00"},
+		{"start of week", "2026-10-12T00:00:00+02:00"},
+		{"end of the month", "2026-10-31T23:59:59+01:00"},
+		{"start of next year", "2027-01-01T00:00:00+01:00"},
+		{"3pm PST", "2026-10-18T15:00:00-08:00"},
+		{"tomorrow 9am new york", "2026-10-19T09:00:00-04:00"},
+		{"9:00 UTC+5:30", "2026-10-18T09:00:00+05:30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseTime(tt.input, now, berlin)
+			if err != nil {
+				t.Fatalf("parseTime(%q) failed: %v", tt.input, err)
+			}
+			if got.Format(time.RFC3339) != tt.want {
+				t.Errorf("parseTime(%q) = %s, want %s", tt.input, got.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTime_Errors(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 14, 30, 0, 0, time.UTC)
+	for _, input := range []string{"", "soonish", "3", "next fortnight", "february 30", "25pm", "3pm 4pm", "in"} {
+		if got, err := parseTime(input, now, time.UTC); err == nil {
+			t.Errorf("parseTime(%q) = %s, want error", input, got)
+		}
+	}
+}
+
+func TestParseSpan(t *testing.T) {
+	start := time.Date(2026, time.January, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"2h30m", "2026-01-31T14:30:00Z"},
+		{"3 days 4 hours", "2026-02-03T16:00:00Z"},
+		{"1 week, 2 days", "2026-02-09T12:00:00Z"},
+		{"-90 minutes", "2026-01-31T10:30:00Z"},
+		{"P1DT2H", "2026-02-01T14:00:00Z"},
+		{"1 month", "2026-03-03T12:00:00Z"}, // Go normalizes 31 February
+		{"a year", "2027-01-31T12:00:00Z"},
+	}
+	for _, tt := range tests {
+		sp, err := parseSpan(tt.input)
+		if err != nil {
+			t.Errorf("parseSpan(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got := sp.addTo(start).Format(time.RFC3339); got != tt.want {
+			t.Errorf("parseSpan(%q) added = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "P", "soon", "3 fortnights", "2 hours lots"} {
+		if _, err := parseSpan(input); err == nil {
+			t.Errorf("parseSpan(%q) should fail", input)
+		}
+	}
+}
+
+func TestResolveZone(t *testing.T) {
+	tests := map[string]string{
+		"Europe/Berlin":    "Europe/Berlin",
+		"america/new york": "America/New_York",
+		"Tokyo":            "Asia/Tokyo",
+		"PT":               "America/Los_Angeles",
+		"pst":              "PST",
+		"UTC+5:30":         "UTC+05:30",
+		"-08:00":           "UTC-08:00",
+	}
+	for input, want := range tests {
+		loc, err := resolveZone(input)
+		if err != nil {
+			t.Errorf("resolveZone(%q) failed: %v", input, err)
+			continue
+		}
+		if loc.String() != want {
+			t.Errorf("resolveZone(%q) = %s, want %s", input, loc, want)
+		}
+	}
+	for _, input := range []string{"", "Mars/Olympus", "UTC+15", "../etc/passwd"} {
+		if _, err := resolveZone(input); err == nil {
+			t.Errorf("resolveZone(%q) should fail", input)
+		}
+	}
+}
+
+func TestBusinessDays(t *testing.T) {
+	friday := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	nextFriday := friday.AddDate(0, 0, 7)
+	holidays := map[string]bool{"2026-10-21": true}
+
+	if got := businessDays(friday, nextFriday, nil); got != 5 {
+		t.Errorf("businessDays = %d, want 5", got)
+	}
+	if got := businessDays(friday, nextFriday, holidays); got != 4 {
+		t.Errorf("businessDays with holiday = %d, want 4", got)
+	}
+	if got := businessDays(nextFriday, friday, nil); got != -5 {
+		t.Errorf("businessDays backwards = %d, want -5", got)
+	}
+	if got := addBusinessDays(friday, 1, nil); got.Weekday() != time.Monday {
+		t.Errorf("addBusinessDays(friday, 1) = %s, want Monday", got.Weekday())
+	}
+	if got := addBusinessDays(friday, 5, holidays).Format(time.DateOnly); got != "2026-10-26" {
+		t.Errorf("addBusinessDays with holiday = %s, want 2026-10-26", got)
+	}
+}
@@ -0,0 +1,108 @@
+package datetime
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // IANA zones even on hosts without zoneinfo
+)
+
+// zoneAbbreviations are common abbreviations with fixed offsets in hours.
+// Ambiguous ones (CST, IST) follow the most common English usage.
+var zoneAbbreviations = map[string]float64{
+	"UTC": 0, "GMT": 0, "Z": 0,
+	"EST": -5, "EDT": -4, "CST": -6, "CDT": -5, "MST": -7, "MDT": -6,
+	"PST": -8, "PDT": -7, "AKST": -9, "AKDT": -8, "HST": -10,
+	"WET": 0, "WEST": 1, "BST": 1, "CET": 1, "CEST": 2, "EET": 2, "EEST": 3, "MSK": 3,
+	"IST": 5.5, "SGT": 8, "HKT": 8, "JST": 9, "KST": 9,
+	"AWST": 8, "ACST": 9.5, "AEST": 10, "AEDT": 11, "NZST": 12, "NZDT": 13,
+}
+
+// zoneAliases name zones that follow daylight saving time.
+var zoneAliases = map[string]string{
+	"ET": "America/New_York", "EASTERN": "America/New_York",
+	"CT": "America/Chicago", "CENTRAL": "America/Chicago",
+	"MT": "America/Denver", "MOUNTAIN": "America/Denver",
+	"PT": "America/Los_Angeles", "PACIFIC": "America/Los_Angeles",
+}
+
+// zoneRegions are tried in turn to resolve bare city names like Berlin.
+var zoneRegions = []string{"Europe", "America", "Asia", "Africa", "Australia", "Pacific", "Atlantic", "Indian"}
+
+var offsetPattern = regexp.MustCompile(`(?i)^(?:UTC|GMT)?\s*([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+// resolveZone finds a timezone by IANA name (Europe/Berlin), city (Berlin,
+// new york), abbreviation (PST), or offset (UTC+5:30, -08:00).
+func resolveZone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("timezone is empty")
+	}
+	upper := strings.ToUpper(name)
+	if upper == "LOCAL" {
+		return time.Local, nil
+	}
+	if hours, ok := zoneAbbreviations[upper]; ok {
+		return time.FixedZone(upper, int(hours*3600)), nil
+	}
+	if alias, ok := zoneAliases[upper]; ok {
+		return time.LoadLocation(alias)
+	}
+	if m := offsetPattern.FindStringSubmatch(name); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+		if hours > 14 || minutes > 59 {
+			return nil, fmt.Errorf("invalid UTC offset: %s", name)
+		}
+		offset := hours*3600 + minutes*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone(fmt.Sprintf("UTC%s%02d:%02d", m[1], hours, minutes), offset), nil
+	}
+
+	if strings.ContainsAny(name, ".\\") {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc, nil
+	}
+	canonical := canonicalZoneName(name)
+	if loc, err := time.LoadLocation(canonical); err == nil {
+		return loc, nil
+	}
+	if !strings.Contains(canonical, "/") {
+		for _, region := range zoneRegions {
+			if loc, err := time.LoadLocation(region + "/" + canonical); err == nil {
+				return loc, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown timezone %q; use an IANA name such as Europe/Berlin", name)
+}
+
+// canonicalZoneName capitalizes each word and joins words with
+// underscores: "america/new york" becomes "America/New_York".
+func canonicalZoneName(name string) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		words := strings.Fields(strings.ReplaceAll(part, "_", " "))
+		for j, w := range words {
+			words[j] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+		}
+		parts[i] = strings.Join(words, "_")
+	}
+	return strings.Join(parts, "/")
+}
+
+// zoneLabel describes a time's zone, e.g. "CET (Europe/Berlin)".
+func zoneLabel(t time.Time) string {
+	abbr, _ := t.Zone()
+	name := t.Location().String()
+	if name == abbr || name == "" {
+		return abbr
+	}
+	return abbr + " (" + name + ")"
+}
@@ -10,6 +10,7 @@ import (
 
 	"nuimanbot/internal/domain"
 	notesRepo "nuimanbot/internal/usecase/notes"
+	"nuimanbot/internal/usecase/tool"
 )
 
 // Notes implements the domain.Tool interface for note management.
@@ -72,9 +73,13 @@ func (n *Notes) InputSchema() map[string]any {
 
 // Execute performs the notes operation.
 func (n *Notes) Execute(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
-	// Extract user ID from context
-	userID, ok := ctx.Value("user_id").(string)
-	if !ok || userID == "" {
+	// Extract user ID from context: the user the tool service runs us for,
+	// or a bare "user_id" value
+	userID, _ := ctx.Value("user_id").(string)
+	if user := tool.UserFromContext(ctx); user != nil {
+		userID = user.ID
+	}
+	if userID == "" {
 		return &domain.ExecutionResult{
 			Error: "user_id not found in context",
 		}, nil
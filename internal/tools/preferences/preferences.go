@@ -0,0 +1,132 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/tool"
+)
+
+// Preferences implements the domain.Tool interface for viewing and changing
+// the calling user's preferences.
+type Preferences struct {
+	repo   domain.PreferencesRepository
+	config domain.ToolConfig
+}
+
+// NewPreferences creates a new Preferences tool.
+func NewPreferences(repo domain.PreferencesRepository) *Preferences {
+	return &Preferences{
+		repo: repo,
+		config: domain.ToolConfig{
+			Enabled: true,
+		},
+	}
+}
+
+// Name returns the tool name.
+func (p *Preferences) Name() string {
+	return "preferences"
+}
+
+// Description returns the tool description.
+func (p *Preferences) Description() string {
+	return "View or change the user's preferences, such as whether model reasoning " +
+		"is shown alongside answers. Use it when the user asks to see or hide your reasoning"
+}
+
+// InputSchema returns the JSON schema for the tool's input parameters.
+func (p *Preferences) InputSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"operation": map[string]any{
+				"type":        "string",
+				"description": "Operation to perform: 'get' or 'set'",
+				"enum":        []string{"get", "set"},
+			},
+			"show_reasoning": map[string]any{
+				"type":        "boolean",
+				"description": "For 'set': show model reasoning (extended thinking) alongside answers",
+			},
+		},
+		"required": []string{"operation"},
+	}
+}
+
+// Execute performs the preferences operation.
+func (p *Preferences) Execute(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	user := tool.UserFromContext(ctx)
+	if user == nil {
+		return &domain.ExecutionResult{
+			Error: "user not found in context",
+		}, nil
+	}
+
+	prefs, err := p.repo.Get(ctx, user.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		prefs, err = domain.DefaultUserPreferences(), nil
+	}
+	if err != nil {
+		return &domain.ExecutionResult{
+			Error: fmt.Sprintf("failed to load preferences: %v", err),
+		}, nil
+	}
+
+	operation, _ := params["operation"].(string)
+	switch operation {
+	case "get":
+	case "set":
+		show, ok := params["show_reasoning"].(bool)
+		if !ok {
+			return &domain.ExecutionResult{
+				Error: "missing or invalid 'show_reasoning' parameter for 'set' operation",
+			}, nil
+		}
+		prefs.ShowReasoning = show
+		if err := p.repo.Save(ctx, user.ID, prefs); err != nil {
+			return &domain.ExecutionResult{
+				Error: fmt.Sprintf("failed to save preferences: %v", err),
+			}, nil
+		}
+	default:
+		return &domain.ExecutionResult{
+			Error: fmt.Sprintf("invalid operation: %q", operation),
+		}, nil
+	}
+
+	return &domain.ExecutionResult{
+		Output: describe(prefs),
+		Metadata: map[string]any{
+			"operation":      operation,
+			"show_reasoning": prefs.ShowReasoning,
+			"timezone":       prefs.Timezone,
+		},
+	}, nil
+}
+
+// describe lists the preferences users can change.
+func describe(prefs domain.UserPreferences) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Show reasoning: %t\n", prefs.ShowReasoning)
+	timezone := prefs.Timezone
+	if timezone == "" {
+		timezone = "server default"
+	}
+	fmt.Fprintf(&sb, "Timezone: %s", timezone)
+	return sb.String()
+}
+
+// RequiredPermissions returns the permissions required to execute this tool.
+func (p *Preferences) RequiredPermissions() []domain.Permission {
+	// Preferences only ever belong to the calling user
+	return []domain.Permission{}
+}
+
+// Config returns the tool's configuration.
+func (p *Preferences) Config() domain.ToolConfig {
+	return p.config
+}
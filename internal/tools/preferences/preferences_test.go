@@ -0,0 +1,66 @@
+package preferences_test
+
+import (
+	"context"
+	"testing"
+
+	"nuimanbot/internal/adapter/repository/memory"
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/tools/preferences"
+	"nuimanbot/internal/usecase/tool"
+)
+
+func TestPreferences_Execute_SetShowReasoning(t *testing.T) {
+	repo := memory.NewPreferencesRepository()
+	p := preferences.NewPreferences(repo)
+	ctx := tool.ContextWithUser(context.Background(), &domain.User{ID: "user-1"})
+
+	result, err := p.Execute(ctx, map[string]any{"operation": "set", "show_reasoning": true})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Unexpected error: %s", result.Error)
+	}
+	saved, err := repo.Get(context.Background(), "user-1")
+	if err != nil || !saved.ShowReasoning {
+		t.Fatalf("Expected show_reasoning to be saved, got %+v, %v", saved, err)
+	}
+
+	result, _ = p.Execute(ctx, map[string]any{"operation": "get"})
+	if result.Metadata["show_reasoning"] != true {
+		t.Errorf("Expected get to report show_reasoning, got %v", result.Metadata)
+	}
+
+	result, _ = p.Execute(ctx, map[string]any{"operation": "set", "show_reasoning": false})
+	if result.Error != "" || result.Metadata["show_reasoning"] != false {
+		t.Errorf("Expected show_reasoning to be turned off, got %+v", result)
+	}
+}
+
+func TestPreferences_Execute_Errors(t *testing.T) {
+	p := preferences.NewPreferences(memory.NewPreferencesRepository())
+	ctx := tool.ContextWithUser(context.Background(), &domain.User{ID: "user-1"})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		params map[string]any
+	}{
+		{"no user", context.Background(), map[string]any{"operation": "get"}},
+		{"invalid operation", ctx, map[string]any{"operation": "reset"}},
+		{"set without value", ctx, map[string]any{"operation": "set"}},
+		{"set with non-bool", ctx, map[string]any{"operation": "set", "show_reasoning": "yes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Execute(tt.ctx, tt.params)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if result.Error == "" {
+				t.Error("Expected an error result")
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	weatherClient "nuimanbot/internal/infrastructure/weather"
 )
@@ -42,10 +43,10 @@ func TestGetCurrentWeather_WithMockServer(t *testing.T) {
 	// Create weather tool with mock server
 	client := weatherClient.NewClientWithBaseURL("test-key", 10, server.URL)
 	w := &Weather{
-		client: client,
+		provider: client,
 	}
 
-	result, err := w.getCurrentWeather(context.Background(), "London", "metric")
+	result, err := w.getCurrentWeather(context.Background(), weatherClient.Place{Name: "London"}, "metric")
 	if err != nil {
 		t.Fatalf("getCurrentWeather() returned error: %v", err)
 	}
@@ -73,8 +74,8 @@ func TestGetCurrentWeather_WithMockServer(t *testing.T) {
 
 func TestGetCurrentWeather_ImperialUnits(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Verify units parameter
-		if r.URL.Query().Get("units") != "imperial" {
+		// Verify units parameter (the alerts request has none)
+		if strings.HasSuffix(r.URL.Path, "/weather") && r.URL.Query().Get("units") != "imperial" {
 			t.Errorf("Expected units=imperial, got %s", r.URL.Query().Get("units"))
 		}
 
@@ -94,9 +95,9 @@ func TestGetCurrentWeather_ImperialUnits(t *testing.T) {
 	defer server.Close()
 
 	client := weatherClient.NewClientWithBaseURL("test-key", 10, server.URL)
-	w := &Weather{client: client}
+	w := &Weather{provider: client}
 
-	result, err := w.getCurrentWeather(context.Background(), "New York", "imperial")
+	result, err := w.getCurrentWeather(context.Background(), weatherClient.Place{Name: "New York"}, "imperial")
 	if err != nil {
 		t.Fatalf("getCurrentWeather() returned error: %v", err)
 	}
@@ -138,9 +139,9 @@ func TestGetForecast_WithMockServer(t *testing.T) {
 	defer server.Close()
 
 	client := weatherClient.NewClientWithBaseURL("test-key", 10, server.URL)
-	w := &Weather{client: client}
+	w := &Weather{provider: client}
 
-	result, err := w.getForecast(context.Background(), "Tokyo", "metric")
+	result, err := w.getForecast(context.Background(), weatherClient.Place{Name: "Tokyo"}, "metric", weatherClient.GranularityHourly)
 	if err != nil {
 		t.Fatalf("getForecast() returned error: %v", err)
 	}
@@ -190,9 +191,9 @@ func TestGetForecast_ManyEntries(t *testing.T) {
 	defer server.Close()
 
 	client := weatherClient.NewClientWithBaseURL("test-key", 10, server.URL)
-	w := &Weather{client: client}
+	w := &Weather{provider: client}
 
-	result, err := w.getForecast(context.Background(), "Paris", "metric")
+	result, err := w.getForecast(context.Background(), weatherClient.Place{Name: "Paris"}, "metric", weatherClient.GranularityHourly)
 	if err != nil {
 		t.Fatalf("getForecast() returned error: %v", err)
 	}
@@ -223,9 +224,9 @@ func TestGetForecast_FewEntries(t *testing.T) {
 	defer server.Close()
 
 	client := weatherClient.NewClientWithBaseURL("test-key", 10, server.URL)
-	w := &Weather{client: client}
+	w := &Weather{provider: client}
 
-	result, err := w.getForecast(context.Background(), "Berlin", "metric")
+	result, err := w.getForecast(context.Background(), weatherClient.Place{Name: "Berlin"}, "metric", weatherClient.GranularityHourly)
 	if err != nil {
 		t.Fatalf("getForecast() returned error: %v", err)
 	}
@@ -258,9 +259,9 @@ func TestGetForecast_StandardUnits(t *testing.T) {
 	defer server.Close()
 
 	client := weatherClient.NewClientWithBaseURL("test-key", 10, server.URL)
-	w := &Weather{client: client}
+	w := &Weather{provider: client}
 
-	result, err := w.getForecast(context.Background(), "Moscow", "standard")
+	result, err := w.getForecast(context.Background(), weatherClient.Place{Name: "Moscow"}, "standard", weatherClient.GranularityHourly)
 	if err != nil {
 		t.Fatalf("getForecast() returned error: %v", err)
 	}
@@ -269,3 +270,114 @@ func TestGetForecast_StandardUnits(t *testing.T) {
 		t.Errorf("Expected units 'standard', got %v", result.Metadata["units"])
 	}
 }
+
+func TestExecute_OpenMeteoDailyForecast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search":
+			w.Write([]byte(`{"results": [{"name": "Oslo", "admin1": "Oslo", "country": "Norway", "country_code": "NO", "latitude": 59.91, "longitude": 10.75}]}`))
+		case "/forecast":
+			if r.URL.Query().Get("latitude") != "59.910000" {
+				t.Errorf("Expected geocoded latitude, got %s", r.URL.Query().Get("latitude"))
+			}
+			w.Write([]byte(`{
+				"utc_offset_seconds": 3600,
+				"daily": {
+					"time": [1609455600],
+					"temperature_2m_max": [-2.0],
+					"temperature_2m_min": [-8.5],
+					"weather_code": [73],
+					"precipitation_probability_max": [80]
+				}
+			}`))
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	w := NewWeatherWithProvider(weatherClient.NewOpenMeteoClientWithBaseURLs(10, server.URL, server.URL))
+
+	result, err := w.Execute(context.Background(), map[string]any{
+		"operation":   "forecast",
+		"location":    "Oslo, NO",
+		"granularity": "daily",
+	})
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Expected no error, got: %s", result.Error)
+	}
+
+	if !strings.Contains(result.Output, "Fri Jan 1: -8.5°C to -2.0°C - moderate snow fall (80% chance of precipitation)") {
+		t.Errorf("Unexpected output:\n%s", result.Output)
+	}
+	if result.Metadata["location"] != "Oslo, Oslo, NO" {
+		t.Errorf("Expected location 'Oslo, Oslo, NO', got %v", result.Metadata["location"])
+	}
+	if result.Metadata["provider"] != "open-meteo" {
+		t.Errorf("Expected provider 'open-meteo', got %v", result.Metadata["provider"])
+	}
+}
+
+func TestExecute_Alerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/geo/1.0/direct":
+			w.Write([]byte(`[{"name": "Miami", "state": "Florida", "country": "US", "lat": 25.77, "lon": -80.19}]`))
+		case "/data/3.0/onecall":
+			w.Write([]byte(`{"alerts": [{"sender_name": "NWS Miami", "event": "Hurricane Warning", "start": 1609459200, "end": 1609502400, "description": "Hurricane conditions expected."}]}`))
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	w := NewWeatherWithProvider(weatherClient.NewClientWithBaseURL("test-key", 10, server.URL))
+
+	result, err := w.Execute(context.Background(), map[string]any{
+		"operation": "alerts",
+		"location":  "Miami",
+	})
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Expected no error, got: %s", result.Error)
+	}
+	if !strings.Contains(result.Output, "Hurricane Warning (NWS Miami)") || !strings.Contains(result.Output, "Hurricane conditions expected.") {
+		t.Errorf("Unexpected output:\n%s", result.Output)
+	}
+
+	alerts, ok := result.Metadata["alerts"].([]map[string]any)
+	if !ok || len(alerts) != 1 {
+		t.Fatalf("Expected one alert in metadata, got %v", result.Metadata["alerts"])
+	}
+}
+
+func TestExecute_AlertsUnsupported(t *testing.T) {
+	w := NewWeatherWithProvider(weatherClient.NewCachedProvider(stubGeocoder{weatherClient.NewOpenMeteoClient(10)}, time.Minute))
+
+	result, err := w.Execute(context.Background(), map[string]any{
+		"operation": "alerts",
+		"location":  "Oslo",
+	})
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	if !strings.Contains(result.Error, "not available") {
+		t.Errorf("Expected alerts unavailable error, got %q", result.Error)
+	}
+}
+
+// stubGeocoder resolves every location without a network call.
+type stubGeocoder struct {
+	weatherClient.Provider
+}
+
+func (s stubGeocoder) Geocode(ctx context.Context, query string) (*weatherClient.Place, error) {
+	return &weatherClient.Place{Name: query}, nil
+}
@@ -2,7 +2,10 @@ package weather
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"nuimanbot/internal/domain"
@@ -11,14 +14,19 @@ import (
 
 // Weather implements the domain.Tool interface for weather information.
 type Weather struct {
-	client *weatherClient.Client
-	config domain.ToolConfig
+	provider weatherClient.Provider
+	config   domain.ToolConfig
 }
 
-// NewWeather creates a new Weather tool.
+// NewWeather creates a new Weather tool backed by OpenWeatherMap.
 func NewWeather(apiKey string, timeoutSeconds int) *Weather {
+	return NewWeatherWithProvider(weatherClient.NewClient(apiKey, timeoutSeconds))
+}
+
+// NewWeatherWithProvider creates a new Weather tool backed by provider.
+func NewWeatherWithProvider(provider weatherClient.Provider) *Weather {
 	return &Weather{
-		client: weatherClient.NewClient(apiKey, timeoutSeconds),
+		provider: provider,
 		config: domain.ToolConfig{
 			Enabled: true,
 		},
@@ -32,7 +40,7 @@ func (w *Weather) Name() string {
 
 // Description returns the tool description.
 func (w *Weather) Description() string {
-	return "Get current weather or forecast for any location using OpenWeatherMap API"
+	return "Get current weather, forecasts or active weather alerts for any location"
 }
 
 // InputSchema returns the JSON schema for the tool's input parameters.
@@ -42,8 +50,8 @@ func (w *Weather) InputSchema() map[string]any {
 		"properties": map[string]any{
 			"operation": map[string]any{
 				"type":        "string",
-				"description": "Operation to perform: 'current' for current weather, 'forecast' for 5-day forecast",
-				"enum":        []string{"current", "forecast"},
+				"description": "Operation to perform: 'current' for current weather, 'forecast' for 5-day forecast, 'alerts' for active weather alerts",
+				"enum":        []string{"current", "forecast", "alerts"},
 			},
 			"location": map[string]any{
 				"type":        "string",
@@ -55,6 +63,12 @@ func (w *Weather) InputSchema() map[string]any {
 				"enum":        []string{"metric", "imperial", "standard"},
 				"default":     "metric",
 			},
+			"granularity": map[string]any{
+				"type":        "string",
+				"description": "Forecast steps: 'hourly' for the next 24 hours, 'daily' for one entry per day with min/max temperatures",
+				"enum":        []string{"hourly", "daily"},
+				"default":     "hourly",
+			},
 		},
 		"required": []string{"operation", "location"},
 	}
@@ -91,22 +105,45 @@ func (w *Weather) Execute(ctx context.Context, params map[string]any) (*domain.E
 		}, nil
 	}
 
+	// Extract granularity (optional, default to hourly)
+	granularity := weatherClient.GranularityHourly
+	if g, ok := params["granularity"].(string); ok && g != "" {
+		granularity = weatherClient.Granularity(g)
+	}
+	if granularity != weatherClient.GranularityHourly && granularity != weatherClient.GranularityDaily {
+		return &domain.ExecutionResult{
+			Error: fmt.Sprintf("invalid granularity: %s (must be hourly or daily)", granularity),
+		}, nil
+	}
+
+	if operation != "current" && operation != "forecast" && operation != "alerts" {
+		return &domain.ExecutionResult{
+			Error: fmt.Sprintf("invalid operation: %s (must be 'current', 'forecast' or 'alerts')", operation),
+		}, nil
+	}
+
+	// Resolve the location before fetching any weather data
+	place, err := w.provider.Geocode(ctx, location)
+	if err != nil {
+		return &domain.ExecutionResult{
+			Error: fmt.Sprintf("failed to find location: %v", err),
+		}, nil
+	}
+
 	// Execute operation
 	switch operation {
 	case "current":
-		return w.getCurrentWeather(ctx, location, units)
+		return w.getCurrentWeather(ctx, *place, units)
 	case "forecast":
-		return w.getForecast(ctx, location, units)
+		return w.getForecast(ctx, *place, units, granularity)
 	default:
-		return &domain.ExecutionResult{
-			Error: fmt.Sprintf("invalid operation: %s (must be 'current' or 'forecast')", operation),
-		}, nil
+		return w.getAlerts(ctx, *place)
 	}
 }
 
-// getCurrentWeather fetches current weather for a location.
-func (w *Weather) getCurrentWeather(ctx context.Context, location, units string) (*domain.ExecutionResult, error) {
-	current, err := w.client.GetCurrentWeather(ctx, location, units)
+// getCurrentWeather fetches current weather and any active alerts for a place.
+func (w *Weather) getCurrentWeather(ctx context.Context, place weatherClient.Place, units string) (*domain.ExecutionResult, error) {
+	current, err := w.provider.GetCurrentWeather(ctx, place, units)
 	if err != nil {
 		return &domain.ExecutionResult{
 			Error: fmt.Sprintf("failed to get current weather: %v", err),
@@ -120,72 +157,173 @@ func (w *Weather) getCurrentWeather(ctx context.Context, location, units string)
 	output += fmt.Sprintf("Conditions: %s\n", current.Description)
 	output += fmt.Sprintf("Humidity: %d%%\n", current.Humidity)
 	output += fmt.Sprintf("Pressure: %d hPa\n", current.Pressure)
-	output += fmt.Sprintf("Wind Speed: %.1f m/s", current.WindSpeed)
+	output += fmt.Sprintf("Wind Speed: %.1f %s", current.WindSpeed, w.getWindSpeedUnit(units))
+
+	metadata := map[string]any{
+		"location":    current.Location,
+		"temperature": current.Temperature,
+		"feels_like":  current.FeelsLike,
+		"humidity":    current.Humidity,
+		"pressure":    current.Pressure,
+		"description": current.Description,
+		"wind_speed":  current.WindSpeed,
+		"units":       units,
+		"provider":    w.provider.Name(),
+	}
+
+	// Alerts are best effort: current conditions are still useful without them
+	alerts, err := w.provider.GetAlerts(ctx, place)
+	if err != nil && !errors.Is(err, weatherClient.ErrAlertsUnsupported) {
+		slog.Debug("Weather alerts unavailable", "provider", w.provider.Name(), "error", err)
+	}
+	if len(alerts) > 0 {
+		output += "\n\n" + w.formatAlerts(alerts)
+		metadata["alerts"] = w.alertsMetadata(alerts)
+	}
 
 	return &domain.ExecutionResult{
-		Output: output,
-		Metadata: map[string]any{
-			"location":    current.Location,
-			"temperature": current.Temperature,
-			"feels_like":  current.FeelsLike,
-			"humidity":    current.Humidity,
-			"pressure":    current.Pressure,
-			"description": current.Description,
-			"wind_speed":  current.WindSpeed,
-			"units":       units,
-		},
+		Output:   output,
+		Metadata: metadata,
 	}, nil
 }
 
-// getForecast fetches 5-day forecast for a location.
-func (w *Weather) getForecast(ctx context.Context, location, units string) (*domain.ExecutionResult, error) {
-	forecast, err := w.client.GetForecast(ctx, location, units)
+// getForecast fetches the 5-day forecast for a place.
+func (w *Weather) getForecast(ctx context.Context, place weatherClient.Place, units string, granularity weatherClient.Granularity) (*domain.ExecutionResult, error) {
+	forecast, err := w.provider.GetForecast(ctx, place, units, granularity)
 	if err != nil {
 		return &domain.ExecutionResult{
 			Error: fmt.Sprintf("failed to get forecast: %v", err),
 		}, nil
 	}
 
-	// Format response (show first 8 entries = ~24 hours)
+	// Format response in the location's local time (daily: every day,
+	// hourly: the next ~24 hours)
 	unitsSymbol := w.getUnitsSymbol(units)
+	zone := time.FixedZone("", forecast.UTCOffset)
 	output := fmt.Sprintf("Weather forecast for %s:\n\n", forecast.Location)
 
-	maxEntries := 8
-	if len(forecast.Forecasts) < maxEntries {
-		maxEntries = len(forecast.Forecasts)
-	}
-
-	for i := 0; i < maxEntries; i++ {
-		entry := forecast.Forecasts[i]
-		timestamp := time.Unix(entry.Timestamp, 0)
-		output += fmt.Sprintf("%s: %.1f%s - %s\n",
-			timestamp.Format("Mon 15:04"),
-			entry.Temperature,
-			unitsSymbol,
-			entry.Description,
-		)
+	for _, entry := range forecast.Forecasts {
+		timestamp := time.Unix(entry.Timestamp, 0).In(zone)
+		if granularity == weatherClient.GranularityDaily {
+			output += fmt.Sprintf("%s: %.1f%s to %.1f%s - %s",
+				timestamp.Format("Mon Jan 2"),
+				entry.TempMin,
+				unitsSymbol,
+				entry.TempMax,
+				unitsSymbol,
+				entry.Description,
+			)
+		} else {
+			if timestamp.Sub(time.Unix(forecast.Forecasts[0].Timestamp, 0)) >= 24*time.Hour {
+				break
+			}
+			output += fmt.Sprintf("%s: %.1f%s - %s",
+				timestamp.Format("Mon 15:04"),
+				entry.Temperature,
+				unitsSymbol,
+				entry.Description,
+			)
+		}
+		if entry.PrecipitationChance > 0 {
+			output += fmt.Sprintf(" (%d%% chance of precipitation)", entry.PrecipitationChance)
+		}
+		output += "\n"
 	}
 
 	// Convert forecasts for metadata field
 	forecastsData := make([]map[string]any, len(forecast.Forecasts))
 	for i, entry := range forecast.Forecasts {
 		forecastsData[i] = map[string]any{
-			"timestamp":   entry.Timestamp,
-			"temperature": entry.Temperature,
-			"description": entry.Description,
+			"timestamp":            entry.Timestamp,
+			"temperature":          entry.Temperature,
+			"temp_min":             entry.TempMin,
+			"temp_max":             entry.TempMax,
+			"description":          entry.Description,
+			"precipitation_chance": entry.PrecipitationChance,
 		}
 	}
 
 	return &domain.ExecutionResult{
 		Output: output,
 		Metadata: map[string]any{
-			"location":  forecast.Location,
-			"forecasts": forecastsData,
-			"units":     units,
+			"location":    forecast.Location,
+			"forecasts":   forecastsData,
+			"units":       units,
+			"granularity": string(granularity),
+			"provider":    w.provider.Name(),
+		},
+	}, nil
+}
+
+// getAlerts fetches active weather alerts for a place.
+func (w *Weather) getAlerts(ctx context.Context, place weatherClient.Place) (*domain.ExecutionResult, error) {
+	alerts, err := w.provider.GetAlerts(ctx, place)
+	if err != nil {
+		return &domain.ExecutionResult{
+			Error: fmt.Sprintf("failed to get weather alerts: %v", err),
+		}, nil
+	}
+
+	output := fmt.Sprintf("No active weather alerts for %s.", place.DisplayName())
+	if len(alerts) > 0 {
+		output = fmt.Sprintf("Weather alerts for %s:\n\n%s", place.DisplayName(), w.formatAlerts(alerts))
+	}
+
+	return &domain.ExecutionResult{
+		Output: output,
+		Metadata: map[string]any{
+			"location": place.DisplayName(),
+			"alerts":   w.alertsMetadata(alerts),
+			"provider": w.provider.Name(),
 		},
 	}, nil
 }
 
+// formatAlerts renders alerts as one block per alert.
+func (w *Weather) formatAlerts(alerts []weatherClient.Alert) string {
+	blocks := make([]string, len(alerts))
+	for i, alert := range alerts {
+		block := fmt.Sprintf("⚠ %s", alert.Event)
+		if alert.Sender != "" {
+			block += fmt.Sprintf(" (%s)", alert.Sender)
+		}
+		if alert.Start > 0 && alert.End > 0 {
+			block += fmt.Sprintf("\n%s until %s",
+				time.Unix(alert.Start, 0).UTC().Format("Mon Jan 2 15:04 MST"),
+				time.Unix(alert.End, 0).UTC().Format("Mon Jan 2 15:04 MST"),
+			)
+		}
+		if desc := strings.TrimSpace(alert.Description); desc != "" {
+			block += "\n" + desc
+		}
+		blocks[i] = block
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// alertsMetadata converts alerts for the metadata field.
+func (w *Weather) alertsMetadata(alerts []weatherClient.Alert) []map[string]any {
+	data := make([]map[string]any, len(alerts))
+	for i, alert := range alerts {
+		data[i] = map[string]any{
+			"event":       alert.Event,
+			"sender":      alert.Sender,
+			"start":       alert.Start,
+			"end":         alert.End,
+			"description": alert.Description,
+		}
+	}
+	return data
+}
+
+// getWindSpeedUnit returns the wind speed unit for the given units.
+func (w *Weather) getWindSpeedUnit(units string) string {
+	if units == "imperial" {
+		return "mph"
+	}
+	return "m/s"
+}
+
 // getUnitsSymbol returns the temperature symbol for the given units.
 func (w *Weather) getUnitsSymbol(units string) string {
 	switch units {
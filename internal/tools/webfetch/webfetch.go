@@ -0,0 +1,142 @@
+package webfetch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/fetch"
+)
+
+const (
+	defaultMaxChars = 20000
+	maxMaxChars     = 100000
+)
+
+// WebFetch implements the domain.Tool interface for reading web pages and
+// documents.
+type WebFetch struct {
+	fetcher *fetch.Service
+	config  domain.ToolConfig
+}
+
+// NewWebFetch creates a new WebFetch tool backed by fetcher; nil uses a
+// service with default options.
+func NewWebFetch(fetcher *fetch.Service) *WebFetch {
+	if fetcher == nil {
+		fetcher = fetch.NewService(fetch.Options{})
+	}
+	return &WebFetch{
+		fetcher: fetcher,
+		config: domain.ToolConfig{
+			Enabled: true,
+		},
+	}
+}
+
+// Name returns the tool name.
+func (w *WebFetch) Name() string {
+	return "web_fetch"
+}
+
+// Description returns the tool description.
+func (w *WebFetch) Description() string {
+	return "Fetch a web page or document (HTML, PDF, plain text, JSON) and return its main readable text"
+}
+
+// InputSchema returns the JSON schema for the tool's input parameters.
+func (w *WebFetch) InputSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"url": map[string]any{
+				"type":        "string",
+				"description": "HTTP or HTTPS URL to fetch",
+			},
+			"max_chars": map[string]any{
+				"type":        "integer",
+				"description": "Maximum characters of text to return; longer documents are truncated",
+				"default":     defaultMaxChars,
+				"minimum":     1,
+				"maximum":     maxMaxChars,
+			},
+		},
+		"required": []string{"url"},
+	}
+}
+
+// Execute fetches the URL and returns its text.
+func (w *WebFetch) Execute(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	rawURL, ok := params["url"].(string)
+	if !ok || rawURL == "" {
+		return &domain.ExecutionResult{
+			Error: "missing url parameter",
+		}, nil
+	}
+
+	maxChars := defaultMaxChars
+	if m, ok := params["max_chars"].(float64); ok {
+		maxChars = int(m)
+	} else if m, ok := params["max_chars"].(int); ok {
+		maxChars = m
+	}
+	if maxChars < 1 || maxChars > maxMaxChars {
+		return &domain.ExecutionResult{
+			Error: fmt.Sprintf("max_chars must be between 1 and %d", maxMaxChars),
+		}, nil
+	}
+
+	doc, err := w.fetcher.Fetch(ctx, fetch.Request{URL: rawURL})
+	if err != nil {
+		return &domain.ExecutionResult{
+			Error: fmt.Sprintf("fetch failed: %v", err),
+		}, nil
+	}
+
+	text := doc.Text
+	runes := []rune(text)
+	truncated := len(runes) > maxChars
+	if truncated {
+		text = string(runes[:maxChars])
+	}
+
+	// Format output
+	var output strings.Builder
+	if doc.Title != "" {
+		output.WriteString(fmt.Sprintf("Title: %s\n", doc.Title))
+	}
+	if doc.Byline != "" {
+		output.WriteString(fmt.Sprintf("Author: %s\n", doc.Byline))
+	}
+	output.WriteString(fmt.Sprintf("URL: %s\n\n", doc.URL))
+	if text == "" {
+		output.WriteString("No readable text found.")
+	} else {
+		output.WriteString(text)
+	}
+	if truncated {
+		output.WriteString(fmt.Sprintf("\n\n[Truncated: showing %d of %d characters]", maxChars, len(runes)))
+	}
+
+	return &domain.ExecutionResult{
+		Output: output.String(),
+		Metadata: map[string]any{
+			"url":          doc.URL,
+			"title":        doc.Title,
+			"content_type": doc.ContentType,
+			"chars":        len(runes),
+			"truncated":    truncated,
+		},
+	}, nil
+}
+
+// RequiredPermissions returns the permissions required for this tool.
+func (w *WebFetch) RequiredPermissions() []domain.Permission {
+	return []domain.Permission{domain.PermissionNetwork}
+}
+
+// Config returns the tool's configuration.
+func (w *WebFetch) Config() domain.ToolConfig {
+	return w.config
+}
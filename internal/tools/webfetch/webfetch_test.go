@@ -0,0 +1,118 @@
+package webfetch_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/fetch"
+	"nuimanbot/internal/tools/webfetch"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/post" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Release notes</title><meta name="author" content="Ada"></head>
+			<body><nav>Menu</nav><article><p>Version 2 adds streaming responses.</p></article></body></html>`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebFetch_Metadata(t *testing.T) {
+	tool := webfetch.NewWebFetch(nil)
+
+	if tool.Name() != "web_fetch" {
+		t.Errorf("Expected name 'web_fetch', got '%s'", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Description should not be empty")
+	}
+	if perms := tool.RequiredPermissions(); len(perms) != 1 || perms[0] != domain.PermissionNetwork {
+		t.Errorf("Expected network permission, got %v", perms)
+	}
+}
+
+func TestWebFetch_Execute(t *testing.T) {
+	server := newServer(t)
+	tool := webfetch.NewWebFetch(fetch.NewService(fetch.Options{AllowPrivateNetworks: true}))
+
+	result, err := tool.Execute(context.Background(), map[string]any{"url": server.URL + "/post"})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Expected no error, got: %s", result.Error)
+	}
+
+	want := "Title: Release notes\nAuthor: Ada\nURL: " + server.URL + "/post\n\nVersion 2 adds streaming responses."
+	if result.Output != want {
+		t.Errorf("Unexpected output:\n%s", result.Output)
+	}
+	if result.Metadata["content_type"] != "text/html" || result.Metadata["truncated"] != false {
+		t.Errorf("Unexpected metadata: %v", result.Metadata)
+	}
+}
+
+func TestWebFetch_Execute_Truncates(t *testing.T) {
+	server := newServer(t)
+	tool := webfetch.NewWebFetch(fetch.NewService(fetch.Options{AllowPrivateNetworks: true}))
+
+	result, err := tool.Execute(context.Background(), map[string]any{"url": server.URL + "/post", "max_chars": float64(9)})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Output, "Version 2\n\n[Truncated: showing 9 of 35 characters]") {
+		t.Errorf("Expected truncation note, got:\n%s", result.Output)
+	}
+	if result.Metadata["truncated"] != true {
+		t.Error("Expected truncated metadata to be true")
+	}
+}
+
+func TestWebFetch_Execute_Errors(t *testing.T) {
+	server := newServer(t)
+	tool := webfetch.NewWebFetch(fetch.NewService(fetch.Options{AllowPrivateNetworks: true}))
+
+	tests := []struct {
+		name   string
+		params map[string]any
+	}{
+		{"missing url", map[string]any{}},
+		{"bad max_chars", map[string]any{"url": server.URL + "/post", "max_chars": 0}},
+		{"unsupported scheme", map[string]any{"url": "file:///etc/passwd"}},
+		{"not found", map[string]any{"url": server.URL + "/missing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Execute(context.Background(), tt.params)
+			if err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+			if result.Error == "" {
+				t.Error("Expected an error result")
+			}
+		})
+	}
+}
+
+func TestWebFetch_Execute_PrivateNetworkBlockedByDefault(t *testing.T) {
+	server := newServer(t)
+	tool := webfetch.NewWebFetch(nil)
+
+	result, err := tool.Execute(context.Background(), map[string]any{"url": server.URL + "/post"})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Error, "private network") {
+		t.Errorf("Expected private network error, got %q", result.Error)
+	}
+}
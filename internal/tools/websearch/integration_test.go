@@ -29,8 +29,8 @@ func TestExecute_WithMockServer_MultipleResults(t *testing.T) {
 
 	client := searchClient.NewClientWithBaseURL(10, server.URL)
 	w := &WebSearch{
-		client: client,
-		config: domain.ToolConfig{Enabled: true},
+		provider: client,
+		config:   domain.ToolConfig{Enabled: true},
 	}
 
 	result, err := w.Execute(context.Background(), map[string]any{
@@ -81,8 +81,8 @@ func TestExecute_WithMockServer_NoResults(t *testing.T) {
 
 	client := searchClient.NewClientWithBaseURL(10, server.URL)
 	w := &WebSearch{
-		client: client,
-		config: domain.ToolConfig{Enabled: true},
+		provider: client,
+		config:   domain.ToolConfig{Enabled: true},
 	}
 
 	result, err := w.Execute(context.Background(), map[string]any{
@@ -117,8 +117,8 @@ func TestExecute_WithMockServer_ResultsWithoutSnippets(t *testing.T) {
 
 	client := searchClient.NewClientWithBaseURL(10, server.URL)
 	w := &WebSearch{
-		client: client,
-		config: domain.ToolConfig{Enabled: true},
+		provider: client,
+		config:   domain.ToolConfig{Enabled: true},
 	}
 
 	result, err := w.Execute(context.Background(), map[string]any{
@@ -155,8 +155,8 @@ func TestExecute_WithMockServer_ServerError(t *testing.T) {
 
 	client := searchClient.NewClientWithBaseURL(10, server.URL)
 	w := &WebSearch{
-		client: client,
-		config: domain.ToolConfig{Enabled: true},
+		provider: client,
+		config:   domain.ToolConfig{Enabled: true},
 	}
 
 	result, err := w.Execute(context.Background(), map[string]any{
@@ -190,8 +190,8 @@ func TestExecute_WithMockServer_LimitAsFloat64(t *testing.T) {
 
 	client := searchClient.NewClientWithBaseURL(10, server.URL)
 	w := &WebSearch{
-		client: client,
-		config: domain.ToolConfig{Enabled: true},
+		provider: client,
+		config:   domain.ToolConfig{Enabled: true},
 	}
 
 	result, err := w.Execute(context.Background(), map[string]any{
@@ -224,8 +224,8 @@ func TestExecute_WithMockServer_LimitAsInt(t *testing.T) {
 
 	client := searchClient.NewClientWithBaseURL(10, server.URL)
 	w := &WebSearch{
-		client: client,
-		config: domain.ToolConfig{Enabled: true},
+		provider: client,
+		config:   domain.ToolConfig{Enabled: true},
 	}
 
 	result, err := w.Execute(context.Background(), map[string]any{
@@ -259,8 +259,8 @@ func TestExecute_WithMockServer_ManyResults(t *testing.T) {
 
 	client := searchClient.NewClientWithBaseURL(10, server.URL)
 	w := &WebSearch{
-		client: client,
-		config: domain.ToolConfig{Enabled: true},
+		provider: client,
+		config:   domain.ToolConfig{Enabled: true},
 	}
 
 	result, err := w.Execute(context.Background(), map[string]any{
@@ -286,3 +286,34 @@ func TestExecute_WithMockServer_ManyResults(t *testing.T) {
 		t.Error("Expected non-zero result count")
 	}
 }
+
+func TestExecute_FiltersOverrideDefaults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("kl") != "fr-fr" || q.Get("kp") != "1" || q.Get("df") != "d" {
+			t.Errorf("Expected kl=fr-fr kp=1 df=d, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`<a class="result__a" href="https://example.fr/">Actualités</a>`))
+	}))
+	defer server.Close()
+
+	w := NewWebSearchWithProvider(searchClient.NewClientWithBaseURL(10, server.URL), searchClient.Options{
+		Region:     "de-de",
+		SafeSearch: searchClient.SafeSearchStrict,
+	})
+
+	result, err := w.Execute(context.Background(), map[string]any{
+		"query":     "actualités",
+		"freshness": "day",
+		"region":    "fr-fr",
+	})
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Expected no error, got: %s", result.Error)
+	}
+	if result.Metadata["provider"] != "duckduckgo" {
+		t.Errorf("Expected provider 'duckduckgo', got %v", result.Metadata["provider"])
+	}
+}
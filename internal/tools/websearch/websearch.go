@@ -11,14 +11,23 @@ import (
 
 // WebSearch implements the domain.Tool interface for web search.
 type WebSearch struct {
-	client *searchClient.Client
-	config domain.ToolConfig
+	provider searchClient.Provider
+	defaults searchClient.Options
+	config   domain.ToolConfig
 }
 
-// NewWebSearch creates a new WebSearch tool.
+// NewWebSearch creates a new WebSearch tool backed by DuckDuckGo.
 func NewWebSearch(timeoutSeconds int) *WebSearch {
+	return NewWebSearchWithProvider(searchClient.NewClient(timeoutSeconds), searchClient.Options{})
+}
+
+// NewWebSearchWithProvider creates a new WebSearch tool backed by provider.
+// defaults supplies the region and safe-search level for calls that do not
+// set them.
+func NewWebSearchWithProvider(provider searchClient.Provider, defaults searchClient.Options) *WebSearch {
 	return &WebSearch{
-		client: searchClient.NewClient(timeoutSeconds),
+		provider: provider,
+		defaults: defaults,
 		config: domain.ToolConfig{
 			Enabled: true,
 		},
@@ -32,7 +41,7 @@ func (w *WebSearch) Name() string {
 
 // Description returns the tool description.
 func (w *WebSearch) Description() string {
-	return "Perform web searches using DuckDuckGo and return relevant results"
+	return "Perform web searches and return relevant results, optionally limited by freshness or region"
 }
 
 // InputSchema returns the JSON schema for the tool's input parameters.
@@ -51,6 +60,21 @@ func (w *WebSearch) InputSchema() map[string]any {
 				"minimum":     1,
 				"maximum":     50,
 			},
+			"freshness": map[string]any{
+				"type":        "string",
+				"description": "Only return pages from the past day, week, month or year",
+				"enum":        []string{"day", "week", "month", "year"},
+			},
+			"region": map[string]any{
+				"type":        "string",
+				"description": "Country-language code to prefer regional results (e.g., 'us-en', 'de-de', 'fr-fr')",
+				"pattern":     "^[A-Za-z]{2}-[A-Za-z]{2}$",
+			},
+			"safe_search": map[string]any{
+				"type":        "string",
+				"description": "Adult content filter",
+				"enum":        []string{"off", "moderate", "strict"},
+			},
 		},
 		"required": []string{"query"},
 	}
@@ -81,8 +105,21 @@ func (w *WebSearch) Execute(ctx context.Context, params map[string]any) (*domain
 		}, nil
 	}
 
+	// Per-call filters override the configured defaults
+	opts := w.defaults
+	opts.Limit = limit
+	if f, ok := params["freshness"].(string); ok {
+		opts.Freshness = searchClient.Freshness(f)
+	}
+	if r, ok := params["region"].(string); ok && r != "" {
+		opts.Region = r
+	}
+	if s, ok := params["safe_search"].(string); ok && s != "" {
+		opts.SafeSearch = searchClient.SafeSearch(s)
+	}
+
 	// Perform search
-	results, err := w.client.Search(ctx, query, limit)
+	results, err := w.provider.Search(ctx, query, opts)
 	if err != nil {
 		return &domain.ExecutionResult{
 			Error: fmt.Sprintf("search failed: %v", err),
@@ -119,9 +156,10 @@ func (w *WebSearch) Execute(ctx context.Context, params map[string]any) (*domain
 	return &domain.ExecutionResult{
 		Output: output.String(),
 		Metadata: map[string]any{
-			"query":   query,
-			"count":   len(results),
-			"results": resultsData,
+			"query":    query,
+			"count":    len(results),
+			"results":  resultsData,
+			"provider": w.provider.Name(),
 		},
 	}, nil
 }
@@ -0,0 +1,78 @@
+package batch
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"nuimanbot/internal/domain"
+)
+
+// inputLine is one line of a batch input file. Either prompt or messages is
+// required; prompt is shorthand for a single user message.
+type inputLine struct {
+	CustomID    string           `json:"custom_id"`
+	Model       string           `json:"model"`
+	System      string           `json:"system"`
+	Prompt      string           `json:"prompt"`
+	Messages    []domain.Message `json:"messages"`
+	MaxTokens   int              `json:"max_tokens"`
+	Temperature float64          `json:"temperature"`
+}
+
+// ParseRequests reads batch requests from JSONL, one request per line:
+//
+//	{"custom_id": "conv-42", "model": "claude-haiku-4-5", "system": "Summarize.", "prompt": "..."}
+//
+// Lines without a custom_id are numbered ("item-1", "item-2", ...). model is
+// used for lines that do not set one. Blank lines are skipped.
+func ParseRequests(r io.Reader, model string) ([]domain.BatchRequest, error) {
+	var requests []domain.BatchRequest
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Archived conversations can be long
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var line inputLine
+		if err := json.Unmarshal([]byte(text), &line); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		messages := line.Messages
+		if line.Prompt != "" {
+			messages = append(messages, domain.Message{Role: "user", Content: line.Prompt})
+		}
+		if len(messages) == 0 {
+			return nil, fmt.Errorf("line %d: prompt or messages is required", lineNum)
+		}
+
+		req := &domain.LLMRequest{
+			Model:        line.Model,
+			Messages:     messages,
+			SystemPrompt: line.System,
+			MaxTokens:    line.MaxTokens,
+			Temperature:  line.Temperature,
+		}
+		if req.Model == "" {
+			req.Model = model
+		}
+
+		customID := line.CustomID
+		if customID == "" {
+			customID = fmt.Sprintf("item-%d", len(requests)+1)
+		}
+		requests = append(requests, domain.BatchRequest{CustomID: customID, Request: req})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch input: %w", err)
+	}
+	return requests, nil
+}
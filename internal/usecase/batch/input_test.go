@@ -0,0 +1,55 @@
+package batch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRequests(t *testing.T) {
+	input := `{"custom_id": "conv-1", "system": "Summarize.", "prompt": "long conversation"}
+
+{"model": "gpt-4o-mini", "messages": [{"role": "user", "content": "hi"}, {"role": "assistant", "content": "hello"}], "prompt": "bye", "max_tokens": 100}
+`
+	reqs, err := ParseRequests(strings.NewReader(input), "claude-haiku-4-5")
+	if err != nil {
+		t.Fatalf("ParseRequests failed: %v", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("Got %d requests, want 2", len(reqs))
+	}
+
+	first := reqs[0]
+	if first.CustomID != "conv-1" || first.Request.Model != "claude-haiku-4-5" || first.Request.SystemPrompt != "Summarize." {
+		t.Errorf("Unexpected first request: %s %+v", first.CustomID, first.Request)
+	}
+
+	second := reqs[1]
+	if second.CustomID != "item-2" {
+		t.Errorf("CustomID = %q, want item-2", second.CustomID)
+	}
+	if second.Request.Model != "gpt-4o-mini" || second.Request.MaxTokens != 100 {
+		t.Errorf("Unexpected second request: %+v", second.Request)
+	}
+	if n := len(second.Request.Messages); n != 3 || second.Request.Messages[2].Content != "bye" {
+		t.Errorf("Expected prompt appended after messages, got %+v", second.Request.Messages)
+	}
+}
+
+func TestParseRequests_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"invalid JSON", "{not json}", "line 1"},
+		{"no prompt", `{"custom_id": "a"}` + "\n" + `{"system": "x"}`, "line 1: prompt or messages is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRequests(strings.NewReader(tt.input), "")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package batch
+
+import (
+	"context"
+
+	"nuimanbot/internal/domain"
+)
+
+// Repository defines the interface for batch job persistence.
+type Repository interface {
+	// CreateJob stores a new job together with its items.
+	CreateJob(ctx context.Context, job *domain.BatchJob, items []domain.BatchItem) error
+
+	// GetJob retrieves a job by ID, with its item counts.
+	GetJob(ctx context.Context, jobID string) (*domain.BatchJob, error)
+
+	// ListJobs retrieves the most recent jobs, newest first.
+	ListJobs(ctx context.Context, limit int) ([]*domain.BatchJob, error)
+
+	// ListActiveJobs retrieves jobs that have not reached a terminal state, oldest first.
+	ListActiveJobs(ctx context.Context) ([]*domain.BatchJob, error)
+
+	// UpdateJob updates a job's status, provider batch ID and error.
+	UpdateJob(ctx context.Context, job *domain.BatchJob) error
+
+	// ListItems retrieves a job's items in submission order. A status of ""
+	// matches all items; limit <= 0 means no limit.
+	ListItems(ctx context.Context, jobID string, status domain.BatchItemStatus, offset, limit int) ([]domain.BatchItem, error)
+
+	// SaveItemResult stores an item's status, response and error.
+	SaveItemResult(ctx context.Context, item *domain.BatchItem) error
+
+	// CancelPendingItems marks a job's pending items as cancelled.
+	CancelPendingItems(ctx context.Context, jobID string) error
+}
@@ -0,0 +1,538 @@
+// Package batch runs bulk LLM work (e.g. summarizing archived conversations or
+// running evals) offline. Jobs go to a provider's batch endpoint when one is
+// available, which is cheaper than the synchronous API, or through a local
+// bounded worker pool otherwise. Job and item state is persisted so that
+// progress survives restarts and partial results can be read at any time.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"nuimanbot/internal/domain"
+)
+
+const (
+	// DefaultWorkers is the size of the local worker pool when none is configured.
+	DefaultWorkers = 4
+
+	// DefaultPollInterval is how often provider batches are checked when none is configured.
+	DefaultPollInterval = time.Minute
+
+	// MaxRequestsPerJob bounds a single job (provider batch APIs cap batch size similarly).
+	MaxRequestsPerJob = 10000
+)
+
+// RequestDefaults fills configured defaults into a request before it is
+// submitted to a provider batch endpoint, which bypasses the LLMService
+// decorators. llm.ParamsService satisfies it.
+type RequestDefaults interface {
+	Merge(provider domain.LLMProvider, req *domain.LLMRequest) *domain.LLMRequest
+}
+
+// JobSpec describes a job to submit.
+type JobSpec struct {
+	Name      string
+	Provider  domain.LLMProvider
+	CreatedBy string
+	Requests  []domain.BatchRequest
+	Local     bool // Use the local worker pool even if the provider has a batch endpoint
+}
+
+// Service submits, tracks and cancels batch jobs.
+type Service struct {
+	repo         Repository
+	llmService   domain.LLMService
+	batcher      domain.LLMBatchService // Optional: provider batch endpoints
+	defaults     RequestDefaults        // Optional
+	pollInterval time.Duration
+	sem          chan struct{} // Bounds concurrent local requests across jobs
+
+	ctx     context.Context // Parent of local job contexts; cancelled by Stop
+	stop    context.CancelFunc
+	mu      sync.Mutex
+	running map[string]context.CancelFunc // Local jobs in progress
+	wg      sync.WaitGroup
+}
+
+// NewService creates a batch job service. workers bounds concurrent local
+// requests; non-positive values use DefaultWorkers.
+func NewService(repo Repository, llmService domain.LLMService, workers int) *Service {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	return &Service{
+		repo:         repo,
+		llmService:   llmService,
+		pollInterval: DefaultPollInterval,
+		sem:          make(chan struct{}, workers),
+		ctx:          ctx,
+		stop:         stop,
+		running:      make(map[string]context.CancelFunc),
+	}
+}
+
+// SetBatchProvider enables provider batch endpoints for the providers b supports.
+func (s *Service) SetBatchProvider(b domain.LLMBatchService) {
+	s.batcher = b
+}
+
+// SetRequestDefaults applies configured request defaults to provider batches.
+func (s *Service) SetRequestDefaults(d RequestDefaults) {
+	s.defaults = d
+}
+
+// SetPollInterval sets how often provider batches are checked.
+func (s *Service) SetPollInterval(d time.Duration) {
+	if d > 0 {
+		s.pollInterval = d
+	}
+}
+
+// Submit stores a new job and starts it. Provider batches are submitted
+// immediately; local jobs run in the background.
+func (s *Service) Submit(ctx context.Context, spec JobSpec) (*domain.BatchJob, error) {
+	if spec.Provider == "" {
+		return nil, fmt.Errorf("provider is required: %w", domain.ErrInvalidInput)
+	}
+	if len(spec.Requests) == 0 {
+		return nil, fmt.Errorf("batch has no requests: %w", domain.ErrInvalidInput)
+	}
+	if len(spec.Requests) > MaxRequestsPerJob {
+		return nil, fmt.Errorf("batch has %d requests, limit is %d: %w", len(spec.Requests), MaxRequestsPerJob, domain.ErrInvalidInput)
+	}
+
+	items := make([]domain.BatchItem, len(spec.Requests))
+	seen := make(map[string]bool, len(spec.Requests))
+	for i, req := range spec.Requests {
+		if req.CustomID == "" || seen[req.CustomID] {
+			return nil, fmt.Errorf("request %d: custom ID %q is empty or duplicated: %w", i+1, req.CustomID, domain.ErrInvalidInput)
+		}
+		if req.Request == nil || len(req.Request.Messages) == 0 {
+			return nil, fmt.Errorf("request %s has no messages: %w", req.CustomID, domain.ErrInvalidInput)
+		}
+		seen[req.CustomID] = true
+		items[i] = domain.BatchItem{CustomID: req.CustomID, Request: *req.Request, Status: domain.BatchItemPending}
+	}
+
+	now := time.Now()
+	job := &domain.BatchJob{
+		ID:        uuid.New().String(),
+		Name:      spec.Name,
+		Provider:  spec.Provider,
+		Mode:      domain.BatchModeLocal,
+		Status:    domain.BatchJobPending,
+		CreatedBy: spec.CreatedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if job.Name == "" {
+		job.Name = "batch-" + now.Format("20060102-150405")
+	}
+	if !spec.Local && s.batcher != nil && s.batcher.SupportsBatch(spec.Provider) {
+		job.Mode = domain.BatchModeProvider
+	}
+	for i := range items {
+		items[i].JobID = job.ID
+	}
+
+	if err := s.repo.CreateJob(ctx, job, items); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Batch job created",
+		"job_id", job.ID,
+		"name", job.Name,
+		"provider", job.Provider,
+		"mode", job.Mode,
+		"requests", len(items),
+	)
+
+	if err := s.start(ctx, job, items); err != nil {
+		return nil, err
+	}
+	return s.repo.GetJob(ctx, job.ID)
+}
+
+// Get returns a job with its current item counts.
+func (s *Service) Get(ctx context.Context, jobID string) (*domain.BatchJob, error) {
+	return s.repo.GetJob(ctx, jobID)
+}
+
+// List returns the most recent jobs, newest first.
+func (s *Service) List(ctx context.Context, limit int) ([]*domain.BatchJob, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	return s.repo.ListJobs(ctx, limit)
+}
+
+// Results returns a page of a job's items with whatever results are available,
+// so partial results can be read while the job is still running.
+func (s *Service) Results(ctx context.Context, jobID string, offset, limit int) ([]domain.BatchItem, error) {
+	if _, err := s.repo.GetJob(ctx, jobID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListItems(ctx, jobID, "", offset, limit)
+}
+
+// Cancel stops a job. Items that already finished keep their results; the
+// rest are marked cancelled. Provider batches finish cancelling on the next poll.
+func (s *Service) Cancel(ctx context.Context, jobID string) (*domain.BatchJob, error) {
+	job, err := s.repo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status.IsTerminal() {
+		return nil, fmt.Errorf("job %s is already %s: %w", jobID, job.Status, domain.ErrConflict)
+	}
+
+	if job.Mode == domain.BatchModeProvider && job.ProviderBatchID != "" && s.batcher != nil {
+		if err := s.batcher.CancelBatch(ctx, job.Provider, job.ProviderBatchID); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		_, err := s.markCancelling(ctx, jobID)
+		s.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return s.repo.GetJob(ctx, jobID)
+	}
+
+	s.mu.Lock()
+	job, err = s.markCancelling(ctx, jobID)
+	if err == nil {
+		if cancel, running := s.running[jobID]; running {
+			// The worker loop marks the job cancelled once in-flight requests return
+			cancel()
+		} else {
+			err = s.finishCancelled(ctx, job)
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetJob(ctx, jobID)
+}
+
+// markCancelling re-reads a job and marks it cancelling unless it has ended in
+// the meantime. Callers hold s.mu, which every status change that can race
+// with a cancel is made under.
+func (s *Service) markCancelling(ctx context.Context, jobID string) (*domain.BatchJob, error) {
+	job, err := s.repo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status.IsTerminal() {
+		return nil, fmt.Errorf("job %s is already %s: %w", jobID, job.Status, domain.ErrConflict)
+	}
+	job.Status = domain.BatchJobCancelling
+	if err := s.repo.UpdateJob(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Run resumes unfinished jobs and polls provider batches until ctx is done.
+func (s *Service) Run(ctx context.Context) {
+	s.resume(ctx)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Poll(ctx); err != nil {
+				slog.Warn("Batch poll failed", "error", err)
+			}
+		}
+	}
+}
+
+// Stop interrupts local jobs and waits for their workers to exit. Interrupted
+// jobs stay running in storage and are resumed by the next Run.
+func (s *Service) Stop() {
+	s.stop()
+	s.wg.Wait()
+}
+
+// Poll checks every active provider batch once and stores the results of
+// batches that have ended.
+func (s *Service) Poll(ctx context.Context) error {
+	jobs, err := s.repo.ListActiveJobs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Mode != domain.BatchModeProvider || job.ProviderBatchID == "" {
+			continue
+		}
+		if err := s.pollJob(ctx, job); err != nil {
+			slog.Warn("Failed to poll batch job",
+				"job_id", job.ID,
+				"provider_batch_id", job.ProviderBatchID,
+				"error", err,
+			)
+		}
+	}
+	return nil
+}
+
+// resume restarts local jobs and submits provider jobs that were interrupted
+// before submission.
+func (s *Service) resume(ctx context.Context) {
+	jobs, err := s.repo.ListActiveJobs(ctx)
+	if err != nil {
+		slog.Warn("Failed to load unfinished batch jobs", "error", err)
+		return
+	}
+	for _, job := range jobs {
+		if job.Mode == domain.BatchModeProvider && job.ProviderBatchID != "" {
+			continue // Picked up by Poll
+		}
+		if job.Status == domain.BatchJobCancelling {
+			if err := s.finishCancelled(ctx, job); err != nil {
+				slog.Warn("Failed to cancel batch job", "job_id", job.ID, "error", err)
+			}
+			continue
+		}
+
+		items, err := s.repo.ListItems(ctx, job.ID, domain.BatchItemPending, 0, 0)
+		if err != nil {
+			slog.Warn("Failed to load batch items", "job_id", job.ID, "error", err)
+			continue
+		}
+		slog.Info("Resuming batch job", "job_id", job.ID, "mode", job.Mode, "pending", len(items))
+		if err := s.start(ctx, job, items); err != nil {
+			slog.Warn("Failed to resume batch job", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+// start submits a provider job or launches the worker pool for a local one.
+func (s *Service) start(ctx context.Context, job *domain.BatchJob, items []domain.BatchItem) error {
+	if job.Mode == domain.BatchModeProvider {
+		if s.batcher == nil || !s.batcher.SupportsBatch(job.Provider) {
+			// Provider batching is no longer configured; fall back to local processing
+			job.Mode = domain.BatchModeLocal
+		} else {
+			return s.submitProvider(ctx, job, items)
+		}
+	}
+
+	job.Status = domain.BatchJobRunning
+	if err := s.repo.UpdateJob(ctx, job); err != nil {
+		return err
+	}
+
+	jobCtx, cancel := context.WithCancel(s.ctx)
+	s.mu.Lock()
+	s.running[job.ID] = cancel
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		s.runLocal(jobCtx, job, items)
+	}()
+	return nil
+}
+
+// submitProvider sends the job's items to the provider batch endpoint. A
+// rejected submission fails the job.
+func (s *Service) submitProvider(ctx context.Context, job *domain.BatchJob, items []domain.BatchItem) error {
+	requests := make([]domain.BatchRequest, len(items))
+	for i := range items {
+		req := &items[i].Request
+		if s.defaults != nil {
+			req = s.defaults.Merge(job.Provider, req)
+		}
+		requests[i] = domain.BatchRequest{CustomID: items[i].CustomID, Request: req}
+	}
+
+	batchID, err := s.batcher.SubmitBatch(ctx, job.Provider, requests)
+	if err != nil {
+		job.Status = domain.BatchJobFailed
+		job.Error = err.Error()
+		if updateErr := s.repo.UpdateJob(ctx, job); updateErr != nil {
+			return updateErr
+		}
+		return fmt.Errorf("failed to submit batch: %w", err)
+	}
+
+	job.ProviderBatchID = batchID
+	job.Status = domain.BatchJobRunning
+	slog.Info("Batch submitted to provider", "job_id", job.ID, "provider", job.Provider, "provider_batch_id", batchID)
+	return s.repo.UpdateJob(ctx, job)
+}
+
+// pollJob stores the results of a provider batch once it has ended.
+func (s *Service) pollJob(ctx context.Context, job *domain.BatchJob) error {
+	status, err := s.batcher.GetBatch(ctx, job.Provider, job.ProviderBatchID)
+	if err != nil {
+		return err
+	}
+	if !status.Done {
+		return nil
+	}
+
+	results, err := s.batcher.BatchResults(ctx, job.Provider, job.ProviderBatchID)
+	if err != nil {
+		return err
+	}
+
+	// Re-read the job so a cancel that raced with the poll is honoured
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, err = s.repo.GetJob(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	if job.Status.IsTerminal() {
+		return nil
+	}
+
+	pending, err := s.repo.ListItems(ctx, job.ID, domain.BatchItemPending, 0, 0)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*domain.BatchItem, len(pending))
+	for i := range pending {
+		byID[pending[i].CustomID] = &pending[i]
+	}
+
+	for _, result := range results {
+		item, ok := byID[result.CustomID]
+		if !ok {
+			continue // Unknown or already stored
+		}
+		item.Status = result.Status
+		item.Response = result.Response
+		item.Error = result.Error
+		if item.Response != nil && item.Request.ResponseFormat.IsJSON() {
+			item.Response.PromoteToolCall(item.Request.ResponseFormat.SchemaName())
+		}
+		if err := s.repo.SaveItemResult(ctx, item); err != nil {
+			return err
+		}
+		delete(byID, result.CustomID)
+	}
+
+	// Requests the provider never ran (cancelled or expired batches)
+	for _, item := range byID {
+		if job.Status == domain.BatchJobCancelling {
+			item.Status = domain.BatchItemCancelled
+		} else {
+			item.Status = domain.BatchItemFailed
+			item.Error = "no result returned by provider"
+		}
+		if err := s.repo.SaveItemResult(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	if job.Status == domain.BatchJobCancelling {
+		job.Status = domain.BatchJobCancelled
+	} else {
+		job.Status = domain.BatchJobCompleted
+	}
+	slog.Info("Batch job finished", "job_id", job.ID, "status", job.Status, "results", len(results))
+	return s.repo.UpdateJob(ctx, job)
+}
+
+// runLocal processes items through the worker pool, storing each result as it
+// arrives, then records how the job ended.
+func (s *Service) runLocal(ctx context.Context, job *domain.BatchJob, items []domain.BatchItem) {
+	var wg sync.WaitGroup
+dispatch:
+	for i := range items {
+		select {
+		case s.sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		wg.Add(1)
+		go func(item *domain.BatchItem) {
+			defer wg.Done()
+			defer func() { <-s.sem }()
+			s.runItem(ctx, job, item)
+		}(&items[i])
+	}
+	wg.Wait()
+
+	// The job leaves s.running in the same critical section as its final
+	// status is written, so Cancel sees either a running job or an ended one
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, job.ID)
+
+	// Job state is written even if ctx was cancelled
+	storeCtx := context.WithoutCancel(ctx)
+	current, err := s.repo.GetJob(storeCtx, job.ID)
+	if err != nil {
+		slog.Error("Failed to load batch job", "job_id", job.ID, "error", err)
+		return
+	}
+
+	switch {
+	case current.Status == domain.BatchJobCancelling:
+		if err := s.finishCancelled(storeCtx, current); err != nil {
+			slog.Error("Failed to cancel batch job", "job_id", job.ID, "error", err)
+		}
+	case ctx.Err() != nil:
+		// Shutting down; pending items are picked up again by resume
+		slog.Info("Batch job interrupted", "job_id", job.ID, "pending", current.Counts.Pending)
+	default:
+		current.Status = domain.BatchJobCompleted
+		if err := s.repo.UpdateJob(storeCtx, current); err != nil {
+			slog.Error("Failed to complete batch job", "job_id", job.ID, "error", err)
+			return
+		}
+		slog.Info("Batch job finished",
+			"job_id", job.ID,
+			"succeeded", current.Counts.Succeeded,
+			"failed", current.Counts.Failed,
+		)
+	}
+}
+
+// runItem completes a single request and stores its result. Requests
+// interrupted by cancellation stay pending.
+func (s *Service) runItem(ctx context.Context, job *domain.BatchJob, item *domain.BatchItem) {
+	resp, err := s.llmService.Complete(ctx, job.Provider, &item.Request)
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		item.Status = domain.BatchItemFailed
+		item.Error = err.Error()
+	} else {
+		item.Status = domain.BatchItemSucceeded
+		item.Response = resp
+	}
+	if err := s.repo.SaveItemResult(context.WithoutCancel(ctx), item); err != nil {
+		slog.Error("Failed to save batch item result", "job_id", job.ID, "custom_id", item.CustomID, "error", err)
+	}
+}
+
+// finishCancelled marks a job's pending items and the job itself cancelled.
+func (s *Service) finishCancelled(ctx context.Context, job *domain.BatchJob) error {
+	if err := s.repo.CancelPendingItems(ctx, job.ID); err != nil {
+		return err
+	}
+	job.Status = domain.BatchJobCancelled
+	slog.Info("Batch job cancelled", "job_id", job.ID)
+	return s.repo.UpdateJob(ctx, job)
+}
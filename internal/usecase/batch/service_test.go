@@ -0,0 +1,463 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nuimanbot/internal/domain"
+)
+
+// memRepository is an in-memory Repository.
+type memRepository struct {
+	mu    sync.Mutex
+	jobs  map[string]*domain.BatchJob
+	items map[string][]domain.BatchItem
+}
+
+func newMemRepository() *memRepository {
+	return &memRepository{jobs: make(map[string]*domain.BatchJob), items: make(map[string][]domain.BatchItem)}
+}
+
+func (r *memRepository) CreateJob(ctx context.Context, job *domain.BatchJob, items []domain.BatchItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *job
+	r.jobs[job.ID] = &copied
+	r.items[job.ID] = append([]domain.BatchItem(nil), items...)
+	return nil
+}
+
+func (r *memRepository) GetJob(ctx context.Context, jobID string) (*domain.BatchJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[jobID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return r.withCounts(job), nil
+}
+
+func (r *memRepository) ListJobs(ctx context.Context, limit int) ([]*domain.BatchJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []*domain.BatchJob
+	for _, job := range r.jobs {
+		jobs = append(jobs, r.withCounts(job))
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+func (r *memRepository) ListActiveJobs(ctx context.Context) ([]*domain.BatchJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []*domain.BatchJob
+	for _, job := range r.jobs {
+		if !job.Status.IsTerminal() {
+			jobs = append(jobs, r.withCounts(job))
+		}
+	}
+	return jobs, nil
+}
+
+func (r *memRepository) UpdateJob(ctx context.Context, job *domain.BatchJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.jobs[job.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	stored.Status = job.Status
+	stored.ProviderBatchID = job.ProviderBatchID
+	stored.Error = job.Error
+	stored.Mode = job.Mode
+	return nil
+}
+
+func (r *memRepository) ListItems(ctx context.Context, jobID string, status domain.BatchItemStatus, offset, limit int) ([]domain.BatchItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []domain.BatchItem
+	for _, item := range r.items[jobID] {
+		if status == "" || item.Status == status {
+			items = append(items, item)
+		}
+	}
+	if offset >= len(items) {
+		return nil, nil
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *memRepository) SaveItemResult(ctx context.Context, item *domain.BatchItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.items[item.JobID] {
+		if stored.CustomID == item.CustomID {
+			r.items[item.JobID][i] = *item
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *memRepository) CancelPendingItems(ctx context.Context, jobID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items[jobID] {
+		if r.items[jobID][i].Status == domain.BatchItemPending {
+			r.items[jobID][i].Status = domain.BatchItemCancelled
+		}
+	}
+	return nil
+}
+
+func (r *memRepository) withCounts(job *domain.BatchJob) *domain.BatchJob {
+	copied := *job
+	copied.Counts = domain.BatchItemCounts{}
+	for _, item := range r.items[job.ID] {
+		switch item.Status {
+		case domain.BatchItemPending:
+			copied.Counts.Pending++
+		case domain.BatchItemSucceeded:
+			copied.Counts.Succeeded++
+		case domain.BatchItemFailed:
+			copied.Counts.Failed++
+		case domain.BatchItemCancelled:
+			copied.Counts.Cancelled++
+		}
+	}
+	return &copied
+}
+
+// fakeLLM answers with the last user message upper-cased, failing on "fail".
+type fakeLLM struct {
+	block chan struct{} // If set, Complete waits on it or the context
+}
+
+func (f *fakeLLM) Complete(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	prompt := req.Messages[len(req.Messages)-1].Content
+	if prompt == "fail" {
+		return nil, errors.New("model error")
+	}
+	return &domain.LLMResponse{Content: strings.ToUpper(prompt), FinishReason: domain.FinishReasonStop}, nil
+}
+
+func (f *fakeLLM) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeLLM) ListModels(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
+	return nil, nil
+}
+
+// fakeBatcher is a provider batch endpoint that finishes when done is set.
+type fakeBatcher struct {
+	submitted []domain.BatchRequest
+	done      bool
+	results   []domain.BatchResult
+	cancelled bool
+}
+
+func (b *fakeBatcher) SupportsBatch(provider domain.LLMProvider) bool {
+	return provider == domain.LLMProviderAnthropic
+}
+
+func (b *fakeBatcher) SubmitBatch(ctx context.Context, provider domain.LLMProvider, requests []domain.BatchRequest) (string, error) {
+	b.submitted = requests
+	return "msgbatch_1", nil
+}
+
+func (b *fakeBatcher) GetBatch(ctx context.Context, provider domain.LLMProvider, batchID string) (*domain.LLMBatchStatus, error) {
+	return &domain.LLMBatchStatus{Done: b.done}, nil
+}
+
+func (b *fakeBatcher) BatchResults(ctx context.Context, provider domain.LLMProvider, batchID string) ([]domain.BatchResult, error) {
+	return b.results, nil
+}
+
+func (b *fakeBatcher) CancelBatch(ctx context.Context, provider domain.LLMProvider, batchID string) error {
+	b.cancelled = true
+	return nil
+}
+
+func requests(prompts ...string) []domain.BatchRequest {
+	reqs := make([]domain.BatchRequest, len(prompts))
+	for i, prompt := range prompts {
+		reqs[i] = domain.BatchRequest{
+			CustomID: "item-" + string(rune('a'+i)),
+			Request:  &domain.LLMRequest{Messages: []domain.Message{{Role: "user", Content: prompt}}},
+		}
+	}
+	return reqs
+}
+
+// waitForStatus polls until the job reaches status or the test times out.
+func waitForStatus(t *testing.T, svc *Service, jobID string, status domain.BatchJobStatus) *domain.BatchJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, err := svc.Get(context.Background(), jobID)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Job status = %s, want %s", job.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSubmit_LocalJob(t *testing.T) {
+	svc := NewService(newMemRepository(), &fakeLLM{}, 2)
+	defer svc.Stop()
+	ctx := context.Background()
+
+	job, err := svc.Submit(ctx, JobSpec{Provider: domain.LLMProviderOllama, Requests: requests("one", "fail", "three")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if job.Mode != domain.BatchModeLocal {
+		t.Errorf("Mode = %s, want local", job.Mode)
+	}
+
+	job = waitForStatus(t, svc, job.ID, domain.BatchJobCompleted)
+	if job.Counts.Succeeded != 2 || job.Counts.Failed != 1 {
+		t.Errorf("Counts = %+v, want 2 succeeded and 1 failed", job.Counts)
+	}
+
+	items, err := svc.Results(ctx, job.ID, 0, 0)
+	if err != nil {
+		t.Fatalf("Results failed: %v", err)
+	}
+	if items[0].Response == nil || items[0].Response.Content != "ONE" {
+		t.Errorf("First result = %+v, want ONE", items[0].Response)
+	}
+	if items[1].Error != "model error" {
+		t.Errorf("Second result error = %q, want model error", items[1].Error)
+	}
+}
+
+func TestSubmit_Validation(t *testing.T) {
+	svc := NewService(newMemRepository(), &fakeLLM{}, 1)
+	defer svc.Stop()
+
+	duplicated := requests("a", "b")
+	duplicated[1].CustomID = duplicated[0].CustomID
+
+	tests := []struct {
+		name string
+		spec JobSpec
+	}{
+		{"no provider", JobSpec{Requests: requests("a")}},
+		{"no requests", JobSpec{Provider: domain.LLMProviderOllama}},
+		{"duplicate custom ID", JobSpec{Provider: domain.LLMProviderOllama, Requests: duplicated}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Submit(context.Background(), tt.spec); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("Submit error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestCancel_LocalJob(t *testing.T) {
+	llm := &fakeLLM{block: make(chan struct{})}
+	svc := NewService(newMemRepository(), llm, 1)
+	defer svc.Stop()
+	ctx := context.Background()
+
+	job, err := svc.Submit(ctx, JobSpec{Provider: domain.LLMProviderOllama, Requests: requests("a", "b", "c")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	if _, err := svc.Cancel(ctx, job.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	job = waitForStatus(t, svc, job.ID, domain.BatchJobCancelled)
+	if job.Counts.Cancelled != 3 {
+		t.Errorf("Counts = %+v, want 3 cancelled", job.Counts)
+	}
+
+	if _, err := svc.Cancel(ctx, job.ID); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Cancelling a finished job: error = %v, want ErrConflict", err)
+	}
+}
+
+// finishingRepository completes a job right after Cancel first reads it, as a
+// worker finishing concurrently would.
+type finishingRepository struct {
+	*memRepository
+	reads int
+}
+
+func (r *finishingRepository) GetJob(ctx context.Context, jobID string) (*domain.BatchJob, error) {
+	job, err := r.memRepository.GetJob(ctx, jobID)
+	r.reads++
+	if err == nil && r.reads == 1 {
+		finished := *job
+		finished.Status = domain.BatchJobCompleted
+		_ = r.memRepository.UpdateJob(ctx, &finished)
+	}
+	return job, err
+}
+
+func TestCancel_JobFinishedConcurrently(t *testing.T) {
+	repo := &finishingRepository{memRepository: newMemRepository()}
+	svc := NewService(repo, &fakeLLM{}, 1)
+	defer svc.Stop()
+	ctx := context.Background()
+
+	job := &domain.BatchJob{ID: "job-1", Provider: domain.LLMProviderOllama, Mode: domain.BatchModeLocal, Status: domain.BatchJobRunning}
+	if err := repo.CreateJob(ctx, job, nil); err != nil {
+		t.Fatalf("CreateJob failed: %v", err)
+	}
+
+	if _, err := svc.Cancel(ctx, job.ID); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Cancel error = %v, want ErrConflict", err)
+	}
+	got, _ := repo.memRepository.GetJob(ctx, job.ID)
+	if got.Status != domain.BatchJobCompleted {
+		t.Errorf("Status = %s, want the job to stay completed", got.Status)
+	}
+}
+
+func TestSubmit_ProviderJob(t *testing.T) {
+	batcher := &fakeBatcher{}
+	svc := NewService(newMemRepository(), &fakeLLM{}, 1)
+	svc.SetBatchProvider(batcher)
+	defer svc.Stop()
+	ctx := context.Background()
+
+	job, err := svc.Submit(ctx, JobSpec{Provider: domain.LLMProviderAnthropic, Requests: requests("a", "b", "c")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if job.Mode != domain.BatchModeProvider || job.ProviderBatchID != "msgbatch_1" || job.Status != domain.BatchJobRunning {
+		t.Fatalf("Unexpected job after submit: %+v", job)
+	}
+	if len(batcher.submitted) != 3 {
+		t.Errorf("Submitted %d requests, want 3", len(batcher.submitted))
+	}
+
+	// Not finished yet
+	if err := svc.Poll(ctx); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if job, _ = svc.Get(ctx, job.ID); job.Status != domain.BatchJobRunning {
+		t.Errorf("Status = %s before the batch ended, want running", job.Status)
+	}
+
+	batcher.done = true
+	batcher.results = []domain.BatchResult{
+		{CustomID: "item-a", Status: domain.BatchItemSucceeded, Response: &domain.LLMResponse{Content: "A"}},
+		{CustomID: "item-b", Status: domain.BatchItemFailed, Error: "overloaded"},
+	}
+	if err := svc.Poll(ctx); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+
+	job, _ = svc.Get(ctx, job.ID)
+	if job.Status != domain.BatchJobCompleted {
+		t.Errorf("Status = %s, want completed", job.Status)
+	}
+	// item-c had no result and is failed
+	if job.Counts.Succeeded != 1 || job.Counts.Failed != 2 {
+		t.Errorf("Counts = %+v, want 1 succeeded and 2 failed", job.Counts)
+	}
+}
+
+func TestSubmit_LocalFlagBypassesProvider(t *testing.T) {
+	batcher := &fakeBatcher{}
+	svc := NewService(newMemRepository(), &fakeLLM{}, 1)
+	svc.SetBatchProvider(batcher)
+	defer svc.Stop()
+
+	job, err := svc.Submit(context.Background(), JobSpec{Provider: domain.LLMProviderAnthropic, Requests: requests("a"), Local: true})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if job.Mode != domain.BatchModeLocal || batcher.submitted != nil {
+		t.Errorf("Expected local job without provider submission, got mode %s", job.Mode)
+	}
+	waitForStatus(t, svc, job.ID, domain.BatchJobCompleted)
+}
+
+func TestCancel_ProviderJob(t *testing.T) {
+	batcher := &fakeBatcher{}
+	svc := NewService(newMemRepository(), &fakeLLM{}, 1)
+	svc.SetBatchProvider(batcher)
+	defer svc.Stop()
+	ctx := context.Background()
+
+	job, err := svc.Submit(ctx, JobSpec{Provider: domain.LLMProviderAnthropic, Requests: requests("a", "b")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	job, err = svc.Cancel(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if !batcher.cancelled || job.Status != domain.BatchJobCancelling {
+		t.Fatalf("Expected provider cancellation pending, got %s", job.Status)
+	}
+
+	batcher.done = true
+	batcher.results = []domain.BatchResult{{CustomID: "item-a", Status: domain.BatchItemSucceeded, Response: &domain.LLMResponse{Content: "A"}}}
+	if err := svc.Poll(ctx); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+
+	job, _ = svc.Get(ctx, job.ID)
+	if job.Status != domain.BatchJobCancelled || job.Counts.Succeeded != 1 || job.Counts.Cancelled != 1 {
+		t.Errorf("Unexpected job after cancellation: status %s, counts %+v", job.Status, job.Counts)
+	}
+}
+
+func TestRun_ResumesInterruptedLocalJob(t *testing.T) {
+	repo := newMemRepository()
+	job := &domain.BatchJob{ID: "job1", Provider: domain.LLMProviderOllama, Mode: domain.BatchModeLocal, Status: domain.BatchJobRunning}
+	items := []domain.BatchItem{
+		{JobID: "job1", CustomID: "done", Status: domain.BatchItemSucceeded, Response: &domain.LLMResponse{Content: "kept"}},
+		{JobID: "job1", CustomID: "todo", Status: domain.BatchItemPending, Request: domain.LLMRequest{Messages: []domain.Message{{Role: "user", Content: "resume"}}}},
+	}
+	if err := repo.CreateJob(context.Background(), job, items); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewService(repo, &fakeLLM{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer svc.Stop()
+	go svc.Run(ctx)
+
+	got := waitForStatus(t, svc, "job1", domain.BatchJobCompleted)
+	if got.Counts.Succeeded != 2 {
+		t.Errorf("Counts = %+v, want 2 succeeded", got.Counts)
+	}
+}
@@ -6,20 +6,16 @@ import (
 	"nuimanbot/internal/domain"
 )
 
-// Provider token limits (maximum context window sizes)
-const (
-	AnthropicTokenLimit = 200000 // Claude 3 Opus/Sonnet: 200k tokens
-	OpenAITokenLimit    = 128000 // GPT-4 Turbo: 128k tokens
-	OllamaTokenLimit    = 32000  // Default for local models: 32k tokens
-	ReservedTokens      = 2000   // Reserve tokens for response generation
-)
+// ReservedTokens are held back from the context window for response generation.
+const ReservedTokens = 2000
 
 // BuildContextWindow constructs a context window from conversation history
-// that fits within the provider's token limit.
+// that fits within the model's token limit. Models missing from the catalog
+// use their provider's default context window.
 // Returns messages (newest to oldest until limit) and total token count.
-func (s *Service) BuildContextWindow(ctx context.Context, conversationID string, provider domain.LLMProvider, maxTokens int) ([]domain.Message, int) {
-	// Get provider-specific token limit if maxTokens is 0 or exceeds limit
-	providerLimit := getProviderTokenLimit(provider)
+func (s *Service) BuildContextWindow(ctx context.Context, conversationID string, provider domain.LLMProvider, model string, maxTokens int) ([]domain.Message, int) {
+	// Get the model's context window if maxTokens is 0 or exceeds it
+	providerLimit := s.catalog.Lookup(provider, model).ContextWindow
 	if maxTokens == 0 || maxTokens > providerLimit {
 		maxTokens = providerLimit
 	}
@@ -63,17 +59,3 @@ func (s *Service) BuildContextWindow(ctx context.Context, conversationID string,
 
 	return messages, totalTokens
 }
-
-// getProviderTokenLimit returns the maximum context window size for the given provider.
-func getProviderTokenLimit(provider domain.LLMProvider) int {
-	switch provider {
-	case domain.LLMProviderAnthropic:
-		return AnthropicTokenLimit
-	case domain.LLMProviderOpenAI:
-		return OpenAITokenLimit
-	case domain.LLMProviderOllama:
-		return OllamaTokenLimit
-	default:
-		return OllamaTokenLimit // Default to smallest limit for safety
-	}
-}
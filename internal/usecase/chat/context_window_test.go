@@ -22,7 +22,7 @@ func TestBuildContextWindow_FitsInWindow(t *testing.T) {
 
 	service := createTestService(&mockLLMService{}, memoryRepo, &mockToolExecutionService{}, &mockSecurityService{})
 
-	messages, totalTokens := service.BuildContextWindow(context.Background(), "conv-123", domain.LLMProviderAnthropic, 1000)
+	messages, totalTokens := service.BuildContextWindow(context.Background(), "conv-123", domain.LLMProviderAnthropic, "", 1000)
 
 	if len(messages) != 3 {
 		t.Errorf("Expected 3 messages in context, got %d", len(messages))
@@ -51,7 +51,7 @@ func TestBuildContextWindow_ExceedsLimit(t *testing.T) {
 	service := createTestService(&mockLLMService{}, memoryRepo, &mockToolExecutionService{}, &mockSecurityService{})
 
 	// Limit to 400 tokens (should drop oldest messages)
-	messages, totalTokens := service.BuildContextWindow(context.Background(), "conv-123", domain.LLMProviderAnthropic, 400)
+	messages, totalTokens := service.BuildContextWindow(context.Background(), "conv-123", domain.LLMProviderAnthropic, "", 400)
 
 	// Should include only recent messages that fit
 	if len(messages) > 3 {
@@ -92,7 +92,37 @@ func TestBuildContextWindow_ProviderLimits(t *testing.T) {
 				},
 			}, &mockToolExecutionService{}, &mockSecurityService{})
 
-			service.BuildContextWindow(context.Background(), "conv-123", tt.provider, tt.expectedLimit)
+			service.BuildContextWindow(context.Background(), "conv-123", tt.provider, "", tt.expectedLimit)
+		})
+	}
+}
+
+// TestBuildContextWindow_ModelLimits tests that the selected model's window is
+// used, with the provider default for models the catalog doesn't know
+func TestBuildContextWindow_ModelLimits(t *testing.T) {
+	tests := []struct {
+		model         string
+		expectedLimit int
+	}{
+		{"gpt-4", 8192},
+		{"gpt-4.1", 1047576},
+		{"gpt-unreleased", 128000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			var got int
+			service := createTestService(&mockLLMService{}, &mockMemoryRepository{
+				getRecentMessagesFunc: func(ctx context.Context, convID string, maxTokens int) ([]domain.StoredMessage, error) {
+					got = maxTokens
+					return []domain.StoredMessage{}, nil
+				},
+			}, &mockToolExecutionService{}, &mockSecurityService{})
+
+			service.BuildContextWindow(context.Background(), "conv-123", domain.LLMProviderOpenAI, tt.model, 0)
+			if got != tt.expectedLimit-ReservedTokens {
+				t.Errorf("Expected maxTokens=%d (limit-reserve), got %d", tt.expectedLimit-ReservedTokens, got)
+			}
 		})
 	}
 }
@@ -107,7 +137,7 @@ func TestBuildContextWindow_EmptyConversation(t *testing.T) {
 
 	service := createTestService(&mockLLMService{}, memoryRepo, &mockToolExecutionService{}, &mockSecurityService{})
 
-	messages, totalTokens := service.BuildContextWindow(context.Background(), "conv-123", domain.LLMProviderAnthropic, 1000)
+	messages, totalTokens := service.BuildContextWindow(context.Background(), "conv-123", domain.LLMProviderAnthropic, "", 1000)
 
 	if len(messages) != 0 {
 		t.Errorf("Expected 0 messages for empty conversation, got %d", len(messages))
@@ -130,7 +160,7 @@ func TestBuildContextWindow_SingleMessage(t *testing.T) {
 
 	service := createTestService(&mockLLMService{}, memoryRepo, &mockToolExecutionService{}, &mockSecurityService{})
 
-	messages, totalTokens := service.BuildContextWindow(context.Background(), "conv-123", domain.LLMProviderAnthropic, 1000)
+	messages, totalTokens := service.BuildContextWindow(context.Background(), "conv-123", domain.LLMProviderAnthropic, "", 1000)
 
 	if len(messages) != 1 {
 		t.Errorf("Expected 1 message, got %d", len(messages))
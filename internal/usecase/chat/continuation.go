@@ -0,0 +1,107 @@
+package chat
+
+import (
+	"context"
+	"strings"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/requestid"
+)
+
+const (
+	// DefaultMaxContinuations is how many follow-up requests are made for a
+	// response cut off by the output token limit.
+	DefaultMaxContinuations = 3
+
+	// continuationPrompt asks the model to resume a truncated answer.
+	continuationPrompt = "Your previous response was cut off. Continue exactly where it stopped, " +
+		"without repeating any text or adding any preamble."
+
+	// maxStitchOverlap bounds the overlap search between a truncated part and its continuation.
+	maxStitchOverlap = 200
+
+	// minStitchOverlap avoids trimming short coincidental matches (e.g. a single space).
+	minStitchOverlap = 8
+)
+
+// SetMaxContinuations sets how many continuation requests are made for a
+// truncated response. Zero disables continuation.
+func (s *Service) SetMaxContinuations(n int) {
+	s.maxContinuations = max(n, 0)
+}
+
+// continueTruncated completes a response that stopped at the output token limit
+// by asking the model to continue, stitching the parts together. Tool-calling
+// responses are returned as is. If a continuation request fails, the parts
+// collected so far are returned (still marked truncated).
+func (s *Service) continueTruncated(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest, resp *domain.LLMResponse, info domain.ModelInfo) *domain.LLMResponse {
+	if !resp.FinishReason.Truncated() || len(resp.ToolCalls) > 0 || s.maxContinuations == 0 {
+		return resp
+	}
+
+	logger := requestid.Logger(ctx)
+	combined := *resp
+
+	for i := 0; i < s.maxContinuations && combined.FinishReason.Truncated(); i++ {
+		contReq := *req
+		contReq.Messages = make([]domain.Message, 0, len(req.Messages)+2)
+		contReq.Messages = append(contReq.Messages, req.Messages...)
+		contReq.Messages = append(contReq.Messages,
+			domain.Message{Role: "assistant", Content: combined.Content},
+			domain.Message{Role: "user", Content: continuationPrompt},
+		)
+		contReq.Cache = historyCachePolicy(len(req.Messages))
+		if len(contReq.Tools) > 0 {
+			// Finish the answer rather than starting a tool loop
+			contReq.ToolChoice = &domain.ToolChoice{Mode: domain.ToolChoiceNone}
+		}
+
+		next, err := s.llmService.Complete(ctx, provider, &contReq)
+		if err != nil {
+			logger.Warn("Continuation of truncated response failed",
+				"continuation", i+1,
+				"error", err,
+			)
+			break
+		}
+		recordCost(provider, info, next.Usage)
+
+		combined.Content = stitch(combined.Content, next.Content)
+		combined.FinishReason = next.FinishReason
+		combined.Reasoning = append(combined.Reasoning, next.Reasoning...)
+		combined.Usage = addUsage(combined.Usage, next.Usage)
+
+		logger.Info("Continued truncated response",
+			"continuation", i+1,
+			"finish_reason", next.FinishReason,
+		)
+	}
+
+	if combined.FinishReason.Truncated() {
+		logger.Warn("Response still truncated after continuations", "max_continuations", s.maxContinuations)
+	}
+	return &combined
+}
+
+// stitch joins a truncated part with its continuation, dropping any text the
+// model repeated from the end of the previous part.
+func stitch(prev, next string) string {
+	limit := min(len(prev), len(next), maxStitchOverlap)
+	for n := limit; n >= minStitchOverlap; n-- {
+		if strings.HasSuffix(prev, next[:n]) {
+			return prev + next[n:]
+		}
+	}
+	return prev + next
+}
+
+// addUsage sums token usage across requests.
+func addUsage(a, b domain.TokenUsage) domain.TokenUsage {
+	return domain.TokenUsage{
+		PromptTokens:        a.PromptTokens + b.PromptTokens,
+		CompletionTokens:    a.CompletionTokens + b.CompletionTokens,
+		TotalTokens:         a.TotalTokens + b.TotalTokens,
+		CacheCreationTokens: a.CacheCreationTokens + b.CacheCreationTokens,
+		CacheReadTokens:     a.CacheReadTokens + b.CacheReadTokens,
+	}
+}
@@ -0,0 +1,156 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"nuimanbot/internal/domain"
+)
+
+func TestStitch(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+		want string
+	}{
+		{"no overlap", "func main() {\n", "\tfmt.Println()\n}", "func main() {\n\tfmt.Println()\n}"},
+		{"repeated tail", "The quick brown fox jumps", "brown fox jumps over the dog", "The quick brown fox jumps over the dog"},
+		{"short coincidental match kept", "end with a", "a new line", "end with aa new line"},
+		{"empty continuation", "partial", "", "partial"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stitch(tt.prev, tt.next); got != tt.want {
+				t.Errorf("stitch(%q, %q) = %q, want %q", tt.prev, tt.next, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessMessage_ContinuesTruncatedResponse(t *testing.T) {
+	var requests []*domain.LLMRequest
+	parts := []*domain.LLMResponse{
+		{Content: "part one, ", FinishReason: domain.FinishReasonLength, Usage: domain.TokenUsage{CompletionTokens: 10}},
+		{Content: "part two, ", FinishReason: domain.FinishReasonLength, Usage: domain.TokenUsage{CompletionTokens: 10}},
+		{Content: "part three.", FinishReason: domain.FinishReasonStop, Usage: domain.TokenUsage{CompletionTokens: 5}},
+	}
+	llmService := &mockLLMService{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			requests = append(requests, req)
+			return parts[len(requests)-1], nil
+		},
+	}
+	toolService := &mockToolExecutionService{
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
+			return []domain.Tool{&mockSkill{name: "calculator"}}, nil
+		},
+	}
+
+	service := createTestService(llmService, &mockMemoryRepository{}, toolService, &mockSecurityService{})
+
+	out, err := service.ProcessMessage(context.Background(), &domain.IncomingMessage{Platform: domain.PlatformCLI, PlatformUID: "u1", Text: "write a report"})
+	if err != nil {
+		t.Fatalf("ProcessMessage failed: %v", err)
+	}
+
+	if out.Content != "part one, part two, part three." {
+		t.Errorf("Expected stitched content, got %q", out.Content)
+	}
+	if _, truncated := out.Metadata["truncated"]; truncated {
+		t.Error("Expected completed response not to be marked truncated")
+	}
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 LLM calls, got %d", len(requests))
+	}
+
+	last := requests[2]
+	n := len(last.Messages)
+	if last.Messages[n-2].Role != "assistant" || last.Messages[n-2].Content != "part one, part two, " {
+		t.Errorf("Expected partial answer as the assistant turn, got %+v", last.Messages[n-2])
+	}
+	if last.Messages[n-1].Content != continuationPrompt {
+		t.Errorf("Expected continuation prompt as the last message, got %q", last.Messages[n-1].Content)
+	}
+	if last.ToolChoice == nil || last.ToolChoice.Mode != domain.ToolChoiceNone {
+		t.Errorf("Expected tool_choice none on continuation, got %+v", last.ToolChoice)
+	}
+}
+
+func TestProcessMessage_ContinuationLimit(t *testing.T) {
+	calls := 0
+	llmService := &mockLLMService{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			calls++
+			return &domain.LLMResponse{Content: "more ", FinishReason: domain.FinishReasonLength}, nil
+		},
+	}
+
+	service := createTestService(llmService, &mockMemoryRepository{}, &mockToolExecutionService{}, &mockSecurityService{})
+	service.SetMaxContinuations(2)
+
+	out, err := service.ProcessMessage(context.Background(), &domain.IncomingMessage{Platform: domain.PlatformCLI, PlatformUID: "u1", Text: "go on forever"})
+	if err != nil {
+		t.Fatalf("ProcessMessage failed: %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 1 request plus 2 continuations, got %d calls", calls)
+	}
+	if out.Content != "more more more " {
+		t.Errorf("Expected stitched content, got %q", out.Content)
+	}
+	if out.Metadata["truncated"] != true {
+		t.Error("Expected response still truncated after the limit to be marked truncated")
+	}
+}
+
+func TestProcessMessage_ContextWindowNotContinued(t *testing.T) {
+	calls := 0
+	llmService := &mockLLMService{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			calls++
+			return &domain.LLMResponse{Content: "cut off", FinishReason: domain.FinishReasonContextWindow}, nil
+		},
+	}
+
+	service := createTestService(llmService, &mockMemoryRepository{}, &mockToolExecutionService{}, &mockSecurityService{})
+
+	out, err := service.ProcessMessage(context.Background(), &domain.IncomingMessage{Platform: domain.PlatformCLI, PlatformUID: "u1", Text: "hi"})
+	if err != nil {
+		t.Fatalf("ProcessMessage failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no continuation once the context window is full, got %d calls", calls)
+	}
+	if out.Content != "cut off" {
+		t.Errorf("Expected the partial answer, got %q", out.Content)
+	}
+}
+
+func TestProcessMessage_ContinuationFailureKeepsPartial(t *testing.T) {
+	calls := 0
+	llmService := &mockLLMService{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			calls++
+			if calls > 1 {
+				return nil, errors.New("provider unavailable")
+			}
+			return &domain.LLMResponse{Content: "partial answer", FinishReason: domain.FinishReasonLength}, nil
+		},
+	}
+
+	service := createTestService(llmService, &mockMemoryRepository{}, &mockToolExecutionService{}, &mockSecurityService{})
+
+	out, err := service.ProcessMessage(context.Background(), &domain.IncomingMessage{Platform: domain.PlatformCLI, PlatformUID: "u1", Text: "hi"})
+	if err != nil {
+		t.Fatalf("ProcessMessage failed: %v", err)
+	}
+	if out.Content != "partial answer" {
+		t.Errorf("Expected partial answer, got %q", out.Content)
+	}
+	if calls != 2 {
+		t.Errorf("Expected continuation to stop after the first failure, got %d calls", calls)
+	}
+}
@@ -0,0 +1,56 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/metrics"
+)
+
+// applyModelCapabilities adapts a request to what the model supports: tools are
+// withheld from models without tool calling and MaxTokens is capped at the
+// model's output limit.
+func applyModelCapabilities(req *domain.LLMRequest, info domain.ModelInfo) {
+	if !info.SupportsTools {
+		req.Tools = nil
+	}
+	if info.MaxOutputTokens > 0 && req.MaxTokens > info.MaxOutputTokens {
+		req.MaxTokens = info.MaxOutputTokens
+	}
+}
+
+// recordCost adds the USD cost of a completion to the cost metric, using the catalog's pricing.
+func recordCost(provider domain.LLMProvider, info domain.ModelInfo, usage domain.TokenUsage) {
+	if cost := info.Pricing.Cost(usage); cost > 0 {
+		metrics.LLMCostUSD.WithLabelValues(string(provider), info.ID).Add(cost)
+	}
+}
+
+// openStream starts a streaming completion, or emulates one with a single
+// Complete call for models that cannot stream.
+func (s *Service) openStream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest, info domain.ModelInfo) (<-chan domain.StreamChunk, error) {
+	if info.SupportsStream {
+		return s.llmService.Stream(ctx, provider, req)
+	}
+
+	resp, err := s.llmService.Complete(ctx, provider, req)
+	if err != nil {
+		return nil, fmt.Errorf("LLM completion failed: %w", err)
+	}
+	recordCost(provider, info, resp.Usage)
+
+	ch := make(chan domain.StreamChunk, len(resp.ToolCalls)+3)
+	if text := resp.ReasoningText(); text != "" {
+		ch <- domain.StreamChunk{Kind: domain.StreamChunkThinking, Delta: text}
+	}
+	if resp.Content != "" {
+		ch <- domain.StreamChunk{Delta: resp.Content}
+	}
+	for i := range resp.ToolCalls {
+		ch <- domain.StreamChunk{ToolCall: &resp.ToolCalls[i]}
+	}
+	ch <- domain.StreamChunk{Done: true}
+	close(ch)
+	return ch, nil
+}
@@ -0,0 +1,80 @@
+package chat
+
+import (
+	"context"
+	"testing"
+
+	"nuimanbot/internal/domain"
+)
+
+// stubCatalog returns the same capabilities for every model.
+type stubCatalog struct {
+	info domain.ModelInfo
+}
+
+func (c *stubCatalog) Lookup(provider domain.LLMProvider, model string) domain.ModelInfo {
+	info := c.info
+	info.ID = model
+	return info
+}
+
+func TestProcessMessage_ModelCapabilities(t *testing.T) {
+	var gotReq *domain.LLMRequest
+	llmService := &mockLLMService{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			gotReq = req
+			return &domain.LLMResponse{Content: "ok"}, nil
+		},
+	}
+	toolService := &mockToolExecutionService{
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
+			return []domain.Tool{&mockSkill{name: "calculator"}}, nil
+		},
+	}
+
+	service := createTestService(llmService, &mockMemoryRepository{}, toolService, &mockSecurityService{})
+	service.SetModelCatalog(&stubCatalog{info: domain.ModelInfo{MaxOutputTokens: 512, SupportsStream: true}})
+
+	_, err := service.ProcessMessage(context.Background(), &domain.IncomingMessage{Platform: domain.PlatformCLI, PlatformUID: "u1", Text: "hi"})
+	if err != nil {
+		t.Fatalf("ProcessMessage failed: %v", err)
+	}
+
+	if len(gotReq.Tools) != 0 {
+		t.Errorf("Expected tools withheld from a model without tool support, got %d", len(gotReq.Tools))
+	}
+	if gotReq.MaxTokens != 512 {
+		t.Errorf("Expected MaxTokens capped at 512, got %d", gotReq.MaxTokens)
+	}
+}
+
+func TestProcessMessageStream_NonStreamingModel(t *testing.T) {
+	llmService := &mockLLMService{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			return &domain.LLMResponse{Content: "whole answer"}, nil
+		},
+		streamFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+			t.Error("Stream should not be called for a model without streaming support")
+			return nil, nil
+		},
+	}
+
+	service := createTestService(llmService, &mockMemoryRepository{}, &mockToolExecutionService{}, &mockSecurityService{})
+	service.SetModelCatalog(&stubCatalog{info: domain.ModelInfo{SupportsTools: true}})
+
+	ch, err := service.ProcessMessageStream(context.Background(), &domain.IncomingMessage{Platform: domain.PlatformCLI, PlatformUID: "u1", Text: "hi"})
+	if err != nil {
+		t.Fatalf("ProcessMessageStream failed: %v", err)
+	}
+
+	var content string
+	for chunk := range ch {
+		if chunk.Error != nil {
+			t.Fatalf("Stream error: %v", chunk.Error)
+		}
+		content += chunk.Delta
+	}
+	if content != "whole answer" {
+		t.Errorf("Expected emulated stream content, got %q", content)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"nuimanbot/internal/domain"
 	"nuimanbot/internal/infrastructure/requestid"
+	"nuimanbot/internal/usecase/llm"
 )
 
 // LLMService defines the interface for LLM interactions required by the ChatService.
@@ -30,8 +31,10 @@ type MemoryRepository interface {
 // ToolExecutionService defines the interface for tool execution required by the ChatService.
 // This will be defined by the Tools Core Agent.
 type ToolExecutionService interface {
-	Execute(ctx context.Context, toolName string, params map[string]any) (*domain.ExecutionResult, error)
-	ListTools(ctx context.Context, userID string) ([]domain.Tool, error)
+	// ExecuteWithUser runs a tool after enforcing the user's role, tool whitelist and rate limits.
+	ExecuteWithUser(ctx context.Context, user *domain.User, toolName string, params map[string]any) (*domain.ExecutionResult, error)
+	// ListTools returns only the tools the user is permitted to execute.
+	ListTools(ctx context.Context, user *domain.User) ([]domain.Tool, error)
 	// Other methods for skill management (e.g., registration, permission checks)
 }
 
@@ -49,13 +52,23 @@ type LLMCache interface {
 	Set(ctx context.Context, prompt string, response *domain.LLMResponse)
 }
 
+// ModelCatalog answers model capability questions (context window, output
+// limit, tool/vision/streaming support, pricing) for the ChatService.
+type ModelCatalog interface {
+	Lookup(provider domain.LLMProvider, model string) domain.ModelInfo
+}
+
 // Service implements the ChatService use case.
 type Service struct {
-	llmService      LLMService
-	memoryRepo      MemoryRepository
-	toolExecService ToolExecutionService // Currently PENDING (will be mocked or basic for now)
-	securityService SecurityService
-	cache           LLMCache // Optional cache for LLM responses
+	llmService       LLMService
+	memoryRepo       MemoryRepository
+	toolExecService  ToolExecutionService // Currently PENDING (will be mocked or basic for now)
+	securityService  SecurityService
+	cache            LLMCache                     // Optional cache for LLM responses
+	prefsRepo        domain.PreferencesRepository // Optional user preferences (e.g., reasoning visibility)
+	catalog          ModelCatalog                 // Model capabilities; defaults to the bundled catalog
+	maxContinuations int                          // Follow-up requests for truncated responses
+	userResolver     UserResolver                 // Optional; unresolved identities are guests
 	// config            *config.ChatConfig // If ChatService needs its own config
 }
 
@@ -67,10 +80,12 @@ func NewService(
 	securityService SecurityService,
 ) *Service {
 	return &Service{
-		llmService:      llmService,
-		memoryRepo:      memoryRepo,
-		toolExecService: toolExecService,
-		securityService: securityService,
+		llmService:       llmService,
+		memoryRepo:       memoryRepo,
+		toolExecService:  toolExecService,
+		securityService:  securityService,
+		catalog:          llm.NewCatalog(),
+		maxContinuations: DefaultMaxContinuations,
 	}
 }
 
@@ -79,6 +94,30 @@ func (s *Service) SetCache(cache LLMCache) {
 	s.cache = cache
 }
 
+// SetModelCatalog replaces the bundled model catalog, e.g. with one that
+// includes configured overrides and models discovered from providers.
+func (s *Service) SetModelCatalog(catalog ModelCatalog) {
+	s.catalog = catalog
+}
+
+// SetPreferencesRepository sets the user preferences repository (optional).
+// Without it, default preferences apply and reasoning is never shown.
+func (s *Service) SetPreferencesRepository(repo domain.PreferencesRepository) {
+	s.prefsRepo = repo
+}
+
+// showReasoning reports whether the user asked to see model reasoning.
+func (s *Service) showReasoning(ctx context.Context, userID string) bool {
+	if s.prefsRepo == nil {
+		return false
+	}
+	prefs, err := s.prefsRepo.Get(ctx, userID)
+	if err != nil {
+		return false
+	}
+	return prefs.ShowReasoning
+}
+
 // getConversationID generates a conversation ID based on platform and user
 func getConversationID(platform domain.Platform, platformUID string) string {
 	return string(platform) + ":" + platformUID
@@ -113,9 +152,9 @@ func (s *Service) ProcessMessage(ctx context.Context, incomingMsg *domain.Incomi
 		return domain.OutgoingMessage{}, fmt.Errorf("failed to get recent messages: %w", err)
 	}
 
-	// 3. Get available skills and convert to tools
-	// Note: Using PlatformUID as user identifier for skill permissions
-	skills, err := s.toolExecService.ListTools(ctx, incomingMsg.PlatformUID)
+	// 3. Get the skills this user may run and convert to tools
+	user := s.resolveUser(ctx, incomingMsg)
+	skills, err := s.toolExecService.ListTools(ctx, user)
 	if err != nil {
 		return domain.OutgoingMessage{}, fmt.Errorf("failed to list skills: %w", err)
 	}
@@ -131,6 +170,7 @@ func (s *Service) ProcessMessage(ctx context.Context, incomingMsg *domain.Incomi
 	// Add current message
 	llmMessages = append(llmMessages, domain.Message{Role: "user", Content: incomingMsg.Text})
 
+	provider := domain.LLMProviderAnthropic // TODO: Route dynamically
 	llmRequest := &domain.LLMRequest{
 		Model:        "claude-3-sonnet-20240229", // TODO: Get from config/user preferences
 		Messages:     llmMessages,
@@ -138,7 +178,10 @@ func (s *Service) ProcessMessage(ctx context.Context, incomingMsg *domain.Incomi
 		Temperature:  0.7,                               // TODO: From config
 		Tools:        tools,                             // Skills exposed as tools
 		SystemPrompt: "You are a helpful AI assistant.", // TODO: From config
+		Cache:        historyCachePolicy(len(recentMessages)),
 	}
+	modelInfo := s.catalog.Lookup(provider, llmRequest.Model)
+	applyModelCapabilities(llmRequest, modelInfo)
 
 	// 5. Tool calling loop (max 5 iterations)
 	const maxToolIterations = 5
@@ -158,14 +201,17 @@ func (s *Service) ProcessMessage(ctx context.Context, incomingMsg *domain.Incomi
 		// Get LLM Response if not cached
 		if llmResponse == nil {
 			var err error
-			llmResponse, err = s.llmService.Complete(ctx, domain.LLMProviderAnthropic, llmRequest) // TODO: Route dynamically
+			llmResponse, err = s.llmService.Complete(ctx, provider, llmRequest)
 			if err != nil {
 				return domain.OutgoingMessage{}, fmt.Errorf("LLM completion failed: %w", err)
 			}
+			recordCost(provider, modelInfo, llmResponse.Usage)
 		}
 
 		// No tool calls - we're done
 		if len(llmResponse.ToolCalls) == 0 {
+			// Stitch together answers cut off by the output token limit
+			llmResponse = s.continueTruncated(ctx, provider, llmRequest, llmResponse, modelInfo)
 			finalResponse = llmResponse
 			// Cache successful final response (no tool calls)
 			if s.cache != nil && iteration == 0 {
@@ -177,12 +223,14 @@ func (s *Service) ProcessMessage(ctx context.Context, incomingMsg *domain.Incomi
 		}
 
 		// Execute tool calls
-		toolResults := s.executeToolCalls(ctx, llmResponse.ToolCalls)
+		toolResults := s.executeToolCalls(ctx, user, llmResponse.ToolCalls)
 
-		// Add assistant message with tool calls to conversation
+		// Add assistant message with tool calls to conversation; signed
+		// thinking blocks must be replayed unmodified for the next iteration
 		llmMessages = append(llmMessages, domain.Message{
-			Role:    "assistant",
-			Content: llmResponse.Content,
+			Role:      "assistant",
+			Content:   llmResponse.Content,
+			Reasoning: llmResponse.Reasoning,
 		})
 
 		// Add tool results as user message
@@ -194,8 +242,10 @@ func (s *Service) ProcessMessage(ctx context.Context, incomingMsg *domain.Incomi
 			Content: toolResultsText,
 		})
 
-		// Update request with new messages
+		// Update request with new messages; everything before the latest
+		// tool results is resent unchanged, so cache it
 		llmRequest.Messages = llmMessages
+		llmRequest.Cache = historyCachePolicy(len(llmMessages) - 1)
 	}
 
 	// If we hit max iterations, use last response
@@ -242,6 +292,12 @@ func (s *Service) ProcessMessage(ctx context.Context, incomingMsg *domain.Incomi
 		Format:      "markdown",                          // Assuming LLM returns markdown
 		Metadata:    map[string]any{"request_id": reqID}, // Include request ID for correlation
 	}
+	if finalResponse.FinishReason.Truncated() {
+		outgoingMsg.Metadata["truncated"] = true
+	}
+	if reasoning := finalResponse.ReasoningText(); reasoning != "" && s.showReasoning(ctx, user.ID) {
+		outgoingMsg.Reasoning = reasoning
+	}
 
 	return outgoingMsg, nil
 }
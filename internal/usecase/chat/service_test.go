@@ -73,19 +73,21 @@ func (m *mockMemoryRepository) ListConversations(ctx context.Context, userID str
 
 type mockToolExecutionService struct {
 	executeFunc    func(ctx context.Context, toolName string, params map[string]any) (*domain.ExecutionResult, error)
-	listSkillsFunc func(ctx context.Context, userID string) ([]domain.Tool, error)
+	listSkillsFunc func(ctx context.Context, user *domain.User) ([]domain.Tool, error)
+	executedBy     []*domain.User
 }
 
-func (m *mockToolExecutionService) Execute(ctx context.Context, toolName string, params map[string]any) (*domain.ExecutionResult, error) {
+func (m *mockToolExecutionService) ExecuteWithUser(ctx context.Context, user *domain.User, toolName string, params map[string]any) (*domain.ExecutionResult, error) {
+	m.executedBy = append(m.executedBy, user)
 	if m.executeFunc != nil {
 		return m.executeFunc(ctx, toolName, params)
 	}
 	return &domain.ExecutionResult{Output: "mock skill result"}, nil
 }
 
-func (m *mockToolExecutionService) ListTools(ctx context.Context, userID string) ([]domain.Tool, error) {
+func (m *mockToolExecutionService) ListTools(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 	if m.listSkillsFunc != nil {
-		return m.listSkillsFunc(ctx, userID)
+		return m.listSkillsFunc(ctx, user)
 	}
 	return []domain.Tool{}, nil
 }
@@ -140,6 +142,27 @@ func (m *mockSkill) Config() domain.ToolConfig {
 }
 
 // Test helper to create a service with mocks
+type mockPreferencesRepository struct {
+	prefs map[string]domain.UserPreferences
+}
+
+func (m *mockPreferencesRepository) Get(ctx context.Context, userID string) (domain.UserPreferences, error) {
+	if prefs, ok := m.prefs[userID]; ok {
+		return prefs, nil
+	}
+	return domain.UserPreferences{}, domain.ErrNotFound
+}
+
+func (m *mockPreferencesRepository) Save(ctx context.Context, userID string, prefs domain.UserPreferences) error {
+	m.prefs[userID] = prefs
+	return nil
+}
+
+func (m *mockPreferencesRepository) Delete(ctx context.Context, userID string) error {
+	delete(m.prefs, userID)
+	return nil
+}
+
 func createTestService(
 	llmService LLMService,
 	memoryRepo MemoryRepository,
@@ -156,7 +179,7 @@ func TestProcessMessage_NoToolCalls(t *testing.T) {
 			return &domain.LLMResponse{
 				Content:      "Hello! How can I help you?",
 				ToolCalls:    []domain.ToolCall{},
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage: domain.TokenUsage{
 					PromptTokens:     10,
 					CompletionTokens: 20,
@@ -173,7 +196,7 @@ func TestProcessMessage_NoToolCalls(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return []domain.Tool{}, nil
 		},
 	}
@@ -225,7 +248,7 @@ func TestProcessMessage_WithToolCalls(t *testing.T) {
 							},
 						},
 					},
-					FinishReason: "tool_use",
+					FinishReason: domain.FinishReasonToolCalls,
 					Usage: domain.TokenUsage{
 						PromptTokens:     10,
 						CompletionTokens: 15,
@@ -237,7 +260,7 @@ func TestProcessMessage_WithToolCalls(t *testing.T) {
 			return &domain.LLMResponse{
 				Content:      "The result is 8.",
 				ToolCalls:    []domain.ToolCall{},
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage: domain.TokenUsage{
 					PromptTokens:     30,
 					CompletionTokens: 10,
@@ -254,7 +277,7 @@ func TestProcessMessage_WithToolCalls(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return []domain.Tool{
 				&mockSkill{
 					name:        "calculator",
@@ -324,7 +347,7 @@ func TestProcessMessage_MultipleToolIterations(t *testing.T) {
 							Arguments: map[string]any{"operation": "add"},
 						},
 					},
-					FinishReason: "tool_use",
+					FinishReason: domain.FinishReasonToolCalls,
 					Usage:        domain.TokenUsage{PromptTokens: 10, CompletionTokens: 10, TotalTokens: 20},
 				}, nil
 			}
@@ -332,7 +355,7 @@ func TestProcessMessage_MultipleToolIterations(t *testing.T) {
 			return &domain.LLMResponse{
 				Content:      "Done!",
 				ToolCalls:    []domain.ToolCall{},
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage:        domain.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
 			}, nil
 		},
@@ -345,7 +368,7 @@ func TestProcessMessage_MultipleToolIterations(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return []domain.Tool{
 				&mockSkill{name: "calculator", description: "Calculator", inputSchema: map[string]any{}},
 			}, nil
@@ -391,7 +414,7 @@ func TestProcessMessage_MaxIterationsExceeded(t *testing.T) {
 				ToolCalls: []domain.ToolCall{
 					{ToolName: "calculator", Arguments: map[string]any{}},
 				},
-				FinishReason: "tool_use",
+				FinishReason: domain.FinishReasonToolCalls,
 				Usage:        domain.TokenUsage{PromptTokens: 10, CompletionTokens: 10, TotalTokens: 20},
 			}, nil
 		},
@@ -404,7 +427,7 @@ func TestProcessMessage_MaxIterationsExceeded(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return []domain.Tool{
 				&mockSkill{name: "calculator", description: "Calculator", inputSchema: map[string]any{}},
 			}, nil
@@ -449,7 +472,7 @@ func TestProcessMessage_ToolExecutionError(t *testing.T) {
 					ToolCalls: []domain.ToolCall{
 						{ToolName: "calculator", Arguments: map[string]any{}},
 					},
-					FinishReason: "tool_use",
+					FinishReason: domain.FinishReasonToolCalls,
 					Usage:        domain.TokenUsage{PromptTokens: 10, CompletionTokens: 10, TotalTokens: 20},
 				}, nil
 			}
@@ -457,7 +480,7 @@ func TestProcessMessage_ToolExecutionError(t *testing.T) {
 			return &domain.LLMResponse{
 				Content:      "I encountered an error executing the tool.",
 				ToolCalls:    []domain.ToolCall{},
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage:        domain.TokenUsage{PromptTokens: 20, CompletionTokens: 10, TotalTokens: 30},
 			}, nil
 		},
@@ -470,7 +493,7 @@ func TestProcessMessage_ToolExecutionError(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return []domain.Tool{
 				&mockSkill{name: "calculator", description: "Calculator", inputSchema: map[string]any{}},
 			}, nil
@@ -546,7 +569,7 @@ func TestProcessMessage_SkillListingError(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return nil, errors.New("skill service unavailable")
 		},
 	}
@@ -588,7 +611,7 @@ func TestProcessMessage_LLMCompletionError(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return []domain.Tool{}, nil
 		},
 	}
@@ -655,7 +678,7 @@ func TestProcessMessage_CacheHit(t *testing.T) {
 			return &domain.LLMResponse{
 				Content:      "Hello! How can I help you?",
 				ToolCalls:    []domain.ToolCall{},
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage: domain.TokenUsage{
 					PromptTokens:     10,
 					CompletionTokens: 20,
@@ -672,7 +695,7 @@ func TestProcessMessage_CacheHit(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return []domain.Tool{}, nil
 		},
 	}
@@ -725,7 +748,7 @@ func TestProcessMessage_CacheMiss(t *testing.T) {
 			return &domain.LLMResponse{
 				Content:      "Response " + string(rune('0'+llmCallCount)),
 				ToolCalls:    []domain.ToolCall{},
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage: domain.TokenUsage{
 					PromptTokens:     10,
 					CompletionTokens: 20,
@@ -742,7 +765,7 @@ func TestProcessMessage_CacheMiss(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return []domain.Tool{}, nil
 		},
 	}
@@ -803,7 +826,7 @@ func TestProcessMessage_NoCacheForToolCalls(t *testing.T) {
 					ToolCalls: []domain.ToolCall{
 						{ToolName: "calculator", Arguments: map[string]any{}},
 					},
-					FinishReason: "tool_use",
+					FinishReason: domain.FinishReasonToolCalls,
 					Usage:        domain.TokenUsage{PromptTokens: 10, CompletionTokens: 10, TotalTokens: 20},
 				}, nil
 			}
@@ -811,7 +834,7 @@ func TestProcessMessage_NoCacheForToolCalls(t *testing.T) {
 			return &domain.LLMResponse{
 				Content:      "Result",
 				ToolCalls:    []domain.ToolCall{},
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage:        domain.TokenUsage{PromptTokens: 20, CompletionTokens: 5, TotalTokens: 25},
 			}, nil
 		},
@@ -824,7 +847,7 @@ func TestProcessMessage_NoCacheForToolCalls(t *testing.T) {
 	}
 
 	toolExecService := &mockToolExecutionService{
-		listSkillsFunc: func(ctx context.Context, userID string) ([]domain.Tool, error) {
+		listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
 			return []domain.Tool{
 				&mockSkill{name: "calculator", description: "Calculator", inputSchema: map[string]any{}},
 			}, nil
@@ -874,3 +897,91 @@ func TestProcessMessage_NoCacheForToolCalls(t *testing.T) {
 		t.Errorf("Expected 4 LLM calls (no cache for tool use), got %d", llmCallCount)
 	}
 }
+
+// TestProcessMessage_ReasoningReplayedInToolLoop tests that thinking blocks are echoed back during tool loops
+func TestProcessMessage_ReasoningReplayedInToolLoop(t *testing.T) {
+	thinking := []domain.ReasoningBlock{{Text: "I should use the calculator.", Signature: "sig-1"}}
+	callCount := 0
+
+	llmService := &mockLLMService{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			callCount++
+			if callCount == 1 {
+				return &domain.LLMResponse{
+					Reasoning: thinking,
+					ToolCalls: []domain.ToolCall{{ToolName: "calculator", Arguments: map[string]any{}}},
+				}, nil
+			}
+
+			// The assistant turn that requested the tool must carry its signed thinking
+			assistant := req.Messages[len(req.Messages)-2]
+			if assistant.Role != "assistant" || len(assistant.Reasoning) != 1 || assistant.Reasoning[0].Signature != "sig-1" {
+				t.Errorf("Expected assistant message with signed reasoning, got %+v", assistant)
+			}
+			return &domain.LLMResponse{
+				Content:   "The result is 8.",
+				Reasoning: []domain.ReasoningBlock{{Text: "The tool returned 8.", Signature: "sig-2"}},
+			}, nil
+		},
+	}
+
+	service := createTestService(llmService, &mockMemoryRepository{}, &mockToolExecutionService{}, &mockSecurityService{})
+
+	outgoingMsg, err := service.ProcessMessage(context.Background(), &domain.IncomingMessage{
+		Platform:    domain.PlatformCLI,
+		PlatformUID: "user-1",
+		Text:        "What is 5 + 3?",
+	})
+	if err != nil {
+		t.Fatalf("ProcessMessage failed: %v", err)
+	}
+
+	if outgoingMsg.Reasoning != "" {
+		t.Errorf("Expected reasoning hidden without preferences, got %q", outgoingMsg.Reasoning)
+	}
+}
+
+// TestProcessMessage_ShowReasoningPreference tests that reasoning is exposed only to users who opted in
+func TestProcessMessage_ShowReasoningPreference(t *testing.T) {
+	llmService := &mockLLMService{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			return &domain.LLMResponse{
+				Content:   "42",
+				Reasoning: []domain.ReasoningBlock{{Text: "Thinking about it.", Signature: "sig"}},
+			}, nil
+		},
+	}
+
+	// Preferences are keyed by the resolved user's ID, not the platform UID
+	service := createTestService(llmService, &mockMemoryRepository{}, &mockToolExecutionService{}, &mockSecurityService{})
+	service.SetPreferencesRepository(&mockPreferencesRepository{prefs: map[string]domain.UserPreferences{
+		"user-curious": {ShowReasoning: true},
+		"curious":      {ShowReasoning: true},
+	}})
+
+	tests := []struct {
+		name string
+		user *domain.User
+		want string
+	}{
+		{name: "opted in", user: &domain.User{ID: "user-curious", Role: domain.RoleUser}, want: "Thinking about it."},
+		{name: "not opted in", user: &domain.User{ID: "user-other", Role: domain.RoleUser}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.SetUserResolver(&mockUserResolver{user: tt.user})
+			outgoingMsg, err := service.ProcessMessage(context.Background(), &domain.IncomingMessage{
+				Platform:    domain.PlatformCLI,
+				PlatformUID: "curious",
+				Text:        "What is the answer?",
+			})
+			if err != nil {
+				t.Fatalf("ProcessMessage failed: %v", err)
+			}
+			if outgoingMsg.Reasoning != tt.want {
+				t.Errorf("Expected reasoning %q, got %q", tt.want, outgoingMsg.Reasoning)
+			}
+		})
+	}
+}
@@ -46,8 +46,9 @@ func (s *Service) ProcessMessageStream(ctx context.Context, incomingMsg *domain.
 			return
 		}
 
-		// 3. Get available skills and convert to tools
-		skills, err := s.toolExecService.ListTools(ctx, incomingMsg.PlatformUID)
+		// 3. Get the skills this user may run and convert to tools
+		user := s.resolveUser(ctx, incomingMsg)
+		skills, err := s.toolExecService.ListTools(ctx, user)
 		if err != nil {
 			outCh <- domain.StreamChunk{Error: fmt.Errorf("failed to list skills: %w", err)}
 			return
@@ -71,13 +72,19 @@ func (s *Service) ProcessMessageStream(ctx context.Context, incomingMsg *domain.
 		})
 
 		// 5. Stream LLM response
-		streamCh, err := s.llmService.Stream(ctx, domain.LLMProviderAnthropic, &domain.LLMRequest{
+		provider := domain.LLMProviderAnthropic
+		llmRequest := &domain.LLMRequest{
 			Model:       "claude-3-5-sonnet-20241022",
 			Messages:    llmMessages,
 			MaxTokens:   4096,
 			Temperature: 0.7,
 			Tools:       tools,
-		})
+			Cache:       historyCachePolicy(len(recentMessages)),
+		}
+		modelInfo := s.catalog.Lookup(provider, llmRequest.Model)
+		applyModelCapabilities(llmRequest, modelInfo)
+
+		streamCh, err := s.openStream(ctx, provider, llmRequest, modelInfo)
 		if err != nil {
 			outCh <- domain.StreamChunk{Error: fmt.Errorf("failed to start LLM stream: %w", err)}
 			return
@@ -86,6 +93,7 @@ func (s *Service) ProcessMessageStream(ctx context.Context, incomingMsg *domain.
 		// 6. Forward stream chunks and handle tool calls
 		var fullContent string
 		var toolCalls []domain.ToolCall
+		showReasoning := s.showReasoning(ctx, user.ID)
 
 		for chunk := range streamCh {
 			// Check for errors
@@ -94,6 +102,14 @@ func (s *Service) ProcessMessageStream(ctx context.Context, incomingMsg *domain.
 				return
 			}
 
+			// Forward reasoning only to users who opted in; it is never stored
+			if chunk.IsThinking() {
+				if showReasoning && chunk.Delta != "" {
+					outCh <- domain.StreamChunk{Kind: domain.StreamChunkThinking, Delta: chunk.Delta}
+				}
+				continue
+			}
+
 			// Accumulate content
 			if chunk.Delta != "" {
 				fullContent += chunk.Delta
@@ -234,3 +234,71 @@ func TestProcessMessageStream_ContextCancellation(t *testing.T) {
 		t.Error("Context cancellation did not stop stream early")
 	}
 }
+
+// Test that thinking chunks are forwarded only to users who opted in and never stored
+func TestProcessMessageStream_ThinkingChunks(t *testing.T) {
+	llmService := &mockLLMService{
+		streamFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+			ch := make(chan domain.StreamChunk, 4)
+			go func() {
+				defer close(ch)
+				ch <- domain.StreamChunk{Kind: domain.StreamChunkThinking, Delta: "Let me think"}
+				ch <- domain.StreamChunk{Kind: domain.StreamChunkThinking, Signature: "sig"}
+				ch <- domain.StreamChunk{Delta: "Answer"}
+				ch <- domain.StreamChunk{Done: true}
+			}()
+			return ch, nil
+		},
+	}
+
+	for _, show := range []bool{true, false} {
+		var saved string
+		memoryRepo := &mockMemoryRepository{
+			saveMessageFunc: func(ctx context.Context, convID string, userID string, platform domain.Platform, msg domain.StoredMessage) error {
+				saved = msg.Content
+				return nil
+			},
+		}
+
+		service := createTestService(llmService, memoryRepo, &mockToolExecutionService{}, &mockSecurityService{})
+		service.SetPreferencesRepository(&mockPreferencesRepository{prefs: map[string]domain.UserPreferences{
+			"user-1": {ShowReasoning: show},
+		}})
+		service.SetUserResolver(&mockUserResolver{user: &domain.User{ID: "user-1", Role: domain.RoleUser}})
+
+		ch, err := service.ProcessMessageStream(context.Background(), &domain.IncomingMessage{
+			Platform:    domain.PlatformCLI,
+			PlatformUID: "12345",
+			Text:        "question",
+		})
+		if err != nil {
+			t.Fatalf("ProcessMessageStream failed: %v", err)
+		}
+
+		var thinking, content string
+		for chunk := range ch {
+			if chunk.Error != nil {
+				t.Fatalf("Stream error: %v", chunk.Error)
+			}
+			if chunk.IsThinking() {
+				thinking += chunk.Delta
+			} else {
+				content += chunk.Delta
+			}
+		}
+
+		wantThinking := ""
+		if show {
+			wantThinking = "Let me think"
+		}
+		if thinking != wantThinking {
+			t.Errorf("show=%v: expected thinking %q, got %q", show, wantThinking, thinking)
+		}
+		if content != "Answer" {
+			t.Errorf("show=%v: expected content 'Answer', got %q", show, content)
+		}
+		if saved != "Answer" {
+			t.Errorf("show=%v: expected stored content 'Answer', got %q", show, saved)
+		}
+	}
+}
@@ -23,12 +23,13 @@ func convertSkillsToTools(skills []domain.Tool) []domain.ToolDefinition {
 	return tools
 }
 
-// executeToolCalls executes a list of tool calls and returns their results
-func (s *Service) executeToolCalls(ctx context.Context, toolCalls []domain.ToolCall) []domain.ToolResult {
+// executeToolCalls executes a list of tool calls on behalf of user and returns their results.
+// Calls the user is not permitted to make come back as errors for the LLM.
+func (s *Service) executeToolCalls(ctx context.Context, user *domain.User, toolCalls []domain.ToolCall) []domain.ToolResult {
 	results := make([]domain.ToolResult, 0, len(toolCalls))
 
 	for _, toolCall := range toolCalls {
-		result, err := s.toolExecService.Execute(ctx, toolCall.ToolName, toolCall.Arguments)
+		result, err := s.toolExecService.ExecuteWithUser(ctx, user, toolCall.ToolName, toolCall.Arguments)
 
 		toolResult := domain.ToolResult{
 			ToolName: toolCall.ToolName,
@@ -74,6 +75,17 @@ func formatToolResults(results []domain.ToolResult) string {
 	return formatted
 }
 
+// historyCachePolicy returns the prompt cache policy for a chat request whose
+// first historyLen messages are unchanged from the previous request. Tool
+// definitions and the system prompt are stable across turns and always cached.
+func historyCachePolicy(historyLen int) domain.CachePolicy {
+	return domain.CachePolicy{
+		Tools:           true,
+		SystemPrompt:    true,
+		HistoryMessages: historyLen,
+	}
+}
+
 // buildCacheKey creates a stable cache key from conversation messages.
 // The key is a concatenation of all message roles and content.
 func buildCacheKey(messages []domain.Message) string {
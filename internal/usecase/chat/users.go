@@ -0,0 +1,49 @@
+package chat
+
+import (
+	"context"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/requestid"
+)
+
+// UserResolver maps a platform identity to the user whose role and tool
+// whitelist govern which tools the LLM may see and call.
+type UserResolver interface {
+	ResolveUser(ctx context.Context, platform domain.Platform, platformUID string) (*domain.User, error)
+}
+
+// SetUserResolver sets how platform identities are resolved to users (optional).
+// Without it, every identity is treated as a guest.
+func (s *Service) SetUserResolver(resolver UserResolver) {
+	s.userResolver = resolver
+}
+
+// resolveUser returns the user for an incoming message. It fails closed:
+// identities that cannot be resolved are treated as guests.
+func (s *Service) resolveUser(ctx context.Context, msg *domain.IncomingMessage) *domain.User {
+	if s.userResolver != nil {
+		user, err := s.userResolver.ResolveUser(ctx, msg.Platform, msg.PlatformUID)
+		if err == nil && user != nil {
+			return user
+		}
+		if err != nil {
+			requestid.Logger(ctx).Warn("Failed to resolve user, treating as guest",
+				"platform", msg.Platform,
+				"user", msg.PlatformUID,
+				"error", err,
+			)
+		}
+	}
+	return guestUser(msg.Platform, msg.PlatformUID)
+}
+
+// guestUser builds a transient guest for a platform identity.
+func guestUser(platform domain.Platform, platformUID string) *domain.User {
+	return &domain.User{
+		ID:          getConversationID(platform, platformUID),
+		Username:    platformUID,
+		Role:        domain.RoleGuest,
+		PlatformIDs: map[domain.Platform]string{platform: platformUID},
+	}
+}
@@ -0,0 +1,83 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"nuimanbot/internal/domain"
+)
+
+type mockUserResolver struct {
+	user *domain.User
+	err  error
+}
+
+func (m *mockUserResolver) ResolveUser(ctx context.Context, platform domain.Platform, platformUID string) (*domain.User, error) {
+	return m.user, m.err
+}
+
+func TestProcessMessage_ToolsScopedToResolvedUser(t *testing.T) {
+	admin := &domain.User{ID: "admin-1", Role: domain.RoleAdmin}
+
+	tests := []struct {
+		name     string
+		resolver UserResolver
+		wantID   string
+		wantRole domain.Role
+	}{
+		{"resolved user", &mockUserResolver{user: admin}, "admin-1", domain.RoleAdmin},
+		{"no resolver", nil, "telegram:42", domain.RoleGuest},
+		{"resolver error", &mockUserResolver{err: errors.New("db down")}, "telegram:42", domain.RoleGuest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			llmService := &mockLLMService{
+				completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+					calls++
+					if calls == 1 {
+						return &domain.LLMResponse{
+							ToolCalls:    []domain.ToolCall{{ToolName: "calculator"}},
+							FinishReason: domain.FinishReasonToolCalls,
+						}, nil
+					}
+					return &domain.LLMResponse{Content: "done", FinishReason: domain.FinishReasonStop}, nil
+				},
+			}
+
+			var listedFor *domain.User
+			toolExecService := &mockToolExecutionService{
+				listSkillsFunc: func(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
+					listedFor = user
+					return nil, nil
+				},
+			}
+
+			service := createTestService(llmService, &mockMemoryRepository{}, toolExecService, &mockSecurityService{})
+			if tt.resolver != nil {
+				service.SetUserResolver(tt.resolver)
+			}
+
+			_, err := service.ProcessMessage(context.Background(), &domain.IncomingMessage{
+				ID:          "msg-1",
+				Platform:    domain.PlatformTelegram,
+				PlatformUID: "42",
+				Text:        "hi",
+				Timestamp:   time.Now(),
+			})
+			if err != nil {
+				t.Fatalf("ProcessMessage failed: %v", err)
+			}
+
+			if listedFor == nil || listedFor.ID != tt.wantID || listedFor.Role != tt.wantRole {
+				t.Errorf("ListTools user = %+v, want ID %s role %s", listedFor, tt.wantID, tt.wantRole)
+			}
+			if len(toolExecService.executedBy) != 1 || toolExecService.executedBy[0] != listedFor {
+				t.Errorf("Expected tool call executed as the listed user, got %+v", toolExecService.executedBy)
+			}
+		})
+	}
+}
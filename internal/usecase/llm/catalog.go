@@ -0,0 +1,239 @@
+package llm
+
+import (
+	"context"
+	_ "embed" // For the bundled model table
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"nuimanbot/internal/domain"
+)
+
+//go:embed models.yaml
+var bundledCatalog []byte
+
+// Conservative limits used when neither the model nor its provider is catalogued.
+const (
+	DefaultContextWindow   = 32000
+	DefaultMaxOutputTokens = 4096
+)
+
+// bedrockRegionPrefixes are cross-region inference profile prefixes stripped before matching.
+var bedrockRegionPrefixes = []string{"us.", "eu.", "apac.", "us-gov."}
+
+// catalogEntry is one row of the model catalog YAML.
+type catalogEntry struct {
+	Provider        domain.LLMProvider `yaml:"provider"`
+	Model           string             `yaml:"model"` // Model ID or prefix; empty for the provider default
+	ContextWindow   int                `yaml:"context_window"`
+	MaxOutputTokens int                `yaml:"max_output_tokens"`
+	Tools           bool               `yaml:"tools"`
+	Vision          bool               `yaml:"vision"`
+	Streaming       bool               `yaml:"streaming"`
+	Pricing         catalogPricing     `yaml:"pricing"`
+}
+
+// catalogPricing is the YAML form of domain.ModelPricing.
+type catalogPricing struct {
+	Input      float64 `yaml:"input"`
+	Output     float64 `yaml:"output"`
+	CacheWrite float64 `yaml:"cache_write"`
+	CacheRead  float64 `yaml:"cache_read"`
+}
+
+func (e catalogEntry) toModelInfo(id string) domain.ModelInfo {
+	return domain.ModelInfo{
+		ID:              id,
+		Name:            id,
+		Provider:        string(e.Provider),
+		ContextWindow:   e.ContextWindow,
+		MaxOutputTokens: e.MaxOutputTokens,
+		SupportsTools:   e.Tools,
+		SupportsVision:  e.Vision,
+		SupportsStream:  e.Streaming,
+		Pricing: domain.ModelPricing{
+			InputPerMTok:      e.Pricing.Input,
+			OutputPerMTok:     e.Pricing.Output,
+			CacheWritePerMTok: e.Pricing.CacheWrite,
+			CacheReadPerMTok:  e.Pricing.CacheRead,
+		},
+	}
+}
+
+// Catalog answers capability questions about models: context window, output
+// limit, tool/vision/streaming support and pricing. It is built from a bundled
+// YAML table, optional overrides, and the models providers report via ListModels.
+type Catalog struct {
+	mu      sync.RWMutex
+	entries map[domain.LLMProvider]map[string]catalogEntry // provider -> model prefix -> entry
+}
+
+// NewCatalog creates a catalog from the bundled table.
+func NewCatalog() *Catalog {
+	c := &Catalog{entries: make(map[domain.LLMProvider]map[string]catalogEntry)}
+	if err := c.LoadYAML(bundledCatalog); err != nil {
+		panic(fmt.Sprintf("invalid bundled model catalog: %v", err)) // Programming error
+	}
+	return c
+}
+
+// LoadCatalog creates a catalog from the bundled table, then applies overrides
+// from overridePath if set. Override entries replace bundled ones with the same
+// provider and model.
+func LoadCatalog(overridePath string) (*Catalog, error) {
+	c := NewCatalog()
+	if overridePath == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(overridePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model catalog: %w", err)
+	}
+	if err := c.LoadYAML(data); err != nil {
+		return nil, fmt.Errorf("failed to load model catalog %s: %w", overridePath, err)
+	}
+	return c, nil
+}
+
+// LoadYAML merges catalog entries from YAML data.
+func (c *Catalog) LoadYAML(data []byte) error {
+	var table struct {
+		Models []catalogEntry `yaml:"models"`
+	}
+	if err := yaml.Unmarshal(data, &table); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, entry := range table.Models {
+		if entry.Provider == "" {
+			return fmt.Errorf("entry %d: provider is required", i)
+		}
+		c.set(entry)
+	}
+	return nil
+}
+
+// Refresh adds models reported by each provider's ListModels. Reported models
+// inherit capabilities from their best catalog match; a reported context window
+// is used when the model has no exact catalog entry. Providers that fail to list
+// models are skipped.
+func (c *Catalog) Refresh(ctx context.Context, svc domain.LLMService, providers ...domain.LLMProvider) {
+	for _, provider := range providers {
+		models, err := svc.ListModels(ctx, provider)
+		if err != nil {
+			slog.Debug("Skipping model catalog refresh", "provider", provider, "error", err)
+			continue
+		}
+
+		c.mu.Lock()
+		for _, m := range models {
+			if _, exact := c.entries[provider][normalizeModelID(provider, m.ID)]; exact {
+				continue
+			}
+			entry, _ := c.match(provider, m.ID)
+			entry.Provider = provider
+			entry.Model = normalizeModelID(provider, m.ID)
+			if m.ContextWindow > 0 {
+				entry.ContextWindow = m.ContextWindow
+			}
+			c.set(entry)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// Lookup returns the capabilities of a model. Unknown models get their
+// provider's defaults, and unknown providers get conservative defaults.
+func (c *Catalog) Lookup(provider domain.LLMProvider, model string) domain.ModelInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.match(provider, model)
+	if !ok {
+		entry = catalogEntry{
+			Provider:        provider,
+			ContextWindow:   DefaultContextWindow,
+			MaxOutputTokens: DefaultMaxOutputTokens,
+			Streaming:       true,
+		}
+	}
+	return entry.toModelInfo(model)
+}
+
+// Models returns every catalogued model for a provider, excluding the provider default.
+func (c *Catalog) Models(provider domain.LLMProvider) []domain.ModelInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	models := make([]domain.ModelInfo, 0, len(c.entries[provider]))
+	for id, entry := range c.entries[provider] {
+		if id != "" {
+			models = append(models, entry.toModelInfo(id))
+		}
+	}
+	return models
+}
+
+// ContextWindow returns the model's context window in tokens.
+func (c *Catalog) ContextWindow(provider domain.LLMProvider, model string) int {
+	return c.Lookup(provider, model).ContextWindow
+}
+
+// Cost returns the USD cost of usage for a model, or 0 if its pricing is unknown.
+func (c *Catalog) Cost(provider domain.LLMProvider, model string, usage domain.TokenUsage) float64 {
+	return c.Lookup(provider, model).Pricing.Cost(usage)
+}
+
+// set stores an entry; callers must hold the write lock.
+func (c *Catalog) set(entry catalogEntry) {
+	if c.entries[entry.Provider] == nil {
+		c.entries[entry.Provider] = make(map[string]catalogEntry)
+	}
+	entry.Model = normalizeModelID(entry.Provider, entry.Model)
+	c.entries[entry.Provider][entry.Model] = entry
+}
+
+// match finds the entry with the longest model prefix matching model, falling
+// back to the provider default. Callers must hold the lock.
+func (c *Catalog) match(provider domain.LLMProvider, model string) (catalogEntry, bool) {
+	entries := c.entries[provider]
+	id := normalizeModelID(provider, model)
+
+	best, found := "", false
+	for prefix := range entries {
+		if prefix == "" || !strings.HasPrefix(id, prefix) {
+			continue
+		}
+		if !found || len(prefix) > len(best) {
+			best, found = prefix, true
+		}
+	}
+	if found {
+		return entries[best], true
+	}
+
+	entry, ok := entries[""]
+	return entry, ok
+}
+
+// normalizeModelID strips Bedrock cross-region prefixes so that profile IDs
+// match their base model entries.
+func normalizeModelID(provider domain.LLMProvider, model string) string {
+	if provider != domain.LLMProviderBedrock {
+		return model
+	}
+	for _, prefix := range bedrockRegionPrefixes {
+		if strings.HasPrefix(model, prefix) {
+			return strings.TrimPrefix(model, prefix)
+		}
+	}
+	return model
+}
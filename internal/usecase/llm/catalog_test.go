@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nuimanbot/internal/domain"
+)
+
+func TestCatalog_Lookup(t *testing.T) {
+	catalog := NewCatalog()
+
+	tests := []struct {
+		name          string
+		provider      domain.LLMProvider
+		model         string
+		contextWindow int
+		maxOutput     int
+		tools         bool
+		vision        bool
+	}{
+		{"dated Anthropic model", domain.LLMProviderAnthropic, "claude-3-5-sonnet-20241022", 200000, 8192, true, true},
+		{"longest prefix wins", domain.LLMProviderOpenAI, "gpt-4o-mini-2024-07-18", 128000, 16384, true, true},
+		{"short prefix", domain.LLMProviderOpenAI, "gpt-4-0613", 8192, 8192, true, false},
+		{"Bedrock region profile", domain.LLMProviderBedrock, "us.anthropic.claude-3-5-sonnet-20241022-v2:0", 200000, 8192, true, true},
+		{"Bedrock unknown model", domain.LLMProviderBedrock, "mistral.mistral-large", 32000, 4096, true, false},
+		{"provider default", domain.LLMProviderOllama, "", 32000, 4096, true, false},
+		{"unknown provider", domain.LLMProvider("acme"), "acme-1", DefaultContextWindow, DefaultMaxOutputTokens, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := catalog.Lookup(tt.provider, tt.model)
+			if info.ContextWindow != tt.contextWindow {
+				t.Errorf("ContextWindow = %d, want %d", info.ContextWindow, tt.contextWindow)
+			}
+			if info.MaxOutputTokens != tt.maxOutput {
+				t.Errorf("MaxOutputTokens = %d, want %d", info.MaxOutputTokens, tt.maxOutput)
+			}
+			if info.SupportsTools != tt.tools || info.SupportsVision != tt.vision {
+				t.Errorf("tools/vision = %v/%v, want %v/%v", info.SupportsTools, info.SupportsVision, tt.tools, tt.vision)
+			}
+			if info.ID != tt.model {
+				t.Errorf("ID = %q, want %q", info.ID, tt.model)
+			}
+		})
+	}
+}
+
+func TestCatalog_Cost(t *testing.T) {
+	catalog := NewCatalog()
+
+	usage := domain.TokenUsage{
+		PromptTokens:        1_200_000,
+		CompletionTokens:    100_000,
+		CacheCreationTokens: 100_000,
+		CacheReadTokens:     100_000,
+	}
+	// 1M uncached * $3 + 0.1M * $15 + 0.1M * $3.75 + 0.1M * $0.30
+	want := 3.0 + 1.5 + 0.375 + 0.03
+	if got := catalog.Cost(domain.LLMProviderAnthropic, "claude-3-5-sonnet-20241022", usage); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost() = %v, want %v", got, want)
+	}
+
+	if got := catalog.Cost(domain.LLMProviderOllama, "llama3", usage); got != 0 {
+		t.Errorf("Expected local models to be free, got %v", got)
+	}
+}
+
+func TestLoadCatalog_Overrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models.yaml")
+	data := `
+models:
+  - provider: ollama
+    model: qwen2.5
+    context_window: 131072
+    max_output_tokens: 8192
+    tools: true
+    streaming: true
+  - provider: openai
+    model: gpt-4o
+    context_window: 64000
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write catalog: %v", err)
+	}
+
+	catalog, err := LoadCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadCatalog() error: %v", err)
+	}
+
+	if got := catalog.ContextWindow(domain.LLMProviderOllama, "qwen2.5:14b"); got != 131072 {
+		t.Errorf("Expected added model context 131072, got %d", got)
+	}
+	if got := catalog.ContextWindow(domain.LLMProviderOpenAI, "gpt-4o"); got != 64000 {
+		t.Errorf("Expected overridden context 64000, got %d", got)
+	}
+
+	if _, err := LoadCatalog(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected error for missing override file")
+	}
+}
+
+func TestCatalog_Refresh(t *testing.T) {
+	catalog := NewCatalog()
+	client := &mockProviderClient{
+		listModelsFunc: func(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
+			if provider != domain.LLMProviderOllama {
+				return nil, errors.New("not configured")
+			}
+			return []domain.ModelInfo{
+				{ID: "llava:13b", ContextWindow: 4096},
+				{ID: "mistral:7b"},
+			}, nil
+		},
+	}
+
+	catalog.Refresh(context.Background(), client, domain.LLMProviderOllama, domain.LLMProviderOpenAI)
+
+	llava := catalog.Lookup(domain.LLMProviderOllama, "llava:13b")
+	if llava.ContextWindow != 4096 || !llava.SupportsVision {
+		t.Errorf("Expected reported context and inherited vision support, got %+v", llava)
+	}
+
+	models := catalog.Models(domain.LLMProviderOllama)
+	found := false
+	for _, m := range models {
+		if m.ID == "mistral:7b" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected discovered model in catalog, got %+v", models)
+	}
+}
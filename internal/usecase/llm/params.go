@@ -0,0 +1,219 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"nuimanbot/internal/config"
+	"nuimanbot/internal/domain"
+)
+
+// ModelParams are request defaults configured per model under llm.models.<name>.params.
+type ModelParams struct {
+	Temperature       float64
+	MaxTokens         int
+	TopP              float64
+	TopK              int
+	StopSequences     []string
+	Seed              *int
+	ToolChoice        *domain.ToolChoice
+	ParallelToolCalls *bool
+}
+
+// ParseModelParams validates and converts a config params map. Recognized keys:
+// temperature, max_tokens, top_p, top_k, stop (string or list), seed,
+// tool_choice ("auto", "none", "required" or a tool name) and parallel_tool_calls.
+func ParseModelParams(params map[string]any) (ModelParams, error) {
+	var p ModelParams
+
+	// Sorted so that the first error reported is deterministic
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := params[key]
+		var ok bool
+		switch key {
+		case "temperature":
+			p.Temperature, ok = toFloat(value)
+		case "max_tokens":
+			p.MaxTokens, ok = toInt(value)
+		case "top_p":
+			p.TopP, ok = toFloat(value)
+		case "top_k":
+			p.TopK, ok = toInt(value)
+		case "seed":
+			var seed int
+			seed, ok = toInt(value)
+			p.Seed = &seed
+		case "stop":
+			p.StopSequences, ok = toStrings(value)
+		case "tool_choice":
+			var choice string
+			choice, ok = value.(string)
+			p.ToolChoice = domain.ParseToolChoice(choice)
+		case "parallel_tool_calls":
+			var parallel bool
+			parallel, ok = value.(bool)
+			p.ParallelToolCalls = &parallel
+		default:
+			return ModelParams{}, fmt.Errorf("unknown model param %q", key)
+		}
+		if !ok {
+			return ModelParams{}, fmt.Errorf("invalid value for model param %q: %v", key, value)
+		}
+	}
+	return p, nil
+}
+
+// Apply fills the request fields the caller left unset, so explicit request
+// values always win over configured defaults.
+func (p ModelParams) Apply(req *domain.LLMRequest) {
+	if req.Temperature == 0 {
+		req.Temperature = p.Temperature
+	}
+	if req.MaxTokens == 0 {
+		req.MaxTokens = p.MaxTokens
+	}
+	if req.TopP == 0 {
+		req.TopP = p.TopP
+	}
+	if req.TopK == 0 {
+		req.TopK = p.TopK
+	}
+	if len(req.StopSequences) == 0 {
+		req.StopSequences = p.StopSequences
+	}
+	if req.Seed == nil {
+		req.Seed = p.Seed
+	}
+	if req.ToolChoice == nil {
+		req.ToolChoice = p.ToolChoice
+	}
+	if req.ParallelToolCalls == nil {
+		req.ParallelToolCalls = p.ParallelToolCalls
+	}
+}
+
+// ParamsService is a domain.LLMService decorator that merges per-model default
+// params from config into every request.
+type ParamsService struct {
+	underlying domain.LLMService
+	params     map[string]ModelParams // Keyed by lower-cased model key or alias
+}
+
+// NewParamsService wraps underlying with the params from models. A model entry
+// is keyed by "provider/model", a bare model ID, or just the provider name (to
+// set defaults for all of its models); aliases are matched as model IDs.
+func NewParamsService(underlying domain.LLMService, models map[string]config.LLMModelConfig) (*ParamsService, error) {
+	s := &ParamsService{
+		underlying: underlying,
+		params:     make(map[string]ModelParams, len(models)),
+	}
+	for name, model := range models {
+		if len(model.Params) == 0 {
+			continue
+		}
+		params, err := ParseModelParams(model.Params)
+		if err != nil {
+			return nil, fmt.Errorf("model %s: %w", name, err)
+		}
+		s.params[strings.ToLower(name)] = params
+		if model.Alias != "" {
+			s.params[strings.ToLower(model.Alias)] = params
+		}
+	}
+	return s, nil
+}
+
+// Complete merges configured params and forwards the request.
+func (s *ParamsService) Complete(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+	return s.underlying.Complete(ctx, provider, s.Merge(provider, req))
+}
+
+// Stream merges configured params and forwards the request.
+func (s *ParamsService) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
+	return s.underlying.Stream(ctx, provider, s.Merge(provider, req))
+}
+
+// ListModels forwards to the underlying service.
+func (s *ParamsService) ListModels(ctx context.Context, provider domain.LLMProvider) ([]domain.ModelInfo, error) {
+	return s.underlying.ListModels(ctx, provider)
+}
+
+// Merge returns a copy of req with model-specific params applied first and
+// provider-wide params filling whatever is still unset.
+func (s *ParamsService) Merge(provider domain.LLMProvider, req *domain.LLMRequest) *domain.LLMRequest {
+	if len(s.params) == 0 {
+		return req
+	}
+
+	model := strings.ToLower(req.Model)
+	var keys []string
+	if model != "" {
+		keys = append(keys, string(provider)+"/"+model, model)
+	}
+	keys = append(keys, string(provider))
+
+	merged := *req
+	for _, key := range keys {
+		if params, ok := s.params[key]; ok {
+			params.Apply(&merged)
+		}
+	}
+	return &merged
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), n == float64(int(n))
+	default:
+		return 0, false
+	}
+}
+
+func toStrings(v any) ([]string, bool) {
+	switch list := v.(type) {
+	case string:
+		return []string{list}, true
+	case []string:
+		return list, true
+	case []any:
+		result := make([]string, len(list))
+		for i, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result[i] = s
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,112 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/config"
+	"nuimanbot/internal/domain"
+)
+
+func TestParseModelParams(t *testing.T) {
+	params, err := ParseModelParams(map[string]any{
+		"temperature":         0.2,
+		"max_tokens":          512,
+		"top_p":               0.9,
+		"top_k":               float64(40), // Decoded YAML/JSON numbers
+		"stop":                []any{"END", "STOP"},
+		"seed":                7,
+		"tool_choice":         "calculator",
+		"parallel_tool_calls": false,
+	})
+	if err != nil {
+		t.Fatalf("ParseModelParams() error: %v", err)
+	}
+
+	if params.Temperature != 0.2 || params.MaxTokens != 512 || params.TopP != 0.9 || params.TopK != 40 {
+		t.Errorf("Unexpected sampling params: %+v", params)
+	}
+	if len(params.StopSequences) != 2 || params.Seed == nil || *params.Seed != 7 {
+		t.Errorf("Unexpected stop/seed: %v, %v", params.StopSequences, params.Seed)
+	}
+	if params.ToolChoice == nil || params.ToolChoice.Mode != domain.ToolChoiceTool || params.ToolChoice.Name != "calculator" {
+		t.Errorf("Expected forced calculator tool, got %+v", params.ToolChoice)
+	}
+	if params.ParallelToolCalls == nil || *params.ParallelToolCalls {
+		t.Errorf("Expected parallel tool calls disabled, got %v", params.ParallelToolCalls)
+	}
+
+	errorCases := []map[string]any{
+		{"temprature": 0.2},
+		{"top_k": 1.5},
+		{"stop": []any{1}},
+		{"parallel_tool_calls": "no"},
+	}
+	for _, tc := range errorCases {
+		if _, err := ParseModelParams(tc); err == nil {
+			t.Errorf("Expected error for %v", tc)
+		}
+	}
+}
+
+func TestParamsService_Merge(t *testing.T) {
+	var got *domain.LLMRequest
+	client := &mockProviderClient{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			got = req
+			return &domain.LLMResponse{}, nil
+		},
+	}
+
+	svc, err := NewParamsService(client, map[string]config.LLMModelConfig{
+		"anthropic": {Params: map[string]any{"temperature": 0.5, "top_p": 0.8, "tool_choice": "auto"}},
+		"anthropic/claude-sonnet-4-5": {
+			Alias:  "sonnet",
+			Params: map[string]any{"temperature": 0.1, "seed": 3},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewParamsService() error: %v", err)
+	}
+
+	// Model params win over provider params; the request's own values win over both
+	req := &domain.LLMRequest{Model: "claude-sonnet-4-5", TopP: 0.95}
+	if _, err := svc.Complete(context.Background(), domain.LLMProviderAnthropic, req); err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+	if got.Temperature != 0.1 || got.TopP != 0.95 || got.Seed == nil || *got.Seed != 3 {
+		t.Errorf("Unexpected merged request: %+v", got)
+	}
+	if got.ToolChoice == nil || got.ToolChoice.Mode != domain.ToolChoiceAuto {
+		t.Errorf("Expected provider-wide tool choice, got %+v", got.ToolChoice)
+	}
+	if req.Temperature != 0 || req.Seed != nil {
+		t.Error("Expected caller's request to be left unmodified")
+	}
+
+	// Aliases match like model IDs
+	if _, err := svc.Complete(context.Background(), domain.LLMProviderAnthropic, &domain.LLMRequest{Model: "sonnet"}); err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+	if got.Temperature != 0.1 {
+		t.Errorf("Expected alias params, got temperature %v", got.Temperature)
+	}
+
+	// Other providers are untouched
+	if _, err := svc.Complete(context.Background(), domain.LLMProviderOpenAI, &domain.LLMRequest{Model: "gpt-4o"}); err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+	if got.Temperature != 0 || got.ToolChoice != nil {
+		t.Errorf("Expected no params for openai, got %+v", got)
+	}
+}
+
+func TestNewParamsService_InvalidParams(t *testing.T) {
+	_, err := NewParamsService(&mockProviderClient{}, map[string]config.LLMModelConfig{
+		"openai/gpt-4o": {Params: map[string]any{"top_k": "many"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "openai/gpt-4o") {
+		t.Errorf("Expected error naming the model, got %v", err)
+	}
+}
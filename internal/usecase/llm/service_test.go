@@ -132,7 +132,7 @@ func TestComplete_Success(t *testing.T) {
 		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
 			return &domain.LLMResponse{
 				Content:      "Hello from " + string(provider),
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 			}, nil
 		},
 	}
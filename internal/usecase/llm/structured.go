@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/jsonschema"
+)
+
+// ErrStructuredOutput is returned when a response cannot be coerced into valid JSON
+// for the requested schema, even after a repair attempt.
+var ErrStructuredOutput = errors.New("structured output validation failed")
+
+// StructuredResult is a schema-validated completion.
+type StructuredResult struct {
+	Response *domain.LLMResponse // Final provider response
+	JSON     json.RawMessage     // Extracted, validated JSON document
+	Value    any                 // Decoded document (map[string]any, []any, ...)
+	Repaired bool                // True if the repair retry was needed
+}
+
+// CompleteStructured performs a completion with req.ResponseFormat set to a JSON mode,
+// validates the response against the requested schema, and retries once with the
+// validation errors fed back to the model if the first answer is invalid.
+func CompleteStructured(ctx context.Context, svc domain.LLMService, provider domain.LLMProvider, req *domain.LLMRequest) (*StructuredResult, error) {
+	if !req.ResponseFormat.IsJSON() {
+		return nil, fmt.Errorf("structured completion requires a JSON response format")
+	}
+
+	resp, err := svc.Complete(ctx, provider, req)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, value, verr := validateStructured(req.ResponseFormat, resp.Content)
+	if verr == nil {
+		return &StructuredResult{Response: resp, JSON: raw, Value: value}, nil
+	}
+
+	// One repair attempt: show the model its output and what was wrong with it.
+	repairReq := *req
+	repairReq.Messages = append(append([]domain.Message{}, req.Messages...),
+		domain.Message{Role: "assistant", Content: resp.Content},
+		domain.Message{Role: "user", Content: repairPrompt(verr)},
+	)
+
+	resp, err = svc.Complete(ctx, provider, &repairReq)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, value, verr = validateStructured(req.ResponseFormat, resp.Content)
+	if verr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStructuredOutput, verr)
+	}
+	return &StructuredResult{Response: resp, JSON: raw, Value: value, Repaired: true}, nil
+}
+
+// CompleteInto performs a structured completion and unmarshals the validated JSON into T.
+func CompleteInto[T any](ctx context.Context, svc domain.LLMService, provider domain.LLMProvider, req *domain.LLMRequest) (T, error) {
+	var out T
+	result, err := CompleteStructured(ctx, svc, provider, req)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(result.JSON, &out); err != nil {
+		return out, fmt.Errorf("%w: %v", ErrStructuredOutput, err)
+	}
+	return out, nil
+}
+
+// validateStructured extracts the JSON document from content and checks it against the format's schema.
+func validateStructured(format *domain.ResponseFormat, content string) (json.RawMessage, any, error) {
+	raw := json.RawMessage(ExtractJSON(content))
+	value, err := jsonschema.ValidateJSON(format.JSONSchema(), raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	return raw, value, nil
+}
+
+// ExtractJSON strips markdown code fences and surrounding prose from a model response,
+// returning the outermost JSON object or array it contains.
+func ExtractJSON(content string) string {
+	s := strings.TrimSpace(content)
+
+	if start := strings.Index(s, "```"); start != -1 {
+		body := s[start+3:]
+		if nl := strings.IndexByte(body, '\n'); nl != -1 {
+			body = body[nl+1:] // drop language tag line
+		}
+		if end := strings.Index(body, "```"); end != -1 {
+			body = body[:end]
+		}
+		s = strings.TrimSpace(body)
+	}
+
+	start := strings.IndexAny(s, "{[")
+	if start == -1 {
+		return s
+	}
+	closer := byte('}')
+	if s[start] == '[' {
+		closer = ']'
+	}
+	if end := strings.LastIndexByte(s, closer); end > start {
+		return s[start : end+1]
+	}
+	return s[start:]
+}
+
+func repairPrompt(err error) string {
+	var b strings.Builder
+	b.WriteString("Your previous response did not match the required JSON schema.\n")
+
+	var verrs jsonschema.ValidationErrors
+	if errors.As(err, &verrs) {
+		for _, v := range verrs {
+			b.WriteString("- " + v.Error() + "\n")
+		}
+	} else {
+		b.WriteString("- " + err.Error() + "\n")
+	}
+
+	b.WriteString("Respond again with only the corrected JSON document, no prose or code fences.")
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/domain"
+)
+
+var answerFormat = &domain.ResponseFormat{
+	Type: domain.ResponseFormatJSONSchema,
+	Name: "answer",
+	Schema: map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"answer": map[string]any{"type": "integer"}},
+		"required":   []string{"answer"},
+	},
+}
+
+func TestCompleteStructured_Valid(t *testing.T) {
+	client := &mockProviderClient{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			return &domain.LLMResponse{Content: "```json\n{\"answer\": 4}\n```"}, nil
+		},
+	}
+
+	result, err := CompleteStructured(context.Background(), client, domain.LLMProviderOpenAI, &domain.LLMRequest{
+		Messages:       []domain.Message{{Role: "user", Content: "2+2?"}},
+		ResponseFormat: answerFormat,
+	})
+	if err != nil {
+		t.Fatalf("CompleteStructured() unexpected error: %v", err)
+	}
+	if string(result.JSON) != `{"answer": 4}` {
+		t.Errorf("Expected fenced JSON to be extracted, got %s", result.JSON)
+	}
+	if result.Repaired {
+		t.Error("Expected no repair retry")
+	}
+	if client.completeCallCount != 1 {
+		t.Errorf("Expected 1 call, got %d", client.completeCallCount)
+	}
+}
+
+func TestCompleteStructured_RepairRetry(t *testing.T) {
+	var repairReq *domain.LLMRequest
+	client := &mockProviderClient{}
+	client.completeFunc = func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+		if client.completeCallCount == 1 {
+			return &domain.LLMResponse{Content: `{"answer": "four"}`}, nil
+		}
+		repairReq = req
+		return &domain.LLMResponse{Content: `{"answer": 4}`}, nil
+	}
+
+	type answer struct {
+		Answer int `json:"answer"`
+	}
+	got, err := CompleteInto[answer](context.Background(), client, domain.LLMProviderOpenAI, &domain.LLMRequest{
+		Messages:       []domain.Message{{Role: "user", Content: "2+2?"}},
+		ResponseFormat: answerFormat,
+	})
+	if err != nil {
+		t.Fatalf("CompleteInto() unexpected error: %v", err)
+	}
+	if got.Answer != 4 {
+		t.Errorf("Expected answer 4, got %d", got.Answer)
+	}
+
+	if repairReq == nil || len(repairReq.Messages) != 3 {
+		t.Fatalf("Expected repair request with 3 messages, got %+v", repairReq)
+	}
+	if repairReq.Messages[1].Role != "assistant" || repairReq.Messages[1].Content != `{"answer": "four"}` {
+		t.Errorf("Expected invalid output replayed as assistant turn, got %+v", repairReq.Messages[1])
+	}
+	if !strings.Contains(repairReq.Messages[2].Content, "$.answer: expected integer, got string") {
+		t.Errorf("Expected validation errors in repair prompt, got %q", repairReq.Messages[2].Content)
+	}
+}
+
+func TestCompleteStructured_RepairFails(t *testing.T) {
+	client := &mockProviderClient{
+		completeFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			return &domain.LLMResponse{Content: "I cannot answer that."}, nil
+		},
+	}
+
+	_, err := CompleteStructured(context.Background(), client, domain.LLMProviderOpenAI, &domain.LLMRequest{
+		Messages:       []domain.Message{{Role: "user", Content: "2+2?"}},
+		ResponseFormat: answerFormat,
+	})
+	if !errors.Is(err, ErrStructuredOutput) {
+		t.Errorf("Expected ErrStructuredOutput, got %v", err)
+	}
+	if client.completeCallCount != 2 {
+		t.Errorf("Expected exactly one retry, got %d calls", client.completeCallCount)
+	}
+}
+
+func TestCompleteStructured_RequiresJSONFormat(t *testing.T) {
+	client := &mockProviderClient{}
+
+	if _, err := CompleteStructured(context.Background(), client, domain.LLMProviderOpenAI, &domain.LLMRequest{}); err == nil {
+		t.Error("Expected error without a JSON response format")
+	}
+	if client.completeCallCount != 0 {
+		t.Error("Expected no provider call")
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{"a":1}`, `{"a":1}`},
+		{"Here you go:\n```json\n{\"a\":1}\n```", `{"a":1}`},
+		{`Sure! {"a":{"b":2}} Hope that helps.`, `{"a":{"b":2}}`},
+		{`[1,2,3]`, `[1,2,3]`},
+		{`no json`, `no json`},
+	}
+	for _, tt := range tests {
+		if got := ExtractJSON(tt.in); got != tt.want {
+			t.Errorf("ExtractJSON(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -22,6 +22,7 @@ func NewContextForker() *ContextForker {
 func (f *ContextForker) Fork(
 	ctx context.Context,
 	parentCtxID string,
+	user *domain.User,
 	parentHistory []domain.Message,
 	skillName string,
 	allowedTools []string,
@@ -58,6 +59,7 @@ func (f *ContextForker) Fork(
 	subagentCtx := &domain.SubagentContext{
 		ID:                  subagentID,
 		ParentContextID:     parentCtxID,
+		User:                user,
 		SkillName:           skillName,
 		AllowedTools:        copiedTools,
 		ResourceLimits:      resourceLimits,
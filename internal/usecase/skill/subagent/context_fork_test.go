@@ -94,9 +94,10 @@ func TestContextForker_Fork(t *testing.T) {
 			forker := NewContextForker()
 
 			parentCtxID := "parent-ctx-123"
+			user := &domain.User{ID: "user-1", Role: domain.RoleUser}
 			ctx := context.Background()
 
-			subagentCtx, err := forker.Fork(ctx, parentCtxID, tt.parentHistory, tt.skillName, tt.allowedTools, tt.resourceLimits)
+			subagentCtx, err := forker.Fork(ctx, parentCtxID, user, tt.parentHistory, tt.skillName, tt.allowedTools, tt.resourceLimits)
 
 			if tt.wantErr {
 				if err == nil {
@@ -114,6 +115,11 @@ func TestContextForker_Fork(t *testing.T) {
 				t.Fatal("Fork() returned nil context")
 			}
 
+			// The subagent runs tools as the parent conversation's user
+			if subagentCtx.User != user {
+				t.Errorf("User = %v, want %v", subagentCtx.User, user)
+			}
+
 			// Validate conversation history
 			if len(subagentCtx.ConversationHistory) != tt.wantHistoryLen {
 				t.Errorf("ConversationHistory length = %v, want %v", len(subagentCtx.ConversationHistory), tt.wantHistoryLen)
@@ -147,6 +153,7 @@ func TestContextForker_Fork_Isolation(t *testing.T) {
 	subagentCtx, err := forker.Fork(
 		ctx,
 		parentCtxID,
+		nil,
 		originalHistory,
 		"isolation-test-skill",
 		[]string{"read_file"},
@@ -213,6 +220,7 @@ func TestContextForker_Fork_ToolRestrictions(t *testing.T) {
 			subagentCtx, err := forker.Fork(
 				context.Background(),
 				"parent-tool-test",
+				nil,
 				history,
 				"tool-test-skill",
 				tt.allowedTools,
@@ -256,6 +264,7 @@ func TestContextForker_Fork_TimestampAndMetadata(t *testing.T) {
 	subagentCtx, err := forker.Fork(
 		context.Background(),
 		"parent-timestamp-test",
+		nil,
 		history,
 		"timestamp-test-skill",
 		[]string{"read_file"},
@@ -330,6 +339,7 @@ func TestContextForker_Fork_InvalidInputs(t *testing.T) {
 			_, err := forker.Fork(
 				context.Background(),
 				tt.parentCtxID,
+				nil,
 				history,
 				tt.skillName,
 				[]string{"read_file"},
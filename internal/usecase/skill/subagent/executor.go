@@ -13,9 +13,10 @@ type LLMService interface {
 	Chat(ctx context.Context, req domain.LLMRequest) (domain.LLMResponse, error)
 }
 
-// ToolExecutor defines the interface for executing tools
+// ToolExecutor defines the interface for executing tools on behalf of a user.
+// It is satisfied by the tool service, which enforces RBAC for the user.
 type ToolExecutor interface {
-	Execute(ctx context.Context, toolName string, args map[string]interface{}) (string, error)
+	ExecuteWithUser(ctx context.Context, user *domain.User, toolName string, params map[string]any) (*domain.ExecutionResult, error)
 }
 
 // SubagentExecutor implements autonomous multi-step subagent execution
@@ -118,7 +119,7 @@ func (e *SubagentExecutor) Execute(ctx context.Context, subagentCtx domain.Subag
 		stepNumber++
 
 		// Check if we're done (no tool calls)
-		if resp.FinishReason == "end_turn" || len(resp.ToolCalls) == 0 {
+		if resp.FinishReason == domain.FinishReasonStop || len(resp.ToolCalls) == 0 {
 			result.Output = resp.Content
 			return e.finalizeResult(result, domain.SubagentStatusComplete, "", tokensUsed, toolCallsMade, startTime), nil
 		}
@@ -137,8 +138,15 @@ func (e *SubagentExecutor) Execute(ctx context.Context, subagentCtx domain.Subag
 				return e.finalizeResult(result, domain.SubagentStatusError, errorMsg, tokensUsed, toolCallsMade, startTime), nil
 			}
 
+			// Tool calls run as the parent conversation's user; without one
+			// there is nobody to authorize them, so fail closed
+			if subagentCtx.User == nil {
+				errorMsg := fmt.Sprintf("tool '%s' not allowed: subagent has no user", toolCall.ToolName)
+				return e.finalizeResult(result, domain.SubagentStatusError, errorMsg, tokensUsed, toolCallsMade, startTime), nil
+			}
+
 			// Execute tool
-			toolResult, err := e.toolExecutor.Execute(ctx, toolCall.ToolName, toolCall.Arguments)
+			toolResult, err := e.toolExecutor.ExecuteWithUser(ctx, subagentCtx.User, toolCall.ToolName, toolCall.Arguments)
 			if err != nil {
 				errorMsg := fmt.Sprintf("tool execution error: %v", err)
 				return e.finalizeResult(result, domain.SubagentStatusError, errorMsg, tokensUsed, toolCallsMade, startTime), nil
@@ -146,10 +154,15 @@ func (e *SubagentExecutor) Execute(ctx context.Context, subagentCtx domain.Subag
 
 			toolCallsMade++
 
-			// Add tool result to conversation
+			// Add tool result to conversation; tool-level errors go back to
+			// the model so it can correct the call
+			content := fmt.Sprintf("Tool result from %s: %s", toolCall.ToolName, toolResult.Output)
+			if toolResult.Error != "" {
+				content = fmt.Sprintf("Tool error from %s: %s", toolCall.ToolName, toolResult.Error)
+			}
 			conversation = append(conversation, domain.Message{
 				Role:    "user",
-				Content: fmt.Sprintf("Tool result from %s: %s", toolCall.ToolName, toolResult),
+				Content: content,
 			})
 		}
 
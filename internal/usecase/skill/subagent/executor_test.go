@@ -31,27 +31,32 @@ func (m *mockLLMService) Chat(ctx context.Context, req domain.LLMRequest) (domai
 type mockToolExecutor struct {
 	results map[string]string
 	callLog []string
+	users   []*domain.User
 	err     error
 }
 
-func (m *mockToolExecutor) Execute(ctx context.Context, toolName string, args map[string]interface{}) (string, error) {
+func (m *mockToolExecutor) ExecuteWithUser(ctx context.Context, user *domain.User, toolName string, params map[string]any) (*domain.ExecutionResult, error) {
 	if m.err != nil {
-		return "", m.err
+		return nil, m.err
 	}
 	m.callLog = append(m.callLog, toolName)
+	m.users = append(m.users, user)
 	if result, ok := m.results[toolName]; ok {
-		return result, nil
+		return &domain.ExecutionResult{Output: result}, nil
 	}
-	return "mock result", nil
+	return &domain.ExecutionResult{Output: "mock result"}, nil
 }
 
+// testUser is the parent conversation's user in tests that make tool calls
+var testUser = &domain.User{ID: "user-1", Role: domain.RoleUser}
+
 // TestSubagentExecutor_Execute_SingleStep tests single-step execution
 func TestSubagentExecutor_Execute_SingleStep(t *testing.T) {
 	mockLLM := &mockLLMService{
 		responses: []domain.LLMResponse{
 			{
 				Content:      "Task completed successfully",
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage: domain.TokenUsage{
 					PromptTokens:     100,
 					CompletionTokens: 50,
@@ -66,6 +71,7 @@ func TestSubagentExecutor_Execute_SingleStep(t *testing.T) {
 	subagentCtx := domain.SubagentContext{
 		ID:              "test-subagent-1",
 		ParentContextID: "parent-1",
+		User:            testUser,
 		SkillName:       "test-skill",
 		AllowedTools:    []string{},
 		ResourceLimits:  domain.DefaultResourceLimits(),
@@ -127,7 +133,7 @@ func TestSubagentExecutor_Execute_MultiStep(t *testing.T) {
 		responses: []domain.LLMResponse{
 			{
 				Content:      "",
-				FinishReason: "tool_use",
+				FinishReason: domain.FinishReasonToolCalls,
 				ToolCalls: []domain.ToolCall{
 					{ToolName: "read_file", Arguments: map[string]interface{}{"path": "test.go"}},
 				},
@@ -135,7 +141,7 @@ func TestSubagentExecutor_Execute_MultiStep(t *testing.T) {
 			},
 			{
 				Content:      "",
-				FinishReason: "tool_use",
+				FinishReason: domain.FinishReasonToolCalls,
 				ToolCalls: []domain.ToolCall{
 					{ToolName: "grep", Arguments: map[string]interface{}{"pattern": "test"}},
 				},
@@ -143,7 +149,7 @@ func TestSubagentExecutor_Execute_MultiStep(t *testing.T) {
 			},
 			{
 				Content:      "Analysis complete: Found 3 test cases",
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage:        domain.TokenUsage{PromptTokens: 150, CompletionTokens: 70, TotalTokens: 220},
 			},
 		},
@@ -154,6 +160,7 @@ func TestSubagentExecutor_Execute_MultiStep(t *testing.T) {
 	subagentCtx := domain.SubagentContext{
 		ID:              "test-subagent-multi",
 		ParentContextID: "parent-1",
+		User:            testUser,
 		SkillName:       "analysis-skill",
 		AllowedTools:    []string{"read_file", "grep"},
 		ResourceLimits:  domain.DefaultResourceLimits(),
@@ -188,10 +195,15 @@ func TestSubagentExecutor_Execute_MultiStep(t *testing.T) {
 		t.Errorf("StepResults length = %v, want 3", len(result.StepResults))
 	}
 
-	// Verify tool calls were made
+	// Verify tool calls were made as the parent conversation's user
 	if len(mockTools.callLog) != 2 {
 		t.Errorf("Tool calls made = %v, want 2", len(mockTools.callLog))
 	}
+	for _, user := range mockTools.users {
+		if user != testUser {
+			t.Errorf("Tool called as %v, want %v", user, testUser)
+		}
+	}
 }
 
 // TestSubagentExecutor_Execute_ToolRestriction tests tool restriction enforcement
@@ -207,7 +219,7 @@ func TestSubagentExecutor_Execute_ToolRestriction(t *testing.T) {
 		responses: []domain.LLMResponse{
 			{
 				Content:      "",
-				FinishReason: "tool_use",
+				FinishReason: domain.FinishReasonToolCalls,
 				ToolCalls: []domain.ToolCall{
 					{ToolName: "write_file", Arguments: map[string]interface{}{"path": "test.go"}},
 				},
@@ -221,6 +233,7 @@ func TestSubagentExecutor_Execute_ToolRestriction(t *testing.T) {
 	subagentCtx := domain.SubagentContext{
 		ID:              "test-subagent-restricted",
 		ParentContextID: "parent-1",
+		User:            testUser,
 		SkillName:       "restricted-skill",
 		AllowedTools:    []string{"read_file", "grep"}, // write_file NOT allowed
 		ResourceLimits:  domain.DefaultResourceLimits(),
@@ -259,7 +272,7 @@ func TestSubagentExecutor_Execute_TokenLimit(t *testing.T) {
 		responses: []domain.LLMResponse{
 			{
 				Content:      "Step 1",
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage:        domain.TokenUsage{PromptTokens: 500, CompletionTokens: 600, TotalTokens: 1100},
 			},
 		},
@@ -270,6 +283,7 @@ func TestSubagentExecutor_Execute_TokenLimit(t *testing.T) {
 	subagentCtx := domain.SubagentContext{
 		ID:              "test-subagent-limit",
 		ParentContextID: "parent-1",
+		User:            testUser,
 		SkillName:       "test-skill",
 		AllowedTools:    []string{},
 		ResourceLimits: domain.ResourceLimits{
@@ -315,7 +329,7 @@ func TestSubagentExecutor_Execute_ToolCallLimit(t *testing.T) {
 	for i := 0; i < 6; i++ {
 		responses[i] = domain.LLMResponse{
 			Content:      "",
-			FinishReason: "tool_use",
+			FinishReason: domain.FinishReasonToolCalls,
 			ToolCalls: []domain.ToolCall{
 				{ToolName: "read_file", Arguments: map[string]interface{}{"path": "test.go"}},
 			},
@@ -332,6 +346,7 @@ func TestSubagentExecutor_Execute_ToolCallLimit(t *testing.T) {
 	subagentCtx := domain.SubagentContext{
 		ID:              "test-subagent-tool-limit",
 		ParentContextID: "parent-1",
+		User:            testUser,
 		SkillName:       "test-skill",
 		AllowedTools:    []string{"read_file"},
 		ResourceLimits: domain.ResourceLimits{
@@ -369,7 +384,7 @@ func TestSubagentExecutor_Execute_ContextCancellation(t *testing.T) {
 		responses: []domain.LLMResponse{
 			{
 				Content:      "Never reached",
-				FinishReason: "end_turn",
+				FinishReason: domain.FinishReasonStop,
 				Usage:        domain.TokenUsage{PromptTokens: 100, CompletionTokens: 50},
 			},
 		},
@@ -380,6 +395,7 @@ func TestSubagentExecutor_Execute_ContextCancellation(t *testing.T) {
 	subagentCtx := domain.SubagentContext{
 		ID:              "test-subagent-cancel",
 		ParentContextID: "parent-1",
+		User:            testUser,
 		SkillName:       "test-skill",
 		AllowedTools:    []string{},
 		ResourceLimits:  domain.DefaultResourceLimits(),
@@ -413,6 +429,7 @@ func TestSubagentExecutor_Execute_LLMError(t *testing.T) {
 	subagentCtx := domain.SubagentContext{
 		ID:              "test-subagent-error",
 		ParentContextID: "parent-1",
+		User:            testUser,
 		SkillName:       "test-skill",
 		AllowedTools:    []string{},
 		ResourceLimits:  domain.DefaultResourceLimits(),
@@ -446,7 +463,7 @@ func TestSubagentExecutor_Execute_MaxIterations(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		responses[i] = domain.LLMResponse{
 			Content:      "",
-			FinishReason: "tool_use",
+			FinishReason: domain.FinishReasonToolCalls,
 			ToolCalls: []domain.ToolCall{
 				{ToolName: "read_file", Arguments: map[string]interface{}{"path": "test.go"}},
 			},
@@ -469,6 +486,7 @@ func TestSubagentExecutor_Execute_MaxIterations(t *testing.T) {
 	subagentCtx := domain.SubagentContext{
 		ID:              "test-subagent-iterations",
 		ParentContextID: "parent-1",
+		User:            testUser,
 		SkillName:       "test-skill",
 		AllowedTools:    []string{"read_file"},
 		ResourceLimits:  domain.DefaultResourceLimits(),
@@ -496,3 +514,88 @@ func TestSubagentExecutor_Execute_MaxIterations(t *testing.T) {
 		t.Error("Should have some step results")
 	}
 }
+
+// TestSubagentExecutor_Execute_NoUser tests that tool calls fail closed without a user
+func TestSubagentExecutor_Execute_NoUser(t *testing.T) {
+	mockTools := &mockToolExecutor{}
+	mockLLM := &mockLLMService{
+		responses: []domain.LLMResponse{
+			{
+				FinishReason: domain.FinishReasonToolCalls,
+				ToolCalls: []domain.ToolCall{
+					{ToolName: "read_file", Arguments: map[string]interface{}{"path": "test.go"}},
+				},
+				Usage: domain.TokenUsage{TotalTokens: 100},
+			},
+		},
+	}
+
+	executor := NewSubagentExecutor(mockLLM, mockTools)
+
+	subagentCtx := domain.SubagentContext{
+		ID:              "test-subagent-no-user",
+		ParentContextID: "parent-1",
+		SkillName:       "test-skill",
+		ResourceLimits:  domain.DefaultResourceLimits(),
+		ConversationHistory: []domain.Message{
+			{Role: "user", Content: "Task"},
+		},
+		CreatedAt: time.Now(),
+		Metadata:  make(map[string]interface{}),
+	}
+
+	result, err := executor.Execute(context.Background(), subagentCtx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Status != domain.SubagentStatusError {
+		t.Errorf("Status = %v, want %v", result.Status, domain.SubagentStatusError)
+	}
+	if len(mockTools.callLog) != 0 {
+		t.Errorf("Tool calls made = %v, want 0", len(mockTools.callLog))
+	}
+}
+
+// TestSubagentExecutor_Execute_ToolDenied tests that a permission denial stops the subagent
+func TestSubagentExecutor_Execute_ToolDenied(t *testing.T) {
+	mockTools := &mockToolExecutor{err: domain.ErrInsufficientPermissions}
+	mockLLM := &mockLLMService{
+		responses: []domain.LLMResponse{
+			{
+				FinishReason: domain.FinishReasonToolCalls,
+				ToolCalls: []domain.ToolCall{
+					{ToolName: "shell", Arguments: map[string]interface{}{"command": "ls"}},
+				},
+				Usage: domain.TokenUsage{TotalTokens: 100},
+			},
+		},
+	}
+
+	executor := NewSubagentExecutor(mockLLM, mockTools)
+
+	subagentCtx := domain.SubagentContext{
+		ID:              "test-subagent-denied",
+		ParentContextID: "parent-1",
+		User:            &domain.User{ID: "guest-1", Role: domain.RoleGuest},
+		SkillName:       "test-skill",
+		ResourceLimits:  domain.DefaultResourceLimits(),
+		ConversationHistory: []domain.Message{
+			{Role: "user", Content: "Task"},
+		},
+		CreatedAt: time.Now(),
+		Metadata:  make(map[string]interface{}),
+	}
+
+	result, err := executor.Execute(context.Background(), subagentCtx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Status != domain.SubagentStatusError {
+		t.Errorf("Status = %v, want %v", result.Status, domain.SubagentStatusError)
+	}
+	if result.ToolCallsMade != 0 {
+		t.Errorf("ToolCallsMade = %v, want 0", result.ToolCallsMade)
+	}
+}
@@ -0,0 +1,59 @@
+package tool
+
+import (
+	"errors"
+	"strings"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/jsonschema"
+)
+
+// ArgumentError reports tool arguments that do not match the tool's input
+// schema. Its message lists every violation by JSON path so the LLM can
+// correct the call on its next iteration. It matches domain.ErrInvalidInput.
+type ArgumentError struct {
+	Tool       string
+	Violations jsonschema.ValidationErrors
+}
+
+// Error implements the error interface.
+func (e *ArgumentError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid arguments for tool '" + e.Tool + "':\n")
+	for _, v := range e.Violations {
+		b.WriteString("- " + v.Error() + "\n")
+	}
+	b.WriteString("Call the tool again with arguments that match its input schema.")
+	return b.String()
+}
+
+// Unwrap makes ArgumentError match domain.ErrInvalidInput.
+func (e *ArgumentError) Unwrap() error {
+	return domain.ErrInvalidInput
+}
+
+// prepareArguments applies schema defaults and safe coercions to params, then
+// validates the result against the tool's input schema.
+func prepareArguments(tool domain.Tool, params map[string]any) (map[string]any, error) {
+	schema := tool.InputSchema()
+	if params == nil {
+		params = map[string]any{}
+	}
+	if len(schema) == 0 {
+		return params, nil
+	}
+
+	coerced, ok := jsonschema.Coerce(schema, params).(map[string]any)
+	if !ok {
+		coerced = params
+	}
+
+	if err := jsonschema.Validate(schema, coerced); err != nil {
+		var violations jsonschema.ValidationErrors
+		if !errors.As(err, &violations) {
+			violations = jsonschema.ValidationErrors{{Path: "$", Message: err.Error()}}
+		}
+		return nil, &ArgumentError{Tool: tool.Name(), Violations: violations}
+	}
+	return coerced, nil
+}
@@ -182,6 +182,20 @@ func (s *CodingAgentSkill) validateParams(params map[string]any) (string, string
 		return "", "", fmt.Errorf("unsupported tool: %s", tool)
 	}
 
+	// Restrict to the tools the admin allowed, if configured
+	if allowed, ok := s.config.Params["allowed_tools"].([]any); ok && len(allowed) > 0 {
+		permitted := false
+		for _, a := range allowed {
+			if a == tool {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			return "", "", fmt.Errorf("tool %s is not enabled", tool)
+		}
+	}
+
 	return tool, task, nil
 }
 
@@ -0,0 +1,29 @@
+package tool
+
+import (
+	"context"
+
+	"nuimanbot/internal/domain"
+)
+
+type userContextKey struct{}
+
+// ContextWithUser returns a context recording the user a tool runs for.
+// ExecuteWithUser sets it so tools and audit events can attribute actions.
+func ContextWithUser(ctx context.Context, user *domain.User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the user a tool runs for, or nil if unknown.
+func UserFromContext(ctx context.Context) *domain.User {
+	user, _ := ctx.Value(userContextKey{}).(*domain.User)
+	return user
+}
+
+// userID returns the ID of the user in ctx, or "" if unknown.
+func userID(ctx context.Context) string {
+	if user := UserFromContext(ctx); user != nil {
+		return user.ID
+	}
+	return ""
+}
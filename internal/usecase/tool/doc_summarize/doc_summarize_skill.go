@@ -4,14 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/fetch"
+	"nuimanbot/internal/usecase/llm"
 )
 
 const (
@@ -25,7 +26,7 @@ const (
 type DocSummarizeSkill struct {
 	config     domain.ToolConfig
 	llmService domain.LLMService
-	httpClient *http.Client
+	fetcher    *fetch.Service
 }
 
 // SummaryOutput represents the structured summary output
@@ -37,22 +38,45 @@ type SummaryOutput struct {
 	Timestamp string   `json:"timestamp"`
 }
 
-// NewDocSummarizeSkill creates a new DocSummarizeSkill instance
+// summaryResponse is the structured answer requested from the LLM.
+type summaryResponse struct {
+	Summary   string   `json:"summary"`
+	KeyTopics []string `json:"key_topics"`
+}
+
+// summarySchema is the JSON schema summaryResponse must satisfy.
+var summarySchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"summary": map[string]any{
+			"type":        "string",
+			"description": "The summary of the document",
+		},
+		"key_topics": map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+			"description": "The main topics the document covers, a few words each",
+		},
+	},
+	"required":             []string{"summary", "key_topics"},
+	"additionalProperties": false,
+}
+
+// NewDocSummarizeSkill creates a new DocSummarizeSkill instance. URLs are
+// read through fetcher; nil uses a fetch service with default options.
 func NewDocSummarizeSkill(
 	config domain.ToolConfig,
 	llmService domain.LLMService,
-	httpClient *http.Client,
+	fetcher *fetch.Service,
 ) *DocSummarizeSkill {
-	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: defaultTimeout,
-		}
+	if fetcher == nil {
+		fetcher = fetch.NewService(fetch.Options{})
 	}
 
 	return &DocSummarizeSkill{
 		config:     config,
 		llmService: llmService,
-		httpClient: httpClient,
+		fetcher:    fetcher,
 	}
 }
 
@@ -119,13 +143,13 @@ func (s *DocSummarizeSkill) Execute(ctx context.Context, params map[string]any)
 		return nil, fmt.Errorf("failed to generate summary: %w", err)
 	}
 
-	output := s.formatOutput(summary, source, params)
+	output := s.formatOutput(summary, source)
 
 	return &domain.ExecutionResult{
 		Output: output,
 		Metadata: map[string]any{
 			"source":     source,
-			"word_count": s.countWords(summary),
+			"word_count": s.countWords(summary.Summary),
 		},
 	}, nil
 }
@@ -184,6 +208,14 @@ func (s *DocSummarizeSkill) getAllowedDomains() []string {
 	return nil
 }
 
+// maxDocumentSize returns the "max_document_size" config param in bytes.
+func (s *DocSummarizeSkill) maxDocumentSize() int64 {
+	if size, ok := s.config.Params["max_document_size"].(float64); ok && size > 0 {
+		return int64(size)
+	}
+	return defaultMaxDocSize
+}
+
 // fetchContent fetches content from source (file or URL)
 func (s *DocSummarizeSkill) fetchContent(ctx context.Context, source string) (string, error) {
 	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
@@ -192,42 +224,34 @@ func (s *DocSummarizeSkill) fetchContent(ctx context.Context, source string) (st
 	return s.readFile(source)
 }
 
-// fetchURL fetches content from HTTP/HTTPS URL
+// fetchURL fetches a document from an HTTP/HTTPS URL and extracts its text
 func (s *DocSummarizeSkill) fetchURL(ctx context.Context, urlStr string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		return "", err
+	req := fetch.Request{
+		URL:      urlStr,
+		MaxBytes: s.maxDocumentSize(),
+		Timeout:  defaultTimeout,
 	}
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	if timeout, ok := s.config.Params["timeout"].(float64); ok && timeout > 0 {
+		req.Timeout = time.Duration(timeout) * time.Second
 	}
 
-	// Read with size limit
-	limitedReader := io.LimitReader(resp.Body, int64(defaultMaxDocSize))
-	content, err := io.ReadAll(limitedReader)
+	doc, err := s.fetcher.Fetch(ctx, req)
 	if err != nil {
 		return "", err
 	}
-
-	return string(content), nil
+	return doc.Text, nil
 }
 
-// readFile reads content from local file
+// readFile reads content from local file. HTML and PDF files are reduced to
+// their text; other files are returned as is.
 func (s *DocSummarizeSkill) readFile(path string) (string, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		return "", fmt.Errorf("file not found or inaccessible: %w", err)
 	}
 
-	if info.Size() > int64(defaultMaxDocSize) {
-		return "", fmt.Errorf("file too large: %d bytes (max %d)", info.Size(), defaultMaxDocSize)
+	if maxSize := s.maxDocumentSize(); info.Size() > maxSize {
+		return "", fmt.Errorf("file too large: %d bytes (max %d)", info.Size(), maxSize)
 	}
 
 	content, err := os.ReadFile(path)
@@ -235,11 +259,27 @@ func (s *DocSummarizeSkill) readFile(path string) (string, error) {
 		return "", err
 	}
 
-	return string(content), nil
+	// Charsets of HTML files come from their <meta> tags
+	contentType := ""
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		contentType = "text/html"
+	case ".pdf":
+		contentType = "application/pdf"
+	default:
+		return string(content), nil
+	}
+
+	doc, err := fetch.Extract(content, contentType, path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text, nil
 }
 
-// generateSummary generates a summary using the LLM service
-func (s *DocSummarizeSkill) generateSummary(ctx context.Context, content string, params map[string]any) (string, error) {
+// generateSummary generates a summary and its key topics using the LLM service.
+// The response is validated against summarySchema, with one repair retry.
+func (s *DocSummarizeSkill) generateSummary(ctx context.Context, content string, params map[string]any) (summaryResponse, error) {
 	// Truncate content if too long
 	if len(content) > maxContentLength {
 		content = content[:maxContentLength] + "..."
@@ -268,15 +308,15 @@ func (s *DocSummarizeSkill) generateSummary(ctx context.Context, content string,
 		},
 		MaxTokens:   2000,
 		Temperature: 0.3,
+		ResponseFormat: &domain.ResponseFormat{
+			Type:   domain.ResponseFormatJSONSchema,
+			Name:   "document_summary",
+			Schema: summarySchema,
+		},
 	}
 
 	// Use default provider (Anthropic) - can be configured later
-	resp, err := s.llmService.Complete(ctx, domain.LLMProviderAnthropic, llmReq)
-	if err != nil {
-		return "", err
-	}
-
-	return resp.Content, nil
+	return llm.CompleteInto[summaryResponse](ctx, s.llmService, domain.LLMProviderAnthropic, llmReq)
 }
 
 // buildSummaryPrompt builds the prompt for the LLM
@@ -287,17 +327,18 @@ func (s *DocSummarizeSkill) buildSummaryPrompt(content string, maxWords int, foc
 		prompt += fmt.Sprintf(", focusing on: %s", focus)
 	}
 
-	prompt += ".\n\nDocument:\n" + content
+	prompt += ", and list its key topics.\n\nDocument:\n" + content
 
 	return prompt
 }
 
 // formatOutput formats the summary output as JSON
-func (s *DocSummarizeSkill) formatOutput(summary, source string, params map[string]any) string {
+func (s *DocSummarizeSkill) formatOutput(summary summaryResponse, source string) string {
 	output := SummaryOutput{
-		Summary:   summary,
+		Summary:   summary.Summary,
 		Source:    source,
-		WordCount: s.countWords(summary),
+		WordCount: s.countWords(summary.Summary),
+		KeyTopics: summary.KeyTopics,
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
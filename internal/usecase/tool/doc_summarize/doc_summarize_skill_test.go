@@ -2,9 +2,15 @@ package doc_summarize
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/fetch"
 	"nuimanbot/internal/usecase/tool/testutil"
 
 	"github.com/stretchr/testify/assert"
@@ -59,7 +65,7 @@ func TestDocSummarizeSkill_Execute_LocalFile(t *testing.T) {
 	mockLLM := &MockLLMService{
 		CompleteFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
 			return &domain.LLMResponse{
-				Content: "This is a test document summary.",
+				Content: `{"summary": "This is a test document summary.", "key_topics": []}`,
 			}, nil
 		},
 	}
@@ -94,7 +100,7 @@ func TestDocSummarizeSkill_Execute_HTTPSAllowedDomain(t *testing.T) {
 	mockLLM := &MockLLMService{
 		CompleteFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
 			return &domain.LLMResponse{
-				Content: "GitHub repository documentation summary.",
+				Content: `{"summary": "GitHub repository documentation summary.", "key_topics": []}`,
 			}, nil
 		},
 	}
@@ -156,7 +162,7 @@ func TestDocSummarizeSkill_Execute_WithFocus(t *testing.T) {
 			}
 			assert.True(t, found)
 			return &domain.LLMResponse{
-				Content: "Security-focused summary of the document.",
+				Content: `{"summary": "Security-focused summary of the document.", "key_topics": []}`,
 			}, nil
 		},
 	}
@@ -186,7 +192,7 @@ func TestDocSummarizeSkill_Execute_WithMaxWords(t *testing.T) {
 		CompleteFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
 			// Just verify Complete is called
 			return &domain.LLMResponse{
-				Content: "Brief summary.",
+				Content: `{"summary": "Brief summary.", "key_topics": []}`,
 			}, nil
 		},
 	}
@@ -211,6 +217,105 @@ func TestDocSummarizeSkill_Execute_WithMaxWords(t *testing.T) {
 	}
 }
 
+func TestDocSummarizeSkill_Execute_URLExtractsText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><script>trackVisitor()</script></head>
+			<body><nav>Docs | Blog</nav><main><h1>Install</h1><p>Run the installer and restart.</p></main></body></html>`))
+	}))
+	defer server.Close()
+
+	var prompt string
+	mockLLM := &MockLLMService{
+		CompleteFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			prompt = req.Messages[0].Content
+			return &domain.LLMResponse{Content: `{"summary": "Install and restart.", "key_topics": []}`}, nil
+		},
+	}
+
+	fetcher := fetch.NewService(fetch.Options{AllowPrivateNetworks: true})
+	skill := NewDocSummarizeSkill(domain.ToolConfig{Enabled: true}, mockLLM, fetcher)
+
+	_, err := skill.Execute(context.Background(), map[string]any{"source": server.URL + "/install"})
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "Install\n\nRun the installer and restart.")
+	assert.NotContains(t, prompt, "trackVisitor")
+	assert.NotContains(t, prompt, "Blog")
+}
+
+func TestDocSummarizeSkill_Execute_URLTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(make([]byte, 2048))
+	}))
+	defer server.Close()
+
+	config := domain.ToolConfig{
+		Enabled: true,
+		Params:  map[string]interface{}{"max_document_size": float64(1024)},
+	}
+	fetcher := fetch.NewService(fetch.Options{AllowPrivateNetworks: true, IgnoreRobots: true})
+	skill := NewDocSummarizeSkill(config, &MockLLMService{}, fetcher)
+
+	_, err := skill.Execute(context.Background(), map[string]any{"source": server.URL + "/big.txt"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, fetch.ErrTooLarge)
+}
+
+func TestDocSummarizeSkill_Execute_LocalHTMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guide.html")
+	require.NoError(t, os.WriteFile(path, []byte(`<html><body><footer>Legal</footer><article><p>Configure the cache first.</p></article></body></html>`), 0o600))
+
+	var prompt string
+	mockLLM := &MockLLMService{
+		CompleteFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			prompt = req.Messages[0].Content
+			return &domain.LLMResponse{Content: `{"summary": "Configure the cache.", "key_topics": []}`}, nil
+		},
+	}
+
+	skill := NewDocSummarizeSkill(domain.ToolConfig{Enabled: true}, mockLLM, nil)
+
+	_, err := skill.Execute(context.Background(), map[string]any{"source": path})
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "Document:\nConfigure the cache first.")
+	assert.NotContains(t, prompt, "Legal")
+}
+
+func TestDocSummarizeSkill_Execute_StructuredSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.md")
+	require.NoError(t, os.WriteFile(path, []byte("Rotate the keys monthly. Audit access weekly."), 0o600))
+
+	var requests []*domain.LLMRequest
+	mockLLM := &MockLLMService{
+		CompleteFunc: func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+			requests = append(requests, req)
+			if len(requests) == 1 {
+				// Prose instead of JSON is repaired on the retry
+				return &domain.LLMResponse{Content: "Keys rotate monthly."}, nil
+			}
+			return &domain.LLMResponse{Content: `{"summary": "Keys rotate monthly.", "key_topics": ["key rotation", "access audits"]}`}, nil
+		},
+	}
+
+	skill := NewDocSummarizeSkill(domain.ToolConfig{Enabled: true}, mockLLM, nil)
+
+	result, err := skill.Execute(context.Background(), map[string]any{"source": path})
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, domain.ResponseFormatJSONSchema, requests[0].ResponseFormat.Type)
+
+	var output SummaryOutput
+	require.NoError(t, json.Unmarshal([]byte(result.Output), &output))
+	assert.Equal(t, "Keys rotate monthly.", output.Summary)
+	assert.Equal(t, []string{"key rotation", "access audits"}, output.KeyTopics)
+	assert.Equal(t, 3, output.WordCount)
+}
+
 // MockLLMService for testing
 type MockLLMService struct {
 	CompleteFunc func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error)
@@ -220,7 +325,7 @@ func (m *MockLLMService) Complete(ctx context.Context, provider domain.LLMProvid
 	if m.CompleteFunc != nil {
 		return m.CompleteFunc(ctx, provider, req)
 	}
-	return &domain.LLMResponse{Content: "Mock summary"}, nil
+	return &domain.LLMResponse{Content: `{"summary": "Mock summary", "key_topics": []}`}, nil
 }
 
 func (m *MockLLMService) Stream(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (<-chan domain.StreamChunk, error) {
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// limitsEnv carries resource limits to the re-executed binary; see
+// applyLimits.
+const limitsEnv = "_NUIMANBOT_EXEC_LIMITS"
+
+func init() {
+	if spec, ok := os.LookupEnv(limitsEnv); ok {
+		execWithLimits(spec)
+	}
+}
+
+// applyLimits makes cmd start under limits. Go cannot run code in the child
+// between fork and exec, so the command is started as this binary, which sets
+// the limits on itself in init and then execs the real command. The limits
+// are in place before the command's first instruction and are inherited by
+// anything it spawns.
+func applyLimits(cmd *exec.Cmd, limits ResourceLimits) error {
+	if limits == (ResourceLimits{}) || cmd.Err != nil {
+		return nil // cmd.Err is reported by Start
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(env, fmt.Sprintf("%s=%d %d %d %d",
+		limitsEnv, limits.CPUSeconds, limits.MemoryBytes, limits.FileSizeBytes, limits.Processes))
+	cmd.Args = append([]string{"nuimanbot-exec", cmd.Path}, cmd.Args...)
+	cmd.Path = "/proc/self/exe"
+	return nil
+}
+
+// execWithLimits runs in the re-executed binary: it sets the limits in spec
+// and replaces itself with the command in os.Args[1:] (its path, then its
+// arguments). It never returns.
+func execWithLimits(spec string) {
+	err := func() error {
+		var limits ResourceLimits
+		if _, err := fmt.Sscanf(spec, "%d %d %d %d",
+			&limits.CPUSeconds, &limits.MemoryBytes, &limits.FileSizeBytes, &limits.Processes); err != nil {
+			return fmt.Errorf("invalid limits %q: %w", spec, err)
+		}
+		if len(os.Args) < 3 {
+			return fmt.Errorf("missing command")
+		}
+		if err := setLimits(limits); err != nil {
+			return err
+		}
+
+		env := make([]string, 0, len(os.Environ()))
+		for _, kv := range os.Environ() {
+			if !strings.HasPrefix(kv, limitsEnv+"=") {
+				env = append(env, kv)
+			}
+		}
+		return syscall.Exec(os.Args[1], os.Args[2:], env)
+	}()
+	fmt.Fprintf(os.Stderr, "nuimanbot: %v\n", err)
+	os.Exit(126)
+}
+
+// setLimits sets resource limits on the current process.
+func setLimits(limits ResourceLimits) error {
+	for _, l := range []struct {
+		resource int
+		value    uint64
+		name     string
+	}{
+		{unix.RLIMIT_CPU, limits.CPUSeconds, "cpu"},
+		{unix.RLIMIT_AS, limits.MemoryBytes, "memory"},
+		{unix.RLIMIT_FSIZE, limits.FileSizeBytes, "file size"},
+		{unix.RLIMIT_NPROC, limits.Processes, "processes"},
+	} {
+		if l.value == 0 {
+			continue
+		}
+		rlimit := unix.Rlimit{Cur: l.value, Max: l.value}
+		if err := unix.Setrlimit(l.resource, &rlimit); err != nil {
+			return fmt.Errorf("failed to set %s limit: %w", l.name, err)
+		}
+	}
+	return nil
+}
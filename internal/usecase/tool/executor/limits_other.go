@@ -0,0 +1,16 @@
+//go:build !linux
+
+package executor
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// applyLimits fails if any limit is set: resource limits are Linux only.
+func applyLimits(cmd *exec.Cmd, limits ResourceLimits) error {
+	if limits != (ResourceLimits{}) {
+		return fmt.Errorf("resource limits are only supported on Linux")
+	}
+	return nil
+}
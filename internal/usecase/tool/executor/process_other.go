@@ -0,0 +1,9 @@
+//go:build !unix
+
+package executor
+
+import "os/exec"
+
+// isolateProcessGroup is a no-op where process groups are unavailable;
+// cancellation kills only the direct child.
+func isolateProcessGroup(cmd *exec.Cmd, newSession bool) {}
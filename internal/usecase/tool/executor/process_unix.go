@@ -0,0 +1,22 @@
+//go:build unix
+
+package executor
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// isolateProcessGroup starts the command in its own process group and makes
+// cancellation kill the whole group, so grandchildren don't outlive it.
+func isolateProcessGroup(cmd *exec.Cmd, newSession bool) {
+	if newSession {
+		// A new session is also a new process group (needed for a PTY)
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	} else {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package executor
+
+import (
+	"fmt"
+	"os"
+)
+
+// openPTY is only implemented on Linux; elsewhere PTY mode falls back to pipes.
+func openPTY() (master, tty *os.File, err error) {
+	return nil, nil, fmt.Errorf("PTY mode is only supported on Linux")
+}
@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxOutputBytes is the default cap on captured output per stream.
+const DefaultMaxOutputBytes = 1024 * 1024
+
+// ringBuffer is an io.Writer that keeps the most recent max bytes written
+// to it. It is safe for concurrent use, so background output can be read
+// while the command is still writing.
+type ringBuffer struct {
+	mu      sync.Mutex
+	buf     []byte
+	max     int
+	dropped int64
+}
+
+func newRingBuffer(max int) *ringBuffer {
+	if max <= 0 {
+		max = DefaultMaxOutputBytes
+	}
+	return &ringBuffer{max: max}
+}
+
+// Write appends p, discarding the oldest bytes beyond the cap.
+func (b *ringBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(p) >= b.max {
+		b.dropped += int64(len(b.buf) + len(p) - b.max)
+		b.buf = append(b.buf[:0], p[len(p)-b.max:]...)
+		return len(p), nil
+	}
+
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.dropped += int64(over)
+		// Copy down rather than reslice so the backing array stays bounded
+		b.buf = b.buf[:copy(b.buf, b.buf[over:])]
+	}
+	return len(p), nil
+}
+
+// String returns the retained output, noting how much was discarded.
+func (b *ringBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.dropped == 0 {
+		return string(b.buf)
+	}
+	// The cut may fall inside a multi-byte character
+	return fmt.Sprintf("[... %d bytes of earlier output discarded ...]\n%s",
+		b.dropped, strings.ToValidUTF8(string(b.buf), ""))
+}
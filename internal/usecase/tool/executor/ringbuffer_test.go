@@ -0,0 +1,22 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingBuffer(t *testing.T) {
+	b := newRingBuffer(10)
+
+	b.Write([]byte("hello"))
+	assert.Equal(t, "hello", b.String())
+
+	b.Write([]byte(" world"))
+	assert.Equal(t, "[... 1 bytes of earlier output discarded ...]\nello world", b.String())
+
+	b.Write([]byte(strings.Repeat("x", 20) + "0123456789"))
+	assert.Equal(t, "[... 31 bytes of earlier output discarded ...]\n0123456789", b.String())
+	assert.LessOrEqual(t, cap(b.buf), 32, "backing array stays bounded")
+}
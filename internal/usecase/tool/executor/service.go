@@ -1,9 +1,10 @@
 package executor
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"sync"
 	"time"
@@ -11,10 +12,21 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	// DefaultSessionTTL is how long finished background sessions are kept.
+	DefaultSessionTTL = time.Hour
+
+	// waitDelay bounds how long Wait waits for output after the command
+	// exits or is killed, e.g. when a detached grandchild keeps a pipe open.
+	waitDelay = 2 * time.Second
+)
+
 // executorService implements ExecutorService
 type executorService struct {
 	sessions map[string]*sessionInfo
-	mu       sync.RWMutex
+	mu       sync.Mutex
+	opts     Options
+	openPTY  func() (master, tty *os.File, err error)
 }
 
 // sessionInfo holds information about a background session
@@ -25,15 +37,35 @@ type sessionInfo struct {
 	StartedAt   time.Time
 	CompletedAt *time.Time
 	ExitCode    *int
-	Output      *bytes.Buffer
+	Output      *ringBuffer
 	Status      SessionState
 	Error       string
 }
 
-// NewExecutorService creates a new ExecutorService
+// process is a started command
+type process struct {
+	cmd    *exec.Cmd
+	master *os.File      // PTY master; nil unless in PTY mode
+	copied chan struct{} // Closed once PTY output is drained
+}
+
+// NewExecutorService creates a new ExecutorService with default options
 func NewExecutorService() ExecutorService {
+	return NewExecutorServiceWithOptions(Options{})
+}
+
+// NewExecutorServiceWithOptions creates a new ExecutorService
+func NewExecutorServiceWithOptions(opts Options) ExecutorService {
+	if opts.MaxOutputBytes <= 0 {
+		opts.MaxOutputBytes = DefaultMaxOutputBytes
+	}
+	if opts.SessionTTL <= 0 {
+		opts.SessionTTL = DefaultSessionTTL
+	}
 	return &executorService{
 		sessions: make(map[string]*sessionInfo),
+		opts:     opts,
+		openPTY:  openPTY,
 	}
 }
 
@@ -41,21 +73,18 @@ func NewExecutorService() ExecutorService {
 func (s *executorService) Execute(ctx context.Context, req ExecutionRequest) (*ExecutionResult, error) {
 	// Create context with timeout
 	execCtx, cancel := s.createExecutionContext(ctx, req.Timeout)
-	if cancel != nil {
-		defer cancel()
-	}
-
-	// Create and configure command
-	cmd := s.createCommand(execCtx, req)
+	defer cancel()
 
-	// Capture output
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	// Capture output, keeping the most recent bytes of each stream
+	stdout := newRingBuffer(s.opts.MaxOutputBytes)
+	stderr := newRingBuffer(s.opts.MaxOutputBytes)
 
 	// Run command and measure duration
 	startTime := time.Now()
-	err := cmd.Run()
+	proc, err := s.start(execCtx, req, stdout, stderr)
+	if err == nil {
+		err = proc.wait()
+	}
 	duration := time.Since(startTime)
 
 	// Handle execution result
@@ -82,40 +111,34 @@ func (s *executorService) ExecuteBackground(ctx context.Context, req ExecutionRe
 	// Create context with timeout or cancellation
 	execCtx, cancel := s.createExecutionContext(ctx, req.Timeout)
 
-	// Create and configure command
-	cmd := s.createCommand(execCtx, req)
+	// Capture combined output
+	output := newRingBuffer(s.opts.MaxOutputBytes)
 
-	// Capture output
-	var output bytes.Buffer
-	cmd.Stdout = &output
-	cmd.Stderr = &output
+	// Start command
+	proc, err := s.start(execCtx, req, output, output)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start background command: %w", err)
+	}
 
 	// Create session info
 	info := &sessionInfo{
 		ID:        sessionID,
-		Cmd:       cmd,
+		Cmd:       proc.cmd,
 		Cancel:    cancel,
 		StartedAt: time.Now(),
-		Output:    &output,
+		Output:    output,
 		Status:    SessionStateRunning,
 	}
 
-	// Store session
+	// Store session, dropping expired ones
 	s.mu.Lock()
+	s.sweepSessions(info.StartedAt)
 	s.sessions[sessionID] = info
 	s.mu.Unlock()
 
-	// Start command in background
-	if err := cmd.Start(); err != nil {
-		cancel()
-		s.mu.Lock()
-		delete(s.sessions, sessionID)
-		s.mu.Unlock()
-		return nil, fmt.Errorf("failed to start background command: %w", err)
-	}
-
 	// Monitor command completion in goroutine
-	go s.monitorSession(sessionID, cmd, cancel)
+	go s.monitorSession(sessionID, proc, cancel)
 
 	session := &BackgroundSession{
 		ID:        sessionID,
@@ -126,16 +149,16 @@ func (s *executorService) ExecuteBackground(ctx context.Context, req ExecutionRe
 }
 
 // monitorSession monitors a background session until completion
-func (s *executorService) monitorSession(sessionID string, cmd *exec.Cmd, cancel context.CancelFunc) {
+func (s *executorService) monitorSession(sessionID string, proc *process, cancel context.CancelFunc) {
 	defer cancel()
 
-	err := cmd.Wait()
+	err := proc.wait()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	info, exists := s.sessions[sessionID]
-	if !exists {
+	if !exists || info.Status == SessionStateCancelled {
 		return
 	}
 
@@ -158,10 +181,21 @@ func (s *executorService) monitorSession(sessionID string, cmd *exec.Cmd, cancel
 	}
 }
 
+// sweepSessions removes sessions that finished more than the TTL ago. It runs
+// whenever sessions are accessed. The caller must hold s.mu.
+func (s *executorService) sweepSessions(now time.Time) {
+	for id, info := range s.sessions {
+		if info.CompletedAt != nil && now.Sub(*info.CompletedAt) > s.opts.SessionTTL {
+			delete(s.sessions, id)
+		}
+	}
+}
+
 // GetSessionStatus returns the status of a background session
 func (s *executorService) GetSessionStatus(ctx context.Context, sessionID string) (*SessionStatus, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweepSessions(time.Now())
 
 	info, exists := s.sessions[sessionID]
 	if !exists {
@@ -182,8 +216,9 @@ func (s *executorService) GetSessionStatus(ctx context.Context, sessionID string
 
 // GetSessionOutput retrieves the output of a background session
 func (s *executorService) GetSessionOutput(ctx context.Context, sessionID string) (string, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweepSessions(time.Now())
 
 	info, exists := s.sessions[sessionID]
 	if !exists {
@@ -197,13 +232,17 @@ func (s *executorService) GetSessionOutput(ctx context.Context, sessionID string
 func (s *executorService) CancelSession(ctx context.Context, sessionID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.sweepSessions(time.Now())
 
 	info, exists := s.sessions[sessionID]
 	if !exists {
 		return fmt.Errorf("session not found: %s", sessionID)
 	}
+	if info.CompletedAt != nil {
+		return nil // Already finished
+	}
 
-	// Cancel the session
+	// Cancel the session (kills its process group)
 	if info.Cancel != nil {
 		info.Cancel()
 	}
@@ -233,8 +272,8 @@ func (s *executorService) createCommand(ctx context.Context, req ExecutionReques
 		cmd.Dir = req.WorkingDir
 	}
 
-	// Set environment variables
-	if len(req.Env) > 0 {
+	// Set environment variables (a non-nil map replaces the inherited environment)
+	if req.Env != nil {
 		cmd.Env = make([]string, 0, len(req.Env))
 		for k, v := range req.Env {
 			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
@@ -244,6 +283,68 @@ func (s *executorService) createCommand(ctx context.Context, req ExecutionReques
 	return cmd
 }
 
+// start starts a command in its own process group under its resource
+// limits, attached to a PTY in PTY mode. Where no PTY can be opened (e.g.
+// outside Linux) the command runs with pipes instead.
+func (s *executorService) start(ctx context.Context, req ExecutionRequest, stdout, stderr io.Writer) (*process, error) {
+	cmd := s.createCommand(ctx, req)
+	proc := &process{cmd: cmd}
+
+	limits := s.opts.Limits
+	if req.Limits != nil {
+		limits = *req.Limits
+	}
+	if err := applyLimits(cmd, limits); err != nil {
+		return nil, err
+	}
+
+	var tty *os.File
+	if req.PTYMode {
+		if master, t, err := s.openPTY(); err == nil {
+			proc.master, tty = master, t
+			defer tty.Close() // The child keeps its own copy
+		}
+	}
+	if proc.master != nil {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+		isolateProcessGroup(cmd, true)
+	} else {
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+		isolateProcessGroup(cmd, false)
+	}
+	cmd.WaitDelay = waitDelay
+
+	if err := cmd.Start(); err != nil {
+		if proc.master != nil {
+			proc.master.Close()
+		}
+		return nil, err
+	}
+
+	if proc.master != nil {
+		proc.copied = make(chan struct{})
+		go func() {
+			defer close(proc.copied)
+			_, _ = io.Copy(stdout, proc.master) // Ends with EIO once the terminal is closed
+		}()
+	}
+
+	return proc, nil
+}
+
+// wait waits for the command to exit and, in PTY mode, for its output
+func (p *process) wait() error {
+	err := p.cmd.Wait()
+	if p.master != nil {
+		select {
+		case <-p.copied:
+		case <-time.After(waitDelay): // A detached grandchild still holds the terminal
+		}
+		p.master.Close()
+	}
+	return err
+}
+
 // handleExecutionError processes command execution errors and returns exit code
 func (s *executorService) handleExecutionError(ctx context.Context, err error) (int, error) {
 	if err == nil {
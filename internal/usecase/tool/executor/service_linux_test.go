@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// processAlive reports whether pid is running (zombies count as exited).
+func processAlive(pid int) bool {
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+func TestExecutorService_CancelKillsProcessGroup(t *testing.T) {
+	svc := NewExecutorService()
+	ctx := context.Background()
+
+	session, err := svc.ExecuteBackground(ctx, ExecutionRequest{
+		Command: "sh",
+		Args:    []string{"-c", "sleep 30 & echo $!; wait"},
+	})
+	require.NoError(t, err)
+
+	var pid int
+	require.Eventually(t, func() bool {
+		output, _ := svc.GetSessionOutput(ctx, session.ID)
+		pid, _ = strconv.Atoi(strings.TrimSpace(output))
+		return pid > 0
+	}, 2*time.Second, 10*time.Millisecond)
+	require.True(t, processAlive(pid))
+
+	require.NoError(t, svc.CancelSession(ctx, session.ID))
+	assert.Eventually(t, func() bool { return !processAlive(pid) }, 2*time.Second, 10*time.Millisecond,
+		"grandchild should be killed with the session")
+
+	status, err := svc.GetSessionStatus(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, SessionStateCancelled, status.Status)
+}
+
+func TestExecutorService_TimeoutKillsProcessGroup(t *testing.T) {
+	svc := NewExecutorService()
+	pidFile := t.TempDir() + "/pid"
+
+	start := time.Now()
+	_, err := svc.Execute(context.Background(), ExecutionRequest{
+		Command: "sh",
+		Args:    []string{"-c", "sleep 30 & echo $! > " + pidFile + "; wait"},
+		Timeout: 200 * time.Millisecond,
+	})
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second, "Execute should not wait for the grandchild")
+
+	data, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	assert.Eventually(t, func() bool { return !processAlive(pid) }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestExecutorService_ResourceLimits(t *testing.T) {
+	svc := NewExecutorServiceWithOptions(Options{Limits: ResourceLimits{CPUSeconds: 7}})
+	ctx := context.Background()
+
+	// sh reports its own limits, which are in place before it runs
+	req := ExecutionRequest{Command: "sh", Args: []string{"-c", "ulimit -t"}, Timeout: 5 * time.Second}
+	result, err := svc.Execute(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "7", strings.TrimSpace(result.Stdout))
+
+	req.Limits = &ResourceLimits{CPUSeconds: 3}
+	result, err = svc.Execute(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "3", strings.TrimSpace(result.Stdout), "request limits override defaults")
+}
+
+func TestExecutorService_ResourceLimitsBeforeExec(t *testing.T) {
+	svc := NewExecutorServiceWithOptions(Options{Limits: ResourceLimits{FileSizeBytes: 4096}})
+
+	// The command's first read of its own limits already sees them
+	result, err := svc.Execute(context.Background(), ExecutionRequest{
+		Command: "grep",
+		Args:    []string{"Max file size", "/proc/self/limits"},
+		Env:     map[string]string{},
+		Timeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Max", "file", "size", "4096", "4096", "bytes"}, strings.Fields(result.Stdout))
+
+	// The shim's variable does not leak into the command's environment
+	result, err = svc.Execute(context.Background(), ExecutionRequest{Command: "env", Env: map[string]string{"A": "1"}})
+	require.NoError(t, err)
+	assert.Equal(t, "A=1", strings.TrimSpace(result.Stdout))
+}
+
+func TestExecutorService_PTYMode(t *testing.T) {
+	svc := NewExecutorService()
+	ctx := context.Background()
+	req := ExecutionRequest{
+		Command: "sh",
+		Args:    []string{"-c", "if [ -t 1 ]; then echo tty; else echo notty; fi"},
+		Timeout: 5 * time.Second,
+	}
+
+	result, err := svc.Execute(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "notty", strings.TrimSpace(result.Stdout))
+
+	req.PTYMode = true
+	result, err = svc.Execute(ctx, req)
+	if err != nil && strings.Contains(err.Error(), "pty") {
+		t.Skipf("No PTY available: %v", err)
+	}
+	require.NoError(t, err)
+	assert.Equal(t, "tty", strings.TrimSpace(result.Stdout))
+	assert.Equal(t, 0, result.ExitCode)
+}
@@ -2,6 +2,8 @@ package executor
 
 import (
 	"context"
+	"errors"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -115,6 +117,20 @@ func TestExecutorService_Execute_Environment(t *testing.T) {
 	assert.Contains(t, result.Stdout, "test_value")
 }
 
+func TestExecutorService_Execute_EmptyEnvironment(t *testing.T) {
+	t.Setenv("EXECUTOR_SECRET", "leaked")
+	svc := NewExecutorService()
+
+	result, err := svc.Execute(context.Background(), ExecutionRequest{
+		Command: "env",
+		Env:     map[string]string{},
+		Timeout: 5 * time.Second,
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(result.Stdout))
+}
+
 func TestExecutorService_ExecuteBackground_CreateSession(t *testing.T) {
 	svc := NewExecutorService()
 	ctx := context.Background()
@@ -234,3 +250,70 @@ func TestExecutorService_GetSessionStatus_NotFound(t *testing.T) {
 	assert.Nil(t, status)
 	assert.Contains(t, err.Error(), "session not found")
 }
+
+func TestExecutorService_Execute_OutputCap(t *testing.T) {
+	svc := NewExecutorServiceWithOptions(Options{MaxOutputBytes: 100})
+
+	result, err := svc.Execute(context.Background(), ExecutionRequest{
+		Command: "seq",
+		Args:    []string{"1", "1000"},
+		Timeout: 5 * time.Second,
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, result.Stdout, "earlier output discarded")
+	assert.True(t, strings.HasSuffix(result.Stdout, "1000\n"), "most recent output is kept")
+}
+
+func TestExecutorService_SessionTTL(t *testing.T) {
+	svc := NewExecutorServiceWithOptions(Options{SessionTTL: 200 * time.Millisecond})
+	ctx := context.Background()
+
+	first, err := svc.ExecuteBackground(ctx, ExecutionRequest{Command: "true"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		status, err := svc.GetSessionStatus(ctx, first.ID)
+		return err == nil && status.Status == SessionStateCompleted
+	}, 2*time.Second, 10*time.Millisecond)
+
+	time.Sleep(250 * time.Millisecond)
+	_, err = svc.ExecuteBackground(ctx, ExecutionRequest{Command: "true"})
+	require.NoError(t, err)
+
+	_, err = svc.GetSessionStatus(ctx, first.ID)
+	assert.ErrorContains(t, err, "session not found", "expired session is removed")
+}
+
+func TestExecutorService_SessionTTL_SweptOnAccess(t *testing.T) {
+	svc := NewExecutorServiceWithOptions(Options{SessionTTL: 200 * time.Millisecond})
+	ctx := context.Background()
+
+	session, err := svc.ExecuteBackground(ctx, ExecutionRequest{Command: "true"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		status, err := svc.GetSessionStatus(ctx, session.ID)
+		return err == nil && status.Status == SessionStateCompleted
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Reading sessions sweeps expired ones without a new background command
+	time.Sleep(250 * time.Millisecond)
+	_, err = svc.GetSessionOutput(ctx, "other")
+	require.Error(t, err)
+	assert.Empty(t, svc.(*executorService).sessions)
+}
+
+func TestExecutorService_PTYModeFallsBackToPipes(t *testing.T) {
+	svc := NewExecutorService()
+	svc.(*executorService).openPTY = func() (*os.File, *os.File, error) {
+		return nil, nil, errors.New("no pty")
+	}
+
+	result, err := svc.Execute(context.Background(), ExecutionRequest{
+		Command: "echo",
+		Args:    []string{"hello"},
+		PTYMode: true,
+		Timeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", strings.TrimSpace(result.Stdout))
+}
@@ -28,9 +28,27 @@ type ExecutionRequest struct {
 	Command    string            // Command to execute
 	Args       []string          // Command arguments
 	WorkingDir string            // Working directory (default: current)
-	Env        map[string]string // Environment variables
+	Env        map[string]string // Environment variables; nil inherits the bot's environment, empty runs with none
 	Timeout    time.Duration     // Execution timeout
-	PTYMode    bool              // Use PTY mode for interactive CLIs
+	PTYMode    bool              // Run attached to a pseudo-terminal, output returned as Stdout; falls back to pipes where unavailable (outside Linux)
+	Limits     *ResourceLimits   // Overrides the service's default limits
+}
+
+// ResourceLimits caps the resources a command and its children may use.
+// Zero fields are unlimited. Limits are set before the command runs and are
+// only supported on Linux.
+type ResourceLimits struct {
+	CPUSeconds    uint64 // CPU time (RLIMIT_CPU)
+	MemoryBytes   uint64 // Address space (RLIMIT_AS)
+	FileSizeBytes uint64 // Largest file the command may write (RLIMIT_FSIZE)
+	Processes     uint64 // Processes for the bot's user, not just this command (RLIMIT_NPROC)
+}
+
+// Options configures an ExecutorService.
+type Options struct {
+	Limits         ResourceLimits // Default limits for every command
+	MaxOutputBytes int            // Output kept per stream, most recent first; 0 uses DefaultMaxOutputBytes
+	SessionTTL     time.Duration  // How long finished background sessions are kept; 0 uses DefaultSessionTTL
 }
 
 // ExecutionResult represents the result of command execution
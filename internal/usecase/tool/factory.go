@@ -0,0 +1,144 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"nuimanbot/internal/config"
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/jsonschema"
+)
+
+// Factory builds a tool from its tools.entries config.
+type Factory struct {
+	// Enabled reports whether the tool is built when its entry does not set enabled.
+	Enabled bool
+	// RequiresAPIKey rejects enabled entries without an api_key.
+	RequiresAPIKey bool
+	// Params is a JSON schema for the entry's params. Omitted params take the
+	// schema's defaults and safe coercions apply, as for tool arguments.
+	Params map[string]any
+	// New constructs the tool. cfg.Params has been validated against Params
+	// and uses encoding/json types (float64 numbers, []any lists).
+	New func(cfg domain.ToolConfig) (domain.Tool, error)
+}
+
+// FactoryRegistry maps tool names to the factories that build them, so which
+// tools exist and how they are configured is decided by configuration alone.
+type FactoryRegistry struct {
+	factories map[string]Factory
+}
+
+// NewFactoryRegistry creates an empty factory registry.
+func NewFactoryRegistry() *FactoryRegistry {
+	return &FactoryRegistry{factories: make(map[string]Factory)}
+}
+
+// Register adds the factory for a tool name.
+func (r *FactoryRegistry) Register(name string, factory Factory) error {
+	if factory.New == nil {
+		return fmt.Errorf("tool factory %s has no constructor", name)
+	}
+	if _, exists := r.factories[name]; exists {
+		return fmt.Errorf("tool factory %s already registered", name)
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// Build constructs every enabled tool from its config entry, in name order.
+// Entries for unknown tools, missing API keys, invalid params and constructor
+// failures are all reported together so startup fails with the full list.
+func (r *FactoryRegistry) Build(entries map[string]config.ToolConfig) ([]domain.Tool, error) {
+	var errs []error
+	for _, name := range sortedNames(entries) {
+		if _, ok := r.factories[name]; !ok {
+			errs = append(errs, fmt.Errorf("tools.entries.%s: unknown tool", name))
+		}
+	}
+
+	var tools []domain.Tool
+	for _, name := range sortedNames(r.factories) {
+		factory := r.factories[name]
+		entry := entries[name]
+
+		enabled := factory.Enabled
+		if entry.Enabled != nil {
+			enabled = *entry.Enabled
+		}
+		if !enabled {
+			slog.Info("Tool disabled", "tool", name)
+			continue
+		}
+
+		cfg, err := factory.config(entry)
+		if err == nil {
+			var t domain.Tool
+			if t, err = factory.New(cfg); err == nil {
+				tools = append(tools, t)
+				continue
+			}
+		}
+		errs = append(errs, fmt.Errorf("tools.entries.%s: %w", name, err))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return tools, nil
+}
+
+// config validates an entry and converts it to the tool's runtime config.
+func (f Factory) config(entry config.ToolConfig) (domain.ToolConfig, error) {
+	if f.RequiresAPIKey && entry.APIKey.Value() == "" {
+		return domain.ToolConfig{}, fmt.Errorf("api_key is required")
+	}
+
+	params, err := normalizeParams(entry.Params)
+	if err != nil {
+		return domain.ToolConfig{}, err
+	}
+	if f.Params != nil {
+		if coerced, ok := jsonschema.Coerce(f.Params, params).(map[string]any); ok {
+			params = coerced
+		}
+		if err := jsonschema.Validate(f.Params, params); err != nil {
+			return domain.ToolConfig{}, fmt.Errorf("params: %w", err)
+		}
+	}
+
+	return domain.ToolConfig{
+		Enabled: true,
+		APIKey:  entry.APIKey,
+		Env:     entry.Env,
+		Params:  params,
+	}, nil
+}
+
+// normalizeParams converts YAML-decoded params to encoding/json types.
+func normalizeParams(params map[string]any) (map[string]any, error) {
+	if len(params) == 0 {
+		return map[string]any{}, nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+	var normalized map[string]any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+	return normalized, nil
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
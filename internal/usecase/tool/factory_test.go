@@ -0,0 +1,163 @@
+package tool_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/config"
+	"nuimanbot/internal/domain"
+	. "nuimanbot/internal/usecase/tool"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func newTestFactories(t *testing.T, built map[string]domain.ToolConfig) *FactoryRegistry {
+	t.Helper()
+	newTool := func(name string) func(cfg domain.ToolConfig) (domain.Tool, error) {
+		return func(cfg domain.ToolConfig) (domain.Tool, error) {
+			built[name] = cfg
+			return &MockTool{NameFunc: func() string { return name }}, nil
+		}
+	}
+
+	registry := NewFactoryRegistry()
+	factories := map[string]Factory{
+		"calculator": {Enabled: true, New: newTool("calculator")},
+		"weather": {
+			RequiresAPIKey: true,
+			Params: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"timeout": map[string]any{"type": "integer", "minimum": 1, "default": 10},
+				},
+				"additionalProperties": false,
+			},
+			New: newTool("weather"),
+		},
+	}
+	for name, f := range factories {
+		if err := registry.Register(name, f); err != nil {
+			t.Fatalf("Register(%s) failed: %v", name, err)
+		}
+	}
+	return registry
+}
+
+func TestFactoryRegistry_Build(t *testing.T) {
+	built := map[string]domain.ToolConfig{}
+	registry := newTestFactories(t, built)
+
+	tools, err := registry.Build(map[string]config.ToolConfig{
+		"weather": {
+			Enabled: boolPtr(true),
+			APIKey:  domain.NewSecureStringFromString("key"),
+			Params:  map[string]any{"timeout": "5"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if len(tools) != 2 || tools[0].Name() != "calculator" || tools[1].Name() != "weather" {
+		t.Fatalf("Expected calculator and weather, got %v", tools)
+	}
+	if got := built["weather"].Params["timeout"]; got != float64(5) {
+		t.Errorf("Expected coerced timeout 5, got %#v", got)
+	}
+	if built["weather"].APIKey.Value() != "key" {
+		t.Error("Expected api_key passed to constructor")
+	}
+	if got := built["calculator"].Params; len(got) != 0 {
+		t.Errorf("Expected no params for calculator, got %v", got)
+	}
+}
+
+func TestFactoryRegistry_BuildDefaultsAndDisable(t *testing.T) {
+	built := map[string]domain.ToolConfig{}
+	registry := newTestFactories(t, built)
+
+	tools, err := registry.Build(map[string]config.ToolConfig{
+		"calculator": {Enabled: boolPtr(false)},
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(tools) != 0 {
+		t.Errorf("Expected no tools (calculator disabled, weather off by default), got %d", len(tools))
+	}
+
+	tools, err = registry.Build(map[string]config.ToolConfig{
+		"weather": {Enabled: boolPtr(true), APIKey: domain.NewSecureStringFromString("key")},
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(tools) != 2 {
+		t.Fatalf("Expected 2 tools, got %d", len(tools))
+	}
+	if got := built["weather"].Params["timeout"]; got != float64(10) {
+		t.Errorf("Expected default timeout 10, got %#v", got)
+	}
+}
+
+func TestFactoryRegistry_BuildReportsAllErrors(t *testing.T) {
+	registry := newTestFactories(t, map[string]domain.ToolConfig{})
+
+	_, err := registry.Build(map[string]config.ToolConfig{
+		"calculater": {},
+		"weather": {
+			Enabled: boolPtr(true),
+			Params:  map[string]any{"timeout": 0, "units": "metric"},
+		},
+	})
+	if err == nil {
+		t.Fatal("Expected configuration errors")
+	}
+	for _, want := range []string{
+		"tools.entries.calculater: unknown tool",
+		"tools.entries.weather: api_key is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got: %v", want, err)
+		}
+	}
+
+	_, err = registry.Build(map[string]config.ToolConfig{
+		"weather": {
+			Enabled: boolPtr(true),
+			APIKey:  domain.NewSecureStringFromString("key"),
+			Params:  map[string]any{"timeout": 0, "units": "metric"},
+		},
+	})
+	for _, want := range []string{"$.timeout: must be >= 1", "$.units: is not an allowed property"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestFactoryRegistry_ConstructorError(t *testing.T) {
+	registry := NewFactoryRegistry()
+	if err := registry.Register("broken", Factory{
+		Enabled: true,
+		New: func(cfg domain.ToolConfig) (domain.Tool, error) {
+			return nil, errors.New("missing binary")
+		},
+	}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	_, err := registry.Build(nil)
+	if err == nil || !strings.Contains(err.Error(), "tools.entries.broken: missing binary") {
+		t.Errorf("Expected constructor error, got: %v", err)
+	}
+}
+
+func TestFactoryRegistry_RegisterDuplicate(t *testing.T) {
+	registry := newTestFactories(t, map[string]domain.ToolConfig{})
+	err := registry.Register("calculator", Factory{New: func(cfg domain.ToolConfig) (domain.Tool, error) { return nil, nil }})
+	if err == nil {
+		t.Error("Expected error registering a duplicate factory")
+	}
+}
@@ -0,0 +1,288 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/tool"
+	"nuimanbot/internal/usecase/tool/common"
+)
+
+const (
+	defaultMaxOutputChars = 16000
+	defaultMaxFileSize    = 10 * 1024 * 1024
+)
+
+// Actions
+const (
+	ActionRead   = "read"
+	ActionList   = "list"
+	ActionStat   = "stat"
+	ActionSearch = "search"
+	ActionWrite  = "write"
+	ActionPatch  = "patch"
+)
+
+// Settings controls where and how the files skill may work.
+type Settings struct {
+	Workspaces     []string // Relative paths resolve against the first; empty uses the working directory
+	MaxOutputChars int      // 0 uses the default
+	MaxFileSize    int64    // Larger files are not searched, written or patched; 0 uses the default
+	ReadOnly       bool     // Disable write and patch
+}
+
+// FilesSkill reads and edits files inside the configured workspaces
+type FilesSkill struct {
+	config     domain.ToolConfig
+	settings   Settings
+	pathVal    *common.PathValidator
+	workspaces []string // Absolute, with symlinks resolved
+}
+
+// NewFilesSkill creates a new FilesSkill instance
+func NewFilesSkill(config domain.ToolConfig, settings Settings) *FilesSkill {
+	if len(settings.Workspaces) == 0 {
+		settings.Workspaces = []string{"."}
+	}
+	if settings.MaxOutputChars <= 0 {
+		settings.MaxOutputChars = defaultMaxOutputChars
+	}
+	if settings.MaxFileSize <= 0 {
+		settings.MaxFileSize = defaultMaxFileSize
+	}
+
+	workspaces := make([]string, len(settings.Workspaces))
+	for i, dir := range settings.Workspaces {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = real
+		}
+		workspaces[i] = dir
+	}
+	settings.Workspaces = workspaces
+
+	return &FilesSkill{
+		config:     config,
+		settings:   settings,
+		pathVal:    common.NewPathValidator(workspaces),
+		workspaces: workspaces,
+	}
+}
+
+// Name returns the skill identifier
+func (s *FilesSkill) Name() string {
+	return "files"
+}
+
+// Description returns a human-readable description
+func (s *FilesSkill) Description() string {
+	desc := "Work with files in the workspace: read (with line ranges), list directories (with glob patterns), " +
+		"stat, and search file contents with a regular expression."
+	if !s.settings.ReadOnly {
+		desc += " Write files, or edit them by applying a unified diff with action patch; read a file before patching it."
+	}
+	return desc + " Paths are relative to " + s.workspaces[0] + "."
+}
+
+// RequiredPermissions returns the permissions needed. Write and patch
+// additionally check the write permission per call.
+func (s *FilesSkill) RequiredPermissions() []domain.Permission {
+	return []domain.Permission{domain.PermissionRead}
+}
+
+// Config returns the skill configuration
+func (s *FilesSkill) Config() domain.ToolConfig {
+	return s.config
+}
+
+// InputSchema returns the JSON schema for parameters
+func (s *FilesSkill) InputSchema() map[string]any {
+	actions := []string{ActionRead, ActionList, ActionStat, ActionSearch}
+	if !s.settings.ReadOnly {
+		actions = append(actions, ActionWrite, ActionPatch)
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        actions,
+				"description": "Operation to perform",
+			},
+			"path": map[string]any{
+				"type":        "string",
+				"description": "File or directory, relative to the workspace (defaults to the workspace for list and search)",
+			},
+			"start_line": map[string]any{
+				"type":        "integer",
+				"minimum":     1,
+				"description": "First line to read (1-based)",
+			},
+			"end_line": map[string]any{
+				"type":        "integer",
+				"minimum":     1,
+				"description": "Last line to read (inclusive)",
+			},
+			"pattern": map[string]any{
+				"type":        "string",
+				"description": "Glob for list and search, e.g. *.go or src/**/*.ts",
+			},
+			"recursive": map[string]any{
+				"type":        "boolean",
+				"default":     false,
+				"description": "List subdirectories too",
+			},
+			"query": map[string]any{
+				"type":        "string",
+				"description": "Regular expression to search for",
+			},
+			"ignore_case": map[string]any{
+				"type":    "boolean",
+				"default": false,
+			},
+			"max_results": map[string]any{
+				"type":    "integer",
+				"minimum": 1,
+				"maximum": maxSearchResults,
+			},
+			"content": map[string]any{
+				"type":        "string",
+				"description": "Full file content for write",
+			},
+			"patch": map[string]any{
+				"type":        "string",
+				"description": "Unified diff for patch; file headers are relative to the workspace, or give path for a single file",
+			},
+		},
+		"required": []string{"action"},
+	}
+}
+
+// Execute runs a file action
+func (s *FilesSkill) Execute(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	action, _ := params["action"].(string)
+	switch action {
+	case ActionRead:
+		return s.read(params)
+	case ActionList:
+		return s.list(params)
+	case ActionStat:
+		return s.stat(params)
+	case ActionSearch:
+		return s.search(ctx, params)
+	case ActionWrite:
+		return s.write(ctx, params)
+	case ActionPatch:
+		return s.patch(ctx, params)
+	case "":
+		return nil, fmt.Errorf("action is required")
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+// resolve turns a user-supplied path into an absolute path inside a
+// workspace. Symlinks are resolved so a link cannot point outside; for a
+// path that does not exist yet, its nearest existing parent is checked.
+func (s *FilesSkill) resolve(p string) (string, error) {
+	if p == "" {
+		p = "."
+	}
+	if !filepath.IsAbs(p) {
+		// Joined without cleaning so the validator still sees any ".."
+		p = s.workspaces[0] + string(filepath.Separator) + p
+	}
+	if err := s.pathVal.ValidatePath(p); err != nil {
+		return "", fmt.Errorf("path validation failed: %w", err)
+	}
+
+	abs := filepath.Clean(p)
+	real, err := evalExisting(abs)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if !s.inWorkspace(real) {
+		return "", fmt.Errorf("path validation failed: path outside allowed workspace: %s", s.display(abs))
+	}
+	return real, nil
+}
+
+// evalExisting resolves symlinks in the longest existing prefix of path.
+func evalExisting(path string) (string, error) {
+	var rest []string
+	for {
+		real, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(append([]string{real}, rest...)...), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", err
+		}
+		rest = append([]string{filepath.Base(path)}, rest...)
+		path = parent
+	}
+}
+
+// inWorkspace reports whether an absolute path is a workspace or inside one.
+func (s *FilesSkill) inWorkspace(path string) bool {
+	for _, ws := range s.workspaces {
+		if path == ws || strings.HasPrefix(path, strings.TrimSuffix(ws, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// display returns a path relative to the first workspace when possible.
+func (s *FilesSkill) display(path string) string {
+	if rel, err := filepath.Rel(s.workspaces[0], path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// truncate caps output at MaxOutputChars, ending with hint.
+func (s *FilesSkill) truncate(output, hint string) (string, bool) {
+	limit := s.settings.MaxOutputChars
+	if len(output) <= limit {
+		return output, false
+	}
+	cut := strings.LastIndexByte(output[:limit], '\n')
+	if cut <= 0 {
+		cut = limit
+	}
+	return strings.ToValidUTF8(output[:cut], "") + "\n... [output truncated; " + hint + "]", true
+}
+
+// checkWrite enforces the read-only setting and the write permission.
+func (s *FilesSkill) checkWrite(ctx context.Context, action string) error {
+	if s.settings.ReadOnly {
+		return fmt.Errorf("%w: the files tool is read-only", domain.ErrForbidden)
+	}
+	if !tool.HasPermission(tool.UserFromContext(ctx), domain.PermissionWrite) {
+		return fmt.Errorf("%w: %s requires the write permission", domain.ErrInsufficientPermissions, action)
+	}
+	return nil
+}
+
+// intParam reads an integer param, which arrives as float64 from JSON.
+func intParam(params map[string]any, name string) int {
+	switch v := params[name].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,276 @@
+package files
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/tool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWorkspace(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func asUser(role domain.Role) context.Context {
+	return tool.ContextWithUser(context.Background(), &domain.User{ID: "u1", Role: role})
+}
+
+func TestFilesSkill_Metadata(t *testing.T) {
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{t.TempDir()}, ReadOnly: true})
+
+	assert.Equal(t, "files", skill.Name())
+	assert.Equal(t, []domain.Permission{domain.PermissionRead}, skill.RequiredPermissions())
+	actions := skill.InputSchema()["properties"].(map[string]any)["action"].(map[string]any)["enum"]
+	assert.Equal(t, []string{ActionRead, ActionList, ActionStat, ActionSearch}, actions, "read-only hides write actions")
+}
+
+func TestFilesSkill_Read(t *testing.T) {
+	ws := newWorkspace(t, map[string]string{"a.txt": "one\ntwo\nthree\nfour\n"})
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}})
+
+	result, err := skill.Execute(context.Background(), map[string]any{"action": ActionRead, "path": "a.txt"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "a.txt (lines 1-4 of 4)")
+	assert.Contains(t, result.Output, "     3\tthree")
+
+	result, err = skill.Execute(context.Background(), map[string]any{
+		"action": ActionRead, "path": "a.txt", "start_line": float64(2), "end_line": float64(3),
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "(lines 2-3 of 4)")
+	assert.NotContains(t, result.Output, "one")
+	assert.NotContains(t, result.Output, "four")
+
+	_, err = skill.Execute(context.Background(), map[string]any{"action": ActionRead, "path": "a.txt", "start_line": float64(9)})
+	assert.ErrorContains(t, err, "past the end")
+}
+
+func TestFilesSkill_ReadTruncatesAndDetectsBinary(t *testing.T) {
+	var long strings.Builder
+	for i := 1; i <= 200; i++ {
+		fmt.Fprintf(&long, "line %d\n", i)
+	}
+	ws := newWorkspace(t, map[string]string{
+		"long.txt":  long.String(),
+		"image.png": "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+	})
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}, MaxOutputChars: 300})
+
+	result, err := skill.Execute(context.Background(), map[string]any{"action": ActionRead, "path": "long.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, true, result.Metadata["truncated"])
+	assert.Equal(t, 200, result.Metadata["total_lines"])
+	assert.Contains(t, result.Output, fmt.Sprintf("continue with start_line=%d", result.Metadata["end_line"].(int)+1))
+
+	result, err = skill.Execute(context.Background(), map[string]any{"action": ActionRead, "path": "image.png"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "binary file (image/png")
+	assert.NotContains(t, result.Output, "IHDR")
+}
+
+func TestFilesSkill_PathConfinement(t *testing.T) {
+	outside := newWorkspace(t, map[string]string{"secret.txt": "secret"})
+	ws := newWorkspace(t, nil)
+	require.NoError(t, os.Symlink(outside, filepath.Join(ws, "link")))
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}})
+
+	for _, path := range []string{"../secret.txt", filepath.Join(outside, "secret.txt"), "link/secret.txt", ws + "-other/x"} {
+		_, err := skill.Execute(context.Background(), map[string]any{"action": ActionRead, "path": path})
+		assert.ErrorContains(t, err, "path validation failed", path)
+	}
+
+	_, err := skill.Execute(asUser(domain.RoleAdmin), map[string]any{"action": ActionWrite, "path": "link/new.txt", "content": "x"})
+	assert.ErrorContains(t, err, "path validation failed", "writes through a symlink are refused")
+	assert.NoFileExists(t, filepath.Join(outside, "new.txt"))
+}
+
+func TestFilesSkill_List(t *testing.T) {
+	ws := newWorkspace(t, map[string]string{
+		"main.go":         "package main",
+		"README.md":       "# readme",
+		"pkg/util.go":     "package pkg",
+		"pkg/sub/deep.go": "package sub",
+		".git/config":     "[core]",
+	})
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}})
+
+	result, err := skill.Execute(context.Background(), map[string]any{"action": ActionList})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "main.go (12 bytes)")
+	assert.Contains(t, result.Output, "\npkg/")
+	assert.NotContains(t, result.Output, "util.go", "not recursive by default")
+	assert.NotContains(t, result.Output, ".git")
+
+	result, err = skill.Execute(context.Background(), map[string]any{"action": ActionList, "pattern": "**/*.go"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Metadata["entries"])
+	assert.Contains(t, result.Output, "pkg/sub/deep.go")
+	assert.NotContains(t, result.Output, "README.md")
+
+	result, err = skill.Execute(context.Background(), map[string]any{"action": ActionList, "path": "pkg", "pattern": "*.go", "recursive": true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Metadata["entries"])
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"*.go", "a/b/c.go", true},
+		{"src/**/*.ts", "src/a.ts", true},
+		{"src/**/*.ts", "src/x/y/a.ts", true},
+		{"src/**/*.ts", "lib/a.ts", false},
+		{"src/*.ts", "src/x/a.ts", false},
+		{"**", "any/thing", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchGlob(tt.pattern, tt.path), "%s vs %s", tt.pattern, tt.path)
+	}
+}
+
+func TestFilesSkill_Stat(t *testing.T) {
+	ws := newWorkspace(t, map[string]string{"a.txt": "hello"})
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}})
+
+	result, err := skill.Execute(context.Background(), map[string]any{"action": ActionStat, "path": "a.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "file", result.Metadata["type"])
+	assert.Equal(t, int64(5), result.Metadata["size"])
+
+	result, err = skill.Execute(context.Background(), map[string]any{"action": ActionStat, "path": "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, false, result.Metadata["exists"])
+}
+
+func TestFilesSkill_Search(t *testing.T) {
+	ws := newWorkspace(t, map[string]string{
+		"a.go":     "package a\nfunc Hello() {}\n",
+		"b.txt":    "hello world\n",
+		"bin.dat":  "hello\x00binary",
+		"sub/c.go": "// HELLO\n",
+	})
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}})
+
+	result, err := skill.Execute(context.Background(), map[string]any{"action": ActionSearch, "query": "hello", "ignore_case": true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Metadata["matches"], "binary files are skipped")
+	assert.Contains(t, result.Output, "a.go:2: func Hello() {}")
+	assert.Contains(t, result.Output, "sub/c.go:1: // HELLO")
+
+	result, err = skill.Execute(context.Background(), map[string]any{"action": ActionSearch, "query": "hello", "ignore_case": true, "pattern": "*.go"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Metadata["matches"])
+
+	_, err = skill.Execute(context.Background(), map[string]any{"action": ActionSearch, "query": "("})
+	assert.ErrorContains(t, err, "invalid query")
+}
+
+func TestFilesSkill_Write(t *testing.T) {
+	ws := newWorkspace(t, map[string]string{"script.sh": "echo old\n"})
+	require.NoError(t, os.Chmod(filepath.Join(ws, "script.sh"), 0o755))
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}})
+
+	_, err := skill.Execute(asUser(domain.RoleGuest), map[string]any{"action": ActionWrite, "path": "x.txt", "content": "x"})
+	assert.ErrorIs(t, err, domain.ErrInsufficientPermissions)
+	_, err = skill.Execute(context.Background(), map[string]any{"action": ActionWrite, "path": "x.txt", "content": "x"})
+	assert.ErrorIs(t, err, domain.ErrInsufficientPermissions, "calls without a user cannot write")
+
+	result, err := skill.Execute(asUser(domain.RoleUser), map[string]any{"action": ActionWrite, "path": "new/dir/x.txt", "content": "hi\n"})
+	require.NoError(t, err)
+	assert.Equal(t, "Created new/dir/x.txt (3 bytes)", result.Output)
+	assert.FileExists(t, filepath.Join(ws, "new/dir/x.txt"))
+
+	_, err = skill.Execute(asUser(domain.RoleUser), map[string]any{"action": ActionWrite, "path": "script.sh", "content": "echo new\n"})
+	require.NoError(t, err)
+	info, err := os.Stat(filepath.Join(ws, "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm(), "mode is kept")
+
+	readOnly := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}, ReadOnly: true})
+	_, err = readOnly.Execute(asUser(domain.RoleAdmin), map[string]any{"action": ActionWrite, "path": "x.txt", "content": "x"})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestFilesSkill_Patch(t *testing.T) {
+	ws := newWorkspace(t, map[string]string{
+		"a.txt": "one\ntwo\nthree\n",
+		"b.txt": "alpha\nbeta\n",
+		"c.txt": "delete me\n",
+	})
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}})
+
+	result, err := skill.Execute(asUser(domain.RoleUser), map[string]any{"action": ActionPatch, "patch": `--- a/a.txt
++++ b/a.txt
+@@ -1,3 +1,3 @@
+ one
+-two
++TWO
+ three
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1,2 @@
++# New
++file
+--- a/c.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-delete me
+`})
+	require.NoError(t, err)
+	assert.Equal(t, "Applied patch: patched a.txt, created docs/new.md, deleted c.txt", result.Output)
+	assertFile(t, filepath.Join(ws, "a.txt"), "one\nTWO\nthree\n")
+	assertFile(t, filepath.Join(ws, "docs/new.md"), "# New\nfile\n")
+	assert.NoFileExists(t, filepath.Join(ws, "c.txt"))
+
+	// Headerless hunk for the file given in path
+	_, err = skill.Execute(asUser(domain.RoleUser), map[string]any{"action": ActionPatch, "path": "b.txt", "patch": "@@ -2 +2 @@\n-beta\n+BETA\n"})
+	require.NoError(t, err)
+	assertFile(t, filepath.Join(ws, "b.txt"), "alpha\nBETA\n")
+}
+
+func TestFilesSkill_PatchConflictChangesNothing(t *testing.T) {
+	ws := newWorkspace(t, map[string]string{"a.txt": "one\ntwo\n", "b.txt": "alpha\n"})
+	skill := NewFilesSkill(domain.ToolConfig{}, Settings{Workspaces: []string{ws}})
+
+	_, err := skill.Execute(asUser(domain.RoleUser), map[string]any{"action": ActionPatch, "patch": `--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ one
+-two
++2
+--- a/b.txt
++++ b/b.txt
+@@ -1 +1 @@
+-gamma
++GAMMA
+`})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Contains(t, err.Error(), "patch does not apply to b.txt")
+	assert.Contains(t, err.Error(), "  |gamma")
+	assert.Contains(t, err.Error(), "  |alpha")
+	assertFile(t, filepath.Join(ws, "a.txt"), "one\ntwo\n")
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(data))
+}
@@ -0,0 +1,282 @@
+package files
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"nuimanbot/internal/domain"
+)
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// filePatch is the part of a unified diff that applies to one file.
+type filePatch struct {
+	oldPath string // Empty for /dev/null (file creation) or a headerless patch
+	newPath string // Empty for /dev/null (file deletion) or a headerless patch
+	hunks   []*hunk
+}
+
+// hunk is one @@ section of a diff.
+type hunk struct {
+	oldStart int
+	lines    []hunkLine
+	// Set by "\ No newline at end of file" markers
+	oldNoEOL, newNoEOL bool
+}
+
+type hunkLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// parsePatch parses a unified diff. Line counts in hunk headers are not
+// trusted, since hand- and LLM-written hunks often get them wrong; a hunk
+// ends at the next hunk or file header.
+func parsePatch(text string) ([]*filePatch, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var patches []*filePatch
+	var current *filePatch
+	var h *hunk
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			current = &filePatch{oldPath: diffPath(line[4:]), newPath: diffPath(lines[i+1][4:])}
+			patches = append(patches, current)
+			h = nil
+			i++
+		case strings.HasPrefix(line, "@@"):
+			m := hunkHeader.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("invalid hunk header: %s", line)
+			}
+			if current == nil {
+				current = &filePatch{}
+				patches = append(patches, current)
+			}
+			start, _ := strconv.Atoi(m[1])
+			h = &hunk{oldStart: start}
+			current.hunks = append(current.hunks, h)
+		case h == nil:
+			// Preamble: "diff --git", "index", mode lines, commentary
+		case strings.HasPrefix(line, `\`):
+			if n := len(h.lines); n > 0 {
+				if h.lines[n-1].op == '+' {
+					h.newNoEOL = true
+				} else {
+					h.oldNoEOL = true
+					if h.lines[n-1].op == ' ' {
+						h.newNoEOL = true
+					}
+				}
+			}
+		case line == "":
+			// Context lines whose single space was stripped
+			h.lines = append(h.lines, hunkLine{op: ' '})
+		case line[0] == ' ' || line[0] == '-' || line[0] == '+':
+			h.lines = append(h.lines, hunkLine{op: line[0], text: line[1:]})
+		case strings.HasPrefix(line, "diff "):
+			h = nil
+		default:
+			return nil, fmt.Errorf("unexpected line in hunk: %q", line)
+		}
+	}
+
+	for _, p := range patches {
+		for _, h := range p.hunks {
+			// Trailing blank lines are usually the end of the text, not context
+			for n := len(h.lines); n > 0 && h.lines[n-1] == (hunkLine{op: ' '}); n-- {
+				h.lines = h.lines[:n-1]
+			}
+		}
+	}
+	if len(patches) == 0 || len(patches[0].hunks) == 0 && patches[0].newPath != "" {
+		return nil, fmt.Errorf("patch contains no hunks")
+	}
+	return patches, nil
+}
+
+// diffPath strips the a/ or b/ prefix and any timestamp from a header path.
+func diffPath(s string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		s = s[2:]
+	}
+	return s
+}
+
+// fileText is a file split into lines, remembering its line endings.
+type fileText struct {
+	lines []string
+	eol   bool // Ends with a newline
+	crlf  bool
+}
+
+func splitText(content string) fileText {
+	t := fileText{crlf: strings.Contains(content, "\r\n")}
+	if t.crlf {
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+	if content == "" {
+		return t
+	}
+	t.eol = strings.HasSuffix(content, "\n")
+	t.lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	return t
+}
+
+func (t fileText) String() string {
+	if len(t.lines) == 0 {
+		return ""
+	}
+	s := strings.Join(t.lines, "\n")
+	if t.eol {
+		s += "\n"
+	}
+	if t.crlf {
+		s = strings.ReplaceAll(s, "\n", "\r\n")
+	}
+	return s
+}
+
+// hunkConflict describes a hunk whose lines were not found in the file.
+type hunkConflict struct {
+	hunk     int // 1-based
+	line     int // Where the hunk was expected, 1-based
+	expected []string
+	actual   []string
+}
+
+// conflictError reports every hunk of a file that failed to apply.
+type conflictError struct {
+	path      string
+	conflicts []hunkConflict
+}
+
+func (e *conflictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "patch does not apply to %s; no changes were made.", e.path)
+	for _, c := range e.conflicts {
+		fmt.Fprintf(&b, "\nHunk %d (expected near line %d) did not match. Expected:\n", c.hunk, c.line)
+		for _, l := range c.expected {
+			fmt.Fprintf(&b, "  |%s\n", l)
+		}
+		b.WriteString("Found:\n")
+		for _, l := range c.actual {
+			fmt.Fprintf(&b, "  |%s\n", l)
+		}
+	}
+	b.WriteString("\nRead the file again and send a patch against its current content.")
+	return b.String()
+}
+
+func (e *conflictError) Unwrap() error {
+	return domain.ErrConflict
+}
+
+// apply applies hunks to a file's text. All hunks must match; otherwise the
+// conflicts are returned and the text is unchanged.
+func apply(path string, text fileText, hunks []*hunk) (fileText, error) {
+	var conflicts []hunkConflict
+	var out []string
+	pos := 0    // Next unconsumed line of the original
+	offset := 0 // Line shift observed from earlier hunks
+
+	for i, h := range hunks {
+		var old, replacement []string
+		for _, l := range h.lines {
+			if l.op != '+' {
+				old = append(old, l.text)
+			}
+			if l.op != '-' {
+				replacement = append(replacement, l.text)
+			}
+		}
+
+		want := h.oldStart - 1 + offset
+		if len(old) == 0 {
+			want = h.oldStart + offset // Pure insertion after line oldStart
+		}
+		at := findLines(text.lines, old, pos, want)
+		if at < 0 {
+			expectedAt := min(max(want, pos), max(len(text.lines)-len(old), pos))
+			end := min(expectedAt+len(old), len(text.lines))
+			conflicts = append(conflicts, hunkConflict{
+				hunk: i + 1, line: expectedAt + 1,
+				expected: old, actual: text.lines[expectedAt:end],
+			})
+			continue
+		}
+
+		out = append(out, text.lines[pos:at]...)
+		out = append(out, replacement...)
+		pos = at + len(old)
+		offset = at - (h.oldStart - 1)
+		if len(old) == 0 {
+			offset = at - h.oldStart
+		}
+
+		if pos == len(text.lines) {
+			switch {
+			case h.newNoEOL:
+				text.eol = false
+			case h.oldNoEOL || len(replacement) > 0:
+				text.eol = true
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return text, &conflictError{path: path, conflicts: conflicts}
+	}
+	out = append(out, text.lines[pos:]...)
+	text.lines = out
+	if len(out) == 0 {
+		text.eol = false
+	}
+	return text, nil
+}
+
+// findLines returns where want occurs in lines at or after from, preferring
+// the match closest to near. Exact matches win over ones that differ only in
+// trailing whitespace.
+func findLines(lines, want []string, from, near int) int {
+	if len(want) == 0 {
+		if near >= from && near <= len(lines) {
+			return near
+		}
+		return -1
+	}
+	for _, equal := range []func(a, b string) bool{
+		func(a, b string) bool { return a == b },
+		func(a, b string) bool { return strings.TrimRight(a, " \t") == strings.TrimRight(b, " \t") },
+	} {
+		for d := 0; near-d >= from || near+d <= len(lines)-len(want); d++ {
+			if at := near - d; at >= from && at+len(want) <= len(lines) && matchAt(lines, want, at, equal) {
+				return at
+			}
+			if at := near + d; d > 0 && at >= from && at+len(want) <= len(lines) && matchAt(lines, want, at, equal) {
+				return at
+			}
+		}
+	}
+	return -1
+}
+
+func matchAt(lines, want []string, at int, equal func(a, b string) bool) bool {
+	for i, w := range want {
+		if !equal(lines[at+i], w) {
+			return false
+		}
+	}
+	return true
+}
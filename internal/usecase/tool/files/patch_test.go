@@ -0,0 +1,122 @@
+package files
+
+import (
+	"errors"
+	"testing"
+
+	"nuimanbot/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func applyPatch(t *testing.T, original, patch string) (string, error) {
+	t.Helper()
+	patches, err := parsePatch(patch)
+	require.NoError(t, err)
+	require.Len(t, patches, 1)
+	result, err := apply("file.txt", splitText(original), patches[0].hunks)
+	return result.String(), err
+}
+
+func TestParsePatch_Headers(t *testing.T) {
+	patches, err := parsePatch(`diff --git a/src/main.go b/src/main.go
+index 83db48f..bf269f4 100644
+--- a/src/main.go
++++ b/src/main.go
+@@ -1,2 +1,2 @@
+ package main
+-var x = 1
++var x = 2
+--- /dev/null
++++ b/NEW.md
+@@ -0,0 +1 @@
++# New
+--- old.txt	2024-01-01 00:00:00
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+`)
+	require.NoError(t, err)
+	require.Len(t, patches, 3)
+
+	assert.Equal(t, "src/main.go", patches[0].oldPath)
+	assert.Equal(t, "src/main.go", patches[0].newPath)
+	assert.Equal(t, []hunkLine{{' ', "package main"}, {'-', "var x = 1"}, {'+', "var x = 2"}}, patches[0].hunks[0].lines)
+	assert.Equal(t, "", patches[1].oldPath, "/dev/null marks a new file")
+	assert.Equal(t, "NEW.md", patches[1].newPath)
+	assert.Equal(t, "old.txt", patches[2].oldPath, "timestamps are stripped")
+	assert.Equal(t, "", patches[2].newPath)
+}
+
+func TestParsePatch_Invalid(t *testing.T) {
+	_, err := parsePatch("just some text")
+	assert.ErrorContains(t, err, "no hunks")
+
+	_, err = parsePatch("@@ -1 +1 @@\n-a\n+b\n?c\n")
+	assert.ErrorContains(t, err, "unexpected line")
+}
+
+func TestApply(t *testing.T) {
+	original := "one\ntwo\nthree\nfour\nfive\nsix\nseven\n"
+
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{
+			name:  "replace line",
+			patch: "@@ -2,3 +2,3 @@\n two\n-three\n+THREE\n four\n",
+			want:  "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\n",
+		},
+		{
+			name:  "wrong line numbers are tolerated",
+			patch: "@@ -40,2 +40,2 @@\n-six\n+SIX\n seven\n",
+			want:  "one\ntwo\nthree\nfour\nfive\nSIX\nseven\n",
+		},
+		{
+			name:  "multiple hunks",
+			patch: "@@ -1,2 +1,3 @@\n one\n+one and a half\n two\n@@ -6,2 +7,1 @@\n six\n-seven\n",
+			want:  "one\none and a half\ntwo\nthree\nfour\nfive\nsix\n",
+		},
+		{
+			name:  "pure insertion",
+			patch: "@@ -3,0 +4,1 @@\n+inserted\n",
+			want:  "one\ntwo\nthree\ninserted\nfour\nfive\nsix\nseven\n",
+		},
+		{
+			name:  "remove trailing newline",
+			patch: "@@ -7 +7 @@\n-seven\n+SEVEN\n\\ No newline at end of file\n",
+			want:  "one\ntwo\nthree\nfour\nfive\nsix\nSEVEN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyPatch(t, original, tt.patch)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApply_PreservesCRLF(t *testing.T) {
+	got, err := applyPatch(t, "a\r\nb\r\n", "@@ -1,2 +1,2 @@\n a\n-b\n+c\n")
+	require.NoError(t, err)
+	assert.Equal(t, "a\r\nc\r\n", got)
+}
+
+func TestApply_Conflict(t *testing.T) {
+	_, err := applyPatch(t, "one\ntwo\nthree\n", "@@ -1,2 +1,2 @@\n one\n-TWO\n+2\n@@ -3 +3 @@\n-three\n+3\n")
+	require.Error(t, err)
+
+	var conflict *conflictError
+	require.True(t, errors.As(err, &conflict))
+	require.Len(t, conflict.conflicts, 1, "only the first hunk conflicts")
+	assert.Equal(t, 1, conflict.conflicts[0].hunk)
+	assert.Equal(t, []string{"one", "TWO"}, conflict.conflicts[0].expected)
+	assert.Equal(t, []string{"one", "two"}, conflict.conflicts[0].actual)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Contains(t, err.Error(), "no changes were made")
+}
@@ -0,0 +1,411 @@
+package files
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"nuimanbot/internal/domain"
+)
+
+const (
+	sniffLen         = 8192
+	maxLineChars     = 2000
+	maxListEntries   = 1000
+	maxSearchResults = 500
+	defaultResults   = 100
+)
+
+// skippedDirs are never listed or searched.
+var skippedDirs = map[string]bool{".git": true}
+
+// read returns numbered lines of a text file
+func (s *FilesSkill) read(params map[string]any) (*domain.ExecutionResult, error) {
+	p, _ := params["path"].(string)
+	if p == "" {
+		return nil, fmt.Errorf("path is required for read")
+	}
+	abs, err := s.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory; use action list", s.display(abs))
+	}
+
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReaderSize(f, sniffLen)
+	if kind, binary := sniff(r); binary {
+		return &domain.ExecutionResult{
+			Output:   fmt.Sprintf("%s is a binary file (%s, %d bytes); its content is not shown", s.display(abs), kind, info.Size()),
+			Metadata: map[string]any{"path": s.display(abs), "binary": true, "size": info.Size()},
+		}, nil
+	}
+
+	start, end := intParam(params, "start_line"), intParam(params, "end_line")
+	if start < 1 {
+		start = 1
+	}
+	if end > 0 && end < start {
+		return nil, fmt.Errorf("end_line must not be before start_line")
+	}
+
+	var b strings.Builder
+	line, last := 0, 0
+	truncated := false
+	for {
+		text, err := r.ReadString('\n')
+		if text == "" && err != nil {
+			if err != io.EOF {
+				return nil, fmt.Errorf("failed to read file: %w", err)
+			}
+			break
+		}
+		line++
+		if line < start || (end > 0 && line > end) || truncated {
+			continue // Keep counting lines for the total
+		}
+		text = strings.TrimRight(text, "\r\n")
+		if len(text) > maxLineChars {
+			text = strings.ToValidUTF8(text[:maxLineChars], "") + " ... [line truncated]"
+		}
+		entry := fmt.Sprintf("%6d\t%s\n", line, text)
+		if b.Len()+len(entry) > s.settings.MaxOutputChars {
+			truncated = true
+			continue
+		}
+		b.WriteString(entry)
+		last = line
+	}
+
+	if line == 0 {
+		return &domain.ExecutionResult{
+			Output:   fmt.Sprintf("%s is empty", s.display(abs)),
+			Metadata: map[string]any{"path": s.display(abs), "total_lines": 0},
+		}, nil
+	}
+	if start > line {
+		return nil, fmt.Errorf("start_line %d is past the end of the file (%d lines)", start, line)
+	}
+
+	header := fmt.Sprintf("%s (lines %d-%d of %d)\n", s.display(abs), start, last, line)
+	output := header + b.String()
+	if truncated {
+		output += fmt.Sprintf("... [output truncated; continue with start_line=%d]", last+1)
+	}
+	return &domain.ExecutionResult{
+		Output: output,
+		Metadata: map[string]any{
+			"path":        s.display(abs),
+			"start_line":  start,
+			"end_line":    last,
+			"total_lines": line,
+			"truncated":   truncated,
+		},
+	}, nil
+}
+
+// sniff reports whether the start of a file looks binary, and its content
+// type.
+func sniff(r *bufio.Reader) (string, bool) {
+	sample, _ := r.Peek(sniffLen)
+	kind := http.DetectContentType(sample)
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return kind, true
+	}
+	// Allow a multi-byte character cut off at the end of the sample
+	for i := 0; i < utf8.UTFMax-1 && len(sample) > 0 && !utf8.Valid(sample); i++ {
+		sample = sample[:len(sample)-1]
+	}
+	return kind, !utf8.Valid(sample)
+}
+
+// list lists a directory, optionally recursively or filtered by a glob
+func (s *FilesSkill) list(params map[string]any) (*domain.ExecutionResult, error) {
+	p, _ := params["path"].(string)
+	abs, err := s.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", s.display(abs))
+	}
+
+	pattern, _ := params["pattern"].(string)
+	if pattern != "" {
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	recursive, _ := params["recursive"].(bool)
+	recursive = recursive || strings.Contains(pattern, "/") || strings.Contains(pattern, "**")
+
+	var entries []string
+	more := false
+	err = filepath.WalkDir(abs, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if p == abs {
+			return nil
+		}
+		rel := filepath.ToSlash(strings.TrimPrefix(p, abs+string(filepath.Separator)))
+		if d.IsDir() && skippedDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if pattern == "" || matchGlob(pattern, rel) {
+			if len(entries) == maxListEntries {
+				more = true
+				return filepath.SkipAll
+			}
+			entries = append(entries, describeEntry(rel, d))
+		}
+		if d.IsDir() && !recursive {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory: %w", err)
+	}
+
+	output := fmt.Sprintf("%s: %d entries", s.display(abs), len(entries))
+	if len(entries) > 0 {
+		output += "\n" + strings.Join(entries, "\n")
+	}
+	if more {
+		output += fmt.Sprintf("\n... [listing stopped at %d entries; narrow it with path or pattern]", maxListEntries)
+	}
+	output, truncated := s.truncate(output, "narrow it with path or pattern")
+	return &domain.ExecutionResult{
+		Output:   output,
+		Metadata: map[string]any{"path": s.display(abs), "entries": len(entries), "truncated": truncated || more},
+	}, nil
+}
+
+// describeEntry formats one listing line
+func describeEntry(rel string, d fs.DirEntry) string {
+	switch {
+	case d.IsDir():
+		return rel + "/"
+	case d.Type()&fs.ModeSymlink != 0:
+		return rel + " (symlink)"
+	}
+	if info, err := d.Info(); err == nil {
+		return fmt.Sprintf("%s (%d bytes)", rel, info.Size())
+	}
+	return rel
+}
+
+// matchGlob matches a slash-separated relative path against a glob where **
+// matches any number of directories. A pattern without a slash matches the
+// base name at any depth.
+func matchGlob(pattern, rel string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
+
+// stat describes a file or directory
+func (s *FilesSkill) stat(params map[string]any) (*domain.ExecutionResult, error) {
+	p, _ := params["path"].(string)
+	if p == "" {
+		return nil, fmt.Errorf("path is required for stat")
+	}
+	abs, err := s.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if errors.Is(err, os.ErrNotExist) {
+		return &domain.ExecutionResult{
+			Output:   fmt.Sprintf("%s does not exist", s.display(abs)),
+			Metadata: map[string]any{"path": s.display(abs), "exists": false},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	kind := "file"
+	if info.IsDir() {
+		kind = "directory"
+	}
+	modified := info.ModTime().UTC().Format(time.RFC3339)
+	return &domain.ExecutionResult{
+		Output: fmt.Sprintf("%s: %s, %d bytes, mode %s, modified %s",
+			s.display(abs), kind, info.Size(), info.Mode().Perm(), modified),
+		Metadata: map[string]any{
+			"path":     s.display(abs),
+			"exists":   true,
+			"type":     kind,
+			"size":     info.Size(),
+			"mode":     info.Mode().Perm().String(),
+			"modified": modified,
+		},
+	}, nil
+}
+
+// search finds lines matching a regular expression
+func (s *FilesSkill) search(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	query, _ := params["query"].(string)
+	if query == "" {
+		return nil, fmt.Errorf("query is required for search")
+	}
+	if ignoreCase, _ := params["ignore_case"].(bool); ignoreCase {
+		query = "(?i)" + query
+	}
+	re, err := regexp.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	limit := intParam(params, "max_results")
+	if limit <= 0 {
+		limit = defaultResults
+	}
+	limit = min(limit, maxSearchResults)
+	pattern, _ := params["pattern"].(string)
+
+	p, _ := params["path"].(string)
+	root, err := s.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	more := false
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d.IsDir() {
+			if p != root && skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel := filepath.ToSlash(strings.TrimPrefix(p, root+string(filepath.Separator)))
+		if pattern != "" && p != root && !matchGlob(pattern, rel) {
+			return nil
+		}
+		found, err := s.searchFile(p, re, limit-len(matches))
+		if err != nil {
+			return nil // Unreadable, binary or too large
+		}
+		for _, m := range found {
+			matches = append(matches, s.display(p)+":"+m)
+		}
+		if len(matches) >= limit {
+			more = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	output := fmt.Sprintf("%d matches for %s", len(matches), re)
+	if len(matches) > 0 {
+		output += "\n" + strings.Join(matches, "\n")
+	}
+	if more {
+		output += fmt.Sprintf("\n... [stopped at %d matches; narrow the search with path or pattern]", limit)
+	}
+	output, truncated := s.truncate(output, "narrow the search with path or pattern")
+	return &domain.ExecutionResult{
+		Output:   output,
+		Metadata: map[string]any{"matches": len(matches), "truncated": truncated || more},
+	}, nil
+}
+
+// searchFile returns up to limit "line: text" matches in a text file
+func (s *FilesSkill) searchFile(p string, re *regexp.Regexp, limit int) ([]string, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > s.settings.MaxFileSize {
+		return nil, fmt.Errorf("file too large")
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReaderSize(f, sniffLen)
+	if _, binary := sniff(r); binary {
+		return nil, fmt.Errorf("binary file")
+	}
+
+	var found []string
+	for n := 1; len(found) < limit; n++ {
+		text, err := r.ReadString('\n')
+		if text == "" && err != nil {
+			break
+		}
+		text = strings.TrimRight(text, "\r\n")
+		if re.MatchString(text) {
+			if len(text) > maxLineChars {
+				text = strings.ToValidUTF8(text[:maxLineChars], "") + " ..."
+			}
+			found = append(found, fmt.Sprintf("%d: %s", n, text))
+		}
+	}
+	return found, nil
+}
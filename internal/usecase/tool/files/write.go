@@ -0,0 +1,214 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nuimanbot/internal/domain"
+)
+
+// write creates or replaces a file
+func (s *FilesSkill) write(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	p, _ := params["path"].(string)
+	if p == "" {
+		return nil, fmt.Errorf("path is required for write")
+	}
+	content, ok := params["content"].(string)
+	if !ok {
+		return nil, fmt.Errorf("content is required for write")
+	}
+	if int64(len(content)) > s.settings.MaxFileSize {
+		return nil, fmt.Errorf("content exceeds the maximum file size of %d bytes", s.settings.MaxFileSize)
+	}
+	abs, err := s.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(abs)
+	exists := err == nil
+	if exists && info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", s.display(abs))
+	}
+
+	if err := s.checkWrite(ctx, ActionWrite); err != nil {
+		return nil, err
+	}
+
+	if err := writeFile(abs, []byte(content)); err != nil {
+		return nil, err
+	}
+
+	result := "Updated"
+	if !exists {
+		result = "Created"
+	}
+	return &domain.ExecutionResult{
+		Output:   fmt.Sprintf("%s %s (%d bytes)", result, s.display(abs), len(content)),
+		Metadata: map[string]any{"path": s.display(abs), "bytes": len(content), "created": !exists},
+	}, nil
+}
+
+// change is a validated edit to one file
+type change struct {
+	path    string // Absolute
+	content string
+	create  bool
+	remove  bool
+}
+
+// patch applies a unified diff. Every hunk of every file must apply;
+// otherwise nothing is written and the conflicts are reported.
+func (s *FilesSkill) patch(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	text, _ := params["patch"].(string)
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("patch is required for patch")
+	}
+	patches, err := parsePatch(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+
+	target, _ := params["path"].(string)
+	if target != "" && len(patches) > 1 {
+		return nil, fmt.Errorf("path can only be given for a single-file patch")
+	}
+
+	var changes []change
+	var errs []error
+	for _, fp := range patches {
+		c, err := s.prepare(fp, target)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changes = append(changes, c)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	paths := make([]string, len(changes))
+	for i, c := range changes {
+		paths[i] = s.display(c.path)
+	}
+	if err := s.checkWrite(ctx, ActionPatch); err != nil {
+		return nil, err
+	}
+
+	var summary []string
+	for _, c := range changes {
+		switch {
+		case c.remove:
+			if err := os.Remove(c.path); err != nil {
+				return nil, fmt.Errorf("failed to delete %s: %w", s.display(c.path), err)
+			}
+			summary = append(summary, "deleted "+s.display(c.path))
+		default:
+			if err := writeFile(c.path, []byte(c.content)); err != nil {
+				return nil, err
+			}
+			verb := "patched "
+			if c.create {
+				verb = "created "
+			}
+			summary = append(summary, verb+s.display(c.path))
+		}
+	}
+
+	return &domain.ExecutionResult{
+		Output:   "Applied patch: " + strings.Join(summary, ", "),
+		Metadata: map[string]any{"files": paths},
+	}, nil
+}
+
+// prepare resolves a file patch's target and applies its hunks in memory
+func (s *FilesSkill) prepare(fp *filePatch, target string) (change, error) {
+	name := target
+	if name == "" {
+		name = fp.newPath
+		if name == "" {
+			name = fp.oldPath
+		}
+	}
+	if name == "" {
+		return change{}, fmt.Errorf("patch has no file header; give the file in path")
+	}
+	abs, err := s.resolve(name)
+	if err != nil {
+		return change{}, err
+	}
+	c := change{path: abs}
+
+	var original string
+	data, err := os.ReadFile(abs)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if fp.oldPath != "" {
+			return change{}, fmt.Errorf("%s does not exist", s.display(abs))
+		}
+		c.create = true
+	case err != nil:
+		return change{}, fmt.Errorf("failed to read %s: %w", s.display(abs), err)
+	case fp.oldPath == "" && fp.newPath != "":
+		return change{}, fmt.Errorf("%s already exists; the patch creates it", s.display(abs))
+	case int64(len(data)) > s.settings.MaxFileSize:
+		return change{}, fmt.Errorf("%s exceeds the maximum file size of %d bytes", s.display(abs), s.settings.MaxFileSize)
+	default:
+		original = string(data)
+	}
+
+	result, err := apply(s.display(abs), splitText(original), fp.hunks)
+	if err != nil {
+		return change{}, err
+	}
+	if fp.newPath == "" && fp.oldPath != "" {
+		if len(result.lines) > 0 {
+			return change{}, fmt.Errorf("%s: the patch deletes the file but leaves content", s.display(abs))
+		}
+		c.remove = true
+	}
+	c.content = result.String()
+	return c, nil
+}
+
+// writeFile replaces a file atomically, creating parent directories and
+// keeping the existing file's mode.
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
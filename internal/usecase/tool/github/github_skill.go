@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/tool"
 	"nuimanbot/internal/usecase/tool/common"
 	"nuimanbot/internal/usecase/tool/executor"
 )
@@ -115,6 +116,21 @@ func (s *GitHubSkill) Execute(ctx context.Context, params map[string]any) (*doma
 		return nil, err
 	}
 
+	if s.rateLimiter != nil {
+		// Each user gets their own budget
+		var userID string
+		if user := tool.UserFromContext(ctx); user != nil {
+			userID = user.ID
+		}
+		allowed, err := s.rateLimiter.Allow(s.Name(), userID, s.rateLimit())
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate_limit: %w", err)
+		}
+		if !allowed {
+			return nil, domain.ErrRateLimitExceeded
+		}
+	}
+
 	repo := s.getRepo(params)
 	actionParams := s.getActionParams(params)
 
@@ -179,6 +195,22 @@ func (s *GitHubSkill) getRepo(params map[string]any) string {
 	return ""
 }
 
+// timeout returns the gh command timeout from the "timeout" config param (seconds).
+func (s *GitHubSkill) timeout() time.Duration {
+	if seconds, ok := s.config.Params["timeout"].(float64); ok && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	return defaultTimeout
+}
+
+// rateLimit returns the "rate_limit" config param, e.g. "30/minute".
+func (s *GitHubSkill) rateLimit() string {
+	if spec, ok := s.config.Params["rate_limit"].(string); ok && spec != "" {
+		return spec
+	}
+	return defaultRateLimit
+}
+
 // getActionParams extracts action-specific parameters
 func (s *GitHubSkill) getActionParams(params map[string]any) map[string]any {
 	if actionParams, ok := params["params"].(map[string]any); ok {
@@ -348,7 +380,7 @@ func (s *GitHubSkill) executeGH(ctx context.Context, args []string) (*executor.E
 	execReq := executor.ExecutionRequest{
 		Command: ghCommand,
 		Args:    args,
-		Timeout: defaultTimeout,
+		Timeout: s.timeout(),
 	}
 
 	execResult, err := s.executor.Execute(ctx, execReq)
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/tool"
+	"nuimanbot/internal/usecase/tool/common"
 	"nuimanbot/internal/usecase/tool/executor"
 	"nuimanbot/internal/usecase/tool/testutil"
 
@@ -216,6 +218,28 @@ func TestGitHubSkill_Execute_WithRateLimit(t *testing.T) {
 	assert.NotNil(t, result)
 }
 
+func TestGitHubSkill_Execute_RateLimitPerUser(t *testing.T) {
+	mockExec := testutil.NewMockExecutor()
+	mockExec.ExecuteFunc = func(ctx context.Context, req executor.ExecutionRequest) (*executor.ExecutionResult, error) {
+		return &executor.ExecutionResult{Stdout: `[]`}, nil
+	}
+
+	config := domain.ToolConfig{Enabled: true, Params: map[string]interface{}{"rate_limit": "1/minute"}}
+	skill := NewGitHubSkill(config, mockExec, common.NewRateLimiter(), nil)
+	params := map[string]any{"action": "issue_list", "repo": "owner/repo"}
+	alice := tool.ContextWithUser(context.Background(), &domain.User{ID: "alice"})
+	bob := tool.ContextWithUser(context.Background(), &domain.User{ID: "bob"})
+
+	_, err := skill.Execute(alice, params)
+	require.NoError(t, err)
+	_, err = skill.Execute(alice, params)
+	assert.ErrorIs(t, err, domain.ErrRateLimitExceeded)
+
+	// Another user's budget is untouched
+	_, err = skill.Execute(bob, params)
+	assert.NoError(t, err)
+}
+
 func TestGitHubSkill_Execute_OutputSanitization(t *testing.T) {
 	mockExec := testutil.NewMockExecutor()
 	mockExec.ExecuteFunc = func(ctx context.Context, req executor.ExecutionRequest) (*executor.ExecutionResult, error) {
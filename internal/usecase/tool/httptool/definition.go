@@ -0,0 +1,168 @@
+package httptool
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultTimeout          = 30 * time.Second
+	defaultMaxResponseBytes = 1024 * 1024 // 1MB
+)
+
+var toolNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// Definition describes an HTTP call exposed as a tool. Definitions are read
+// from YAML or JSON files:
+//
+//	name: jira_issue
+//	description: Look up a Jira issue by key
+//	method: GET
+//	url: https://jira.internal/rest/api/2/issue/{{key}}
+//	query:
+//	  fields: summary,status
+//	headers:
+//	  Authorization: "Bearer {{secret:jira_token}}"
+//	input_schema:
+//	  type: object
+//	  properties:
+//	    key: {type: string, pattern: "^[A-Z]+-[0-9]+$"}
+//	  required: [key]
+//	response:
+//	  path: $.fields.summary
+//	timeout: 10
+//
+// {{name}} placeholders are replaced with tool arguments (escaped for their
+// position in the URL, JSON-encoded in the body) and {{secret:key}}
+// placeholders with credentials from the vault. Placeholders are not allowed
+// in the URL's scheme or host.
+type Definition struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Method      string            `yaml:"method"`  // Defaults to GET
+	URL         string            `yaml:"url"`     // URL template
+	Query       map[string]string `yaml:"query"`   // Query parameter templates; omitted when an argument is missing
+	Headers     map[string]string `yaml:"headers"` // Header templates; omitted when an argument is missing
+	// Body is a JSON template for POST/PUT/PATCH. Without one, the
+	// arguments are sent as a JSON object. No body is sent when an
+	// argument it references is missing.
+	Body        string         `yaml:"body"`
+	InputSchema map[string]any `yaml:"input_schema"`
+	Response    struct {
+		Path     string `yaml:"path"`      // JSONPath of the value to return, e.g. $.items[*].name
+		MaxBytes int64  `yaml:"max_bytes"` // Response size limit; defaults to 1MB
+		MaxChars int    `yaml:"max_chars"` // Output beyond this is truncated; 0 means no limit
+	} `yaml:"response"`
+	Timeout int `yaml:"timeout"` // Seconds; defaults to 30
+
+	// OmitBody sends no body even for POST/PUT/PATCH, for generated
+	// definitions of operations that take none.
+	OmitBody bool `yaml:"-"`
+}
+
+// ParseDefinition parses and validates a YAML or JSON tool definition.
+func ParseDefinition(data []byte) (*Definition, error) {
+	var def Definition
+	if err := yaml.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("invalid definition: %w", err)
+	}
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// Validate checks the definition and fills in defaults.
+func (d *Definition) Validate() error {
+	if !toolNamePattern.MatchString(d.Name) {
+		return fmt.Errorf("name %q must be lowercase letters, digits and underscores", d.Name)
+	}
+	if strings.TrimSpace(d.Description) == "" {
+		return fmt.Errorf("description is required")
+	}
+
+	d.Method = strings.ToUpper(d.Method)
+	switch d.Method {
+	case "":
+		d.Method = http.MethodGet
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return fmt.Errorf("unsupported method %q", d.Method)
+	}
+	if d.Body != "" && !d.hasBody() {
+		return fmt.Errorf("body is not allowed for %s", d.Method)
+	}
+
+	if err := validateURLTemplate(d.URL); err != nil {
+		return err
+	}
+
+	if d.InputSchema == nil {
+		d.InputSchema = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	if t, _ := d.InputSchema["type"].(string); t != "object" {
+		return fmt.Errorf("input_schema must be an object schema")
+	}
+
+	if d.Response.Path != "" {
+		if _, err := parsePath(d.Response.Path); err != nil {
+			return fmt.Errorf("response.path: %w", err)
+		}
+	}
+	if d.Response.MaxBytes < 0 || d.Response.MaxChars < 0 || d.Timeout < 0 {
+		return fmt.Errorf("response.max_bytes, response.max_chars and timeout must not be negative")
+	}
+	if d.Response.MaxBytes == 0 {
+		d.Response.MaxBytes = defaultMaxResponseBytes
+	}
+	return nil
+}
+
+// timeout returns the request timeout.
+func (d *Definition) timeout() time.Duration {
+	if d.Timeout > 0 {
+		return time.Duration(d.Timeout) * time.Second
+	}
+	return defaultTimeout
+}
+
+// hasBody reports whether the method sends a request body.
+func (d *Definition) hasBody() bool {
+	if d.OmitBody {
+		return false
+	}
+	return d.Method == http.MethodPost || d.Method == http.MethodPut || d.Method == http.MethodPatch
+}
+
+// validateURLTemplate requires an absolute http(s) URL whose scheme and host
+// are fixed, so arguments can never redirect the request to another server.
+func validateURLTemplate(tmpl string) error {
+	if tmpl == "" {
+		return fmt.Errorf("url is required")
+	}
+	parsed, err := url.Parse(placeholderPattern.ReplaceAllString(tmpl, "x"))
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("url must use http or https")
+	}
+	prefix := tmpl
+	if i := strings.Index(tmpl, "://"); i >= 0 {
+		rest := tmpl[i+3:]
+		if j := strings.IndexAny(rest, "/?#"); j >= 0 {
+			rest = rest[:j]
+		}
+		prefix = tmpl[:i+3] + rest
+	}
+	if placeholderPattern.MatchString(prefix) {
+		return fmt.Errorf("url scheme and host must not contain placeholders")
+	}
+	return nil
+}
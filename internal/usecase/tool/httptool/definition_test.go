@@ -0,0 +1,131 @@
+package httptool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDefinition_Defaults(t *testing.T) {
+	def, err := ParseDefinition([]byte(`
+name: status
+description: Service status
+url: https://status.example.com/api/{{service}}
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "GET", def.Method)
+	assert.Equal(t, int64(defaultMaxResponseBytes), def.Response.MaxBytes)
+	assert.Equal(t, defaultTimeout, def.timeout())
+	assert.Equal(t, "object", def.InputSchema["type"])
+}
+
+func TestParseDefinition_JSON(t *testing.T) {
+	def, err := ParseDefinition([]byte(`{
+		"name": "create_ticket",
+		"description": "Create a ticket",
+		"method": "post",
+		"url": "https://tickets.example.com/api",
+		"body": "{\"title\": {{title}}}",
+		"timeout": 5
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "POST", def.Method)
+	assert.True(t, def.hasBody())
+	assert.Equal(t, "5s", def.timeout().String())
+}
+
+func TestParseDefinition_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name:    "invalid name",
+			yaml:    "name: Bad-Name\ndescription: x\nurl: https://example.com",
+			wantErr: "name",
+		},
+		{
+			name:    "missing description",
+			yaml:    "name: t\nurl: https://example.com",
+			wantErr: "description",
+		},
+		{
+			name:    "unsupported method",
+			yaml:    "name: t\ndescription: x\nmethod: TRACE\nurl: https://example.com",
+			wantErr: "unsupported method",
+		},
+		{
+			name:    "body on GET",
+			yaml:    "name: t\ndescription: x\nurl: https://example.com\nbody: '{}'",
+			wantErr: "body is not allowed",
+		},
+		{
+			name:    "non-http scheme",
+			yaml:    "name: t\ndescription: x\nurl: file:///etc/passwd",
+			wantErr: "http or https",
+		},
+		{
+			name:    "placeholder in host",
+			yaml:    "name: t\ndescription: x\nurl: https://{{host}}/api",
+			wantErr: "must not contain placeholders",
+		},
+		{
+			name:    "non-object schema",
+			yaml:    "name: t\ndescription: x\nurl: https://example.com\ninput_schema: {type: string}",
+			wantErr: "object schema",
+		},
+		{
+			name:    "bad response path",
+			yaml:    "name: t\ndescription: x\nurl: https://example.com\nresponse: {path: items}",
+			wantErr: "response.path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDefinition([]byte(tt.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestExtractPath(t *testing.T) {
+	doc := map[string]any{
+		"data": map[string]any{
+			"items": []any{
+				map[string]any{"name": "a", "tags": []any{"x"}},
+				map[string]any{"name": "b", "tags": []any{"y", "z"}},
+			},
+			"my key": "spaced",
+		},
+	}
+
+	tests := []struct {
+		path string
+		want any
+	}{
+		{"$", doc},
+		{"$.data.items[0].name", "a"},
+		{"$.data.items[-1].name", "b"},
+		{"$.data['my key']", "spaced"},
+		{"$.data.items[*].name", []any{"a", "b"}},
+		{"$.data.items[1].tags.*", []any{"y", "z"}},
+		{"$.data.missing[*]", []any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := extractPath(doc, tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := extractPath(doc, "$.data.missing")
+	assert.ErrorContains(t, err, "matched nothing")
+}
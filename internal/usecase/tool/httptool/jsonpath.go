@@ -0,0 +1,140 @@
+package httptool
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pathStep is one step of a parsed JSONPath: a member name, an array index
+// or a wildcard over all members or elements.
+type pathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parsePath parses the JSONPath subset used for response extraction:
+// $, .name, ['name'], [n] (negative counts from the end), [*] and .*
+func parsePath(path string) ([]pathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path must start with $")
+	}
+
+	var steps []pathStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("empty member name in %q", path)
+			}
+			if name == "*" {
+				steps = append(steps, pathStep{wildcard: true})
+			} else {
+				steps = append(steps, pathStep{key: name})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed [ in %q", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			switch {
+			case inner == "*":
+				steps = append(steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, pathStep{key: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in %q", inner, path)
+				}
+				steps = append(steps, pathStep{index: n, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", rest[0], path)
+		}
+	}
+	return steps, nil
+}
+
+// extractPath evaluates a JSONPath against a decoded JSON document. Paths
+// with a wildcard return the list of matches; others return the single match.
+func extractPath(doc any, path string) (any, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []any{doc}
+	multi := false
+	for _, step := range steps {
+		var next []any
+		for _, node := range nodes {
+			switch {
+			case step.wildcard:
+				switch v := node.(type) {
+				case []any:
+					next = append(next, v...)
+				case map[string]any:
+					for _, key := range sortedKeys(v) {
+						next = append(next, v[key])
+					}
+				}
+			case step.isIndex:
+				arr, ok := node.([]any)
+				if !ok {
+					continue
+				}
+				i := step.index
+				if i < 0 {
+					i += len(arr)
+				}
+				if i >= 0 && i < len(arr) {
+					next = append(next, arr[i])
+				}
+			default:
+				if obj, ok := node.(map[string]any); ok {
+					if v, ok := obj[step.key]; ok {
+						next = append(next, v)
+					}
+				}
+			}
+		}
+		if step.wildcard {
+			multi = true
+		}
+		nodes = next
+	}
+
+	if multi {
+		if nodes == nil {
+			nodes = []any{}
+		}
+		return nodes, nil
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("path %s matched nothing", path)
+	}
+	return nodes[0], nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
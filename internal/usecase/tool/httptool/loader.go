@@ -0,0 +1,186 @@
+package httptool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nuimanbot/internal/domain"
+)
+
+// DefaultWatchInterval is how often watched directories are rescanned.
+const DefaultWatchInterval = 2 * time.Second
+
+// Registry is the subset of tool.InMemoryRegistry the loader needs to add
+// and replace tools.
+type Registry interface {
+	Register(tool domain.Tool) error
+	Unregister(name string) error
+}
+
+// loadedFile tracks a definition file between scans.
+type loadedFile struct {
+	modTime time.Time
+	size    int64
+	tool    domain.Tool // Last good tool registered from the file; nil if it never loaded
+}
+
+// Loader registers HTTP tools from definition files (*.yaml, *.yml, *.json)
+// in a set of directories and keeps the registry in sync as files change.
+type Loader struct {
+	dirs     []string
+	registry Registry
+	vault    domain.CredentialVault
+	client   *http.Client
+
+	mu    sync.Mutex
+	files map[string]loadedFile
+}
+
+// NewLoader creates a loader. The vault resolves {{secret:key}} placeholders.
+func NewLoader(dirs []string, registry Registry, vault domain.CredentialVault) *Loader {
+	return &Loader{
+		dirs:     dirs,
+		registry: registry,
+		vault:    vault,
+		client:   &http.Client{},
+		files:    make(map[string]loadedFile),
+	}
+}
+
+// Load scans the directories and registers every definition. It returns all
+// invalid definitions and name conflicts together.
+func (l *Loader) Load() error {
+	return l.sync()
+}
+
+// Watch rescans the directories every interval until ctx is cancelled,
+// registering new definitions, replacing changed ones and removing deleted
+// ones. A definition that becomes invalid keeps its last good version.
+func (l *Loader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.sync(); err != nil {
+				slog.Error("Failed to reload HTTP tools", "error", err)
+			}
+		}
+	}
+}
+
+// sync reconciles the registry with the definition files on disk.
+func (l *Loader) sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	paths, errs := l.scan()
+	seen := make(map[string]bool, len(paths))
+
+	for _, path := range paths {
+		seen[path] = true
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // Removed between scan and stat; handled next time
+		}
+
+		prev, known := l.files[path]
+		if known && prev.modTime.Equal(info.ModTime()) && prev.size == info.Size() {
+			continue
+		}
+
+		current := loadedFile{modTime: info.ModTime(), size: info.Size(), tool: prev.tool}
+		if err := l.loadFile(path, &current); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+		l.files[path] = current
+	}
+
+	for path, file := range l.files {
+		if seen[path] {
+			continue
+		}
+		if file.tool != nil {
+			if err := l.registry.Unregister(file.tool.Name()); err == nil {
+				slog.Info("HTTP tool removed", "tool", file.tool.Name(), "file", path)
+			}
+		}
+		delete(l.files, path)
+	}
+
+	return errors.Join(errs...)
+}
+
+// loadFile parses a definition and registers it in place of the file's
+// previous tool. On failure the previous tool stays registered.
+func (l *Loader) loadFile(path string, file *loadedFile) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	def, err := ParseDefinition(data)
+	if err != nil {
+		return err
+	}
+
+	t := NewTool(def, l.client, l.vault)
+	t.SetSource(path)
+
+	previous := file.tool
+	if previous != nil {
+		if err := l.registry.Unregister(previous.Name()); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", previous.Name(), err)
+		}
+	}
+	if err := l.registry.Register(t); err != nil {
+		if previous != nil {
+			if restoreErr := l.registry.Register(previous); restoreErr != nil {
+				slog.Error("Failed to restore HTTP tool", "tool", previous.Name(), "file", path, "error", restoreErr)
+			}
+		}
+		return err
+	}
+
+	file.tool = t
+	slog.Info("HTTP tool loaded", "tool", def.Name, "file", path)
+	return nil
+}
+
+// scan lists definition files in the configured directories.
+func (l *Loader) scan() ([]string, []error) {
+	var paths []string
+	var errs []error
+	for _, dir := range l.dirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // May be created later
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read %s: %w", dir, err))
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".yaml", ".yml", ".json":
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, errs
+}
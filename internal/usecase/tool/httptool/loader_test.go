@@ -0,0 +1,83 @@
+package httptool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nuimanbot/internal/usecase/tool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDefinition(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	// Set the time explicitly so changes within one filesystem tick are seen
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestLoader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	registry := tool.NewInMemoryRegistry()
+	loader := NewLoader([]string{dir, filepath.Join(dir, "missing")}, registry, nil)
+	now := time.Now()
+
+	path := filepath.Join(dir, "status.yaml")
+	writeDefinition(t, path, "name: status\ndescription: v1\nurl: https://example.com", now)
+	writeDefinition(t, filepath.Join(dir, "notes.txt"), "ignored", now)
+
+	require.NoError(t, loader.Load())
+	loaded, err := registry.Get("status")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", loaded.Description())
+	assert.Equal(t, path, loaded.Config().Params["source"])
+	assert.Len(t, registry.List(), 1)
+
+	// Modified definition replaces the tool
+	writeDefinition(t, path, "name: status\ndescription: v2\nurl: https://example.com", now.Add(time.Second))
+	require.NoError(t, loader.sync())
+	loaded, err = registry.Get("status")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", loaded.Description())
+
+	// Invalid definition keeps the last good version
+	writeDefinition(t, path, "name: status\nurl: https://example.com", now.Add(2*time.Second))
+	assert.ErrorContains(t, loader.sync(), "description is required")
+	loaded, err = registry.Get("status")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", loaded.Description())
+
+	// Renamed definition replaces the old name
+	writeDefinition(t, path, "name: status_v3\ndescription: v3\nurl: https://example.com", now.Add(3*time.Second))
+	require.NoError(t, loader.sync())
+	_, err = registry.Get("status")
+	assert.Error(t, err)
+	_, err = registry.Get("status_v3")
+	assert.NoError(t, err)
+
+	// Removed file unregisters the tool
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, loader.sync())
+	assert.Empty(t, registry.List())
+}
+
+func TestLoader_NameConflict(t *testing.T) {
+	dir := t.TempDir()
+	registry := tool.NewInMemoryRegistry()
+	loader := NewLoader([]string{dir}, registry, nil)
+	now := time.Now()
+
+	writeDefinition(t, filepath.Join(dir, "a.yaml"), "name: dup\ndescription: a\nurl: https://example.com", now)
+	writeDefinition(t, filepath.Join(dir, "b.json"), `{"name": "dup", "description": "b", "url": "https://example.com"}`, now)
+
+	err := loader.Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "b.json")
+
+	loaded, err := registry.Get("dup")
+	require.NoError(t, err)
+	assert.Equal(t, "a", loaded.Description())
+}
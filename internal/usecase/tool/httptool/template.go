@@ -0,0 +1,153 @@
+package httptool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"nuimanbot/internal/domain"
+)
+
+// placeholderPattern matches {{name}} and {{secret:key}} placeholders.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+const secretPrefix = "secret:"
+
+// errMissingArgument is returned when a template references an argument the
+// call did not provide.
+type errMissingArgument struct{ name string }
+
+func (e errMissingArgument) Error() string {
+	return fmt.Sprintf("missing argument %q", e.name)
+}
+
+// renderer substitutes placeholders for one tool call.
+type renderer struct {
+	ctx   context.Context
+	args  map[string]any
+	vault domain.CredentialVault
+}
+
+// render replaces placeholders in tmpl, passing each substituted value
+// through escape. Secrets are resolved from the vault.
+func (r *renderer) render(tmpl string, escape func(any) (string, error)) (string, error) {
+	var firstErr error
+	out := placeholderPattern.ReplaceAllStringFunc(tmpl, func(match string) string {
+		if firstErr != nil {
+			return ""
+		}
+		key := placeholderPattern.FindStringSubmatch(match)[1]
+
+		var value any
+		if name, ok := strings.CutPrefix(key, secretPrefix); ok {
+			secret, err := r.secret(name)
+			if err != nil {
+				firstErr = err
+				return ""
+			}
+			value = secret
+		} else {
+			v, ok := r.args[key]
+			if !ok || v == nil {
+				firstErr = errMissingArgument{name: key}
+				return ""
+			}
+			value = v
+		}
+
+		s, err := escape(value)
+		if err != nil {
+			firstErr = err
+			return ""
+		}
+		return s
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
+
+func (r *renderer) secret(key string) (string, error) {
+	if r.vault == nil {
+		return "", fmt.Errorf("secret %q: no credential vault configured", key)
+	}
+	secret, err := r.vault.Retrieve(r.ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("secret %q: %w", key, err)
+	}
+	return secret.Value(), nil
+}
+
+// renderURL renders a URL template, path-escaping values before the query
+// string and query-escaping values after it.
+func (r *renderer) renderURL(tmpl string) (string, error) {
+	path, query, hasQuery := strings.Cut(tmpl, "?")
+	rendered, err := r.render(path, escaped(url.PathEscape))
+	if err != nil {
+		return "", err
+	}
+	if hasQuery {
+		q, err := r.render(query, escaped(url.QueryEscape))
+		if err != nil {
+			return "", err
+		}
+		rendered += "?" + q
+	}
+	return rendered, nil
+}
+
+// renderText renders a template with plain values, e.g. for query
+// parameters that are encoded afterwards.
+func (r *renderer) renderText(tmpl string) (string, error) {
+	return r.render(tmpl, func(v any) (string, error) {
+		return formatValue(v), nil
+	})
+}
+
+// renderHeader renders a header value, rejecting values that would split the header.
+func (r *renderer) renderHeader(tmpl string) (string, error) {
+	return r.render(tmpl, func(v any) (string, error) {
+		s := formatValue(v)
+		if strings.ContainsAny(s, "\r\n") {
+			return "", fmt.Errorf("header values must not contain line breaks")
+		}
+		return s, nil
+	})
+}
+
+// renderJSON renders a JSON body template with JSON-encoded values.
+func (r *renderer) renderJSON(tmpl string) (string, error) {
+	return r.render(tmpl, func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	})
+}
+
+func escaped(escape func(string) string) func(any) (string, error) {
+	return func(v any) (string, error) {
+		return escape(formatValue(v)), nil
+	}
+}
+
+// formatValue formats an argument for text positions (URL, headers).
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
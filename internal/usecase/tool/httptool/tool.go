@@ -0,0 +1,207 @@
+package httptool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"nuimanbot/internal/domain"
+)
+
+// maxErrorBodyChars bounds how much of a failed response is shown to the LLM.
+const maxErrorBodyChars = 500
+
+// Tool is a domain.Tool backed by a declarative HTTP definition.
+type Tool struct {
+	def    *Definition
+	client *http.Client
+	vault  domain.CredentialVault
+	source string // File the definition was loaded from
+}
+
+// NewTool creates a tool from a validated definition. The vault resolves
+// {{secret:key}} placeholders and may be nil if none are used.
+func NewTool(def *Definition, client *http.Client, vault domain.CredentialVault) *Tool {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &Tool{def: def, client: client, vault: vault}
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return t.def.Name
+}
+
+// Description returns the tool description.
+func (t *Tool) Description() string {
+	return t.def.Description
+}
+
+// InputSchema returns the definition's input schema.
+func (t *Tool) InputSchema() map[string]any {
+	return t.def.InputSchema
+}
+
+// RequiredPermissions returns the permissions needed to execute this tool.
+func (t *Tool) RequiredPermissions() []domain.Permission {
+	return []domain.Permission{domain.PermissionNetwork}
+}
+
+// SetSource records where the definition came from (a file or spec path),
+// reported in the tool config.
+func (t *Tool) SetSource(source string) {
+	t.source = source
+}
+
+// Config returns the tool configuration.
+func (t *Tool) Config() domain.ToolConfig {
+	return domain.ToolConfig{
+		Enabled: true,
+		Params:  map[string]any{"source": t.source},
+	}
+}
+
+// Execute performs the HTTP call and returns the extracted response.
+func (t *Tool) Execute(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.def.timeout())
+	defer cancel()
+
+	req, err := t.buildRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.def.Response.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > t.def.Response.MaxBytes {
+		return nil, fmt.Errorf("response exceeds %d bytes", t.def.Response.MaxBytes)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet := string(body)
+		if len(snippet) > maxErrorBodyChars {
+			snippet = snippet[:maxErrorBodyChars] + "..."
+		}
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(snippet))
+	}
+
+	output, err := t.extract(body)
+	if err != nil {
+		return nil, err
+	}
+	if limit := t.def.Response.MaxChars; limit > 0 && len(output) > limit {
+		output = fmt.Sprintf("%s\n... [truncated %d characters]", strings.ToValidUTF8(output[:limit], ""), len(output)-limit)
+	}
+
+	return &domain.ExecutionResult{
+		Output: output,
+		Metadata: map[string]any{
+			"status_code": resp.StatusCode,
+			"url":         req.URL.Scheme + "://" + req.URL.Host + req.URL.Path, // Query may carry secrets
+		},
+	}, nil
+}
+
+// buildRequest renders the definition's templates for one call.
+func (t *Tool) buildRequest(ctx context.Context, params map[string]any) (*http.Request, error) {
+	r := &renderer{ctx: ctx, args: params, vault: t.vault}
+
+	target, err := r.renderURL(t.def.URL)
+	if err != nil {
+		return nil, fmt.Errorf("url: %w", err)
+	}
+
+	var body io.Reader
+	if t.def.hasBody() {
+		var payload []byte
+		if t.def.Body != "" {
+			rendered, err := r.renderJSON(t.def.Body)
+			switch {
+			case errors.As(err, new(errMissingArgument)):
+				payload = nil // Optional body not provided
+			case err != nil:
+				return nil, fmt.Errorf("body: %w", err)
+			default:
+				payload = []byte(rendered)
+			}
+		} else if payload, err = json.Marshal(params); err != nil {
+			return nil, fmt.Errorf("body: %w", err)
+		}
+		if payload != nil {
+			body = bytes.NewReader(payload)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, t.def.Method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	query := req.URL.Query()
+	for name, tmpl := range t.def.Query {
+		value, err := r.renderText(tmpl)
+		if errors.As(err, new(errMissingArgument)) {
+			continue // Optional argument not provided
+		}
+		if err != nil {
+			return nil, fmt.Errorf("query %s: %w", name, err)
+		}
+		query.Set(name, value)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	for name, tmpl := range t.def.Headers {
+		value, err := r.renderHeader(tmpl)
+		if errors.As(err, new(errMissingArgument)) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", name, err)
+		}
+		req.Header.Set(name, value)
+	}
+
+	return req, nil
+}
+
+// extract applies the response path, if any, and formats the result.
+func (t *Tool) extract(body []byte) (string, error) {
+	if t.def.Response.Path == "" {
+		return string(body), nil
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return "", fmt.Errorf("response is not JSON: %w", err)
+	}
+	value, err := extractPath(doc, t.def.Response.Path)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	out, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to format response: %w", err)
+	}
+	return string(out), nil
+}
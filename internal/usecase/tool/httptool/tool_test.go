@@ -0,0 +1,146 @@
+package httptool
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault is an in-memory CredentialVault.
+type fakeVault struct {
+	domain.CredentialVault
+	secrets map[string]string
+}
+
+func (v *fakeVault) Retrieve(ctx context.Context, key string) (domain.SecureString, error) {
+	s, ok := v.secrets[key]
+	if !ok {
+		return domain.SecureString{}, domain.ErrNotFound
+	}
+	return domain.NewSecureStringFromString(s), nil
+}
+
+func newTestTool(t *testing.T, yaml string, vault domain.CredentialVault) *Tool {
+	t.Helper()
+	def, err := ParseDefinition([]byte(yaml))
+	require.NoError(t, err)
+	return NewTool(def, nil, vault)
+}
+
+func TestTool_Execute_GET(t *testing.T) {
+	var gotPath, gotQuery, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotQuery = r.URL.RawQuery
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"fields": {"summary": "Fix login", "labels": ["bug", "auth"]}}`))
+	}))
+	defer server.Close()
+
+	tool := newTestTool(t, `
+name: issue
+description: Look up an issue
+url: `+server.URL+`/issue/{{key}}?expand={{expand}}
+query:
+  fields: "{{fields}}"
+  optional: "{{missing}}"
+headers:
+  Authorization: "Bearer {{secret:token}}"
+response:
+  path: $.fields.summary
+`, &fakeVault{secrets: map[string]string{"token": "s3cret"}})
+
+	result, err := tool.Execute(context.Background(), map[string]any{
+		"key":    "A/B 1",
+		"expand": "a&b",
+		"fields": "summary,status",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Fix login", result.Output)
+	assert.Equal(t, "/issue/A%2FB%201", gotPath)
+	assert.Contains(t, gotQuery, "expand=a%26b")
+	assert.Contains(t, gotQuery, "fields=summary%2Cstatus")
+	assert.NotContains(t, gotQuery, "optional")
+	assert.Equal(t, "Bearer s3cret", gotAuth)
+	assert.Equal(t, http.StatusOK, result.Metadata["status_code"])
+	assert.NotContains(t, result.Metadata["url"], "expand")
+}
+
+func TestTool_Execute_BodyTemplate(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &got))
+		_, _ = w.Write([]byte(`{"items": [{"id": 1}, {"id": 2}]}`))
+	}))
+	defer server.Close()
+
+	tool := newTestTool(t, `
+name: create
+description: Create a ticket
+method: POST
+url: `+server.URL+`/tickets
+body: '{"title": {{title}}, "priority": {{priority}}}'
+response:
+  path: $.items[*].id
+`, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]any{
+		"title":    `Say "hi"`,
+		"priority": float64(2),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"title": `Say "hi"`, "priority": float64(2)}, got)
+	assert.JSONEq(t, `[1, 2]`, result.Output)
+}
+
+func TestTool_Execute_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(strings.Repeat("x", 1000)))
+	}))
+	defer server.Close()
+
+	tool := newTestTool(t, "name: t\ndescription: x\nurl: "+server.URL+"/{{id}}", nil)
+
+	_, err := tool.Execute(context.Background(), map[string]any{"id": "1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 404")
+	assert.Less(t, len(err.Error()), 600)
+
+	_, err = tool.Execute(context.Background(), map[string]any{})
+	assert.ErrorContains(t, err, `missing argument "id"`)
+
+	secretTool := newTestTool(t, "name: t\ndescription: x\nurl: "+server.URL+"\nheaders: {X-Key: '{{secret:absent}}'}", &fakeVault{})
+	_, err = secretTool.Execute(context.Background(), map[string]any{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	headerTool := newTestTool(t, "name: t\ndescription: x\nurl: "+server.URL+"\nheaders: {X-Name: '{{name}}'}", nil)
+	_, err = headerTool.Execute(context.Background(), map[string]any{"name": "a\r\nX-Injected: 1"})
+	assert.ErrorContains(t, err, "line breaks")
+}
+
+func TestTool_Execute_ResponseTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 200)))
+	}))
+	defer server.Close()
+
+	tool := newTestTool(t, "name: t\ndescription: x\nurl: "+server.URL+"\nresponse: {max_bytes: 100}", nil)
+
+	_, err := tool.Execute(context.Background(), map[string]any{})
+	assert.ErrorContains(t, err, "exceeds 100 bytes")
+}
@@ -66,3 +66,15 @@ func (r *InMemoryRegistry) List() []domain.Tool {
 func (r *InMemoryRegistry) ListForUser(ctx context.Context, userID string) ([]domain.Tool, error) {
 	return r.List(), nil
 }
+
+// Unregister removes a tool from the registry.
+func (r *InMemoryRegistry) Unregister(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tools[name]; !ok {
+		return fmt.Errorf("tool not found: %s", name)
+	}
+	delete(r.tools, name)
+	return nil
+}
@@ -140,6 +140,22 @@ func TestGet_NotFound(t *testing.T) {
 	}
 }
 
+// TestUnregister tests removing a registered tool
+func TestUnregister(t *testing.T) {
+	registry := NewInMemoryRegistry()
+	registry.Register(&mockTool{name: "test_skill", description: "Test"})
+
+	if err := registry.Unregister("test_skill"); err != nil {
+		t.Fatalf("Unregister() returned error: %v", err)
+	}
+	if _, err := registry.Get("test_skill"); err == nil {
+		t.Error("Get() should error after Unregister()")
+	}
+	if err := registry.Unregister("test_skill"); err == nil {
+		t.Error("Unregister() should error for non-existent tool")
+	}
+}
+
 // TestList tests listing all registered skills
 func TestList(t *testing.T) {
 	registry := NewInMemoryRegistry()
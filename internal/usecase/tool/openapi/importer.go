@@ -0,0 +1,223 @@
+// Package openapi generates HTTP tools from OpenAPI 3 documents.
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"nuimanbot/internal/usecase/tool/httptool"
+)
+
+const (
+	defaultMaxResponseChars = 8000
+	maxDescriptionChars     = 1024
+	maxToolNameLength       = 64
+)
+
+// methods lists the operations that can be exposed, in a stable order.
+var methods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+var (
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+	camelBoundary    = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	invalidArgChars  = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+	serverVariable   = regexp.MustCompile(`\{([^{}]+)\}`)
+)
+
+// Options selects and configures the operations to import.
+type Options struct {
+	Prefix           string   // Tool name prefix
+	BaseURL          string   // Overrides the document's first server URL
+	Include          []string // operationIds or "METHOD /path" patterns; empty includes all
+	Exclude          []string // Applied after Include
+	AuthScheme       string   // securitySchemes entry; defaults to the first one an operation requires
+	VaultKey         string   // Vault key of the credential; empty disables auth
+	MaxResponseChars int      // Defaults to 8000
+	Timeout          int      // Seconds; 0 uses the HTTP tool default
+}
+
+// LoadFile reads an OpenAPI document and generates tool definitions for the
+// selected operations.
+func LoadFile(path string, opts Options) ([]*httptool.Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI spec: %w", err)
+	}
+	defs, err := Import(data, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return defs, nil
+}
+
+// Import generates tool definitions for the selected operations of an
+// OpenAPI 3 document. Operations that can't be expressed as a tool (e.g.
+// non-JSON request bodies) are skipped with a warning.
+func Import(data []byte, opts Options) ([]*httptool.Definition, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	normalized, err := normalize(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	root, ok := normalized.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid OpenAPI document: expected an object")
+	}
+	doc := document(root)
+
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q: only 3.x is supported", version)
+	}
+
+	baseURL, err := doc.baseURL(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxResponseChars == 0 {
+		opts.MaxResponseChars = defaultMaxResponseChars
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	pathNames := make([]string, 0, len(paths))
+	for p := range paths {
+		pathNames = append(pathNames, p)
+	}
+	sort.Strings(pathNames)
+
+	var defs []*httptool.Definition
+	var errs []error
+	names := map[string]string{}
+	for _, path := range pathNames {
+		item, err := doc.resolve(paths[path])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("paths.%s: %w", path, err))
+			continue
+		}
+		for _, method := range methods {
+			op, ok := item[strings.ToLower(method)].(map[string]any)
+			if !ok {
+				continue
+			}
+			id, _ := op["operationId"].(string)
+			if id == "" {
+				id = strings.ToLower(method) + " " + path
+			}
+			if !selected(opts, id, method+" "+path) {
+				continue
+			}
+
+			b := &builder{doc: doc, opts: opts, baseURL: baseURL, method: method, path: path, item: item, op: op}
+			def, err := b.build(toolName(opts.Prefix, id))
+			var skip skipError
+			if errors.As(err, &skip) {
+				slog.Warn("Skipping OpenAPI operation", "operation", id, "reason", skip.reason)
+				continue
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", method, path, err))
+				continue
+			}
+			if other, ok := names[def.Name]; ok {
+				errs = append(errs, fmt.Errorf("%s %s: tool name %s is also used by %s", method, path, def.Name, other))
+				continue
+			}
+			names[def.Name] = method + " " + path
+			defs = append(defs, def)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+// baseURL returns the override or the first server URL with its variables
+// set to their defaults.
+func (d document) baseURL(override string) (string, error) {
+	if override != "" {
+		return strings.TrimRight(override, "/"), nil
+	}
+
+	servers, _ := d["servers"].([]any)
+	if len(servers) == 0 {
+		return "", fmt.Errorf("document has no servers; set base_url")
+	}
+	server, _ := servers[0].(map[string]any)
+	serverURL, _ := server["url"].(string)
+	variables, _ := server["variables"].(map[string]any)
+	serverURL = serverVariable.ReplaceAllStringFunc(serverURL, func(match string) string {
+		variable, _ := variables[match[1:len(match)-1]].(map[string]any)
+		if def, ok := variable["default"].(string); ok {
+			return def
+		}
+		return match
+	})
+
+	if !strings.HasPrefix(serverURL, "http://") && !strings.HasPrefix(serverURL, "https://") {
+		return "", fmt.Errorf("server URL %q is not absolute; set base_url", serverURL)
+	}
+	return strings.TrimRight(serverURL, "/"), nil
+}
+
+// selected applies the include and exclude patterns to an operation.
+func selected(opts Options, id, route string) bool {
+	matches := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if matchPattern(pattern, id) || matchPattern(pattern, route) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(opts.Include) > 0 && !matches(opts.Include) {
+		return false
+	}
+	return !matches(opts.Exclude)
+}
+
+// matchPattern matches s against a pattern where * matches any characters.
+func matchPattern(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re, err := regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+	return err == nil && re.MatchString(s)
+}
+
+// toolName derives a snake_case tool name from an operationId.
+func toolName(prefix, id string) string {
+	name := camelBoundary.ReplaceAllString(prefix+id, "${1}_${2}")
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		name = "op_" + name
+	}
+	if len(name) > maxToolNameLength {
+		name = strings.TrimRight(name[:maxToolNameLength], "_")
+	}
+	return name
+}
+
+// argName makes a parameter name usable as a tool argument.
+func argName(name string) string {
+	return strings.Trim(invalidArgChars.ReplaceAllString(name, "_"), "_")
+}
+
+// skipError marks an operation that can't be exposed as a tool.
+type skipError struct{ reason string }
+
+func (e skipError) Error() string { return e.reason }
@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/tool/httptool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore = `
+openapi: 3.0.3
+info: {title: Petstore, version: "1.0"}
+servers:
+  - url: https://{region}.pets.example.com/v1
+    variables:
+      region: {default: eu}
+security:
+  - apiKey: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+        - {name: X-Request-ID, in: header, schema: {type: string}}
+      responses:
+        200: {description: ok}
+    post:
+      operationId: createPet
+      summary: Create a pet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/NewPet'}
+      responses:
+        201: {description: created}
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    get:
+      operationId: showPetById
+      description: Info for a specific pet
+      security: []
+      responses:
+        200: {description: ok}
+    delete:
+      operationId: deletePet
+      responses:
+        204: {description: deleted}
+  /pets/{petId}/photo:
+    put:
+      operationId: uploadPhoto
+      parameters:
+        - $ref: '#/components/parameters/PetId'
+      requestBody:
+        required: true
+        content:
+          image/png: {}
+      responses:
+        200: {description: ok}
+components:
+  parameters:
+    PetId: {name: petId, in: path, required: true, description: Pet ID, schema: {type: string}}
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, example: Rex}
+        tag: {type: string, nullable: true}
+        owner: {$ref: '#/components/schemas/Owner'}
+    Owner:
+      type: object
+      properties:
+        name: {type: string}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+`
+
+func definitionsByName(t *testing.T, defs []*httptool.Definition) map[string]*httptool.Definition {
+	t.Helper()
+	byName := make(map[string]*httptool.Definition, len(defs))
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+	return byName
+}
+
+func TestImport(t *testing.T) {
+	defs, err := Import([]byte(petstore), Options{Prefix: "pets_", VaultKey: "pets_key"})
+	require.NoError(t, err)
+
+	byName := definitionsByName(t, defs)
+	assert.Len(t, byName, 4, "uploadPhoto has a non-JSON body and is skipped")
+
+	list := byName["pets_list_pets"]
+	require.NotNil(t, list)
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, "https://eu.pets.example.com/v1/pets", list.URL)
+	assert.Equal(t, "{{limit}}", list.Query["limit"])
+	assert.Equal(t, "{{X_Request_ID}}", list.Headers["X-Request-ID"])
+	assert.Equal(t, "{{secret:pets_key}}", list.Headers["X-API-Key"])
+	assert.Equal(t, defaultMaxResponseChars, list.Response.MaxChars)
+	props := list.InputSchema["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "integer", "maximum": float64(100)}, props["limit"])
+	assert.Nil(t, list.InputSchema["required"])
+
+	create := byName["pets_create_pet"]
+	require.NotNil(t, create)
+	assert.Equal(t, "{{body}}", create.Body)
+	assert.Equal(t, []any{"body"}, create.InputSchema["required"])
+	body := create.InputSchema["properties"].(map[string]any)["body"].(map[string]any)
+	bodyProps := body["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string"}, bodyProps["name"], "example is removed")
+	assert.Equal(t, []any{"string", "null"}, bodyProps["tag"].(map[string]any)["type"])
+	assert.Equal(t, "object", bodyProps["owner"].(map[string]any)["type"], "$ref is inlined")
+
+	show := byName["pets_show_pet_by_id"]
+	require.NotNil(t, show)
+	assert.Equal(t, "https://eu.pets.example.com/v1/pets/{{petId}}", show.URL)
+	assert.Equal(t, "Info for a specific pet", show.Description)
+	assert.Equal(t, []any{"petId"}, show.InputSchema["required"])
+	assert.Empty(t, show.Headers, "security: [] disables auth")
+
+	assert.Equal(t, "DELETE", byName["pets_delete_pet"].Method)
+}
+
+func TestImport_IncludeExclude(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{"include by id", []string{"listPets"}, nil, []string{"list_pets"}},
+		{"include by route", []string{"GET /pets*"}, nil, []string{"list_pets", "show_pet_by_id"}},
+		{"exclude", nil, []string{"DELETE *", "createPet"}, []string{"list_pets", "show_pet_by_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs, err := Import([]byte(petstore), Options{Include: tt.include, Exclude: tt.exclude})
+			require.NoError(t, err)
+
+			var names []string
+			for _, def := range defs {
+				names = append(names, def.Name)
+			}
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	_, err := Import([]byte(`swagger: "2.0"`), Options{})
+	assert.ErrorContains(t, err, "only 3.x")
+
+	_, err = Import([]byte("openapi: 3.0.0\nservers: [{url: /v1}]\npaths: {}"), Options{})
+	assert.ErrorContains(t, err, "set base_url")
+
+	_, err = Import([]byte(petstore), Options{VaultKey: "k", AuthScheme: "missing"})
+	assert.ErrorContains(t, err, `security scheme "missing" not found`)
+}
+
+// fakeVault is an in-memory CredentialVault.
+type fakeVault struct {
+	domain.CredentialVault
+	secrets map[string]string
+}
+
+func (v *fakeVault) Retrieve(ctx context.Context, key string) (domain.SecureString, error) {
+	s, ok := v.secrets[key]
+	if !ok {
+		return domain.SecureString{}, domain.ErrNotFound
+	}
+	return domain.NewSecureStringFromString(s), nil
+}
+
+func TestImport_ExecuteGeneratedTool(t *testing.T) {
+	var gotKey, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-API-Key")
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "` + strings.Repeat("x", 50) + `"}`))
+	}))
+	defer server.Close()
+
+	defs, err := Import([]byte(petstore), Options{
+		BaseURL:          server.URL,
+		Include:          []string{"createPet"},
+		VaultKey:         "pets_key",
+		MaxResponseChars: 20,
+	})
+	require.NoError(t, err)
+	require.Len(t, defs, 1)
+
+	tool := httptool.NewTool(defs[0], nil, &fakeVault{secrets: map[string]string{"pets_key": "k-123"}})
+	result, err := tool.Execute(context.Background(), map[string]any{
+		"body": map[string]any{"name": "Rex"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "k-123", gotKey)
+	var sent map[string]any
+	require.NoError(t, json.Unmarshal([]byte(gotBody), &sent))
+	assert.Equal(t, map[string]any{"name": "Rex"}, sent)
+	assert.Contains(t, result.Output, "[truncated")
+}
@@ -0,0 +1,292 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"nuimanbot/internal/usecase/tool/httptool"
+)
+
+// builder turns one OpenAPI operation into an HTTP tool definition.
+type builder struct {
+	doc     document
+	opts    Options
+	baseURL string
+	method  string
+	path    string
+	item    map[string]any // Path item, for shared parameters
+	op      map[string]any
+}
+
+func (b *builder) build(name string) (*httptool.Definition, error) {
+	def := &httptool.Definition{
+		Name:        name,
+		Description: b.description(),
+		Method:      b.method,
+		Query:       map[string]string{},
+		Headers:     map[string]string{},
+		Timeout:     b.opts.Timeout,
+	}
+	def.Response.MaxChars = b.opts.MaxResponseChars
+
+	props := map[string]any{}
+	var required []any
+	urlPath := b.path
+
+	params, err := b.parameters()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range params {
+		name, _ := p["name"].(string)
+		in, _ := p["in"].(string)
+		isRequired, _ := p["required"].(bool)
+
+		arg := argName(name)
+		if arg == "" {
+			return nil, skipError{fmt.Sprintf("parameter %q has no usable name", name)}
+		}
+		if _, ok := props[arg]; ok {
+			return nil, skipError{fmt.Sprintf("parameters map to the same argument %s", arg)}
+		}
+
+		placeholder := "{{" + arg + "}}"
+		switch in {
+		case "path":
+			urlPath = strings.ReplaceAll(urlPath, "{"+name+"}", placeholder)
+			isRequired = true
+		case "query":
+			def.Query[name] = placeholder
+		case "header":
+			def.Headers[name] = placeholder
+		default:
+			if isRequired {
+				return nil, skipError{fmt.Sprintf("required %s parameter %s is not supported", in, name)}
+			}
+			continue
+		}
+
+		schema := map[string]any{"type": "string"}
+		if raw, ok := p["schema"]; ok {
+			if schema, err = b.doc.schema(raw, 0); err != nil {
+				return nil, fmt.Errorf("parameter %s: %w", name, err)
+			}
+		}
+		if desc, ok := p["description"].(string); ok && schema["description"] == nil {
+			schema["description"] = desc
+		}
+		props[arg] = schema
+		if isRequired {
+			required = append(required, arg)
+		}
+	}
+
+	if undeclared := serverVariable.FindString(strings.ReplaceAll(strings.ReplaceAll(urlPath, "{{", ""), "}}", "")); undeclared != "" {
+		return nil, skipError{fmt.Sprintf("path parameter %s is not declared", undeclared)}
+	}
+
+	if err := b.requestBody(def, props, &required); err != nil {
+		return nil, err
+	}
+	if err := b.applyAuth(def); err != nil {
+		return nil, err
+	}
+
+	def.URL = b.baseURL + urlPath
+	def.InputSchema = map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		def.InputSchema["required"] = required
+	}
+
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// description combines the operation's summary and description.
+func (b *builder) description() string {
+	var parts []string
+	for _, key := range []string{"summary", "description"} {
+		if s, _ := b.op[key].(string); strings.TrimSpace(s) != "" {
+			parts = append(parts, strings.TrimSpace(s))
+		}
+	}
+	desc := strings.Join(parts, "\n\n")
+	if desc == "" {
+		desc = b.method + " " + b.path
+	}
+	if len(desc) > maxDescriptionChars {
+		desc = strings.ToValidUTF8(desc[:maxDescriptionChars], "") + "..."
+	}
+	return desc
+}
+
+// parameters merges path-level and operation parameters; operation
+// parameters override path-level ones with the same name and location.
+func (b *builder) parameters() ([]map[string]any, error) {
+	var params []map[string]any
+	index := map[string]int{}
+	for _, source := range []map[string]any{b.item, b.op} {
+		list, _ := source["parameters"].([]any)
+		for _, raw := range list {
+			p, err := b.doc.resolve(raw)
+			if err != nil {
+				return nil, fmt.Errorf("parameter: %w", err)
+			}
+			key := fmt.Sprint(p["in"], ":", p["name"])
+			if i, ok := index[key]; ok {
+				params[i] = p
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, p)
+		}
+	}
+	return params, nil
+}
+
+// requestBody adds a JSON request body as the "body" argument.
+func (b *builder) requestBody(def *httptool.Definition, props map[string]any, required *[]any) error {
+	if b.method != http.MethodPost && b.method != http.MethodPut && b.method != http.MethodPatch {
+		return nil
+	}
+	raw, ok := b.op["requestBody"]
+	if !ok {
+		def.OmitBody = true
+		return nil
+	}
+	body, err := b.doc.resolve(raw)
+	if err != nil {
+		return fmt.Errorf("requestBody: %w", err)
+	}
+	isRequired, _ := body["required"].(bool)
+
+	content, _ := body["content"].(map[string]any)
+	media, ok := jsonMedia(content)
+	if !ok {
+		if isRequired {
+			return skipError{"request body is not JSON"}
+		}
+		def.OmitBody = true
+		return nil
+	}
+
+	arg := "body"
+	if _, taken := props[arg]; taken {
+		arg = "request_body"
+	}
+	schema := map[string]any{}
+	if rawSchema, ok := media["schema"]; ok {
+		if schema, err = b.doc.schema(rawSchema, 0); err != nil {
+			return fmt.Errorf("requestBody: %w", err)
+		}
+	}
+	if desc, ok := body["description"].(string); ok && schema["description"] == nil {
+		schema["description"] = desc
+	}
+
+	props[arg] = schema
+	def.Body = "{{" + arg + "}}"
+	if isRequired {
+		*required = append(*required, arg)
+	}
+	return nil
+}
+
+// jsonMedia returns the JSON media type object of a content map, preferring
+// application/json over other +json types.
+func jsonMedia(content map[string]any) (map[string]any, bool) {
+	if media, ok := content["application/json"].(map[string]any); ok {
+		return media, true
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		if strings.HasSuffix(strings.Split(t, ";")[0], "json") {
+			media, ok := content[t].(map[string]any)
+			return media, ok
+		}
+	}
+	return nil, false
+}
+
+// applyAuth adds the configured credential for operations that require
+// authentication.
+func (b *builder) applyAuth(def *httptool.Definition) error {
+	if b.opts.VaultKey == "" {
+		return nil
+	}
+	requirements, ok := b.op["security"].([]any)
+	if !ok {
+		requirements, _ = b.doc["security"].([]any)
+	}
+
+	// An empty requirement ({}) only makes authentication optional
+	var required bool
+	scheme := b.opts.AuthScheme
+	for _, raw := range requirements {
+		req, _ := raw.(map[string]any)
+		if len(req) == 0 {
+			continue
+		}
+		required = true
+		if scheme == "" {
+			names := make([]string, 0, len(req))
+			for name := range req {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			scheme = names[0]
+		}
+	}
+	if !required {
+		return nil
+	}
+
+	components, _ := b.doc["components"].(map[string]any)
+	schemes, _ := components["securitySchemes"].(map[string]any)
+	rawScheme, ok := schemes[scheme]
+	if !ok {
+		return fmt.Errorf("security scheme %q not found", scheme)
+	}
+	s, err := b.doc.resolve(rawScheme)
+	if err != nil {
+		return fmt.Errorf("security scheme %s: %w", scheme, err)
+	}
+
+	secret := "{{secret:" + b.opts.VaultKey + "}}"
+	switch s["type"] {
+	case "http":
+		switch httpScheme, _ := s["scheme"].(string); strings.ToLower(httpScheme) {
+		case "bearer":
+			def.Headers["Authorization"] = "Bearer " + secret
+		case "basic":
+			def.Headers["Authorization"] = "Basic " + secret
+		default:
+			return fmt.Errorf("security scheme %s: unsupported http scheme %q", scheme, httpScheme)
+		}
+	case "apiKey":
+		name, _ := s["name"].(string)
+		switch s["in"] {
+		case "header":
+			def.Headers[name] = secret
+		case "query":
+			def.Query[name] = secret
+		case "cookie":
+			def.Headers["Cookie"] = name + "=" + secret
+		default:
+			return fmt.Errorf("security scheme %s: unsupported location %v", scheme, s["in"])
+		}
+	case "oauth2", "openIdConnect":
+		def.Headers["Authorization"] = "Bearer " + secret // Vault holds an access token
+	default:
+		return fmt.Errorf("security scheme %s: unsupported type %v", scheme, s["type"])
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// maxRefDepth bounds $ref expansion; deeper (usually recursive) schemas are
+// left unconstrained.
+const maxRefDepth = 16
+
+// openAPIOnlyKeywords are schema keywords JSON Schema validators and LLM
+// providers don't understand.
+var openAPIOnlyKeywords = []string{
+	"nullable", "discriminator", "xml", "externalDocs", "example",
+	"deprecated", "readOnly", "writeOnly",
+}
+
+// document is a parsed OpenAPI document with JSON-compatible values.
+type document map[string]any
+
+// normalize converts decoded YAML into JSON-compatible values: maps with
+// non-string keys (e.g. unquoted response codes) get string keys, and
+// numbers become float64 like encoding/json produces.
+func normalize(v any) (any, error) {
+	data, err := json.Marshal(stringKeys(v))
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func stringKeys(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = stringKeys(item)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[fmt.Sprint(k)] = stringKeys(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = stringKeys(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// resolve follows a local $ref ("#/components/...") if node is a reference
+// object, returning the referenced node.
+func (d document) resolve(node any) (map[string]any, error) {
+	seen := map[string]bool{}
+	for {
+		obj, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an object")
+		}
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return obj, nil
+		}
+		if seen[ref] {
+			return nil, fmt.Errorf("circular $ref %s", ref)
+		}
+		seen[ref] = true
+		target, err := d.lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+		node = target
+	}
+}
+
+// lookup evaluates a local JSON pointer reference.
+func (d document) lookup(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %s: only local references are supported", ref)
+	}
+
+	var node any = map[string]any(d)
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("$ref %s not found", ref)
+		}
+		if node, ok = obj[token]; !ok {
+			return nil, fmt.Errorf("$ref %s not found", ref)
+		}
+	}
+	return node, nil
+}
+
+// schema converts an OpenAPI schema into a self-contained JSON Schema:
+// references are inlined and OpenAPI-only keywords are removed, with
+// nullable mapped to a "null" type.
+func (d document) schema(node any, depth int) (map[string]any, error) {
+	if depth > maxRefDepth {
+		return map[string]any{}, nil
+	}
+	obj, err := d.resolve(node)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]any, len(obj))
+	for key, value := range obj {
+		switch key {
+		case "properties":
+			props, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			converted := make(map[string]any, len(props))
+			for name, prop := range props {
+				if converted[name], err = d.schema(prop, depth+1); err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+			}
+			out[key] = converted
+		case "items", "not":
+			if out[key], err = d.schema(value, depth+1); err != nil {
+				return nil, err
+			}
+		case "additionalProperties":
+			if _, ok := value.(bool); ok {
+				out[key] = value
+			} else if out[key], err = d.schema(value, depth+1); err != nil {
+				return nil, err
+			}
+		case "allOf", "anyOf", "oneOf":
+			list, ok := value.([]any)
+			if !ok {
+				continue
+			}
+			converted := make([]any, len(list))
+			for i, item := range list {
+				if converted[i], err = d.schema(item, depth+1); err != nil {
+					return nil, err
+				}
+			}
+			out[key] = converted
+		default:
+			out[key] = value
+		}
+	}
+
+	if nullable, _ := obj["nullable"].(bool); nullable {
+		if t, ok := obj["type"].(string); ok {
+			out["type"] = []any{t, "null"}
+		}
+	}
+	for _, key := range openAPIOnlyKeywords {
+		delete(out, key)
+	}
+	return out, nil
+}
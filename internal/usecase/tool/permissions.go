@@ -11,8 +11,10 @@ import "nuimanbot/internal/domain"
 //   - RoleAdmin: Available only to administrators
 var ToolPermissions = map[string]domain.Role{
 	// Built-in tools (Phase 1) - Available to all
-	"calculator": domain.RoleGuest,
-	"datetime":   domain.RoleGuest,
+	"calculator":  domain.RoleGuest,
+	"datetime":    domain.RoleGuest,
+	"read_result": domain.RoleGuest, // Results are scoped to the user who produced them
+	"preferences": domain.RoleGuest, // Preferences are scoped to the calling user
 
 	// Extended tools (Phase 2) - Require registered user
 	"weather":    domain.RoleUser,
@@ -26,3 +28,28 @@ var ToolPermissions = map[string]domain.Role{
 // DefaultToolPermission is the role required for tools not explicitly listed
 // in ToolPermissions. This provides a safe default of requiring user registration.
 const DefaultToolPermission = domain.RoleUser
+
+// PermissionRoles maps tool capabilities to the minimum role allowed to use
+// them. A tool is only available to users whose role satisfies every one of
+// its RequiredPermissions, in addition to its ToolPermissions entry.
+// Permissions not in this map require RoleAdmin.
+var PermissionRoles = map[domain.Permission]domain.Role{
+	domain.PermissionRead:    domain.RoleGuest,
+	domain.PermissionWrite:   domain.RoleUser,
+	domain.PermissionNetwork: domain.RoleUser,
+	domain.PermissionShell:   domain.RoleAdmin,
+}
+
+// HasPermission reports whether a user's role allows a capability. Tools
+// with actions of different sensitivity (e.g. read and write) use it to
+// check the user from UserFromContext per call.
+func HasPermission(user *domain.User, perm domain.Permission) bool {
+	if user == nil {
+		return false
+	}
+	role, ok := PermissionRoles[perm]
+	if !ok {
+		role = domain.RoleAdmin
+	}
+	return user.Role.HasPermission(role)
+}
@@ -0,0 +1,224 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"nuimanbot/internal/domain"
+)
+
+const (
+	defaultReadLimit = 200
+	maxReadLimit     = 1000
+	maxReadContext   = 5
+)
+
+// ReadResultTool pages through and searches tool outputs kept by a
+// ResultStore.
+type ReadResultTool struct {
+	store *ResultStore
+}
+
+// NewReadResultTool creates the read_result tool for store.
+func NewReadResultTool(store *ResultStore) *ReadResultTool {
+	return &ReadResultTool{store: store}
+}
+
+// Name returns the tool name.
+func (t *ReadResultTool) Name() string {
+	return "read_result"
+}
+
+// Description returns the tool description.
+func (t *ReadResultTool) Description() string {
+	return "Read more of a truncated tool output by its result id: page through it by line or search it with a regular expression"
+}
+
+// InputSchema returns the JSON schema for the tool's input parameters.
+func (t *ReadResultTool) InputSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id": map[string]any{
+				"type":        "string",
+				"description": "Result id from a truncated tool output (e.g. res_3f9a1c2b7d4e)",
+			},
+			"offset": map[string]any{
+				"type":        "integer",
+				"description": "First line to read or search from (1-based)",
+				"default":     1,
+				"minimum":     1,
+			},
+			"limit": map[string]any{
+				"type":        "integer",
+				"description": "Maximum lines to return, or matches when searching",
+				"default":     defaultReadLimit,
+				"minimum":     1,
+				"maximum":     maxReadLimit,
+			},
+			"pattern": map[string]any{
+				"type":        "string",
+				"description": "Regular expression; returns matching lines with their line numbers instead of a page",
+			},
+			"context": map[string]any{
+				"type":        "integer",
+				"description": "Lines of context around each match",
+				"default":     0,
+				"minimum":     0,
+				"maximum":     maxReadContext,
+			},
+		},
+		"required": []string{"id"},
+	}
+}
+
+// Execute returns a page of, or the matches in, a stored result.
+func (t *ReadResultTool) Execute(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	id, _ := params["id"].(string)
+	if id == "" {
+		return &domain.ExecutionResult{Error: "missing id parameter"}, nil
+	}
+	result, ok := t.store.Get(userID(ctx), id)
+	if !ok {
+		return &domain.ExecutionResult{Error: fmt.Sprintf("result %s not found or expired; run the original tool again", id)}, nil
+	}
+
+	offset := intParam(params, "offset", 1)
+	limit := intParam(params, "limit", defaultReadLimit)
+	contextLines := intParam(params, "context", 0)
+	if offset < 1 {
+		return &domain.ExecutionResult{Error: "offset must be at least 1"}, nil
+	}
+	if limit < 1 || limit > maxReadLimit {
+		return &domain.ExecutionResult{Error: fmt.Sprintf("limit must be between 1 and %d", maxReadLimit)}, nil
+	}
+	if contextLines < 0 || contextLines > maxReadContext {
+		return &domain.ExecutionResult{Error: fmt.Sprintf("context must be between 0 and %d", maxReadContext)}, nil
+	}
+	if offset > len(result.Lines) {
+		return &domain.ExecutionResult{Error: fmt.Sprintf("offset %d is past the last line (%d)", offset, len(result.Lines))}, nil
+	}
+
+	if pattern, _ := params["pattern"].(string); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return &domain.ExecutionResult{Error: fmt.Sprintf("invalid pattern: %v", err)}, nil
+		}
+		return t.search(result, re, offset, limit, contextLines), nil
+	}
+	return t.page(result, offset, limit), nil
+}
+
+// page returns up to limit lines from offset, stopping early rather than
+// exceeding the store's output size.
+func (t *ReadResultTool) page(result *StoredResult, offset, limit int) *domain.ExecutionResult {
+	budget := t.store.MaxChars()
+	var body strings.Builder
+	end := offset - 1
+	for end < len(result.Lines) && end-offset+1 < limit {
+		line := result.Lines[end]
+		if body.Len() > 0 && utf8.RuneCountInString(body.String())+utf8.RuneCountInString(line)+1 > budget {
+			break
+		}
+		body.WriteString(line)
+		body.WriteString("\n")
+		end++
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Result %s (%s, %d lines), lines %d-%d:\n\n", result.ID, result.Tool, len(result.Lines), offset, end))
+	output.WriteString(body.String())
+	if end < len(result.Lines) {
+		output.WriteString(fmt.Sprintf("\n[Lines %d-%d remain; call read_result with offset=%d.]", end+1, len(result.Lines), end+1))
+	}
+
+	return &domain.ExecutionResult{
+		Output: output.String(),
+		Metadata: map[string]any{
+			"id":          result.ID,
+			"tool":        result.Tool,
+			"total_lines": len(result.Lines),
+			"start_line":  offset,
+			"end_line":    end,
+		},
+	}
+}
+
+// search returns up to limit lines matching re from offset on, each prefixed
+// with its line number, plus contextLines of surrounding lines.
+func (t *ReadResultTool) search(result *StoredResult, re *regexp.Regexp, offset, limit, contextLines int) *domain.ExecutionResult {
+	budget := t.store.MaxChars()
+	var body strings.Builder
+	matches, lastPrinted, next := 0, 0, 0
+	for i := offset - 1; i < len(result.Lines); i++ {
+		if !re.MatchString(result.Lines[i]) {
+			continue
+		}
+		if matches == limit || utf8.RuneCountInString(body.String()) > budget {
+			next = i + 1
+			break
+		}
+		matches++
+
+		from := max(i-contextLines, lastPrinted, offset-1)
+		to := min(i+contextLines, len(result.Lines)-1)
+		if contextLines > 0 && lastPrinted > 0 && from > lastPrinted {
+			body.WriteString("--\n")
+		}
+		for j := from; j <= to; j++ {
+			sep := "-"
+			if j == i {
+				sep = ":"
+			}
+			body.WriteString(fmt.Sprintf("%d%s %s\n", j+1, sep, result.Lines[j]))
+		}
+		lastPrinted = to + 1
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Result %s (%s, %d lines): ", result.ID, result.Tool, len(result.Lines)))
+	if matches == 0 {
+		output.WriteString(fmt.Sprintf("no lines match %q from line %d.", re.String(), offset))
+	} else {
+		output.WriteString(fmt.Sprintf("%d matching lines for %q:\n\n", matches, re.String()))
+		output.WriteString(body.String())
+	}
+	if next > 0 {
+		output.WriteString(fmt.Sprintf("\n[More matches may follow; call read_result with the same pattern and offset=%d.]", next))
+	}
+
+	return &domain.ExecutionResult{
+		Output: output.String(),
+		Metadata: map[string]any{
+			"id":          result.ID,
+			"tool":        result.Tool,
+			"total_lines": len(result.Lines),
+			"matches":     matches,
+		},
+	}
+}
+
+// RequiredPermissions returns the permissions required for this tool.
+// Stored results are scoped to the user whose tool call produced them.
+func (t *ReadResultTool) RequiredPermissions() []domain.Permission {
+	return nil
+}
+
+// Config returns the tool's configuration.
+func (t *ReadResultTool) Config() domain.ToolConfig {
+	return domain.ToolConfig{Enabled: true}
+}
+
+// intParam returns an integer parameter, accepting JSON numbers.
+func intParam(params map[string]any, key string, def int) int {
+	switch v := params[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return def
+}
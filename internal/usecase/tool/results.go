@@ -0,0 +1,213 @@
+package tool
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultResultMaxChars  = 16000
+	defaultResultHeadChars = 4000
+	defaultResultTTL       = 30 * time.Minute
+
+	// maxStoredChars bounds the total size of all stored results; the oldest
+	// are evicted first.
+	maxStoredChars = 20 * 1024 * 1024
+	// maxLineChars is the longest line kept intact; longer lines are wrapped
+	// so line-based paging never returns unbounded pages.
+	maxLineChars = 2000
+)
+
+// StoredResult is a tool output kept for paging with read_result.
+type StoredResult struct {
+	ID        string
+	Tool      string
+	UserID    string
+	Lines     []string
+	Chars     int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// ResultStore keeps oversized tool outputs for a short time so the LLM can
+// read them in pages instead of receiving them whole. Results are only
+// visible to the user whose tool call produced them.
+type ResultStore struct {
+	maxChars  int
+	headChars int
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	results map[string]*StoredResult
+	order   []string // Insertion order for eviction
+	total   int
+}
+
+// NewResultStore creates a store that keeps outputs longer than maxChars for
+// ttl, replacing them with their first headChars characters. Zero values use
+// the defaults (16000, 4000 and 30 minutes).
+func NewResultStore(maxChars, headChars int, ttl time.Duration) *ResultStore {
+	if maxChars <= 0 {
+		maxChars = defaultResultMaxChars
+	}
+	if headChars <= 0 {
+		headChars = defaultResultHeadChars
+	}
+	if headChars > maxChars {
+		headChars = maxChars
+	}
+	if ttl <= 0 {
+		ttl = defaultResultTTL
+	}
+	return &ResultStore{
+		maxChars:  maxChars,
+		headChars: headChars,
+		ttl:       ttl,
+		now:       time.Now,
+		results:   make(map[string]*StoredResult),
+	}
+}
+
+// MaxChars returns the longest output passed through unchanged; read_result
+// pages never exceed it.
+func (s *ResultStore) MaxChars() int {
+	return s.maxChars
+}
+
+// Oversized reports whether output exceeds the store's threshold.
+func (s *ResultStore) Oversized(output string) bool {
+	return utf8.RuneCountInString(output) > s.maxChars
+}
+
+// Put stores output for userID and returns the stored result.
+func (s *ResultStore) Put(userID, toolName, output string) *StoredResult {
+	now := s.now()
+	result := &StoredResult{
+		ID:        newResultID(),
+		Tool:      toolName,
+		UserID:    userID,
+		Lines:     splitLines(output),
+		Chars:     utf8.RuneCountInString(output),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+	for s.total+result.Chars > maxStoredChars && len(s.order) > 0 {
+		s.remove(s.order[0])
+	}
+	s.results[result.ID] = result
+	s.order = append(s.order, result.ID)
+	s.total += result.Chars
+	return result
+}
+
+// Get returns the stored result with id if it belongs to userID and has not
+// expired.
+func (s *ResultStore) Get(userID, id string) (*StoredResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(s.now())
+	result, ok := s.results[id]
+	if !ok || result.UserID != userID {
+		return nil, false
+	}
+	return result, true
+}
+
+// Excerpt returns the head of output followed by a note telling the LLM how
+// to read the rest of result.
+func (s *ResultStore) Excerpt(output string, result *StoredResult) string {
+	head := truncateRunes(output, s.headChars)
+	// Prefer to cut at a line break in the second half of the head
+	if i := strings.LastIndex(head, "\n"); i > len(head)/2 {
+		head = head[:i]
+	}
+	shown := utf8.RuneCountInString(head)
+
+	return fmt.Sprintf("%s\n\n[Output truncated: showing the first %d of %d characters (%d lines). "+
+		"The full output is stored as %q for %s; call read_result with id=%q and offset/limit to page through it "+
+		"or pattern to search it.]",
+		head, shown, result.Chars, len(result.Lines), result.ID, formatTTL(s.ttl), result.ID)
+}
+
+// expire removes expired results. Callers hold s.mu.
+func (s *ResultStore) expire(now time.Time) {
+	for len(s.order) > 0 {
+		oldest := s.results[s.order[0]]
+		if now.Before(oldest.ExpiresAt) {
+			return
+		}
+		s.remove(oldest.ID)
+	}
+}
+
+// remove deletes a result. Callers hold s.mu.
+func (s *ResultStore) remove(id string) {
+	result, ok := s.results[id]
+	if !ok {
+		return
+	}
+	delete(s.results, id)
+	s.total -= result.Chars
+	for i, stored := range s.order {
+		if stored == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// newResultID returns a random handle such as "res_3f9a1c2b7d4e".
+func newResultID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b) //nolint:errcheck // crypto/rand.Read never fails on supported platforms
+	return "res_" + hex.EncodeToString(b)
+}
+
+// splitLines splits output into lines, wrapping lines longer than
+// maxLineChars.
+func splitLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		for utf8.RuneCountInString(line) > maxLineChars {
+			head := truncateRunes(line, maxLineChars)
+			lines = append(lines, head)
+			line = line[len(head):]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// truncateRunes returns the first n characters of s.
+func truncateRunes(s string, n int) string {
+	count := 0
+	for i := range s {
+		if count == n {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
+
+// formatTTL renders a TTL such as "30m" or "1h".
+func formatTTL(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
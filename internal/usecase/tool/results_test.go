@@ -0,0 +1,164 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"nuimanbot/internal/domain"
+)
+
+// numberedLines returns "line 1\nline 2\n...".
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+func TestResultStore_PutGet(t *testing.T) {
+	store := NewResultStore(100, 40, time.Minute)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	output := numberedLines(30)
+	if !store.Oversized(output) {
+		t.Fatal("Expected output to be oversized")
+	}
+	if store.Oversized("short") {
+		t.Error("Expected short output not to be oversized")
+	}
+
+	result := store.Put("alice", "repo_search", output)
+	if !strings.HasPrefix(result.ID, "res_") || len(result.Lines) != 30 || result.Chars != len(output) {
+		t.Errorf("Unexpected stored result: %+v", result)
+	}
+
+	if _, ok := store.Get("alice", result.ID); !ok {
+		t.Error("Expected the owner to read the result")
+	}
+	if _, ok := store.Get("bob", result.ID); ok {
+		t.Error("Expected other users not to read the result")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := store.Get("alice", result.ID); ok {
+		t.Error("Expected the result to expire")
+	}
+}
+
+func TestResultStore_Excerpt(t *testing.T) {
+	store := NewResultStore(100, 40, 30*time.Minute)
+	output := numberedLines(30)
+	result := store.Put("", "github", output)
+
+	excerpt := store.Excerpt(output, result)
+	if !strings.HasPrefix(excerpt, "line 1\nline 2\nline 3\nline 4\nline 5\n\n[Output truncated") {
+		t.Errorf("Expected the excerpt to end at a line break, got:\n%s", excerpt)
+	}
+	for _, want := range []string{"first 34 of 231 characters (30 lines)", result.ID, "for 30m", "read_result"} {
+		if !strings.Contains(excerpt, want) {
+			t.Errorf("Expected excerpt to contain %q, got:\n%s", want, excerpt)
+		}
+	}
+}
+
+func TestResultStore_WrapsLongLines(t *testing.T) {
+	store := NewResultStore(0, 0, 0)
+	result := store.Put("", "web_fetch", strings.Repeat("é", maxLineChars*2+5))
+
+	if len(result.Lines) != 3 {
+		t.Fatalf("Expected 3 wrapped lines, got %d", len(result.Lines))
+	}
+	if got := len([]rune(result.Lines[2])); got != 5 {
+		t.Errorf("Expected the last line to hold 5 characters, got %d", got)
+	}
+}
+
+func TestReadResultTool_Page(t *testing.T) {
+	store := NewResultStore(100, 40, time.Minute)
+	result := store.Put("alice", "repo_search", numberedLines(30))
+	readTool := NewReadResultTool(store)
+	ctx := ContextWithUser(context.Background(), &domain.User{ID: "alice"})
+
+	res, err := readTool.Execute(ctx, map[string]any{"id": result.ID, "offset": float64(3), "limit": float64(2)})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	want := fmt.Sprintf("Result %s (repo_search, 30 lines), lines 3-4:\n\nline 3\nline 4\n\n[Lines 5-30 remain; call read_result with offset=5.]", result.ID)
+	if res.Output != want {
+		t.Errorf("Unexpected page:\n%s", res.Output)
+	}
+
+	// Pages stop at the store's size threshold
+	res, _ = readTool.Execute(ctx, map[string]any{"id": result.ID, "offset": float64(1)})
+	if res.Metadata["end_line"] != 13 {
+		t.Errorf("Expected the page to stop at line 13 to stay within 100 characters, got %v", res.Metadata["end_line"])
+	}
+
+	res, _ = readTool.Execute(ctx, map[string]any{"id": result.ID, "offset": float64(29)})
+	if strings.Contains(res.Output, "remain") || !strings.HasSuffix(res.Output, "line 29\nline 30\n") {
+		t.Errorf("Expected the last page without a continuation note, got:\n%s", res.Output)
+	}
+}
+
+func TestReadResultTool_Search(t *testing.T) {
+	store := NewResultStore(1000, 100, time.Minute)
+	result := store.Put("", "gh", numberedLines(30))
+	readTool := NewReadResultTool(store)
+
+	res, err := readTool.Execute(context.Background(), map[string]any{"id": result.ID, "pattern": `^line 2\d$`, "limit": 3})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	for _, want := range []string{"3 matching lines", "20: line 20\n21: line 21\n22: line 22\n", "offset=23"} {
+		if !strings.Contains(res.Output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, res.Output)
+		}
+	}
+
+	res, _ = readTool.Execute(context.Background(), map[string]any{"id": result.ID, "pattern": `line (5|9)$`, "context": 1})
+	if !strings.Contains(res.Output, "4- line 4\n5: line 5\n6- line 6\n--\n8- line 8\n9: line 9\n10- line 10\n") {
+		t.Errorf("Expected matches with context, got:\n%s", res.Output)
+	}
+
+	res, _ = readTool.Execute(context.Background(), map[string]any{"id": result.ID, "pattern": "missing"})
+	if !strings.Contains(res.Output, "no lines match") {
+		t.Errorf("Expected no-match message, got:\n%s", res.Output)
+	}
+}
+
+func TestReadResultTool_Errors(t *testing.T) {
+	store := NewResultStore(100, 40, time.Minute)
+	result := store.Put("alice", "repo_search", numberedLines(30))
+	readTool := NewReadResultTool(store)
+	ctx := ContextWithUser(context.Background(), &domain.User{ID: "alice"})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		params map[string]any
+		want   string
+	}{
+		{"missing id", ctx, map[string]any{}, "missing id"},
+		{"unknown id", ctx, map[string]any{"id": "res_000000000000"}, "not found"},
+		{"other user", context.Background(), map[string]any{"id": result.ID}, "not found"},
+		{"past end", ctx, map[string]any{"id": result.ID, "offset": 31}, "past the last line"},
+		{"bad limit", ctx, map[string]any{"id": result.ID, "limit": 0}, "limit must be"},
+		{"bad pattern", ctx, map[string]any{"id": result.ID, "pattern": "("}, "invalid pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := readTool.Execute(tt.ctx, tt.params)
+			if err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+			if !strings.Contains(res.Error, tt.want) {
+				t.Errorf("Expected error containing %q, got %q", tt.want, res.Error)
+			}
+		})
+	}
+}
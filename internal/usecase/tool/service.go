@@ -17,18 +17,32 @@ type Service struct {
 	registry    ToolRegistry
 	securitySvc domain.SecurityService // Use domain.SecurityService
 	rateLimiter *ratelimit.RateLimiter // Optional rate limiter
+	results     *ResultStore           // Oversized outputs; nil passes all output through
 	// timeout      time.Duration // Default timeout for tool execution
 }
 
-// NewService creates a new ToolExecutionService instance.
+// NewService creates a new ToolExecutionService instance. Outputs longer
+// than cfg.Results.MaxChars are kept in a ResultStore and replaced by an
+// excerpt; register NewReadResultTool(svc.Results()) so the LLM can read them.
 func NewService(cfg *config.ToolsSystemConfig, registry ToolRegistry, securitySvc domain.SecurityService) *Service {
 	// TODO: Load default timeout from config
-	return &Service{
+	s := &Service{
 		cfg:         cfg,
 		registry:    registry,
 		securitySvc: securitySvc,
 		// timeout:      time.Duration(cfg.DefaultToolTimeoutSeconds) * time.Second,
 	}
+	if cfg != nil && cfg.Results.MaxChars >= 0 {
+		s.results = NewResultStore(cfg.Results.MaxChars, cfg.Results.HeadChars,
+			time.Duration(cfg.Results.TTLMinutes)*time.Minute)
+	}
+	return s
+}
+
+// Results returns the store of oversized tool outputs, or nil when
+// tools.results.max_chars is -1.
+func (s *Service) Results() *ResultStore {
+	return s.results
 }
 
 // Execute runs a registered tool with given parameters.
@@ -39,12 +53,30 @@ func (s *Service) Execute(ctx context.Context, toolName string, params map[strin
 		return nil, fmt.Errorf("tool '%s' not found: %w", toolName, err)
 	}
 
+	// Validate arguments against the tool's input schema so the LLM gets a
+	// consistent, correctable error instead of each tool's own parse failure
+	params, err = prepareArguments(tool, params)
+	if err != nil {
+		if auditErr := s.securitySvc.Audit(ctx, &domain.AuditEvent{
+			Timestamp: time.Now(),
+			UserID:    userID(ctx),
+			Action:    fmt.Sprintf("tool_execute:%s", toolName),
+			Resource:  toolName,
+			Outcome:   "invalid_arguments",
+			Details:   map[string]any{"error": err.Error()},
+		}); auditErr != nil {
+			slog.Error("Error auditing invalid tool arguments", "error", auditErr)
+		}
+		return nil, err
+	}
+
 	// TODO: Implement timeout logic for tool execution (from config).
 	// Currently, the tool's own context will manage its timeout.
 
 	// Audit the tool execution
 	if err := s.securitySvc.Audit(ctx, &domain.AuditEvent{
 		Timestamp: time.Now(),
+		UserID:    userID(ctx),
 		Action:    fmt.Sprintf("tool_execute:%s", toolName),
 		Resource:  toolName,
 		Outcome:   "attempt",
@@ -58,6 +90,7 @@ func (s *Service) Execute(ctx context.Context, toolName string, params map[strin
 		// Audit failure
 		if auditErr := s.securitySvc.Audit(ctx, &domain.AuditEvent{
 			Timestamp: time.Now(),
+			UserID:    userID(ctx),
 			Action:    fmt.Sprintf("tool_execute:%s", toolName),
 			Resource:  toolName,
 			Outcome:   "failure",
@@ -68,9 +101,16 @@ func (s *Service) Execute(ctx context.Context, toolName string, params map[strin
 		return nil, fmt.Errorf("failed to execute tool '%s': %w", toolName, err)
 	}
 
+	// Keep oversized output out of the prompt; read_result pages through it.
+	// read_result pages are already bounded by the store.
+	if s.results != nil && toolName != "read_result" && s.results.Oversized(result.Output) {
+		result = s.storeResult(ctx, toolName, result)
+	}
+
 	// Audit success
 	if auditErr := s.securitySvc.Audit(ctx, &domain.AuditEvent{
 		Timestamp: time.Now(),
+		UserID:    userID(ctx),
 		Action:    fmt.Sprintf("tool_execute:%s", toolName),
 		Resource:  toolName,
 		Outcome:   "success",
@@ -82,6 +122,22 @@ func (s *Service) Execute(ctx context.Context, toolName string, params map[strin
 	return result, nil
 }
 
+// storeResult saves result's output and returns a copy carrying an excerpt
+// and the stored result's id.
+func (s *Service) storeResult(ctx context.Context, toolName string, result *domain.ExecutionResult) *domain.ExecutionResult {
+	stored := s.results.Put(userID(ctx), toolName, result.Output)
+
+	excerpt := *result
+	excerpt.Output = s.results.Excerpt(result.Output, stored)
+	excerpt.Metadata = make(map[string]any, len(result.Metadata)+2)
+	for k, v := range result.Metadata {
+		excerpt.Metadata[k] = v
+	}
+	excerpt.Metadata["result_id"] = stored.ID
+	excerpt.Metadata["result_chars"] = stored.Chars
+	return &excerpt
+}
+
 // SetRateLimiter sets the rate limiter for tool execution.
 // This is optional - if not set, no rate limiting is applied.
 func (s *Service) SetRateLimiter(limiter *ratelimit.RateLimiter) {
@@ -91,8 +147,13 @@ func (s *Service) SetRateLimiter(limiter *ratelimit.RateLimiter) {
 // ExecuteWithUser runs a registered tool with given parameters after checking permissions and rate limits.
 // This method enforces RBAC based on the user's role and AllowedTools whitelist.
 func (s *Service) ExecuteWithUser(ctx context.Context, user *domain.User, toolName string, params map[string]any) (*domain.ExecutionResult, error) {
+	tool, err := s.registry.Get(toolName)
+	if err != nil {
+		return nil, fmt.Errorf("tool '%s' not found: %w", toolName, err)
+	}
+
 	// Check permissions first
-	if err := s.checkPermission(user, toolName); err != nil {
+	if err := s.checkPermission(user, tool); err != nil {
 		// Audit permission denial for security monitoring
 		s.auditPermissionDenial(ctx, user, toolName, err)
 		return nil, err
@@ -117,7 +178,7 @@ func (s *Service) ExecuteWithUser(ctx context.Context, user *domain.User, toolNa
 	}
 
 	// Permission check and rate limit passed, execute the tool
-	return s.Execute(ctx, toolName, params)
+	return s.Execute(ContextWithUser(ctx, user), toolName, params)
 }
 
 // auditPermissionDenial logs a permission denial event for security monitoring.
@@ -140,8 +201,11 @@ func (s *Service) auditPermissionDenial(ctx context.Context, user *domain.User,
 // checkPermission checks if a user has permission to execute a tool.
 // Permission is granted if:
 //  1. The user's role meets or exceeds the required role for the tool
-//  2. If the user has an AllowedTools whitelist, the tool must be in it
-func (s *Service) checkPermission(user *domain.User, toolName string) error {
+//  2. The user's role meets the PermissionRoles entry of each of the tool's RequiredPermissions
+//  3. If the user has an AllowedTools whitelist, the tool must be in it
+func (s *Service) checkPermission(user *domain.User, tool domain.Tool) error {
+	toolName := tool.Name()
+
 	// Get required role for this tool (default to RoleUser if not specified)
 	requiredRole := DefaultToolPermission
 	if role, ok := ToolPermissions[toolName]; ok {
@@ -153,6 +217,13 @@ func (s *Service) checkPermission(user *domain.User, toolName string) error {
 		return domain.ErrInsufficientPermissions
 	}
 
+	// Check the capabilities the tool needs (e.g. shell access is admin only)
+	for _, perm := range tool.RequiredPermissions() {
+		if !HasPermission(user, perm) {
+			return domain.ErrInsufficientPermissions
+		}
+	}
+
 	// If AllowedTools whitelist is set, verify tool is whitelisted
 	if len(user.AllowedTools) > 0 && !s.isToolWhitelisted(toolName, user.AllowedTools) {
 		return domain.ErrInsufficientPermissions
@@ -171,8 +242,13 @@ func (s *Service) isToolWhitelisted(toolName string, allowedTools []string) bool
 	return false
 }
 
-// ListTools returns all registered tools for a given user.
-func (s *Service) ListTools(ctx context.Context, userID string) ([]domain.Tool, error) {
-	// TODO: Implement user-specific tool filtering using registry.ListForUser
-	return s.registry.List(), nil
+// ListTools returns the registered tools the user is permitted to execute.
+func (s *Service) ListTools(ctx context.Context, user *domain.User) ([]domain.Tool, error) {
+	var tools []domain.Tool
+	for _, tool := range s.registry.List() {
+		if s.checkPermission(user, tool) == nil {
+			tools = append(tools, tool)
+		}
+	}
+	return tools, nil
 }
@@ -3,6 +3,7 @@ package tool_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,14 +22,24 @@ type MockTool struct {
 	ConfigFunc              func() domain.ToolConfig
 }
 
-func (m *MockTool) Name() string                { return m.NameFunc() }
-func (m *MockTool) Description() string         { return m.DescriptionFunc() }
-func (m *MockTool) InputSchema() map[string]any { return m.InputSchemaFunc() }
+func (m *MockTool) Name() string        { return m.NameFunc() }
+func (m *MockTool) Description() string { return m.DescriptionFunc() }
+func (m *MockTool) InputSchema() map[string]any {
+	if m.InputSchemaFunc == nil {
+		return nil
+	}
+	return m.InputSchemaFunc()
+}
 func (m *MockTool) Execute(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
 	return m.ExecuteFunc(ctx, params)
 }
-func (m *MockTool) RequiredPermissions() []domain.Permission { return m.RequiredPermissionsFunc() }
-func (m *MockTool) Config() domain.ToolConfig                { return m.ConfigFunc() }
+func (m *MockTool) RequiredPermissions() []domain.Permission {
+	if m.RequiredPermissionsFunc == nil {
+		return nil
+	}
+	return m.RequiredPermissionsFunc()
+}
+func (m *MockTool) Config() domain.ToolConfig { return m.ConfigFunc() }
 
 // MockToolRegistry implements the SkillRegistry interface.
 type MockToolRegistry struct {
@@ -229,7 +240,7 @@ func TestListSkills(t *testing.T) {
 	svc := NewService(&config.ToolsSystemConfig{}, mockRegistry, mockSecurity)
 
 	ctx := context.Background()
-	listedSkills, err := svc.ListTools(ctx, "user1") // userID is ignored in mock for now
+	listedSkills, err := svc.ListTools(ctx, &domain.User{ID: "user1", Role: domain.RoleUser})
 	if err != nil {
 		t.Errorf("ListSkills returned an unexpected error: %v", err)
 	}
@@ -242,31 +253,53 @@ func TestListSkills(t *testing.T) {
 }
 
 func TestListSkillsForUser(t *testing.T) {
-	// For now, MockToolRegistry.ListForUser simply calls List().
-	// This test just ensures the method is callable and returns expected results from List().
 	mockTools := []domain.Tool{
-		&MockTool{NameFunc: func() string { return "skill1" }},
-		&MockTool{NameFunc: func() string { return "skill2" }},
+		&MockTool{NameFunc: func() string { return "calculator" }},
+		&MockTool{
+			NameFunc:                func() string { return "weather" },
+			RequiredPermissionsFunc: func() []domain.Permission { return []domain.Permission{domain.PermissionNetwork} },
+		},
+		&MockTool{
+			NameFunc:                func() string { return "coding_agent" },
+			RequiredPermissionsFunc: func() []domain.Permission { return []domain.Permission{domain.PermissionShell} },
+		},
 	}
 	mockRegistry := &MockToolRegistry{
 		ListFunc: func() []domain.Tool { return mockTools },
 	}
-	mockSecurity := &MockSecurityService{
-		AuditFunc: func(ctx context.Context, event *domain.AuditEvent) error { return nil },
-	}
-	svc := NewService(&config.ToolsSystemConfig{}, mockRegistry, mockSecurity)
-
-	ctx := context.Background()
-	listedSkills, err := svc.ListTools(ctx, "user1")
-
-	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
-	}
-	if len(listedSkills) != len(mockTools) {
-		t.Errorf("Expected %d skills, got %d", len(mockTools), len(listedSkills))
-	}
-	if listedSkills[0].Name() != "skill1" || listedSkills[1].Name() != "skill2" {
-		t.Errorf("Listed skills mismatch: got %+v", listedSkills)
+	svc := NewService(&config.ToolsSystemConfig{}, mockRegistry, &MockSecurityService{})
+
+	tests := []struct {
+		name string
+		user *domain.User
+		want []string
+	}{
+		{"guest", &domain.User{ID: "g", Role: domain.RoleGuest}, []string{"calculator"}},
+		{"user", &domain.User{ID: "u", Role: domain.RoleUser}, []string{"calculator", "weather"}},
+		{"admin", &domain.User{ID: "a", Role: domain.RoleAdmin}, []string{"calculator", "weather", "coding_agent"}},
+		{"whitelist", &domain.User{ID: "w", Role: domain.RoleAdmin, AllowedTools: []string{"weather"}}, []string{"weather"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed, err := svc.ListTools(context.Background(), tt.user)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			var names []string
+			for _, tool := range listed {
+				names = append(names, tool.Name())
+			}
+			if len(names) != len(tt.want) {
+				t.Fatalf("Expected tools %v, got %v", tt.want, names)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					t.Errorf("Expected tools %v, got %v", tt.want, names)
+					break
+				}
+			}
+		})
 	}
 }
 
@@ -523,3 +556,167 @@ func TestExecuteWithUser_EmptyAllowedSkillsAllowsAllForRole(t *testing.T) {
 		t.Errorf("Expected weather result, got: %v", result)
 	}
 }
+
+func TestExecuteWithUser_ShellPermissionRequiresAdmin(t *testing.T) {
+	mockTool := &MockTool{
+		NameFunc:                func() string { return "coding_agent" },
+		RequiredPermissionsFunc: func() []domain.Permission { return []domain.Permission{domain.PermissionShell} },
+		ExecuteFunc: func(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+			return &domain.ExecutionResult{Output: "done"}, nil
+		},
+	}
+	mockRegistry := &MockToolRegistry{
+		GetFunc: func(name string) (domain.Tool, error) { return mockTool, nil },
+	}
+	var auditedUsers []string
+	svc := NewService(&config.ToolsSystemConfig{}, mockRegistry, &MockSecurityService{
+		AuditFunc: func(ctx context.Context, event *domain.AuditEvent) error {
+			if strings.HasPrefix(event.Action, "tool_execute:") {
+				auditedUsers = append(auditedUsers, event.UserID)
+			}
+			return nil
+		},
+	})
+
+	ctx := context.Background()
+	_, err := svc.ExecuteWithUser(ctx, &domain.User{ID: "user1", Role: domain.RoleUser}, "coding_agent", nil)
+	if !errors.Is(err, domain.ErrInsufficientPermissions) {
+		t.Errorf("Expected ErrInsufficientPermissions for shell tool, got: %v", err)
+	}
+
+	result, err := svc.ExecuteWithUser(ctx, &domain.User{ID: "admin1", Role: domain.RoleAdmin}, "coding_agent", nil)
+	if err != nil {
+		t.Fatalf("Admin should be able to execute shell tools, got error: %v", err)
+	}
+	if result.Output != "done" {
+		t.Errorf("Expected tool output, got: %v", result)
+	}
+	if len(auditedUsers) != 2 || auditedUsers[0] != "admin1" || auditedUsers[1] != "admin1" {
+		t.Errorf("Expected execution audited for admin1, got %v", auditedUsers)
+	}
+}
+
+var calculatorSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"operation": map[string]any{"type": "string", "enum": []string{"add", "subtract"}},
+		"a":         map[string]any{"type": "number"},
+		"b":         map[string]any{"type": "number", "default": 1},
+	},
+	"required": []string{"operation", "a"},
+}
+
+func TestExecute_CoercesArguments(t *testing.T) {
+	var received map[string]any
+	mockTool := &MockTool{
+		NameFunc:        func() string { return "calculator" },
+		InputSchemaFunc: func() map[string]any { return calculatorSchema },
+		ExecuteFunc: func(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+			received = params
+			return &domain.ExecutionResult{Output: "ok"}, nil
+		},
+	}
+	mockRegistry := &MockToolRegistry{
+		GetFunc: func(name string) (domain.Tool, error) { return mockTool, nil },
+	}
+	svc := NewService(&config.ToolsSystemConfig{}, mockRegistry, &MockSecurityService{})
+
+	_, err := svc.Execute(context.Background(), "calculator", map[string]any{"operation": "add", "a": "5"})
+	if err != nil {
+		t.Fatalf("Execute returned an unexpected error: %v", err)
+	}
+	if received["a"] != float64(5) {
+		t.Errorf("Expected a coerced to 5, got %#v", received["a"])
+	}
+	if received["b"] != float64(1) {
+		t.Errorf("Expected default b=1, got %#v", received["b"])
+	}
+}
+
+func TestExecute_InvalidArguments(t *testing.T) {
+	executed := false
+	mockTool := &MockTool{
+		NameFunc:        func() string { return "calculator" },
+		InputSchemaFunc: func() map[string]any { return calculatorSchema },
+		ExecuteFunc: func(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+			executed = true
+			return &domain.ExecutionResult{}, nil
+		},
+	}
+	mockRegistry := &MockToolRegistry{
+		GetFunc: func(name string) (domain.Tool, error) { return mockTool, nil },
+	}
+	svc := NewService(&config.ToolsSystemConfig{}, mockRegistry, &MockSecurityService{})
+
+	_, err := svc.Execute(context.Background(), "calculator", map[string]any{"operation": "multiply", "a": "five"})
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+	var argErr *ArgumentError
+	if !errors.As(err, &argErr) {
+		t.Fatalf("Expected *ArgumentError, got %T", err)
+	}
+	if len(argErr.Violations) != 2 {
+		t.Errorf("Expected 2 violations, got %v", argErr.Violations)
+	}
+	for _, want := range []string{"$.a: expected number, got string", "$.operation: must be one of [add, subtract]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got: %s", want, err.Error())
+		}
+	}
+	if executed {
+		t.Error("Tool should not run with invalid arguments")
+	}
+}
+
+func TestExecuteWithUser_StoresOversizedOutput(t *testing.T) {
+	output := strings.Repeat("match: internal/usecase/tool/service.go\n", 100)
+	mockTool := &MockTool{
+		NameFunc: func() string { return "repo_search" },
+		ExecuteFunc: func(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+			return &domain.ExecutionResult{Output: output, Metadata: map[string]any{"count": 100}}, nil
+		},
+		RequiredPermissionsFunc: func() []domain.Permission { return nil },
+	}
+	registry := NewInMemoryRegistry()
+	cfg := &config.ToolsSystemConfig{}
+	cfg.Results.MaxChars = 1000
+	cfg.Results.HeadChars = 200
+	svc := NewService(cfg, registry, &MockSecurityService{})
+	if err := registry.Register(mockTool); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(NewReadResultTool(svc.Results())); err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{ID: "user1", Role: domain.RoleUser}
+
+	result, err := svc.ExecuteWithUser(context.Background(), user, "repo_search", nil)
+	if err != nil {
+		t.Fatalf("ExecuteWithUser() error = %v", err)
+	}
+	id, ok := result.Metadata["result_id"].(string)
+	if !ok {
+		t.Fatalf("Expected result_id metadata, got %v", result.Metadata)
+	}
+	if len(result.Output) > 1000 || !strings.Contains(result.Output, id) {
+		t.Errorf("Expected a short excerpt naming %s, got %d characters", id, len(result.Output))
+	}
+	if result.Metadata["count"] != 100 || result.Metadata["result_chars"] != len(output) {
+		t.Errorf("Expected tool metadata to be kept, got %v", result.Metadata)
+	}
+
+	page, err := svc.ExecuteWithUser(context.Background(), user, "read_result", map[string]any{"id": id, "offset": 100})
+	if err != nil {
+		t.Fatalf("read_result error = %v", err)
+	}
+	if !strings.HasSuffix(page.Output, "match: internal/usecase/tool/service.go\n") || page.Error != "" {
+		t.Errorf("Expected the last stored line, got %q (%s)", page.Output, page.Error)
+	}
+
+	// Disabled with max_chars -1
+	cfg.Results.MaxChars = -1
+	if NewService(cfg, registry, &MockSecurityService{}).Results() != nil {
+		t.Error("Expected no result store when max_chars is -1")
+	}
+}
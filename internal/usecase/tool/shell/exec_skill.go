@@ -0,0 +1,417 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/tool"
+	"nuimanbot/internal/usecase/tool/common"
+	"nuimanbot/internal/usecase/tool/executor"
+)
+
+const (
+	defaultTimeout        = 60 * time.Second
+	defaultMaxOutputChars = 16000
+)
+
+// Actions
+const (
+	ActionRun    = "run"
+	ActionStatus = "status"
+	ActionOutput = "output"
+	ActionCancel = "cancel"
+)
+
+// DefaultDeniedCommands are refused unless Settings.DeniedCommands is set.
+var DefaultDeniedCommands = []string{
+	"sudo", "su", "doas", "shutdown", "reboot", "halt", "poweroff", "mkfs", "dd",
+}
+
+// DefaultAllowedEnv are the variables passed through unless
+// Settings.AllowedEnv is set. Everything else, including API keys in the
+// bot's environment, is scrubbed.
+var DefaultAllowedEnv = []string{"PATH", "HOME", "LANG", "TERM"}
+
+// Settings controls what the exec skill may run.
+type Settings struct {
+	Workspace           string        // Default working directory
+	RestrictToWorkspace bool          // Confine working directories to Workspace
+	AllowedCommands     []string      // Executable names; empty allows any command not denied
+	DeniedCommands      []string      // Nil uses DefaultDeniedCommands
+	AllowedEnv          []string      // Nil uses DefaultAllowedEnv
+	Timeout             time.Duration // Maximum per command; 0 uses the default
+	MaxOutputChars      int           // 0 uses the default
+	AllowBackground     bool
+}
+
+// Auditor records exec invocations; domain.SecurityService satisfies it.
+type Auditor interface {
+	Audit(ctx context.Context, event *domain.AuditEvent) error
+}
+
+// ExecSkill runs commands on the host for administrators
+type ExecSkill struct {
+	config    domain.ToolConfig
+	settings  Settings
+	executor  executor.ExecutorService
+	pathVal   *common.PathValidator
+	sanitizer *common.OutputSanitizer
+	auditor   Auditor
+}
+
+// NewExecSkill creates a new ExecSkill instance
+func NewExecSkill(
+	config domain.ToolConfig,
+	settings Settings,
+	executor executor.ExecutorService,
+	sanitizer *common.OutputSanitizer,
+	auditor Auditor,
+) *ExecSkill {
+	if settings.Workspace == "" {
+		settings.Workspace = "."
+	}
+	if abs, err := filepath.Abs(settings.Workspace); err == nil {
+		settings.Workspace = abs
+	}
+	if settings.DeniedCommands == nil {
+		settings.DeniedCommands = DefaultDeniedCommands
+	}
+	if settings.AllowedEnv == nil {
+		settings.AllowedEnv = DefaultAllowedEnv
+	}
+	if settings.Timeout <= 0 {
+		settings.Timeout = defaultTimeout
+	}
+	if settings.MaxOutputChars <= 0 {
+		settings.MaxOutputChars = defaultMaxOutputChars
+	}
+
+	var pathVal *common.PathValidator
+	if settings.RestrictToWorkspace {
+		pathVal = common.NewPathValidator([]string{settings.Workspace})
+	}
+
+	return &ExecSkill{
+		config:    config,
+		settings:  settings,
+		executor:  executor,
+		pathVal:   pathVal,
+		sanitizer: sanitizer,
+		auditor:   auditor,
+	}
+}
+
+// Name returns the skill identifier
+func (s *ExecSkill) Name() string {
+	return "exec"
+}
+
+// Description returns a human-readable description
+func (s *ExecSkill) Description() string {
+	desc := "Run a command on the host (no shell: pass the executable as command and its arguments in args). " +
+		"Returns the exit code, stdout and stderr."
+	if len(s.settings.AllowedCommands) > 0 {
+		desc += " Allowed commands: " + strings.Join(s.settings.AllowedCommands, ", ") + "."
+	}
+	if s.settings.AllowBackground {
+		desc += " Long-running commands can run with background=true; check them with action status, output or cancel and the session_id."
+	}
+	return desc
+}
+
+// RequiredPermissions returns the permissions needed
+func (s *ExecSkill) RequiredPermissions() []domain.Permission {
+	return []domain.Permission{domain.PermissionShell}
+}
+
+// Config returns the skill configuration
+func (s *ExecSkill) Config() domain.ToolConfig {
+	return s.config
+}
+
+// InputSchema returns the JSON schema for parameters
+func (s *ExecSkill) InputSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        []string{ActionRun, ActionStatus, ActionOutput, ActionCancel},
+				"default":     ActionRun,
+				"description": "run a command, or check, read or cancel a background session",
+			},
+			"command": map[string]any{
+				"type":        "string",
+				"description": "Executable name, e.g. ls (required for run)",
+			},
+			"args": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Command arguments",
+			},
+			"working_dir": map[string]any{
+				"type":        "string",
+				"description": "Working directory, relative to the workspace",
+			},
+			"timeout": map[string]any{
+				"type":        "integer",
+				"minimum":     1,
+				"maximum":     int(s.settings.Timeout.Seconds()),
+				"description": "Timeout in seconds",
+			},
+			"background": map[string]any{
+				"type":        "boolean",
+				"default":     false,
+				"description": "Run in the background and return a session_id",
+			},
+			"session_id": map[string]any{
+				"type":        "string",
+				"description": "Background session (required for status, output and cancel)",
+			},
+		},
+	}
+}
+
+// Execute runs a command or manages a background session
+func (s *ExecSkill) Execute(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	action, _ := params["action"].(string)
+	switch action {
+	case "", ActionRun:
+		return s.run(ctx, params)
+	case ActionStatus, ActionOutput, ActionCancel:
+		return s.session(ctx, action, params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+// run validates and executes a command
+func (s *ExecSkill) run(ctx context.Context, params map[string]any) (*domain.ExecutionResult, error) {
+	command, _ := params["command"].(string)
+	args := stringList(params["args"])
+	background, _ := params["background"].(bool)
+	details := map[string]any{"command": command, "args": args, "background": background}
+
+	req, err := s.buildRequest(command, args, params)
+	if err != nil {
+		details["error"] = err.Error()
+		s.audit(ctx, "denied", details)
+		return nil, err
+	}
+	details["working_dir"] = req.WorkingDir
+
+	if background {
+		// The session outlives this tool call, so detach it from ctx
+		session, err := s.executor.ExecuteBackground(context.WithoutCancel(ctx), req)
+		if err != nil {
+			details["error"] = err.Error()
+			s.audit(ctx, "failure", details)
+			return nil, err
+		}
+		details["session_id"] = session.ID
+		s.audit(ctx, "started", details)
+		return &domain.ExecutionResult{
+			Output:   fmt.Sprintf("Started background session %s", session.ID),
+			Metadata: map[string]any{"session_id": session.ID, "working_dir": req.WorkingDir},
+		}, nil
+	}
+
+	result, err := s.executor.Execute(ctx, req)
+	if err != nil {
+		details["error"] = err.Error()
+		s.audit(ctx, "failure", details)
+		return nil, err
+	}
+	details["exit_code"] = result.ExitCode
+	details["duration"] = result.Duration.Seconds()
+	s.audit(ctx, "success", details)
+
+	output := fmt.Sprintf("Exit code: %d\n--- stdout ---\n%s\n--- stderr ---\n%s",
+		result.ExitCode, strings.TrimRight(result.Stdout, "\n"), strings.TrimRight(result.Stderr, "\n"))
+
+	return &domain.ExecutionResult{
+		Output: s.clean(output),
+		Metadata: map[string]any{
+			"exit_code":   result.ExitCode,
+			"duration":    result.Duration.Seconds(),
+			"working_dir": req.WorkingDir,
+		},
+	}, nil
+}
+
+// session checks, reads or cancels a background session
+func (s *ExecSkill) session(ctx context.Context, action string, params map[string]any) (*domain.ExecutionResult, error) {
+	id, _ := params["session_id"].(string)
+	if id == "" {
+		return nil, fmt.Errorf("session_id is required for %s", action)
+	}
+	s.audit(ctx, action, map[string]any{"session_id": id})
+
+	switch action {
+	case ActionStatus:
+		status, err := s.executor.GetSessionStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		output := fmt.Sprintf("Session %s: %s", id, status.Status)
+		if status.ExitCode != nil {
+			output += fmt.Sprintf(" (exit code %d)", *status.ExitCode)
+		}
+		if status.Error != "" {
+			output += ": " + status.Error
+		}
+		return &domain.ExecutionResult{Output: output, Metadata: map[string]any{"status": string(status.Status)}}, nil
+	case ActionOutput:
+		output, err := s.executor.GetSessionOutput(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.ExecutionResult{Output: s.clean(output)}, nil
+	default:
+		if err := s.executor.CancelSession(ctx, id); err != nil {
+			return nil, err
+		}
+		return &domain.ExecutionResult{Output: fmt.Sprintf("Cancelled session %s", id)}, nil
+	}
+}
+
+// buildRequest checks the command and working directory against the
+// settings and builds the execution request
+func (s *ExecSkill) buildRequest(command string, args []string, params map[string]any) (executor.ExecutionRequest, error) {
+	if err := s.checkCommand(command); err != nil {
+		return executor.ExecutionRequest{}, err
+	}
+
+	dir, err := s.workingDir(params)
+	if err != nil {
+		return executor.ExecutionRequest{}, err
+	}
+
+	if background, _ := params["background"].(bool); background && !s.settings.AllowBackground {
+		return executor.ExecutionRequest{}, fmt.Errorf("%w: background commands are disabled", domain.ErrForbidden)
+	}
+
+	timeout := s.settings.Timeout
+	if t, ok := params["timeout"].(float64); ok && t > 0 {
+		timeout = min(time.Duration(t)*time.Second, s.settings.Timeout)
+	}
+
+	return executor.ExecutionRequest{
+		Command:    command,
+		Args:       args,
+		WorkingDir: dir,
+		Env:        s.environment(),
+		Timeout:    timeout,
+	}, nil
+}
+
+// checkCommand applies the allow and deny lists
+func (s *ExecSkill) checkCommand(command string) error {
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if strings.ContainsAny(command, " \t\n") {
+		return fmt.Errorf("command must be a single executable; pass arguments in args")
+	}
+
+	name := filepath.Base(command)
+	if slices.Contains(s.settings.DeniedCommands, name) {
+		return fmt.Errorf("%w: command %s is denied", domain.ErrForbidden, name)
+	}
+	if len(s.settings.AllowedCommands) > 0 {
+		// A path would let any binary named like an allowed one through
+		if strings.ContainsRune(command, filepath.Separator) {
+			return fmt.Errorf("%w: commands must be given by name", domain.ErrForbidden)
+		}
+		if !slices.Contains(s.settings.AllowedCommands, name) {
+			return fmt.Errorf("%w: command %s is not allowed", domain.ErrForbidden, name)
+		}
+	}
+	return nil
+}
+
+// workingDir resolves the working directory against the workspace
+func (s *ExecSkill) workingDir(params map[string]any) (string, error) {
+	dir := s.settings.Workspace
+	if wd, ok := params["working_dir"].(string); ok && wd != "" {
+		if filepath.IsAbs(wd) {
+			dir = wd
+		} else {
+			if s.pathVal != nil && strings.Contains(wd, "..") {
+				return "", fmt.Errorf("working directory validation failed: path traversal detected: %s", wd)
+			}
+			dir = filepath.Join(s.settings.Workspace, wd)
+		}
+	}
+
+	if s.pathVal != nil {
+		if err := s.pathVal.ValidatePath(dir); err != nil {
+			return "", fmt.Errorf("working directory validation failed: %w", err)
+		}
+	}
+	return dir, nil
+}
+
+// environment returns the allowed variables from the bot's environment
+func (s *ExecSkill) environment() map[string]string {
+	env := make(map[string]string, len(s.settings.AllowedEnv))
+	for _, name := range s.settings.AllowedEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// clean redacts secrets and truncates output, keeping its start and end
+func (s *ExecSkill) clean(output string) string {
+	if s.sanitizer != nil {
+		output = s.sanitizer.SanitizeOutput(output)
+	}
+	limit := s.settings.MaxOutputChars
+	if len(output) <= limit {
+		return output
+	}
+	head, tail := output[:limit/2], output[len(output)-limit/2:]
+	return fmt.Sprintf("%s\n... [%d characters truncated] ...\n%s",
+		strings.ToValidUTF8(head, ""), len(output)-len(head)-len(tail), strings.ToValidUTF8(tail, ""))
+}
+
+// audit records an invocation
+func (s *ExecSkill) audit(ctx context.Context, outcome string, details map[string]any) {
+	if s.auditor == nil {
+		return
+	}
+	event := &domain.AuditEvent{
+		Timestamp: time.Now(),
+		Action:    "exec",
+		Resource:  s.Name(),
+		Outcome:   outcome,
+		Details:   details,
+	}
+	if user := tool.UserFromContext(ctx); user != nil {
+		event.UserID = user.ID
+	}
+	if err := s.auditor.Audit(ctx, event); err != nil {
+		slog.Error("Error auditing exec invocation", "error", err)
+	}
+}
+
+// stringList converts a validated string array argument
+func stringList(v any) []string {
+	list, _ := v.([]any)
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,197 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nuimanbot/internal/domain"
+	"nuimanbot/internal/usecase/tool"
+	"nuimanbot/internal/usecase/tool/common"
+	"nuimanbot/internal/usecase/tool/executor"
+	"nuimanbot/internal/usecase/tool/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAuditor collects audit events.
+type recordingAuditor struct {
+	events []*domain.AuditEvent
+}
+
+func (a *recordingAuditor) Audit(ctx context.Context, event *domain.AuditEvent) error {
+	a.events = append(a.events, event)
+	return nil
+}
+
+func newTestSkill(settings Settings, exec executor.ExecutorService) (*ExecSkill, *recordingAuditor) {
+	auditor := &recordingAuditor{}
+	return NewExecSkill(domain.ToolConfig{}, settings, exec, common.NewOutputSanitizer(), auditor), auditor
+}
+
+func TestExecSkill_Metadata(t *testing.T) {
+	skill, _ := newTestSkill(Settings{AllowedCommands: []string{"ls"}}, testutil.NewMockExecutor())
+
+	assert.Equal(t, "exec", skill.Name())
+	assert.Contains(t, skill.Description(), "Allowed commands: ls")
+	assert.Equal(t, []domain.Permission{domain.PermissionShell}, skill.RequiredPermissions())
+	assert.NotNil(t, skill.InputSchema()["properties"])
+}
+
+func TestExecSkill_Run(t *testing.T) {
+	t.Setenv("EXEC_TEST_SECRET", "should-not-leak")
+	workspace := t.TempDir()
+	mockExec := testutil.NewMockExecutor()
+	var got executor.ExecutionRequest
+	mockExec.ExecuteFunc = func(ctx context.Context, req executor.ExecutionRequest) (*executor.ExecutionResult, error) {
+		got = req
+		return &executor.ExecutionResult{Stdout: "hello\n", Stderr: "warn\n", ExitCode: 3, Duration: time.Second}, nil
+	}
+	skill, auditor := newTestSkill(Settings{Workspace: workspace, Timeout: 30 * time.Second}, mockExec)
+
+	ctx := tool.ContextWithUser(context.Background(), &domain.User{ID: "admin-1"})
+	result, err := skill.Execute(ctx, map[string]any{
+		"command":     "ls",
+		"args":        []any{"-la"},
+		"working_dir": "sub",
+		"timeout":     float64(120),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ls", got.Command)
+	assert.Equal(t, []string{"-la"}, got.Args)
+	assert.Equal(t, filepath.Join(workspace, "sub"), got.WorkingDir)
+	assert.Equal(t, 30*time.Second, got.Timeout, "timeout is capped at the configured maximum")
+	assert.NotContains(t, got.Env, "EXEC_TEST_SECRET")
+	assert.NotNil(t, got.Env, "environment is never inherited wholesale")
+
+	assert.Contains(t, result.Output, "Exit code: 3")
+	assert.Contains(t, result.Output, "hello")
+	assert.Contains(t, result.Output, "warn")
+	assert.Equal(t, 3, result.Metadata["exit_code"])
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, "success", auditor.events[0].Outcome)
+	assert.Equal(t, "admin-1", auditor.events[0].UserID)
+	assert.Equal(t, 3, auditor.events[0].Details["exit_code"])
+}
+
+func TestExecSkill_CommandPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		command  string
+		wantErr  string
+	}{
+		{"default deny list", Settings{}, "sudo", "denied"},
+		{"denied by path", Settings{}, "/usr/bin/sudo", "denied"},
+		{"not allowed", Settings{AllowedCommands: []string{"git"}}, "curl", "not allowed"},
+		{"path with allow list", Settings{AllowedCommands: []string{"git"}}, "/tmp/git", "by name"},
+		{"arguments in command", Settings{}, "ls -la", "single executable"},
+		{"missing command", Settings{}, "", "command is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExec := testutil.NewMockExecutor()
+			mockExec.ExecuteFunc = func(ctx context.Context, req executor.ExecutionRequest) (*executor.ExecutionResult, error) {
+				t.Fatal("command should not run")
+				return nil, nil
+			}
+			skill, auditor := newTestSkill(tt.settings, mockExec)
+
+			_, err := skill.Execute(context.Background(), map[string]any{"command": tt.command})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			require.Len(t, auditor.events, 1)
+			assert.Equal(t, "denied", auditor.events[0].Outcome)
+		})
+	}
+
+	skill, _ := newTestSkill(Settings{AllowedCommands: []string{"git"}}, testutil.NewMockExecutor())
+	_, err := skill.Execute(context.Background(), map[string]any{"command": "git"})
+	assert.NoError(t, err)
+}
+
+func TestExecSkill_RestrictToWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	skill, _ := newTestSkill(Settings{Workspace: workspace, RestrictToWorkspace: true}, testutil.NewMockExecutor())
+
+	for _, dir := range []string{"../", "/etc"} {
+		_, err := skill.Execute(context.Background(), map[string]any{"command": "ls", "working_dir": dir})
+		assert.ErrorContains(t, err, "working directory validation failed", dir)
+	}
+
+	_, err := skill.Execute(context.Background(), map[string]any{"command": "ls", "working_dir": "src"})
+	assert.NoError(t, err)
+}
+
+func TestExecSkill_TruncatesAndRedactsOutput(t *testing.T) {
+	mockExec := testutil.NewMockExecutor()
+	mockExec.ExecuteFunc = func(ctx context.Context, req executor.ExecutionRequest) (*executor.ExecutionResult, error) {
+		stdout := "token=abcdefghijklmnop\n" + strings.Repeat("x", 500) + "\nEND"
+		return &executor.ExecutionResult{Stdout: stdout}, nil
+	}
+	skill, _ := newTestSkill(Settings{MaxOutputChars: 200}, mockExec)
+
+	result, err := skill.Execute(context.Background(), map[string]any{"command": "cat"})
+	require.NoError(t, err)
+
+	assert.Contains(t, result.Output, "[REDACTED]")
+	assert.NotContains(t, result.Output, "abcdefghijklmnop")
+	assert.Contains(t, result.Output, "characters truncated")
+	assert.Contains(t, result.Output, "END", "the end of the output is kept")
+}
+
+func TestExecSkill_Background(t *testing.T) {
+	mockExec := testutil.NewMockExecutor()
+	mockExec.ExecuteBackgroundFunc = func(ctx context.Context, req executor.ExecutionRequest) (*executor.BackgroundSession, error) {
+		return &executor.BackgroundSession{ID: "sess-1", StartedAt: time.Now()}, nil
+	}
+	exitCode := 0
+	mockExec.GetSessionStatusFunc = func(ctx context.Context, sessionID string) (*executor.SessionStatus, error) {
+		return &executor.SessionStatus{ID: sessionID, Status: executor.SessionStateCompleted, ExitCode: &exitCode}, nil
+	}
+	mockExec.CancelSessionFunc = func(ctx context.Context, sessionID string) error {
+		return errors.New("session not found: " + sessionID)
+	}
+
+	disabled, _ := newTestSkill(Settings{}, mockExec)
+	_, err := disabled.Execute(context.Background(), map[string]any{"command": "sleep", "background": true})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	skill, auditor := newTestSkill(Settings{AllowBackground: true}, mockExec)
+	result, err := skill.Execute(context.Background(), map[string]any{"command": "sleep", "args": []any{"60"}, "background": true})
+	require.NoError(t, err)
+	assert.Equal(t, "sess-1", result.Metadata["session_id"])
+
+	result, err = skill.Execute(context.Background(), map[string]any{"action": ActionStatus, "session_id": "sess-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "Session sess-1: completed (exit code 0)", result.Output)
+
+	_, err = skill.Execute(context.Background(), map[string]any{"action": ActionCancel, "session_id": "other"})
+	assert.ErrorContains(t, err, "session not found")
+
+	_, err = skill.Execute(context.Background(), map[string]any{"action": ActionOutput})
+	assert.ErrorContains(t, err, "session_id is required")
+
+	outcomes := make([]string, len(auditor.events))
+	for i, e := range auditor.events {
+		outcomes[i] = e.Outcome
+	}
+	assert.Equal(t, []string{"started", ActionStatus, ActionCancel}, outcomes)
+}
+
+func TestExecSkill_RealCommand(t *testing.T) {
+	skill, _ := newTestSkill(Settings{Workspace: t.TempDir()}, executor.NewExecutorService())
+
+	result, err := skill.Execute(context.Background(), map[string]any{"command": "echo", "args": []any{"hi"}})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "Exit code: 0")
+	assert.Contains(t, result.Output, "hi")
+}
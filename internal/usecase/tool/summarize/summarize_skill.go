@@ -4,13 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/fetch"
 	"nuimanbot/internal/usecase/tool/executor"
 )
 
@@ -25,7 +24,7 @@ type SummarizeSkill struct {
 	config     domain.ToolConfig
 	llmService domain.LLMService
 	executor   executor.ExecutorService
-	httpClient *http.Client
+	fetcher    *fetch.Service
 }
 
 // SummaryOutput represents the structured summary output
@@ -40,24 +39,23 @@ type SummaryOutput struct {
 	URL           string   `json:"url"`
 }
 
-// NewSummarizeSkill creates a new SummarizeSkill instance
+// NewSummarizeSkill creates a new SummarizeSkill instance. Web pages are
+// read through fetcher; nil uses a fetch service with default options.
 func NewSummarizeSkill(
 	config domain.ToolConfig,
 	llmService domain.LLMService,
 	executor executor.ExecutorService,
-	httpClient *http.Client,
+	fetcher *fetch.Service,
 ) *SummarizeSkill {
-	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: defaultTimeout,
-		}
+	if fetcher == nil {
+		fetcher = fetch.NewService(fetch.Options{})
 	}
 
 	return &SummarizeSkill{
 		config:     config,
 		llmService: llmService,
 		executor:   executor,
-		httpClient: httpClient,
+		fetcher:    fetcher,
 	}
 }
 
@@ -113,17 +111,17 @@ func (s *SummarizeSkill) Execute(ctx context.Context, params map[string]any) (*d
 		return nil, err
 	}
 
-	content, sourceType, err := s.fetchContent(ctx, urlStr)
+	doc, sourceType, err := s.fetchContent(ctx, urlStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch content: %w", err)
 	}
 
-	summary, err := s.generateSummary(ctx, content, params)
+	summary, err := s.generateSummary(ctx, doc.Text, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate summary: %w", err)
 	}
 
-	output := s.formatOutput(summary, urlStr, sourceType, params)
+	output := s.formatOutput(summary, urlStr, sourceType, doc)
 
 	return &domain.ExecutionResult{
 		Output: output,
@@ -162,16 +160,19 @@ func (s *SummarizeSkill) validateURL(params map[string]any) (string, error) {
 }
 
 // fetchContent fetches content from URL (web page or YouTube)
-func (s *SummarizeSkill) fetchContent(ctx context.Context, urlStr string) (string, string, error) {
+func (s *SummarizeSkill) fetchContent(ctx context.Context, urlStr string) (*fetch.Document, string, error) {
 	// Check if it's a YouTube URL
 	if s.isYouTubeURL(urlStr) {
 		content, err := s.fetchYouTubeTranscript(ctx, urlStr)
-		return content, "youtube", err
+		if err != nil {
+			return nil, "", err
+		}
+		return &fetch.Document{URL: urlStr, Text: content}, "youtube", nil
 	}
 
 	// Regular web page
-	content, err := s.fetchWebPage(ctx, urlStr)
-	return content, "webpage", err
+	doc, err := s.fetchWebPage(ctx, urlStr)
+	return doc, "webpage", err
 }
 
 // isYouTubeURL checks if the URL is a YouTube video
@@ -211,38 +212,21 @@ func (s *SummarizeSkill) fetchYouTubeTranscript(ctx context.Context, urlStr stri
 	return execResult.Stdout, nil
 }
 
-// fetchWebPage fetches web page content via HTTP
-func (s *SummarizeSkill) fetchWebPage(ctx context.Context, urlStr string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		return "", err
+// fetchWebPage fetches a web page or document and extracts its main text
+func (s *SummarizeSkill) fetchWebPage(ctx context.Context, urlStr string) (*fetch.Document, error) {
+	req := fetch.Request{
+		URL:      urlStr,
+		MaxBytes: maxWebPageSize,
+		Timeout:  defaultTimeout,
 	}
-
-	// Set user agent
-	userAgent := "NuimanBot/1.0"
 	if ua, ok := s.config.Params["user_agent"].(string); ok {
-		userAgent = ua
-	}
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		req.UserAgent = ua
 	}
-
-	// Read with size limit
-	limitedReader := io.LimitReader(resp.Body, int64(maxWebPageSize))
-	content, err := io.ReadAll(limitedReader)
-	if err != nil {
-		return "", err
+	if timeout, ok := s.config.Params["timeout"].(float64); ok && timeout > 0 {
+		req.Timeout = time.Duration(timeout) * time.Second
 	}
 
-	return string(content), nil
+	return s.fetcher.Fetch(ctx, req)
 }
 
 // generateSummary generates a summary using the LLM service
@@ -309,9 +293,11 @@ func (s *SummarizeSkill) buildSummaryPrompt(content, format string, includeQuote
 }
 
 // formatOutput formats the summary output as JSON
-func (s *SummarizeSkill) formatOutput(summary, urlStr, sourceType string, params map[string]any) string {
+func (s *SummarizeSkill) formatOutput(summary, urlStr, sourceType string, doc *fetch.Document) string {
 	output := SummaryOutput{
 		Summary:    summary,
+		Title:      doc.Title,
+		Author:     doc.Byline,
 		URL:        urlStr,
 		SourceType: sourceType,
 	}
@@ -2,10 +2,13 @@ package summarize
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"nuimanbot/internal/domain"
+	"nuimanbot/internal/infrastructure/fetch"
 	"nuimanbot/internal/usecase/tool/executor"
 	"nuimanbot/internal/usecase/tool/testutil"
 
@@ -187,6 +190,34 @@ func TestSummarizeSkill_Execute_WithIncludeQuotes(t *testing.T) {
 	}
 }
 
+func TestSummarizeSkill_FetchWebPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "TestAgent/2.0", r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Launch day</title><meta name="author" content="Grace"></head>
+			<body><aside>Ads</aside><article><p>The rocket launched on schedule.</p></article></body></html>`))
+	}))
+	defer server.Close()
+
+	config := domain.ToolConfig{
+		Enabled: true,
+		Params:  map[string]any{"user_agent": "TestAgent/2.0", "timeout": float64(5)},
+	}
+	skill := NewSummarizeSkill(config, nil, nil, fetch.NewService(fetch.Options{AllowPrivateNetworks: true}))
+
+	doc, err := skill.fetchWebPage(context.Background(), server.URL+"/launch")
+	require.NoError(t, err)
+	assert.Equal(t, "The rocket launched on schedule.", doc.Text)
+
+	output := skill.formatOutput("Summary.", server.URL+"/launch", "webpage", doc)
+	assert.Contains(t, output, `"title":"Launch day"`)
+	assert.Contains(t, output, `"author":"Grace"`)
+}
+
 // MockLLMService for testing
 type MockLLMService struct {
 	CompleteFunc func(ctx context.Context, provider domain.LLMProvider, req *domain.LLMRequest) (*domain.LLMResponse, error)
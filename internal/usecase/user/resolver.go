@@ -0,0 +1,62 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"nuimanbot/internal/domain"
+)
+
+// Resolver maps platform identities to users for authorization.
+// Registered users are looked up in the repository; any other identity gets a
+// transient user with the default role configured for its platform.
+type Resolver struct {
+	userRepo      domain.UserRepository // Optional; without it every identity gets its default role
+	defaultRole   domain.Role
+	platformRoles map[domain.Platform]domain.Role
+}
+
+// NewResolver creates a resolver. defaultRole applies to unregistered
+// identities on platforms without an entry in platformRoles; an empty or
+// unknown role falls back to guest.
+func NewResolver(userRepo domain.UserRepository, defaultRole domain.Role, platformRoles map[domain.Platform]domain.Role) *Resolver {
+	return &Resolver{
+		userRepo:      userRepo,
+		defaultRole:   defaultRole,
+		platformRoles: platformRoles,
+	}
+}
+
+// ResolveUser returns the registered user for a platform identity, or a
+// transient user with the platform's default role if none is registered.
+func (r *Resolver) ResolveUser(ctx context.Context, platform domain.Platform, platformUID string) (*domain.User, error) {
+	if r.userRepo != nil {
+		user, err := r.userRepo.GetUserByPlatformID(ctx, platform, platformUID)
+		if err == nil {
+			return user, nil
+		}
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, fmt.Errorf("failed to look up user: %w", err)
+		}
+	}
+
+	return &domain.User{
+		ID:          string(platform) + ":" + platformUID,
+		Username:    platformUID,
+		Role:        r.roleFor(platform),
+		PlatformIDs: map[domain.Platform]string{platform: platformUID},
+	}, nil
+}
+
+// roleFor returns the role given to unregistered identities on a platform.
+func (r *Resolver) roleFor(platform domain.Platform) domain.Role {
+	role, ok := r.platformRoles[platform]
+	if !ok {
+		role = r.defaultRole
+	}
+	if role.Level() < 0 {
+		return domain.RoleGuest
+	}
+	return role
+}
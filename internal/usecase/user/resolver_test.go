@@ -0,0 +1,78 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"nuimanbot/internal/domain"
+	. "nuimanbot/internal/usecase/user"
+)
+
+func TestResolver_RegisteredUser(t *testing.T) {
+	repo := NewMockUserRepository()
+	repo.users["u1"] = &domain.User{
+		ID:          "u1",
+		Role:        domain.RoleAdmin,
+		PlatformIDs: map[domain.Platform]string{domain.PlatformTelegram: "42"},
+	}
+	resolver := NewResolver(repo, domain.RoleGuest, nil)
+
+	user, err := resolver.ResolveUser(context.Background(), domain.PlatformTelegram, "42")
+	if err != nil {
+		t.Fatalf("ResolveUser failed: %v", err)
+	}
+	if user.ID != "u1" || user.Role != domain.RoleAdmin {
+		t.Errorf("Expected registered admin u1, got %+v", user)
+	}
+}
+
+func TestResolver_UnregisteredIdentityGetsDefaultRole(t *testing.T) {
+	resolver := NewResolver(NewMockUserRepository(), domain.RoleGuest, map[domain.Platform]domain.Role{
+		domain.PlatformCLI: domain.RoleUser,
+	})
+
+	tests := []struct {
+		platform domain.Platform
+		want     domain.Role
+	}{
+		{domain.PlatformTelegram, domain.RoleGuest},
+		{domain.PlatformCLI, domain.RoleUser},
+	}
+	for _, tt := range tests {
+		user, err := resolver.ResolveUser(context.Background(), tt.platform, "someone")
+		if err != nil {
+			t.Fatalf("ResolveUser(%s) failed: %v", tt.platform, err)
+		}
+		if user.Role != tt.want {
+			t.Errorf("ResolveUser(%s) role = %s, want %s", tt.platform, user.Role, tt.want)
+		}
+		if user.ID != string(tt.platform)+":someone" {
+			t.Errorf("ResolveUser(%s) ID = %s", tt.platform, user.ID)
+		}
+	}
+}
+
+func TestResolver_InvalidDefaultRoleFallsBackToGuest(t *testing.T) {
+	resolver := NewResolver(nil, domain.Role("superuser"), nil)
+
+	user, err := resolver.ResolveUser(context.Background(), domain.PlatformSlack, "U1")
+	if err != nil {
+		t.Fatalf("ResolveUser failed: %v", err)
+	}
+	if user.Role != domain.RoleGuest {
+		t.Errorf("Expected guest role, got %s", user.Role)
+	}
+}
+
+func TestResolver_RepositoryError(t *testing.T) {
+	repo := NewMockUserRepository()
+	repo.GetUserByPlatformIDFunc = func(ctx context.Context, platform domain.Platform, platformUID string) (*domain.User, error) {
+		return nil, errors.New("db down")
+	}
+	resolver := NewResolver(repo, domain.RoleAdmin, nil)
+
+	if _, err := resolver.ResolveUser(context.Background(), domain.PlatformCLI, "me"); err == nil {
+		t.Error("Expected error when the repository fails")
+	}
+}